@@ -1,15 +1,48 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	// Embedded zone data for users' timezones on hosts without it
+	_ "time/tzdata"
 
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/auditlog"
+	"real-estate-manager/backend/internal/calendar"
+	"real-estate-manager/backend/internal/captcha"
+	"real-estate-manager/backend/internal/crm"
+	"real-estate-manager/backend/internal/enrichment"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/esign"
+	"real-estate-manager/backend/internal/events"
 	"real-estate-manager/backend/internal/handlers"
+	"real-estate-manager/backend/internal/leads"
+	"real-estate-manager/backend/internal/mailer"
 	"real-estate-manager/backend/internal/middleware"
 	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/scanner"
+	"real-estate-manager/backend/internal/scheduler"
 	"real-estate-manager/backend/internal/services"
+	"real-estate-manager/backend/internal/sms"
+	"real-estate-manager/backend/internal/syndication"
+	"real-estate-manager/backend/internal/worker"
 	"real-estate-manager/backend/pkg/database"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+	"real-estate-manager/backend/pkg/imagestore"
+	"real-estate-manager/backend/pkg/mlsauth"
+	"real-estate-manager/backend/pkg/objectstore"
+	"real-estate-manager/backend/pkg/secrets"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -24,17 +57,37 @@ func getEnv(key, defaultValue string) string {
 }
 
 func main() {
+	// Timestamps created by the server, like the database's, are UTC so API
+	// responses carry RFC 3339 times ending in Z whatever the host's zone
+	time.Local = time.UTC
 	loadEnvironment()
-	validateJWTSecret()
-	
-	db := initializeDatabase()
+	secretsProvider := initializeSecrets()
+	jwtSecret := loadJWTSecret(secretsProvider)
+	db := initializeDatabase(secretsProvider)
 	defer db.Close()
 
-	repositories := initializeRepositories(db)
-	services := initializeServices(repositories)
+	cipher := initializeFieldEncryption(secretsProvider)
+	repositories := initializeRepositories(db, cipher)
+	services := initializeServices(db, repositories, jwtSecret, cipher)
+	runStartupDiagnostics(db, services.SimplyRETSService, jwtSecret)
 	handlers := initializeHandlers(repositories, services)
 
-	router := setupRouter(handlers, services.AuthService)
+	origins := middleware.NewOriginList(corsOrigins())
+	services.Reloader.Register("cors_origins", func(ctx context.Context) error {
+		origins.Set(corsOrigins())
+		return nil
+	})
+	go reloadOnSIGHUP(services.Reloader)
+
+	sched := startScheduler(services)
+	defer sched.Stop()
+	defer services.Events.Close()
+	defer services.Audit.Flush()
+	// Background work drains before the event bus it publishes to closes
+	defer services.ImageWorkers.Stop(context.Background())
+	defer services.PhotoBackfill.Stop(context.Background())
+
+	router := setupRouter(handlers, origins, initializeLoadShedder(db), services.AuthService, services.Permissions, services.Audit, services.LoginGuard, services.IPAccess, services.FeatureFlagService)
 	startServer(router)
 }
 
@@ -45,20 +98,97 @@ func loadEnvironment() {
 			log.Println("No .env.dev file found, using environment variables")
 		}
 	}
+	if err := middleware.SetLogLevel(getEnv("LOG_LEVEL", "info")); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// reloadOnSIGHUP reloads the configuration whenever the process receives
+// SIGHUP, e.g. from `kill -HUP` or `docker kill --signal=HUP`
+func reloadOnSIGHUP(reloader *services.ReloadService) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		log.Println("SIGHUP received, reloading configuration")
+		reloader.Reload(context.Background())
+	}
+}
+
+// initializeReloader registers the configuration that can be reloaded
+// without a restart. In development the environment is read from .env.dev
+// again first; in production it only changes through the secrets provider
+// or the database.
+func initializeReloader(featureFlags *services.FeatureFlagService, settings *services.SettingsService) *services.ReloadService {
+	reloader := services.NewReloadService()
+	if gin.Mode() != gin.ReleaseMode {
+		reloader.Register("environment", func(ctx context.Context) error {
+			if err := godotenv.Overload(".env.dev"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			return nil
+		})
+	}
+	reloader.Register("log_level", func(ctx context.Context) error {
+		return middleware.SetLogLevel(getEnv("LOG_LEVEL", "info"))
+	})
+	reloader.Register("feature_flags", featureFlags.Load)
+	// Rate limits such as public_images_per_minute are settings
+	reloader.Register("settings", settings.Load)
+	return reloader
+}
+
+// trustedProxies are the proxies whose X-Forwarded-For headers are
+// believed when working out a client's IP, for IP rules and per-IP limits.
+// None are trusted by default, so clients cannot forge their address.
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
 }
 
-func validateJWTSecret() {
-	jwtSecret := os.Getenv("JWT_SECRET")
+// corsOrigins returns the origins allowed to call the API from a browser,
+// a comma-separated CORS_ALLOWED_ORIGINS
+func corsOrigins() []string {
+	return strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"), ",")
+}
+
+func initializeSecrets() secrets.Provider {
+	provider, err := secrets.NewProviderFromEnv()
+	if err != nil {
+		log.Fatal("Failed to initialize secrets provider:", err)
+	}
+	return provider
+}
+
+func loadJWTSecret(provider secrets.Provider) string {
+	jwtSecret, err := secrets.GetOrDefault(context.Background(), provider, "JWT_SECRET", "")
+	if err != nil {
+		log.Fatal("Failed to load JWT secret:", err)
+	}
+	// The development secret is public, so it is never accepted in release mode
+	allowDev := os.Getenv("ALLOW_DEV_JWT_SECRET") == "true" && gin.Mode() != gin.ReleaseMode
+	resolved, err := services.ResolveJWTSecret(jwtSecret, allowDev)
+	if err != nil {
+		log.Fatal(err)
+	}
 	if jwtSecret == "" {
-		log.Println("Warning: JWT_SECRET not set, using default (insecure for production)")
+		log.Println("Warning: JWT_SECRET not set, using the development secret (ALLOW_DEV_JWT_SECRET=true)")
 	} else if len(jwtSecret) < 32 {
 		log.Println("Warning: JWT_SECRET should be at least 32 characters long")
 	}
+	return resolved
 }
 
-func initializeDatabase() *sql.DB {
-	// Database configuration from environment variables
-	dbConfig := database.NewConfigFromEnv()
+func initializeDatabase(provider secrets.Provider) *sql.DB {
+	// Database configuration from environment variables and the secrets provider
+	dbConfig, err := database.NewConfigFromProvider(context.Background(), provider)
+	if err != nil {
+		log.Fatal("Failed to load database configuration:", err)
+	}
 
 	// Create database if it doesn't exist
 	if err := database.CreateDatabaseIfNotExists(dbConfig); err != nil {
@@ -80,95 +210,1056 @@ func initializeDatabase() *sql.DB {
 }
 
 type Repositories struct {
-	UserRepo     repository.UserRepository
-	PropertyRepo repository.PropertyRepository
+	UserRepo           repository.UserRepository
+	PropertyRepo       repository.PropertyRepository
+	RevisionRepo       repository.PropertyRevisionRepository
+	AmenityRepo        repository.AmenityRepository
+	EnrichmentRepo     repository.EnrichmentRepository
+	FeatureFlagRepo    repository.FeatureFlagRepository
+	SettingRepo        repository.SettingRepository
+	StorageRepo        repository.StorageRepository
+	UploadRepo         repository.UploadRepository
+	UploadSessionRepo  repository.UploadSessionRepository
+	RoleRepo           repository.RolePermissionRepository
+	AuditRepo          repository.AuditRepository
+	MagicLinkRepo      repository.EmailTokenRepository
+	PasswordResetRepo  repository.EmailTokenRepository
+	RevokedTokenRepo   repository.RevokedTokenRepository
+	ServiceAccountRepo repository.ServiceAccountRepository
+	ChangeRepo         repository.ChangeRepository
+	SuppressionRepo    repository.EmailSuppressionRepository
+	DataQualityRepo    repository.DataQualityRepository
+	ArchiveRepo        repository.ArchiveRepository
+	NotificationRepo   repository.NotificationPreferenceRepository
+	InboxRepo          repository.NotificationRepository
+	ActivityRepo       repository.ActivityRepository
+	SyndicationRepo    repository.SyndicationRepository
+	LeadRepo           repository.LeadRepository
+	CalendarRepo       repository.CalendarRepository
+	ShowingRepo        repository.ShowingRepository
+	CRMRepo            repository.CRMRepository
+	DealRepo           repository.DealRepository
+	InspectionRepo     repository.InspectionRepository
+	OfferRepo          repository.OfferRepository
+	SignatureRepo      repository.SignatureRepository
+	ContactRepo        repository.ContactRepository
+	ValuationRepo      repository.ValuationRepository
+	MarketRepo         repository.MarketRepository
+	ViewRepo           repository.ViewRepository
+	FavoriteRepo       repository.FavoriteRepository
+	SavedSearchRepo    repository.SavedSearchRepository
+	RecommendationRepo repository.RecommendationRepository
+	JobRepo            repository.JobRepository
+	PhotoManifestRepo  repository.PhotoManifestRepository
+	FileScanRepo       repository.FileScanRepository
+	EncryptedFieldRepo repository.EncryptedFieldRepository
+	PublicationRepo    repository.ListingPublicationRepository
 }
 
-func initializeRepositories(db *sql.DB) *Repositories {
+// initializeFieldEncryption returns nil, which stores sensitive columns in
+// plaintext, unless FIELD_ENCRYPTION_KEYS is set
+func initializeFieldEncryption(provider secrets.Provider) *fieldcrypt.Cipher {
+	cipher, err := fieldcrypt.NewFromProvider(context.Background(), provider)
+	if err != nil {
+		log.Fatal("Failed to load field encryption keys:", err)
+	}
+	if cipher == nil {
+		log.Println("Warning: FIELD_ENCRYPTION_KEYS not set, phone numbers and OAuth tokens are stored unencrypted")
+	}
+	return cipher
+}
+
+func initializeRepositories(db *sql.DB, cipher *fieldcrypt.Cipher) *Repositories {
 	return &Repositories{
-		UserRepo:     repository.NewUserRepository(db),
-		PropertyRepo: repository.NewPropertyRepository(db),
+		UserRepo:           repository.NewUserRepository(db),
+		PropertyRepo:       repository.NewPropertyRepository(db),
+		RevisionRepo:       repository.NewPropertyRevisionRepository(db),
+		AmenityRepo:        repository.NewAmenityRepository(db),
+		EnrichmentRepo:     repository.NewEnrichmentRepository(db),
+		FeatureFlagRepo:    repository.NewFeatureFlagRepository(db),
+		SettingRepo:        repository.NewSettingRepository(db),
+		StorageRepo:        repository.NewStorageRepository(db),
+		UploadRepo:         repository.NewUploadRepository(db),
+		UploadSessionRepo:  repository.NewUploadSessionRepository(db),
+		RoleRepo:           repository.NewRolePermissionRepository(db),
+		AuditRepo:          repository.NewAuditRepository(db),
+		MagicLinkRepo:      repository.NewMagicLinkRepository(db),
+		PasswordResetRepo:  repository.NewPasswordResetRepository(db),
+		RevokedTokenRepo:   repository.NewRevokedTokenRepository(db),
+		ServiceAccountRepo: repository.NewServiceAccountRepository(db),
+		ChangeRepo:         repository.NewChangeRepository(db),
+		SuppressionRepo:    repository.NewEmailSuppressionRepository(db),
+		DataQualityRepo:    repository.NewDataQualityRepository(db),
+		ArchiveRepo:        repository.NewArchiveRepository(db),
+		NotificationRepo:   repository.NewNotificationPreferenceRepository(db, cipher),
+		InboxRepo:          repository.NewNotificationRepository(db),
+		ActivityRepo:       repository.NewActivityRepository(db),
+		SyndicationRepo:    repository.NewSyndicationRepository(db),
+		LeadRepo:           repository.NewLeadRepository(db, cipher),
+		CalendarRepo:       repository.NewCalendarRepository(db, cipher),
+		ShowingRepo:        repository.NewShowingRepository(db),
+		CRMRepo:            repository.NewCRMRepository(db, cipher),
+		DealRepo:           repository.NewDealRepository(db),
+		InspectionRepo:     repository.NewInspectionRepository(db),
+		OfferRepo:          repository.NewOfferRepository(db, cipher),
+		SignatureRepo:      repository.NewSignatureRepository(db),
+		ContactRepo:        repository.NewContactRepository(db, cipher),
+		ValuationRepo:      repository.NewValuationRepository(db),
+		MarketRepo:         repository.NewMarketRepository(db),
+		ViewRepo:           repository.NewViewRepository(db),
+		FavoriteRepo:       repository.NewFavoriteRepository(db),
+		SavedSearchRepo:    repository.NewSavedSearchRepository(db),
+		RecommendationRepo: repository.NewRecommendationRepository(db),
+		JobRepo:            repository.NewJobRepository(db),
+		PhotoManifestRepo:  repository.NewPhotoManifestRepository(db),
+		FileScanRepo:       repository.NewFileScanRepository(db),
+		EncryptedFieldRepo: repository.NewEncryptedFieldRepository(db, cipher),
+		PublicationRepo:    repository.NewListingPublicationRepository(db),
 	}
 }
 
 type Services struct {
-	AuthService       *services.AuthService
-	PropertyService   *services.PropertyService
-	SimplyRETSService *services.SimplyRETSService
+	AuthService        *services.AuthService
+	TokenBlacklist     *services.TokenBlacklist
+	PropertyService    *services.PropertyService
+	SimplyRETSService  *services.SimplyRETSService
+	ImportQuotas       *services.ImportQuotaService
+	AmenityService     *services.AmenityService
+	FeatureFlagService *services.FeatureFlagService
+	SettingsService    *services.SettingsService
+	StaleListings      *services.StaleListingService
+	Enrichment         *services.EnrichmentService
+	Storage            *services.StorageService
+	Photos             *services.PhotoService
+	Images             imagestore.Storage
+	DirectUploads      *services.DirectUploadService
+	UploadSessions     *services.UploadSessionService
+	VirusScans         *services.VirusScanService
+	Permissions        *services.PermissionService
+	Audit              *services.AuditService
+	Impersonation      *services.ImpersonationService
+	MagicLinks         *services.MagicLinkService
+	PasswordResets     *services.PasswordResetService
+	LoginGuard         *services.LoginGuard
+	ServiceAccounts    *services.ServiceAccountService
+	Changes            *services.ChangeService
+	PublicImages       *services.PublicImageService
+	ImageWorkers       *worker.Pool
+	PhotoBackfill      *worker.Pool
+	EmailSuppressions  *services.EmailSuppressionService
+	DataQuality        *services.DataQualityService
+	Archives           *services.ArchiveService
+	Notifications      *services.NotificationService
+	Alerts             *services.AlertService
+	Readiness          *services.ReadinessService
+	Health             *services.HealthService
+	Reloader           *services.ReloadService
+	FieldEncryption    *services.FieldEncryptionService
+	Inbox              *services.NotificationCenterService
+	Activity           *services.ActivityService
+	Flyers             *services.FlyerService
+	Syndication        *services.SyndicationService
+	Leads              *services.LeadService
+	Calendars          *services.CalendarService
+	Showings           *services.ShowingService
+	CRM                *services.CRMService
+	Deals              *services.DealService
+	Inspections        *services.InspectionService
+	Offers             *services.OfferService
+	Signatures         *services.SignatureService
+	Contacts           *services.ContactService
+	Valuations         *services.ValuationService
+	Market             *services.MarketService
+	Views              *services.ViewService
+	Exports            *services.ExportService
+	IPAccess           *services.IPAccessService
+	SyncSchedule       *services.SyncScheduleService
+	Favorites          *services.FavoriteService
+	Recommendations    *services.RecommendationService
+	Publications       *services.PublicationService
+	Runtime            *services.RuntimeService
+	PublicIDs          *services.PublicIDService
+	ListingExpiry      *services.ListingExpiryService
+	// Events only leave the process when EVENT_BUS is configured
+	Events *events.Bus
 }
 
-func initializeServices(repos *Repositories) *Services {
+func initializeServices(db *sql.DB, repos *Repositories, jwtSecret string, cipher *fieldcrypt.Cipher) *Services {
+	featureFlagService := services.NewFeatureFlagService(repos.FeatureFlagRepo)
+	if err := featureFlagService.Load(context.Background()); err != nil {
+		log.Printf("Warning: failed to load feature flags, using defaults: %v", err)
+	}
+
+	jobManager := services.NewJobManager()
+	settingsService := services.NewSettingsService(repos.SettingRepo)
+	settingsService.Subscribe(func(name, value string) {
+		if name == services.SettingJobRetention {
+			jobManager.SetRetention(settingsService.GetDuration(name))
+		}
+	})
+	if err := settingsService.Load(context.Background()); err != nil {
+		log.Printf("Warning: failed to load settings, using defaults: %v", err)
+	}
+	// Pick up changes made through other instances
+	go settingsService.Watch(context.Background(), time.Minute)
+
+	alertNotifier, err := alerts.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure ops alerts:", err)
+	}
+	alertService := services.NewAlertService(alertNotifier, settingsService)
+
+	permissionService := services.NewPermissionService(repos.RoleRepo, repos.UserRepo)
+	if err := permissionService.Load(context.Background()); err != nil {
+		log.Printf("Warning: failed to load role permissions, using built-in roles: %v", err)
+	}
+
+	bus, err := events.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure event bus:", err)
+	}
+	if bus == nil {
+		// The notification center and the activity feed are fed from the bus
+		bus = events.NewLocalBus()
+	}
+	inbox := services.NewNotificationCenterService(repos.InboxRepo)
+	bus.Subscribe(inbox.HandleEvent)
+	activity := services.NewActivityService(repos.ActivityRepo, repos.UserRepo)
+	bus.Subscribe(activity.HandleEvent)
+
+	storageService := services.NewStorageService(repos.StorageRepo, repos.UserRepo, settingsService)
+	virusScanner, virusScans := initializeVirusScans(repos)
+	propertyOptions := []services.PropertyServiceOption{
+		services.WithRevisions(repos.RevisionRepo), services.WithAuthorizer(permissionService), services.WithPropertyEvents(bus),
+	}
+	propertyService := services.NewPropertyService(repos.PropertyRepo, propertyOptions...)
+
+	tokenBlacklist := services.NewTokenBlacklist(repos.RevokedTokenRepo)
+	if err := tokenBlacklist.Load(context.Background()); err != nil {
+		log.Printf("Warning: failed to load revoked tokens: %v", err)
+	}
+	// Pick up tokens revoked through other instances
+	go tokenBlacklist.Watch(context.Background(), time.Minute)
+	authService := services.NewAuthServiceWithSecret(repos.UserRepo, jwtSecret, services.WithTokenBlacklist(tokenBlacklist))
+	auditSinks, err := auditlog.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure audit sinks:", err)
+	}
+	auditService := services.NewAuditService(repos.AuditRepo, auditSinks...)
+
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure mailer:", err)
+	}
+	// Release builds must deliver login and password reset links, not drop them
+	if _, ok := mail.(mailer.LogMailer); ok && gin.Mode() == gin.ReleaseMode {
+		log.Fatal("MAIL_PROVIDER must be smtp, sendgrid or ses in release mode")
+	}
+	mail = mailer.WithSuppression(mail, repos.SuppressionRepo)
+	verifier, err := captcha.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure CAPTCHA:", err)
+	}
+	texts, err := sms.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure SMS:", err)
+	}
+	notificationService := services.NewNotificationService(repos.NotificationRepo, texts)
+
+	listingURL := getEnv("PUBLIC_LISTING_URL", "http://localhost:3000/properties/{id}")
+	flyerConfig := services.FlyerConfig{
+		BrandName:  getEnv("FLYER_BRAND_NAME", ""),
+		ListingURL: listingURL,
+	}
+	imageWorkers := initializeImageWorkers()
+
+	// Listings are pushed to portals as they change, off the event bus
+	syndicationService := services.NewSyndicationService(syndication.NewFromEnv(), repos.SyndicationRepo, repos.PropertyRepo, repos.PublicationRepo, repos.UserRepo,
+		services.SyndicationConfig{PublicBaseURL: getEnv("APP_BASE_URL", "http://localhost:8080"), ListingURL: listingURL})
+	if syndicationService.Enabled() {
+		bus.Subscribe(syndicationService.HandleEvent)
+		go syndicationService.Run(context.Background())
+	}
+
+	// Confirmed showings go to the agents' connected calendars
+	calendarService := services.NewCalendarService(calendar.NewFromEnv(), repos.CalendarRepo, jwtSecret,
+		getEnv("APP_BASE_URL", "http://localhost:8080"))
+
+	crmConnector, err := crm.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure CRM:", err)
+	}
+
+	// Lazy imports queue photo downloads on a few workers of their own
+	photoBackfill, err := worker.NewFromEnv("photo_backfill", "PHOTO_BACKFILL", 2, 1000)
+	if err != nil {
+		log.Fatal("Failed to configure photo backfill workers:", err)
+	}
+	// Photos are served and health-checked from imagesDir even when stored in S3
+	if err := os.MkdirAll(imagesDir(), 0755); err != nil {
+		log.Fatal("Failed to create images directory:", err)
+	}
+	images, err := imageStorage()
+	if err != nil {
+		log.Fatal("Failed to configure image storage:", err)
+	}
+	simplyRETSOptions := []services.SimplyRETSOption{
+		services.WithImageStorage(images), services.WithSettings(settingsService), services.WithAmenities(repos.AmenityRepo), services.WithStorage(storageService),
+		services.WithAlerts(alertService), services.WithImportEvents(bus), services.WithJobHistory(repos.JobRepo),
+		services.WithJobArtifacts("./uploads/artifacts"), services.WithJobManager(jobManager),
+		services.WithPhotoManifest(repos.PhotoManifestRepo), services.WithPhotoBackfill(photoBackfill), services.WithFeatureFlags(featureFlagService),
+	}
+	simplyRETSConfig := services.SimplyRETSConfig{
+		BaseURL:   getEnv("SIMPLYRETS_BASE_URL", ""),
+		Username:  getEnv("SIMPLYRETS_USERNAME", ""),
+		Password:  getEnv("SIMPLYRETS_PASSWORD", ""),
+		ImagesDir: imagesDir(),
+	}
+	if err := simplyRETSConfig.Validate(); err != nil {
+		log.Fatal("Invalid SimplyRETS configuration:", err)
+	}
+	simplyRETSOptions = append(simplyRETSOptions, services.WithConfig(simplyRETSConfig))
+	mlsAuth, err := mlsauth.NewFromEnv("SIMPLYRETS")
+	if err != nil {
+		log.Fatal("Failed to configure SimplyRETS auth:", err)
+	}
+	if mlsAuth != nil {
+		simplyRETSOptions = append(simplyRETSOptions, services.WithAuth(mlsAuth))
+	}
+
+	simplyRETSService := services.NewSimplyRETSService(repos.PropertyRepo, simplyRETSOptions...)
+	syncSchedule, err := services.NewSyncScheduleService(simplyRETSService, settingsService, getEnv("SIMPLYRETS_SYNC_CRON", ""))
+	if err != nil {
+		log.Fatal("Failed to configure scheduled sync:", err)
+	}
+
 	return &Services{
-		AuthService:       services.NewAuthService(repos.UserRepo),
-		PropertyService:   services.NewPropertyService(repos.PropertyRepo),
-		SimplyRETSService: services.NewSimplyRETSService(repos.PropertyRepo),
+		AuthService:        authService,
+		TokenBlacklist:     tokenBlacklist,
+		PropertyService:    propertyService,
+		SimplyRETSService:  simplyRETSService,
+		ImportQuotas:       services.NewImportQuotaService(settingsService),
+		AmenityService:     services.NewAmenityService(repos.AmenityRepo, repos.PropertyRepo),
+		FeatureFlagService: featureFlagService,
+		SettingsService:    settingsService,
+		StaleListings:      services.NewStaleListingService(repos.PropertyRepo, settingsService, services.NewMailStaleNotifier(repos.UserRepo, mail, notificationService)),
+		Enrichment:         initializeEnrichment(repos, settingsService),
+		Storage:            storageService,
+		Photos:             services.NewPhotoService(propertyService, storageService, virusScans, imagesDir(), images),
+		Images:             images,
+		DirectUploads:      initializeDirectUploads(repos, propertyService, storageService, virusScans),
+		UploadSessions:     initializeUploadSessions(repos, propertyService, storageService, virusScans, images),
+		VirusScans:         virusScans,
+		Permissions:        permissionService,
+		Audit:              auditService,
+		Impersonation:      services.NewImpersonationService(authService, repos.UserRepo, auditService),
+		MagicLinks: services.NewMagicLinkService(authService, repos.UserRepo, repos.MagicLinkRepo, mail, settingsService,
+			getEnv("APP_BASE_URL", "http://localhost:8080")),
+		PasswordResets: services.NewPasswordResetService(authService, repos.UserRepo, repos.PasswordResetRepo, mail, settingsService,
+			getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password?token={token}")),
+		LoginGuard:      services.NewLoginGuard(verifier, settingsService, alertService),
+		ServiceAccounts: services.NewServiceAccountService(repos.ServiceAccountRepo, repos.UserRepo, authService, permissionService, auditService),
+		Changes:         services.NewChangeService(repos.ChangeRepo),
+		Events:          bus,
+		PublicImages: services.NewPublicImageService(repos.PropertyRepo, repos.PublicationRepo, settingsService, imageWorkers, imagesDir(), "./uploads/cache/public",
+			getEnv("PUBLIC_WATERMARK_TEXT", "")),
+		ImageWorkers:      imageWorkers,
+		PhotoBackfill:     photoBackfill,
+		EmailSuppressions: services.NewEmailSuppressionService(repos.SuppressionRepo),
+		DataQuality:       services.NewDataQualityService(repos.DataQualityRepo, settingsService, alertService),
+		Archives:          services.NewArchiveService(repos.ArchiveRepo, repos.AmenityRepo, settingsService, bus, imagesDir(), getEnv("ARCHIVE_DIR", "./uploads/archive")),
+		Notifications:     notificationService,
+		Alerts:            alertService,
+		Readiness:         services.NewReadinessService(db, alertService),
+		Health:            initializeHealth(db, simplyRETSService, mail, imageWorkers, virusScanner),
+		Reloader:          initializeReloader(featureFlagService, settingsService),
+		FieldEncryption:   services.NewFieldEncryptionService(repos.EncryptedFieldRepo, cipher),
+		Inbox:             inbox,
+		Activity:          activity,
+		Flyers:            services.NewFlyerService(propertyService, repos.UserRepo, imageWorkers, imagesDir(), "./uploads/cache/flyers", flyerConfig),
+		Syndication:       syndicationService,
+		Leads:             services.NewLeadService(repos.LeadRepo, repos.PropertyRepo, repos.UserRepo, leads.SecretsFromEnv(), notificationService, bus),
+		Calendars:         calendarService,
+		Showings:          services.NewShowingService(repos.ShowingRepo, propertyService, calendarService),
+		CRM:               services.NewCRMService(crmConnector, repos.CRMRepo, repos.PropertyRepo, repos.UserRepo, settingsService),
+		Deals:             services.NewDealService(repos.DealRepo, propertyService, repos.UserRepo),
+		Inspections:       services.NewInspectionService(repos.InspectionRepo, propertyService, repos.UploadRepo, repos.UserRepo),
+		Offers:            services.NewOfferService(repos.OfferRepo, propertyService, bus),
+		Signatures:        initializeSignatures(repos, propertyService),
+		Contacts:          services.NewContactService(repos.ContactRepo, repos.LeadRepo, repos.ShowingRepo, repos.DealRepo, propertyService),
+		Valuations:        services.NewValuationService(repos.ValuationRepo, propertyService, repos.EnrichmentRepo),
+		Market:            services.NewMarketService(repos.MarketRepo),
+		Views:             services.NewViewService(repos.ViewRepo, propertyService),
+		Exports:           services.NewExportService(auditService, repos.UserRepo, settingsService),
+		IPAccess:          services.NewIPAccessService(settingsService),
+		SyncSchedule:      syncSchedule,
+		Favorites:         services.NewFavoriteService(repos.FavoriteRepo, repos.SavedSearchRepo, propertyService),
+		Recommendations:   services.NewRecommendationService(repos.RecommendationRepo, repos.FavoriteRepo, repos.ViewRepo, repos.SavedSearchRepo),
+		Publications:      services.NewPublicationService(repos.PublicationRepo, propertyService, bus),
+		Runtime:           services.NewRuntimeService(db, jobManager, imageWorkers, photoBackfill),
+		PublicIDs:         services.NewPublicIDService(repos.PropertyRepo, repos.UserRepo),
+		ListingExpiry:     services.NewListingExpiryService(repos.PropertyRepo, settingsService, bus),
 	}
 }
 
+// runStartupDiagnostics checks the server can work before it starts
+// listening. STARTUP_DIAGNOSTICS picks what happens on failures: fail
+// (default) refuses to start when a required check fails, strict when any
+// does, warn only logs them and off skips the checks.
+func runStartupDiagnostics(db *sql.DB, simplyRETS *services.SimplyRETSService, jwtSecret string) {
+	mode := getEnv("STARTUP_DIAGNOSTICS", services.DiagnosticsFailFast)
+	switch mode {
+	case services.DiagnosticsOff:
+		return
+	case services.DiagnosticsFailFast, services.DiagnosticsStrict, services.DiagnosticsWarn:
+	default:
+		log.Fatalf("Invalid STARTUP_DIAGNOSTICS %q: use fail, strict, warn or off", mode)
+	}
+
+	diagnostics := []services.Diagnostic{
+		{Name: "migrations", Required: true, Check: services.CheckFunc(func(ctx context.Context) error {
+			return database.CheckMigrations(db, "./migrations")
+		})},
+		{Name: "uploads", Required: true, Check: services.DirCheck(imagesDir())},
+		// The default secret is tolerated outside release builds
+		{Name: "jwt_secret", Required: gin.Mode() == gin.ReleaseMode, Check: services.JWTSecretCheck(jwtSecret)},
+		{Name: "simplyrets", Check: simplyRETS},
+	}
+	if store := objectstore.NewS3StoreFromEnv(); store != nil {
+		diagnostics = append(diagnostics, services.Diagnostic{Name: "object_storage", Required: true, Check: store})
+	}
+
+	report := services.RunDiagnostics(context.Background(), diagnostics)
+	log.Printf("Startup diagnostics:\n%s", report)
+	if report.Refuse(mode) {
+		log.Fatal("Refusing to start: startup diagnostics failed")
+	}
+}
+
+// initializeHealth registers the dependencies reported by
+// /api/health/details. Only MySQL is critical: without the others the
+// server still serves requests, just not every feature.
+func initializeHealth(db *sql.DB, simplyRETS *services.SimplyRETSService, mail mailer.Mailer, imageWorkers *worker.Pool, virusScanner scanner.Scanner) *services.HealthService {
+	health := services.NewHealthService()
+	health.Register("mysql", true, services.PingCheck(db))
+	health.Register("storage", false, services.DirCheck(imagesDir()))
+	if store := objectstore.NewS3StoreFromEnv(); store != nil {
+		health.Register("object_storage", false, store)
+	}
+	health.Register("simplyrets", false, simplyRETS)
+	health.Register("mailer", false, services.CheckFunc(func(ctx context.Context) error {
+		return mailer.Check(ctx, mail)
+	}))
+	health.Register("image_workers", false, services.CheckFunc(func(ctx context.Context) error {
+		if stats := imageWorkers.Stats(); stats.Queued >= stats.Capacity {
+			return fmt.Errorf("image queue is full: %d queued, %d running", stats.Queued, stats.Running)
+		}
+		return nil
+	}))
+	if virusScanner != nil {
+		health.Register("virus_scanner", false, virusScanner)
+	}
+	return health
+}
+
+// initializeVirusScans scans uploads with the clamd at CLAMAV_ADDRESS.
+// Without it uploads are stored unscanned.
+func initializeVirusScans(repos *Repositories) (scanner.Scanner, *services.VirusScanService) {
+	virusScanner, err := scanner.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure virus scanner:", err)
+	}
+	if virusScanner == nil {
+		log.Println("Warning: CLAMAV_ADDRESS not set, uploads are not scanned for viruses")
+	}
+	return virusScanner, services.NewVirusScanService(virusScanner, repos.FileScanRepo, "./uploads/quarantine")
+}
+
+// initializeImageWorkers starts the pool that runs image resizing and flyer
+// rendering, sized by IMAGE_WORKERS and IMAGE_QUEUE_SIZE. By default it
+// uses half the CPUs so API requests and imports keep the rest.
+func initializeImageWorkers() *worker.Pool {
+	pool, err := worker.NewFromEnv("images", "IMAGE", max(1, runtime.NumCPU()/2), 64)
+	if err != nil {
+		log.Fatal("Failed to configure image workers:", err)
+	}
+	return pool
+}
+
+// initializeDirectUploads returns nil unless an S3 bucket is configured
+func initializeDirectUploads(repos *Repositories, properties *services.PropertyService, storage *services.StorageService, scans *services.VirusScanService) *services.DirectUploadService {
+	store := objectstore.NewS3StoreFromEnv()
+	if store == nil {
+		log.Println("Direct uploads disabled: S3_BUCKET not set")
+		return nil
+	}
+	return services.NewDirectUploadService(store, repos.UploadRepo, properties, storage, scans)
+}
+
+// initializeUploadSessions takes resumable uploads, staged in
+// UPLOAD_SESSIONS_DIR until complete. Documents are stored in the S3 bucket
+// direct uploads go to, so without it only photos are taken.
+func initializeUploadSessions(repos *Repositories, properties *services.PropertyService, storage *services.StorageService,
+	scans *services.VirusScanService, images imagestore.Storage) *services.UploadSessionService {
+	var documents services.DocumentStore
+	if store := objectstore.NewS3StoreFromEnv(); store != nil {
+		documents = store
+	}
+	return services.NewUploadSessionService(repos.UploadSessionRepo, repos.UploadRepo, documents, properties, storage, scans, images,
+		getEnv("UPLOAD_SESSIONS_DIR", "./uploads/sessions"))
+}
+
+// initializeSignatures sends documents for signature through the
+// ESIGN_PROVIDER, reading them from the S3 bucket direct uploads go to
+func initializeSignatures(repos *Repositories, properties *services.PropertyService) *services.SignatureService {
+	provider, err := esign.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure e-signatures:", err)
+	}
+	var store services.ObjectStore
+	if s3 := objectstore.NewS3StoreFromEnv(); s3 != nil {
+		store = s3
+	}
+	if provider != nil && store == nil {
+		log.Println("Warning: ESIGN_PROVIDER is set but S3_BUCKET is not, documents cannot be sent for signature")
+	}
+	return services.NewSignatureService(repos.SignatureRepo, provider, store, properties, repos.UploadRepo, repos.OfferRepo)
+}
+
+func initializeEnrichment(repos *Repositories, settings *services.SettingsService) *services.EnrichmentService {
+	geocoder, providers := enrichment.NewFromEnv()
+	if geocoder == nil {
+		log.Println("Property enrichment disabled")
+	}
+	return services.NewEnrichmentService(repos.EnrichmentRepo, geocoder, providers, settings)
+}
+
+func startScheduler(services *Services) *scheduler.Scheduler {
+	sched := scheduler.New()
+	sched.EveryFunc("simplyrets-sync", services.SyncSchedule.Interval, services.SyncSchedule.Run)
+	// Schedule changes, here or on another instance, apply right away
+	services.SyncSchedule.OnChange(func() { sched.Wake("simplyrets-sync") })
+	sched.Every("stale-listings", time.Hour, func(ctx context.Context) error {
+		count, err := services.StaleListings.DetectStale(ctx)
+		if err == nil && count > 0 {
+			log.Printf("Flagged %d stale listings", count)
+		}
+		return err
+	})
+	sched.Every("listing-expiry", time.Hour, func(ctx context.Context) error {
+		expired, err := services.ListingExpiry.ExpireListings(ctx)
+		if err != nil {
+			return err
+		}
+		notified, err := services.ListingExpiry.NotifyExpiring(ctx)
+		if expired > 0 || notified > 0 {
+			log.Printf("Expired %d listings, warned agents about %d expiring", expired, notified)
+		}
+		return err
+	})
+	if services.Enrichment.Enabled() {
+		sched.Every("property-enrichment", time.Hour, func(ctx context.Context) error {
+			count, err := services.Enrichment.RefreshDue(ctx)
+			if err == nil && count > 0 {
+				log.Printf("Enriched %d properties", count)
+			}
+			return err
+		})
+	}
+	if services.Syndication.Enabled() {
+		sched.Every("syndication", 15*time.Minute, func(ctx context.Context) error {
+			count, err := services.Syndication.SyncDue(ctx)
+			if err == nil && count > 0 {
+				log.Printf("Synced %d listings to portals", count)
+			}
+			return err
+		})
+	}
+	sched.Every("revoked-token-cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := services.TokenBlacklist.Prune(ctx)
+		return err
+	})
+	sched.Every("activity-cleanup", 24*time.Hour, func(ctx context.Context) error {
+		_, err := services.Activity.Prune(ctx)
+		return err
+	})
+	sched.Every("market-reports", 6*time.Hour, func(ctx context.Context) error {
+		count, err := services.Market.Refresh(ctx)
+		if err == nil {
+			log.Printf("Refreshed market statistics for %d area-months", count)
+		}
+		return err
+	})
+	sched.Every("market-snapshot", 24*time.Hour, func(ctx context.Context) error {
+		count, err := services.Market.Snapshot(ctx)
+		if err == nil {
+			log.Printf("Snapshotted inventory for %d city-statuses", count)
+		}
+		return err
+	})
+	sched.Every("data-quality", 24*time.Hour, func(ctx context.Context) error {
+		report, err := services.DataQuality.Run(ctx)
+		if err != nil {
+			return err
+		}
+		if report.Exceeded {
+			log.Printf("Data-quality report %d found anomalies over threshold", report.ID)
+		}
+		_, err = services.DataQuality.Prune(ctx)
+		return err
+	})
+	sched.Every("upload-session-cleanup", time.Hour, func(ctx context.Context) error {
+		count, err := services.UploadSessions.Clean(ctx)
+		if err == nil && count > 0 {
+			log.Printf("Removed %d expired upload sessions", count)
+		}
+		return err
+	})
+	sched.Every("listing-archive", 24*time.Hour, func(ctx context.Context) error {
+		run, err := services.Archives.ArchiveDue(ctx)
+		if err == nil && run.Archived > 0 {
+			log.Printf("Archived %d listings (%d bytes)", run.Archived, run.Bytes)
+		}
+		return err
+	})
+	sched.Every("recommendations", time.Hour, func(ctx context.Context) error {
+		count, err := services.Recommendations.Refresh(ctx)
+		if err == nil && count > 0 {
+			log.Printf("Refreshed recommendations for %d users", count)
+		}
+		return err
+	})
+	if services.CRM.Enabled() {
+		sched.Every("crm-export", 10*time.Minute, func(ctx context.Context) error {
+			count, err := services.CRM.SyncDue(ctx)
+			if err == nil && count > 0 {
+				log.Printf("Exported %d leads to the CRM", count)
+			}
+			return err
+		})
+	}
+	if services.FieldEncryption.Enabled() {
+		sched.Every("field-encryption-rotation", time.Hour, func(ctx context.Context) error {
+			count, err := services.FieldEncryption.RotateAll(ctx)
+			if count > 0 {
+				log.Printf("Encrypted %d sensitive values with the primary key", count)
+			}
+			return err
+		})
+	}
+	sched.Start(context.Background())
+	return sched
+}
+
 type Handlers struct {
-	AuthHandler       *handlers.AuthHandler
-	PropertyHandler   *handlers.PropertyHandler
-	SimplyRETSHandler *handlers.SimplyRETSHandler
+	AuthHandler           *handlers.AuthHandler
+	PropertyHandler       *handlers.PropertyHandler
+	SimplyRETSHandler     *handlers.SimplyRETSHandler
+	AdminHandler          *handlers.AdminHandler
+	AmenityHandler        *handlers.AmenityHandler
+	EnrichmentHandler     *handlers.EnrichmentHandler
+	PhotoHandler          *handlers.PhotoHandler
+	ImageHandler          *handlers.ImageHandler
+	UploadHandler         *handlers.UploadHandler
+	UploadSessionHandler  *handlers.UploadSessionHandler
+	RoleHandler           *handlers.RoleHandler
+	ImpersonationHandler  *handlers.ImpersonationHandler
+	MagicLinkHandler      *handlers.MagicLinkHandler
+	PasswordResetHandler  *handlers.PasswordResetHandler
+	ServiceAccountHandler *handlers.ServiceAccountHandler
+	ChangeHandler         *handlers.ChangeHandler
+	PublicImageHandler    *handlers.PublicImageHandler
+	SuppressionHandler    *handlers.EmailSuppressionHandler
+	DataQualityHandler    *handlers.DataQualityHandler
+	ArchiveHandler        *handlers.ArchiveHandler
+	NotificationHandler   *handlers.NotificationHandler
+	ActivityHandler       *handlers.ActivityHandler
+	HealthHandler         *handlers.HealthHandler
+	FlyerHandler          *handlers.FlyerHandler
+	SyndicationHandler    *handlers.SyndicationHandler
+	LeadHandler           *handlers.LeadHandler
+	CalendarHandler       *handlers.CalendarHandler
+	ShowingHandler        *handlers.ShowingHandler
+	CRMHandler            *handlers.CRMHandler
+	DealHandler           *handlers.DealHandler
+	InspectionHandler     *handlers.InspectionHandler
+	OfferHandler          *handlers.OfferHandler
+	SignatureHandler      *handlers.SignatureHandler
+	ContactHandler        *handlers.ContactHandler
+	ValuationHandler      *handlers.ValuationHandler
+	MarketHandler         *handlers.MarketHandler
+	ViewHandler           *handlers.ViewHandler
+	FavoriteHandler       *handlers.FavoriteHandler
+	RecommendationHandler *handlers.RecommendationHandler
+	FileScanHandler       *handlers.FileScanHandler
+	PublicationHandler    *handlers.PublicationHandler
+	DebugHandler          *handlers.DebugHandler
+	PublicIDHandler       *handlers.PublicIDHandler
 }
 
 func initializeHandlers(repos *Repositories, services *Services) *Handlers {
+	var uploadHandler *handlers.UploadHandler
+	if services.DirectUploads != nil {
+		uploadHandler = handlers.NewUploadHandler(services.DirectUploads)
+	}
+
 	return &Handlers{
-		AuthHandler:       handlers.NewAuthHandler(repos.UserRepo),
-		PropertyHandler:   handlers.NewPropertyHandler(services.PropertyService),
-		SimplyRETSHandler: handlers.NewSimplyRETSHandler(services.SimplyRETSService),
+		AuthHandler:           handlers.NewAuthHandler(services.AuthService, services.Audit),
+		PropertyHandler:       handlers.NewPropertyHandler(services.PropertyService, services.Views, services.Exports),
+		SimplyRETSHandler:     handlers.NewSimplyRETSHandler(services.SimplyRETSService, services.ImportQuotas, services.SyncSchedule),
+		AdminHandler:          handlers.NewAdminHandler(services.FeatureFlagService, services.SettingsService, services.Storage, services.Reloader, services.IPAccess),
+		AmenityHandler:        handlers.NewAmenityHandler(services.AmenityService),
+		EnrichmentHandler:     handlers.NewEnrichmentHandler(services.Enrichment),
+		PhotoHandler:          handlers.NewPhotoHandler(services.Photos),
+		ImageHandler:          handlers.NewImageHandler(services.Images),
+		UploadHandler:         uploadHandler,
+		UploadSessionHandler:  handlers.NewUploadSessionHandler(services.UploadSessions),
+		RoleHandler:           handlers.NewRoleHandler(services.Permissions),
+		ImpersonationHandler:  handlers.NewImpersonationHandler(services.Impersonation, services.Audit),
+		MagicLinkHandler:      handlers.NewMagicLinkHandler(services.MagicLinks),
+		PasswordResetHandler:  handlers.NewPasswordResetHandler(services.PasswordResets),
+		ServiceAccountHandler: handlers.NewServiceAccountHandler(services.ServiceAccounts),
+		ChangeHandler:         handlers.NewChangeHandler(services.Changes),
+		PublicImageHandler:    handlers.NewPublicImageHandler(services.PublicImages),
+		SuppressionHandler:    handlers.NewEmailSuppressionHandler(services.EmailSuppressions),
+		DataQualityHandler:    handlers.NewDataQualityHandler(services.DataQuality),
+		ArchiveHandler:        handlers.NewArchiveHandler(services.Archives),
+		NotificationHandler:   handlers.NewNotificationHandler(services.Notifications, services.Inbox),
+		ActivityHandler:       handlers.NewActivityHandler(services.Activity),
+		HealthHandler:         handlers.NewHealthHandler(services.Readiness, services.Health),
+		FlyerHandler:          handlers.NewFlyerHandler(services.Flyers),
+		SyndicationHandler:    handlers.NewSyndicationHandler(services.Syndication),
+		LeadHandler:           handlers.NewLeadHandler(services.Leads),
+		CalendarHandler:       handlers.NewCalendarHandler(services.Calendars),
+		ShowingHandler:        handlers.NewShowingHandler(services.Showings),
+		CRMHandler:            handlers.NewCRMHandler(services.CRM),
+		DealHandler:           handlers.NewDealHandler(services.Deals, services.Notifications),
+		InspectionHandler:     handlers.NewInspectionHandler(services.Inspections),
+		OfferHandler:          handlers.NewOfferHandler(services.Offers),
+		SignatureHandler:      handlers.NewSignatureHandler(services.Signatures),
+		ContactHandler:        handlers.NewContactHandler(services.Contacts),
+		ValuationHandler:      handlers.NewValuationHandler(services.Valuations),
+		MarketHandler:         handlers.NewMarketHandler(services.Market, services.Notifications),
+		ViewHandler:           handlers.NewViewHandler(services.Views),
+		FavoriteHandler:       handlers.NewFavoriteHandler(services.Favorites),
+		RecommendationHandler: handlers.NewRecommendationHandler(services.Recommendations),
+		FileScanHandler:       handlers.NewFileScanHandler(services.VirusScans),
+		PublicationHandler:    handlers.NewPublicationHandler(services.Publications),
+		DebugHandler:          handlers.NewDebugHandler(services.Runtime),
+		PublicIDHandler:       handlers.NewPublicIDHandler(services.PublicIDs),
 	}
 }
 
-func setupRouter(handlers *Handlers, authService *services.AuthService) *gin.Engine {
-	r := gin.Default()
+func setupRouter(handlers *Handlers, origins *middleware.OriginList, shedder *middleware.LoadShedder, authService *services.AuthService, permissions *services.PermissionService, audit *services.AuditService, guard *services.LoginGuard, ipAccess *services.IPAccessService, featureFlags *services.FeatureFlagService) *gin.Engine {
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+	r.Use(gin.Recovery(), middleware.RequestID(), middleware.AccessLog(), middleware.IPDenylist(ipAccess), shedder.Track(), middleware.AuditImpersonation(audit))
 
 	// CORS middleware for frontend
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowOriginFunc:  origins.Allow,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIDHeader, middleware.CaptchaHeader, "Upload-Offset"},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader, "Upload-Offset"},
 		AllowCredentials: true,
 	}))
 
-	// Static file serving for images
-	r.Static("/images", "./uploads/images")
+	// Photo URLs in responses point at the CDN when CDN_BASE_URL is set; it
+	// fetches them from /images
+	cdn, err := services.NewPhotoCDN(getEnv("CDN_BASE_URL", ""), imagesDir())
+	if err != nil {
+		log.Fatal("Invalid CDN_BASE_URL:", err)
+	}
+	r.Use(middleware.PhotoCDN(cdn))
+
+	// Readiness probe for load balancers and orchestrators
+	r.GET("/ready", middleware.SkipAccessLog(), handlers.HealthHandler.Ready)
+
+	// Photos, from local disk or the bucket IMAGE_STORAGE selects
+	r.GET("/images/:name", handlers.ImageHandler.GetImage)
+	r.HEAD("/images/:name", handlers.ImageHandler.GetImage)
+
+	// Resized, watermarked photos for public listing sites, while the
+	// public_api flag is on. Embedding is limited to
+	// PUBLIC_IMAGES_ALLOWED_REFERERS (comma-separated hosts).
+	public := r.Group("/public", middleware.RequireFeature(featureFlags, services.FlagPublicAPI), middleware.HotlinkProtection(strings.Split(getEnv("PUBLIC_IMAGES_ALLOWED_REFERERS", ""), ",")))
+	public.GET("/images/:id/:index", handlers.PublicIDHandler.Property("id"), handlers.PublicImageHandler.GetImage)
 
-	setupAPIRoutes(r, handlers, authService)
+	// Profiling and runtime stats, for admins when DEBUG_ENDPOINTS=true
+	if getEnv("DEBUG_ENDPOINTS", "false") == "true" {
+		debug := r.Group("/debug", middleware.AdminIPAllowlist(ipAccess), middleware.AuthMiddleware(authService), middleware.RequireAdmin())
+		debug.GET("/stats", handlers.DebugHandler.GetStats)
+		debug.GET("/pprof/*profile", handlers.DebugHandler.Profile)
+		debug.POST("/pprof/*profile", handlers.DebugHandler.Profile)
+	}
+
+	setupAPIRoutes(r.Group("/api"), handlers, shedder, authService, permissions, guard, ipAccess)
+	// The same routes, answering in the {"data", "meta", "errors"} envelope
+	setupAPIRoutes(r.Group("/api/v1", envelope.Versioned()), handlers, shedder, authService, permissions, guard, ipAccess)
 
 	return r
 }
 
-func setupAPIRoutes(r *gin.Engine, handlers *Handlers, authService *services.AuthService) {
-	api := r.Group("/api")
+func setupAPIRoutes(api *gin.RouterGroup, handlers *Handlers, shedder *middleware.LoadShedder, authService *services.AuthService, permissions *services.PermissionService, guard *services.LoginGuard, ipAccess *services.IPAccessService) {
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(permissions, permission)
+	}
+	api.Use(requestTimeouts(api.BasePath()))
+	// Polling and analytics give way to CRUD traffic under load
+	lowPriority := shedder.LowPriority()
+	// Property and user routes take public IDs as well as numeric ones
+	propertyID := handlers.PublicIDHandler.Property("id")
+	userID := handlers.PublicIDHandler.User("id")
+
 	{
 		// Authentication routes
-		api.POST("/register", handlers.AuthHandler.Register)
-		api.POST("/login", handlers.AuthHandler.Login)
+		bruteForce := middleware.BruteForceProtection(guard)
+		api.POST("/register", bruteForce, handlers.AuthHandler.Register)
+		api.POST("/login", bruteForce, handlers.AuthHandler.Login)
+		api.POST("/auth/magic-link", handlers.MagicLinkHandler.RequestLink)
+		api.GET("/auth/magic/callback", handlers.MagicLinkHandler.Callback)
+		api.POST("/password-reset/request", handlers.PasswordResetHandler.RequestReset)
+		api.POST("/password-reset/confirm", bruteForce, handlers.PasswordResetHandler.ConfirmReset)
+
+		// Lead webhooks, authenticated by their signature
+		api.POST("/integrations/leads/:source", handlers.LeadHandler.IngestLead)
+
+		// E-signature status callbacks, authenticated by their signature
+		api.POST("/integrations/esign/callback", handlers.SignatureHandler.Callback)
+
+		// Calendar OAuth redirect, authenticated by its signed state
+		api.GET("/calendar/:provider/callback", handlers.CalendarHandler.Callback)
+
+		// Per-dependency health for ops dashboards
+		api.GET("/health/details", lowPriority, middleware.AdminIPAllowlist(ipAccess), middleware.AuthMiddleware(authService), middleware.RequireAdmin(), handlers.HealthHandler.Details)
 
 		// SimplyRETS integration routes (protected)
 		simplyrets := api.Group("/simplyrets")
 		simplyrets.Use(middleware.AuthMiddleware(authService))
 		{
-			simplyrets.POST("/process", handlers.SimplyRETSHandler.StartProcessing)
-			simplyrets.GET("/jobs/:jobId/status", handlers.SimplyRETSHandler.GetJobStatus)
-			simplyrets.DELETE("/jobs/:jobId", handlers.SimplyRETSHandler.CancelJob)
-			simplyrets.GET("/health", handlers.SimplyRETSHandler.HealthCheck)
+			simplyrets.POST("/process", can(services.PermJobsRun), handlers.SimplyRETSHandler.StartProcessing)
+			simplyrets.GET("/jobs", can(services.PermJobsRead), handlers.SimplyRETSHandler.GetProcessingHistory)
+			// Polled by the frontend while a job runs, so kept out of the access log
+			simplyrets.GET("/jobs/:jobId/status", middleware.SkipAccessLog(), lowPriority, can(services.PermJobsRead), handlers.SimplyRETSHandler.GetJobStatus)
+			simplyrets.GET("/jobs/:jobId/artifacts/:name", can(services.PermJobsRead), handlers.SimplyRETSHandler.GetJobArtifact)
+			simplyrets.POST("/jobs/:jobId/resume", can(services.PermJobsRun), handlers.SimplyRETSHandler.ResumeJob)
+			simplyrets.DELETE("/jobs/:jobId", can(services.PermJobsCancel), handlers.SimplyRETSHandler.CancelJob)
+			simplyrets.GET("/health", lowPriority, handlers.SimplyRETSHandler.HealthCheck)
+			simplyrets.GET("/quota", handlers.SimplyRETSHandler.GetQuota)
+			simplyrets.GET("/schedule", can(services.PermJobsRead), handlers.SimplyRETSHandler.GetSchedule)
+			simplyrets.PUT("/schedule", middleware.AdminIPAllowlist(ipAccess), middleware.RequireAdmin(), handlers.SimplyRETSHandler.UpdateSchedule)
 		}
 
 		// Protected routes
 		protected := api.Group("/")
 		protected.Use(middleware.AuthMiddleware(authService))
 		{
-			protected.GET("/properties", handlers.PropertyHandler.GetProperties)
-			protected.GET("/properties/:id", handlers.PropertyHandler.GetProperty)
-			protected.POST("/properties", handlers.PropertyHandler.CreateProperty)
-			protected.PUT("/properties/:id", handlers.PropertyHandler.UpdateProperty)
-			protected.DELETE("/properties/:id", handlers.PropertyHandler.DeleteProperty)
+			protected.POST("/logout", handlers.AuthHandler.Logout)
+			protected.GET("/properties", can(services.PermPropertiesRead), handlers.PropertyHandler.GetProperties)
+			protected.GET("/properties/export", can(services.PermPropertiesExport), handlers.PropertyHandler.ExportProperties)
+			protected.GET("/properties/facets", can(services.PermPropertiesRead), handlers.PropertyHandler.GetPropertyFacets)
+			// Archived listings are no longer properties, so :id is not resolved
+			// by propertyID; the handler takes either ID
+			protected.GET("/properties/archived", can(services.PermPropertiesRead), handlers.ArchiveHandler.GetArchived)
+			protected.POST("/properties/archived/:id/unarchive", can(services.PermPropertiesUpdate), handlers.ArchiveHandler.Unarchive)
+			protected.GET("/properties/:id", can(services.PermPropertiesRead), propertyID, handlers.PropertyHandler.GetProperty)
+			protected.POST("/properties", can(services.PermPropertiesCreate), handlers.PropertyHandler.CreateProperty)
+			protected.POST("/properties/bulk-update", can(services.PermPropertiesBulkUpdate), handlers.PropertyHandler.BulkUpdate)
+			protected.PUT("/properties/:id", can(services.PermPropertiesUpdate), propertyID, handlers.PropertyHandler.UpdateProperty)
+			protected.POST("/properties/:id/photos", can(services.PermPropertiesUpdate), propertyID, handlers.PhotoHandler.UploadPhoto)
+			protected.PATCH("/properties/:id/photos/order", can(services.PermPropertiesUpdate), propertyID, handlers.PhotoHandler.ReorderPhotos)
+			protected.PUT("/properties/:id/photos/cover", can(services.PermPropertiesUpdate), propertyID, handlers.PhotoHandler.SetCoverPhoto)
+			protected.PATCH("/properties/:id/photos/:index", can(services.PermPropertiesUpdate), propertyID, handlers.PhotoHandler.UpdatePhoto)
+			protected.GET("/properties/:id/revisions", can(services.PermPropertiesRead), propertyID, handlers.PropertyHandler.GetRevisions)
+			protected.GET("/properties/:id/amenities", can(services.PermPropertiesRead), propertyID, handlers.AmenityHandler.GetAmenities)
+			protected.PUT("/properties/:id/amenities", can(services.PermPropertiesUpdate), propertyID, handlers.AmenityHandler.UpdateAmenities)
+			protected.GET("/properties/:id/enrichment", can(services.PermPropertiesRead), propertyID, handlers.EnrichmentHandler.GetEnrichment)
+			protected.GET("/properties/:id/estimate", can(services.PermPropertiesRead), propertyID, handlers.ValuationHandler.GetEstimate)
+			protected.GET("/properties/:id/views", lowPriority, can(services.PermPropertiesRead), propertyID, handlers.ViewHandler.GetViewStats)
+			protected.GET("/properties/:id/flyer.pdf", can(services.PermPropertiesRead), propertyID, handlers.FlyerHandler.GetFlyer)
+			protected.GET("/properties/:id/publication", can(services.PermPropertiesRead), propertyID, handlers.PublicationHandler.GetPublication)
+			protected.POST("/properties/:id/publication/submit", can(services.PermPropertiesUpdate), propertyID, handlers.PublicationHandler.Submit)
+			protected.POST("/properties/:id/publication/unpublish", can(services.PermPropertiesUpdate), propertyID, handlers.PublicationHandler.Unpublish)
+			protected.GET("/properties/:id/syndication", can(services.PermPropertiesRead), propertyID, handlers.SyndicationHandler.GetSyndication)
+			protected.POST("/properties/:id/syndication/:portal", can(services.PermPropertiesSyndicate), propertyID, handlers.SyndicationHandler.Publish)
+			protected.DELETE("/properties/:id/syndication/:portal", can(services.PermPropertiesSyndicate), propertyID, handlers.SyndicationHandler.Unpublish)
+			protected.GET("/properties/:id/showings", can(services.PermPropertiesRead), propertyID, handlers.ShowingHandler.GetShowings)
+			protected.POST("/properties/:id/showings", can(services.PermPropertiesUpdate), propertyID, handlers.ShowingHandler.CreateShowing)
+			protected.POST("/showings/:id/confirm", can(services.PermPropertiesUpdate), handlers.ShowingHandler.ConfirmShowing)
+			protected.POST("/properties/:id/revert/:revisionId", can(services.PermPropertiesUpdate), propertyID, handlers.PropertyHandler.RevertProperty)
+			protected.DELETE("/properties/:id", can(services.PermPropertiesDelete), propertyID, handlers.PropertyHandler.DeleteProperty)
+			protected.GET("/changes", can(services.PermPropertiesRead), handlers.ChangeHandler.GetChanges)
+			protected.POST("/sync", can(services.PermPropertiesRead), handlers.PropertyHandler.Sync)
+			protected.GET("/leads", handlers.LeadHandler.GetLeads)
+			protected.GET("/deals", can(services.PermDealsRead), handlers.DealHandler.GetDeals)
+			protected.GET("/deals/pipeline", lowPriority, can(services.PermDealsRead), handlers.DealHandler.GetPipeline)
+			protected.GET("/deals/:id", can(services.PermDealsRead), handlers.DealHandler.GetDeal)
+			protected.POST("/deals", can(services.PermDealsWrite), handlers.DealHandler.CreateDeal)
+			protected.PUT("/deals/:id", can(services.PermDealsWrite), handlers.DealHandler.UpdateDeal)
+			protected.DELETE("/deals/:id", can(services.PermDealsWrite), handlers.DealHandler.DeleteDeal)
+			protected.GET("/reports/revenue", lowPriority, can(services.PermDealsRead), handlers.DealHandler.GetRevenueReport)
+			protected.GET("/properties/:id/offers", can(services.PermDealsRead), propertyID, handlers.OfferHandler.GetOfferDashboard)
+			protected.POST("/properties/:id/offers", can(services.PermDealsWrite), propertyID, handlers.OfferHandler.CreateOffer)
+			protected.GET("/offers/:id", can(services.PermDealsRead), handlers.OfferHandler.GetOffer)
+			protected.POST("/offers/:id/status", can(services.PermDealsWrite), handlers.OfferHandler.TransitionOffer)
+			protected.GET("/properties/:id/signatures", can(services.PermDealsRead), propertyID, handlers.SignatureHandler.GetSignatureRequests)
+			protected.POST("/properties/:id/signatures", can(services.PermDealsWrite), propertyID, handlers.SignatureHandler.SendForSignature)
+			protected.GET("/signatures/:id", can(services.PermDealsRead), handlers.SignatureHandler.GetSignatureRequest)
+			protected.POST("/signatures/:id/void", can(services.PermDealsWrite), handlers.SignatureHandler.VoidSignatureRequest)
+			protected.GET("/contacts", can(services.PermContactsRead), handlers.ContactHandler.GetContacts)
+			protected.POST("/contacts", can(services.PermContactsWrite), handlers.ContactHandler.CreateContact)
+			protected.GET("/contacts/:id", can(services.PermContactsRead), handlers.ContactHandler.GetContact)
+			protected.PUT("/contacts/:id", can(services.PermContactsWrite), handlers.ContactHandler.UpdateContact)
+			protected.DELETE("/contacts/:id", can(services.PermContactsWrite), handlers.ContactHandler.DeleteContact)
+			protected.POST("/contacts/:id/links", can(services.PermContactsWrite), handlers.ContactHandler.LinkContact)
+			protected.DELETE("/contacts/:id/links/:type/:entityId", can(services.PermContactsWrite), handlers.ContactHandler.UnlinkContact)
+			protected.GET("/properties/:id/inspections", can(services.PermInspectionsRead), propertyID, handlers.InspectionHandler.GetInspections)
+			protected.POST("/properties/:id/inspections", can(services.PermInspectionsWrite), propertyID, handlers.InspectionHandler.CreateInspection)
+			protected.GET("/properties/:id/punch-list", can(services.PermInspectionsRead), propertyID, handlers.InspectionHandler.GetPunchList)
+			protected.GET("/inspections/:id", can(services.PermInspectionsRead), handlers.InspectionHandler.GetInspection)
+			protected.PUT("/inspections/:id", can(services.PermInspectionsWrite), handlers.InspectionHandler.UpdateInspection)
+			protected.DELETE("/inspections/:id", can(services.PermInspectionsWrite), handlers.InspectionHandler.DeleteInspection)
+			protected.POST("/inspections/:id/reports", can(services.PermInspectionsWrite), handlers.InspectionHandler.AttachReport)
+			protected.DELETE("/inspections/:id/reports/:uploadId", can(services.PermInspectionsWrite), handlers.InspectionHandler.DetachReport)
+			protected.POST("/inspections/:id/repairs", can(services.PermInspectionsWrite), handlers.InspectionHandler.CreateRepair)
+			protected.PUT("/repairs/:id", can(services.PermInspectionsWrite), handlers.InspectionHandler.UpdateRepair)
+			protected.GET("/reports/market", lowPriority, can(services.PermPropertiesRead), handlers.MarketHandler.GetMarketReport)
+			protected.GET("/reports/market/inventory", lowPriority, can(services.PermPropertiesRead), handlers.MarketHandler.GetInventoryTrend)
+			protected.GET("/calendar/connections", handlers.CalendarHandler.GetConnections)
+			protected.GET("/calendar/:provider/connect", handlers.CalendarHandler.Connect)
+			protected.DELETE("/calendar/:provider", handlers.CalendarHandler.Disconnect)
+			protected.GET("/me/recently-viewed", lowPriority, can(services.PermPropertiesRead), handlers.ViewHandler.GetRecentlyViewed)
+			protected.GET("/me/favorites", can(services.PermPropertiesRead), handlers.FavoriteHandler.GetFavorites)
+			protected.PUT("/me/favorites/:id", can(services.PermPropertiesRead), propertyID, handlers.FavoriteHandler.AddFavorite)
+			protected.DELETE("/me/favorites/:id", can(services.PermPropertiesRead), propertyID, handlers.FavoriteHandler.RemoveFavorite)
+			protected.GET("/me/saved-searches", can(services.PermPropertiesRead), handlers.FavoriteHandler.GetSavedSearches)
+			protected.POST("/me/saved-searches", can(services.PermPropertiesRead), handlers.FavoriteHandler.CreateSavedSearch)
+			protected.DELETE("/me/saved-searches/:id", can(services.PermPropertiesRead), handlers.FavoriteHandler.DeleteSavedSearch)
+			protected.GET("/me/recommendations", lowPriority, can(services.PermPropertiesRead), handlers.RecommendationHandler.GetRecommendations)
+			protected.GET("/notifications", lowPriority, handlers.NotificationHandler.GetNotifications)
+			protected.POST("/notifications/read-all", handlers.NotificationHandler.MarkAllRead)
+			protected.POST("/notifications/:id/read", handlers.NotificationHandler.MarkRead)
+			protected.GET("/notifications/preferences", handlers.NotificationHandler.GetPreferences)
+			protected.PUT("/notifications/preferences", handlers.NotificationHandler.UpdatePreferences)
+			protected.GET("/activity", lowPriority, handlers.ActivityHandler.GetActivity)
+			if handlers.UploadHandler != nil {
+				protected.POST("/uploads/presign", can(services.PermPropertiesUpdate), handlers.UploadHandler.Presign)
+				protected.POST("/uploads/:id/confirm", can(services.PermPropertiesUpdate), handlers.UploadHandler.Confirm)
+			}
+			// Resumable uploads: create a session, PATCH chunks at Upload-Offset, then complete it
+			protected.POST("/uploads/sessions", can(services.PermPropertiesUpdate), handlers.UploadSessionHandler.CreateSession)
+			protected.GET("/uploads/sessions/:id", can(services.PermPropertiesUpdate), handlers.UploadSessionHandler.GetSession)
+			protected.PATCH("/uploads/sessions/:id", can(services.PermPropertiesUpdate), handlers.UploadSessionHandler.WriteChunk)
+			protected.POST("/uploads/sessions/:id/complete", can(services.PermPropertiesUpdate), handlers.UploadSessionHandler.CompleteSession)
+			protected.DELETE("/uploads/sessions/:id", can(services.PermPropertiesUpdate), handlers.UploadSessionHandler.CancelSession)
+		}
+
+		// Admin routes (protected, admin role only)
+		admin := api.Group("/admin")
+		admin.Use(middleware.AdminIPAllowlist(ipAccess), middleware.AuthMiddleware(authService), middleware.RequireAdmin())
+		{
+			admin.GET("/overview", handlers.AdminHandler.GetOverview)
+			admin.GET("/feature-flags", handlers.AdminHandler.GetFeatureFlags)
+			admin.PUT("/feature-flags/:name", handlers.AdminHandler.UpdateFeatureFlag)
+			admin.GET("/settings", handlers.AdminHandler.GetSettings)
+			admin.PUT("/settings", handlers.AdminHandler.UpdateSettings)
+			admin.POST("/reload", handlers.AdminHandler.Reload)
+			admin.GET("/roles", handlers.RoleHandler.GetRoles)
+			admin.PUT("/roles/:role", handlers.RoleHandler.UpdateRole)
+			admin.DELETE("/roles/:role", handlers.RoleHandler.DeleteRole)
+			admin.PUT("/users/:id/role", userID, handlers.RoleHandler.AssignRole)
+			admin.POST("/users/:id/import-quota/reset", userID, handlers.SimplyRETSHandler.ResetQuota)
+			admin.POST("/impersonate/:userId", handlers.PublicIDHandler.User("userId"), handlers.ImpersonationHandler.Impersonate)
+			admin.GET("/audit-log", handlers.ImpersonationHandler.GetAuditLog)
+			admin.GET("/service-accounts", handlers.ServiceAccountHandler.GetServiceAccounts)
+			admin.POST("/service-accounts", handlers.ServiceAccountHandler.CreateServiceAccount)
+			admin.POST("/service-accounts/:id/tokens", handlers.ServiceAccountHandler.IssueToken)
+			admin.POST("/tokens/revoke", handlers.AuthHandler.RevokeToken)
+			admin.GET("/email-suppressions", handlers.SuppressionHandler.GetSuppressions)
+			admin.DELETE("/email-suppressions/:email", handlers.SuppressionHandler.DeleteSuppression)
+			admin.GET("/data-quality/reports", handlers.DataQualityHandler.GetReports)
+			admin.POST("/data-quality/reports", handlers.DataQualityHandler.RunChecks)
+			admin.POST("/archives/run", handlers.ArchiveHandler.RunArchive)
+			admin.GET("/lead-routing-rules", handlers.LeadHandler.GetRoutingRules)
+			admin.POST("/lead-routing-rules", handlers.LeadHandler.CreateRoutingRule)
+			admin.DELETE("/lead-routing-rules/:id", handlers.LeadHandler.DeleteRoutingRule)
+			admin.GET("/crm", handlers.CRMHandler.GetCRM)
+			admin.GET("/crm/records", handlers.CRMHandler.GetRecords)
+			admin.POST("/crm/sync", handlers.CRMHandler.Sync)
+			admin.POST("/crm/retry", handlers.CRMHandler.Retry)
+			admin.POST("/reports/market/refresh", handlers.MarketHandler.Refresh)
+			admin.GET("/file-scans", handlers.FileScanHandler.GetScans)
+			admin.GET("/reviews", handlers.PublicationHandler.GetQueue)
+			admin.POST("/reviews/:id/approve", propertyID, handlers.PublicationHandler.Approve)
+			admin.POST("/reviews/:id/reject", propertyID, handlers.PublicationHandler.Reject)
 		}
 	}
 }
 
+// initializeLoadShedder sheds low-priority requests when more than
+// SHED_INFLIGHT_LIMIT requests are in flight (default 200, 0 for no limit)
+// or every database connection is in use, as requests then queue for one
+func initializeLoadShedder(db *sql.DB) *middleware.LoadShedder {
+	limit, err := strconv.Atoi(getEnv("SHED_INFLIGHT_LIMIT", "200"))
+	if err != nil || limit < 0 {
+		log.Fatal("Invalid SHED_INFLIGHT_LIMIT:", getEnv("SHED_INFLIGHT_LIMIT", ""))
+	}
+	return middleware.NewLoadShedder(limit, func() bool {
+		stats := db.Stats()
+		return stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections
+	})
+}
+
+// requestTimeouts limits how long API requests under base may run:
+// REQUEST_TIMEOUT (default 5s) for most, SLOW_REQUEST_TIMEOUT (default 30s)
+// for exports, uploads and other bulk work. "0" disables a limit.
+func requestTimeouts(base string) gin.HandlerFunc {
+	timeout := getEnvDuration("REQUEST_TIMEOUT", 5*time.Second)
+	slow := getEnvDuration("SLOW_REQUEST_TIMEOUT", 30*time.Second)
+	routes := map[string]time.Duration{}
+	for _, route := range []string{
+		"/properties/export",
+		"/properties/bulk-update",
+		"/properties/:id/photos",
+		"/properties/:id/signatures",
+		"/uploads/sessions/:id",
+		"/uploads/sessions/:id/complete",
+		"/simplyrets/jobs/:jobId/artifacts/:name",
+		"/sync",
+		"/admin/crm/sync",
+		"/admin/reports/market/refresh",
+	} {
+		routes[base+route] = slow
+	}
+	return middleware.Timeout(timeout, routes)
+}
+
+// imagesDir holds photos, imported or uploaded, served under /images
+func imagesDir() string {
+	return getEnv("SIMPLYRETS_IMAGES_DIR", services.DefaultImagesDir)
+}
+
+// imageStorage keeps photos in imagesDir or, with IMAGE_STORAGE=s3, in the
+// S3_BUCKET bucket under S3_IMAGES_PREFIX, so every instance serves the
+// photos any of them downloaded
+func imageStorage() (imagestore.Storage, error) {
+	return imagestore.New(getEnv("IMAGE_STORAGE", "local"), imagesDir(), objectstore.NewS3StoreFromEnv(), getEnv("S3_IMAGES_PREFIX", "images/"))
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return duration
+}
+
 func startServer(router *gin.Engine) {
 	port := getEnv("PORT", "8080")
 	log.Printf("Server starting on port %s", port)
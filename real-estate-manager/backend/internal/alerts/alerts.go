@@ -0,0 +1,158 @@
+// Package alerts posts operational alerts, such as failed import jobs, to
+// a Slack or Microsoft Teams incoming webhook configured with
+// ALERT_WEBHOOK_URL.
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Alert types; each can be switched off at runtime
+const (
+	JobFailed       = "job_failed"
+	AuthLockout     = "auth_lockout"
+	CircuitOpen     = "circuit_open"
+	ReadinessFailed = "readiness_failed"
+	DataQuality     = "data_quality"
+)
+
+// Types lists every alert type
+var Types = []string{JobFailed, AuthLockout, CircuitOpen, ReadinessFailed, DataQuality}
+
+// Webhook payload formats
+const (
+	FormatSlack = "slack"
+	FormatTeams = "teams"
+)
+
+// Alert is a single operational event
+type Alert struct {
+	Type   string
+	Title  string
+	Fields map[string]string
+	At     time.Time
+}
+
+// Notifier delivers alerts
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NewFromEnv builds a webhook notifier from ALERT_WEBHOOK_URL and
+// ALERT_WEBHOOK_FORMAT ("slack", the default, or "teams"). It returns nil
+// when no webhook is configured.
+func NewFromEnv() (Notifier, error) {
+	url := os.Getenv("ALERT_WEBHOOK_URL")
+	if url == "" {
+		return nil, nil
+	}
+	format := strings.ToLower(os.Getenv("ALERT_WEBHOOK_FORMAT"))
+	if format == "" {
+		format = FormatSlack
+	}
+	if format != FormatSlack && format != FormatTeams {
+		return nil, fmt.Errorf("unknown ALERT_WEBHOOK_FORMAT %q", format)
+	}
+	return NewWebhookNotifier(url, format, os.Getenv("ALERT_ENVIRONMENT")), nil
+}
+
+// WebhookNotifier posts alerts to an incoming webhook
+type WebhookNotifier struct {
+	url         string
+	format      string
+	environment string
+	client      *http.Client
+}
+
+// NewWebhookNotifier creates the notifier; environment (e.g. "production")
+// is prefixed to titles when set
+func NewWebhookNotifier(url, format, environment string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, format: format, environment: environment, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	title := alert.Title
+	if n.environment != "" {
+		title = fmt.Sprintf("[%s] %s", n.environment, title)
+	}
+
+	var payload any
+	if n.format == FormatTeams {
+		payload = teamsPayload(title, alert)
+	} else {
+		payload = slackPayload(title, alert)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sortedFields returns the alert fields ordered by name
+func sortedFields(alert Alert) [][2]string {
+	names := make([]string, 0, len(alert.Fields))
+	for name := range alert.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([][2]string, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, [2]string{name, alert.Fields[name]})
+	}
+	return fields
+}
+
+// slackPayload is a plain mrkdwn message, which Slack-compatible webhooks
+// (Mattermost, Rocket.Chat) also accept
+func slackPayload(title string, alert Alert) map[string]string {
+	lines := []string{fmt.Sprintf(":rotating_light: *%s*", title)}
+	for _, field := range sortedFields(alert) {
+		lines = append(lines, fmt.Sprintf("*%s:* %s", field[0], field[1]))
+	}
+	lines = append(lines, fmt.Sprintf("_%s at %s_", alert.Type, alert.At.UTC().Format(time.RFC3339)))
+	return map[string]string{"text": strings.Join(lines, "\n")}
+}
+
+// teamsPayload is a legacy MessageCard, accepted by Teams incoming webhooks
+func teamsPayload(title string, alert Alert) map[string]any {
+	facts := []map[string]string{}
+	for _, field := range sortedFields(alert) {
+		facts = append(facts, map[string]string{"name": field[0], "value": field[1]})
+	}
+	facts = append(facts, map[string]string{"name": "type", "value": alert.Type},
+		map[string]string{"name": "at", "value": alert.At.UTC().Format(time.RFC3339)})
+
+	return map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    title,
+		"title":      title,
+		"themeColor": "D93F0B",
+		"sections":   []map[string]any{{"facts": facts}},
+	}
+}
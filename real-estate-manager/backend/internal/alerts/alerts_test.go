@@ -0,0 +1,67 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	alert := Alert{
+		Type:   JobFailed,
+		Title:  "SimplyRETS import job failed",
+		Fields: map[string]string{"job": "job_1", "error": "timeout"},
+		At:     time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, payload map[string]any)
+	}{
+		{
+			name:   "slack",
+			format: FormatSlack,
+			check: func(t *testing.T, payload map[string]any) {
+				text, _ := payload["text"].(string)
+				if !strings.Contains(text, "*[production] SimplyRETS import job failed*") ||
+					!strings.Contains(text, "*error:* timeout\n*job:* job_1") {
+					t.Errorf("unexpected text %q", text)
+				}
+			},
+		},
+		{
+			name:   "teams",
+			format: FormatTeams,
+			check: func(t *testing.T, payload map[string]any) {
+				if payload["@type"] != "MessageCard" || payload["title"] != "[production] SimplyRETS import job failed" {
+					t.Errorf("unexpected card %v", payload)
+				}
+				sections, _ := payload["sections"].([]any)
+				if len(sections) != 1 || len(sections[0].(map[string]any)["facts"].([]any)) != 4 {
+					t.Errorf("expected 4 facts, got %v", payload["sections"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&payload)
+			}))
+			defer server.Close()
+
+			notifier := NewWebhookNotifier(server.URL, tt.format, "production")
+			if err := notifier.Notify(context.Background(), alert); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, payload)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Package apperrors defines the error kinds shared by services and handlers.
+// Services wrap failures in one of the sentinel kinds below and handlers map
+// them to HTTP statuses in one place.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrValidation   = errors.New("validation failed")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrTooLarge     = errors.New("payload too large")
+	ErrRateLimited  = errors.New("too many requests")
+	ErrUnavailable  = errors.New("service unavailable")
+)
+
+// Error carries a user-facing message alongside its sentinel kind, so
+// errors.Is(err, ErrNotFound) works while Error() stays descriptive
+type Error struct {
+	Kind    error
+	Message string
+	// Format and Args render Message in other languages; Format is the
+	// English message, or its format string, and keys the translations
+	Format string
+	Args   []any
+}
+
+func newError(kind error, message string) error {
+	return &Error{Kind: kind, Message: message, Format: message}
+}
+
+func newErrorf(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Format: format, Args: args}
+}
+
+// Format returns the format and args err's message was built from, so it
+// can be translated. Other errors, and app errors wrapped with more context,
+// give their message as is.
+func Format(err error) (string, []any) {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Format != "" && appErr.Message == err.Error() {
+		return appErr.Format, appErr.Args
+	}
+	return err.Error(), nil
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(message string) error {
+	return newError(ErrNotFound, message)
+}
+
+func NotFoundf(format string, args ...any) error {
+	return newErrorf(ErrNotFound, format, args...)
+}
+
+func Validation(message string) error {
+	return newError(ErrValidation, message)
+}
+
+func Validationf(format string, args ...any) error {
+	return newErrorf(ErrValidation, format, args...)
+}
+
+func Conflict(message string) error {
+	return newError(ErrConflict, message)
+}
+
+func Conflictf(format string, args ...any) error {
+	return newErrorf(ErrConflict, format, args...)
+}
+
+func Unauthorized(message string) error {
+	return newError(ErrUnauthorized, message)
+}
+
+func Forbidden(message string) error {
+	return newError(ErrForbidden, message)
+}
+
+func Forbiddenf(format string, args ...any) error {
+	return newErrorf(ErrForbidden, format, args...)
+}
+
+// TooLarge reports an upload rejected for its size, e.g. an exceeded quota
+func TooLarge(message string) error {
+	return newError(ErrTooLarge, message)
+}
+
+func TooLargef(format string, args ...any) error {
+	return newErrorf(ErrTooLarge, format, args...)
+}
+
+// RateLimited reports a caller that exceeded a rate limit
+func RateLimited(message string) error {
+	return newError(ErrRateLimited, message)
+}
+
+// Unavailable reports work turned away because the server is at capacity;
+// the caller may retry later
+func Unavailable(message string) error {
+	return newError(ErrUnavailable, message)
+}
+
+// HTTPStatus returns the status code for err; unknown errors are 500
+func HTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
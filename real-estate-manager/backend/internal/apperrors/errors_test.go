@@ -0,0 +1,62 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "not found", err: NotFound("property not found"), expectedStatus: http.StatusNotFound},
+		{name: "validation", err: Validation("invalid property data"), expectedStatus: http.StatusBadRequest},
+		{name: "conflict", err: Conflict("user already exists"), expectedStatus: http.StatusConflict},
+		{name: "unauthorized", err: Unauthorized("invalid credentials"), expectedStatus: http.StatusUnauthorized},
+		{name: "forbidden", err: Forbidden("admin access required"), expectedStatus: http.StatusForbidden},
+		{name: "too large", err: TooLarge("storage quota exceeded"), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "rate limited", err: RateLimited("try again later"), expectedStatus: http.StatusTooManyRequests},
+		{name: "unavailable", err: Unavailable("image queue is full"), expectedStatus: http.StatusServiceUnavailable},
+		{name: "wrapped sentinel", err: fmt.Errorf("lookup failed: %w", ErrNotFound), expectedStatus: http.StatusNotFound},
+		{name: "unknown error", err: errors.New("database connection failed"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := HTTPStatus(tt.err); status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestError_Message(t *testing.T) {
+	err := NotFound("property not found")
+	if err.Error() != "property not found" {
+		t.Errorf("Expected message 'property not found', got '%s'", err.Error())
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected error to match ErrNotFound")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	err := Validationf("limit must be between 1 and %d", 50)
+	if err.Error() != "limit must be between 1 and 50" {
+		t.Errorf("Expected the formatted message, got '%s'", err.Error())
+	}
+	format, args := Format(err)
+	if format != "limit must be between 1 and %d" || len(args) != 1 || args[0] != 50 {
+		t.Errorf("Expected the format and its args, got '%s' %v", format, args)
+	}
+
+	// Context added by wrapping is not part of the format
+	wrapped := fmt.Errorf("importing: %w", err)
+	if format, args := Format(wrapped); format != wrapped.Error() || args != nil {
+		t.Errorf("Expected the wrapped message as is, got '%s' %v", format, args)
+	}
+}
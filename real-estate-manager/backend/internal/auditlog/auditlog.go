@@ -0,0 +1,131 @@
+// Package auditlog forwards audit entries to external sinks, such as a
+// SIEM, in addition to the audit_log table. Sinks are chosen with
+// AUDIT_SINKS: "file" appends JSON lines, "syslog" writes to a syslog
+// daemon and "http" posts each entry to an endpoint.
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// Sink receives every audit entry once it is stored
+type Sink interface {
+	Write(ctx context.Context, entry models.AuditEntry) error
+}
+
+// NewFromEnv builds the sinks listed in the comma-separated AUDIT_SINKS:
+// "file" (AUDIT_FILE_PATH), "syslog" (AUDIT_SYSLOG_ADDR, the local daemon
+// when empty, and AUDIT_SYSLOG_TAG) and "http" (AUDIT_HTTP_URL and
+// optionally AUDIT_HTTP_TOKEN, sent as a bearer token). It returns no sinks
+// when AUDIT_SINKS is empty.
+func NewFromEnv() ([]Sink, error) {
+	var sinks []Sink
+	for _, name := range strings.Split(os.Getenv("AUDIT_SINKS"), ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "":
+		case "file":
+			path := os.Getenv("AUDIT_FILE_PATH")
+			if path == "" {
+				return nil, fmt.Errorf("AUDIT_FILE_PATH is required for the file audit sink")
+			}
+			sink, err := NewFileSink(path)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "syslog":
+			tag := os.Getenv("AUDIT_SYSLOG_TAG")
+			if tag == "" {
+				tag = "real-estate-manager"
+			}
+			sink, err := NewSyslogSink(os.Getenv("AUDIT_SYSLOG_ADDR"), tag)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "http":
+			url := os.Getenv("AUDIT_HTTP_URL")
+			if url == "" {
+				return nil, fmt.Errorf("AUDIT_HTTP_URL is required for the http audit sink")
+			}
+			sinks = append(sinks, NewHTTPSink(url, os.Getenv("AUDIT_HTTP_TOKEN")))
+		default:
+			return nil, fmt.Errorf("unknown audit sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+// FileSink appends entries to a file as JSON lines
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens path for appending, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Write(ctx context.Context, entry models.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// HTTPSink posts each entry as JSON to an endpoint, such as a SIEM's HTTP
+// event collector
+type HTTPSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSink creates the sink; token is sent as a bearer token when set
+func NewHTTPSink(url, token string) *HTTPSink {
+	return &HTTPSink{url: url, token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *HTTPSink) Write(ctx context.Context, entry models.AuditEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create audit request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post audit entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package auditlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+func TestFileSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, action := range []string{models.AuditLoginFailed, models.AuditLoginSucceeded} {
+		if err := sink.Write(context.Background(), models.AuditEntry{Action: action}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var actions []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		actions = append(actions, entry.Action)
+	}
+	if len(actions) != 2 || actions[0] != models.AuditLoginFailed || actions[1] != models.AuditLoginSucceeded {
+		t.Errorf("expected one line per entry in order, got %v", actions)
+	}
+}
+
+func TestHTTPSink_Write(t *testing.T) {
+	var authorization string
+	var entry models.AuditEntry
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&entry)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, "siem-token")
+	if err := sink.Write(context.Background(), models.AuditEntry{ID: 7, Action: models.AuditLoginFailed}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if authorization != "Bearer siem-token" || entry.ID != 7 || entry.Action != models.AuditLoginFailed {
+		t.Errorf("unexpected request: authorization %q, entry %+v", authorization, entry)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Write(context.Background(), models.AuditEntry{ID: 8}); err == nil {
+		t.Error("expected an error for a failed post")
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_SINKS", "")
+	if sinks, err := NewFromEnv(); err != nil || len(sinks) != 0 {
+		t.Errorf("expected no sinks by default, got %v and %v", sinks, err)
+	}
+
+	t.Setenv("AUDIT_SINKS", "file, http")
+	t.Setenv("AUDIT_FILE_PATH", filepath.Join(t.TempDir(), "audit.jsonl"))
+	t.Setenv("AUDIT_HTTP_URL", "https://siem.example.com/events")
+	if sinks, err := NewFromEnv(); err != nil || len(sinks) != 2 {
+		t.Errorf("expected file and http sinks, got %v and %v", sinks, err)
+	}
+
+	t.Setenv("AUDIT_SINKS", "kafka")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected an unknown sink to be rejected")
+	}
+}
@@ -0,0 +1,45 @@
+//go:build !windows && !plan9
+
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"strings"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// SyslogSink writes entries as JSON messages to a syslog daemon with the
+// auth facility
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink connects to the daemon at addr ("udp://host:514" or
+// "tcp://host:514"), or the local one when addr is empty
+func NewSyslogSink(addr, tag string) (*SyslogSink, error) {
+	var network, host string
+	if addr != "" {
+		var ok bool
+		network, host, ok = strings.Cut(addr, "://")
+		if !ok || (network != "udp" && network != "tcp") {
+			return nil, fmt.Errorf("invalid AUDIT_SYSLOG_ADDR %q: use udp://host:port or tcp://host:port", addr)
+		}
+	}
+	writer, err := syslog.Dial(network, host, syslog.LOG_NOTICE|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+func (s *SyslogSink) Write(ctx context.Context, entry models.AuditEntry) error {
+	message, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return s.writer.Notice(string(message))
+}
@@ -0,0 +1,21 @@
+//go:build windows || plan9
+
+package auditlog
+
+import (
+	"context"
+	"errors"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// SyslogSink is unavailable on platforms without log/syslog
+type SyslogSink struct{}
+
+func NewSyslogSink(addr, tag string) (*SyslogSink, error) {
+	return nil, errors.New("the syslog audit sink is not supported on this platform")
+}
+
+func (s *SyslogSink) Write(ctx context.Context, entry models.AuditEntry) error {
+	return errors.New("the syslog audit sink is not supported on this platform")
+}
@@ -0,0 +1,208 @@
+// Package calendar connects agents' Google and Outlook calendars. Agents
+// authorize access with OAuth 2.0; the backend then reads their busy times
+// to avoid double-booking showings and adds confirmed showings as events.
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Calendar providers
+const (
+	Google  = "google"
+	Outlook = "outlook"
+)
+
+// ErrUnauthorized is returned when the provider rejects an access token;
+// the connection must be authorized again
+var ErrUnauthorized = errors.New("calendar access was revoked or expired")
+
+// Token is an OAuth 2.0 token pair. RefreshToken may be empty when the
+// provider does not rotate it on refresh.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// Interval is a busy period in a calendar
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Event is an appointment added to a calendar
+type Event struct {
+	Title       string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+}
+
+// Provider is a calendar service agents can connect
+type Provider interface {
+	Name() string
+	// AuthCodeURL is where the agent is sent to grant access; the
+	// provider redirects back to redirectURL with a code and state
+	AuthCodeURL(state, redirectURL string) string
+	Exchange(ctx context.Context, code, redirectURL string) (*Token, error)
+	Refresh(ctx context.Context, refreshToken string) (*Token, error)
+	// Busy returns the busy periods of the primary calendar between
+	// start and end
+	Busy(ctx context.Context, accessToken string, start, end time.Time) ([]Interval, error)
+	// CreateEvent adds an event to the primary calendar and returns its ID
+	CreateEvent(ctx context.Context, accessToken string, event Event) (string, error)
+}
+
+// NewFromEnv returns the providers whose OAuth client is configured:
+// GOOGLE_CALENDAR_CLIENT_ID/_SECRET and OUTLOOK_CALENDAR_CLIENT_ID/_SECRET
+func NewFromEnv() []Provider {
+	client := &http.Client{Timeout: 30 * time.Second}
+	var providers []Provider
+	if id := os.Getenv("GOOGLE_CALENDAR_CLIENT_ID"); id != "" {
+		providers = append(providers, NewGoogleProvider(client, id, os.Getenv("GOOGLE_CALENDAR_CLIENT_SECRET")))
+	}
+	if id := os.Getenv("OUTLOOK_CALENDAR_CLIENT_ID"); id != "" {
+		providers = append(providers, NewOutlookProvider(client, id, os.Getenv("OUTLOOK_CALENDAR_CLIENT_SECRET")))
+	}
+	return providers
+}
+
+// oauthClient runs the OAuth 2.0 authorization code flow against one
+// provider
+type oauthClient struct {
+	client       *http.Client
+	authURL      string
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scope        string
+	// extra is added to the authorization URL
+	extra url.Values
+	now   func() time.Time
+}
+
+func (o *oauthClient) authCodeURL(state, redirectURL string) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {o.clientID},
+		"redirect_uri":  {redirectURL},
+		"scope":         {o.scope},
+		"state":         {state},
+	}
+	for name, values := range o.extra {
+		query[name] = values
+	}
+	return o.authURL + "?" + query.Encode()
+}
+
+func (o *oauthClient) exchange(ctx context.Context, code, redirectURL string) (*Token, error) {
+	return o.token(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURL},
+	})
+}
+
+func (o *oauthClient) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return o.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (o *oauthClient) token(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", o.clientID)
+	form.Set("client_secret", o.clientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		// invalid_grant: the code was used or the refresh token revoked
+		return nil, ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint returned no access token")
+	}
+	token := &Token{AccessToken: result.AccessToken, RefreshToken: result.RefreshToken}
+	if result.ExpiresIn > 0 {
+		token.Expiry = o.now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// doJSON sends a JSON API request with the access token and decodes the
+// response into result
+func doJSON(ctx context.Context, client *http.Client, method, target, accessToken string, headers map[string]string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calendar request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("calendar API returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode calendar response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestOAuthClient_AuthCodeURL(t *testing.T) {
+	provider := NewGoogleProvider(http.DefaultClient, "client-1", "secret").(*googleProvider)
+	target, err := url.Parse(provider.AuthCodeURL("state-1", "https://api.example.com/api/calendar/google/callback"))
+	if err != nil {
+		t.Fatalf("Invalid URL: %v", err)
+	}
+	query := target.Query()
+	if query.Get("client_id") != "client-1" || query.Get("state") != "state-1" || query.Get("response_type") != "code" ||
+		query.Get("redirect_uri") != "https://api.example.com/api/calendar/google/callback" || query.Get("access_type") != "offline" {
+		t.Errorf("Unexpected authorization URL %s", target)
+	}
+}
+
+func TestOAuthClient_Exchange(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		if form.Get("code") == "used" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	oauth := &oauthClient{client: server.Client(), tokenURL: server.URL, clientID: "client-1", clientSecret: "secret",
+		now: func() time.Time { return testNow }}
+	token, err := oauth.exchange(context.Background(), "code-1", "https://api.example.com/callback")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if form.Get("grant_type") != "authorization_code" || form.Get("code") != "code-1" || form.Get("client_secret") != "secret" {
+		t.Errorf("Unexpected token request %v", form)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || !token.Expiry.Equal(testNow.Add(time.Hour)) {
+		t.Errorf("Unexpected token %+v", token)
+	}
+
+	if _, err := oauth.exchange(context.Background(), "used", "https://api.example.com/callback"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for a rejected code, got %v", err)
+	}
+}
+
+func TestGoogleProvider_Busy(t *testing.T) {
+	var auth string
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/freeBusy" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"calendars":{"primary":{"busy":[{"start":"2024-06-02T15:00:00Z","end":"2024-06-02T16:00:00Z"}]}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewGoogleProvider(server.Client(), "client-1", "secret").(*googleProvider)
+	provider.apiURL = server.URL
+	busy, err := provider.Busy(context.Background(), "access-1", testNow, testNow.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if auth != "Bearer access-1" || request["timeMin"] != "2024-06-01T12:00:00Z" {
+		t.Errorf("Unexpected request %v (auth %q)", request, auth)
+	}
+	want := Interval{Start: time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC), End: time.Date(2024, 6, 2, 16, 0, 0, 0, time.UTC)}
+	if len(busy) != 1 || !busy[0].Start.Equal(want.Start) || !busy[0].End.Equal(want.End) {
+		t.Errorf("Expected %v, got %v", want, busy)
+	}
+}
+
+func TestOutlookProvider_Busy(t *testing.T) {
+	var prefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefer = r.Header.Get("Prefer")
+		w.Write([]byte(`{"value":[
+			{"start":{"dateTime":"2024-06-02T15:00:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2024-06-02T16:00:00.0000000","timeZone":"UTC"},"showAs":"busy"},
+			{"start":{"dateTime":"2024-06-02T18:00:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2024-06-02T19:00:00.0000000","timeZone":"UTC"},"showAs":"free"}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOutlookProvider(server.Client(), "client-1", "secret").(*outlookProvider)
+	provider.apiURL = server.URL
+	busy, err := provider.Busy(context.Background(), "access-1", testNow, testNow.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if prefer != `outlook.timezone="UTC"` {
+		t.Errorf("Expected UTC times to be requested, got Prefer %q", prefer)
+	}
+	if len(busy) != 1 || !busy[0].Start.Equal(time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected only the busy event, got %v", busy)
+	}
+}
+
+func TestDoJSON_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	provider := NewOutlookProvider(server.Client(), "client-1", "secret").(*outlookProvider)
+	provider.apiURL = server.URL
+	_, err := provider.CreateEvent(context.Background(), "expired", Event{Title: "Showing", Start: testNow, End: testNow.Add(time.Hour)})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
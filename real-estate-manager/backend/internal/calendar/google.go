@@ -0,0 +1,98 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type googleProvider struct {
+	oauth  *oauthClient
+	apiURL string
+}
+
+// NewGoogleProvider connects Google Calendar through the Calendar API v3
+func NewGoogleProvider(client *http.Client, clientID, clientSecret string) Provider {
+	return &googleProvider{
+		oauth: &oauthClient{
+			client:       client,
+			authURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+			tokenURL:     "https://oauth2.googleapis.com/token",
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			scope:        "https://www.googleapis.com/auth/calendar.events https://www.googleapis.com/auth/calendar.freebusy",
+			// Without offline access and consent Google issues no
+			// refresh token
+			extra: url.Values{"access_type": {"offline"}, "prompt": {"consent"}},
+			now:   time.Now,
+		},
+		apiURL: "https://www.googleapis.com/calendar/v3",
+	}
+}
+
+func (p *googleProvider) Name() string {
+	return Google
+}
+
+func (p *googleProvider) AuthCodeURL(state, redirectURL string) string {
+	return p.oauth.authCodeURL(state, redirectURL)
+}
+
+func (p *googleProvider) Exchange(ctx context.Context, code, redirectURL string) (*Token, error) {
+	return p.oauth.exchange(ctx, code, redirectURL)
+}
+
+func (p *googleProvider) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return p.oauth.refresh(ctx, refreshToken)
+}
+
+type googleTime struct {
+	DateTime time.Time `json:"dateTime"`
+}
+
+func (p *googleProvider) Busy(ctx context.Context, accessToken string, start, end time.Time) ([]Interval, error) {
+	request := map[string]any{
+		"timeMin": start.UTC().Format(time.RFC3339),
+		"timeMax": end.UTC().Format(time.RFC3339),
+		"items":   []map[string]string{{"id": "primary"}},
+	}
+	var response struct {
+		Calendars map[string]struct {
+			Busy []struct {
+				Start time.Time `json:"start"`
+				End   time.Time `json:"end"`
+			} `json:"busy"`
+		} `json:"calendars"`
+	}
+	if err := doJSON(ctx, p.oauth.client, http.MethodPost, p.apiURL+"/freeBusy", accessToken, nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	var busy []Interval
+	for _, period := range response.Calendars["primary"].Busy {
+		busy = append(busy, Interval{Start: period.Start, End: period.End})
+	}
+	return busy, nil
+}
+
+func (p *googleProvider) CreateEvent(ctx context.Context, accessToken string, event Event) (string, error) {
+	request := map[string]any{
+		"summary":     event.Title,
+		"description": event.Description,
+		"location":    event.Location,
+		"start":       googleTime{DateTime: event.Start.UTC()},
+		"end":         googleTime{DateTime: event.End.UTC()},
+	}
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := doJSON(ctx, p.oauth.client, http.MethodPost, p.apiURL+"/calendars/primary/events", accessToken, nil, request, &response); err != nil {
+		return "", err
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("google returned no event ID")
+	}
+	return response.ID, nil
+}
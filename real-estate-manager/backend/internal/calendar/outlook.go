@@ -0,0 +1,115 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// outlookTimeLayout is how Microsoft Graph writes date-times, without an
+// offset; the zone is given separately
+const outlookTimeLayout = "2006-01-02T15:04:05.9999999"
+
+type outlookProvider struct {
+	oauth  *oauthClient
+	apiURL string
+}
+
+// NewOutlookProvider connects Outlook and Microsoft 365 calendars through
+// Microsoft Graph
+func NewOutlookProvider(client *http.Client, clientID, clientSecret string) Provider {
+	return &outlookProvider{
+		oauth: &oauthClient{
+			client:       client,
+			authURL:      "https://login.microsoftonline.com/common/oauth2/v2.0/authorize",
+			tokenURL:     "https://login.microsoftonline.com/common/oauth2/v2.0/token",
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			scope:        "offline_access Calendars.ReadWrite",
+			now:          time.Now,
+		},
+		apiURL: "https://graph.microsoft.com/v1.0",
+	}
+}
+
+func (p *outlookProvider) Name() string {
+	return Outlook
+}
+
+func (p *outlookProvider) AuthCodeURL(state, redirectURL string) string {
+	return p.oauth.authCodeURL(state, redirectURL)
+}
+
+func (p *outlookProvider) Exchange(ctx context.Context, code, redirectURL string) (*Token, error) {
+	return p.oauth.exchange(ctx, code, redirectURL)
+}
+
+func (p *outlookProvider) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return p.oauth.refresh(ctx, refreshToken)
+}
+
+type outlookTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// utcHeaders asks Graph to return date-times in UTC
+var utcHeaders = map[string]string{"Prefer": `outlook.timezone="UTC"`}
+
+func (p *outlookProvider) Busy(ctx context.Context, accessToken string, start, end time.Time) ([]Interval, error) {
+	query := url.Values{
+		"startDateTime": {start.UTC().Format(time.RFC3339)},
+		"endDateTime":   {end.UTC().Format(time.RFC3339)},
+		"$select":       {"start,end,showAs"},
+		"$top":          {"100"},
+	}
+	var response struct {
+		Value []struct {
+			Start  outlookTime `json:"start"`
+			End    outlookTime `json:"end"`
+			ShowAs string      `json:"showAs"`
+		} `json:"value"`
+	}
+	if err := doJSON(ctx, p.oauth.client, http.MethodGet, p.apiURL+"/me/calendarView?"+query.Encode(), accessToken, utcHeaders, nil, &response); err != nil {
+		return nil, err
+	}
+
+	var busy []Interval
+	for _, event := range response.Value {
+		if event.ShowAs == "free" {
+			continue
+		}
+		eventStart, err := time.ParseInLocation(outlookTimeLayout, event.Start.DateTime, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event start %q: %w", event.Start.DateTime, err)
+		}
+		eventEnd, err := time.ParseInLocation(outlookTimeLayout, event.End.DateTime, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event end %q: %w", event.End.DateTime, err)
+		}
+		busy = append(busy, Interval{Start: eventStart, End: eventEnd})
+	}
+	return busy, nil
+}
+
+func (p *outlookProvider) CreateEvent(ctx context.Context, accessToken string, event Event) (string, error) {
+	request := map[string]any{
+		"subject":  event.Title,
+		"body":     map[string]string{"contentType": "text", "content": event.Description},
+		"location": map[string]string{"displayName": event.Location},
+		"start":    outlookTime{DateTime: event.Start.UTC().Format(outlookTimeLayout), TimeZone: "UTC"},
+		"end":      outlookTime{DateTime: event.End.UTC().Format(outlookTimeLayout), TimeZone: "UTC"},
+	}
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := doJSON(ctx, p.oauth.client, http.MethodPost, p.apiURL+"/me/events", accessToken, utcHeaders, request, &response); err != nil {
+		return "", err
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("outlook returned no event ID")
+	}
+	return response.ID, nil
+}
@@ -0,0 +1,114 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSiteVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		status        int
+		hostname      string
+		expectValid   bool
+		expectedError bool
+	}{
+		{name: "success", response: `{"success": true, "hostname": "app.example.com"}`, expectValid: true},
+		{name: "failure", response: `{"success": false, "error-codes": ["invalid-input-response"]}`},
+		{name: "expected hostname", response: `{"success": true, "hostname": "App.Example.com"}`, hostname: "app.example.com", expectValid: true},
+		{name: "wrong hostname", response: `{"success": true, "hostname": "evil.example.net"}`, hostname: "app.example.com"},
+		{name: "error status", status: http.StatusServiceUnavailable, expectedError: true},
+		{name: "malformed response", response: `<html>`, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				if r.PostForm.Get("secret") != "s3cret" || r.PostForm.Get("response") != "token" || r.PostForm.Get("remoteip") != "203.0.113.7" {
+					t.Errorf("Unexpected form %v", r.PostForm)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			verifier := NewSiteVerifier(server.Client(), server.URL, "s3cret", tt.hostname)
+			valid, err := verifier.Verify(context.Background(), "token", "203.0.113.7")
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if valid != tt.expectValid {
+				t.Errorf("Expected valid %v, got %v", tt.expectValid, valid)
+			}
+		})
+	}
+}
+
+func TestSiteVerifier_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	verifier := NewSiteVerifier(server.Client(), server.URL, "s3cret", "")
+	if valid, err := verifier.Verify(context.Background(), "token", ""); err == nil || valid {
+		t.Errorf("Expected a transport error, got %v, %v", valid, err)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      string
+		secret        string
+		expectURL     string
+		expectedError bool
+	}{
+		{name: "unset"},
+		{name: "none", provider: "none"},
+		{name: "unknown", provider: "recaptcha", secret: "s3cret", expectedError: true},
+		{name: "missing secret", provider: "hcaptcha", expectedError: true},
+		{name: "hcaptcha", provider: "hcaptcha", secret: "s3cret", expectURL: hCaptchaVerifyURL},
+		{name: "turnstile", provider: "Turnstile", secret: "s3cret", expectURL: turnstileVerifyURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CAPTCHA_PROVIDER", tt.provider)
+			t.Setenv("CAPTCHA_SECRET", tt.secret)
+			t.Setenv("CAPTCHA_HOSTNAME", "app.example.com")
+
+			verifier, err := NewFromEnv()
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectURL == "" {
+				if verifier != nil {
+					t.Errorf("Expected CAPTCHA to be disabled, got %+v", verifier)
+				}
+				return
+			}
+			site := verifier.(*SiteVerifier)
+			if site.verifyURL != tt.expectURL || site.secret != "s3cret" || site.hostname != "app.example.com" {
+				t.Errorf("Unexpected verifier %+v", site)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Package crm exports leads and their contacts to an external CRM such as
+// HubSpot or Salesforce. Each connector writes records through the CRM's
+// REST API; which of our fields go to which CRM field is configurable.
+package crm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+// CRM connectors
+const (
+	HubSpot    = "hubspot"
+	Salesforce = "salesforce"
+)
+
+// Record kinds. A contact is the person behind one or more leads.
+const (
+	Contact = "contact"
+	Lead    = "lead"
+)
+
+// Fields each record kind provides for mapping
+var (
+	ContactFields = []string{"name", "first_name", "last_name", "email", "phone"}
+	LeadFields    = []string{"name", "first_name", "last_name", "email", "phone", "message", "source",
+		"property_id", "property_name", "property_address", "agent_email", "created_at"}
+)
+
+// ErrNotFound is returned when a record that was synced before no longer
+// exists in the CRM
+var ErrNotFound = errors.New("record not found in CRM")
+
+// Record is a contact or lead to write to the CRM
+type Record struct {
+	Kind string
+	// ID is the record's ID in the CRM from an earlier sync; empty creates
+	// the record
+	ID string
+	// ContactID links a lead to its contact in the CRM, when the CRM
+	// associates the two
+	ContactID string
+	// Properties are CRM field names and values, after mapping
+	Properties map[string]any
+}
+
+// Connector writes records to one CRM
+type Connector interface {
+	Name() string
+	// DefaultMapping is used for any kind the configured mapping leaves out
+	DefaultMapping() Mapping
+	// Upsert creates or updates a record and returns its ID in the CRM
+	Upsert(ctx context.Context, record Record) (string, error)
+}
+
+// Mapping maps record kinds to our field names to CRM field names
+type Mapping map[string]map[string]string
+
+// ParseMapping reads a JSON mapping such as
+// {"contact": {"email": "email"}, "lead": {"message": "description"}}.
+// An empty value is an empty mapping.
+func ParseMapping(value string) (Mapping, error) {
+	mapping := Mapping{}
+	if strings.TrimSpace(value) == "" {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+		return nil, fmt.Errorf("invalid field mapping: %w", err)
+	}
+	for kind, fields := range mapping {
+		var known []string
+		switch kind {
+		case Contact:
+			known = ContactFields
+		case Lead:
+			known = LeadFields
+		default:
+			return nil, fmt.Errorf("unknown record kind %q; expected %s or %s", kind, Contact, Lead)
+		}
+		for field, target := range fields {
+			if !slices.Contains(known, field) {
+				return nil, fmt.Errorf("unknown %s field %q; expected any of %s", kind, field, strings.Join(known, ", "))
+			}
+			if target == "" {
+				return nil, fmt.Errorf("%s field %q is mapped to an empty CRM field", kind, field)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// Merge returns the mapping with each kind it leaves out taken from
+// defaults
+func (m Mapping) Merge(defaults Mapping) Mapping {
+	merged := Mapping{}
+	for kind, fields := range defaults {
+		merged[kind] = fields
+	}
+	for kind, fields := range m {
+		merged[kind] = fields
+	}
+	return merged
+}
+
+// Apply maps a record's fields to CRM properties, leaving out empty values
+func (m Mapping) Apply(kind string, fields map[string]any) map[string]any {
+	properties := map[string]any{}
+	for field, target := range m[kind] {
+		value, ok := fields[field]
+		if !ok || value == nil || value == "" {
+			continue
+		}
+		properties[target] = value
+	}
+	return properties
+}
+
+// NewFromEnv returns the connector selected by CRM_PROVIDER, or nil when
+// none is: "hubspot" needs HUBSPOT_ACCESS_TOKEN (a private app token);
+// "salesforce" needs SALESFORCE_INSTANCE_URL, SALESFORCE_CLIENT_ID and
+// SALESFORCE_CLIENT_SECRET of a connected app with the client credentials
+// flow enabled
+func NewFromEnv() (Connector, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch provider := strings.ToLower(os.Getenv("CRM_PROVIDER")); provider {
+	case "", "none":
+		return nil, nil
+	case HubSpot:
+		token := os.Getenv("HUBSPOT_ACCESS_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("HUBSPOT_ACCESS_TOKEN is required for the hubspot CRM")
+		}
+		return NewHubSpotConnector(client, "", mlsauth.Bearer{Token: token}), nil
+	case Salesforce:
+		instance := strings.TrimSuffix(os.Getenv("SALESFORCE_INSTANCE_URL"), "/")
+		clientID, clientSecret := os.Getenv("SALESFORCE_CLIENT_ID"), os.Getenv("SALESFORCE_CLIENT_SECRET")
+		if instance == "" || clientID == "" || clientSecret == "" {
+			return nil, fmt.Errorf("SALESFORCE_INSTANCE_URL, SALESFORCE_CLIENT_ID and SALESFORCE_CLIENT_SECRET are required for the salesforce CRM")
+		}
+		auth := mlsauth.NewClientCredentials(client, instance+"/services/oauth2/token", clientID, clientSecret, "")
+		return NewSalesforceConnector(client, instance, auth), nil
+	default:
+		return nil, fmt.Errorf("unknown CRM_PROVIDER %q", provider)
+	}
+}
+
+// apiClient sends JSON requests to a CRM's REST API
+type apiClient struct {
+	name   string
+	client *http.Client
+	auth   mlsauth.Authenticator
+}
+
+// do sends body to target and decodes the response into result, if any.
+// Rejected credentials are discarded and the request is tried once more.
+func (c *apiClient) do(ctx context.Context, method, target string, body, result any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(encoded))
+		if err != nil {
+			return fmt.Errorf("failed to create %s request: %w", c.name, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if err := c.auth.Authorize(ctx, req); err != nil {
+			return fmt.Errorf("failed to authorize %s request: %w", c.name, err)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("%s request failed: %w", c.name, err)
+		}
+		invalidator, canRetry := c.auth.(mlsauth.Invalidator)
+		if resp.StatusCode == http.StatusUnauthorized && canRetry && attempt == 0 {
+			resp.Body.Close()
+			invalidator.Invalidate()
+			continue
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return ErrNotFound
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("%s returned status %d: %s", c.name, resp.StatusCode, bytes.TrimSpace(detail))
+		}
+		if result == nil || resp.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", c.name, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package crm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expectError bool
+	}{
+		{name: "empty", value: ""},
+		{name: "valid", value: `{"contact": {"email": "email"}, "lead": {"message": "description"}}`},
+		{name: "not JSON", value: `email=email`, expectError: true},
+		{name: "unknown kind", value: `{"deal": {"email": "email"}}`, expectError: true},
+		{name: "unknown field", value: `{"contact": {"message": "notes"}}`, expectError: true},
+		{name: "empty target", value: `{"lead": {"message": ""}}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMapping(tt.value)
+			if (err != nil) != tt.expectError {
+				t.Errorf("Expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestMapping_MergeAndApply(t *testing.T) {
+	defaults := Mapping{Contact: {"email": "Email"}, Lead: {"message": "Description", "source": "LeadSource"}}
+	mapping := Mapping{Lead: {"message": "Notes__c"}}.Merge(defaults)
+
+	properties := mapping.Apply(Lead, map[string]any{"message": "Call me", "source": "", "email": "jane@example.com"})
+	if len(properties) != 1 || properties["Notes__c"] != "Call me" {
+		t.Errorf("Expected only the configured lead field, got %v", properties)
+	}
+	if contact := mapping.Apply(Contact, map[string]any{"email": "jane@example.com"}); contact["Email"] != "jane@example.com" {
+		t.Errorf("Expected the default contact mapping, got %v", contact)
+	}
+}
+
+func TestHubSpotConnector_Upsert(t *testing.T) {
+	var requests []string
+	var leadBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			t.Errorf("Missing token, got %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == http.MethodPatch:
+			// The contact was deleted in HubSpot
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/crm/v3/objects/contacts":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "501"}`))
+		default:
+			json.NewDecoder(r.Body).Decode(&leadBody)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "902"}`))
+		}
+	}))
+	defer server.Close()
+
+	connector := NewHubSpotConnector(server.Client(), server.URL, mlsauth.Bearer{Token: "token-1"})
+	contactID, err := connector.Upsert(context.Background(), Record{Kind: Contact, ID: "17", Properties: map[string]any{"email": "jane@example.com"}})
+	if err != nil || contactID != "501" {
+		t.Fatalf("Expected the contact to be created again as 501, got %q, %v", contactID, err)
+	}
+	leadID, err := connector.Upsert(context.Background(), Record{Kind: Lead, ContactID: contactID, Properties: map[string]any{"hs_lead_name": "Jane"}})
+	if err != nil || leadID != "902" {
+		t.Fatalf("Expected lead 902, got %q, %v", leadID, err)
+	}
+
+	expected := "PATCH /crm/v3/objects/contacts/17,POST /crm/v3/objects/contacts,POST /crm/v3/objects/leads"
+	if got := strings.Join(requests, ","); got != expected {
+		t.Errorf("Expected requests %s, got %s", expected, got)
+	}
+	associations, _ := leadBody["associations"].([]any)
+	if len(associations) != 1 || !strings.Contains(toJSON(associations[0]), `"id":"501"`) {
+		t.Errorf("Expected the lead to be associated with contact 501, got %v", leadBody)
+	}
+}
+
+func TestSalesforceConnector_Upsert(t *testing.T) {
+	var body map[string]any
+	var tokens int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/token" {
+			tokens++
+			w.Write([]byte(`{"access_token": "access-` + string(rune('0'+tokens)) + `", "expires_in": 3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			// The first token was revoked
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/"+salesforceAPIVersion+"/sobjects/Lead" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "00Q5e000001", "success": true}`))
+	}))
+	defer server.Close()
+
+	auth := mlsauth.NewClientCredentials(server.Client(), server.URL+"/services/oauth2/token", "client-1", "secret", "")
+	connector := NewSalesforceConnector(server.Client(), server.URL, auth)
+	id, err := connector.Upsert(context.Background(), Record{Kind: Lead, Properties: map[string]any{"Email": "jane@example.com"}})
+	if err != nil || id != "00Q5e000001" {
+		t.Fatalf("Expected lead 00Q5e000001, got %q, %v", id, err)
+	}
+	if tokens != 2 {
+		t.Errorf("Expected the rejected token to be replaced, got %d token requests", tokens)
+	}
+	if body["LastName"] != salesforceNotProvided || body["Company"] != salesforceNotProvided || body["Email"] != "jane@example.com" {
+		t.Errorf("Expected required fields to be filled in, got %v", body)
+	}
+}
+
+func toJSON(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
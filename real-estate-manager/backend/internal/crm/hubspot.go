@@ -0,0 +1,83 @@
+package crm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+// hubSpotLeadToContact is HubSpot's association type from a lead to its
+// primary contact; HubSpot does not accept a lead without one
+const hubSpotLeadToContact = 578
+
+// hubSpotObjects are the CRM objects records are written to
+var hubSpotObjects = map[string]string{Contact: "contacts", Lead: "leads"}
+
+type hubSpotConnector struct {
+	api     apiClient
+	baseURL string
+}
+
+// NewHubSpotConnector writes records through the HubSpot CRM v3 objects
+// API. baseURL defaults to https://api.hubapi.com.
+func NewHubSpotConnector(client *http.Client, baseURL string, auth mlsauth.Authenticator) Connector {
+	if baseURL == "" {
+		baseURL = "https://api.hubapi.com"
+	}
+	return &hubSpotConnector{api: apiClient{name: HubSpot, client: client, auth: auth}, baseURL: baseURL}
+}
+
+func (c *hubSpotConnector) Name() string {
+	return HubSpot
+}
+
+func (c *hubSpotConnector) DefaultMapping() Mapping {
+	return Mapping{
+		Contact: {"first_name": "firstname", "last_name": "lastname", "email": "email", "phone": "phone"},
+		Lead:    {"name": "hs_lead_name"},
+	}
+}
+
+func (c *hubSpotConnector) Upsert(ctx context.Context, record Record) (string, error) {
+	object, ok := hubSpotObjects[record.Kind]
+	if !ok {
+		return "", fmt.Errorf("unsupported record kind %q", record.Kind)
+	}
+	endpoint, err := url.JoinPath(c.baseURL, "crm/v3/objects", object)
+	if err != nil {
+		return "", fmt.Errorf("invalid HubSpot URL: %w", err)
+	}
+
+	if record.ID != "" {
+		err := c.api.do(ctx, http.MethodPatch, endpoint+"/"+url.PathEscape(record.ID), map[string]any{"properties": record.Properties}, nil)
+		if !errors.Is(err, ErrNotFound) {
+			return record.ID, err
+		}
+		// Deleted in HubSpot since the last sync; create it again
+	}
+
+	body := map[string]any{"properties": record.Properties}
+	if record.Kind == Lead {
+		if record.ContactID == "" {
+			return "", fmt.Errorf("a HubSpot lead needs a contact")
+		}
+		body["associations"] = []map[string]any{{
+			"to":    map[string]string{"id": record.ContactID},
+			"types": []map[string]any{{"associationCategory": "HUBSPOT_DEFINED", "associationTypeId": hubSpotLeadToContact}},
+		}}
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, endpoint, body, &created); err != nil {
+		return "", err
+	}
+	if created.ID == "" {
+		return "", fmt.Errorf("HubSpot returned no record ID")
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,91 @@
+package crm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+// salesforceAPIVersion is the REST API version records are written with
+const salesforceAPIVersion = "v60.0"
+
+// salesforceNotProvided fills fields Salesforce requires when we have no
+// value, as Salesforce's own web-to-lead does
+const salesforceNotProvided = "[not provided]"
+
+// salesforceObjects are the sObjects records are written to
+var salesforceObjects = map[string]string{Contact: "Contact", Lead: "Lead"}
+
+type salesforceConnector struct {
+	api         apiClient
+	instanceURL string
+}
+
+// NewSalesforceConnector writes records through the Salesforce REST API of
+// the org at instanceURL
+func NewSalesforceConnector(client *http.Client, instanceURL string, auth mlsauth.Authenticator) Connector {
+	return &salesforceConnector{api: apiClient{name: Salesforce, client: client, auth: auth}, instanceURL: instanceURL}
+}
+
+func (c *salesforceConnector) Name() string {
+	return Salesforce
+}
+
+func (c *salesforceConnector) DefaultMapping() Mapping {
+	return Mapping{
+		Contact: {"first_name": "FirstName", "last_name": "LastName", "email": "Email", "phone": "Phone"},
+		Lead: {"first_name": "FirstName", "last_name": "LastName", "email": "Email", "phone": "Phone",
+			"message": "Description", "source": "LeadSource"},
+	}
+}
+
+// Upsert writes a Contact or Lead. Salesforce leads stand on their own, so
+// ContactID is not used.
+func (c *salesforceConnector) Upsert(ctx context.Context, record Record) (string, error) {
+	object, ok := salesforceObjects[record.Kind]
+	if !ok {
+		return "", fmt.Errorf("unsupported record kind %q", record.Kind)
+	}
+	endpoint, err := url.JoinPath(c.instanceURL, "services/data", salesforceAPIVersion, "sobjects", object)
+	if err != nil {
+		return "", fmt.Errorf("invalid Salesforce URL: %w", err)
+	}
+
+	if record.ID != "" {
+		err := c.api.do(ctx, http.MethodPatch, endpoint+"/"+url.PathEscape(record.ID), record.Properties, nil)
+		if !errors.Is(err, ErrNotFound) {
+			return record.ID, err
+		}
+		// Deleted in Salesforce since the last sync; create it again
+	}
+
+	properties := map[string]any{}
+	for name, value := range record.Properties {
+		properties[name] = value
+	}
+	required := []string{"LastName"}
+	if record.Kind == Lead {
+		required = append(required, "Company")
+	}
+	for _, name := range required {
+		if _, ok := properties[name]; !ok {
+			properties[name] = salesforceNotProvided
+		}
+	}
+
+	var created struct {
+		ID      string `json:"id"`
+		Success bool   `json:"success"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, endpoint, properties, &created); err != nil {
+		return "", err
+	}
+	if !created.Success || created.ID == "" {
+		return "", fmt.Errorf("Salesforce did not create the %s", object)
+	}
+	return created.ID, nil
+}
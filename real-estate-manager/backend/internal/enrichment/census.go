@@ -0,0 +1,61 @@
+package enrichment
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+const censusBaseURL = "https://geocoding.geo.census.gov/geocoder"
+
+// School district layers, most specific first
+var censusSchoolLayers = []string{"Unified School Districts", "Secondary School Districts", "Elementary School Districts"}
+
+// CensusSchoolDistrictProvider looks up the US school district for a
+// coordinate with the Census Bureau geographies API
+type CensusSchoolDistrictProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewCensusSchoolDistrictProvider(client *http.Client) *CensusSchoolDistrictProvider {
+	return &CensusSchoolDistrictProvider{client: client, baseURL: censusBaseURL}
+}
+
+func (p *CensusSchoolDistrictProvider) Name() string {
+	return "census_schools"
+}
+
+func (p *CensusSchoolDistrictProvider) Enrich(ctx context.Context, location Location, enrichment *models.Enrichment) error {
+	query := url.Values{
+		"x":         {strconv.FormatFloat(location.Longitude, 'f', 7, 64)},
+		"y":         {strconv.FormatFloat(location.Latitude, 'f', 7, 64)},
+		"benchmark": {"Public_AR_Current"},
+		"vintage":   {"Current_Current"},
+		"layers":    {"all"},
+		"format":    {"json"},
+	}
+
+	var response struct {
+		Result struct {
+			Geographies map[string][]struct {
+				Name string `json:"NAME"`
+			} `json:"geographies"`
+		} `json:"result"`
+	}
+	if err := getJSON(ctx, p.client, p.baseURL+"/geographies/coordinates?"+query.Encode(), &response); err != nil {
+		return err
+	}
+
+	for _, layer := range censusSchoolLayers {
+		if districts := response.Result.Geographies[layer]; len(districts) > 0 && districts[0].Name != "" {
+			enrichment.SchoolDistrict = models.NullString{NullString: sql.NullString{String: districts[0].Name, Valid: true}}
+			return nil
+		}
+	}
+	return ErrNoMatch
+}
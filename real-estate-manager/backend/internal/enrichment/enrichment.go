@@ -0,0 +1,75 @@
+// Package enrichment attaches third-party location data (school district,
+// walk score, neighborhood) to properties. Geocoders and providers are
+// pluggable so sources can be swapped per deployment.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// ErrNoMatch is returned when a source has no data for a location
+var ErrNoMatch = errors.New("no match for location")
+
+// Location is a geocoded address
+type Location struct {
+	Address   string
+	Latitude  float64
+	Longitude float64
+}
+
+// Geocoder resolves a free-text address to coordinates
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (Location, error)
+}
+
+// Provider fills in the enrichment fields it is responsible for
+type Provider interface {
+	Name() string
+	Enrich(ctx context.Context, location Location, enrichment *models.Enrichment) error
+}
+
+const userAgent = "real-estate-manager/1.0"
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second}
+}
+
+// NewFromEnv builds the geocoder and providers configured through
+// ENRICHMENT_PROVIDERS (comma separated; default: census_schools,neighborhood
+// plus walkscore when WALKSCORE_API_KEY is set). ENRICHMENT_PROVIDERS=none
+// disables enrichment, in which case the geocoder is nil.
+func NewFromEnv() (Geocoder, []Provider) {
+	walkScoreKey := os.Getenv("WALKSCORE_API_KEY")
+
+	names := os.Getenv("ENRICHMENT_PROVIDERS")
+	if names == "" {
+		names = "census_schools,neighborhood"
+		if walkScoreKey != "" {
+			names += ",walkscore"
+		}
+	}
+	if names == "none" {
+		return nil, nil
+	}
+
+	client := newHTTPClient()
+	var providers []Provider
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "census_schools":
+			providers = append(providers, NewCensusSchoolDistrictProvider(client))
+		case "neighborhood":
+			providers = append(providers, NewNominatimNeighborhoodProvider(client))
+		case "walkscore":
+			providers = append(providers, NewWalkScoreProvider(client, walkScoreKey))
+		}
+	}
+	return NewNominatimGeocoder(client), providers
+}
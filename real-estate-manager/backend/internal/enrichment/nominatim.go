@@ -0,0 +1,113 @@
+package enrichment
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+const nominatimBaseURL = "https://nominatim.openstreetmap.org"
+
+// NominatimGeocoder geocodes addresses with OpenStreetMap Nominatim
+type NominatimGeocoder struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewNominatimGeocoder(client *http.Client) *NominatimGeocoder {
+	return &NominatimGeocoder{client: client, baseURL: nominatimBaseURL}
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (Location, error) {
+	query := url.Values{"q": {address}, "format": {"jsonv2"}, "limit": {"1"}}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := getJSON(ctx, g.client, g.baseURL+"/search?"+query.Encode(), &results); err != nil {
+		return Location{}, err
+	}
+	if len(results) == 0 {
+		return Location{}, ErrNoMatch
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid latitude %q: %w", results[0].Lat, err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid longitude %q: %w", results[0].Lon, err)
+	}
+	return Location{Address: address, Latitude: lat, Longitude: lon}, nil
+}
+
+// NominatimNeighborhoodProvider reverse-geocodes the neighborhood name
+type NominatimNeighborhoodProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewNominatimNeighborhoodProvider(client *http.Client) *NominatimNeighborhoodProvider {
+	return &NominatimNeighborhoodProvider{client: client, baseURL: nominatimBaseURL}
+}
+
+func (p *NominatimNeighborhoodProvider) Name() string {
+	return "neighborhood"
+}
+
+func (p *NominatimNeighborhoodProvider) Enrich(ctx context.Context, location Location, enrichment *models.Enrichment) error {
+	query := url.Values{
+		"lat":    {strconv.FormatFloat(location.Latitude, 'f', 7, 64)},
+		"lon":    {strconv.FormatFloat(location.Longitude, 'f', 7, 64)},
+		"format": {"jsonv2"},
+		"zoom":   {"16"},
+	}
+
+	var result struct {
+		Address struct {
+			Neighbourhood string `json:"neighbourhood"`
+			Quarter       string `json:"quarter"`
+			Suburb        string `json:"suburb"`
+		} `json:"address"`
+	}
+	if err := getJSON(ctx, p.client, p.baseURL+"/reverse?"+query.Encode(), &result); err != nil {
+		return err
+	}
+
+	for _, name := range []string{result.Address.Neighbourhood, result.Address.Quarter, result.Address.Suburb} {
+		if name != "" {
+			enrichment.Neighborhood = models.NullString{NullString: sql.NullString{String: name, Valid: true}}
+			return nil
+		}
+	}
+	return ErrNoMatch
+}
+
+// getJSON performs a GET request and decodes a JSON response
+func getJSON(ctx context.Context, client *http.Client, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
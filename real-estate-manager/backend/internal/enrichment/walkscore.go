@@ -0,0 +1,58 @@
+package enrichment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+const walkScoreBaseURL = "https://api.walkscore.com"
+
+// WalkScoreProvider fetches the Walk Score for a location
+type WalkScoreProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func NewWalkScoreProvider(client *http.Client, apiKey string) *WalkScoreProvider {
+	return &WalkScoreProvider{client: client, baseURL: walkScoreBaseURL, apiKey: apiKey}
+}
+
+func (p *WalkScoreProvider) Name() string {
+	return "walkscore"
+}
+
+func (p *WalkScoreProvider) Enrich(ctx context.Context, location Location, enrichment *models.Enrichment) error {
+	if p.apiKey == "" {
+		return errors.New("WALKSCORE_API_KEY is not set")
+	}
+
+	query := url.Values{
+		"format":   {"json"},
+		"address":  {location.Address},
+		"lat":      {strconv.FormatFloat(location.Latitude, 'f', 7, 64)},
+		"lon":      {strconv.FormatFloat(location.Longitude, 'f', 7, 64)},
+		"wsapikey": {p.apiKey},
+	}
+
+	var result struct {
+		Status    int `json:"status"`
+		WalkScore int `json:"walkscore"`
+	}
+	if err := getJSON(ctx, p.client, p.baseURL+"/score?"+query.Encode(), &result); err != nil {
+		return err
+	}
+	// Status 1 means a score is available; 2 means it is still being calculated
+	if result.Status != 1 {
+		return ErrNoMatch
+	}
+
+	enrichment.WalkScore = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(result.WalkScore), Valid: true}}
+	return nil
+}
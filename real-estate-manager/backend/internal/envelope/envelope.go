@@ -0,0 +1,148 @@
+// Package envelope writes JSON responses. Routes under /api/v1 wrap every
+// body in the same envelope,
+//
+//	{"data": ..., "meta": {"pagination": ...}, "errors": [{"code": ..., "message": ...}]}
+//
+// so clients parse all responses one way. The unversioned /api routes keep
+// their original bodies for existing clients.
+//
+// Error messages are translated to the locale negotiated from the request's
+// Accept-Language header, which is echoed in Content-Language.
+package envelope
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"real-estate-manager/backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+const versionedKey = "envelope.versioned"
+
+// Body is the envelope of a /api/v1 response. Data is null on errors.
+type Body struct {
+	Data   any       `json:"data"`
+	Meta   Meta      `json:"meta,omitempty"`
+	Errors []Problem `json:"errors,omitempty"`
+}
+
+// Meta describes a response beyond its data, e.g. its "pagination"
+type Meta map[string]any
+
+// Problem is one error in a response. Code is derived from the status,
+// e.g. "not_found"; Details carries structured context such as conflicts.
+type Problem struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details gin.H  `json:"details,omitempty"`
+}
+
+// Pagination is the "pagination" meta of a list. Limit and Page echo the
+// request, so a page with fewer than Limit items is the last. Cursor-paged
+// lists also set Cursor and HasMore.
+type Pagination struct {
+	Limit   int    `json:"limit,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	Count   int    `json:"count"`
+	Cursor  string `json:"cursor,omitempty"`
+	HasMore bool   `json:"has_more,omitempty"`
+}
+
+// Versioned marks the requests of a route group as /api/v1 ones. It must
+// run before any middleware that may respond.
+func Versioned() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(versionedKey, true)
+		c.Next()
+	}
+}
+
+// IsVersioned reports whether the request came through Versioned
+func IsVersioned(c *gin.Context) bool {
+	return c.GetBool(versionedKey)
+}
+
+// JSON writes data with status
+func JSON(c *gin.Context, status int, data any) {
+	if !IsVersioned(c) {
+		writeJSON(c, status, data)
+		return
+	}
+	writeJSON(c, status, Body{Data: data})
+}
+
+// List writes a page of items with its pagination. Count is filled in.
+func List(c *gin.Context, items any, page Pagination) {
+	Page(c, items, items, Meta{"pagination": page})
+}
+
+// Page writes a list whose unversioned body differs from its items, e.g.
+// {"jobs": [...]}. Versioned requests get items as data and meta, where a
+// Pagination's Count is filled in.
+func Page(c *gin.Context, unversioned, items any, meta Meta) {
+	if !IsVersioned(c) {
+		writeJSON(c, http.StatusOK, unversioned)
+		return
+	}
+	if page, ok := meta["pagination"].(Pagination); ok {
+		if value := reflect.ValueOf(items); value.Kind() == reflect.Slice {
+			page.Count = value.Len()
+		}
+		meta["pagination"] = page
+	}
+	writeJSON(c, http.StatusOK, Body{Data: items, Meta: meta})
+}
+
+// Error writes an error message, as {"error": message} on unversioned
+// routes
+func Error(c *gin.Context, status int, message string) {
+	ErrorDetails(c, status, message, nil)
+}
+
+// Errorf writes an error message built from format and args, translating
+// format before the args are applied
+func Errorf(c *gin.Context, status int, format string, args ...any) {
+	writeError(c, status, localize(c, format, args), nil)
+}
+
+// ErrorDetails writes an error with structured details, which unversioned
+// routes merge into the body next to "error"
+func ErrorDetails(c *gin.Context, status int, message string, details gin.H) {
+	writeError(c, status, localize(c, message, nil), details)
+}
+
+// Translate returns message in the locale negotiated for the request, for
+// text of successful responses, such as warnings
+func Translate(c *gin.Context, message string) string {
+	return localize(c, message, nil)
+}
+
+func localize(c *gin.Context, format string, args []any) string {
+	locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	return i18n.Translate(locale, format, args...)
+}
+
+func writeError(c *gin.Context, status int, message string, details gin.H) {
+	if !IsVersioned(c) {
+		body := gin.H{"error": message}
+		for key, value := range details {
+			body[key] = value
+		}
+		writeJSON(c, status, body)
+		return
+	}
+	writeJSON(c, status, Body{Errors: []Problem{{Code: Code(status), Message: message, Details: details}}})
+}
+
+// Code names an error status in snake case, e.g. "too_many_requests"
+func Code(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		text = "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
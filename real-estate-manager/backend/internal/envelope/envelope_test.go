@@ -0,0 +1,128 @@
+package envelope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serve(versioned bool, handler gin.HandlerFunc) string {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if versioned {
+		r.Use(Versioned())
+	}
+	r.GET("/", handler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	return w.Body.String()
+}
+
+func TestResponses(t *testing.T) {
+	tests := []struct {
+		name                string
+		handler             gin.HandlerFunc
+		expectedUnversioned string
+		expectedVersioned   string
+	}{
+		{
+			name:                "data",
+			handler:             func(c *gin.Context) { JSON(c, http.StatusOK, gin.H{"token": "abc"}) },
+			expectedUnversioned: `{"token":"abc"}`,
+			expectedVersioned:   `{"data":{"token":"abc"}}`,
+		},
+		{
+			name:                "list",
+			handler:             func(c *gin.Context) { List(c, []int{1, 2}, Pagination{Limit: 20, Page: 2}) },
+			expectedUnversioned: `[1,2]`,
+			expectedVersioned:   `{"data":[1,2],"meta":{"pagination":{"limit":20,"page":2,"count":2}}}`,
+		},
+		{
+			name: "page with its own unversioned body",
+			handler: func(c *gin.Context) {
+				Page(c, gin.H{"jobs": []int{}}, []int{}, Meta{"pagination": Pagination{Cursor: "c1", HasMore: true}})
+			},
+			expectedUnversioned: `{"jobs":[]}`,
+			expectedVersioned:   `{"data":[],"meta":{"pagination":{"count":0,"cursor":"c1","has_more":true}}}`,
+		},
+		{
+			name:                "error",
+			handler:             func(c *gin.Context) { Error(c, http.StatusNotFound, "Property not found") },
+			expectedUnversioned: `{"error":"Property not found"}`,
+			expectedVersioned:   `{"data":null,"errors":[{"code":"not_found","message":"Property not found"}]}`,
+		},
+		{
+			name: "error with details",
+			handler: func(c *gin.Context) {
+				ErrorDetails(c, http.StatusConflict, "Outdated version", gin.H{"conflicts": []int{4}})
+			},
+			expectedUnversioned: `{"conflicts":[4],"error":"Outdated version"}`,
+			expectedVersioned:   `{"data":null,"errors":[{"code":"conflict","message":"Outdated version","details":{"conflicts":[4]}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if body := serve(false, tt.handler); body != tt.expectedUnversioned {
+				t.Errorf("Expected unversioned body %s, got %s", tt.expectedUnversioned, body)
+			}
+			if body := serve(true, tt.handler); body != tt.expectedVersioned {
+				t.Errorf("Expected versioned body %s, got %s", tt.expectedVersioned, body)
+			}
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	if code := Code(http.StatusTooManyRequests); code != "too_many_requests" {
+		t.Errorf("Expected too_many_requests, got %s", code)
+	}
+	if code := Code(499); code != "error" {
+		t.Errorf("Expected error for an unknown status, got %s", code)
+	}
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		Errorf(c, http.StatusBadRequest, "limit must be between 1 and %d", 200)
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if body := w.Body.String(); body != `{"error":"limit deve estar entre 1 e 200"}` {
+		t.Errorf("Expected a Portuguese error, got %s", body)
+	}
+	if language := w.Header().Get("Content-Language"); language != "pt" {
+		t.Errorf("Expected Content-Language pt, got %q", language)
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+	items := make([]map[string]any, 10000)
+	for i := range items {
+		items[i] = map[string]any{"id": i, "name": "Casa Verde", "location": "12 Elm St, Austin, TX 78701", "price": 350000}
+	}
+	handlers := map[string]gin.HandlerFunc{
+		"gin":    func(c *gin.Context) { c.JSON(http.StatusOK, Body{Data: items}) },
+		"pooled": func(c *gin.Context) { List(c, items, Pagination{Limit: len(items)}) },
+	}
+
+	for _, name := range []string{"gin", "pooled"} {
+		r := gin.New()
+		r.Use(Versioned())
+		r.GET("/", handlers[name])
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			}
+		})
+	}
+}
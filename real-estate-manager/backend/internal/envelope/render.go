@@ -0,0 +1,52 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPooledBuffer keeps the buffers of unusually large responses out of the
+// pool, so one huge export does not stay in memory
+const maxPooledBuffer = 4 << 20
+
+// buffers holds the buffers responses are encoded into. A property list
+// of thousands of rows otherwise grows a new multi-megabyte buffer, copy by
+// copy, for every response.
+var buffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// pooledJSON renders data as gin's JSON renderer does, into a pooled buffer
+type pooledJSON struct {
+	data any
+}
+
+func (r pooledJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	buf := buffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			buffers.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(r.data); err != nil {
+		return err
+	}
+	// Encode ends the value with a newline json.Marshal does not add
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+func (r pooledJSON) WriteContentType(w http.ResponseWriter) {
+	if header := w.Header(); len(header["Content-Type"]) == 0 {
+		header["Content-Type"] = []string{"application/json; charset=utf-8"}
+	}
+}
+
+func writeJSON(c *gin.Context, status int, data any) {
+	c.Render(status, pooledJSON{data: data})
+}
@@ -0,0 +1,176 @@
+package esign
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+// docuSignStatuses maps DocuSign Connect envelope events to statuses
+var docuSignStatuses = map[string]string{
+	"envelope-sent":      StatusSent,
+	"envelope-delivered": StatusDelivered,
+	"envelope-completed": StatusCompleted,
+	"envelope-declined":  StatusDeclined,
+	"envelope-voided":    StatusVoided,
+}
+
+// docuSignSignaturePrefix starts the canonical names of the headers
+// carrying Connect HMAC signatures, one per active key
+const docuSignSignaturePrefix = "X-Docusign-Signature-"
+
+type docuSignProvider struct {
+	client     *http.Client
+	baseURL    string
+	accountID  string
+	auth       mlsauth.Authenticator
+	connectKey []byte
+}
+
+// NewDocuSignProvider sends envelopes through the DocuSign eSignature
+// REST API v2.1 and reads DocuSign Connect JSON callbacks signed with
+// connectKey. baseURL defaults to https://demo.docusign.net/restapi.
+func NewDocuSignProvider(client *http.Client, baseURL, accountID string, auth mlsauth.Authenticator, connectKey string) Provider {
+	if baseURL == "" {
+		baseURL = "https://demo.docusign.net/restapi"
+	}
+	return &docuSignProvider{client: client, baseURL: strings.TrimSuffix(baseURL, "/"), accountID: accountID, auth: auth,
+		connectKey: []byte(connectKey)}
+}
+
+func (p *docuSignProvider) Name() string {
+	return DocuSign
+}
+
+func (p *docuSignProvider) Send(ctx context.Context, envelope Envelope) (string, error) {
+	extension := strings.TrimPrefix(strings.ToLower(filepath.Ext(envelope.DocumentName)), ".")
+	if extension == "" {
+		extension = "pdf"
+	}
+	signers := make([]map[string]string, len(envelope.Signers))
+	for i, signer := range envelope.Signers {
+		id := strconv.Itoa(i + 1)
+		signers[i] = map[string]string{"name": signer.Name, "email": signer.Email, "recipientId": id, "routingOrder": id}
+	}
+	body := map[string]any{
+		"emailSubject": envelope.Subject,
+		"emailBlurb":   envelope.Message,
+		"documents": []map[string]string{{
+			"documentId":     "1",
+			"name":           envelope.DocumentName,
+			"fileExtension":  extension,
+			"documentBase64": base64.StdEncoding.EncodeToString(envelope.Document),
+		}},
+		"recipients": map[string]any{"signers": signers},
+		"status":     "sent",
+	}
+
+	var created struct {
+		EnvelopeID string `json:"envelopeId"`
+	}
+	if err := p.do(ctx, http.MethodPost, p.envelopesURL(), body, &created); err != nil {
+		return "", err
+	}
+	if created.EnvelopeID == "" {
+		return "", fmt.Errorf("DocuSign returned no envelope ID")
+	}
+	return created.EnvelopeID, nil
+}
+
+func (p *docuSignProvider) Void(ctx context.Context, envelopeID, reason string) error {
+	body := map[string]string{"status": "voided", "voidedReason": reason}
+	return p.do(ctx, http.MethodPut, p.envelopesURL()+"/"+url.PathEscape(envelopeID), body, nil)
+}
+
+// ParseCallback reads a Connect JSON (SIM) callback. Connect signs each
+// delivery with every active key, so any one signature may match.
+func (p *docuSignProvider) ParseCallback(header http.Header, body []byte) (*Event, error) {
+	if !p.verify(header, body) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload struct {
+		Event             string    `json:"event"`
+		GeneratedDateTime time.Time `json:"generatedDateTime"`
+		Data              struct {
+			EnvelopeID string `json:"envelopeId"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if payload.Data.EnvelopeID == "" {
+		return nil, fmt.Errorf("%w: no envelope ID", ErrInvalidPayload)
+	}
+	return &Event{EnvelopeID: payload.Data.EnvelopeID, Status: docuSignStatuses[payload.Event], At: payload.GeneratedDateTime}, nil
+}
+
+func (p *docuSignProvider) verify(header http.Header, body []byte) bool {
+	mac := hmac.New(sha256.New, p.connectKey)
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	for name, values := range header {
+		if !strings.HasPrefix(name, docuSignSignaturePrefix) {
+			continue
+		}
+		for _, value := range values {
+			signature, err := base64.StdEncoding.DecodeString(value)
+			if err == nil && hmac.Equal(signature, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *docuSignProvider) envelopesURL() string {
+	return p.baseURL + "/v2.1/accounts/" + url.PathEscape(p.accountID) + "/envelopes"
+}
+
+// do sends body to target and decodes the response into result, if any
+func (p *docuSignProvider) do(ctx context.Context, method, target string, body, result any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create DocuSign request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if err := p.auth.Authorize(ctx, req); err != nil {
+		return fmt.Errorf("failed to authorize DocuSign request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("DocuSign request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("DocuSign returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode DocuSign response: %w", err)
+	}
+	return nil
+}
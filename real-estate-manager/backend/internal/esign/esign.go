@@ -0,0 +1,99 @@
+// Package esign sends documents for electronic signature through a
+// provider such as DocuSign and reads the status callbacks the provider
+// posts back as signers open, sign or decline them.
+package esign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+// E-signature providers
+const (
+	DocuSign = "docusign"
+)
+
+// Envelope statuses reported by callbacks. An envelope is delivered once a
+// signer opened it; completed, declined and voided are final.
+const (
+	StatusSent      = "sent"
+	StatusDelivered = "delivered"
+	StatusCompleted = "completed"
+	StatusDeclined  = "declined"
+	StatusVoided    = "voided"
+)
+
+var (
+	// ErrInvalidSignature is returned for callbacks not signed with the
+	// provider's key
+	ErrInvalidSignature = errors.New("invalid callback signature")
+	// ErrInvalidPayload is returned for malformed callbacks
+	ErrInvalidPayload = errors.New("invalid callback payload")
+)
+
+// Signer is someone who must sign the document. Signers sign in the order
+// given.
+type Signer struct {
+	Name  string
+	Email string
+}
+
+// Envelope is a document sent out for signature
+type Envelope struct {
+	Subject string
+	Message string
+	// DocumentName is shown to signers; its extension tells the provider
+	// the document's format
+	DocumentName string
+	Document     []byte
+	Signers      []Signer
+}
+
+// Event is an envelope's status change read from a callback. Status is
+// empty for callbacks about anything else, which are acknowledged and
+// ignored.
+type Event struct {
+	EnvelopeID string
+	Status     string
+	At         time.Time
+}
+
+// Provider sends envelopes through one e-signature service
+type Provider interface {
+	Name() string
+	// Send sends an envelope to its signers and returns its ID at the
+	// provider
+	Send(ctx context.Context, envelope Envelope) (string, error)
+	// Void cancels an envelope that was not completed
+	Void(ctx context.Context, envelopeID, reason string) error
+	// ParseCallback verifies a callback delivery and reads the status
+	// change it reports
+	ParseCallback(header http.Header, body []byte) (*Event, error)
+}
+
+// NewFromEnv returns the provider selected by ESIGN_PROVIDER, or nil when
+// none is. "docusign" needs DOCUSIGN_ACCOUNT_ID, DOCUSIGN_ACCESS_TOKEN and
+// DOCUSIGN_CONNECT_KEY, the HMAC key callbacks are signed with;
+// DOCUSIGN_BASE_URL defaults to the demo environment.
+func NewFromEnv() (Provider, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch provider := strings.ToLower(os.Getenv("ESIGN_PROVIDER")); provider {
+	case "", "none":
+		return nil, nil
+	case DocuSign:
+		accountID, token, key := os.Getenv("DOCUSIGN_ACCOUNT_ID"), os.Getenv("DOCUSIGN_ACCESS_TOKEN"), os.Getenv("DOCUSIGN_CONNECT_KEY")
+		if accountID == "" || token == "" || key == "" {
+			return nil, fmt.Errorf("DOCUSIGN_ACCOUNT_ID, DOCUSIGN_ACCESS_TOKEN and DOCUSIGN_CONNECT_KEY are required for the docusign provider")
+		}
+		return NewDocuSignProvider(client, os.Getenv("DOCUSIGN_BASE_URL"), accountID, mlsauth.Bearer{Token: token}, key), nil
+	default:
+		return nil, fmt.Errorf("unknown ESIGN_PROVIDER %q", provider)
+	}
+}
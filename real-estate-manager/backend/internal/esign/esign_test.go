@@ -0,0 +1,117 @@
+package esign
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"real-estate-manager/backend/pkg/mlsauth"
+)
+
+func TestDocuSignProvider_Send(t *testing.T) {
+	var body struct {
+		EmailSubject string `json:"emailSubject"`
+		Documents    []struct {
+			Name           string `json:"name"`
+			FileExtension  string `json:"fileExtension"`
+			DocumentBase64 string `json:"documentBase64"`
+		} `json:"documents"`
+		Recipients struct {
+			Signers []map[string]string `json:"signers"`
+		} `json:"recipients"`
+		Status string `json:"status"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2.1/accounts/acct-1/envelopes" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			t.Errorf("Missing token, got %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Invalid body: %v", err)
+		}
+		w.Write([]byte(`{"envelopeId": "env-1", "status": "sent"}`))
+	}))
+	defer server.Close()
+
+	provider := NewDocuSignProvider(server.Client(), server.URL, "acct-1", mlsauth.Bearer{Token: "token-1"}, "key")
+	id, err := provider.Send(context.Background(), Envelope{
+		Subject:      "Please sign your offer",
+		DocumentName: "Offer.PDF",
+		Document:     []byte("%PDF-1.4"),
+		Signers:      []Signer{{Name: "Jane Roe", Email: "jane@example.com"}, {Name: "John Roe", Email: "john@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "env-1" {
+		t.Errorf("Expected env-1, got %q", id)
+	}
+	if body.Status != "sent" || len(body.Documents) != 1 || body.Documents[0].FileExtension != "pdf" ||
+		body.Documents[0].DocumentBase64 != base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")) {
+		t.Errorf("Unexpected envelope %+v", body)
+	}
+	if signers := body.Recipients.Signers; len(signers) != 2 || signers[1]["routingOrder"] != "2" || signers[1]["email"] != "john@example.com" {
+		t.Errorf("Expected signers in routing order, got %v", signers)
+	}
+}
+
+func TestDocuSignProvider_ParseCallback(t *testing.T) {
+	sign := func(key string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write(body)
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	completed := []byte(`{"event": "envelope-completed", "generatedDateTime": "2024-06-01T12:00:00Z", "data": {"envelopeId": "env-1"}}`)
+	recipient := []byte(`{"event": "recipient-sent", "data": {"envelopeId": "env-1"}}`)
+
+	tests := []struct {
+		name         string
+		body         []byte
+		signatures   map[string]string
+		expectErr    error
+		expectStatus string
+	}{
+		{name: "completed", body: completed, signatures: map[string]string{"X-DocuSign-Signature-1": sign("key", completed)},
+			expectStatus: StatusCompleted},
+		{name: "second key matches", body: completed,
+			signatures:   map[string]string{"X-DocuSign-Signature-1": sign("old", completed), "X-DocuSign-Signature-2": sign("key", completed)},
+			expectStatus: StatusCompleted},
+		{name: "other event", body: recipient, signatures: map[string]string{"X-DocuSign-Signature-1": sign("key", recipient)}},
+		{name: "wrong key", body: completed, signatures: map[string]string{"X-DocuSign-Signature-1": sign("old", completed)},
+			expectErr: ErrInvalidSignature},
+		{name: "unsigned", body: completed, expectErr: ErrInvalidSignature},
+		{name: "malformed", body: []byte(`{`), signatures: map[string]string{"X-DocuSign-Signature-1": sign("key", []byte(`{`))},
+			expectErr: ErrInvalidPayload},
+	}
+
+	provider := NewDocuSignProvider(http.DefaultClient, "", "acct-1", mlsauth.Bearer{Token: "token-1"}, "key")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.signatures {
+				header.Set(name, value)
+			}
+			event, err := provider.ParseCallback(header, tt.body)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if event.EnvelopeID != "env-1" || event.Status != tt.expectStatus {
+				t.Errorf("Unexpected event %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Encoding formats
+const (
+	FormatJSON     = "json"
+	FormatProtobuf = "protobuf"
+)
+
+// Field numbers of the Envelope message in events.proto
+const (
+	fieldID            protowire.Number = 1
+	fieldType          protowire.Number = 2
+	fieldSchemaVersion protowire.Number = 3
+	fieldOccurredAt    protowire.Number = 4
+	fieldSubject       protowire.Number = 5
+	fieldActorID       protowire.Number = 6
+	fieldData          protowire.Number = 7
+)
+
+// ContentType returns the MIME type of payloads in format
+func ContentType(format string) string {
+	if format == FormatProtobuf {
+		return "application/x-protobuf"
+	}
+	return "application/json"
+}
+
+// Encode serializes event as JSON or as a protobuf Envelope whose data
+// field holds the JSON-encoded event data
+func Encode(event Event, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.Marshal(event)
+	case FormatProtobuf:
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		var b []byte
+		b = appendString(b, fieldID, event.ID)
+		b = appendString(b, fieldType, event.Type)
+		b = protowire.AppendTag(b, fieldSchemaVersion, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(event.SchemaVersion))
+		b = protowire.AppendTag(b, fieldOccurredAt, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(event.OccurredAt.UnixMilli()))
+		b = appendString(b, fieldSubject, event.Subject)
+		if event.ActorID != 0 {
+			b = protowire.AppendTag(b, fieldActorID, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(event.ActorID))
+		}
+		b = protowire.AppendTag(b, fieldData, protowire.BytesType)
+		b = protowire.AppendBytes(b, data)
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown event format %q", format)
+	}
+}
+
+func appendString(b []byte, field protowire.Number, value string) []byte {
+	b = protowire.AppendTag(b, field, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
@@ -0,0 +1,243 @@
+// Package events publishes property and job domain events to a message bus
+// (NATS or Kafka) so downstream systems can follow changes in near real
+// time. Events are encoded as JSON or as a protobuf envelope (see
+// events.proto) and carry a schema version. In-process subscribers, such as
+// the notification center, receive every event whether or not a broker is
+// configured.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is bumped whenever the shape of an event's data changes
+// incompatibly
+const SchemaVersion = 1
+
+// Event types
+const (
+	PropertyCreated       = "property.created"
+	PropertyUpdated       = "property.updated"
+	PropertyDeleted       = "property.deleted"
+	PropertiesBulkUpdated = "property.bulk_updated"
+	JobStarted            = "job.started"
+	JobCompleted          = "job.completed"
+	JobFailed             = "job.failed"
+	JobCancelled          = "job.cancelled"
+	LeadCreated           = "lead.created"
+	ListingSubmitted      = "listing.submitted"
+	ListingApproved       = "listing.approved"
+	ListingRejected       = "listing.rejected"
+	ListingUnpublished    = "listing.unpublished"
+	ListingExpiring       = "listing.expiring"
+	ListingExpired        = "listing.expired"
+	OfferSubmitted        = "offer.submitted"
+	OfferCountered        = "offer.countered"
+	OfferAccepted         = "offer.accepted"
+	OfferRejected         = "offer.rejected"
+)
+
+// defaultBuffer is how many events may wait for the bus before new ones are
+// dropped
+const defaultBuffer = 1000
+
+// Event is a domain event. Subject identifies the entity, e.g. "property/12",
+// and is used as the message key so events for one entity stay ordered.
+type Event struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	SchemaVersion int       `json:"schema_version"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Subject       string    `json:"subject"`
+	ActorID       int       `json:"actor_id,omitempty"`
+	Data          any       `json:"data,omitempty"`
+}
+
+// New returns an event of eventType about subject, stamped with a new ID and
+// the current time
+func New(eventType, subject string, data any) Event {
+	return Event{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		SchemaVersion: SchemaVersion,
+		OccurredAt:    time.Now().UTC(),
+		Subject:       subject,
+		Data:          data,
+	}
+}
+
+// Message is an encoded event ready for a transport
+type Message struct {
+	Topic   string
+	Key     string
+	Type    string
+	Payload []byte
+	Headers map[string]string
+}
+
+// Transport delivers messages to a broker
+type Transport interface {
+	Send(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Handler receives events in-process
+type Handler func(ctx context.Context, event Event)
+
+// Bus hands events to its subscribers and encodes them for a transport from
+// a background goroutine, so publishing never blocks or fails the request
+// that caused the event. When the buffer is full, events are dropped and
+// logged.
+type Bus struct {
+	transport Transport
+	topic     string
+	format    string
+	queue     chan Event
+	done      sync.WaitGroup
+	closeOnce sync.Once
+
+	mu          sync.RWMutex
+	subscribers []Handler
+}
+
+// NewBus creates a bus; transport may be nil, in which case events only
+// reach subscribers
+func NewBus(transport Transport, topic, format string, buffer int) *Bus {
+	bus := &Bus{transport: transport, topic: topic, format: format, queue: make(chan Event, buffer)}
+	bus.done.Add(1)
+	go bus.run()
+	return bus
+}
+
+// NewLocalBus creates a bus that only delivers to in-process subscribers
+func NewLocalBus() *Bus {
+	return NewBus(nil, "", FormatJSON, defaultBuffer)
+}
+
+// NewFromEnv builds the bus selected by EVENT_BUS: "none" (default, returns
+// nil), "nats" (NATS_URL) or "kafka" (KAFKA_REST_URL, a Kafka REST proxy).
+// EVENT_TOPIC names the topic and EVENT_FORMAT selects "json" (default) or
+// "protobuf".
+func NewFromEnv() (*Bus, error) {
+	format := strings.ToLower(getEnv("EVENT_FORMAT", FormatJSON))
+	if format != FormatJSON && format != FormatProtobuf {
+		return nil, fmt.Errorf("unknown EVENT_FORMAT %q", format)
+	}
+	topic := getEnv("EVENT_TOPIC", "real-estate.events")
+
+	var transport Transport
+	switch provider := strings.ToLower(os.Getenv("EVENT_BUS")); provider {
+	case "", "none":
+		return nil, nil
+	case "nats":
+		nats, err := NewNATSTransport(getEnv("NATS_URL", "nats://localhost:4222"))
+		if err != nil {
+			return nil, err
+		}
+		transport = nats
+	case "kafka":
+		restURL := os.Getenv("KAFKA_REST_URL")
+		if restURL == "" {
+			return nil, fmt.Errorf("KAFKA_REST_URL is required for the kafka event bus")
+		}
+		transport = NewKafkaRESTTransport(nil, restURL)
+	default:
+		return nil, fmt.Errorf("unknown EVENT_BUS %q", provider)
+	}
+	return NewBus(transport, topic, format, defaultBuffer), nil
+}
+
+// Subscribe registers handler for every event published after the call.
+// Handlers run one at a time on the bus goroutine and should be quick.
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, handler)
+}
+
+// Publish queues event for delivery
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	select {
+	case b.queue <- event:
+	default:
+		log.Printf("Event bus full, dropping %s event for %s", event.Type, event.Subject)
+	}
+}
+
+// Close delivers queued events and closes the transport
+func (b *Bus) Close() error {
+	b.closeOnce.Do(func() { close(b.queue) })
+	b.done.Wait()
+	if b.transport == nil {
+		return nil
+	}
+	return b.transport.Close()
+}
+
+func (b *Bus) run() {
+	defer b.done.Done()
+	for event := range b.queue {
+		b.notify(event)
+		if b.transport == nil {
+			continue
+		}
+
+		msg, err := b.encode(event)
+		if err != nil {
+			log.Printf("Failed to encode %s event for %s: %v", event.Type, event.Subject, err)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = b.transport.Send(ctx, msg)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to publish %s event for %s: %v", event.Type, event.Subject, err)
+		}
+	}
+}
+
+func (b *Bus) notify(event Event) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, handler := range subscribers {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		handler(ctx, event)
+		cancel()
+	}
+}
+
+func (b *Bus) encode(event Event) (Message, error) {
+	payload, err := Encode(event, b.format)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Topic:   b.topic,
+		Key:     event.Subject,
+		Type:    event.Type,
+		Payload: payload,
+		Headers: map[string]string{
+			"Content-Type":   ContentType(b.format),
+			"Event-Type":     event.Type,
+			"Schema-Version": fmt.Sprint(event.SchemaVersion),
+		},
+	}, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
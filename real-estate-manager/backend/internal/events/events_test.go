@@ -0,0 +1,211 @@
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestEncode(t *testing.T) {
+	event := Event{
+		ID:            "6f1c",
+		Type:          PropertyUpdated,
+		SchemaVersion: SchemaVersion,
+		OccurredAt:    time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Subject:       "property/12",
+		ActorID:       3,
+		Data:          map[string]int{"id": 12},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		payload, err := Encode(event, FormatJSON)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if decoded["type"] != PropertyUpdated || decoded["schema_version"] != float64(1) || decoded["subject"] != "property/12" {
+			t.Errorf("Unexpected event: %s", payload)
+		}
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		payload, err := Encode(event, FormatProtobuf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		strs := map[protowire.Number]string{}
+		ints := map[protowire.Number]uint64{}
+		for len(payload) > 0 {
+			num, typ, n := protowire.ConsumeTag(payload)
+			if n < 0 {
+				t.Fatalf("Invalid tag: %v", protowire.ParseError(n))
+			}
+			payload = payload[n:]
+			switch typ {
+			case protowire.VarintType:
+				v, n := protowire.ConsumeVarint(payload)
+				ints[num], payload = v, payload[n:]
+			case protowire.BytesType:
+				v, n := protowire.ConsumeBytes(payload)
+				strs[num], payload = string(v), payload[n:]
+			default:
+				t.Fatalf("Unexpected wire type %v for field %d", typ, num)
+			}
+		}
+
+		if strs[fieldID] != "6f1c" || strs[fieldType] != PropertyUpdated || strs[fieldSubject] != "property/12" {
+			t.Errorf("Unexpected string fields: %v", strs)
+		}
+		if ints[fieldSchemaVersion] != 1 || ints[fieldActorID] != 3 || ints[fieldOccurredAt] != uint64(event.OccurredAt.UnixMilli()) {
+			t.Errorf("Unexpected numeric fields: %v", ints)
+		}
+		if strs[fieldData] != `{"id":12}` {
+			t.Errorf("Expected JSON data, got %q", strs[fieldData])
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := Encode(event, "avro"); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+}
+
+func TestKafkaRESTTransport_Send(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		expectError bool
+	}{
+		{name: "record produced", status: http.StatusOK, response: `{"offsets": [{"partition": 0, "offset": 7}]}`},
+		{name: "record rejected", status: http.StatusOK, response: `{"offsets": [{"error_code": 40403, "error": "topic not found"}]}`, expectError: true},
+		{name: "proxy error", status: http.StatusInternalServerError, response: `{"message": "down"}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/topics/real-estate.events" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				if r.Header.Get("Content-Type") != "application/vnd.kafka.binary.v2+json" {
+					t.Errorf("Unexpected content type %s", r.Header.Get("Content-Type"))
+				}
+				var body kafkaProduceRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Records) != 1 {
+					t.Fatalf("Unexpected body: %v", err)
+				}
+				key, _ := base64.StdEncoding.DecodeString(body.Records[0].Key)
+				value, _ := base64.StdEncoding.DecodeString(body.Records[0].Value)
+				if string(key) != "property/12" || string(value) != "payload" {
+					t.Errorf("Unexpected record %q=%q", key, value)
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.response)
+			}))
+			defer server.Close()
+
+			transport := NewKafkaRESTTransport(server.Client(), server.URL+"/")
+			err := transport.Send(context.Background(), Message{Topic: "real-estate.events", Key: "property/12", Payload: []byte("payload")})
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+type recordingTransport struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (t *recordingTransport) Send(ctx context.Context, msg Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent = append(t.sent, msg)
+	return nil
+}
+
+func (t *recordingTransport) Close() error {
+	return nil
+}
+
+func TestBus_Publish(t *testing.T) {
+	transport := &recordingTransport{}
+	bus := NewBus(transport, "real-estate.events", FormatProtobuf, 10)
+
+	bus.Publish(context.Background(), New(JobStarted, "job/1", nil))
+	bus.Publish(context.Background(), New(JobCompleted, "job/1", nil))
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(transport.sent) != 2 {
+		t.Fatalf("Expected queued events to be delivered on close, got %d", len(transport.sent))
+	}
+	msg := transport.sent[1]
+	if msg.Topic != "real-estate.events" || msg.Key != "job/1" || msg.Type != JobCompleted {
+		t.Errorf("Unexpected message: %+v", msg)
+	}
+	if msg.Headers["Content-Type"] != "application/x-protobuf" || msg.Headers["Schema-Version"] != "1" {
+		t.Errorf("Unexpected headers: %v", msg.Headers)
+	}
+}
+
+func TestBus_Subscribe(t *testing.T) {
+	transport := &recordingTransport{}
+	for _, bus := range []*Bus{NewBus(transport, "real-estate.events", FormatJSON, 10), NewLocalBus()} {
+		var received []string
+		bus.Subscribe(func(ctx context.Context, event Event) {
+			received = append(received, event.Type)
+		})
+
+		bus.Publish(context.Background(), New(PropertyUpdated, "property/12", nil))
+		bus.Publish(context.Background(), New(JobFailed, "job/1", nil))
+		if err := bus.Close(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(received) != 2 || received[0] != PropertyUpdated || received[1] != JobFailed {
+			t.Errorf("Expected subscriber to receive events in order, got %v", received)
+		}
+	}
+	if len(transport.sent) != 2 {
+		t.Errorf("Expected subscribers not to replace the transport, got %d messages", len(transport.sent))
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("EVENT_BUS", "")
+	if bus, err := NewFromEnv(); bus != nil || err != nil {
+		t.Errorf("Expected no bus when disabled, got %v, %v", bus, err)
+	}
+
+	t.Setenv("EVENT_BUS", "kafka")
+	t.Setenv("KAFKA_REST_URL", "")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("Expected error without KAFKA_REST_URL")
+	}
+
+	t.Setenv("EVENT_BUS", "nats")
+	t.Setenv("EVENT_FORMAT", "xml")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
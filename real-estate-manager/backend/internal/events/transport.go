@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSTransport publishes each event to the subject "<topic>.<event type>",
+// e.g. "real-estate.events.property.updated", so consumers can subscribe
+// with wildcards
+type NATSTransport struct {
+	conn *nats.Conn
+}
+
+func NewNATSTransport(serverURL string) (*NATSTransport, error) {
+	conn, err := nats.Connect(serverURL, nats.Name("real-estate-manager"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return &NATSTransport{conn: conn}, nil
+}
+
+func (t *NATSTransport) Send(ctx context.Context, msg Message) error {
+	natsMsg := nats.NewMsg(msg.Topic + "." + msg.Type)
+	natsMsg.Data = msg.Payload
+	for key, value := range msg.Headers {
+		natsMsg.Header.Set(key, value)
+	}
+	return t.conn.PublishMsg(natsMsg)
+}
+
+func (t *NATSTransport) Close() error {
+	return t.conn.Drain()
+}
+
+// KafkaRESTTransport produces records through a Kafka REST proxy (the
+// Confluent v2 API), keyed by the event subject so a partition sees every
+// event of an entity in order
+type KafkaRESTTransport struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewKafkaRESTTransport(client *http.Client, baseURL string) *KafkaRESTTransport {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KafkaRESTTransport{client: client, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (t *KafkaRESTTransport) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{{
+		Key:   base64.StdEncoding.EncodeToString([]byte(msg.Key)),
+		Value: base64.StdEncoding.EncodeToString(msg.Payload),
+	}}})
+	if err != nil {
+		return err
+	}
+
+	endpoint := t.baseURL + "/topics/" + url.PathEscape(msg.Topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.binary.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka REST proxy returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&produced); err != nil {
+		return fmt.Errorf("failed to decode kafka REST proxy response: %w", err)
+	}
+	for _, offset := range produced.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka REST proxy rejected record: %s", offset.Error)
+		}
+	}
+	return nil
+}
+
+func (t *KafkaRESTTransport) Close() error {
+	return nil
+}
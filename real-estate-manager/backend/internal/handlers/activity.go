@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityHandler struct {
+	service *services.ActivityService
+}
+
+func NewActivityHandler(service *services.ActivityService) *ActivityHandler {
+	return &ActivityHandler{service: service}
+}
+
+// GetActivity returns the activity feed, newest first: the caller's own
+// with ?scope=me (the default) or their organization's with ?scope=org.
+// ?type= takes comma-separated event types or families such as "offer",
+// ?since= (RFC 3339) skips older activity, ?before= takes the cursor of the
+// previous page and ?limit= the page size.
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	var query struct {
+		Scope    string     `form:"scope"`
+		Types    string     `form:"type"`
+		Since    *time.Time `form:"since"`
+		BeforeID int64      `form:"before" binding:"min=0"`
+		Limit    int        `form:"limit,default=50" binding:"min=1,max=200"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	activityQuery := models.ActivityQuery{BeforeID: query.BeforeID, Limit: query.Limit}
+	if query.Types != "" {
+		for _, eventType := range strings.Split(query.Types, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				activityQuery.Types = append(activityQuery.Types, eventType)
+			}
+		}
+	}
+	if query.Since != nil {
+		activityQuery.Since = *query.Since
+	}
+
+	page, err := h.service.List(c.Request.Context(), userID, query.Scope, activityQuery)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.Page(c, page, page.Activities, envelope.Meta{
+		"pagination": envelope.Pagination{Limit: query.Limit, Cursor: page.Cursor, HasMore: page.HasMore},
+	})
+}
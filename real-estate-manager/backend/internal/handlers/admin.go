@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	featureFlags *services.FeatureFlagService
+	settings     *services.SettingsService
+	storage      *services.StorageService
+	reloader     *services.ReloadService
+	ipAccess     *services.IPAccessService
+}
+
+func NewAdminHandler(featureFlags *services.FeatureFlagService, settings *services.SettingsService, storage *services.StorageService, reloader *services.ReloadService, ipAccess *services.IPAccessService) *AdminHandler {
+	return &AdminHandler{
+		featureFlags: featureFlags,
+		settings:     settings,
+		storage:      storage,
+		reloader:     reloader,
+		ipAccess:     ipAccess,
+	}
+}
+
+// GetOverview summarizes system state for administrators, including storage
+// usage per organization and user against their quotas
+func (h *AdminHandler) GetOverview(c *gin.Context) {
+	usage, err := h.storage.Usage(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var totalBytes int64
+	var totalFiles int64
+	for _, u := range usage {
+		// Organization rows repeat their members' files; count users only
+		if u.OwnerType == "user" {
+			totalBytes += u.UsedBytes
+			totalFiles += u.Files
+		}
+	}
+
+	envelope.JSON(c, http.StatusOK, gin.H{
+		"storage": gin.H{
+			"total_bytes": totalBytes,
+			"total_files": totalFiles,
+			"usage":       usage,
+		},
+		"feature_flags": h.featureFlags.List(),
+	})
+}
+
+// GetFeatureFlags lists all feature flags and their current state
+func (h *AdminHandler) GetFeatureFlags(c *gin.Context) {
+	envelope.JSON(c, http.StatusOK, h.featureFlags.List())
+}
+
+// UpdateFeatureFlag toggles a feature flag at runtime
+func (h *AdminHandler) UpdateFeatureFlag(c *gin.Context) {
+	var request struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || request.Enabled == nil {
+		envelope.Error(c, http.StatusBadRequest, "enabled is required")
+		return
+	}
+
+	flag, err := h.featureFlags.SetFlag(c.Request.Context(), c.Param("name"), *request.Enabled, c.GetString("username"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, flag)
+}
+
+// GetSettings lists runtime settings and their current values
+func (h *AdminHandler) GetSettings(c *gin.Context) {
+	envelope.JSON(c, http.StatusOK, h.settings.List())
+}
+
+// UpdateSettings changes one or more runtime settings, e.g.
+// {"import_batch_size": "20", "job_retention": "30m"}
+func (h *AdminHandler) UpdateSettings(c *gin.Context) {
+	var updates map[string]string
+	if err := c.ShouldBindJSON(&updates); err != nil || len(updates) == 0 {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+	// An admin cannot lock themselves out with the IP rules
+	if err := h.ipAccess.CheckUpdate(updates, c.ClientIP()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	settings, err := h.settings.Update(c.Request.Context(), updates, c.GetString("username"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, settings)
+}
+
+// Reload re-reads the log level, CORS origins, feature flags and settings
+// without a restart, like sending the server SIGHUP. Parts that fail to
+// reload keep their previous values and are reported with their error.
+func (h *AdminHandler) Reload(c *gin.Context) {
+	envelope.JSON(c, http.StatusOK, gin.H{"reloaded": h.reloader.Reload(c.Request.Context())})
+}
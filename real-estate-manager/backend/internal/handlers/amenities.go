@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AmenityHandler struct {
+	service *services.AmenityService
+}
+
+func NewAmenityHandler(service *services.AmenityService) *AmenityHandler {
+	return &AmenityHandler{service: service}
+}
+
+// GetAmenities returns the structured amenities of a property
+func (h *AmenityHandler) GetAmenities(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	amenities, err := h.service.GetAmenities(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, amenities)
+}
+
+// UpdateAmenities replaces the structured amenities of a property
+func (h *AmenityHandler) UpdateAmenities(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var amenities models.Amenities
+	if err := c.ShouldBindJSON(&amenities); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	amenities.PropertyID = id
+	if err := h.service.UpdateAmenities(c.Request.Context(), &amenities); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.service.GetAmenities(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, updated)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveHandler struct {
+	service *services.ArchiveService
+}
+
+func NewArchiveHandler(service *services.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{service: service}
+}
+
+// GetArchived lists archived listings, most recently archived first, up to
+// ?limit= (default 50, max 500)
+func (h *ArchiveHandler) GetArchived(c *gin.Context) {
+	var query struct {
+		Limit int `form:"limit"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	archives, err := h.service.List(c.Request.Context(), query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, archives)
+}
+
+// Unarchive restores an archived listing, by ID or public ID, and returns
+// it
+func (h *ArchiveHandler) Unarchive(c *gin.Context) {
+	property, err := h.service.Unarchive(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, property)
+}
+
+// RunArchive archives the listings due for it now, rather than waiting
+// for the daily run
+func (h *ArchiveHandler) RunArchive(c *gin.Context) {
+	run, err := h.service.ArchiveDue(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, run)
+}
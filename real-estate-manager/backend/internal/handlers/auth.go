@@ -1,67 +1,149 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"log"
 	"net/http"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
 	"real-estate-manager/backend/internal/models"
-	"real-estate-manager/backend/internal/repository"
 	"real-estate-manager/backend/internal/services"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
 	authService *services.AuthService
+	audit       *services.AuditService
 }
 
-func NewAuthHandler(userRepo repository.UserRepository) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, audit *services.AuditService) *AuthHandler {
 	return &AuthHandler{
-		authService: services.NewAuthService(userRepo),
+		authService: authService,
+		audit:       audit,
 	}
 }
 
+// Login exchanges a username and password for a token. Every attempt is
+// audited with the username and client IP.
 func (h *AuthHandler) Login(c *gin.Context) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
 		return
 	}
 
-	token, err := h.authService.Login(user.Username, user.Password)
+	token, err := h.authService.Login(c.Request.Context(), user.Username, user.Password)
+	h.auditLogin(c, user.Username, err)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	envelope.JSON(c, http.StatusOK, gin.H{"token": token})
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
 		return
 	}
 
-	if err := h.authService.Register(user); err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	if err := h.authService.Register(c.Request.Context(), user); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully"})
+	envelope.JSON(c, http.StatusCreated, gin.H{"message": "User registered successfully"})
 }
 
 func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	tokenString := c.Request.Header.Get("Authorization")
 	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No token provided"})
+		envelope.Error(c, http.StatusUnauthorized, "No token provided")
 		return
 	}
 
 	_, err := h.authService.ValidateToken(tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Token is valid"})
-}
\ No newline at end of file
+	envelope.JSON(c, http.StatusOK, gin.H{"message": "Token is valid"})
+}
+
+// Logout revokes the caller's token, which is refused from then on
+func (h *AuthHandler) Logout(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	revoked, err := h.authService.RevokeToken(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.auditRevocation(c, revoked, "logout")
+
+	envelope.JSON(c, http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+type revokeTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RevokeToken lets an admin kill another user's token before it expires,
+// e.g. one that leaked
+func (h *AuthHandler) RevokeToken(c *gin.Context) {
+	var req revokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	revoked, err := h.authService.RevokeToken(c.Request.Context(), strings.TrimPrefix(req.Token, "Bearer "))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.auditRevocation(c, revoked, "admin")
+
+	envelope.JSON(c, http.StatusOK, gin.H{"message": "Token revoked", "expires_at": revoked.ExpiresAt})
+}
+
+// auditRevocation records a revoked token against its owner. The token
+// itself is not logged.
+func (h *AuthHandler) auditRevocation(c *gin.Context, revoked *models.RevokedToken, reason string) {
+	entry := &models.AuditEntry{Action: models.AuditTokenRevoked, ActorID: revoked.RevokedBy}
+	if revoked.UserID.Valid {
+		entry.TargetType.String, entry.TargetType.Valid = "user", true
+		entry.TargetID.String, entry.TargetID.Valid = strconv.Itoa(int(revoked.UserID.Int32)), true
+	}
+	entry.Details, _ = json.Marshal(map[string]interface{}{"reason": reason, "expires_at": revoked.ExpiresAt})
+	if requestID := middleware.GetRequestID(c); requestID != "" {
+		entry.RequestID.String, entry.RequestID.Valid = requestID, true
+	}
+	if err := h.audit.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+		log.Printf("Failed to audit token revocation: %v", err)
+	}
+}
+// auditLogin records a login attempt. The username is the target since a
+// failed attempt may not match any user.
+func (h *AuthHandler) auditLogin(c *gin.Context, username string, loginErr error) {
+	entry := &models.AuditEntry{Action: models.AuditLoginSucceeded}
+	if loginErr != nil {
+		entry.Action = models.AuditLoginFailed
+	}
+	entry.TargetType.String, entry.TargetType.Valid = "user", true
+	entry.TargetID.String, entry.TargetID.Valid = username, true
+	entry.Details, _ = json.Marshal(map[string]string{"client_ip": c.ClientIP(), "user_agent": c.Request.UserAgent()})
+	if requestID := middleware.GetRequestID(c); requestID != "" {
+		entry.RequestID.String, entry.RequestID.Valid = requestID, true
+	}
+	// Attempts are audited even when the client has gone away
+	if err := h.audit.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+		log.Printf("Failed to audit login of %q: %v", username, err)
+	}
+}
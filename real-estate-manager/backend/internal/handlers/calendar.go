@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	service *services.CalendarService
+}
+
+func NewCalendarHandler(service *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// GetConnections lists the configured calendar providers and the ones the
+// caller connected
+func (h *CalendarHandler) GetConnections(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	connections, err := h.service.Connections(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"providers": h.service.Providers(), "connections": connections})
+}
+
+// Connect returns the provider URL where the caller grants calendar access
+func (h *CalendarHandler) Connect(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	url, err := h.service.AuthURL(userID, c.Param("provider"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"url": url})
+}
+
+// Callback is where the provider redirects after the user granted or denied
+// access. It is public; the signed state identifies the user.
+func (h *CalendarHandler) Callback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		envelope.Errorf(c, http.StatusBadRequest, "Calendar access was not granted: %s", reason)
+		return
+	}
+
+	provider := c.Param("provider")
+	if _, err := h.service.Connect(c.Request.Context(), provider, c.Query("code"), c.Query("state")); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"connected": provider})
+}
+
+// Disconnect forgets the caller's connection to a calendar provider
+func (h *CalendarHandler) Disconnect(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	if err := h.service.Disconnect(c.Request.Context(), userID, c.Param("provider")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChangeHandler struct {
+	changes *services.ChangeService
+}
+
+func NewChangeHandler(changes *services.ChangeService) *ChangeHandler {
+	return &ChangeHandler{changes: changes}
+}
+
+// GetChanges returns entities modified after ?cursor= (from a previous
+// response) or since ?since= (RFC 3339), with tombstones for deletions.
+// Without either the feed starts from the beginning, for a full sync, or
+// from the stored sync token of ?client_id= when the device has synced
+// before.
+func (h *ChangeHandler) GetChanges(c *gin.Context) {
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+
+	var query struct {
+		Cursor   string     `form:"cursor"`
+		Since    *time.Time `form:"since"`
+		Limit    int        `form:"limit,default=500" binding:"min=1,max=1000"`
+		ClientID string     `form:"client_id"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	page, err := h.changes.List(c.Request.Context(), services.ChangesQuery(query))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	for _, change := range page.Changes {
+		if property, ok := change.Data.(*models.Property); ok {
+			presentProperty(c, system, property)
+		}
+	}
+	envelope.Page(c, page, page.Changes, envelope.Meta{
+		"pagination": envelope.Pagination{Limit: query.Limit, Cursor: page.Cursor, HasMore: page.HasMore},
+	})
+}
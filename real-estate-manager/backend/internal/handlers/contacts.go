@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContactHandler struct {
+	service *services.ContactService
+}
+
+func NewContactHandler(service *services.ContactService) *ContactHandler {
+	return &ContactHandler{service: service}
+}
+
+// GetContacts lists contacts, filtered by ?kind=, ?tag=, ?q= and the
+// entity they are linked to with ?entity_type= and ?entity_id=
+func (h *ContactHandler) GetContacts(c *gin.Context) {
+	var filter models.ContactFilter
+	if !bindQuery(c, &filter) {
+		return
+	}
+
+	contacts, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, contacts)
+}
+
+// GetContact returns a contact with its links
+func (h *ContactHandler) GetContact(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+
+	contact, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, contact)
+}
+
+func (h *ContactHandler) CreateContact(c *gin.Context) {
+	var contact models.Contact
+	if err := c.ShouldBindJSON(&contact); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Create(c.Request.Context(), &contact); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, contact)
+}
+
+// UpdateContact replaces a contact's details and tags
+func (h *ContactHandler) UpdateContact(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+
+	var changes models.Contact
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	contact, err := h.service.Update(c.Request.Context(), id, &changes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, contact)
+}
+
+func (h *ContactHandler) DeleteContact(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LinkContact links a contact to a lead, showing or deal
+func (h *ContactHandler) LinkContact(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+
+	var link models.ContactLink
+	if err := c.ShouldBindJSON(&link); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Link(c.Request.Context(), id, &link); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, link)
+}
+
+// UnlinkContact removes the link from a contact to /:type/:entityId
+func (h *ContactHandler) UnlinkContact(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+	entityID, err := strconv.Atoi(c.Param("entityId"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid entity ID")
+		return
+	}
+
+	link := models.ContactLink{ContactID: id, EntityType: c.Param("type"), EntityID: entityID}
+	if err := h.service.Unlink(c.Request.Context(), link); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CRMHandler struct {
+	service *services.CRMService
+}
+
+func NewCRMHandler(service *services.CRMService) *CRMHandler {
+	return &CRMHandler{service: service}
+}
+
+// GetCRM returns the configured CRM, the field mapping in effect and how
+// many records are synced, failed or pending
+func (h *CRMHandler) GetCRM(c *gin.Context) {
+	summary, err := h.service.Summary(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, summary)
+}
+
+// GetRecords lists the sync status of exported records, filtered by ?type=
+// and ?status= and up to ?limit=
+func (h *CRMHandler) GetRecords(c *gin.Context) {
+	var query struct {
+		Type   string `form:"type"`
+		Status string `form:"status"`
+		Limit  int    `form:"limit,default=50" binding:"min=1,max=200"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	records, err := h.service.Records(c.Request.Context(), query.Type, query.Status, query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.List(c, records, envelope.Pagination{Limit: query.Limit})
+}
+
+// Sync exports a batch of due leads right away instead of waiting for the
+// schedule
+func (h *CRMHandler) Sync(c *gin.Context) {
+	synced, err := h.service.SyncDue(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"synced": synced})
+}
+
+// Retry lets failed records that ran out of attempts be tried again
+func (h *CRMHandler) Retry(c *gin.Context) {
+	count, err := h.service.Retry(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"retrying": count})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataQualityHandler struct {
+	service *services.DataQualityService
+}
+
+func NewDataQualityHandler(service *services.DataQualityService) *DataQualityHandler {
+	return &DataQualityHandler{service: service}
+}
+
+// GetReports lists the latest data-quality reports, newest first, up to
+// ?limit= (default 10, max 100)
+func (h *DataQualityHandler) GetReports(c *gin.Context) {
+	var query struct {
+		Limit int `form:"limit"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	reports, err := h.service.List(c.Request.Context(), query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, reports)
+}
+
+// RunChecks runs the data-quality checks now, rather than waiting for the
+// daily run, and returns the report
+func (h *DataQualityHandler) RunChecks(c *gin.Context) {
+	report, err := h.service.Run(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusCreated, report)
+}
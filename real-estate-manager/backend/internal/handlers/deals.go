@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DealHandler struct {
+	service       *services.DealService
+	notifications *services.NotificationService
+}
+
+// NewDealHandler creates the handler; notifications resolves the caller's
+// time zone for reports
+func NewDealHandler(service *services.DealService, notifications *services.NotificationService) *DealHandler {
+	return &DealHandler{service: service, notifications: notifications}
+}
+
+// GetDeals lists deals, filtered by ?stage=, ?agent_id= and ?property_id=
+func (h *DealHandler) GetDeals(c *gin.Context) {
+	var filter models.DealFilter
+	if !bindQuery(c, &filter) {
+		return
+	}
+
+	deals, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, deals)
+}
+
+func (h *DealHandler) GetDeal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid deal ID")
+		return
+	}
+
+	deal, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, deal)
+}
+
+func (h *DealHandler) CreateDeal(c *gin.Context) {
+	var deal models.Deal
+	if err := c.ShouldBindJSON(&deal); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Create(c.Request.Context(), &deal); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, deal)
+}
+
+// UpdateDeal replaces a deal's stage, terms and commission splits
+func (h *DealHandler) UpdateDeal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid deal ID")
+		return
+	}
+
+	var changes models.Deal
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	deal, err := h.service.Update(c.Request.Context(), id, &changes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, deal)
+}
+
+func (h *DealHandler) DeleteDeal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid deal ID")
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetPipeline totals deals by stage, optionally for one ?agent_id=
+func (h *DealHandler) GetPipeline(c *gin.Context) {
+	var query struct {
+		AgentID uint `form:"agent_id"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	stages, err := h.service.Pipeline(c.Request.Context(), query.AgentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, stages)
+}
+
+// GetRevenueReport reports commission for deals closed between ?from= and
+// ?to=, dates in ?tz= or the caller's time zone
+func (h *DealHandler) GetRevenueReport(c *gin.Context) {
+	var query struct {
+		From     string `form:"from"`
+		To       string `form:"to"`
+		Timezone string `form:"tz"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+	location, ok := reportLocation(c, h.notifications, query.Timezone)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.RevenueReport(c.Request.Context(), query.From, query.To, location)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, report)
+}
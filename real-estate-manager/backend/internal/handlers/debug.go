@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebugHandler struct {
+	runtime *services.RuntimeService
+}
+
+func NewDebugHandler(runtime *services.RuntimeService) *DebugHandler {
+	return &DebugHandler{runtime: runtime}
+}
+
+// GetStats reports goroutines, heap, database pool and worker pool usage
+func (h *DebugHandler) GetStats(c *gin.Context) {
+	envelope.JSON(c, http.StatusOK, h.runtime.Stats())
+}
+
+// Profile serves net/http/pprof under /debug/pprof/, e.g.
+// `go tool pprof https://host/debug/pprof/profile?seconds=30`
+func (h *DebugHandler) Profile(c *gin.Context) {
+	switch c.Param("profile") {
+	case "/cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "/profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "/symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "/trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// The index and the named profiles: heap, goroutine, allocs, ...
+		pprof.Index(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailSuppressionHandler struct {
+	service *services.EmailSuppressionService
+}
+
+func NewEmailSuppressionHandler(service *services.EmailSuppressionService) *EmailSuppressionHandler {
+	return &EmailSuppressionHandler{service: service}
+}
+
+// GetSuppressions lists addresses that no longer receive email
+func (h *EmailSuppressionHandler) GetSuppressions(c *gin.Context) {
+	suppressions, err := h.service.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, suppressions)
+}
+
+// DeleteSuppression allows email to be sent to an address again
+func (h *EmailSuppressionHandler) DeleteSuppression(c *gin.Context) {
+	if err := h.service.Remove(c.Request.Context(), c.Param("email")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
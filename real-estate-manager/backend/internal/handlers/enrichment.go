@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EnrichmentHandler struct {
+	service *services.EnrichmentService
+}
+
+func NewEnrichmentHandler(service *services.EnrichmentService) *EnrichmentHandler {
+	return &EnrichmentHandler{service: service}
+}
+
+// GetEnrichment returns the school district, walk score and neighborhood
+// attached to a property
+func (h *EnrichmentHandler) GetEnrichment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	enrichment, err := h.service.GetEnrichment(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, enrichment)
+}
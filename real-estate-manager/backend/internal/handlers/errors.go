@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest is logged for requests abandoned by the client
+// before a response was written, as nginx does
+const statusClientClosedRequest = 499
+
+// respondError writes a service error using the shared status mapping.
+// Unexpected errors are logged and hidden behind a generic message.
+func respondError(c *gin.Context, err error) {
+	// Nobody is left to read a response to an abandoned request
+	if errors.Is(c.Request.Context().Err(), context.Canceled) {
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+	// Whatever failed, it failed because the request ran out of time
+	if middleware.TimedOut(c) {
+		middleware.RespondTimeout(c)
+		return
+	}
+	status := apperrors.HTTPStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+		envelope.Error(c, status, "Internal server error")
+		return
+	}
+	format, args := apperrors.Format(err)
+	envelope.Errorf(c, status, format, args...)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteHandler struct {
+	service *services.FavoriteService
+}
+
+func NewFavoriteHandler(service *services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{service: service}
+}
+
+// GetFavorites lists the caller's favorite properties
+func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+	var query photoExpansion
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	favorites, err := h.service.Favorites(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range favorites {
+		presentProperty(c, system, &favorites[i].Property)
+		query.apply(&favorites[i].Property)
+	}
+	envelope.JSON(c, http.StatusOK, favorites)
+}
+
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	if err := h.service.AddFavorite(c.Request.Context(), userID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	if err := h.service.RemoveFavorite(c.Request.Context(), userID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSavedSearches lists the caller's saved searches
+func (h *FavoriteHandler) GetSavedSearches(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	searches, err := h.service.SavedSearches(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, searches)
+}
+
+func (h *FavoriteHandler) CreateSavedSearch(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	var search models.SavedSearch
+	if err := c.ShouldBindJSON(&search); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.SaveSearch(c.Request.Context(), userID, &search); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, search)
+}
+
+func (h *FavoriteHandler) DeleteSavedSearch(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid saved search ID")
+		return
+	}
+
+	if err := h.service.DeleteSavedSearch(c.Request.Context(), userID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FileScanHandler struct {
+	service *services.VirusScanService
+}
+
+func NewFileScanHandler(service *services.VirusScanService) *FileScanHandler {
+	return &FileScanHandler{service: service}
+}
+
+// GetScans lists recent virus scan verdicts, filtered by ?status=
+func (h *FileScanHandler) GetScans(c *gin.Context) {
+	var query struct {
+		Status string `form:"status"`
+		Limit  int    `form:"limit,default=50" binding:"min=1,max=500"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	scans, err := h.service.List(c.Request.Context(), query.Status, query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.List(c, scans, envelope.Pagination{Limit: query.Limit})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FlyerHandler struct {
+	service *services.FlyerService
+}
+
+func NewFlyerHandler(service *services.FlyerService) *FlyerHandler {
+	return &FlyerHandler{service: service}
+}
+
+// GetFlyer serves the PDF flyer of a property, with measurements in the
+// ?units= system. While the flyer is being generated it responds 202 with
+// Retry-After; clients poll until the PDF is ready.
+func (h *FlyerHandler) GetFlyer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+
+	flyer, err := h.service.Flyer(c.Request.Context(), id, system)
+	if errors.Is(err, services.ErrFlyerPending) {
+		c.Header("Retry-After", "2")
+		envelope.JSON(c, http.StatusAccepted, gin.H{"status": "generating"})
+		return
+	}
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUnavailable) {
+			c.Header("Retry-After", "5")
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("ETag", flyer.ETag)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="listing-%d.pdf"`, id))
+	c.Header("Content-Type", "application/pdf")
+	c.File(flyer.Path)
+}
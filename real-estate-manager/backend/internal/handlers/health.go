@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds a probe so a hung dependency reports as not ready
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	readiness *services.ReadinessService
+	health    *services.HealthService
+}
+
+func NewHealthHandler(readiness *services.ReadinessService, health *services.HealthService) *HealthHandler {
+	return &HealthHandler{readiness: readiness, health: health}
+}
+
+// Ready is the readiness probe: 200 when the server can handle requests,
+// 503 otherwise
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := h.readiness.Check(ctx); err != nil {
+		log.Printf("Readiness check failed: %v", err)
+		envelope.JSON(c, http.StatusServiceUnavailable, gin.H{"status": "unavailable"})
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"status": "ready"})
+}
+
+// Details reports every dependency's status, check latency and when it was
+// last checked, with 503 while a critical dependency is down
+func (h *HealthHandler) Details(c *gin.Context) {
+	report := h.health.Details(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == services.HealthDown {
+		status = http.StatusServiceUnavailable
+	}
+	envelope.JSON(c, status, report)
+}
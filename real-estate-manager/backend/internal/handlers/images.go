@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/pkg/imagestore"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageHandler struct {
+	images imagestore.Storage
+}
+
+func NewImageHandler(images imagestore.Storage) *ImageHandler {
+	return &ImageHandler{images: images}
+}
+
+// GetImage serves a stored photo under /images, from local disk or the
+// bucket the photos are kept in
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	name := path.Base(c.Param("name"))
+	content, err := h.images.Get(c.Request.Context(), name)
+	if errors.Is(err, imagestore.ErrNotFound) {
+		envelope.Error(c, http.StatusNotFound, "Image not found")
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	// Local files support ranges and If-Modified-Since
+	if file, ok := content.(*os.File); ok {
+		if info, err := file.Stat(); err == nil {
+			http.ServeContent(c.Writer, c.Request, name, info.ModTime(), file)
+			return
+		}
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if c.Request.Method != http.MethodHead {
+		io.Copy(c.Writer, content)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	impersonation *services.ImpersonationService
+	audit         *services.AuditService
+}
+
+func NewImpersonationHandler(impersonation *services.ImpersonationService, audit *services.AuditService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonation: impersonation, audit: audit}
+}
+
+// Impersonate issues a short-lived token to act as another user. An
+// optional {"reason": "..."} body is stored in the audit log.
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	targetID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	// Impersonation tokens must not be able to start another impersonation
+	if _, impersonating := middleware.ImpersonatorID(c); impersonating {
+		envelope.Error(c, http.StatusForbidden, "Already impersonating a user")
+		return
+	}
+	adminID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			envelope.Error(c, http.StatusBadRequest, "Invalid input")
+			return
+		}
+	}
+
+	token, err := h.impersonation.Impersonate(c.Request.Context(), adminID, uint(targetID), request.Reason, middleware.GetRequestID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, token)
+}
+
+// GetAuditLog lists audit entries, newest first. Supports ?actor_id=,
+// ?impersonator_id=, ?action= and ?limit= (default 100, max 1000).
+func (h *ImpersonationHandler) GetAuditLog(c *gin.Context) {
+	var filter models.AuditFilter
+	if !bindQuery(c, &filter) {
+		return
+	}
+
+	entries, err := h.audit.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.List(c, entries, envelope.Pagination{Limit: filter.Limit})
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InspectionHandler struct {
+	service *services.InspectionService
+}
+
+func NewInspectionHandler(service *services.InspectionService) *InspectionHandler {
+	return &InspectionHandler{service: service}
+}
+
+// GetInspections lists the property's inspections in schedule order
+func (h *InspectionHandler) GetInspections(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	inspections, err := h.service.List(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, inspections)
+}
+
+// CreateInspection schedules an inspection of the property
+func (h *InspectionHandler) CreateInspection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var inspection models.Inspection
+	if err := c.ShouldBindJSON(&inspection); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Create(c.Request.Context(), id, &inspection); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, inspection)
+}
+
+// GetPunchList lists the property's repairs, filtered by ?status= and
+// ?assignee_id=
+func (h *InspectionHandler) GetPunchList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+	var filter models.RepairFilter
+	if !bindQuery(c, &filter) {
+		return
+	}
+
+	list, err := h.service.PunchList(c.Request.Context(), id, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, list)
+}
+
+func (h *InspectionHandler) GetInspection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid inspection ID")
+		return
+	}
+
+	inspection, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, inspection)
+}
+
+// UpdateInspection replaces an inspection's schedule, kind, inspector,
+// status and notes
+func (h *InspectionHandler) UpdateInspection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid inspection ID")
+		return
+	}
+
+	var changes models.Inspection
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	inspection, err := h.service.Update(c.Request.Context(), id, &changes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, inspection)
+}
+
+func (h *InspectionHandler) DeleteInspection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid inspection ID")
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AttachReport attaches a confirmed document upload to an inspection
+func (h *InspectionHandler) AttachReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid inspection ID")
+		return
+	}
+
+	var req struct {
+		UploadID string `json:"upload_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	inspection, err := h.service.AttachReport(c.Request.Context(), id, req.UploadID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, inspection)
+}
+
+func (h *InspectionHandler) DetachReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid inspection ID")
+		return
+	}
+
+	if err := h.service.DetachReport(c.Request.Context(), id, c.Param("uploadId")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateRepair records a repair the inspection called for
+func (h *InspectionHandler) CreateRepair(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid inspection ID")
+		return
+	}
+
+	var repair models.RepairItem
+	if err := c.ShouldBindJSON(&repair); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.AddRepair(c.Request.Context(), id, &repair); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, repair)
+}
+
+// UpdateRepair replaces a repair's description, status, assignee and due
+// date
+func (h *InspectionHandler) UpdateRepair(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid repair ID")
+		return
+	}
+
+	var changes models.RepairItem
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	repair, err := h.service.UpdateRepair(c.Request.Context(), id, &changes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, repair)
+}
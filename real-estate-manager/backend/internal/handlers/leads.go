@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLeadPayload caps the size of a lead webhook body
+const maxLeadPayload = 1 << 20
+
+type LeadHandler struct {
+	service *services.LeadService
+}
+
+func NewLeadHandler(service *services.LeadService) *LeadHandler {
+	return &LeadHandler{service: service}
+}
+
+// IngestLead receives a lead webhook from :source. The body must be signed
+// with the source's secret in X-Signature-256 (Facebook's
+// X-Hub-Signature-256 is accepted as well). A new lead is answered with
+// 201, a redelivered one with 200.
+func (h *LeadHandler) IngestLead(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxLeadPayload))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		envelope.Error(c, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	signature := c.GetHeader("X-Signature-256")
+	if signature == "" {
+		signature = c.GetHeader("X-Hub-Signature-256")
+	}
+	lead, created, err := h.service.Ingest(c.Request.Context(), c.Param("source"), body, signature)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	envelope.JSON(c, status, gin.H{"id": lead.ID, "assigned_to": lead.AssignedTo})
+}
+
+// GetLeads returns the newest leads assigned to the caller, up to ?limit=
+func (h *LeadHandler) GetLeads(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	var query struct {
+		Limit int `form:"limit,default=50" binding:"min=1,max=200"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	leads, err := h.service.ListAssigned(c.Request.Context(), userID, query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.List(c, leads, envelope.Pagination{Limit: query.Limit})
+}
+
+// GetRoutingRules lists the lead routing rules in the order they are tried
+func (h *LeadHandler) GetRoutingRules(c *gin.Context) {
+	rules, err := h.service.ListRoutingRules(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, rules)
+}
+
+// CreateRoutingRule adds a lead routing rule
+func (h *LeadHandler) CreateRoutingRule(c *gin.Context) {
+	var rule models.LeadRoutingRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.CreateRoutingRule(c.Request.Context(), &rule); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusCreated, rule)
+}
+
+// DeleteRoutingRule removes a lead routing rule
+func (h *LeadHandler) DeleteRoutingRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	if err := h.service.DeleteRoutingRule(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
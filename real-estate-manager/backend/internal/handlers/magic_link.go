@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MagicLinkHandler struct {
+	service *services.MagicLinkService
+}
+
+func NewMagicLinkHandler(service *services.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{service: service}
+}
+
+// RequestLink emails a single-use login link. The response is the same
+// whether or not the email belongs to an account.
+func (h *MagicLinkHandler) RequestLink(c *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	if err := h.service.RequestLink(c.Request.Context(), request.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusAccepted, gin.H{"message": "If an account exists for this email, a login link has been sent"})
+}
+
+// Callback exchanges the ?token= from a login link for a JWT
+func (h *MagicLinkHandler) Callback(c *gin.Context) {
+	token, err := h.service.Exchange(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, gin.H{"token": token})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MarketHandler struct {
+	service       *services.MarketService
+	notifications *services.NotificationService
+}
+
+// NewMarketHandler creates the handler; notifications resolves the
+// caller's time zone for reports
+func NewMarketHandler(service *services.MarketService, notifications *services.NotificationService) *MarketHandler {
+	return &MarketHandler{service: service, notifications: notifications}
+}
+
+// GetMarketReport returns the monthly market history of a city or ZIP code
+// given as ?area=, for the last ?months= up to the current month in ?tz= or
+// the caller's time zone
+func (h *MarketHandler) GetMarketReport(c *gin.Context) {
+	var query struct {
+		Area     string `form:"area"`
+		Months   int    `form:"months,default=12" binding:"min=1,max=24"`
+		Timezone string `form:"tz"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+	location, ok := reportLocation(c, h.notifications, query.Timezone)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.Report(c.Request.Context(), query.Area, query.Months, location)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, report)
+}
+
+// GetInventoryTrend returns a city's month-end inventory by status, given
+// as ?city=, for the last ?months= up to the current month in ?tz= or the
+// caller's time zone
+func (h *MarketHandler) GetInventoryTrend(c *gin.Context) {
+	var query struct {
+		City     string `form:"city"`
+		Months   int    `form:"months,default=12" binding:"min=1,max=60"`
+		Timezone string `form:"tz"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+	location, ok := reportLocation(c, h.notifications, query.Timezone)
+	if !ok {
+		return
+	}
+
+	trend, err := h.service.Inventory(c.Request.Context(), query.City, query.Months, location)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, trend)
+}
+
+// Refresh rebuilds the market statistics right away instead of waiting for
+// the schedule
+func (h *MarketHandler) Refresh(c *gin.Context) {
+	count, err := h.service.Refresh(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"refreshed": count})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	service *services.NotificationService
+	center  *services.NotificationCenterService
+}
+
+func NewNotificationHandler(service *services.NotificationService, center *services.NotificationCenterService) *NotificationHandler {
+	return &NotificationHandler{service: service, center: center}
+}
+
+// GetNotifications returns the caller's notifications, newest first, with
+// their unread count. ?before= takes the cursor of the previous page,
+// ?limit= the page size and ?unread=true skips read notifications.
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	var query struct {
+		BeforeID   int  `form:"before" binding:"min=0"`
+		Limit      int  `form:"limit,default=20" binding:"min=1,max=100"`
+		UnreadOnly bool `form:"unread"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	page, err := h.center.List(c.Request.Context(), userID, models.NotificationQuery(query))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.Page(c, page, page.Notifications, envelope.Meta{
+		"pagination":   envelope.Pagination{Limit: query.Limit, Cursor: page.Cursor, HasMore: page.HasMore},
+		"unread_count": page.UnreadCount,
+	})
+}
+
+// MarkRead marks one of the caller's notifications as read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	if err := h.center.MarkRead(c.Request.Context(), userID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllRead marks all of the caller's notifications as read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	count, err := h.center.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, gin.H{"marked_read": count})
+}
+
+// GetPreferences returns the caller's text-message preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, prefs)
+}
+
+// UpdatePreferences replaces the caller's text-message preferences, e.g.
+// {"phone": "+15551234567", "sms_opt_in": true, "channel": "whatsapp",
+// "quiet_start": "21:00", "quiet_end": "08:00", "timezone": "America/Chicago"}
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	var prefs models.NotificationPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.UpdatePreferences(c.Request.Context(), userID, &prefs); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, prefs)
+}
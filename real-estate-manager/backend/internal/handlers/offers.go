@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OfferHandler struct {
+	service *services.OfferService
+}
+
+func NewOfferHandler(service *services.OfferService) *OfferHandler {
+	return &OfferHandler{service: service}
+}
+
+// GetOfferDashboard lists the offers on the property with their totals
+func (h *OfferHandler) GetOfferDashboard(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	dashboard, err := h.service.Dashboard(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, dashboard)
+}
+
+// CreateOffer submits an offer on the property
+func (h *OfferHandler) CreateOffer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var offer models.Offer
+	if err := c.ShouldBindJSON(&offer); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Create(c.Request.Context(), id, &offer); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, offer)
+}
+
+func (h *OfferHandler) GetOffer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid offer ID")
+		return
+	}
+
+	offer, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, offer)
+}
+
+// TransitionOffer counters, accepts or rejects an offer, or submits a new
+// amount after a counter
+func (h *OfferHandler) TransitionOffer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid offer ID")
+		return
+	}
+
+	var transition models.OfferTransition
+	if err := c.ShouldBindJSON(&transition); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	offer, err := h.service.Transition(c.Request.Context(), id, transition)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, offer)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetHandler struct {
+	service *services.PasswordResetService
+}
+
+func NewPasswordResetHandler(service *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{service: service}
+}
+
+// RequestReset emails a single-use password reset link. The response is
+// the same whether or not the email belongs to an account.
+func (h *PasswordResetHandler) RequestReset(c *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	if err := h.service.RequestReset(c.Request.Context(), request.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusAccepted, gin.H{"message": "If an account exists for this email, a password reset link has been sent"})
+}
+
+// ConfirmReset sets a new password from the token in a reset link
+func (h *PasswordResetHandler) ConfirmReset(c *gin.Context) {
+	var request struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "token and password are required")
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), request.Token, request.Password); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, gin.H{"message": "Your password has been reset"})
+}
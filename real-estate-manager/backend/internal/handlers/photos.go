@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PhotoHandler struct {
+	service *services.PhotoService
+}
+
+func NewPhotoHandler(service *services.PhotoService) *PhotoHandler {
+	return &PhotoHandler{service: service}
+}
+
+// UploadPhoto accepts a multipart "photo" file and an optional "caption"
+// and adds it to the property's photos
+func (h *PhotoHandler) UploadPhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	header, err := c.FormFile("photo")
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "photo file is required")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid photo upload")
+		return
+	}
+	defer file.Close()
+
+	property, err := h.service.UploadPhoto(c.Request.Context(), id, header.Filename, header.Size, file, c.PostForm("caption"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.CurrentPhotoCDN(c).Rewrite(property)
+	envelope.JSON(c, http.StatusCreated, property)
+}
+
+// ReorderPhotos rearranges a property's photos from a body such as
+// {"order": [2, 0, 1], "version": 7}, listing the current positions in the
+// new order. The first photo is the cover.
+func (h *PhotoHandler) ReorderPhotos(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var request struct {
+		Order   []int `json:"order" binding:"required"`
+		Version int   `json:"version"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "order is required")
+		return
+	}
+
+	property, err := h.service.ReorderPhotos(c.Request.Context(), id, request.Order, request.Version)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	middleware.CurrentPhotoCDN(c).Rewrite(property)
+	envelope.JSON(c, http.StatusOK, property)
+}
+
+// SetCoverPhoto makes the photo at a position the cover, from a body such
+// as {"index": 2, "version": 7}
+func (h *PhotoHandler) SetCoverPhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var request struct {
+		Index   *int `json:"index" binding:"required"`
+		Version int  `json:"version"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "index is required")
+		return
+	}
+
+	property, err := h.service.SetCoverPhoto(c.Request.Context(), id, *request.Index, request.Version)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	middleware.CurrentPhotoCDN(c).Rewrite(property)
+	envelope.JSON(c, http.StatusOK, property)
+}
+
+// UpdatePhoto edits the alt text and caption of the photo at a position,
+// from a body such as {"alt_text": "Kitchen with island", "version": 7}
+func (h *PhotoHandler) UpdatePhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid photo index")
+		return
+	}
+
+	var update models.PhotoUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid photo update")
+		return
+	}
+
+	property, err := h.service.UpdatePhoto(c.Request.Context(), id, index, update)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	middleware.CurrentPhotoCDN(c).Rewrite(property)
+	envelope.JSON(c, http.StatusOK, property)
+}
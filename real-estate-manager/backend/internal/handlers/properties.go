@@ -1,104 +1,487 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"log"
 	"net/http"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
 	"real-estate-manager/backend/internal/models"
 	services "real-estate-manager/backend/internal/services"
+	"real-estate-manager/backend/pkg/units"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type PropertyHandler struct {
 	Service *services.PropertyService
+	// Views records who views a property; nil disables tracking
+	Views *services.ViewService
+	// Exports audits and watermarks bulk exports
+	Exports *services.ExportService
 }
 
 // NewPropertyHandler creates a new PropertyHandler instance
-func NewPropertyHandler(service *services.PropertyService) *PropertyHandler {
+func NewPropertyHandler(service *services.PropertyService, views *services.ViewService, exports *services.ExportService) *PropertyHandler {
 	return &PropertyHandler{
 		Service: service,
+		Views:   views,
+		Exports: exports,
+	}
+}
+
+// unitSystem reads the ?units= query parameter, responding 400 when the
+// value is not supported
+func unitSystem(c *gin.Context) (units.System, bool) {
+	system, err := units.ParseSystem(c.Query("units"))
+	if err != nil {
+		envelope.Errorf(c, http.StatusBadRequest, "units must be %q or %q", units.Imperial, units.Metric)
+		return "", false
+	}
+	return system, true
+}
+
+// presentProperty prepares a property for a response: its measurements in
+// the caller's unit system and its photo URLs on the CDN, if there is one
+func presentProperty(c *gin.Context, system units.System, property *models.Property) {
+	property.ApplyUnits(system)
+	middleware.CurrentPhotoCDN(c).Rewrite(property)
+}
+
+// photoExpansion is the ?expand= parameter of property lists, which carry
+// only each property's cover photo and photo_count unless it is "photos"
+type photoExpansion struct {
+	Expand string `form:"expand" binding:"omitempty,oneof=photos"`
+}
+
+// apply trims property's photos unless they were expanded
+func (e photoExpansion) apply(property *models.Property) {
+	if e.Expand != "photos" {
+		property.SummarizePhotos()
+	}
+}
+
+// propertyListQuery is the query string of GET /api/properties
+type propertyListQuery struct {
+	photoExpansion
+	Stale              bool      `form:"stale"`
+	Status             string    `form:"status"`
+	ExpiringWithinDays int       `form:"expiring_within_days" binding:"omitempty,min=1,max=365"`
+	ListingType        string    `form:"listing_type"`
+	MaxMonthlyRent     *float64  `form:"max_monthly_rent" binding:"omitempty,min=0"`
+	AvailableBy        time.Time `form:"available_by" time_format:"2006-01-02"`
+	Category           string    `form:"category"`
+	Zoning             string    `form:"zoning"`
+	MinCapRate         *float64  `form:"min_cap_rate" binding:"omitempty,min=0"`
+	MaxAnnualTax       *float64  `form:"max_annual_tax" binding:"omitempty,min=0"`
+	MinParkingSpaces   *int      `form:"min_parking_spaces" binding:"omitempty,min=0"`
+	MinPrice           *float64  `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice           *float64  `form:"max_price" binding:"omitempty,min=0"`
+	Bedrooms           *int      `form:"bedrooms" binding:"omitempty,min=0"`
+	Bathrooms          *int      `form:"bathrooms" binding:"omitempty,min=0"`
+	PropertyType       string    `form:"property_type"`
+	Location           string    `form:"location"`
+	MinYearBuilt       *int      `form:"min_year_built" binding:"omitempty,min=1600,max=2100"`
+	MaxYearBuilt       *int      `form:"max_year_built" binding:"omitempty,min=1600,max=2100"`
+	HasPool            *bool     `form:"has_pool"`
+	MinGarageSpaces    *int      `form:"min_garage_spaces" binding:"omitempty,min=0"`
+	HVACType           *string   `form:"hvac_type"`
+	MaxHOAFee          *float64  `form:"max_hoa_fee" binding:"omitempty,min=0"`
+	Sort               string    `form:"sort"`
+	Page               int       `form:"page,default=1" binding:"min=1"`
+	Limit              int       `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+func (q propertyListQuery) search() models.PropertySearch {
+	return models.PropertySearch{
+		Stale:              q.Stale,
+		Status:             q.Status,
+		ExpiringWithinDays: q.ExpiringWithinDays,
+		ListingType:        q.ListingType,
+		MaxMonthlyRent:     q.MaxMonthlyRent,
+		AvailableBy:        q.AvailableBy,
+		Category:           q.Category,
+		Zoning:             q.Zoning,
+		MinCapRate:         q.MinCapRate,
+		MaxAnnualTax:       q.MaxAnnualTax,
+		MinParkingSpaces:   q.MinParkingSpaces,
+		MinPrice:           q.MinPrice,
+		MaxPrice:           q.MaxPrice,
+		MinBedrooms:        q.Bedrooms,
+		MinBathrooms:       q.Bathrooms,
+		PropertyType:       strings.TrimSpace(q.PropertyType),
+		Location:           strings.TrimSpace(q.Location),
+		MinYearBuilt:       q.MinYearBuilt,
+		MaxYearBuilt:       q.MaxYearBuilt,
+		Amenities: models.AmenityFilter{
+			HasPool:         q.HasPool,
+			MinGarageSpaces: q.MinGarageSpaces,
+			HVACType:        q.HVACType,
+			MaxHOAFee:       q.MaxHOAFee,
+		},
+		Sort:  q.Sort,
+		Limit: q.Limit,
+		Page:  q.Page,
 	}
 }
 
 func (h *PropertyHandler) CreateProperty(c *gin.Context) {
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+
 	var property models.Property
 	if err := c.ShouldBindJSON(&property); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
 		return
 	}
 
+	// Listings are assigned to their creator unless an agent is given
+	if !property.AgentID.Valid {
+		if userID, ok := middleware.CurrentUserID(c); ok {
+			property.AgentID = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(userID), Valid: true}}
+		}
+	}
+
 	err := h.Service.CreateProperty(c.Request.Context(), &property)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, property)
+	translateWarnings(c, property.Warnings)
+	presentProperty(c, system, &property)
+	envelope.JSON(c, http.StatusCreated, property)
 }
 
 func (h *PropertyHandler) GetProperties(c *gin.Context) {
-	properties, err := h.Service.GetAllProperties(c.Request.Context())
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+
+	var query propertyListQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	properties, err := h.Service.SearchProperties(c.Request.Context(), query.search())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, properties)
+	for i := range properties {
+		presentProperty(c, system, &properties[i])
+		query.apply(&properties[i])
+	}
+	envelope.List(c, properties, envelope.Pagination{Limit: query.Limit, Page: query.Page})
+}
+
+// GetPropertyFacets counts the properties matching the GET /api/properties
+// filters by category, listing type, status and zoning. Sorting and paging
+// are ignored.
+func (h *PropertyHandler) GetPropertyFacets(c *gin.Context) {
+	var query propertyListQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	facets, err := h.Service.Facets(c.Request.Context(), query.search())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, facets)
+}
+
+// ExportProperties streams every property in ID order, as newline-delimited
+// JSON (?format=ndjson, one object per line) or as CSV (?format=csv, one
+// row per line after a header). ?after= resumes an interrupted export after
+// the last ID received; an interrupted export ends with an {"error": ...}
+// line. Every export is audited. With the export_watermark setting on, the
+// exporting user is named in the X-Export-Watermark header and, for CSV, in
+// a leading "# " comment line.
+func (h *PropertyHandler) ExportProperties(c *gin.Context) {
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+	var query struct {
+		Format  string `form:"format,default=ndjson" binding:"oneof=ndjson csv"`
+		AfterID int    `form:"after" binding:"min=0"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	export := &services.Export{Kind: "properties", Format: query.Format, Filter: c.Request.URL.Query(), RequestID: middleware.GetRequestID(c)}
+	if err := h.Exports.Start(c.Request.Context(), export); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	csvWriter := csv.NewWriter(c.Writer)
+	// Headers are only sent with the first line, so a failure before then
+	// can still be reported as a JSON error
+	started := false
+	start := func() error {
+		c.Header("X-Export-ID", export.ID)
+		if export.Watermark != "" {
+			c.Header("X-Export-Watermark", export.Watermark)
+		}
+		started = true
+		if query.Format == "ndjson" {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			return nil
+		}
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", `attachment; filename="properties.csv"`)
+		c.Status(http.StatusOK)
+		if export.Watermark != "" {
+			if _, err := c.Writer.WriteString("# " + export.Watermark + "\n"); err != nil {
+				return err
+			}
+		}
+		return csvWriter.Write(services.PropertyCSVHeader)
+	}
+
+	encoder := json.NewEncoder(c.Writer)
+	exported := 0
+	err := h.Service.ExportProperties(c.Request.Context(), query.AfterID, func(property models.Property) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if query.Format == "csv" {
+			if err := csvWriter.Write(services.PropertyCSVRecord(property)); err != nil {
+				return err
+			}
+		} else {
+			presentProperty(c, system, &property)
+			if err := encoder.Encode(property); err != nil {
+				return err
+			}
+		}
+		if exported++; exported%services.ExportPageSize == 0 {
+			csvWriter.Flush()
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	csvWriter.Flush()
+	if err == nil {
+		err = csvWriter.Error()
+	}
+	h.Exports.Finish(c.Request.Context(), export, exported, err)
+	if err != nil {
+		if c.Request.Context().Err() != nil {
+			log.Printf("Property export abandoned by the client after %d properties", exported)
+			return
+		}
+		if !started {
+			respondError(c, err)
+			return
+		}
+		// The status is already sent, so the failure is reported as a final
+		// line; clients resume with ?after= set to the last ID received
+		log.Printf("Property export stopped after %d properties: %v", exported, err)
+		encoder.Encode(gin.H{"error": "Export interrupted"})
+		return
+	}
+	c.Writer.WriteHeaderNow()
 }
 
 func (h *PropertyHandler) GetProperty(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid property ID"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	system, ok := unitSystem(c)
+	if !ok {
 		return
 	}
 
 	property, err := h.Service.GetProperty(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
+	h.recordView(c, id)
+
+	presentProperty(c, system, property)
+	envelope.JSON(c, http.StatusOK, property)
+}
 
-	c.JSON(http.StatusOK, property)
+// recordView adds a property to the caller's recently viewed list. Admins
+// impersonating a user leave the user's list alone, and a failure only
+// costs the view.
+func (h *PropertyHandler) recordView(c *gin.Context, id int) {
+	if h.Views == nil {
+		return
+	}
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return
+	}
+	if _, impersonated := middleware.ImpersonatorID(c); impersonated {
+		return
+	}
+	if err := h.Views.Record(c.Request.Context(), userID, id); err != nil {
+		log.Printf("Failed to record view of property %d by user %d: %v", id, userID, err)
+	}
 }
 
 func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid property ID"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	system, ok := unitSystem(c)
+	if !ok {
 		return
 	}
 
 	var property models.Property
 	if err := c.ShouldBindJSON(&property); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
 		return
 	}
 
 	property.ID = id
 	err = h.Service.UpdateProperty(c.Request.Context(), &property)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	translateWarnings(c, property.Warnings)
+	presentProperty(c, system, &property)
+	envelope.JSON(c, http.StatusOK, property)
+}
+
+// translateWarnings puts the messages of a saved property's warnings in
+// the request's language
+func translateWarnings(c *gin.Context, warnings []models.PropertyWarning) {
+	for i := range warnings {
+		warnings[i].Message = envelope.Translate(c, warnings[i].Message)
+	}
+}
+
+// Sync applies a batch of offline mutations all-or-nothing. A batch with
+// stale base versions is rejected with 409 and the conflicting properties'
+// current state.
+func (h *PropertyHandler) Sync(c *gin.Context) {
+	var request models.SyncRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	result, err := h.Service.Sync(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if len(result.Conflicts) > 0 {
+		envelope.ErrorDetails(c, http.StatusConflict, "Some mutations are based on outdated versions", gin.H{"conflicts": result.Conflicts})
+		return
+	}
+	envelope.JSON(c, http.StatusOK, result)
+}
+
+// GetRevisions lists the stored snapshots of a property
+func (h *PropertyHandler) GetRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	revisions, err := h.Service.GetRevisions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, revisions)
+}
+
+// RevertProperty restores a property to one of its revisions
+func (h *PropertyHandler) RevertProperty(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+	revisionID, err := strconv.Atoi(c.Param("revisionId"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+
+	property, err := h.Service.RevertProperty(c.Request.Context(), id, revisionID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	presentProperty(c, system, property)
+	envelope.JSON(c, http.StatusOK, property)
+}
+
+// BulkUpdate applies a patch to all properties matching a filter
+func (h *PropertyHandler) BulkUpdate(c *gin.Context) {
+	var req models.BulkUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	result, err := h.Service.BulkUpdate(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, property)
+	envelope.JSON(c, http.StatusOK, result)
 }
 
 func (h *PropertyHandler) DeleteProperty(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid property ID"})
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
 		return
 	}
 
 	if err := h.Service.DeleteProperty(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusNoContent, gin.H{"message": "Property deleted successfully"})
+	envelope.JSON(c, http.StatusNoContent, gin.H{"message": "Property deleted successfully"})
 }
\ No newline at end of file
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PublicIDHandler lets routes that take a numeric property or user ID be
+// called with its public ID instead, while clients move over to them
+type PublicIDHandler struct {
+	service *services.PublicIDService
+}
+
+func NewPublicIDHandler(service *services.PublicIDService) *PublicIDHandler {
+	return &PublicIDHandler{service: service}
+}
+
+// Property replaces a property's public ID in the route parameter with its
+// numeric ID. Numeric IDs are passed through.
+func (h *PublicIDHandler) Property(param string) gin.HandlerFunc {
+	return h.resolve(param, func(ctx context.Context, publicID string) (string, error) {
+		id, err := h.service.PropertyID(ctx, publicID)
+		return strconv.Itoa(id), err
+	})
+}
+
+// User replaces a user's public ID in the route parameter with their
+// numeric ID. Numeric IDs are passed through.
+func (h *PublicIDHandler) User(param string) gin.HandlerFunc {
+	return h.resolve(param, func(ctx context.Context, publicID string) (string, error) {
+		id, err := h.service.UserID(ctx, publicID)
+		return strconv.FormatUint(uint64(id), 10), err
+	})
+}
+
+func (h *PublicIDHandler) resolve(param string, lookup func(ctx context.Context, publicID string) (string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.Param(param)
+		if _, err := uuid.Parse(value); err != nil {
+			c.Next()
+			return
+		}
+		id, err := lookup(c.Request.Context(), value)
+		if err != nil {
+			respondError(c, err)
+			c.Abort()
+			return
+		}
+		for i := range c.Params {
+			if c.Params[i].Key == param {
+				c.Params[i].Value = id
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PublicImageHandler struct {
+	service *services.PublicImageService
+}
+
+func NewPublicImageHandler(service *services.PublicImageService) *PublicImageHandler {
+	return &PublicImageHandler{service: service}
+}
+
+// GetImage serves a resized, watermarked photo of a public listing.
+// ?size= selects small, medium (default) or large. Variants are immutable
+// for a given source, so clients revalidate with the ETag.
+func (h *PublicImageHandler) GetImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid photo index")
+		return
+	}
+
+	image, err := h.service.Variant(c.Request.Context(), c.ClientIP(), id, index, c.Query("size"))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrRateLimited) {
+			c.Header("Retry-After", "60")
+		} else if errors.Is(err, apperrors.ErrUnavailable) {
+			c.Header("Retry-After", "5")
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Header("ETag", image.ETag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cross-Origin-Resource-Policy", "cross-origin")
+	c.File(image.Path)
+}
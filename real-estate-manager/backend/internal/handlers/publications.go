@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PublicationHandler struct {
+	service *services.PublicationService
+}
+
+func NewPublicationHandler(service *services.PublicationService) *PublicationHandler {
+	return &PublicationHandler{service: service}
+}
+
+// reviewRequest is the body of an approval or rejection
+type reviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// GetPublication returns the property's publish state and last review
+func (h *PublicationHandler) GetPublication(c *gin.Context) {
+	h.respond(c, h.service.Get)
+}
+
+// Submit puts the property in the review queue
+func (h *PublicationHandler) Submit(c *gin.Context) {
+	h.respond(c, h.service.Submit)
+}
+
+// Unpublish takes the property out of public feeds and the review queue
+func (h *PublicationHandler) Unpublish(c *gin.Context) {
+	h.respond(c, h.service.Unpublish)
+}
+
+// GetQueue lists the listings waiting for review, longest waiting first
+func (h *PublicationHandler) GetQueue(c *gin.Context) {
+	var query struct {
+		Limit int `form:"limit,default=50" binding:"min=1,max=200"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	reviews, err := h.service.Queue(c.Request.Context(), query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	cdn := middleware.CurrentPhotoCDN(c)
+	for i := range reviews {
+		cdn.Rewrite(&reviews[i].Property)
+	}
+	envelope.List(c, reviews, envelope.Pagination{Limit: query.Limit})
+}
+
+// Approve publishes a listing waiting for review, with an optional
+// {"comment": "..."}
+func (h *PublicationHandler) Approve(c *gin.Context) {
+	h.review(c, h.service.Approve)
+}
+
+// Reject sends a listing waiting for review back to its agent; the body's
+// comment says why and is required
+func (h *PublicationHandler) Reject(c *gin.Context) {
+	h.review(c, h.service.Reject)
+}
+
+func (h *PublicationHandler) respond(c *gin.Context, action func(ctx context.Context, propertyID int) (*models.ListingPublication, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	publication, err := action(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, publication)
+}
+
+func (h *PublicationHandler) review(c *gin.Context, decide func(ctx context.Context, propertyID int, comment string) (*models.ListingPublication, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+	var req reviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			envelope.Error(c, http.StatusBadRequest, "Invalid input")
+			return
+		}
+	}
+
+	publication, err := decide(c.Request.Context(), id, req.Comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, publication)
+}
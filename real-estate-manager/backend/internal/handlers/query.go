@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// bindQuery fills query, a pointer to a struct of query parameters. Each
+// field names its parameter, and optionally its default, in a form tag and
+// its bounds in a binding tag:
+//
+//	Limit int `form:"limit,default=50" binding:"min=1,max=200"`
+//
+// A malformed or out-of-range value is answered with a 400 saying what the
+// parameter accepts, and bindQuery returns false.
+func bindQuery(c *gin.Context, query any) bool {
+	if err := c.ShouldBindQuery(query); err != nil {
+		format, args := queryError(c, query, err)
+		envelope.Errorf(c, http.StatusBadRequest, format, args...)
+		return false
+	}
+	return true
+}
+
+// queryError describes the parameter that failed to bind. Validation errors
+// name their field; parse errors do not, so the first value that does not
+// parse as its field's type is reported. The description is returned as a
+// format and its args, so it can be translated.
+func queryError(c *gin.Context, query any, err error) (string, []any) {
+	queryType := reflect.TypeOf(query).Elem()
+
+	var invalid validator.ValidationErrors
+	if errors.As(err, &invalid) {
+		if field, ok := queryType.FieldByName(invalid[0].StructField()); ok {
+			return describeParam(field)
+		}
+	}
+	for _, field := range reflect.VisibleFields(queryType) {
+		if field.Anonymous {
+			continue
+		}
+		if raw, ok := c.GetQuery(paramName(field)); ok && !parses(field, raw) {
+			return describeParam(field)
+		}
+	}
+	return "invalid query parameters", nil
+}
+
+func paramName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+	return name
+}
+
+// timeLayout returns the layout of a time field: its time_format tag, as
+// gin binds it, or RFC 3339
+func timeLayout(field reflect.StructField) string {
+	if layout := field.Tag.Get("time_format"); layout != "" {
+		return layout
+	}
+	return time.RFC3339
+}
+
+// parses reports whether raw is a valid value for field
+func parses(field reflect.StructField, raw string) bool {
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if raw == "" {
+		return true
+	}
+	var err error
+	switch {
+	case t == timeType:
+		_, err = time.Parse(timeLayout(field), raw)
+	case t.Kind() == reflect.Bool:
+		_, err = strconv.ParseBool(raw)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		_, err = strconv.ParseInt(raw, 10, t.Bits())
+	case t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64:
+		_, err = strconv.ParseUint(raw, 10, t.Bits())
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		_, err = strconv.ParseFloat(raw, t.Bits())
+	}
+	return err == nil
+}
+
+// describeParam says what a parameter accepts, from its type and binding
+// rules, e.g. "limit must be a number between 1 and 200", as a format and
+// its args
+func describeParam(field reflect.StructField) (string, []any) {
+	rules := map[string]string{}
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		rules[name] = param
+	}
+	name := paramName(field)
+
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	min, hasMin := rules["min"]
+	max, hasMax := rules["max"]
+	switch {
+	case t == timeType && timeLayout(field) == time.RFC3339:
+		return "%s must be an RFC 3339 timestamp", []any{name}
+	case t == timeType:
+		example := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC).Format(timeLayout(field))
+		return "%s must be formatted like %s", []any{name, example}
+	case t.Kind() == reflect.Bool:
+		return "%s must be true or false", []any{name}
+	case t.Kind() == reflect.String:
+		if values, ok := rules["oneof"]; ok {
+			return "%s must be %s", []any{name, listOptions(strings.Fields(values))}
+		}
+		if _, ok := rules["required"]; ok {
+			return "%s is required", []any{name}
+		}
+		return "%s is invalid", []any{name}
+	case hasMin && hasMax:
+		return "%s must be a number between %s and %s", []any{name, min, max}
+	case hasMin && min == "0":
+		return "%s must be a non-negative number", []any{name}
+	case hasMin:
+		return "%s must be a number of at least %s", []any{name, min}
+	case hasMax:
+		return "%s must be a number of at most %s", []any{name, max}
+	default:
+		return "%s must be a number", []any{name}
+	}
+}
+
+// listOptions joins values as "a, b or c"
+func listOptions(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
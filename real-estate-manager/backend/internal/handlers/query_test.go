@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBindQuery_AvailableBy(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectStatus int
+		expectBody   string
+	}{
+		{name: "date", query: "available_by=2026-05-01", expectStatus: http.StatusOK},
+		{name: "timestamp", query: "available_by=2026-05-01T00:00:00Z", expectStatus: http.StatusBadRequest,
+			expectBody: `{"error":"available_by must be formatted like 2024-01-31"}`},
+		// The valid date is not blamed for another parameter
+		{name: "date with a bad price", query: "available_by=2026-05-01&max_price=cheap", expectStatus: http.StatusBadRequest,
+			expectBody: `{"error":"max_price must be a non-negative number"}`},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query propertyListQuery
+			r := gin.New()
+			r.GET("/", func(c *gin.Context) {
+				if bindQuery(c, &query) {
+					c.Status(http.StatusOK)
+				}
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/?"+tt.query, nil))
+
+			if w.Code != tt.expectStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectStatus, w.Code, w.Body.String())
+			}
+			if tt.expectBody != "" && w.Body.String() != tt.expectBody {
+				t.Errorf("Expected %s, got %s", tt.expectBody, w.Body.String())
+			}
+			if tt.expectStatus == http.StatusOK && !query.AvailableBy.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)) {
+				t.Errorf("Unexpected available_by %v", query.AvailableBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	service *services.RecommendationService
+}
+
+func NewRecommendationHandler(service *services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{service: service}
+}
+
+// GetRecommendations lists the listings recommended to the caller, best
+// first, up to ?limit=
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+	var query struct {
+		photoExpansion
+		Limit int `form:"limit,default=20" binding:"min=1,max=20"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	recommendations, err := h.service.For(c.Request.Context(), userID, query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range recommendations {
+		presentProperty(c, system, recommendations[i].Property)
+		query.apply(recommendations[i].Property)
+	}
+	envelope.List(c, recommendations, envelope.Pagination{Limit: query.Limit})
+}
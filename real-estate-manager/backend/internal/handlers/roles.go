@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	permissions *services.PermissionService
+}
+
+func NewRoleHandler(permissions *services.PermissionService) *RoleHandler {
+	return &RoleHandler{permissions: permissions}
+}
+
+// GetRoles lists role mappings and the permissions that can be granted
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	envelope.JSON(c, http.StatusOK, gin.H{
+		"roles":       h.permissions.List(),
+		"permissions": h.permissions.Permissions(),
+	})
+}
+
+// UpdateRole creates or replaces a role, e.g.
+// {"permissions": ["properties:read", "jobs:*"], "organization_id": 3}
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var request struct {
+		Permissions    []string `json:"permissions" binding:"required"`
+		OrganizationID int      `json:"organization_id"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "permissions is required")
+		return
+	}
+
+	role, err := h.permissions.SetRole(c.Request.Context(), c.Param("role"), request.OrganizationID,
+		request.Permissions, c.GetString("username"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, role)
+}
+
+// DeleteRole removes a role; ?organization_id= selects a brokerage's role
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	organizationID := 0
+	if value := c.Query("organization_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			envelope.Error(c, http.StatusBadRequest, "Invalid organization ID")
+			return
+		}
+		organizationID = id
+	}
+
+	if err := h.permissions.DeleteRole(c.Request.Context(), c.Param("role"), organizationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AssignRole sets a user's role, e.g. {"role": "viewer"}
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var request struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "role is required")
+		return
+	}
+
+	user, err := h.permissions.AssignRole(c.Request.Context(), uint(id), request.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, user)
+}
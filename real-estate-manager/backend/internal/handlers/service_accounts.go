@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceAccountHandler struct {
+	accounts *services.ServiceAccountService
+}
+
+func NewServiceAccountHandler(accounts *services.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{accounts: accounts}
+}
+
+// GetServiceAccounts lists service accounts and the scopes tokens can carry
+func (h *ServiceAccountHandler) GetServiceAccounts(c *gin.Context) {
+	accounts, err := h.accounts.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, gin.H{
+		"service_accounts": accounts,
+		"scopes":           h.accounts.Scopes(),
+	})
+}
+
+// CreateServiceAccount adds an automation account, e.g.
+// {"username": "nightly-export", "role": "viewer"}
+func (h *ServiceAccountHandler) CreateServiceAccount(c *gin.Context) {
+	var request models.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "username is required")
+		return
+	}
+
+	account, err := h.accounts.Create(c.Request.Context(), request, c.GetString("username"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusCreated, account)
+}
+
+// IssueToken signs a scoped token for a service account, e.g.
+// {"scopes": ["read:properties"], "expires_in": "720h"}
+func (h *ServiceAccountHandler) IssueToken(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		envelope.Error(c, http.StatusBadRequest, "Invalid service account ID")
+		return
+	}
+	adminID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	var request models.IssueServiceTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "scopes is required")
+		return
+	}
+	var ttl time.Duration
+	if request.ExpiresIn != "" {
+		if ttl, err = time.ParseDuration(request.ExpiresIn); err != nil {
+			envelope.Error(c, http.StatusBadRequest, "expires_in must be a duration such as 720h")
+			return
+		}
+	}
+
+	token, err := h.accounts.IssueToken(c.Request.Context(), id, request.Scopes, ttl, adminID, middleware.GetRequestID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusCreated, token)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShowingHandler struct {
+	service *services.ShowingService
+}
+
+func NewShowingHandler(service *services.ShowingService) *ShowingHandler {
+	return &ShowingHandler{service: service}
+}
+
+// GetShowings lists the property's showings and open houses
+func (h *ShowingHandler) GetShowings(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	showings, err := h.service.List(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, showings)
+}
+
+// CreateShowing requests a showing or open house slot for the property
+func (h *ShowingHandler) CreateShowing(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var showing models.Showing
+	if err := c.ShouldBindJSON(&showing); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Create(c.Request.Context(), id, &showing); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, showing)
+}
+
+// ConfirmShowing confirms a requested slot. A slot that overlaps another
+// confirmed showing or a calendar event is answered with 409 and the
+// conflicts.
+func (h *ShowingHandler) ConfirmShowing(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid showing ID")
+		return
+	}
+
+	showing, conflicts, err := h.service.Confirm(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(conflicts) > 0 {
+		envelope.ErrorDetails(c, http.StatusConflict, "The slot conflicts with the agent's schedule", gin.H{"conflicts": conflicts})
+		return
+	}
+	envelope.JSON(c, http.StatusOK, showing)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSignatureCallback caps the size of an e-signature callback body
+const maxSignatureCallback = 1 << 20
+
+type SignatureHandler struct {
+	service *services.SignatureService
+}
+
+func NewSignatureHandler(service *services.SignatureService) *SignatureHandler {
+	return &SignatureHandler{service: service}
+}
+
+// GetSignatureRequests lists the documents of the property sent for
+// signature
+func (h *SignatureHandler) GetSignatureRequests(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	requests, err := h.service.List(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, requests)
+}
+
+// SendForSignature sends an offer or disclosure document of the property
+// to its signers
+func (h *SignatureHandler) SendForSignature(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	var request models.SignatureRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.Send(c.Request.Context(), id, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusCreated, request)
+}
+
+func (h *SignatureHandler) GetSignatureRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid signature request ID")
+		return
+	}
+
+	request, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, request)
+}
+
+// VoidSignatureRequest cancels a request signers have not finished
+func (h *SignatureHandler) VoidSignatureRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid signature request ID")
+		return
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	request, err := h.service.Void(c.Request.Context(), id, body.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, request)
+}
+
+// Callback receives the e-signature provider's status callbacks, which
+// are authenticated by their signature
+func (h *SignatureHandler) Callback(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignatureCallback))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		envelope.Error(c, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := h.service.HandleCallback(c.Request.Context(), c.Request.Header, body); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/models"
 	"real-estate-manager/backend/internal/services"
 	"strconv"
 	"time"
@@ -14,22 +18,28 @@ import (
 
 type SimplyRETSHandler struct {
 	simplyRETSService *services.SimplyRETSService
+	quota             *services.ImportQuotaService
+	schedule          *services.SyncScheduleService
 }
 
-func NewSimplyRETSHandler(simplyRETSService *services.SimplyRETSService) *SimplyRETSHandler {
+func NewSimplyRETSHandler(simplyRETSService *services.SimplyRETSService, quota *services.ImportQuotaService, schedule *services.SyncScheduleService) *SimplyRETSHandler {
 	return &SimplyRETSHandler{
 		simplyRETSService: simplyRETSService,
+		quota:             quota,
+		schedule:          schedule,
 	}
 }
 
-// StartProcessing starts the property processing job
+// StartProcessing starts the property processing job. An optional label,
+// description and metadata are kept in the job history.
 func (h *SimplyRETSHandler) StartProcessing(c *gin.Context) {
 	var request struct {
 		Limit int `json:"limit"`
+		models.JobDetails
 	}
 	
-	// Default limit to 50 if not provided
-	request.Limit = 50
+	// Default limit comes from the sync_default_limit setting (50 unless changed)
+	request.Limit = h.simplyRETSService.DefaultLimit()
 	
 	if err := c.ShouldBindJSON(&request); err != nil {
 		// If binding fails, use query parameter or default
@@ -42,92 +52,203 @@ func (h *SimplyRETSHandler) StartProcessing(c *gin.Context) {
 	
 	// Validate limit
 	if request.Limit <= 0 || request.Limit > 500 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Limit must be between 1 and 500",
-		})
+		envelope.Error(c, http.StatusBadRequest, "Limit must be between 1 and 500")
+		return
+	}
+	
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	// Non-admins may only start so many jobs per hour and day
+	if quota, err := h.quota.Check(userID, middleware.IsAdmin(c)); err != nil {
+		c.Header("Retry-After", quota.RetryAfterHeader(time.Now()))
+		envelope.ErrorDetails(c, http.StatusTooManyRequests, err.Error(), gin.H{"quota": quota})
 		return
 	}
 	
 	// Generate unique job ID
 	jobID := uuid.New().String()
 	
-	// Start processing with a background context instead of request context
-	// This prevents the job from being cancelled when the HTTP request completes
-	err := h.simplyRETSService.StartPropertyProcessing(context.Background(), jobID, request.Limit)
+	// Detach the job from the request so it is not cancelled when the HTTP
+	// request completes, but keep who started it
+	jobCtx := context.WithoutCancel(c.Request.Context())
+	err := h.simplyRETSService.StartPropertyProcessing(jobCtx, jobID, request.Limit, &request.JobDetails)
+	if errors.Is(err, apperrors.ErrTooLarge) || errors.Is(err, apperrors.ErrValidation) || errors.Is(err, apperrors.ErrConflict) {
+		respondError(c, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("Failed to start processing: %v", err),
-		})
+		envelope.Errorf(c, http.StatusInternalServerError, "Failed to start processing: %v", err)
 		return
 	}
+	h.quota.Record(userID)
 	
-	c.JSON(http.StatusAccepted, gin.H{
+	envelope.JSON(c, http.StatusAccepted, gin.H{
 		"job_id":    jobID,
 		"message":   "Property processing started",
 		"limit":     request.Limit,
+		"label":     request.Label,
 		"started_at": time.Now(),
 	})
 }
 
-// GetJobStatus returns the status of a processing job
+// GetSchedule returns when imports run automatically and how the last
+// scheduled run went
+func (h *SimplyRETSHandler) GetSchedule(c *gin.Context) {
+	envelope.JSON(c, http.StatusOK, h.schedule.Get())
+}
+
+// UpdateSchedule changes the automatic import schedule: a cron expression,
+// "off", or empty to follow SIMPLYRETS_SYNC_CRON
+func (h *SimplyRETSHandler) UpdateSchedule(c *gin.Context) {
+	var request struct {
+		Cron *string `json:"cron" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	schedule, err := h.schedule.Update(c.Request.Context(), *request.Cron, c.GetString("username"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, schedule)
+}
+
+// GetQuota returns the caller's usage of their import job quotas
+func (h *SimplyRETSHandler) GetQuota(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	envelope.JSON(c, http.StatusOK, h.quota.Status(userID, middleware.IsAdmin(c)))
+}
+
+// ResetQuota clears a user's import job usage so they can start jobs again
+// before their quota window ends
+func (h *SimplyRETSHandler) ResetQuota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	h.quota.Reset(uint(id))
+	envelope.JSON(c, http.StatusOK, h.quota.Status(uint(id), false))
+}
+
+// GetJobStatus returns the status of a processing job to the user who
+// started it or an admin
 func (h *SimplyRETSHandler) GetJobStatus(c *gin.Context) {
 	jobID := c.Param("jobId")
 	if jobID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Job ID is required",
-		})
+		envelope.Error(c, http.StatusBadRequest, "Job ID is required")
 		return
 	}
 	
-	status, exists := h.simplyRETSService.GetJobStatus(jobID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Job not found",
-		})
+	status, err := h.simplyRETSService.GetJobStatus(c.Request.Context(), jobID, jobScope(c))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 	
-	c.JSON(http.StatusOK, status)
+	envelope.JSON(c, http.StatusOK, status)
+}
+
+// ResumeJob runs a failed, cancelled or interrupted job again, skipping the
+// listings it already saved. Like starting a job, it counts toward the
+// caller's import quota.
+func (h *SimplyRETSHandler) ResumeJob(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	if quota, err := h.quota.Check(userID, middleware.IsAdmin(c)); err != nil {
+		c.Header("Retry-After", quota.RetryAfterHeader(time.Now()))
+		envelope.ErrorDetails(c, http.StatusTooManyRequests, err.Error(), gin.H{"quota": quota})
+		return
+	}
+
+	jobID := c.Param("jobId")
+	// Detach the job from the request like a newly started one
+	jobCtx := context.WithoutCancel(c.Request.Context())
+	if err := h.simplyRETSService.ResumeJob(jobCtx, jobID, jobScope(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+	h.quota.Record(userID)
+
+	envelope.JSON(c, http.StatusAccepted, gin.H{
+		"job_id":     jobID,
+		"message":    "Property processing resumed",
+		"resumed_at": time.Now(),
+	})
 }
 
-// CancelJob cancels a running processing job
+// GetJobArtifact downloads one of a finished job's artifacts: errors.csv,
+// changes.csv, pages.json or an archived page such as page-001.json
+func (h *SimplyRETSHandler) GetJobArtifact(c *gin.Context) {
+	path, err := h.simplyRETSService.JobArtifact(c.Request.Context(), c.Param("jobId"), c.Param("name"), jobScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.FileAttachment(path, c.Param("name"))
+}
+
+// CancelJob cancels a running processing job for the user who started it
+// or an admin
 func (h *SimplyRETSHandler) CancelJob(c *gin.Context) {
 	jobID := c.Param("jobId")
 	if jobID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Job ID is required",
-		})
+		envelope.Error(c, http.StatusBadRequest, "Job ID is required")
 		return
 	}
 	
-	cancelled := h.simplyRETSService.CancelJob(jobID)
-	if !cancelled {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Job not found or already completed",
-		})
+	if err := h.simplyRETSService.CancelJob(jobID, jobScope(c)); err != nil {
+		respondError(c, err)
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
+	envelope.JSON(c, http.StatusOK, gin.H{
 		"message": "Job cancelled successfully",
 		"job_id":  jobID,
 	})
 }
 
-// GetProcessingHistory returns a summary of processing activities
+// GetProcessingHistory lists past and running jobs, newest first.
+// ?label= keeps only jobs with that label and ?limit= caps how many are
+// returned.
 func (h *SimplyRETSHandler) GetProcessingHistory(c *gin.Context) {
-	// This would typically come from a database table storing job history
-	// For now, we'll return a simple response
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Processing history endpoint - to be implemented with persistent storage",
-	})
+	var filter models.JobFilter
+	if !bindQuery(c, &filter) {
+		return
+	}
+
+	jobs, err := h.simplyRETSService.ListJobs(c.Request.Context(), filter, jobScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.Page(c, gin.H{"jobs": jobs}, jobs, envelope.Meta{"pagination": envelope.Pagination{Limit: filter.Limit}})
 }
 
 // HealthCheck returns the health status of the SimplyRETS service
 func (h *SimplyRETSHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
+	envelope.JSON(c, http.StatusOK, gin.H{
 		"status":    "healthy",
 		"service":   "SimplyRETS Integration",
 		"timestamp": time.Now(),
 	})
 }
+
+// jobScope is who is asking about a job
+func jobScope(c *gin.Context) services.JobScope {
+	userID, _ := middleware.CurrentUserID(c)
+	return services.JobScope{UserID: userID, OrganizationID: middleware.CurrentOrganizationID(c), All: middleware.IsAdmin(c)}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SyndicationHandler struct {
+	service *services.SyndicationService
+}
+
+func NewSyndicationHandler(service *services.SyndicationService) *SyndicationHandler {
+	return &SyndicationHandler{service: service}
+}
+
+// GetSyndication lists the configured portals and the property's status on
+// each portal it was published to
+func (h *SyndicationHandler) GetSyndication(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	listings, err := h.service.Status(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, gin.H{"portals": h.service.Portals(), "listings": listings})
+}
+
+// Publish puts the property on a portal and returns its status there
+func (h *SyndicationHandler) Publish(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	listing, err := h.service.Publish(c.Request.Context(), id, c.Param("portal"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, listing)
+}
+
+// Unpublish takes the property off a portal and returns its status there
+func (h *SyndicationHandler) Unpublish(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	listing, err := h.service.Unpublish(c.Request.Context(), id, c.Param("portal"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, listing)
+}
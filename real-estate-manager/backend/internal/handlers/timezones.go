@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reportLocation resolves the time zone of a report from ?tz=, falling back
+// to the caller's preference. It responds with an error and returns false
+// when the zone is unknown.
+func reportLocation(c *gin.Context, notifications *services.NotificationService, tz string) (*time.Location, bool) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return nil, false
+	}
+	location, err := notifications.Location(c.Request.Context(), userID, tz)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return location, true
+}
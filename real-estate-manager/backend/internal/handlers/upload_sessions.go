@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadOffsetHeader carries the offset a chunk starts at, and in
+// responses the bytes a session has received
+const UploadOffsetHeader = "Upload-Offset"
+
+type UploadSessionHandler struct {
+	service *services.UploadSessionService
+}
+
+func NewUploadSessionHandler(service *services.UploadSessionService) *UploadSessionHandler {
+	return &UploadSessionHandler{service: service}
+}
+
+// CreateSession starts a resumable upload
+func (h *UploadSessionHandler) CreateSession(c *gin.Context) {
+	var req models.UploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "property_id, filename and size_bytes are required")
+		return
+	}
+
+	session, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header(UploadOffsetHeader, "0")
+	envelope.JSON(c, http.StatusCreated, session)
+}
+
+// GetSession reports a session's progress, including the offset to resume
+// from after an interrupted chunk
+func (h *UploadSessionHandler) GetSession(c *gin.Context) {
+	session, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header(UploadOffsetHeader, strconv.FormatInt(session.ReceivedBytes, 10))
+	envelope.JSON(c, http.StatusOK, session)
+}
+
+// WriteChunk appends the request body to the session at the offset in the
+// Upload-Offset header
+func (h *UploadSessionHandler) WriteChunk(c *gin.Context) {
+	offset, err := strconv.ParseInt(c.GetHeader(UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		envelope.Error(c, http.StatusBadRequest, "Upload-Offset header must be the number of bytes already received")
+		return
+	}
+
+	session, err := h.service.WriteChunk(c.Request.Context(), c.Param("id"), offset, c.Request.Body)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header(UploadOffsetHeader, strconv.FormatInt(session.ReceivedBytes, 10))
+	envelope.JSON(c, http.StatusOK, session)
+}
+
+// CompleteSession stores a fully received file
+func (h *UploadSessionHandler) CompleteSession(c *gin.Context) {
+	session, err := h.service.Complete(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, session)
+}
+
+// CancelSession abandons a session
+func (h *UploadSessionHandler) CancelSession(c *gin.Context) {
+	if err := h.service.Cancel(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UploadHandler struct {
+	service *services.DirectUploadService
+}
+
+func NewUploadHandler(service *services.DirectUploadService) *UploadHandler {
+	return &UploadHandler{service: service}
+}
+
+// Presign issues a pre-signed PUT URL for uploading a file straight to
+// object storage
+func (h *UploadHandler) Presign(c *gin.Context) {
+	var req models.PresignUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		envelope.Error(c, http.StatusBadRequest, "property_id, filename and size_bytes are required")
+		return
+	}
+
+	presigned, err := h.service.Presign(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusCreated, presigned)
+}
+
+// Confirm registers an upload once the client has PUT the file
+func (h *UploadHandler) Confirm(c *gin.Context) {
+	upload, err := h.service.Confirm(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	envelope.JSON(c, http.StatusOK, upload)
+}
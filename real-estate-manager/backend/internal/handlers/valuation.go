@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ValuationHandler struct {
+	service *services.ValuationService
+}
+
+func NewValuationHandler(service *services.ValuationService) *ValuationHandler {
+	return &ValuationHandler{service: service}
+}
+
+// GetEstimate returns an estimated value range for a property with the
+// comparables and methodology behind it
+func (h *ValuationHandler) GetEstimate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	estimate, err := h.service.Estimate(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, estimate)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/middleware"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ViewHandler struct {
+	service *services.ViewService
+}
+
+func NewViewHandler(service *services.ViewService) *ViewHandler {
+	return &ViewHandler{service: service}
+}
+
+// GetRecentlyViewed lists the properties the caller viewed, most recent
+// first, up to ?limit=
+func (h *ViewHandler) GetRecentlyViewed(c *gin.Context) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		envelope.Error(c, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+	system, ok := unitSystem(c)
+	if !ok {
+		return
+	}
+	var query struct {
+		photoExpansion
+		Limit int `form:"limit,default=20" binding:"min=1,max=50"`
+	}
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	viewed, err := h.service.Recent(c.Request.Context(), userID, query.Limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range viewed {
+		presentProperty(c, system, &viewed[i].Property)
+		query.apply(&viewed[i].Property)
+	}
+	envelope.List(c, viewed, envelope.Pagination{Limit: query.Limit})
+}
+
+// GetViewStats returns a property's view counters
+func (h *ViewHandler) GetViewStats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		envelope.Error(c, http.StatusBadRequest, "Invalid property ID")
+		return
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	envelope.JSON(c, http.StatusOK, stats)
+}
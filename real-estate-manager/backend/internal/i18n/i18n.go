@@ -0,0 +1,113 @@
+// Package i18n translates user-facing messages. Messages are written in
+// English, and locales/<locale>.json maps each message, or the format string
+// it was built from, to its translation:
+//
+//	{"property not found": "imóvel não encontrado"}
+//
+// Catalogs are embedded in the binary. Locales without a catalog, and
+// messages missing from one, fall back to English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fallback is the locale messages are written in
+const Fallback = "en"
+
+//go:embed locales
+var localeFiles embed.FS
+
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := fs.Glob(localeFiles, "locales/*.json")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("parsing %s: %v", file, err))
+		}
+		loaded[strings.TrimSuffix(path.Base(file), ".json")] = catalog
+	}
+	return loaded
+}
+
+// Locales lists the supported locales, the fallback first
+func Locales() []string {
+	locales := []string{Fallback}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// Negotiate picks the supported locale that best matches an Accept-Language
+// header, e.g. "pt-BR,pt;q=0.9,en;q=0.8" gives "pt". A regional tag matches
+// its language's catalog; nothing supported gives Fallback.
+func Negotiate(acceptLanguage string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag == "" || quality <= 0 {
+			continue
+		}
+		preferences = append(preferences, preference{strings.ToLower(tag), quality})
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, preference := range preferences {
+		language, _, _ := strings.Cut(preference.tag, "-")
+		for _, candidate := range []string{preference.tag, language} {
+			if candidate == Fallback {
+				return Fallback
+			}
+			if _, ok := catalogs[candidate]; ok {
+				return candidate
+			}
+		}
+	}
+	return Fallback
+}
+
+// Translate renders format in locale with args, as fmt.Sprintf does.
+// Without args format is the message itself and is not interpreted.
+func Translate(locale, format string, args ...any) string {
+	if translated, ok := catalogs[locale][format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
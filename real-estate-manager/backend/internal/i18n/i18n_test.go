@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", Fallback},
+		{"pt-BR,pt;q=0.9,en;q=0.8", "pt"},
+		{"es-MX", "es"},
+		{"fr-FR, es;q=0.5", "es"},
+		{"en;q=0.4, pt;q=0.7", "pt"},
+		{"es;q=0, de", Fallback},
+		{"ES", "es"},
+		{"*", Fallback},
+	}
+
+	for _, tt := range tests {
+		if locale := Negotiate(tt.header); locale != tt.expected {
+			t.Errorf("Negotiate(%q): expected %s, got %s", tt.header, tt.expected, locale)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if message := Translate("pt", "property not found"); message != "imóvel não encontrado" {
+		t.Errorf("Expected the Portuguese message, got %s", message)
+	}
+	if message := Translate("es", "limit must be between 1 and %d", 50); message != "limit debe estar entre 1 y 50" {
+		t.Errorf("Expected the formatted Spanish message, got %s", message)
+	}
+	if message := Translate("pt", "no translation for %s", "this"); message != "no translation for this" {
+		t.Errorf("Expected the English message for a missing key, got %s", message)
+	}
+	if message := Translate(Fallback, "splits add up to more than 100%"); message != "splits add up to more than 100%" {
+		t.Errorf("Expected a message without args to be left as is, got %s", message)
+	}
+}
+
+var verb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+
+// A translation must take the same arguments as its English format
+func TestCatalogsKeepVerbs(t *testing.T) {
+	if locales := Locales(); !slices.Equal(locales, []string{"en", "es", "pt"}) {
+		t.Errorf("Expected locales en, es and pt, got %v", locales)
+	}
+	for locale, catalog := range catalogs {
+		for format, translated := range catalog {
+			expected := verb.FindAllString(format, -1)
+			if actual := verb.FindAllString(translated, -1); !slices.Equal(actual, expected) {
+				t.Errorf("%s: %q has verbs %v, expected %v", locale, translated, actual, expected)
+			}
+		}
+	}
+}
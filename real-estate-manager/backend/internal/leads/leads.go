@@ -0,0 +1,113 @@
+// Package leads normalizes lead webhooks from portals and landing pages.
+// Every source posts its own payload shape; Parse turns each into a Lead.
+// Deliveries are signed with a per-source shared secret.
+package leads
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Lead sources
+const (
+	Zillow   = "zillow"
+	Facebook = "facebook"
+	Website  = "website"
+)
+
+// Sources lists the lead sources that can be configured
+var Sources = []string{Zillow, Facebook, Website}
+
+// ErrInvalidPayload is returned for payloads that are malformed or carry
+// no way to contact the lead
+var ErrInvalidPayload = errors.New("invalid lead payload")
+
+// Lead is an enquiry in the source-neutral shape. PropertyPublicID or
+// PropertyID is set when the source knows our listing ID; otherwise
+// MLSNumber or Address may identify the listing.
+type Lead struct {
+	ExternalID       string
+	Name             string
+	Email            string
+	Phone            string
+	Message          string
+	PropertyID       int
+	PropertyPublicID string
+	MLSNumber        string
+	Address          string
+}
+
+// Parse normalizes a webhook payload from source. Payloads without an
+// ID of their own are identified by a hash of the body, so a redelivery
+// is recognized.
+func Parse(source string, body []byte) (*Lead, error) {
+	var lead *Lead
+	var err error
+	switch source {
+	case Zillow:
+		lead, err = parseZillow(body)
+	case Facebook:
+		lead, err = parseFacebook(body)
+	case Website:
+		lead, err = parseWebsite(body)
+	default:
+		return nil, fmt.Errorf("unknown lead source %q", source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	lead.Name = strings.TrimSpace(lead.Name)
+	lead.Email = strings.ToLower(strings.TrimSpace(lead.Email))
+	lead.Phone = strings.TrimSpace(lead.Phone)
+	lead.Message = strings.TrimSpace(lead.Message)
+	if lead.Email == "" && lead.Phone == "" {
+		return nil, fmt.Errorf("%w: an email address or phone number is required", ErrInvalidPayload)
+	}
+	if lead.ExternalID == "" {
+		sum := sha256.Sum256(body)
+		lead.ExternalID = hex.EncodeToString(sum[:16])
+	}
+	return lead, nil
+}
+
+// Verify reports whether signature, in the form "sha256=<hex HMAC of the
+// body>", was made with secret
+func Verify(secret string, body []byte, signature string) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok || secret == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Sign returns the signature Verify expects for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SecretsFromEnv returns the signing secret of each enabled source, read
+// from LEAD_WEBHOOK_SECRET_<SOURCE> (e.g. LEAD_WEBHOOK_SECRET_ZILLOW). A
+// source without a secret does not accept leads.
+func SecretsFromEnv() map[string]string {
+	secrets := make(map[string]string)
+	for _, source := range Sources {
+		if secret := os.Getenv("LEAD_WEBHOOK_SECRET_" + strings.ToUpper(source)); secret != "" {
+			secrets[source] = secret
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,101 @@
+package leads
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		body   string
+		expect Lead
+	}{
+		{
+			name:   "zillow",
+			source: Zillow,
+			body: `{"leadId": "z-81", "contact": {"name": "Jane Doe", "email": " Jane@Example.com ", "phone": "+15551234567"},
+				"message": "Is it still available?", "listing": {"providerListingId": "12", "mlsNumber": "1005192", "address": "12 Main St"}}`,
+			expect: Lead{ExternalID: "z-81", Name: "Jane Doe", Email: "jane@example.com", Phone: "+15551234567",
+				Message: "Is it still available?", PropertyID: 12, MLSNumber: "1005192", Address: "12 Main St"},
+		},
+		{
+			name:   "facebook",
+			source: Facebook,
+			body: `{"id": "fb-7", "field_data": [{"name": "first_name", "values": ["Jane"]}, {"name": "last_name", "values": ["Doe"]},
+				{"name": "email", "values": ["jane@example.com"]}, {"name": "mls_number", "values": ["1005192"]}]}`,
+			expect: Lead{ExternalID: "fb-7", Name: "Jane Doe", Email: "jane@example.com", MLSNumber: "1005192"},
+		},
+		{
+			name:   "zillow public ID",
+			source: Zillow,
+			body: `{"leadId": "z-82", "contact": {"email": "jane@example.com"},
+				"listing": {"providerListingId": "6F1C2B9E-3D4A-4E5F-8A7B-9C0D1E2F3A4B"}}`,
+			expect: Lead{ExternalID: "z-82", Email: "jane@example.com", PropertyPublicID: "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b"},
+		},
+		{
+			name:   "website",
+			source: Website,
+			body:   `{"id": "w-1", "name": "Jane", "phone": "555-1234", "message": "Call me", "property_id": 3}`,
+			expect: Lead{ExternalID: "w-1", Name: "Jane", Phone: "555-1234", Message: "Call me", PropertyID: 3},
+		},
+		{
+			name:   "website public ID",
+			source: Website,
+			body:   `{"id": "w-2", "email": "jane@example.com", "property_id": "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b"}`,
+			expect: Lead{ExternalID: "w-2", Email: "jane@example.com", PropertyPublicID: "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lead, err := Parse(tt.source, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *lead != tt.expect {
+				t.Errorf("Expected %+v, got %+v", tt.expect, *lead)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse(Website, []byte(`{"name": "Jane"}`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("Expected a lead without contact details to be rejected, got %v", err)
+	}
+	if _, err := Parse(Website, []byte(`not json`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("Expected malformed JSON to be rejected, got %v", err)
+	}
+	if _, err := Parse("craigslist", []byte(`{}`)); err == nil {
+		t.Error("Expected an unknown source to be rejected")
+	}
+}
+
+func TestParse_HashesPayloadWithoutID(t *testing.T) {
+	body := []byte(`{"name": "Jane", "email": "jane@example.com"}`)
+	first, _ := Parse(Website, body)
+	second, _ := Parse(Website, body)
+	if first.ExternalID == "" || first.ExternalID != second.ExternalID {
+		t.Errorf("Expected a stable ID for a redelivered payload, got %q and %q", first.ExternalID, second.ExternalID)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"email": "jane@example.com"}`)
+	signature := Sign("secret", body)
+
+	if !Verify("secret", body, signature) {
+		t.Error("Expected a valid signature to verify")
+	}
+	if Verify("other", body, signature) {
+		t.Error("Expected a signature made with another secret to fail")
+	}
+	if Verify("secret", []byte(`{"email": "eve@example.com"}`), signature) {
+		t.Error("Expected a tampered body to fail")
+	}
+	if Verify("secret", body, signature[len("sha256="):]) || Verify("", body, Sign("", body)) {
+		t.Error("Expected a signature without its prefix, or an empty secret, to fail")
+	}
+}
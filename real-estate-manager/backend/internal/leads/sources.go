@@ -0,0 +1,138 @@
+package leads
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// listingRef is our ID of a listing as a source sends it: the numeric ID,
+// as a number or a string, or the public ID
+type listingRef string
+
+func (r *listingRef) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*r = ""
+		return nil
+	}
+	*r = listingRef(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// parse splits the reference into a numeric or a public ID; a reference
+// that is neither leaves both empty
+func (r listingRef) parse() (int, string) {
+	value := strings.TrimSpace(string(r))
+	if id, err := uuid.Parse(value); err == nil {
+		return 0, id.String()
+	}
+	id, _ := strconv.Atoi(value)
+	return id, ""
+}
+
+// zillowLead is a Zillow contact form lead. Listings we syndicate carry
+// our property's public ID, or its numeric ID if they were syndicated
+// before public IDs, as providerListingId.
+type zillowLead struct {
+	LeadID  string `json:"leadId"`
+	Contact struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	} `json:"contact"`
+	Message string `json:"message"`
+	Listing struct {
+		ProviderListingID listingRef `json:"providerListingId"`
+		MLSNumber         string     `json:"mlsNumber"`
+		Address           string     `json:"address"`
+	} `json:"listing"`
+}
+
+func parseZillow(body []byte) (*Lead, error) {
+	var payload zillowLead
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	propertyID, publicID := payload.Listing.ProviderListingID.parse()
+	return &Lead{
+		ExternalID:       payload.LeadID,
+		Name:             payload.Contact.Name,
+		Email:            payload.Contact.Email,
+		Phone:            payload.Contact.Phone,
+		Message:          payload.Message,
+		PropertyID:       propertyID,
+		PropertyPublicID: publicID,
+		MLSNumber:        payload.Listing.MLSNumber,
+		Address:          payload.Listing.Address,
+	}, nil
+}
+
+// facebookLead is a Facebook Lead Ads lead, with the form answers as
+// field_data. Forms may add custom property_id, mls_number and address
+// questions.
+type facebookLead struct {
+	ID        string `json:"id"`
+	FieldData []struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	} `json:"field_data"`
+}
+
+func parseFacebook(body []byte) (*Lead, error) {
+	var payload facebookLead
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string)
+	for _, field := range payload.FieldData {
+		if len(field.Values) > 0 {
+			fields[strings.ToLower(field.Name)] = field.Values[0]
+		}
+	}
+
+	name := fields["full_name"]
+	if name == "" {
+		name = strings.TrimSpace(fields["first_name"] + " " + fields["last_name"])
+	}
+	propertyID, publicID := listingRef(fields["property_id"]).parse()
+	return &Lead{
+		ExternalID:       payload.ID,
+		Name:             name,
+		Email:            fields["email"],
+		Phone:            fields["phone_number"],
+		Message:          fields["message"],
+		PropertyID:       propertyID,
+		PropertyPublicID: publicID,
+		MLSNumber:        fields["mls_number"],
+		Address:          fields["address"],
+	}, nil
+}
+
+// websiteLead is posted by our own landing pages and contact forms
+type websiteLead struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Phone      string     `json:"phone"`
+	Message    string     `json:"message"`
+	PropertyID listingRef `json:"property_id"`
+}
+
+func parseWebsite(body []byte) (*Lead, error) {
+	var payload websiteLead
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	propertyID, publicID := payload.PropertyID.parse()
+	return &Lead{
+		ExternalID:       payload.ID,
+		Name:             payload.Name,
+		Email:            payload.Email,
+		Phone:            payload.Phone,
+		Message:          payload.Message,
+		PropertyID:       propertyID,
+		PropertyPublicID: publicID,
+	}, nil
+}
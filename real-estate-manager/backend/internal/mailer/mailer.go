@@ -0,0 +1,206 @@
+// Package mailer sends transactional email such as login links and
+// digests. The provider is chosen with MAIL_PROVIDER; the default logs
+// who messages are for instead of sending them, which is convenient in
+// development.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain-text body and an optional HTML
+// alternative
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	HTML    string
+}
+
+// Mailer delivers messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Checker is implemented by mailers that can verify their provider is
+// reachable without sending a message
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Check verifies m's provider is reachable. Mailers that cannot be checked
+// pass.
+func Check(ctx context.Context, m Mailer) error {
+	if checker, ok := m.(Checker); ok {
+		return checker.Check(ctx)
+	}
+	return nil
+}
+
+// NewFromEnv builds the mailer selected by MAIL_PROVIDER: "log" (default),
+// "smtp" (SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD), "sendgrid"
+// (SENDGRID_API_KEY) or "ses" (SES_REGION or AWS_REGION and the AWS_*
+// credentials). MAIL_FROM sets the sender address. Providers that send
+// email retry temporary failures MAIL_MAX_ATTEMPTS times (default 3).
+func NewFromEnv() (Mailer, error) {
+	from := getEnv("MAIL_FROM", "no-reply@localhost")
+	var provider Mailer
+	switch name := strings.ToLower(getEnv("MAIL_PROVIDER", "log")); name {
+	case "log":
+		return LogMailer{}, nil
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp mail provider")
+		}
+		provider = &SMTPMailer{
+			Addr:     net.JoinHostPort(host, getEnv("SMTP_PORT", "587")),
+			Host:     host,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}
+	case "sendgrid":
+		apiKey := os.Getenv("SENDGRID_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("SENDGRID_API_KEY is required for the sendgrid mail provider")
+		}
+		provider = NewSendGridMailer(apiKey, from, os.Getenv("SENDGRID_ENDPOINT"))
+	case "ses":
+		region := getEnv("SES_REGION", os.Getenv("AWS_REGION"))
+		if region == "" {
+			return nil, fmt.Errorf("SES_REGION or AWS_REGION is required for the ses mail provider")
+		}
+		provider = NewSESMailer(SESConfig{
+			Region:          region,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Endpoint:        os.Getenv("SES_ENDPOINT"),
+			From:            from,
+		})
+	default:
+		return nil, fmt.Errorf("unknown MAIL_PROVIDER %q", name)
+	}
+
+	attempts, err := strconv.Atoi(getEnv("MAIL_MAX_ATTEMPTS", "3"))
+	if err != nil || attempts < 1 {
+		return nil, fmt.Errorf("MAIL_MAX_ATTEMPTS must be a positive integer")
+	}
+	return WithRetry(provider, attempts, time.Second), nil
+}
+
+// LogMailer writes the recipient and subject of messages to the server log
+// instead of sending them. Bodies are left out, since they carry login and
+// password reset links.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Email to %s: %s", msg.To, msg.Subject)
+	return nil
+}
+
+// SMTPMailer sends messages through an SMTP server using PLAIN auth when a
+// username is set. Messages with HTML are sent as multipart/alternative.
+type SMTPMailer struct {
+	Addr     string
+	Host     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	headers := []string{
+		"From: " + headerValue(m.From),
+		"To: " + headerValue(msg.To),
+		"Subject: " + headerValue(msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+	}
+	body, contentType := smtpBody(msg)
+	message := strings.Join(append(headers, "Content-Type: "+contentType, "", body), "\r\n")
+
+	if err := smtp.SendMail(m.Addr, auth, m.From, []string{msg.To}, []byte(message)); err != nil {
+		return classifySMTPError(err)
+	}
+	return nil
+}
+
+// Check connects to the SMTP server and waits for its greeting
+func (m *SMTPMailer) Check(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	return client.Quit()
+}
+
+// smtpBody returns the message body and its content type
+func smtpBody(msg Message) (string, string) {
+	if msg.HTML == "" {
+		return msg.Body, "text/plain; charset=UTF-8"
+	}
+
+	var body strings.Builder
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Body},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		w, _ := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		w.Write([]byte(part.content))
+	}
+	writer.Close()
+	return body.String(), "multipart/alternative; boundary=" + writer.Boundary()
+}
+
+// classifySMTPError marks 5xx replies as permanent; 550-553 reject the
+// recipient itself. Other failures (4xx, network) are temporary.
+func classifySMTPError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return &DeliveryError{
+			Err:      fmt.Errorf("failed to send email: %w", err),
+			Rejected: reply.Code >= 550 && reply.Code <= 553,
+		}
+	}
+	return &DeliveryError{Err: fmt.Errorf("failed to send email: %w", err), Temporary: true}
+}
+
+// headerValue strips line breaks so values cannot inject extra headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,210 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedMailer fails with errs in order, then succeeds
+type scriptedMailer struct {
+	errs  []error
+	calls int
+}
+
+func (m *scriptedMailer) Send(ctx context.Context, msg Message) error {
+	m.calls++
+	if m.calls <= len(m.errs) {
+		return m.errs[m.calls-1]
+	}
+	return nil
+}
+
+type memorySuppressionList map[string]string
+
+func (l memorySuppressionList) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	_, ok := l[email]
+	return ok, nil
+}
+
+func (l memorySuppressionList) Suppress(ctx context.Context, email, reason string) error {
+	l[email] = reason
+	return nil
+}
+
+func TestRender(t *testing.T) {
+	msg, err := Render("agent@example.com", "magic_link", map[string]any{
+		"Username":  "jane",
+		"ExpiresIn": "15m0s",
+		"URL":       "https://api.example.com/callback?token=a&b=<c>",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.To != "agent@example.com" || msg.Subject != "Your sign-in link" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if !strings.Contains(msg.Body, "https://api.example.com/callback?token=a&b=<c>") {
+		t.Errorf("expected the raw link in the text body, got %q", msg.Body)
+	}
+	if !strings.Contains(msg.HTML, "<!DOCTYPE html>") || strings.Contains(msg.HTML, "<c>") {
+		t.Errorf("expected an escaped HTML body inside the layout, got %q", msg.HTML)
+	}
+
+	if _, err := Render("agent@example.com", "missing", nil); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
+
+func TestLogMailer_Send(t *testing.T) {
+	var output strings.Builder
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	err := LogMailer{}.Send(context.Background(), Message{To: "ana@example.com", Subject: "Your sign-in link",
+		Body: "https://app.example.com/login?token=secret"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output.String(), "ana@example.com") || strings.Contains(output.String(), "token=secret") {
+		t.Errorf("Expected the recipient and no body in the log, got %q", output.String())
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	temporary := &DeliveryError{Err: errors.New("throttled"), Temporary: true}
+	permanent := &DeliveryError{Err: errors.New("rejected")}
+
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectError   bool
+	}{
+		{name: "succeeds after temporary failures", errs: []error{temporary, errors.New("connection reset")}, expectedCalls: 3},
+		{name: "gives up after the last attempt", errs: []error{temporary, temporary, temporary}, expectedCalls: 3, expectError: true},
+		{name: "does not retry permanent failures", errs: []error{permanent}, expectedCalls: 1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &scriptedMailer{errs: tt.errs}
+			err := WithRetry(next, 3, time.Millisecond).Send(context.Background(), Message{To: "a@example.com"})
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+			if next.calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, next.calls)
+			}
+		})
+	}
+}
+
+func TestWithSuppression(t *testing.T) {
+	list := memorySuppressionList{"bounced@example.com": "550 no such user"}
+
+	next := &scriptedMailer{}
+	mail := WithSuppression(next, list)
+	if err := mail.Send(context.Background(), Message{To: "Bounced@Example.com"}); err != nil {
+		t.Fatalf("expected suppressed messages to be skipped silently, got %v", err)
+	}
+	if next.calls != 0 {
+		t.Errorf("expected no delivery to a suppressed address, got %d", next.calls)
+	}
+
+	next = &scriptedMailer{errs: []error{&DeliveryError{Err: errors.New("550 mailbox unavailable"), Rejected: true}}}
+	mail = WithSuppression(next, list)
+	if err := mail.Send(context.Background(), Message{To: "new@example.com"}); err == nil {
+		t.Fatal("expected the rejection to be returned")
+	}
+	if _, ok := list["new@example.com"]; !ok {
+		t.Error("expected the rejected address to be suppressed")
+	}
+
+	next = &scriptedMailer{errs: []error{&DeliveryError{Err: errors.New("timeout"), Temporary: true}}}
+	WithSuppression(next, list).Send(context.Background(), Message{To: "slow@example.com"})
+	if _, ok := list["slow@example.com"]; ok {
+		t.Error("expected temporary failures not to suppress the address")
+	}
+}
+
+func TestSendGridMailer_Send(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		response      string
+		expectError   bool
+		expectRetry   bool
+		expectRejects bool
+	}{
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "throttled", status: http.StatusTooManyRequests, expectError: true, expectRetry: true},
+		{name: "bad recipient", status: http.StatusBadRequest, response: `{"errors":[{"field":"personalizations.0.to.0.email"}]}`,
+			expectError: true, expectRejects: true},
+		{name: "bad API key", status: http.StatusUnauthorized, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received sendGridRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer key" {
+					t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+				}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &received)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			mail := NewSendGridMailer("key", "no-reply@example.com", server.URL)
+			err := mail.Send(context.Background(), Message{To: "a@example.com", Subject: "Hi", Body: "text", HTML: "<p>html</p>"})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expected error %v, got %v", tt.expectError, err)
+			}
+			if err != nil {
+				if isTemporary(err) != tt.expectRetry {
+					t.Errorf("expected temporary %v, got %v", tt.expectRetry, isTemporary(err))
+				}
+				if IsRejected(err) != tt.expectRejects {
+					t.Errorf("expected rejected %v, got %v", tt.expectRejects, IsRejected(err))
+				}
+			}
+			if len(received.Content) != 2 || received.Content[0].Type != "text/plain" || received.Personalizations[0].To[0].Email != "a@example.com" {
+				t.Errorf("unexpected request: %+v", received)
+			}
+		})
+	}
+}
+
+func TestSESMailer_Send(t *testing.T) {
+	var path, authorization string
+	var received sesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, authorization = r.URL.Path, r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	mail := NewSESMailer(SESConfig{Region: "us-east-1", AccessKeyID: "AKID", SecretAccessKey: "secret", Endpoint: server.URL, From: "no-reply@example.com"})
+	if err := mail.Send(context.Background(), Message{To: "a@example.com", Subject: "Hi", Body: "text"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/v2/email/outbound-emails" || !strings.Contains(authorization, "/us-east-1/ses/aws4_request") {
+		t.Errorf("unexpected request to %s with %q", path, authorization)
+	}
+	if received.Destination.ToAddresses[0] != "a@example.com" || received.Content.Simple.Body.HTML != nil {
+		t.Errorf("unexpected request body: %+v", received)
+	}
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// DeliveryError describes a message a provider did not accept
+type DeliveryError struct {
+	Err error
+	// Temporary failures (throttling, outages) may succeed when retried
+	Temporary bool
+	// Rejected means the provider refused the recipient address, e.g. it
+	// does not exist or has bounced before
+	Rejected bool
+}
+
+func (e *DeliveryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// isTemporary reports whether err is worth retrying. Errors that are not
+// DeliveryErrors come from the network and are treated as temporary.
+func isTemporary(err error) bool {
+	var delivery *DeliveryError
+	if errors.As(err, &delivery) {
+		return delivery.Temporary
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsRejected reports whether err means the recipient address was refused
+func IsRejected(err error) bool {
+	var delivery *DeliveryError
+	return errors.As(err, &delivery) && delivery.Rejected
+}
+
+type retryingMailer struct {
+	next     Mailer
+	attempts int
+	backoff  time.Duration
+}
+
+func (m *retryingMailer) Check(ctx context.Context) error {
+	return Check(ctx, m.next)
+}
+
+// WithRetry wraps next so temporary failures are retried up to attempts
+// times in total, doubling the wait from backoff between attempts
+func WithRetry(next Mailer, attempts int, backoff time.Duration) Mailer {
+	return &retryingMailer{next: next, attempts: attempts, backoff: backoff}
+}
+
+func (m *retryingMailer) Send(ctx context.Context, msg Message) error {
+	wait := m.backoff
+	for attempt := 1; ; attempt++ {
+		err := m.next.Send(ctx, msg)
+		if err == nil || attempt >= m.attempts || !isTemporary(err) {
+			return err
+		}
+
+		log.Printf("Email to %s failed (attempt %d of %d), retrying in %s: %v", msg.To, attempt, m.attempts, wait, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
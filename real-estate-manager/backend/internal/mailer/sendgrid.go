@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridMailer sends messages through the SendGrid v3 mail API
+type SendGridMailer struct {
+	apiKey   string
+	from     string
+	endpoint string
+	client   *http.Client
+}
+
+// NewSendGridMailer creates the mailer; endpoint overrides the API URL and
+// may be empty
+func NewSendGridMailer(apiKey, from, endpoint string) *SendGridMailer {
+	if endpoint == "" {
+		endpoint = sendGridEndpoint
+	}
+	return &SendGridMailer{apiKey: apiKey, from: from, endpoint: endpoint, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+func (m *SendGridMailer) Send(ctx context.Context, msg Message) error {
+	request := sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: msg.To}}}},
+		From:             sendGridAddress{Email: m.from},
+		Subject:          msg.Subject,
+		// SendGrid requires text/plain to come before text/html
+		Content: []sendGridContent{{Type: "text/plain", Value: msg.Body}},
+	}
+	if msg.HTML != "" {
+		request.Content = append(request.Content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create SendGrid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	failure := httpDeliveryError("SendGrid", resp.StatusCode, detail)
+	// A 400 naming the recipient field means SendGrid refused the address
+	if resp.StatusCode == http.StatusBadRequest && strings.Contains(string(detail), "personalizations.0.to") {
+		failure.Rejected = true
+	}
+	return failure
+}
+
+// httpDeliveryError wraps a failed API response; throttling and server
+// errors are temporary
+func httpDeliveryError(provider string, status int, detail []byte) *DeliveryError {
+	return &DeliveryError{
+		Err:       fmt.Errorf("%s returned status %d: %s", provider, status, strings.TrimSpace(string(detail))),
+		Temporary: status == http.StatusTooManyRequests || status >= 500,
+	}
+}
@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/pkg/awsauth"
+)
+
+// SESConfig describes the Amazon SES account used to send email
+type SESConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // optional override, e.g. for LocalStack
+	From            string
+}
+
+// SESMailer sends messages through the SES v2 SendEmail API
+type SESMailer struct {
+	config SESConfig
+	client *http.Client
+}
+
+func NewSESMailer(config SESConfig) *SESMailer {
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", config.Region)
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	return &SESMailer{config: config, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesBody struct {
+	Text *sesContent `json:"Text,omitempty"`
+	HTML *sesContent `json:"Html,omitempty"`
+}
+
+type sesRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    sesBody    `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+func (m *SESMailer) Send(ctx context.Context, msg Message) error {
+	var request sesRequest
+	request.FromEmailAddress = m.config.From
+	request.Destination.ToAddresses = []string{msg.To}
+	request.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	request.Content.Simple.Body.Text = &sesContent{Data: msg.Body, Charset: "UTF-8"}
+	if msg.HTML != "" {
+		request.Content.Simple.Body.HTML = &sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.config.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create SES request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awsauth.SignRequest(req, body, awsauth.Credentials{
+		AccessKeyID:     m.config.AccessKeyID,
+		SecretAccessKey: m.config.SecretAccessKey,
+		SessionToken:    m.config.SessionToken,
+	}, m.config.Region, "ses", time.Now())
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	failure := httpDeliveryError("SES", resp.StatusCode, detail)
+	// SES reports bounces asynchronously; synchronously it only refuses
+	// addresses that are malformed
+	if resp.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(string(detail)), "illegal address") {
+		failure.Rejected = true
+	}
+	return failure
+}
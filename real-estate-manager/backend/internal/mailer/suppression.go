@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// SuppressionList records addresses that must not receive email, such as
+// ones that bounced
+type SuppressionList interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	Suppress(ctx context.Context, email, reason string) error
+}
+
+type suppressingMailer struct {
+	next Mailer
+	list SuppressionList
+}
+
+// WithSuppression wraps next so messages to suppressed addresses are
+// skipped, and addresses the provider rejects are added to list
+func WithSuppression(next Mailer, list SuppressionList) Mailer {
+	return &suppressingMailer{next: next, list: list}
+}
+
+func (m *suppressingMailer) Check(ctx context.Context) error {
+	return Check(ctx, m.next)
+}
+
+func (m *suppressingMailer) Send(ctx context.Context, msg Message) error {
+	email := strings.ToLower(strings.TrimSpace(msg.To))
+	suppressed, err := m.list.IsSuppressed(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to check suppression list: %w", err)
+	}
+	if suppressed {
+		log.Printf("Skipping email to suppressed address %s: %s", email, msg.Subject)
+		return nil
+	}
+
+	err = m.next.Send(ctx, msg)
+	if IsRejected(err) {
+		if suppressErr := m.list.Suppress(ctx, email, err.Error()); suppressErr != nil {
+			log.Printf("Failed to suppress %s: %v", email, suppressErr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Templates are pairs of files under templates/: <name>.txt holds the
+// plain-text body and defines a "subject" block, and <name>.html holds the
+// HTML body as a "content" block rendered inside layout.html.
+//
+//go:embed templates
+var templateFiles embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]emailTemplate {
+	names, err := fs.Glob(templateFiles, "templates/*.txt")
+	if err != nil {
+		panic(err)
+	}
+
+	parsed := make(map[string]emailTemplate, len(names))
+	for _, file := range names {
+		name := strings.TrimSuffix(path.Base(file), ".txt")
+		parsed[name] = emailTemplate{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFiles, file)),
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/layout.html", "templates/"+name+".html")),
+		}
+	}
+	return parsed
+}
+
+// Render builds a message to to from the named template and data
+func Render(to, name string, data any) (Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout.html", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s HTML: %w", name, err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimLeft(text.String(), "\n"),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditImpersonation records every request made with an impersonation
+// token. It runs before the route's AuthMiddleware and inspects the claims
+// once the request has been handled.
+func AuditImpersonation(audit *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatorID, ok := ImpersonatorID(c)
+		if !ok {
+			return
+		}
+
+		details, _ := json.Marshal(map[string]interface{}{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+			"route":  c.FullPath(),
+			"status": c.Writer.Status(),
+		})
+		entry := &models.AuditEntry{
+			Action:         models.AuditImpersonatedRequest,
+			ImpersonatorID: nullID(impersonatorID),
+			Details:        details,
+		}
+		if userID, ok := CurrentUserID(c); ok {
+			entry.ActorID = nullID(userID)
+		}
+		if requestID := GetRequestID(c); requestID != "" {
+			entry.RequestID.String, entry.RequestID.Valid = requestID, true
+		}
+
+		if err := audit.Record(c.Request.Context(), entry); err != nil {
+			log.Printf("Failed to audit impersonated request %s %s by admin %d: %v",
+				c.Request.Method, c.Request.URL.Path, impersonatorID, err)
+		}
+	}
+}
+
+func nullID(id uint) models.NullInt32 {
+	var n models.NullInt32
+	n.Int32, n.Valid = int32(id), true
+	return n
+}
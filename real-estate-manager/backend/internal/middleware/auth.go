@@ -2,6 +2,9 @@ package middleware
 
 import (
 	"net/http"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
 	"real-estate-manager/backend/internal/services"
 	"strings"
 
@@ -12,7 +15,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			envelope.Error(c, http.StatusUnauthorized, "Authorization header required")
 			c.Abort()
 			return
 		}
@@ -22,7 +25,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			envelope.Error(c, http.StatusUnauthorized, "Invalid token")
 			c.Abort()
 			return
 		}
@@ -30,7 +33,149 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		// Set user info in context
 		c.Set("user_id", (*claims)["user_id"])
 		c.Set("username", (*claims)["username"])
+		c.Set("role", (*claims)["role"])
+		if orgID, ok := (*claims)["org_id"]; ok {
+			c.Set("org_id", orgID)
+		}
+		if impersonatorID, ok := (*claims)["impersonator_id"]; ok {
+			c.Set("impersonator_id", impersonatorID)
+			c.Set("impersonator", (*claims)["impersonator"])
+		}
+		if scopes, ok := (*claims)["scopes"]; ok {
+			c.Set("scopes", claimStrings(scopes))
+		}
+		scopes, _ := TokenScopes(c)
+		ctx := services.WithPrincipal(c.Request.Context(), services.Principal{
+			Role:           c.GetString("role"),
+			OrganizationID: CurrentOrganizationID(c),
+			Scopes:         scopes,
+		})
+		if userID, ok := CurrentUserID(c); ok {
+			ctx = services.WithActor(ctx, userID)
+		}
+		// Admins manage every organization's properties; everyone else is
+		// limited to their own organization's and shared ones
+		if !IsAdmin(c) {
+			ctx = repository.WithTenant(ctx, repository.Tenant{OrganizationID: CurrentOrganizationID(c)})
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects requests from users without the admin role, and
+// from scoped service-account tokens. It must run after AuthMiddleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) {
+			envelope.Error(c, http.StatusForbidden, "Admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission rejects requests whose role does not grant permission,
+// or whose token scopes do not cover it. It must run after AuthMiddleware.
+func RequirePermission(permissions *services.PermissionService, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !permissions.Allowed(c.GetString("role"), CurrentOrganizationID(c), permission) {
+			envelope.Errorf(c, http.StatusForbidden, "Missing permission %s", permission)
+			c.Abort()
+			return
+		}
+		if scopes, scoped := TokenScopes(c); scoped && !services.ScopesAllow(scopes, permission) {
+			envelope.Errorf(c, http.StatusForbidden, "Token scope does not allow %s", permission)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
 
+// RequireFeature hides a route behind a feature flag, responding as if the
+// route did not exist while the flag is off
+func RequireFeature(flags *services.FeatureFlagService, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !flags.IsEnabled(name) {
+			envelope.Error(c, http.StatusNotFound, "Not found")
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// IsAdmin reports whether the authenticated user has the admin role.
+// Scoped tokens never count as admin.
+func IsAdmin(c *gin.Context) bool {
+	_, scoped := TokenScopes(c)
+	return c.GetString("role") == models.RoleAdmin && !scoped
+}
+
+// TokenScopes returns the scopes of a service-account token. ok is false
+// for regular tokens, which are limited by their role alone.
+func TokenScopes(c *gin.Context) ([]string, bool) {
+	value, exists := c.Get("scopes")
+	if !exists {
+		return nil, false
+	}
+	scopes, _ := value.([]string)
+	return scopes, true
+}
+
+// claimStrings converts a JSON array claim to strings. Malformed claims
+// yield an empty, non-nil slice so they grant nothing.
+func claimStrings(value interface{}) []string {
+	strs := []string{}
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// CurrentUserID returns the authenticated user's ID from the JWT claims
+func CurrentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return 0, false
+	}
+	// JWT numeric claims are decoded as float64
+	id, ok := value.(float64)
+	if !ok || id <= 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// CurrentOrganizationID returns the authenticated user's organization, or 0
+// when they do not belong to one
+func CurrentOrganizationID(c *gin.Context) int {
+	value, exists := c.Get("org_id")
+	if !exists {
+		return 0
+	}
+	id, ok := value.(float64)
+	if !ok || id <= 0 {
+		return 0
+	}
+	return int(id)
+}
+
+// ImpersonatorID returns the admin acting through an impersonation token
+func ImpersonatorID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("impersonator_id")
+	if !exists {
+		return 0, false
+	}
+	id, ok := value.(float64)
+	if !ok || id <= 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
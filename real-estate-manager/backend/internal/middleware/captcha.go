@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CaptchaHeader carries a solved CAPTCHA token
+const CaptchaHeader = "X-Captcha-Token"
+
+// BruteForceProtection guards credential endpoints with guard. Client
+// errors other than the guard's own count as failed attempts from the
+// caller's IP; once over the threshold, requests must carry a CAPTCHA token.
+// The IP is the connecting address unless the router trusts the proxy it
+// came through, so a forged X-Forwarded-For cannot spread attempts over
+// made-up addresses.
+func BruteForceProtection(guard *services.LoginGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		if err := guard.Check(c.Request.Context(), ip, c.GetHeader(CaptchaHeader)); err != nil {
+			status := apperrors.HTTPStatus(err)
+			switch {
+			case errors.Is(err, services.ErrCaptchaRequired), errors.Is(err, services.ErrCaptchaInvalid):
+				envelope.ErrorDetails(c, status, err.Error(), gin.H{"captcha_required": true})
+			case status == http.StatusInternalServerError:
+				log.Printf("CAPTCHA verification for %s failed: %v", ip, err)
+				envelope.Error(c, http.StatusServiceUnavailable, "CAPTCHA verification unavailable")
+			default:
+				envelope.Error(c, status, err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if status := c.Writer.Status(); status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+			guard.Failed(ip)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// OriginList is the set of origins allowed to make cross-origin requests.
+// It can be replaced while the server runs.
+type OriginList struct {
+	origins atomic.Pointer[[]string]
+}
+
+func NewOriginList(origins []string) *OriginList {
+	l := &OriginList{}
+	l.Set(origins)
+	return l
+}
+
+// Set replaces the allowed origins. Blank entries and trailing slashes are
+// ignored.
+func (l *OriginList) Set(origins []string) {
+	cleaned := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			cleaned = append(cleaned, strings.ToLower(origin))
+		}
+	}
+	l.origins.Store(&cleaned)
+}
+
+// Allow reports whether origin may make cross-origin requests. It is meant
+// for cors.Config.AllowOriginFunc. There is no wildcard, since requests
+// carry credentials.
+func (l *OriginList) Allow(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range *l.origins.Load() {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
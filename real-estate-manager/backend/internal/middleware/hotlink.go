@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HotlinkProtection rejects requests whose Referer is another site unless
+// its host is in allowedHosts. Entries may start with "*." to allow every
+// subdomain. Requests without a Referer and from the serving host itself
+// are always allowed, so direct links and privacy-conscious browsers work.
+func HotlinkProtection(allowedHosts []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		referer := c.GetHeader("Referer")
+		if referer == "" {
+			c.Next()
+			return
+		}
+
+		parsed, err := url.Parse(referer)
+		if err != nil || parsed.Hostname() == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Hotlinking is not allowed"})
+			return
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if host == requestHost(c.Request) || hostAllowed(host, allowedHosts) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Hotlinking is not allowed"})
+	}
+}
+
+// requestHost returns the lowercased host the request was sent to, without
+// the port
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
+
+func hostAllowed(host string, allowedHosts []string) bool {
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
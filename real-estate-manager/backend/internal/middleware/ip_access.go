@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"real-estate-manager/backend/internal/envelope"
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPDenylist rejects requests from the ranges in the ip_denylist setting
+// with 403, before they reach authentication
+func IPDenylist(access *services.IPAccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if access.Denied(c.ClientIP()) {
+			envelope.Error(c, http.StatusForbidden, "Access denied")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminIPAllowlist limits a route group to the ranges in the
+// admin_ip_allowlist setting, when set. It runs before authentication, so
+// other addresses cannot even try admin credentials.
+func AdminIPAllowlist(access *services.IPAccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !access.AdminAllowed(c.ClientIP()) {
+			envelope.Error(c, http.StatusForbidden, "Access denied")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
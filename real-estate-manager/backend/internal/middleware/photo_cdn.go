@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"real-estate-manager/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const photoCDNKey = "photo_cdn"
+
+// PhotoCDN makes cdn available to the handlers that return photos, which
+// rewrite their URLs with it
+func PhotoCDN(cdn *services.PhotoCDN) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(photoCDNKey, cdn)
+		c.Next()
+	}
+}
+
+// CurrentPhotoCDN returns the CDN set by PhotoCDN, or nil, which leaves
+// photo URLs alone
+func CurrentPhotoCDN(c *gin.Context) *services.PhotoCDN {
+	cdn, _ := c.Value(photoCDNKey).(*services.PhotoCDN)
+	return cdn
+}
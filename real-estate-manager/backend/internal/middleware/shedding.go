@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"real-estate-manager/backend/internal/envelope"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shedRetryAfter is how many seconds a shed request is told to wait
+const shedRetryAfter = 5
+
+// LoadShedder turns low-priority requests away while the server is
+// overloaded, so polling and analytics cannot starve CRUD traffic or the
+// database during spikes. The server is overloaded when more than limit
+// requests are in flight or when saturated reports true, e.g. because
+// requests are queueing for database connections.
+type LoadShedder struct {
+	inFlight  atomic.Int64
+	limit     int64
+	saturated func() bool
+}
+
+// NewLoadShedder creates a shedder; a limit of 0 ignores the number of
+// requests in flight and saturated may be nil
+func NewLoadShedder(limit int, saturated func() bool) *LoadShedder {
+	return &LoadShedder{limit: int64(limit), saturated: saturated}
+}
+
+// Track counts the requests in flight; it must run for every request
+func (s *LoadShedder) Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		c.Next()
+	}
+}
+
+// LowPriority rejects the route's requests with 503 and Retry-After while
+// the server is overloaded
+func (s *LoadShedder) LowPriority() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.Overloaded() {
+			c.Next()
+			return
+		}
+		c.Header("Retry-After", strconv.Itoa(shedRetryAfter))
+		envelope.Error(c, http.StatusServiceUnavailable, "The server is busy, try again shortly")
+		c.Abort()
+	}
+}
+
+// Overloaded reports whether low-priority requests are being shed
+func (s *LoadShedder) Overloaded() bool {
+	if s.limit > 0 && s.inFlight.Load() > s.limit {
+		return true
+	}
+	return s.saturated != nil && s.saturated()
+}
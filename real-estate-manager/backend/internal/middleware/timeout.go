@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"real-estate-manager/backend/internal/envelope"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout gives every request a deadline: the one routes lists for its
+// route pattern (as registered, e.g. "/api/properties/export"), or
+// defaultTimeout. The deadline is set on the request context, so database
+// queries and outbound calls made with it are cancelled once it passes,
+// and a request that runs out of time is answered with 504 and its request
+// ID unless the handler already responded.
+func Timeout(defaultTimeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := routes[c.FullPath()]
+		if !ok {
+			timeout = defaultTimeout
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		log.Printf("%s %s timed out after %s (request %s)", c.Request.Method, c.FullPath(), timeout, GetRequestID(c))
+		if !c.Writer.Written() {
+			RespondTimeout(c)
+		}
+	}
+}
+
+// TimedOut reports whether the request ran out of time
+func TimedOut(c *gin.Context) bool {
+	return errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// RespondTimeout answers a request that ran out of time with 504 and its
+// request ID, so the slow request can be found in the logs
+func RespondTimeout(c *gin.Context) {
+	envelope.ErrorDetails(c, http.StatusGatewayTimeout, "The request took too long", gin.H{"request_id": GetRequestID(c)})
+	c.Abort()
+}
@@ -11,8 +11,11 @@ package mocks
 
 import (
 	context "context"
+	sql "database/sql"
 	models "real-estate-manager/backend/internal/models"
+	repository "real-estate-manager/backend/internal/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +44,37 @@ func (m *MockPropertyRepository) EXPECT() *MockPropertyRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ApplyBatch mocks base method.
+func (m *MockPropertyRepository) ApplyBatch(ctx context.Context, mutations []models.PropertyMutation, revision *repository.Revision) ([]models.MutationResult, []models.SyncConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", ctx, mutations, revision)
+	ret0, _ := ret[0].([]models.MutationResult)
+	ret1, _ := ret[1].([]models.SyncConflict)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockPropertyRepositoryMockRecorder) ApplyBatch(ctx, mutations, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockPropertyRepository)(nil).ApplyBatch), ctx, mutations, revision)
+}
+
+// BulkUpdate mocks base method.
+func (m *MockPropertyRepository) BulkUpdate(ctx context.Context, filter models.PropertyFilter, patch models.PropertyPatch, dryRun bool, revision *repository.Revision) (*models.BulkUpdateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdate", ctx, filter, patch, dryRun, revision)
+	ret0, _ := ret[0].(*models.BulkUpdateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdate indicates an expected call of BulkUpdate.
+func (mr *MockPropertyRepositoryMockRecorder) BulkUpdate(ctx, filter, patch, dryRun, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdate", reflect.TypeOf((*MockPropertyRepository)(nil).BulkUpdate), ctx, filter, patch, dryRun, revision)
+}
+
 // Create mocks base method.
 func (m *MockPropertyRepository) Create(ctx context.Context, property *models.Property) error {
 	m.ctrl.T.Helper()
@@ -69,6 +103,66 @@ func (mr *MockPropertyRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPropertyRepository)(nil).Delete), ctx, id)
 }
 
+// Facets mocks base method.
+func (m *MockPropertyRepository) Facets(ctx context.Context, search models.PropertySearch) (*models.PropertyFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Facets", ctx, search)
+	ret0, _ := ret[0].(*models.PropertyFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Facets indicates an expected call of Facets.
+func (mr *MockPropertyRepositoryMockRecorder) Facets(ctx, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Facets", reflect.TypeOf((*MockPropertyRepository)(nil).Facets), ctx, search)
+}
+
+// FindExpired mocks base method.
+func (m *MockPropertyRepository) FindExpired(ctx context.Context, now time.Time) ([]models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpired", ctx, now)
+	ret0, _ := ret[0].([]models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpired indicates an expected call of FindExpired.
+func (mr *MockPropertyRepositoryMockRecorder) FindExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpired", reflect.TypeOf((*MockPropertyRepository)(nil).FindExpired), ctx, now)
+}
+
+// FindExpiring mocks base method.
+func (m *MockPropertyRepository) FindExpiring(ctx context.Context, before time.Time) ([]models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiring", ctx, before)
+	ret0, _ := ret[0].([]models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiring indicates an expected call of FindExpiring.
+func (mr *MockPropertyRepositoryMockRecorder) FindExpiring(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiring", reflect.TypeOf((*MockPropertyRepository)(nil).FindExpiring), ctx, before)
+}
+
+// FindStaleCandidates mocks base method.
+func (m *MockPropertyRepository) FindStaleCandidates(ctx context.Context, cutoff time.Time) ([]models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStaleCandidates", ctx, cutoff)
+	ret0, _ := ret[0].([]models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStaleCandidates indicates an expected call of FindStaleCandidates.
+func (mr *MockPropertyRepositoryMockRecorder) FindStaleCandidates(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStaleCandidates", reflect.TypeOf((*MockPropertyRepository)(nil).FindStaleCandidates), ctx, cutoff)
+}
+
 // GetAll mocks base method.
 func (m *MockPropertyRepository) GetAll(ctx context.Context) ([]models.Property, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +178,21 @@ func (mr *MockPropertyRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPropertyRepository)(nil).GetAll), ctx)
 }
 
+// GetByExternalID mocks base method.
+func (m *MockPropertyRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalID", ctx, externalID)
+	ret0, _ := ret[0].(*models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalID indicates an expected call of GetByExternalID.
+func (mr *MockPropertyRepositoryMockRecorder) GetByExternalID(ctx, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalID", reflect.TypeOf((*MockPropertyRepository)(nil).GetByExternalID), ctx, externalID)
+}
+
 // GetByID mocks base method.
 func (m *MockPropertyRepository) GetByID(ctx context.Context, id int) (*models.Property, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +208,108 @@ func (mr *MockPropertyRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPropertyRepository)(nil).GetByID), ctx, id)
 }
 
+// GetByMLSNumber mocks base method.
+func (m *MockPropertyRepository) GetByMLSNumber(ctx context.Context, mlsNumber string) (*models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByMLSNumber", ctx, mlsNumber)
+	ret0, _ := ret[0].(*models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByMLSNumber indicates an expected call of GetByMLSNumber.
+func (mr *MockPropertyRepositoryMockRecorder) GetByMLSNumber(ctx, mlsNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByMLSNumber", reflect.TypeOf((*MockPropertyRepository)(nil).GetByMLSNumber), ctx, mlsNumber)
+}
+
+// GetByPublicID mocks base method.
+func (m *MockPropertyRepository) GetByPublicID(ctx context.Context, publicID string) (*models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPublicID", ctx, publicID)
+	ret0, _ := ret[0].(*models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPublicID indicates an expected call of GetByPublicID.
+func (mr *MockPropertyRepositoryMockRecorder) GetByPublicID(ctx, publicID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPublicID", reflect.TypeOf((*MockPropertyRepository)(nil).GetByPublicID), ctx, publicID)
+}
+
+// ListAfter mocks base method.
+func (m *MockPropertyRepository) ListAfter(ctx context.Context, afterID, limit int) ([]models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, afterID, limit)
+	ret0, _ := ret[0].([]models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockPropertyRepositoryMockRecorder) ListAfter(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockPropertyRepository)(nil).ListAfter), ctx, afterID, limit)
+}
+
+// MarkExpired mocks base method.
+func (m *MockPropertyRepository) MarkExpired(ctx context.Context, ids []int, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExpired", ctx, ids, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkExpired indicates an expected call of MarkExpired.
+func (mr *MockPropertyRepositoryMockRecorder) MarkExpired(ctx, ids, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExpired", reflect.TypeOf((*MockPropertyRepository)(nil).MarkExpired), ctx, ids, now)
+}
+
+// MarkExpiryNotified mocks base method.
+func (m *MockPropertyRepository) MarkExpiryNotified(ctx context.Context, ids []int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExpiryNotified", ctx, ids, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkExpiryNotified indicates an expected call of MarkExpiryNotified.
+func (mr *MockPropertyRepositoryMockRecorder) MarkExpiryNotified(ctx, ids, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExpiryNotified", reflect.TypeOf((*MockPropertyRepository)(nil).MarkExpiryNotified), ctx, ids, at)
+}
+
+// MarkStale mocks base method.
+func (m *MockPropertyRepository) MarkStale(ctx context.Context, ids []int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkStale", ctx, ids, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkStale indicates an expected call of MarkStale.
+func (mr *MockPropertyRepositoryMockRecorder) MarkStale(ctx, ids, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStale", reflect.TypeOf((*MockPropertyRepository)(nil).MarkStale), ctx, ids, at)
+}
+
+// Search mocks base method.
+func (m *MockPropertyRepository) Search(ctx context.Context, search models.PropertySearch) ([]models.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, search)
+	ret0, _ := ret[0].([]models.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockPropertyRepositoryMockRecorder) Search(ctx, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPropertyRepository)(nil).Search), ctx, search)
+}
+
 // Update mocks base method.
 func (m *MockPropertyRepository) Update(ctx context.Context, property *models.Property) error {
 	m.ctrl.T.Helper()
@@ -112,3 +323,157 @@ func (mr *MockPropertyRepositoryMockRecorder) Update(ctx, property any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPropertyRepository)(nil).Update), ctx, property)
 }
+
+// UpdateWithRevision mocks base method.
+func (m *MockPropertyRepository) UpdateWithRevision(ctx context.Context, property *models.Property, revision *repository.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWithRevision", ctx, property, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWithRevision indicates an expected call of UpdateWithRevision.
+func (mr *MockPropertyRepositoryMockRecorder) UpdateWithRevision(ctx, property, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithRevision", reflect.TypeOf((*MockPropertyRepository)(nil).UpdateWithRevision), ctx, property, revision)
+}
+
+// Upsert mocks base method.
+func (m *MockPropertyRepository) Upsert(ctx context.Context, property *models.Property) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, property)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockPropertyRepositoryMockRecorder) Upsert(ctx, property any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockPropertyRepository)(nil).Upsert), ctx, property)
+}
+
+// MockrowScanner is a mock of rowScanner interface.
+type MockrowScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockrowScannerMockRecorder
+	isgomock struct{}
+}
+
+// MockrowScannerMockRecorder is the mock recorder for MockrowScanner.
+type MockrowScannerMockRecorder struct {
+	mock *MockrowScanner
+}
+
+// NewMockrowScanner creates a new mock instance.
+func NewMockrowScanner(ctrl *gomock.Controller) *MockrowScanner {
+	mock := &MockrowScanner{ctrl: ctrl}
+	mock.recorder = &MockrowScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrowScanner) EXPECT() *MockrowScannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *MockrowScanner) Scan(dest ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockrowScannerMockRecorder) Scan(dest ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockrowScanner)(nil).Scan), dest...)
+}
+
+// Mockdbtx is a mock of dbtx interface.
+type Mockdbtx struct {
+	ctrl     *gomock.Controller
+	recorder *MockdbtxMockRecorder
+	isgomock struct{}
+}
+
+// MockdbtxMockRecorder is the mock recorder for Mockdbtx.
+type MockdbtxMockRecorder struct {
+	mock *Mockdbtx
+}
+
+// NewMockdbtx creates a new mock instance.
+func NewMockdbtx(ctrl *gomock.Controller) *Mockdbtx {
+	mock := &Mockdbtx{ctrl: ctrl}
+	mock.recorder = &MockdbtxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockdbtx) EXPECT() *MockdbtxMockRecorder {
+	return m.recorder
+}
+
+// ExecContext mocks base method.
+func (m *Mockdbtx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecContext", varargs...)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecContext indicates an expected call of ExecContext.
+func (mr *MockdbtxMockRecorder) ExecContext(ctx, query any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecContext", reflect.TypeOf((*Mockdbtx)(nil).ExecContext), varargs...)
+}
+
+// QueryContext mocks base method.
+func (m *Mockdbtx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryContext", varargs...)
+	ret0, _ := ret[0].(*sql.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryContext indicates an expected call of QueryContext.
+func (mr *MockdbtxMockRecorder) QueryContext(ctx, query any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryContext", reflect.TypeOf((*Mockdbtx)(nil).QueryContext), varargs...)
+}
+
+// QueryRowContext mocks base method.
+func (m *Mockdbtx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryRowContext", varargs...)
+	ret0, _ := ret[0].(*sql.Row)
+	return ret0
+}
+
+// QueryRowContext indicates an expected call of QueryRowContext.
+func (mr *MockdbtxMockRecorder) QueryRowContext(ctx, query any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowContext", reflect.TypeOf((*Mockdbtx)(nil).QueryRowContext), varargs...)
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Activity feed scopes
+const (
+	ActivityScopeMe  = "me"
+	ActivityScopeOrg = "org"
+)
+
+// Activity is a domain event recorded in the activity feed. ActorID is the
+// user who caused it, if any, and OrganizationID the organization it
+// concerns.
+type Activity struct {
+	ID             int64     `json:"id" db:"id"`
+	EventID        string    `json:"event_id" db:"event_id"`
+	Type           string    `json:"type" db:"type"`
+	Subject        string    `json:"subject" db:"subject"`
+	ActorID        NullInt32 `json:"actor_id" db:"actor_id"`
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+	Summary        string    `json:"summary" db:"summary"`
+	OccurredAt     time.Time `json:"occurred_at" db:"occurred_at"`
+}
+
+// ActivityQuery selects a page of the feed, newest first. Exactly one of
+// ActorID and OrganizationID is set. Types holds event types, such as
+// "offer.accepted", or families, such as "offer"; empty matches every
+// type. BeforeID continues after the last activity of a previous page.
+type ActivityQuery struct {
+	ActorID        uint
+	OrganizationID int
+	Types          []string
+	Since          time.Time
+	BeforeID       int64
+	Limit          int
+}
+
+// ActivityPage is a page of the feed. Cursor is passed back as ?before= to
+// fetch the next page and is empty on the last one.
+type ActivityPage struct {
+	Activities []Activity `json:"activities"`
+	Cursor     string     `json:"cursor"`
+	HasMore    bool       `json:"has_more"`
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Normalized HVAC types
+const (
+	HVACCentral  = "central"
+	HVACHeatPump = "heat_pump"
+	HVACWindow   = "window"
+	HVACRadiant  = "radiant"
+	HVACNone     = "none"
+	HVACOther    = "other"
+)
+
+// IsValidHVACType reports whether hvacType is a known normalized HVAC type
+func IsValidHVACType(hvacType string) bool {
+	switch hvacType {
+	case HVACCentral, HVACHeatPump, HVACWindow, HVACRadiant, HVACNone, HVACOther:
+		return true
+	}
+	return false
+}
+
+// Amenities holds the structured features of a property
+type Amenities struct {
+	PropertyID   int         `json:"property_id" db:"property_id"`
+	HasPool      bool        `json:"has_pool" db:"has_pool"`
+	GarageSpaces NullInt32   `json:"garage_spaces" db:"garage_spaces"`
+	HVACType     NullString  `json:"hvac_type" db:"hvac_type"`
+	HOAFee       NullFloat64 `json:"hoa_fee" db:"hoa_fee"`
+	Features     StringList  `json:"features" db:"features"`
+	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// AmenityFilter narrows listing searches by amenities. Nil fields are ignored.
+type AmenityFilter struct {
+	HasPool         *bool
+	MinGarageSpaces *int
+	HVACType        *string
+	MaxHOAFee       *float64
+}
+
+// IsEmpty reports whether no amenity filter is set
+func (f AmenityFilter) IsEmpty() bool {
+	return f.HasPool == nil && f.MinGarageSpaces == nil && f.HVACType == nil && f.MaxHOAFee == nil
+}
+
+// PropertySorts are the orders GET /api/properties accepts; a leading "-"
+// sorts descending
+var PropertySorts = []string{"created_at", "-created_at", "price", "-price", "expires_at", "-expires_at"}
+
+// PropertySearch narrows GET /api/properties
+type PropertySearch struct {
+	// Stale limits results to listings flagged by stale-listing detection
+	Stale bool
+	// Status limits results to listings in that status when set
+	Status string
+	// ExpiringWithinDays limits results to active and pending listings
+	// that expire within that many days when positive
+	ExpiringWithinDays int
+	// ListingType limits results to sales or rentals when set.
+	// MaxMonthlyRent and AvailableBy only match rentals, those available
+	// by that date including ones without an available_from date.
+	ListingType    string
+	MaxMonthlyRent *float64
+	AvailableBy    time.Time
+	// Category limits results to one listing category when set. Zoning
+	// only matches commercial and land listings, MinCapRate only
+	// commercial ones.
+	Category   string
+	Zoning     string
+	MinCapRate *float64
+	// MaxAnnualTax and MinParkingSpaces are ignored when nil
+	MaxAnnualTax     *float64
+	MinParkingSpaces *int
+	// MinPrice and MaxPrice bound the price, and MinBedrooms and
+	// MinBathrooms set the smallest room counts, when not nil
+	MinPrice     *float64
+	MaxPrice     *float64
+	MinBedrooms  *int
+	MinBathrooms *int
+	// PropertyType matches the type exactly and Location part of the
+	// address, when set
+	PropertyType string
+	Location     string
+	// MinYearBuilt and MaxYearBuilt bound the construction year when not
+	// nil; listings with an unknown year never match
+	MinYearBuilt *int
+	MaxYearBuilt *int
+	Amenities    AmenityFilter
+	// Sort is one of PropertySorts; newest first when empty
+	Sort string
+	// Limit splits results into pages of that size when set. Page counts
+	// from 1.
+	Limit int
+	Page  int
+}
+
+// IsEmpty reports whether the search has no criteria
+func (s PropertySearch) IsEmpty() bool {
+	return !s.Stale && s.Status == "" && s.ExpiringWithinDays == 0 && s.ListingType == "" && s.MaxMonthlyRent == nil &&
+		s.AvailableBy.IsZero() && s.Category == "" && s.Zoning == "" && s.MinCapRate == nil && s.MaxAnnualTax == nil &&
+		s.MinParkingSpaces == nil && s.MinPrice == nil && s.MaxPrice == nil && s.MinBedrooms == nil && s.MinBathrooms == nil &&
+		s.PropertyType == "" && s.Location == "" && s.MinYearBuilt == nil && s.MaxYearBuilt == nil && s.Amenities.IsEmpty()
+}
+
+// PropertyFacets counts the listings matching a search by each value of
+// the fields clients narrow searches by. Zoning only counts listings with
+// a zoning.
+type PropertyFacets struct {
+	Category    map[string]int `json:"category"`
+	ListingType map[string]int `json:"listing_type"`
+	Status      map[string]int `json:"status"`
+	Zoning      map[string]int `json:"zoning"`
+}
+
+// StringList is a slice of strings stored as a JSON array
+type StringList []string
+
+// Value implements the driver.Valuer interface for database storage
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan into StringList")
+	}
+
+	return json.Unmarshal(bytes, l)
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// PropertyArchive is a sold or withdrawn listing moved to cold storage.
+// The listing itself, with its photos, is in the compressed archive at
+// ArchivePath until it is unarchived.
+type PropertyArchive struct {
+	PropertyID     int       `json:"id"`
+	PublicID       string    `json:"public_id"`
+	OrganizationID NullInt32 `json:"organization_id"`
+	Name           string    `json:"name"`
+	Location       string    `json:"location"`
+	Status         string    `json:"status"`
+	ListingType    string    `json:"listing_type"`
+	Price          float64   `json:"price"`
+	PhotoCount     int       `json:"photo_count"`
+	ArchivePath    string    `json:"-"`
+	ArchiveBytes   int64     `json:"archive_bytes"`
+	ListedAt       time.Time `json:"listed_at"`
+	LastUpdatedAt  time.Time `json:"last_updated_at"`
+	ArchivedAt     time.Time `json:"archived_at"`
+}
+
+// ArchivedListing is the document kept in a listing's archive: the
+// listing as it was, its metric measurements, which Property leaves out of
+// its JSON, and its amenities
+type ArchivedListing struct {
+	Property      Property    `json:"property"`
+	LivingAreaSqm NullFloat64 `json:"living_area_sqm"`
+	LotAreaSqm    NullFloat64 `json:"lot_area_sqm"`
+	Amenities     *Amenities  `json:"amenities,omitempty"`
+}
+
+// ArchiveRun reports what one run of the listing archive did
+type ArchiveRun struct {
+	Archived int   `json:"archived"`
+	Skipped  int   `json:"skipped"`
+	Bytes    int64 `json:"bytes"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditImpersonationStarted = "impersonation_started"
+	AuditImpersonatedRequest  = "impersonated_request"
+	AuditServiceTokenIssued   = "service_token_issued"
+	AuditLoginSucceeded       = "login_succeeded"
+	AuditLoginFailed          = "login_failed"
+	AuditTokenRevoked         = "token_revoked"
+	AuditDataExported         = "data_exported"
+)
+
+// AuditEntry records a security-relevant action. ImpersonatorID is set when
+// an admin acted as ActorID through an impersonation token.
+type AuditEntry struct {
+	ID             int             `json:"id" db:"id"`
+	Action         string          `json:"action" db:"action"`
+	ActorID        NullInt32       `json:"actor_id" db:"actor_id"`
+	ImpersonatorID NullInt32       `json:"impersonator_id" db:"impersonator_id"`
+	TargetType     NullString      `json:"target_type" db:"target_type"`
+	TargetID       NullString      `json:"target_id" db:"target_id"`
+	Details        json.RawMessage `json:"details,omitempty" db:"details"`
+	RequestID      NullString      `json:"request_id" db:"request_id"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter narrows audit log queries. Zero values are ignored.
+type AuditFilter struct {
+	ActorID        int    `form:"actor_id" binding:"min=0"`
+	ImpersonatorID int    `form:"impersonator_id" binding:"min=0"`
+	Action         string `form:"action"`
+	Limit          int    `form:"limit,default=100" binding:"min=1,max=1000"`
+}
@@ -0,0 +1,40 @@
+package models
+
+// PropertyFilter selects properties for bulk operations. Empty fields are
+// ignored; at least one must be set.
+type PropertyFilter struct {
+	IDs          []int   `json:"ids,omitempty"`
+	AgentID      *int    `json:"agent_id,omitempty"`
+	Status       *string `json:"status,omitempty"`
+	PropertyType *string `json:"property_type,omitempty"`
+}
+
+// IsEmpty reports whether the filter would match every property
+func (f PropertyFilter) IsEmpty() bool {
+	return len(f.IDs) == 0 && f.AgentID == nil && f.Status == nil && f.PropertyType == nil
+}
+
+// PropertyPatch lists the fields a bulk update may set
+type PropertyPatch struct {
+	Status  *string `json:"status,omitempty"`
+	AgentID *int    `json:"agent_id,omitempty"`
+}
+
+// IsEmpty reports whether the patch would change nothing
+func (p PropertyPatch) IsEmpty() bool {
+	return p.Status == nil && p.AgentID == nil
+}
+
+// BulkUpdateRequest is the body of POST /api/properties/bulk-update
+type BulkUpdateRequest struct {
+	Filter PropertyFilter `json:"filter"`
+	Patch  PropertyPatch  `json:"patch"`
+	DryRun bool           `json:"dry_run"`
+}
+
+// BulkUpdateResult reports how many rows a bulk update matched and changed
+type BulkUpdateResult struct {
+	Matched int64 `json:"matched"`
+	Updated int64 `json:"updated"`
+	DryRun  bool  `json:"dry_run"`
+}
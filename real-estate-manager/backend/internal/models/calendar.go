@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// CalendarConnection is an agent's authorized Google or Outlook calendar.
+// The tokens never leave the backend.
+type CalendarConnection struct {
+	UserID       uint       `json:"user_id" db:"user_id"`
+	Provider     string     `json:"provider" db:"provider"`
+	AccessToken  string     `json:"-" db:"access_token"`
+	RefreshToken string     `json:"-" db:"refresh_token"`
+	ExpiresAt    *time.Time `json:"-" db:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
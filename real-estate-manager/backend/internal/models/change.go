@@ -0,0 +1,111 @@
+package models
+
+import "time"
+
+// Entity types reported by the changes feed
+const (
+	ChangeEntityProperty = "property"
+	ChangeEntityPhotos   = "photos"
+	ChangeEntityAgent    = "agent"
+)
+
+// Change operations; a delete is a tombstone and carries no data
+const (
+	ChangeUpsert = "upsert"
+	ChangeDelete = "delete"
+)
+
+// Change is one entry of the changes feed: the current state of an entity
+// modified after the client's cursor, or a tombstone for a deleted one
+type Change struct {
+	Entity    string    `json:"entity"`
+	ID        int       `json:"id"`
+	Operation string    `json:"op"`
+	ChangedAt time.Time `json:"changed_at"`
+	Data      any       `json:"data,omitempty"`
+
+	// Position is where the change sits in the feed, used to build the
+	// next cursor
+	Position ChangePosition `json:"-"`
+}
+
+// ChangePosition orders the changes feed. Changes are sorted by time, then
+// by source table and row key, so a position identifies exactly where a
+// page ended even when many rows share a timestamp.
+type ChangePosition struct {
+	At     time.Time
+	Source int
+	Key    int
+}
+
+// PropertyPhotos is the photo set of a property, reported as its own entity
+// so clients can sync images separately from listing details
+type PropertyPhotos struct {
+	PropertyID int       `json:"property_id"`
+	Photos     PhotoList `json:"photos"`
+}
+
+// Agent is the public profile of a user assigned to listings
+type Agent struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ChangesPage is a page of the changes feed. Cursor resumes after the last
+// change; HasMore reports whether another page is already available.
+type ChangesPage struct {
+	Changes []Change `json:"changes"`
+	Cursor  string   `json:"cursor"`
+	HasMore bool     `json:"has_more"`
+}
+
+// Batch sync mutation ops
+const (
+	MutationCreate = "create"
+	MutationUpdate = "update"
+	MutationDelete = "delete"
+)
+
+// PropertyMutation is a change made on an offline client. Updates and
+// deletes carry the version the client based them on; ClientRef lets the
+// client match a created property to its server ID.
+type PropertyMutation struct {
+	Op          string    `json:"op"`
+	ID          int       `json:"id,omitempty"`
+	BaseVersion int       `json:"base_version,omitempty"`
+	ClientRef   string    `json:"client_ref,omitempty"`
+	Property    *Property `json:"property,omitempty"`
+}
+
+// SyncRequest is a batch of mutations applied all-or-nothing
+type SyncRequest struct {
+	ClientID  string             `json:"client_id"`
+	Mutations []PropertyMutation `json:"mutations"`
+}
+
+// MutationResult reports the server ID and new version of an applied
+// mutation
+type MutationResult struct {
+	Index     int    `json:"index"`
+	Op        string `json:"op"`
+	ClientRef string `json:"client_ref,omitempty"`
+	ID        int    `json:"id"`
+	Version   int    `json:"version,omitempty"`
+}
+
+// SyncConflict is a mutation whose base version is stale. Current is the
+// server's copy, or nil when the property has been deleted.
+type SyncConflict struct {
+	Index       int       `json:"index"`
+	ID          int       `json:"id"`
+	BaseVersion int       `json:"base_version"`
+	Current     *Property `json:"current"`
+}
+
+// SyncResult is the outcome of a batch sync: results when every mutation
+// applied, or the conflicts that rolled the batch back
+type SyncResult struct {
+	Results   []MutationResult `json:"results,omitempty"`
+	Conflicts []SyncConflict   `json:"conflicts,omitempty"`
+}
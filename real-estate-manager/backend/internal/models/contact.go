@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Contact kinds
+const (
+	ContactBuyer  = "buyer"
+	ContactSeller = "seller"
+	ContactVendor = "vendor"
+)
+
+// ContactKinds lists the contact kinds
+var ContactKinds = []string{ContactBuyer, ContactSeller, ContactVendor}
+
+// Entities a contact can be linked to
+const (
+	ContactLinkLead    = "lead"
+	ContactLinkShowing = "showing"
+	ContactLinkDeal    = "deal"
+)
+
+// ContactLinkTypes lists the entities a contact can be linked to
+var ContactLinkTypes = []string{ContactLinkLead, ContactLinkShowing, ContactLinkDeal}
+
+// Contact is a buyer, seller or vendor in the contact book. Tags are
+// lowercase labels used to group contacts. Links are only loaded for a
+// single contact.
+type Contact struct {
+	ID             int           `json:"id" db:"id"`
+	OrganizationID NullInt32     `json:"organization_id" db:"organization_id"`
+	Kind           string        `json:"kind" db:"kind"`
+	Name           string        `json:"name" db:"name"`
+	Email          string        `json:"email" db:"email"`
+	Phone          string        `json:"phone" db:"phone"`
+	Company        string        `json:"company" db:"company"`
+	Notes          string        `json:"notes" db:"notes"`
+	Tags           StringList    `json:"tags" db:"tags"`
+	CreatedBy      NullInt32     `json:"created_by" db:"created_by"`
+	Links          []ContactLink `json:"links,omitempty" db:"-"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// ContactLink ties a contact to a lead, showing or deal
+type ContactLink struct {
+	ContactID  int       `json:"contact_id" db:"contact_id"`
+	EntityType string    `json:"entity_type" db:"entity_type" binding:"required"`
+	EntityID   int       `json:"entity_id" db:"entity_id" binding:"required,min=1"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ContactFilter narrows a contact listing; zero values match everything.
+// Q matches part of the name, email or company. EntityType and EntityID
+// together return the contacts linked to that entity.
+type ContactFilter struct {
+	Kind       string `form:"kind"`
+	Tag        string `form:"tag"`
+	Q          string `form:"q"`
+	EntityType string `form:"entity_type"`
+	EntityID   int    `form:"entity_id" binding:"min=0"`
+	Limit      int    `form:"limit" binding:"min=0,max=500"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// CRM sync statuses of a lead or contact. Records that were never exported
+// have no status yet.
+const (
+	CRMSynced = "synced"
+	// CRMFailed could not be exported and is retried on schedule until it
+	// runs out of attempts
+	CRMFailed = "failed"
+)
+
+// CRMSyncRecord tracks one lead or contact in the external CRM
+type CRMSyncRecord struct {
+	CRM        string `json:"crm" db:"crm"`
+	RecordType string `json:"record_type" db:"record_type"`
+	// RecordKey is the lead ID, or the contact's email address or phone
+	// number
+	RecordKey  string     `json:"record_key" db:"record_key"`
+	ExternalID string     `json:"external_id,omitempty" db:"external_id"`
+	Status     string     `json:"status" db:"status"`
+	LastError  string     `json:"last_error,omitempty" db:"last_error"`
+	Attempts   int        `json:"attempts" db:"attempts"`
+	SyncedAt   *time.Time `json:"synced_at" db:"synced_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Data-quality checks
+const (
+	// QualityZeroPrice finds live listings without a price: sales priced at
+	// zero and rentals without a monthly rent
+	QualityZeroPrice = "zero_price"
+	// QualityDuplicateAddress finds live listings of one organization that
+	// share an address
+	QualityDuplicateAddress = "duplicate_address"
+	// QualityMissingPhotos finds live imported listings without photos
+	QualityMissingPhotos = "missing_photos"
+	// QualityGeocodeFailed finds listings whose address enrichment could
+	// not geocode
+	QualityGeocodeFailed = "geocode_failed"
+)
+
+// QualityChecks lists every data-quality check, in report order
+var QualityChecks = []string{QualityZeroPrice, QualityDuplicateAddress, QualityMissingPhotos, QualityGeocodeFailed}
+
+// QualityIssue is a listing a check found or, for duplicate addresses, the
+// listings sharing Location
+type QualityIssue struct {
+	PropertyIDs []int  `json:"property_ids"`
+	Location    string `json:"location"`
+}
+
+// QualityCheckResult is the outcome of one check. Count is the number of
+// issues found, of which Samples holds the first few; the check is
+// Exceeded when Count is over Threshold.
+type QualityCheckResult struct {
+	Check     string         `json:"check"`
+	Count     int            `json:"count"`
+	Threshold int            `json:"threshold"`
+	Exceeded  bool           `json:"exceeded"`
+	Samples   []QualityIssue `json:"samples"`
+}
+
+// QualityReport is one run of the data-quality checks
+type QualityReport struct {
+	ID        int64                `json:"id" db:"id"`
+	Checks    []QualityCheckResult `json:"checks" db:"checks"`
+	Exceeded  bool                 `json:"exceeded" db:"exceeded"`
+	CreatedAt time.Time            `json:"created_at" db:"created_at"`
+}
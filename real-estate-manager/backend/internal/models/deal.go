@@ -0,0 +1,134 @@
+package models
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Deal stages, in pipeline order. Offers and deals under contract are
+// open; closed and lost deals are final.
+const (
+	DealOffer         = "offer"
+	DealUnderContract = "under_contract"
+	DealClosed        = "closed"
+	DealLost          = "lost"
+)
+
+// DealStages lists the stages in pipeline order
+var DealStages = []string{DealOffer, DealUnderContract, DealClosed, DealLost}
+
+// Deal is the sale of a property moving through the pipeline. Commission
+// is the gross commission on Price; it is computed, not stored.
+type Deal struct {
+	ID                int               `json:"id" db:"id"`
+	PropertyID        int               `json:"property_id" db:"property_id"`
+	Stage             string            `json:"stage" db:"stage"`
+	Price             float64           `json:"price" db:"price"`
+	CommissionPercent float64           `json:"commission_percent" db:"commission_percent"`
+	Commission        float64           `json:"commission" db:"-"`
+	ExpectedCloseDate NullDate          `json:"expected_close_date" db:"expected_close_date"`
+	ClosedAt          NullTime          `json:"closed_at" db:"closed_at"`
+	Notes             string            `json:"notes" db:"notes"`
+	CreatedBy         NullInt32         `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+	Splits            []CommissionSplit `json:"splits" db:"-"`
+}
+
+// CommissionSplit is an agent's share of a deal's commission. Amount is
+// computed from the deal.
+type CommissionSplit struct {
+	AgentID uint    `json:"agent_id" db:"agent_id"`
+	Role    string  `json:"role" db:"role"`
+	Percent float64 `json:"percent" db:"percent"`
+	Amount  float64 `json:"amount" db:"-"`
+}
+
+// DealFilter narrows a deal listing; zero values match everything
+type DealFilter struct {
+	Stage      string `form:"stage"`
+	AgentID    uint   `form:"agent_id"`
+	PropertyID int    `form:"property_id" binding:"min=0"`
+}
+
+// PipelineStage totals the deals in one stage
+type PipelineStage struct {
+	Stage      string  `json:"stage"`
+	Deals      int     `json:"deals"`
+	Volume     float64 `json:"volume"`
+	Commission float64 `json:"commission"`
+}
+
+// RevenuePeriod totals the deals of one month, as "2006-01"
+type RevenuePeriod struct {
+	Period     string  `json:"period"`
+	Deals      int     `json:"deals"`
+	Volume     float64 `json:"volume"`
+	Commission float64 `json:"commission"`
+}
+
+// AgentRevenue is an agent's share of the commission in a report
+type AgentRevenue struct {
+	AgentID    uint    `json:"agent_id"`
+	Deals      int     `json:"deals"`
+	Commission float64 `json:"commission"`
+}
+
+// RevenueReport covers deals closed between From and To, by month and by
+// agent, and the commission open deals are expected to bring in that
+// period
+type RevenueReport struct {
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Closed     []RevenuePeriod `json:"closed"`
+	Agents     []AgentRevenue  `json:"agents"`
+	Expected   []RevenuePeriod `json:"expected"`
+	Commission float64         `json:"commission"`
+	// Timezone is the zone From and To are dates in
+	Timezone string `json:"timezone"`
+}
+
+// DateLayout is how calendar dates are written in the API
+const DateLayout = "2006-01-02"
+
+// NullDate is a calendar date without a time of day, written as
+// "2006-01-02" in JSON and to the database so time zones cannot shift it
+type NullDate struct {
+	sql.NullTime
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (nd NullDate) MarshalJSON() ([]byte, error) {
+	if !nd.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(nd.Time.Format(DateLayout))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (nd *NullDate) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil || *value == "" {
+		nd.Valid = false
+		return nil
+	}
+	date, err := time.Parse(DateLayout, *value)
+	if err != nil {
+		return err
+	}
+	nd.Time, nd.Valid = date, true
+	return nil
+}
+
+// Value implements driver.Valuer interface
+func (nd NullDate) Value() (driver.Value, error) {
+	if !nd.Valid {
+		return nil, nil
+	}
+	return nd.Time.Format(DateLayout), nil
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// EmailSuppression is an address that no longer receives email, usually
+// because the mail provider rejected it
+type EmailSuppression struct {
+	Email     string    `json:"email" db:"email"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
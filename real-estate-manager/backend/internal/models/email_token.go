@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// EmailToken is a single-use token emailed to a user, such as a passwordless
+// login link or a password reset. Only the SHA-256 hash of the emailed token
+// is stored.
+type EmailToken struct {
+	ID        int       `json:"id" db:"id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	UserID    uint      `json:"user_id" db:"user_id"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	UsedAt    NullTime  `json:"used_at" db:"used_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
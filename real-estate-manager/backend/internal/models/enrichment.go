@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Enrichment holds third-party location data attached to a property
+type Enrichment struct {
+	PropertyID     int         `json:"property_id" db:"property_id"`
+	Latitude       NullFloat64 `json:"latitude" db:"latitude"`
+	Longitude      NullFloat64 `json:"longitude" db:"longitude"`
+	SchoolDistrict NullString  `json:"school_district" db:"school_district"`
+	WalkScore      NullInt32   `json:"walk_score" db:"walk_score"`
+	Neighborhood   NullString  `json:"neighborhood" db:"neighborhood"`
+	EnrichedAt     time.Time   `json:"enriched_at" db:"enriched_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// FeatureFlag toggles optional functionality at runtime
+type FeatureFlag struct {
+	Name        string     `json:"name" db:"name"`
+	Enabled     bool       `json:"enabled" db:"enabled"`
+	Description NullString `json:"description" db:"description"`
+	UpdatedBy   NullString `json:"updated_by" db:"updated_by"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Virus scan verdicts of uploaded files
+const (
+	FileScanClean    = "clean"
+	FileScanInfected = "infected"
+	// FileScanSkipped marks files too large for the scanner, accepted
+	// unscanned
+	FileScanSkipped = "skipped"
+)
+
+// FileScan records the virus scan of an uploaded photo or document
+type FileScan struct {
+	ID             int       `json:"id" db:"id"`
+	UserID         NullInt32 `json:"user_id" db:"user_id"`
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+	PropertyID     NullInt32 `json:"property_id" db:"property_id"`
+	Kind           string    `json:"kind" db:"kind"`
+	Filename       string    `json:"filename" db:"filename"`
+	SizeBytes      int64     `json:"size_bytes" db:"size_bytes"`
+	Status         string    `json:"status" db:"status"`
+	Signature      string    `json:"signature,omitempty" db:"signature"`
+	QuarantinePath string    `json:"quarantine_path,omitempty" db:"quarantine_path"`
+	ScannedAt      time.Time `json:"scanned_at" db:"scanned_at"`
+}
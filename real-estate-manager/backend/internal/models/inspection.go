@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Inspection kinds
+const (
+	InspectionGeneral    = "general"
+	InspectionRoof       = "roof"
+	InspectionPest       = "pest"
+	InspectionRadon      = "radon"
+	InspectionSewer      = "sewer"
+	InspectionStructural = "structural"
+	InspectionOther      = "other"
+)
+
+// InspectionKinds lists the kinds an inspection may have
+var InspectionKinds = []string{InspectionGeneral, InspectionRoof, InspectionPest, InspectionRadon, InspectionSewer,
+	InspectionStructural, InspectionOther}
+
+// Inspection statuses. Completed and cancelled inspections are final.
+const (
+	InspectionScheduled = "scheduled"
+	InspectionCompleted = "completed"
+	InspectionCancelled = "cancelled"
+)
+
+// InspectionStatuses lists the statuses an inspection may have
+var InspectionStatuses = []string{InspectionScheduled, InspectionCompleted, InspectionCancelled}
+
+// Repair statuses. Done and waived repairs are off the punch list's open
+// count; waived ones were accepted as they are.
+const (
+	RepairOpen       = "open"
+	RepairInProgress = "in_progress"
+	RepairDone       = "done"
+	RepairWaived     = "waived"
+)
+
+// RepairStatuses lists the repair statuses in punch-list order
+var RepairStatuses = []string{RepairOpen, RepairInProgress, RepairDone, RepairWaived}
+
+// Inspection is an inspection of a property, with the report documents
+// attached to it and the repairs it called for
+type Inspection struct {
+	ID          int                `json:"id" db:"id"`
+	PropertyID  int                `json:"property_id" db:"property_id"`
+	Kind        string             `json:"kind" db:"kind"`
+	Inspector   string             `json:"inspector" db:"inspector"`
+	ScheduledAt time.Time          `json:"scheduled_at" db:"scheduled_at"`
+	Status      string             `json:"status" db:"status"`
+	CompletedAt NullTime           `json:"completed_at" db:"completed_at"`
+	Notes       string             `json:"notes" db:"notes"`
+	CreatedBy   NullInt32          `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+	Reports     []InspectionReport `json:"reports" db:"-"`
+	Repairs     []RepairItem       `json:"repairs,omitempty" db:"-"`
+}
+
+// InspectionReport is a document upload attached to an inspection
+type InspectionReport struct {
+	UploadID    string    `json:"upload_id" db:"upload_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	SizeBytes   int64     `json:"size_bytes" db:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// RepairItem is a repair an inspection called for, tracked until it is
+// done or waived
+type RepairItem struct {
+	ID           int       `json:"id" db:"id"`
+	InspectionID int       `json:"inspection_id" db:"inspection_id"`
+	PropertyID   int       `json:"property_id" db:"property_id"`
+	Description  string    `json:"description" db:"description"`
+	Status       string    `json:"status" db:"status"`
+	AssigneeID   NullInt32 `json:"assignee_id" db:"assignee_id"`
+	DueDate      NullDate  `json:"due_date" db:"due_date"`
+	CompletedAt  NullTime  `json:"completed_at" db:"completed_at"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RepairFilter narrows a punch list; zero values match everything
+type RepairFilter struct {
+	Status     string `form:"status"`
+	AssigneeID uint   `form:"assignee_id"`
+}
+
+// PunchList is a property's repairs from all its inspections, with the
+// number in each status
+type PunchList struct {
+	PropertyID int            `json:"property_id"`
+	Counts     map[string]int `json:"counts"`
+	// Overdue counts open and in-progress repairs past their due date
+	Overdue int          `json:"overdue"`
+	Items   []RepairItem `json:"items"`
+}
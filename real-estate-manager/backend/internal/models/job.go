@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Labels of jobs started without a label and of automatic syncs
+const (
+	JobLabelManual    = "manual"
+	JobLabelScheduled = "scheduled"
+)
+
+// JobDetails describe why an import job was started, so scheduled, manual
+// and backfill runs can be told apart in the job history
+type JobDetails struct {
+	Label       string      `json:"label"`
+	Description string      `json:"description,omitempty"`
+	Metadata    JobMetadata `json:"metadata,omitempty"`
+	// LazyPhotos saves listings with their photo URLs only and downloads
+	// the photos afterwards, so listings are searchable sooner
+	LazyPhotos bool `json:"lazy_photos,omitempty"`
+}
+
+// JobMetadata is arbitrary JSON attached to a job by whoever started it
+type JobMetadata map[string]any
+
+// Value implements the driver.Valuer interface for database storage
+func (m JobMetadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (m *JobMetadata) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return errors.New("cannot scan into JobMetadata")
+	}
+}
+
+// ProcessingJob is an import job as recorded in the job history
+type ProcessingJob struct {
+	ID string `json:"id"`
+	JobDetails
+	Limit           int        `json:"limit"`
+	Status          string     `json:"status"`
+	TotalProperties int        `json:"total_properties"`
+	ProcessedCount  int        `json:"processed_count"`
+	FailedCount     int        `json:"failed_count"`
+	PhotosSkipped   int        `json:"photos_skipped"`
+	ListingsSkipped int        `json:"listings_skipped"`
+	ErrorMessage    string     `json:"error_message,omitempty"`
+	CreatedBy       NullInt32  `json:"created_by"`
+	OrganizationID  NullInt32  `json:"organization_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	HeartbeatAt     *time.Time `json:"heartbeat_at,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// JobFilter selects jobs from the history, newest first. A zero
+// CreatedBy selects jobs started by anyone; it comes from the caller, never
+// the query string.
+type JobFilter struct {
+	Label     string `form:"label"`
+	CreatedBy uint   `form:"-"`
+	Limit     int    `form:"limit,default=50" binding:"min=1,max=500"`
+}
+
+// SyncSchedule is when SimplyRETS imports run automatically. Cron is
+// empty when they are off; Source says whether the schedule comes from the
+// sync_schedule setting or the SIMPLYRETS_SYNC_CRON environment variable.
+type SyncSchedule struct {
+	Cron      string     `json:"cron"`
+	Enabled   bool       `json:"enabled"`
+	Source    string     `json:"source"`
+	Timezone  string     `json:"timezone"`
+	NextRunAt *time.Time `json:"next_run_at"`
+	LastRun   *SyncRun   `json:"last_run"`
+}
+
+// SyncRun is a scheduled import since the server started. Skipped runs
+// found the previous scheduled job still running.
+type SyncRun struct {
+	At      time.Time `json:"at"`
+	JobID   string    `json:"job_id,omitempty"`
+	Skipped bool      `json:"skipped"`
+	Error   string    `json:"error,omitempty"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// jsonWriter appends JSON values to a buffer without reflection, for the
+// hand-written marshalers of types encoded in bulk. Its output is what
+// encoding/json produces for the same values. The first error is kept
+// for the marshaler to return.
+type jsonWriter struct {
+	buf []byte
+	err error
+}
+
+func (w *jsonWriter) raw(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+func (w *jsonWriter) int(i int64) {
+	w.buf = strconv.AppendInt(w.buf, i, 10)
+}
+
+// float formats f as encoding/json does: like %f, but in exponent form for
+// very small and very large values
+func (w *jsonWriter) float(f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		w.marshal(f)
+		return
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	w.buf = strconv.AppendFloat(w.buf, f, format, -1, 64)
+	if format == 'e' {
+		// e-09 is written e-9
+		n := len(w.buf)
+		if n >= 4 && w.buf[n-4] == 'e' && w.buf[n-3] == '-' && w.buf[n-2] == '0' {
+			w.buf[n-2] = w.buf[n-1]
+			w.buf = w.buf[:n-1]
+		}
+	}
+}
+
+// string quotes s. Strings of plain ASCII, most of them, are copied as
+// they are; anything encoding/json would escape goes through it.
+func (w *jsonWriter) string(s string) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			w.marshal(s)
+			return
+		}
+	}
+	w.buf = append(w.buf, '"')
+	w.buf = append(w.buf, s...)
+	w.buf = append(w.buf, '"')
+}
+
+// time writes t in RFC 3339 with nanoseconds, as time.Time.MarshalJSON
+func (w *jsonWriter) time(t time.Time) {
+	if year := t.Year(); year < 0 || year > 9999 {
+		w.marshal(t)
+		return
+	}
+	w.buf = append(w.buf, '"')
+	w.buf = t.AppendFormat(w.buf, time.RFC3339Nano)
+	w.buf = append(w.buf, '"')
+}
+
+// marshal encodes v with encoding/json, for values the fast paths leave to
+// it: strings to escape, and values it rejects with an error
+func (w *jsonWriter) marshal(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return
+	}
+	w.buf = append(w.buf, data...)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// LeadStatusNew is the status of a lead nobody has followed up yet
+const LeadStatusNew = "new"
+
+// Lead is an enquiry received from a portal or landing page
+type Lead struct {
+	ID     int    `json:"id" db:"id"`
+	Source string `json:"source" db:"source"`
+	// ExternalID is the lead's ID at the source, or a hash of the payload
+	// when the source has none
+	ExternalID string    `json:"external_id" db:"external_id"`
+	Name       string    `json:"name" db:"name"`
+	Email      string    `json:"email" db:"email"`
+	Phone      string    `json:"phone" db:"phone"`
+	Message    string    `json:"message" db:"message"`
+	PropertyID NullInt32 `json:"property_id" db:"property_id"`
+	AssignedTo NullInt32 `json:"assigned_to" db:"assigned_to"`
+	Status     string    `json:"status" db:"status"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// LeadRoutingRule assigns leads without a listing agent to AgentID. Rules
+// are tried by ascending Priority; an empty Source or Location matches any
+// lead, and Location matches part of the listing's or the lead's address.
+type LeadRoutingRule struct {
+	ID        int       `json:"id" db:"id"`
+	Priority  int       `json:"priority" db:"priority"`
+	Source    string    `json:"source" db:"source"`
+	Location  string    `json:"location" db:"location"`
+	AgentID   uint      `json:"agent_id" db:"agent_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// Kinds of area market statistics are kept for
+const (
+	MarketAreaCity = "city"
+	MarketAreaZip  = "zip"
+)
+
+// MarketListing is a listing with what the market reports need: when it
+// came on the market and, if it left, when and for how much. Sale price and
+// date come from the latest closed deal when there is one.
+type MarketListing struct {
+	PropertyID int
+	Location   string
+	Status     string
+	Price      float64
+	SquareFeet NullInt32
+	ListedAt   time.Time
+	UpdatedAt  time.Time
+	SalePrice  NullFloat64
+	SoldAt     NullTime
+}
+
+// MarketStat summarizes a city's or ZIP code's market in a month, as
+// "2006-01". Active listings are counted at the end of the month.
+type MarketStat struct {
+	AreaType            string      `json:"-" db:"area_type"`
+	Area                string      `json:"-" db:"area"`
+	Month               string      `json:"month" db:"month"`
+	NewListings         int         `json:"new_listings" db:"new_listings"`
+	ActiveListings      int         `json:"active_listings" db:"active_listings"`
+	Sales               int         `json:"sales" db:"sales"`
+	MedianListPrice     NullFloat64 `json:"median_list_price" db:"median_list_price"`
+	MedianSalePrice     NullFloat64 `json:"median_sale_price" db:"median_sale_price"`
+	AvgSalePricePerSqft NullFloat64 `json:"avg_sale_price_per_sqft" db:"avg_sale_price_per_sqft"`
+	RefreshedAt         time.Time   `json:"-" db:"refreshed_at"`
+
+	// MonthsOfSupply is how long the active listings would last at the
+	// month's pace of sales
+	MonthsOfSupply *float64 `json:"months_of_supply" db:"-"`
+}
+
+// MarketStatFilter selects the statistics of one area. With AnyState a
+// city given without its state matches the city in every state.
+type MarketStatFilter struct {
+	AreaType string
+	Area     string
+	AnyState bool
+	Since    string
+}
+
+// MarketReport is the monthly history of one area's market, oldest month
+// first
+type MarketReport struct {
+	AreaType    string       `json:"area_type"`
+	Area        string       `json:"area"`
+	Months      []MarketStat `json:"months"`
+	RefreshedAt *time.Time   `json:"refreshed_at"`
+	// Timezone is the zone that decides the current month
+	Timezone string `json:"timezone"`
+}
+
+// MarketSnapshot is the inventory of one city in one status on the day it
+// was taken, as "2006-01-02"
+type MarketSnapshot struct {
+	Date        string      `json:"date" db:"snapshot_date"`
+	City        string      `json:"-" db:"city"`
+	Status      string      `json:"-" db:"status"`
+	Listings    int         `json:"listings" db:"listings"`
+	MedianPrice NullFloat64 `json:"median_price" db:"median_price"`
+}
+
+// MarketSnapshotFilter selects the snapshots of one city from the day
+// Since on. With AnyState a city given without its state matches the city
+// in every state.
+type MarketSnapshotFilter struct {
+	City     string
+	AnyState bool
+	Since    string
+}
+
+// InventoryStatus is a city's inventory in one status. ListingsChange is
+// the difference from the month before, when that month has a snapshot.
+type InventoryStatus struct {
+	Listings       int         `json:"listings"`
+	MedianPrice    NullFloat64 `json:"median_price"`
+	ListingsChange *int        `json:"listings_change"`
+}
+
+// InventoryMonth is a city's inventory by status as of the last snapshot
+// of the month, as "2006-01". Months without a snapshot have no date and
+// no statuses.
+type InventoryMonth struct {
+	Month        string                     `json:"month"`
+	SnapshotDate *string                    `json:"snapshot_date"`
+	Statuses     map[string]InventoryStatus `json:"statuses"`
+}
+
+// InventoryTrend is the monthly inventory history of one city, oldest
+// month first
+type InventoryTrend struct {
+	City     string           `json:"city"`
+	Months   []InventoryMonth `json:"months"`
+	Timezone string           `json:"timezone"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// NotificationPreferences controls how a user is reached for time-sensitive
+// notifications. Text messages are only sent to users who opted in, and
+// never during their quiet hours.
+type NotificationPreferences struct {
+	UserID   uint   `json:"user_id" db:"user_id"`
+	Phone    string `json:"phone" db:"phone"`
+	SMSOptIn bool   `json:"sms_opt_in" db:"sms_opt_in"`
+	// Channel is "sms" or "whatsapp"
+	Channel string `json:"channel" db:"channel"`
+	// QuietStart and QuietEnd are "HH:MM" in Timezone; the range may span
+	// midnight. Both empty disables quiet hours.
+	QuietStart string    `json:"quiet_start" db:"quiet_start"`
+	QuietEnd   string    `json:"quiet_end" db:"quiet_end"`
+	Timezone   string    `json:"timezone" db:"timezone"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Notification is an entry in a user's in-app notification center
+type Notification struct {
+	ID     int    `json:"id" db:"id"`
+	UserID uint   `json:"user_id" db:"user_id"`
+	Type   string `json:"type" db:"type"`
+	Title  string `json:"title" db:"title"`
+	Body   string `json:"body" db:"body"`
+	// Subject is the entity the notification is about, e.g. "property/12"
+	Subject   string     `json:"subject,omitempty" db:"subject"`
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NotificationQuery selects a page of a user's notifications, newest first.
+// BeforeID continues after the last notification of a previous page.
+type NotificationQuery struct {
+	BeforeID   int
+	Limit      int
+	UnreadOnly bool
+}
+
+// NotificationPage is a page of notifications. Cursor is passed back as
+// ?before= to fetch the next page and is empty on the last one.
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unread_count"`
+	Cursor        string         `json:"cursor"`
+	HasMore       bool           `json:"has_more"`
+}
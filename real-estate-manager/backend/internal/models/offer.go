@@ -0,0 +1,105 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Offer statuses. A submitted offer may be countered, accepted or
+// rejected; the buyer answers a counter by submitting again. Accepted and
+// rejected offers are final.
+const (
+	OfferSubmitted = "submitted"
+	OfferCountered = "countered"
+	OfferAccepted  = "accepted"
+	OfferRejected  = "rejected"
+)
+
+// OfferStatuses lists the offer statuses in workflow order
+var OfferStatuses = []string{OfferSubmitted, OfferCountered, OfferAccepted, OfferRejected}
+
+// offerTransitions are the statuses each open status may move to
+var offerTransitions = map[string][]string{
+	OfferSubmitted: {OfferCountered, OfferAccepted, OfferRejected},
+	OfferCountered: {OfferSubmitted, OfferAccepted, OfferRejected},
+}
+
+// CanTransitionOffer reports whether an offer may move from one status to
+// another
+func CanTransitionOffer(from, to string) bool {
+	return slices.Contains(offerTransitions[from], to)
+}
+
+// Offer contingencies
+const (
+	ContingencyFinancing  = "financing"
+	ContingencyInspection = "inspection"
+	ContingencyAppraisal  = "appraisal"
+	ContingencyHomeSale   = "home_sale"
+	ContingencyTitle      = "title"
+)
+
+// IsValidContingency reports whether contingency is a known contingency
+func IsValidContingency(contingency string) bool {
+	switch contingency {
+	case ContingencyFinancing, ContingencyInspection, ContingencyAppraisal, ContingencyHomeSale, ContingencyTitle:
+		return true
+	}
+	return false
+}
+
+// Offer is a buyer's offer on a listing. CounterAmount is the seller's
+// latest counter. Expired is computed: the offer is still open past
+// ExpiresAt.
+type Offer struct {
+	ID            int         `json:"id" db:"id"`
+	PropertyID    int         `json:"property_id" db:"property_id"`
+	Amount        float64     `json:"amount" db:"amount"`
+	CounterAmount NullFloat64 `json:"counter_amount" db:"counter_amount"`
+	BuyerName     string      `json:"buyer_name" db:"buyer_name"`
+	BuyerEmail    string      `json:"buyer_email" db:"buyer_email"`
+	BuyerPhone    string      `json:"buyer_phone" db:"buyer_phone"`
+	Contingencies StringList  `json:"contingencies" db:"contingencies"`
+	ExpiresAt     NullTime    `json:"expires_at" db:"expires_at"`
+	Status        string      `json:"status" db:"status"`
+	Expired       bool        `json:"expired" db:"-"`
+	Notes         string      `json:"notes" db:"notes"`
+	CreatedBy     NullInt32   `json:"created_by" db:"created_by"`
+	RespondedAt   NullTime    `json:"responded_at" db:"responded_at"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen reports whether the offer still awaits an answer
+func (o Offer) IsOpen() bool {
+	return o.Status == OfferSubmitted || o.Status == OfferCountered
+}
+
+// OfferTransition moves an offer to another status. CounterAmount is
+// required to counter, Amount to submit again after a counter. ExpiresAt
+// replaces the offer's expiry when set.
+type OfferTransition struct {
+	Status        string   `json:"status" binding:"required"`
+	Amount        float64  `json:"amount"`
+	CounterAmount float64  `json:"counter_amount"`
+	ExpiresAt     NullTime `json:"expires_at"`
+	Notes         *string  `json:"notes"`
+}
+
+// OfferActivity is an offer with its listing, the data of offer events
+type OfferActivity struct {
+	Offer
+	Property Property `json:"property"`
+}
+
+// OfferDashboard sums up the offers on a listing. HighestOpen and
+// NextExpiry only consider open offers that have not expired.
+type OfferDashboard struct {
+	PropertyID  int            `json:"property_id"`
+	Counts      map[string]int `json:"counts"`
+	Open        int            `json:"open"`
+	Expired     int            `json:"expired"`
+	HighestOpen *float64       `json:"highest_open"`
+	NextExpiry  *time.Time     `json:"next_expiry"`
+	Offers      []Offer        `json:"offers"`
+}
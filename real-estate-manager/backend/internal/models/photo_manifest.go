@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PhotoManifestEntry is a listing photo an import downloaded, with the
+// ETag the provider served it with. A later sync sends the ETag back and
+// reuses the local copy when the provider reports it unchanged.
+type PhotoManifestEntry struct {
+	ListingID string    `json:"listing_id"`
+	URL       string    `json:"url"`
+	ETag      string    `json:"etag"`
+	LocalURL  string    `json:"local_url"`
+	Size      int64     `json:"size"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
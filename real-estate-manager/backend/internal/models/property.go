@@ -5,7 +5,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"real-estate-manager/backend/pkg/units"
 )
 
 // NullString wraps sql.NullString with proper JSON marshaling
@@ -15,10 +19,9 @@ type NullString struct {
 
 // MarshalJSON implements json.Marshaler interface
 func (ns NullString) MarshalJSON() ([]byte, error) {
-	if !ns.Valid {
-		return []byte("null"), nil
-	}
-	return json.Marshal(ns.String)
+	w := &jsonWriter{}
+	w.nullString(ns)
+	return w.buf, w.err
 }
 
 // UnmarshalJSON implements json.Unmarshaler interface
@@ -43,10 +46,9 @@ type NullInt32 struct {
 
 // MarshalJSON implements json.Marshaler interface
 func (ni NullInt32) MarshalJSON() ([]byte, error) {
-	if !ni.Valid {
-		return []byte("null"), nil
-	}
-	return json.Marshal(ni.Int32)
+	w := &jsonWriter{}
+	w.nullInt32(ni)
+	return w.buf, w.err
 }
 
 // UnmarshalJSON implements json.Unmarshaler interface
@@ -64,6 +66,60 @@ func (ni *NullInt32) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// NullFloat64 wraps sql.NullFloat64 with proper JSON marshaling
+type NullFloat64 struct {
+	sql.NullFloat64
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (nf NullFloat64) MarshalJSON() ([]byte, error) {
+	w := &jsonWriter{}
+	w.nullFloat64(nf)
+	return w.buf, w.err
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (nf *NullFloat64) UnmarshalJSON(data []byte) error {
+	var f *float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	if f != nil {
+		nf.Valid = true
+		nf.Float64 = *f
+	} else {
+		nf.Valid = false
+	}
+	return nil
+}
+
+// NullTime wraps sql.NullTime with proper JSON marshaling
+type NullTime struct {
+	sql.NullTime
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (nt NullTime) MarshalJSON() ([]byte, error) {
+	w := &jsonWriter{}
+	w.nullTime(nt)
+	return w.buf, w.err
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (nt *NullTime) UnmarshalJSON(data []byte) error {
+	var t *time.Time
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	if t != nil {
+		nt.Valid = true
+		nt.Time = *t
+	} else {
+		nt.Valid = false
+	}
+	return nil
+}
+
 // FlexibleString can unmarshal both string and number JSON values as strings
 type FlexibleString string
 
@@ -91,15 +147,87 @@ func (fs FlexibleString) String() string {
 	return string(fs)
 }
 
+// Listing statuses
+const (
+	PropertyStatusActive    = "active"
+	PropertyStatusPending   = "pending"
+	PropertyStatusSold      = "sold"
+	PropertyStatusWithdrawn = "withdrawn"
+	PropertyStatusExpired   = "expired"
+)
+
+// Listing types. Properties without one are for sale.
+const (
+	ListingTypeSale = "sale"
+	ListingTypeRent = "rent"
+)
+
+// IsValidListingType reports whether listingType is a known listing type
+func IsValidListingType(listingType string) bool {
+	return listingType == ListingTypeSale || listingType == ListingTypeRent
+}
+
+// Listing categories. Properties without one are residential; land has no
+// structure.
+const (
+	CategoryResidential = "residential"
+	CategoryCommercial  = "commercial"
+	CategoryLand        = "land"
+)
+
+// IsValidCategory reports whether category is a known listing category
+func IsValidCategory(category string) bool {
+	switch category {
+	case CategoryResidential, CategoryCommercial, CategoryLand:
+		return true
+	}
+	return false
+}
+
+// Utilities a land listing can have available
+const (
+	UtilityWater       = "water"
+	UtilitySewer       = "sewer"
+	UtilitySeptic      = "septic"
+	UtilityElectricity = "electricity"
+	UtilityGas         = "gas"
+	UtilityInternet    = "internet"
+)
+
+// IsValidUtility reports whether utility is a known utility
+func IsValidUtility(utility string) bool {
+	switch utility {
+	case UtilityWater, UtilitySewer, UtilitySeptic, UtilityElectricity, UtilityGas, UtilityInternet:
+		return true
+	}
+	return false
+}
+
+// IsValidPropertyStatus reports whether status is a known listing status
+func IsValidPropertyStatus(status string) bool {
+	switch status {
+	case PropertyStatusActive, PropertyStatusPending, PropertyStatusSold, PropertyStatusWithdrawn, PropertyStatusExpired:
+		return true
+	}
+	return false
+}
+
 type Property struct {
+	// PublicID is the UUID the property is known by outside the API, in
+	// public feeds and webhooks; ID is only used internally
 	ID          int        `json:"id" db:"id"`
+	PublicID    string     `json:"public_id" db:"public_id"`
 	Name        string     `json:"name" db:"name"`
 	Location    string     `json:"location" db:"location"`
 	Price       float64    `json:"price" db:"price"`
 	Description NullString `json:"description" db:"description"`
 	Photos      PhotoList  `json:"photos" db:"photos"`
+	Status      string     `json:"status" db:"status"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	// Version increases with every write. Clients send the version they
+	// last read to have a concurrent change rejected instead of overwritten.
+	Version int `json:"version" db:"version"`
 	
 	// SimplyRETS specific fields
 	ExternalID    NullString `json:"external_id,omitempty" db:"external_id"`
@@ -110,13 +238,214 @@ type Property struct {
 	SquareFeet    NullInt32  `json:"square_feet,omitempty" db:"square_feet"`
 	LotSize       NullString `json:"lot_size,omitempty" db:"lot_size"`
 	YearBuilt     NullInt32  `json:"year_built,omitempty" db:"year_built"`
+
+	// Carrying costs and parking; the HOA fee is one of the amenities
+	AnnualTax          NullFloat64 `json:"annual_tax" db:"annual_tax"`
+	ParkingSpaces      NullInt32   `json:"parking_spaces" db:"parking_spaces"`
+	ParkingDescription NullString  `json:"parking_description" db:"parking_description"`
+
+	// Rentals are priced by MonthlyRent instead of Price, and only they
+	// have the rental terms
+	ListingType     string      `json:"listing_type" db:"listing_type"`
+	MonthlyRent     NullFloat64 `json:"monthly_rent" db:"monthly_rent"`
+	SecurityDeposit NullFloat64 `json:"security_deposit" db:"security_deposit"`
+	LeaseTermMonths NullInt32   `json:"lease_term_months" db:"lease_term_months"`
+	AvailableFrom   NullTime    `json:"available_from" db:"available_from"`
+
+	// Category-specific fields, left out of responses when unset: Zoning
+	// applies to commercial listings and land, CapRate (a percentage), NOI
+	// and UnitCount to commercial listings, and Acreage and Utilities to
+	// land
+	Category  string     `json:"category" db:"category"`
+	Zoning    *string    `json:"zoning,omitempty" db:"zoning"`
+	CapRate   *float64   `json:"cap_rate,omitempty" db:"cap_rate"`
+	NOI       *float64   `json:"noi,omitempty" db:"noi"`
+	UnitCount *int       `json:"unit_count,omitempty" db:"unit_count"`
+	Acreage   *float64   `json:"acreage,omitempty" db:"acreage"`
+	Utilities StringList `json:"utilities,omitempty" db:"utilities"`
+
+	// Assigned agent and staleness tracking; LastSyncedAt and StaleAt are
+	// maintained by the server. Active and pending listings are expired
+	// once ExpiresAt passes.
+	AgentID      NullInt32 `json:"agent_id" db:"agent_id"`
+	LastSyncedAt NullTime  `json:"last_synced_at" db:"last_synced_at"`
+	StaleAt      NullTime  `json:"stale_at" db:"stale_at"`
+	ExpiresAt    NullTime  `json:"expires_at" db:"expires_at"`
+
+	// OrganizationID is the organization that owns the property; shared
+	// properties, visible to everyone, have none
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+
+	// Canonical metric measurements, exposed through Area and Lot
+	LivingAreaSqm NullFloat64 `json:"-" db:"living_area_sqm"`
+	LotAreaSqm    NullFloat64 `json:"-" db:"lot_area_sqm"`
+
+	// Area and Lot are filled per request in the caller's unit system
+	Area *Measurement `json:"area,omitempty" db:"-"`
+	Lot  *Measurement `json:"lot,omitempty" db:"-"`
+
+	// PhotoCount is set in list responses, whose Photos hold only the cover
+	// photo; the detail endpoint or ?expand=photos returns them all
+	PhotoCount *int `json:"photo_count,omitempty" db:"-"`
+
+	// Warnings is set in create and update responses, for problems worth
+	// fixing that did not stop the listing from being saved
+	Warnings []PropertyWarning `json:"warnings,omitempty" db:"-"`
+}
+
+// IsRental reports whether the property is listed for rent
+func (p *Property) IsRental() bool {
+	return p.ListingType == ListingTypeRent
+}
+
+// HasCommercialFields reports whether any commercial field is set
+func (p *Property) HasCommercialFields() bool {
+	return p.Zoning != nil || p.CapRate != nil || p.NOI != nil || p.UnitCount != nil
+}
+
+// HasStructureFields reports whether any field describing a building is set
+func (p *Property) HasStructureFields() bool {
+	return p.Bedrooms.Valid || p.Bathrooms.Valid || p.SquareFeet.Valid || p.YearBuilt.Valid
+}
+
+// PropertyWarning is a problem with a listing that, unlike a validation
+// error, does not block saving it. Code identifies the problem for
+// clients, and Field names the property field it concerns.
+type PropertyWarning struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Property warning codes
+const (
+	WarningMissingPhotos       = "missing_photos"
+	WarningShortDescription    = "short_description"
+	WarningUnusualPricePerSqft = "unusual_price_per_sqft"
+	WarningMissingAcreage      = "missing_acreage"
+)
+
+// Measurement is an area value in a specific unit
+type Measurement struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// NormalizeMeasurements derives the canonical metric values from the raw
+// square footage and free-text lot size
+func (p *Property) NormalizeMeasurements() {
+	if p.SquareFeet.Valid && p.SquareFeet.Int32 > 0 {
+		p.LivingAreaSqm = NullFloat64{sql.NullFloat64{Float64: units.Round(units.SquareFeetToSquareMeters(float64(p.SquareFeet.Int32)), 2), Valid: true}}
+	} else {
+		p.LivingAreaSqm = NullFloat64{}
+	}
+
+	if sqm, ok := units.ParseLotSize(p.LotSize.String); p.LotSize.Valid && ok {
+		p.LotAreaSqm = NullFloat64{sql.NullFloat64{Float64: units.Round(sqm, 2), Valid: true}}
+	} else if p.Acreage != nil && *p.Acreage > 0 {
+		p.LotAreaSqm = NullFloat64{sql.NullFloat64{Float64: units.Round(units.AcresToSquareMeters(*p.Acreage), 2), Valid: true}}
+	} else {
+		p.LotAreaSqm = NullFloat64{}
+	}
+}
+
+// NormalizePhotos numbers the photos in their order and gives those without
+// alt text a description of the listing
+func (p *Property) NormalizePhotos() {
+	alt := p.DefaultPhotoAltText()
+	for i := range p.Photos {
+		p.Photos[i].Position = i
+		if strings.TrimSpace(p.Photos[i].AltText) == "" {
+			p.Photos[i].AltText = alt
+		}
+	}
+}
+
+// DefaultPhotoAltText describes the listing from its details, such as
+// "12 Oak St, a 3-bedroom, 2-bathroom home in Austin, TX"
+func (p *Property) DefaultPhotoAltText() string {
+	var rooms []string
+	if p.Bedrooms.Valid && p.Bedrooms.Int32 > 0 {
+		rooms = append(rooms, fmt.Sprintf("%d-bedroom", p.Bedrooms.Int32))
+	}
+	if p.Bathrooms.Valid && p.Bathrooms.Int32 > 0 {
+		rooms = append(rooms, fmt.Sprintf("%d-bathroom", p.Bathrooms.Int32))
+	}
+	kind := "home"
+	switch p.Category {
+	case CategoryCommercial:
+		kind = "commercial property"
+	case CategoryLand:
+		kind = "lot"
+	}
+	if p.IsRental() {
+		kind += " for rent"
+	}
+
+	// Imported listings are named after the start of their address
+	name, location := strings.TrimSpace(p.Name), strings.TrimSpace(p.Location)
+	if strings.HasPrefix(location, name) {
+		name, location = location, ""
+	}
+	alt := name + ", a " + kind
+	if len(rooms) > 0 {
+		alt = name + ", a " + strings.Join(rooms, ", ") + " " + kind
+	}
+	if location != "" {
+		alt += " in " + location
+	}
+	if runes := []rune(alt); len(runes) > MaxPhotoAltTextLength {
+		alt = string(runes[:MaxPhotoAltTextLength])
+	}
+	return alt
+}
+
+// ApplyUnits fills Area and Lot from the canonical metric values
+func (p *Property) ApplyUnits(system units.System) {
+	p.Area, p.Lot = nil, nil
+	if p.LivingAreaSqm.Valid {
+		value, unit := units.LivingArea(p.LivingAreaSqm.Float64, system)
+		p.Area = &Measurement{Value: value, Unit: unit}
+	}
+	if p.LotAreaSqm.Valid {
+		value, unit := units.LotArea(p.LotAreaSqm.Float64, system)
+		p.Lot = &Measurement{Value: value, Unit: unit}
+	}
+}
+
+// SummarizePhotos keeps only the cover photo, the first, and records how
+// many photos there are in PhotoCount
+func (p *Property) SummarizePhotos() {
+	count := len(p.Photos)
+	p.PhotoCount = &count
+	if count > 1 {
+		p.Photos = p.Photos[:1]
+	}
 }
 
 // Photo represents a property photo
 type Photo struct {
 	URL      string `json:"url"`
 	LocalURL string `json:"local_url,omitempty"`
-	Caption  string `json:"caption,omitempty"`
+	// Smaller JPEG copies of a downloaded photo, for lists and galleries
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	MediumURL    string `json:"medium_url,omitempty"`
+	Caption      string `json:"caption,omitempty"`
+	// Text screen readers announce for the photo; a description of the
+	// listing is filled in when none was given
+	AltText string `json:"alt_text,omitempty"`
+	// Place of the photo among the property's photos, 0 for the cover
+	Position int `json:"position"`
+}
+
+// MaxPhotoAltTextLength caps a photo's alt text, in characters
+const MaxPhotoAltTextLength = 500
+
+// PhotoUpdate edits a photo's text; nil fields are left unchanged
+type PhotoUpdate struct {
+	AltText *string `json:"alt_text"`
+	Caption *string `json:"caption"`
+	Version int     `json:"version"`
 }
 
 // PhotoList is a slice of photos that implements SQL driver interfaces
@@ -147,7 +476,14 @@ func (p *PhotoList) Scan(value interface{}) error {
 		return errors.New("cannot scan into PhotoList")
 	}
 	
-	return json.Unmarshal(bytes, p)
+	if err := json.Unmarshal(bytes, p); err != nil {
+		return err
+	}
+	// Photos saved before positions were recorded get them from their order
+	for i := range *p {
+		(*p)[i].Position = i
+	}
+	return nil
 }
 
 // SimplyRETS API Response structures
@@ -159,6 +495,25 @@ type SimplyRETSProperty struct {
 	Property     SimplyRETSPropertyDetails  `json:"property"`
 	Photos       []string                   `json:"photos"`
 	Remarks      string                     `json:"remarks"`
+	Association  SimplyRETSAssociation      `json:"association"`
+	Tax          SimplyRETSTax              `json:"tax"`
+}
+
+// SimplyRETSAssociation is the HOA section. Frequency is how often Fee is
+// due, e.g. "Monthly", "Quarterly" or "Annually".
+type SimplyRETSAssociation struct {
+	Fee       float64 `json:"fee"`
+	Frequency string  `json:"frequency"`
+}
+
+type SimplyRETSTax struct {
+	TaxYear         int     `json:"taxYear"`
+	TaxAnnualAmount float64 `json:"taxAnnualAmount"`
+}
+
+type SimplyRETSParking struct {
+	Spaces      int    `json:"spaces"`
+	Description string `json:"description"`
 }
 
 type SimplyRETSAddress struct {
@@ -171,6 +526,14 @@ type SimplyRETSAddress struct {
 	PostalCode   string         `json:"postalCode"`
 }
 
+// SimplyRETS property types with a listing type or category of their own.
+// The list price of rentals is their monthly rent.
+const (
+	SimplyRETSTypeRental     = "RNT"
+	SimplyRETSTypeCommercial = "CRE"
+	SimplyRETSTypeLand       = "LND"
+)
+
 type SimplyRETSPropertyDetails struct {
 	PropertyType string `json:"type"`
 	Style        string `json:"style"`
@@ -180,6 +543,17 @@ type SimplyRETSPropertyDetails struct {
 	LotSize      string `json:"lotSize"`
 	Bedrooms     int    `json:"bedrooms"`
 	Bathrooms    int    `json:"bathrooms"`
+
+	// Feature lists are free text, comma separated
+	GarageSpaces     float64 `json:"garageSpaces"`
+	Pool             string  `json:"pool"`
+	Cooling          string  `json:"cooling"`
+	Heating          string  `json:"heating"`
+	InteriorFeatures string  `json:"interiorFeatures"`
+	ExteriorFeatures string  `json:"exteriorFeatures"`
+
+	Parking SimplyRETSParking `json:"parking"`
+	Acres   float64           `json:"acres"`
 }
 
 // ProcessingStatus represents the status of property processing
@@ -189,7 +563,14 @@ type ProcessingStatus struct {
 	TotalProperties int       `json:"total_properties"`
 	ProcessedCount  int       `json:"processed_count"`
 	FailedCount     int       `json:"failed_count"`
+	// PhotosSkipped counts photos left as they were because the provider
+	// reported them unchanged since the last sync
+	PhotosSkipped   int       `json:"photos_skipped"`
+	// ListingsSkipped counts listings a resumed job had already saved
+	ListingsSkipped int       `json:"listings_skipped"`
 	StartedAt       time.Time `json:"started_at"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
 	ErrorMessage    string    `json:"error_message,omitempty"`
+	// Artifacts names the files left by a finished job, for download
+	Artifacts []string `json:"artifacts,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,233 @@
+package models
+
+// propertyJSONSize is the buffer a property starts encoding into, enough
+// for a typical listing with a few photos
+const propertyJSONSize = 1024
+
+// MarshalJSON encodes a property without reflection. Property lists run to
+// thousands of rows, and encoding/json spent most of their time and
+// allocations walking the struct and calling the null wrappers' marshalers
+// field by field. The output is what encoding/json produces from the
+// struct tags; TestPropertyMarshalJSON compares the two, so new fields
+// must be added here too.
+func (p Property) MarshalJSON() ([]byte, error) {
+	w := &jsonWriter{buf: make([]byte, 0, propertyJSONSize)}
+	w.raw(`{"id":`)
+	w.int(int64(p.ID))
+	w.raw(`,"public_id":`)
+	w.string(p.PublicID)
+	w.raw(`,"name":`)
+	w.string(p.Name)
+	w.raw(`,"location":`)
+	w.string(p.Location)
+	w.raw(`,"price":`)
+	w.float(p.Price)
+	w.raw(`,"description":`)
+	w.nullString(p.Description)
+	w.raw(`,"photos":`)
+	w.photos(p.Photos)
+	w.raw(`,"status":`)
+	w.string(p.Status)
+	w.raw(`,"created_at":`)
+	w.time(p.CreatedAt)
+	w.raw(`,"updated_at":`)
+	w.time(p.UpdatedAt)
+	w.raw(`,"version":`)
+	w.int(int64(p.Version))
+	w.raw(`,"external_id":`)
+	w.nullString(p.ExternalID)
+	w.raw(`,"mls_number":`)
+	w.nullString(p.MLSNumber)
+	w.raw(`,"property_type":`)
+	w.nullString(p.PropertyType)
+	w.raw(`,"bedrooms":`)
+	w.nullInt32(p.Bedrooms)
+	w.raw(`,"bathrooms":`)
+	w.nullInt32(p.Bathrooms)
+	w.raw(`,"square_feet":`)
+	w.nullInt32(p.SquareFeet)
+	w.raw(`,"lot_size":`)
+	w.nullString(p.LotSize)
+	w.raw(`,"year_built":`)
+	w.nullInt32(p.YearBuilt)
+	w.raw(`,"annual_tax":`)
+	w.nullFloat64(p.AnnualTax)
+	w.raw(`,"parking_spaces":`)
+	w.nullInt32(p.ParkingSpaces)
+	w.raw(`,"parking_description":`)
+	w.nullString(p.ParkingDescription)
+	w.raw(`,"listing_type":`)
+	w.string(p.ListingType)
+	w.raw(`,"monthly_rent":`)
+	w.nullFloat64(p.MonthlyRent)
+	w.raw(`,"security_deposit":`)
+	w.nullFloat64(p.SecurityDeposit)
+	w.raw(`,"lease_term_months":`)
+	w.nullInt32(p.LeaseTermMonths)
+	w.raw(`,"available_from":`)
+	w.nullTime(p.AvailableFrom)
+	w.raw(`,"category":`)
+	w.string(p.Category)
+	if p.Zoning != nil {
+		w.raw(`,"zoning":`)
+		w.string(*p.Zoning)
+	}
+	if p.CapRate != nil {
+		w.raw(`,"cap_rate":`)
+		w.float(*p.CapRate)
+	}
+	if p.NOI != nil {
+		w.raw(`,"noi":`)
+		w.float(*p.NOI)
+	}
+	if p.UnitCount != nil {
+		w.raw(`,"unit_count":`)
+		w.int(int64(*p.UnitCount))
+	}
+	if p.Acreage != nil {
+		w.raw(`,"acreage":`)
+		w.float(*p.Acreage)
+	}
+	if len(p.Utilities) > 0 {
+		w.raw(`,"utilities":`)
+		w.strings(p.Utilities)
+	}
+	w.raw(`,"agent_id":`)
+	w.nullInt32(p.AgentID)
+	w.raw(`,"last_synced_at":`)
+	w.nullTime(p.LastSyncedAt)
+	w.raw(`,"stale_at":`)
+	w.nullTime(p.StaleAt)
+	w.raw(`,"expires_at":`)
+	w.nullTime(p.ExpiresAt)
+	w.raw(`,"organization_id":`)
+	w.nullInt32(p.OrganizationID)
+	if p.Area != nil {
+		w.raw(`,"area":`)
+		w.measurement(p.Area)
+	}
+	if p.Lot != nil {
+		w.raw(`,"lot":`)
+		w.measurement(p.Lot)
+	}
+	if p.PhotoCount != nil {
+		w.raw(`,"photo_count":`)
+		w.int(int64(*p.PhotoCount))
+	}
+	if len(p.Warnings) > 0 {
+		w.raw(`,"warnings":`)
+		w.warnings(p.Warnings)
+	}
+	w.raw(`}`)
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.buf, nil
+}
+
+func (w *jsonWriter) nullString(ns NullString) {
+	if !ns.Valid {
+		w.raw("null")
+		return
+	}
+	w.string(ns.String)
+}
+
+func (w *jsonWriter) nullInt32(ni NullInt32) {
+	if !ni.Valid {
+		w.raw("null")
+		return
+	}
+	w.int(int64(ni.Int32))
+}
+
+func (w *jsonWriter) nullFloat64(nf NullFloat64) {
+	if !nf.Valid {
+		w.raw("null")
+		return
+	}
+	w.float(nf.Float64)
+}
+
+func (w *jsonWriter) nullTime(nt NullTime) {
+	if !nt.Valid {
+		w.raw("null")
+		return
+	}
+	w.time(nt.Time)
+}
+
+func (w *jsonWriter) photos(photos PhotoList) {
+	if photos == nil {
+		w.raw("null")
+		return
+	}
+	w.raw("[")
+	for i, photo := range photos {
+		if i > 0 {
+			w.raw(",")
+		}
+		w.raw(`{"url":`)
+		w.string(photo.URL)
+		if photo.LocalURL != "" {
+			w.raw(`,"local_url":`)
+			w.string(photo.LocalURL)
+		}
+		if photo.ThumbnailURL != "" {
+			w.raw(`,"thumbnail_url":`)
+			w.string(photo.ThumbnailURL)
+		}
+		if photo.MediumURL != "" {
+			w.raw(`,"medium_url":`)
+			w.string(photo.MediumURL)
+		}
+		if photo.Caption != "" {
+			w.raw(`,"caption":`)
+			w.string(photo.Caption)
+		}
+		if photo.AltText != "" {
+			w.raw(`,"alt_text":`)
+			w.string(photo.AltText)
+		}
+		w.raw(`,"position":`)
+		w.int(int64(photo.Position))
+		w.raw("}")
+	}
+	w.raw("]")
+}
+
+func (w *jsonWriter) strings(list []string) {
+	w.raw("[")
+	for i, value := range list {
+		if i > 0 {
+			w.raw(",")
+		}
+		w.string(value)
+	}
+	w.raw("]")
+}
+
+func (w *jsonWriter) warnings(warnings []PropertyWarning) {
+	w.raw("[")
+	for i, warning := range warnings {
+		if i > 0 {
+			w.raw(",")
+		}
+		w.raw(`{"code":`)
+		w.string(warning.Code)
+		w.raw(`,"field":`)
+		w.string(warning.Field)
+		w.raw(`,"message":`)
+		w.string(warning.Message)
+		w.raw("}")
+	}
+	w.raw("]")
+}
+
+func (w *jsonWriter) measurement(m *Measurement) {
+	w.raw(`{"value":`)
+	w.float(m.Value)
+	w.raw(`,"unit":`)
+	w.string(m.Unit)
+	w.raw("}")
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// plainProperty has Property's fields and tags but not its MarshalJSON,
+// so encoding/json encodes it by reflection
+type plainProperty Property
+
+func testProperty(i int) Property {
+	count := 3
+	return Property{
+		ID:          i,
+		PublicID:    "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b",
+		Name:        "Casa Verde",
+		Location:    "12 Elm St, Austin, TX 78701",
+		Price:       350000.5,
+		Description: NullString{sql.NullString{String: "Bright <b>corner</b> unit & garden, \"quiet\"\nstreet — café", Valid: true}},
+		Photos: PhotoList{
+			{URL: "https://mls.example.com/a.jpg", LocalURL: "/images/a.jpg", Caption: "Front"},
+			{URL: "https://mls.example.com/b.jpg", AltText: "Casa Verde, a 3-bedroom home", Position: 1},
+		},
+		Status:        PropertyStatusActive,
+		CreatedAt:     time.Date(2024, 5, 1, 14, 30, 0, 123456000, time.UTC),
+		UpdatedAt:     time.Date(2024, 5, 2, 9, 0, 0, 0, time.FixedZone("CDT", -5*3600)),
+		Version:       7,
+		ExternalID:    NullString{sql.NullString{String: "1005192", Valid: true}},
+		Bedrooms:      NullInt32{sql.NullInt32{Int32: 3, Valid: true}},
+		SquareFeet:    NullInt32{sql.NullInt32{Int32: 1850, Valid: true}},
+		LotSize:       NullString{sql.NullString{String: "0.25 acres", Valid: true}},
+		AnnualTax:     NullFloat64{sql.NullFloat64{Float64: 6120.45, Valid: true}},
+		ParkingSpaces: NullInt32{sql.NullInt32{Int32: 2, Valid: true}},
+		AgentID:       NullInt32{sql.NullInt32{Int32: 5, Valid: true}},
+		StaleAt:       NullTime{sql.NullTime{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+		ExpiresAt:     NullTime{sql.NullTime{Time: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+		Area:          &Measurement{Value: 171.87, Unit: "sqm"},
+		PhotoCount:    &count,
+	}
+}
+
+func commercialProperty() Property {
+	zoning, capRate, noi, units := "C-2", 6.75, 182500.0, 12
+	return Property{ID: 6, Category: CategoryCommercial, Zoning: &zoning, CapRate: &capRate, NOI: &noi, UnitCount: &units}
+}
+
+func landProperty() Property {
+	zoning, acreage := "AG-1", 12.5
+	return Property{ID: 7, Category: CategoryLand, Zoning: &zoning, Acreage: &acreage,
+		Utilities: StringList{UtilityElectricity, UtilityWater}}
+}
+
+func TestPropertyMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		property Property
+	}{
+		{name: "full", property: testProperty(1)},
+		{name: "empty", property: Property{}},
+		{name: "no photos", property: Property{ID: 2, Name: "Lot", Photos: PhotoList{}, Lot: &Measurement{Value: 1e-7, Unit: "ha"}}},
+		{name: "large price", property: Property{ID: 3, Price: 1e21}},
+		{name: "rental", property: Property{ID: 5, ListingType: ListingTypeRent,
+			MonthlyRent:     NullFloat64{sql.NullFloat64{Float64: 2450, Valid: true}},
+			SecurityDeposit: NullFloat64{sql.NullFloat64{Float64: 4900, Valid: true}},
+			LeaseTermMonths: NullInt32{sql.NullInt32{Int32: 12, Valid: true}},
+			AvailableFrom:   NullTime{sql.NullTime{Time: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+		}},
+		{name: "commercial", property: commercialProperty()},
+		{name: "land", property: landProperty()},
+		{name: "warnings", property: Property{ID: 4, Warnings: []PropertyWarning{
+			{Code: WarningMissingPhotos, Field: "photos", Message: "The listing has no photos"},
+			{Code: WarningShortDescription, Field: "description", Message: "The description is short"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := json.Marshal(plainProperty(tt.property))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := json.Marshal(tt.property)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(actual) != string(expected) {
+				t.Errorf("Expected\n%s\ngot\n%s", expected, actual)
+			}
+		})
+	}
+
+	if _, err := json.Marshal(Property{Price: math.NaN()}); err == nil {
+		t.Error("Expected an error for a NaN price")
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	values := []any{
+		"", "plain text", "quote \" and \\ backslash", "<script>&", "tab\tnewline\n", "café ☕", "\u2028", "bad \xff utf-8",
+		0.0, -1.5, 350000.5, 1e-7, 123456789e15, 1e21, 0.000001, float64(math.MaxInt64),
+		time.Date(2024, 5, 1, 14, 30, 0, 123456000, time.UTC), time.Date(1, 1, 1, 0, 0, 0, 0, time.FixedZone("", 5400)),
+	}
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := &jsonWriter{}
+		switch v := value.(type) {
+		case string:
+			w.string(v)
+		case float64:
+			w.float(v)
+		case time.Time:
+			w.time(v)
+		}
+		if w.err != nil || string(w.buf) != string(expected) {
+			t.Errorf("%#v: expected %s, got %s (%v)", value, expected, w.buf, w.err)
+		}
+	}
+}
+
+// benchmarkProperties is the size of a large property list response
+const benchmarkProperties = 10000
+
+func BenchmarkPropertyListJSON(b *testing.B) {
+	properties := make([]Property, benchmarkProperties)
+	plain := make([]plainProperty, benchmarkProperties)
+	for i := range properties {
+		properties[i] = testProperty(i)
+		plain[i] = plainProperty(properties[i])
+	}
+
+	b.Run("reflection", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := json.Marshal(plain); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("marshaler", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := json.Marshal(properties); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestProperty_NormalizePhotos(t *testing.T) {
+	property := Property{
+		Name:      "12 Oak St",
+		Location:  "12 Oak St, Austin, TX",
+		Bedrooms:  NullInt32{sql.NullInt32{Int32: 3, Valid: true}},
+		Bathrooms: NullInt32{sql.NullInt32{Int32: 2, Valid: true}},
+		Photos:    PhotoList{{URL: "/images/a.jpg", Position: 4}, {URL: "/images/b.jpg", AltText: "Kitchen with island"}},
+	}
+	property.NormalizePhotos()
+
+	expected := PhotoList{
+		{URL: "/images/a.jpg", AltText: "12 Oak St, Austin, TX, a 3-bedroom, 2-bathroom home"},
+		{URL: "/images/b.jpg", AltText: "Kitchen with island", Position: 1},
+	}
+	if !reflect.DeepEqual(property.Photos, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, property.Photos)
+	}
+
+	land := Property{Name: "North parcel", Location: "Bastrop, TX", Category: CategoryLand}
+	if alt := land.DefaultPhotoAltText(); alt != "North parcel, a lot in Bastrop, TX" {
+		t.Errorf("Unexpected alt text %q", alt)
+	}
+}
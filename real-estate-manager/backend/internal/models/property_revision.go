@@ -0,0 +1,15 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PropertyRevision is a full JSON snapshot of a property taken before an update
+type PropertyRevision struct {
+	ID         int             `json:"id" db:"id"`
+	PropertyID int             `json:"property_id" db:"property_id"`
+	Snapshot   json.RawMessage `json:"snapshot" db:"snapshot"`
+	ChangedBy  NullInt32       `json:"changed_by" db:"changed_by"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// Publish states of a listing. A listing is a draft until its agent
+// submits it for review; only approved listings appear in public feeds.
+const (
+	PublishDraft         = "draft"
+	PublishPendingReview = "pending_review"
+	PublishApproved      = "approved"
+	PublishRejected      = "rejected"
+)
+
+// ListingPublication is a listing's publish state, kept apart from its
+// lifecycle status, with the latest submission and review. ReviewComment
+// is the reviewer's reason for the decision, required on rejections.
+type ListingPublication struct {
+	PropertyID    int       `json:"property_id" db:"property_id"`
+	State         string    `json:"state" db:"state"`
+	SubmittedBy   NullInt32 `json:"submitted_by" db:"submitted_by"`
+	SubmittedAt   NullTime  `json:"submitted_at" db:"submitted_at"`
+	ReviewedBy    NullInt32 `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt    NullTime  `json:"reviewed_at" db:"reviewed_at"`
+	ReviewComment string    `json:"review_comment" db:"review_comment"`
+}
+
+// ListingReview is a listing waiting in the review queue
+type ListingReview struct {
+	ListingPublication
+	Property Property `json:"property"`
+}
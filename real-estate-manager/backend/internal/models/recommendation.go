@@ -0,0 +1,87 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Favorite is a property a user saved
+type Favorite struct {
+	FavoritedAt time.Time `json:"favorited_at"`
+	Property    Property  `json:"property"`
+}
+
+// SavedSearch is a set of listing criteria a user saved
+type SavedSearch struct {
+	ID        int                 `json:"id" db:"id"`
+	UserID    uint                `json:"-" db:"user_id"`
+	Name      string              `json:"name" db:"name"`
+	Criteria  SavedSearchCriteria `json:"criteria" db:"criteria"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+// SavedSearchCriteria are the conditions a listing must meet to match a
+// saved search; unset criteria match any listing. Location matches part of
+// the listing's location, ignoring case.
+type SavedSearchCriteria struct {
+	Location     string   `json:"location,omitempty"`
+	PropertyType string   `json:"property_type,omitempty"`
+	MinPrice     *float64 `json:"min_price,omitempty"`
+	MaxPrice     *float64 `json:"max_price,omitempty"`
+	MinBedrooms  *int     `json:"min_bedrooms,omitempty"`
+}
+
+// IsEmpty reports whether the criteria match every listing
+func (c SavedSearchCriteria) IsEmpty() bool {
+	return c.Location == "" && c.PropertyType == "" && c.MinPrice == nil && c.MaxPrice == nil && c.MinBedrooms == nil
+}
+
+// Matches reports whether a listing meets the criteria
+func (c SavedSearchCriteria) Matches(property *Property) bool {
+	if c.Location != "" && !strings.Contains(strings.ToLower(property.Location), strings.ToLower(c.Location)) {
+		return false
+	}
+	if c.PropertyType != "" && !strings.EqualFold(property.PropertyType.String, c.PropertyType) {
+		return false
+	}
+	if c.MinPrice != nil && property.Price < *c.MinPrice {
+		return false
+	}
+	if c.MaxPrice != nil && property.Price > *c.MaxPrice {
+		return false
+	}
+	if c.MinBedrooms != nil && (!property.Bedrooms.Valid || int(property.Bedrooms.Int32) < *c.MinBedrooms) {
+		return false
+	}
+	return true
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (c SavedSearchCriteria) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (c *SavedSearchCriteria) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return errors.New("cannot scan into SavedSearchCriteria")
+	}
+}
+
+// Recommendation is a listing recommended to a user, with a score from 0
+// to 1 and why it was picked
+type Recommendation struct {
+	PropertyID int        `json:"property_id" db:"property_id"`
+	Score      float64    `json:"score" db:"score"`
+	Reasons    StringList `json:"reasons" db:"reasons"`
+	ComputedAt time.Time  `json:"computed_at" db:"computed_at"`
+	Property   *Property  `json:"property,omitempty" db:"-"`
+}
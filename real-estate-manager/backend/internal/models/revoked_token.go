@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RevokedToken is a JWT that must no longer be accepted, although it has
+// not expired. Only the SHA-256 hash of the token is stored. RevokedBy is
+// the user who revoked it: its owner when logging out, or an admin.
+type RevokedToken struct {
+	TokenHash string    `json:"-" db:"token_hash"`
+	UserID    NullInt32 `json:"user_id" db:"user_id"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	RevokedBy NullInt32 `json:"revoked_by" db:"revoked_by"`
+	RevokedAt time.Time `json:"revoked_at" db:"revoked_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RolePermissions maps a role to the permissions it grants. OrganizationID
+// 0 applies to all organizations; a non-zero ID defines or overrides the
+// role for one brokerage.
+type RolePermissions struct {
+	Role           string     `json:"role" db:"role"`
+	OrganizationID int        `json:"organization_id" db:"organization_id"`
+	Permissions    StringList `json:"permissions" db:"permissions"`
+	BuiltIn        bool       `json:"built_in" db:"-"`
+	UpdatedBy      NullString `json:"updated_by" db:"updated_by"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
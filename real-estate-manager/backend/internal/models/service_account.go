@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ServiceAccount is a non-human user used by automation such as nightly
+// exports. It cannot log in with a password; admins issue it scoped tokens.
+type ServiceAccount struct {
+	UserID         int       `json:"user_id" db:"user_id"`
+	Username       string    `json:"username" db:"username"`
+	Role           string    `json:"role" db:"role"`
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+	Description    string    `json:"description" db:"description"`
+	CreatedBy      string    `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateServiceAccountRequest is the body of POST /api/admin/service-accounts
+type CreateServiceAccountRequest struct {
+	Username       string `json:"username" binding:"required"`
+	Description    string `json:"description"`
+	Role           string `json:"role"`
+	OrganizationID int    `json:"organization_id"`
+}
+
+// IssueServiceTokenRequest is the body of POST /api/admin/service-accounts/:id/tokens.
+// ExpiresIn is a duration such as "720h".
+type IssueServiceTokenRequest struct {
+	Scopes    []string `json:"scopes" binding:"required"`
+	ExpiresIn string   `json:"expires_in"`
+}
+
+// ServiceToken is a scoped token issued to a service account
+type ServiceToken struct {
+	Token     string    `json:"token"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
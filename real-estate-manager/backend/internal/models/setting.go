@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Setting is a runtime-tunable configuration value stored as text
+type Setting struct {
+	Name      string     `json:"name" db:"name"`
+	Value     string     `json:"value" db:"value"`
+	UpdatedBy NullString `json:"updated_by" db:"updated_by"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
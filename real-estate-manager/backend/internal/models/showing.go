@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Showing kinds
+const (
+	ShowingPrivate   = "showing"
+	ShowingOpenHouse = "open_house"
+)
+
+// Showing statuses. A requested slot becomes confirmed once it is checked
+// against the agent's calendars.
+const (
+	ShowingRequested = "requested"
+	ShowingConfirmed = "confirmed"
+)
+
+// Showing is a private showing or open house of a listing
+type Showing struct {
+	ID           int       `json:"id" db:"id"`
+	PropertyID   int       `json:"property_id" db:"property_id"`
+	AgentID      uint      `json:"agent_id" db:"agent_id"`
+	Kind         string    `json:"kind" db:"kind"`
+	StartsAt     time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt       time.Time `json:"ends_at" db:"ends_at"`
+	Status       string    `json:"status" db:"status"`
+	ContactName  string    `json:"contact_name" db:"contact_name"`
+	ContactEmail string    `json:"contact_email" db:"contact_email"`
+	Notes        string    `json:"notes" db:"notes"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ShowingConflict is a busy period that overlaps a showing: another
+// confirmed showing (Source "showing", with its ID) or an event in one of
+// the agent's calendars (Source is the calendar provider)
+type ShowingConflict struct {
+	Source    string    `json:"source"`
+	ShowingID int       `json:"showing_id,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
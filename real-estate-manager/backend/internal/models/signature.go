@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Signature request kinds: an offer, or a seller's disclosure of the
+// property
+const (
+	SignatureKindOffer      = "offer"
+	SignatureKindDisclosure = "disclosure"
+)
+
+// Signature request statuses, following the provider's envelope. A request
+// is delivered once a signer opened it; completed, declined and voided are
+// final.
+const (
+	SignatureSent      = "sent"
+	SignatureDelivered = "delivered"
+	SignatureCompleted = "completed"
+	SignatureDeclined  = "declined"
+	SignatureVoided    = "voided"
+)
+
+// SignatureStatuses lists the signature request statuses in the order an
+// envelope goes through them
+var SignatureStatuses = []string{SignatureSent, SignatureDelivered, SignatureCompleted, SignatureDeclined, SignatureVoided}
+
+// IsFinalSignatureStatus reports whether a request with status can no
+// longer change
+func IsFinalSignatureStatus(status string) bool {
+	return status == SignatureCompleted || status == SignatureDeclined || status == SignatureVoided
+}
+
+// Signer is someone who must sign a document
+type Signer struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// SignerList is a slice of signers stored as a JSON array, in signing
+// order
+type SignerList []Signer
+
+// Value implements the driver.Valuer interface for database storage
+func (l SignerList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (l *SignerList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return errors.New("cannot scan into SignerList")
+	}
+}
+
+// SignatureRequest is a property document sent out for e-signature.
+// UploadID is the document, uploaded to the property; OfferID is set for
+// offer documents. EnvelopeID identifies the request at Provider, whose
+// callbacks update Status.
+type SignatureRequest struct {
+	ID          int        `json:"id" db:"id"`
+	PropertyID  int        `json:"property_id" db:"property_id"`
+	OfferID     NullInt32  `json:"offer_id" db:"offer_id"`
+	Kind        string     `json:"kind" db:"kind"`
+	UploadID    string     `json:"upload_id" db:"upload_id"`
+	Provider    string     `json:"provider" db:"provider"`
+	EnvelopeID  string     `json:"envelope_id" db:"envelope_id"`
+	Subject     string     `json:"subject" db:"subject"`
+	Message     string     `json:"message" db:"message"`
+	Signers     SignerList `json:"signers" db:"signers"`
+	Status      string     `json:"status" db:"status"`
+	SentBy      NullInt32  `json:"sent_by" db:"sent_by"`
+	CompletedAt NullTime   `json:"completed_at" db:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
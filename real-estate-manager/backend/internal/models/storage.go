@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Kinds of stored files
+const (
+	FileKindPhoto       = "photo"
+	FileKindDocument    = "document"
+	FileKindJobArtifact = "job_artifact"
+)
+
+// Organization is a brokerage grouping users
+type Organization struct {
+	ID             int       `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name"`
+	StorageQuotaMB NullInt32 `json:"storage_quota_mb" db:"storage_quota_mb"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// StoredFile records a photo or document counted against storage quotas
+type StoredFile struct {
+	ID             int       `json:"id" db:"id"`
+	UserID         NullInt32 `json:"user_id" db:"user_id"`
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+	PropertyID     NullInt32 `json:"property_id" db:"property_id"`
+	Kind           string    `json:"kind" db:"kind"`
+	Path           string    `json:"path" db:"path"`
+	SizeBytes      int64     `json:"size_bytes" db:"size_bytes"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// StorageUsage is the storage consumed by one user or organization
+type StorageUsage struct {
+	OwnerType  string `json:"owner_type"` // "user" or "organization"
+	OwnerID    int    `json:"owner_id"`
+	Name       string `json:"name"`
+	Files      int64  `json:"files"`
+	UsedBytes  int64  `json:"used_bytes"`
+	QuotaBytes int64  `json:"quota_bytes"` // 0 means unlimited
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Syndication statuses of a listing on a portal
+const (
+	// SyndicationPending is waiting for its first push
+	SyndicationPending = "pending"
+	// SyndicationPublished is live on the portal
+	SyndicationPublished = "published"
+	// SyndicationFailed could not be pushed and is retried on schedule
+	SyndicationFailed = "failed"
+	// SyndicationWithdrawn was taken down because the listing went off the
+	// market; it is published again once the listing is active
+	SyndicationWithdrawn = "withdrawn"
+	// SyndicationUnpublished was taken down on request
+	SyndicationUnpublished = "unpublished"
+)
+
+// SyndicationListing tracks one listing on one third-party portal. Enabled
+// records whether the listing should be on the portal; Status whether it
+// is. ListingID is the ID the portal knows the listing by, empty until it
+// is first pushed.
+type SyndicationListing struct {
+	PropertyID int        `json:"property_id" db:"property_id"`
+	Portal     string     `json:"portal" db:"portal"`
+	ListingID  string     `json:"listing_id,omitempty" db:"listing_id"`
+	Enabled    bool       `json:"enabled" db:"enabled"`
+	Status     string     `json:"status" db:"status"`
+	LastError  string     `json:"last_error,omitempty" db:"last_error"`
+	SyncedAt   *time.Time `json:"synced_at" db:"synced_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
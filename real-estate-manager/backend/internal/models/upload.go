@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Upload statuses
+const (
+	UploadStatusPending   = "pending"
+	UploadStatusCompleted = "completed"
+)
+
+// Upload is a direct-to-storage upload issued a pre-signed URL. It stays
+// pending until the client confirms the object was written.
+type Upload struct {
+	ID             string    `json:"id" db:"id"`
+	UserID         NullInt32 `json:"user_id" db:"user_id"`
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+	PropertyID     int       `json:"property_id" db:"property_id"`
+	Kind           string    `json:"kind" db:"kind"`
+	ObjectKey      string    `json:"object_key" db:"object_key"`
+	Filename       string    `json:"filename" db:"filename"`
+	ContentType    string    `json:"content_type" db:"content_type"`
+	Caption        string    `json:"caption,omitempty" db:"caption"`
+	SizeBytes      int64     `json:"size_bytes" db:"size_bytes"`
+	Status         string    `json:"status" db:"status"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	CompletedAt    NullTime  `json:"completed_at" db:"completed_at"`
+}
+
+// PresignUploadRequest asks for a pre-signed URL for one file
+type PresignUploadRequest struct {
+	PropertyID  int    `json:"property_id" binding:"required"`
+	Kind        string `json:"kind"` // photo (default) or document
+	Filename    string `json:"filename" binding:"required"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes" binding:"required"`
+	Caption     string `json:"caption,omitempty"`
+}
+
+// PresignedUpload tells the client where to PUT the file and which upload
+// to confirm afterwards
+type PresignedUpload struct {
+	UploadID  string            `json:"upload_id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// Upload session statuses
+const (
+	UploadSessionActive    = "active"
+	UploadSessionCompleted = "completed"
+)
+
+// UploadSession is a resumable upload sent in chunks. ReceivedBytes is the
+// offset the next chunk starts at; once it reaches SizeBytes the session
+// can be completed, storing the file like any other upload at URL.
+type UploadSession struct {
+	ID             string    `json:"id" db:"id"`
+	UserID         NullInt32 `json:"user_id" db:"user_id"`
+	OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
+	PropertyID     int       `json:"property_id" db:"property_id"`
+	Kind           string    `json:"kind" db:"kind"`
+	Filename       string    `json:"filename" db:"filename"`
+	ContentType    string    `json:"content_type" db:"content_type"`
+	Caption        string    `json:"caption,omitempty" db:"caption"`
+	SizeBytes      int64     `json:"size_bytes" db:"size_bytes"`
+	ReceivedBytes  int64     `json:"received_bytes" db:"received_bytes"`
+	Status         string    `json:"status" db:"status"`
+	URL            string    `json:"url,omitempty" db:"url"`
+	ExpiresAt      time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	CompletedAt    NullTime  `json:"completed_at" db:"completed_at"`
+}
+
+// UploadSessionRequest starts a resumable upload of one file
+type UploadSessionRequest struct {
+	PropertyID  int    `json:"property_id" binding:"required"`
+	Kind        string `json:"kind"` // photo (default) or document
+	Filename    string `json:"filename" binding:"required"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes" binding:"required"`
+	Caption     string `json:"caption,omitempty"`
+}
@@ -2,11 +2,21 @@ package models
 
 import "time"
 
+const (
+    RoleUser  = "user"
+    RoleAdmin = "admin"
+)
+
 type User struct {
     ID        uint      `json:"id" db:"id"`
+    // PublicID is the UUID the user is known by outside the API
+    PublicID  string    `json:"public_id" db:"public_id"`
     Username  string    `json:"username" db:"username"`
     Password  string    `json:"password,omitempty" db:"password"`
     Email     string    `json:"email" db:"email"`
+    Role      string    `json:"role,omitempty" db:"role"`
+    // OrganizationID is the brokerage the user belongs to, if any
+    OrganizationID NullInt32 `json:"organization_id" db:"organization_id"`
     CreatedAt time.Time `json:"created_at" db:"created_at"`
     UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// Comparable sources: a recorded sale, or the asking price of a listing
+// still on the market
+const (
+	ComparableSale    = "sale"
+	ComparableListing = "listing"
+)
+
+// ComparableCriteria selects candidate comparables for a property. Either
+// the bounding box (when the property has coordinates) or Locality limits
+// the area.
+type ComparableCriteria struct {
+	ExcludeID    int
+	PropertyType string
+	MinSqft      int
+	MaxSqft      int
+	Since        time.Time
+	Locality     string
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+	UseBounds    bool
+	Limit        int
+}
+
+// Comparable is a nearby property with a known price. Price and
+// ObservedAt come from the property's latest closed deal when it has one,
+// and from the property itself otherwise.
+type Comparable struct {
+	PropertyID int         `json:"property_id"`
+	Name       string      `json:"name"`
+	Location   string      `json:"location"`
+	Status     string      `json:"status"`
+	Source     string      `json:"source"`
+	Price      float64     `json:"price"`
+	SquareFeet int         `json:"square_feet"`
+	Bedrooms   NullInt32   `json:"bedrooms"`
+	ObservedAt time.Time   `json:"observed_at"`
+	Latitude   NullFloat64 `json:"-"`
+	Longitude  NullFloat64 `json:"-"`
+
+	// Filled in by the estimator
+	PricePerSqft         float64  `json:"price_per_sqft"`
+	AdjustedPricePerSqft float64  `json:"adjusted_price_per_sqft"`
+	DistanceKm           *float64 `json:"distance_km,omitempty"`
+	Weight               float64  `json:"weight"`
+}
+
+// ValuationTrend is the change in price per square foot over the
+// comparables' period
+type ValuationTrend struct {
+	MonthlyChangePercent float64 `json:"monthly_change_percent"`
+	Months               int     `json:"months"`
+	Applied              bool    `json:"applied"`
+}
+
+// ValuationMethodology explains how an estimate was reached
+type ValuationMethodology struct {
+	Summary             string         `json:"summary"`
+	Area                string         `json:"area"`
+	Since               time.Time      `json:"since"`
+	PropertyType        string         `json:"property_type,omitempty"`
+	CompsConsidered     int            `json:"comps_considered"`
+	CompsUsed           int            `json:"comps_used"`
+	RecencyHalfLifeDays int            `json:"recency_half_life_days"`
+	Trend               ValuationTrend `json:"trend"`
+}
+
+// ValuationEstimate is an automated estimate of a property's market value
+type ValuationEstimate struct {
+	PropertyID   int                  `json:"property_id"`
+	Value        float64              `json:"value"`
+	Low          float64              `json:"low"`
+	High         float64              `json:"high"`
+	Confidence   string               `json:"confidence"`
+	PricePerSqft float64              `json:"price_per_sqft"`
+	SquareFeet   int                  `json:"square_feet"`
+	Methodology  ValuationMethodology `json:"methodology"`
+	Comparables  []Comparable         `json:"comparables"`
+	GeneratedAt  time.Time            `json:"generated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RecentlyViewed is a property a user viewed, with when they last viewed
+// it and how often
+type RecentlyViewed struct {
+	ViewedAt time.Time `json:"viewed_at"`
+	Views    int       `json:"views"`
+	Property Property  `json:"property"`
+}
+
+// PropertyViewStats are a property's view counters. Repeat views by the
+// same user in quick succession count once.
+type PropertyViewStats struct {
+	PropertyID   int        `json:"property_id" db:"property_id"`
+	Views        int64      `json:"views" db:"views"`
+	LastViewedAt *time.Time `json:"last_viewed_at" db:"last_viewed_at"`
+}
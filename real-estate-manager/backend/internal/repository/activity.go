@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// ActivityRepository stores the activity feed
+type ActivityRepository interface {
+	Create(ctx context.Context, activity *models.Activity) error
+	List(ctx context.Context, query models.ActivityQuery) ([]models.Activity, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type activityRepository struct {
+	db *sql.DB
+}
+
+func NewActivityRepository(db *sql.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// Create records an activity. An event delivered twice is recorded once.
+func (r *activityRepository) Create(ctx context.Context, activity *models.Activity) error {
+	query := `INSERT INTO activity_events (event_id, type, subject, actor_id, organization_id, summary, occurred_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)`
+	result, err := r.db.ExecContext(ctx, query, activity.EventID, activity.Type, activity.Subject, activity.ActorID,
+		activity.OrganizationID, activity.Summary, activity.OccurredAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	activity.ID = id
+	return nil
+}
+
+// List returns a page of an actor's or an organization's activity, newest
+// first
+func (r *activityRepository) List(ctx context.Context, query models.ActivityQuery) ([]models.Activity, error) {
+	sqlQuery := `SELECT id, event_id, type, subject, actor_id, organization_id, summary, occurred_at FROM activity_events`
+	var args []any
+	if query.ActorID != 0 {
+		sqlQuery += ` WHERE actor_id = ?`
+		args = append(args, query.ActorID)
+	} else {
+		sqlQuery += ` WHERE organization_id = ?`
+		args = append(args, query.OrganizationID)
+	}
+	if len(query.Types) > 0 {
+		conditions := make([]string, 0, len(query.Types))
+		for _, eventType := range query.Types {
+			if strings.Contains(eventType, ".") {
+				conditions = append(conditions, `type = ?`)
+				args = append(args, eventType)
+			} else {
+				conditions = append(conditions, `type LIKE ?`)
+				args = append(args, escapeLike(eventType)+".%")
+			}
+		}
+		sqlQuery += ` AND (` + strings.Join(conditions, ` OR `) + `)`
+	}
+	if !query.Since.IsZero() {
+		sqlQuery += ` AND occurred_at >= ?`
+		args = append(args, query.Since)
+	}
+	if query.BeforeID > 0 {
+		sqlQuery += ` AND id < ?`
+		args = append(args, query.BeforeID)
+	}
+	sqlQuery += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, query.Limit)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []models.Activity{}
+	for rows.Next() {
+		var activity models.Activity
+		if err := rows.Scan(&activity.ID, &activity.EventID, &activity.Type, &activity.Subject, &activity.ActorID,
+			&activity.OrganizationID, &activity.Summary, &activity.OccurredAt); err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, rows.Err()
+}
+
+// DeleteBefore removes activity that occurred before cutoff and returns
+// how much was removed
+func (r *activityRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM activity_events WHERE occurred_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestActivityRepository_List(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Families match every type in them; full types match exactly
+	mock.ExpectQuery("SELECT (.+) FROM activity_events WHERE organization_id = \\? AND \\(type LIKE \\? OR type = \\?\\) "+
+		"AND occurred_at >= \\? AND id < \\? ORDER BY id DESC LIMIT \\?").
+		WithArgs(3, "offer.%", "property.deleted", since, int64(90), 21).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "type", "subject", "actor_id", "organization_id", "summary", "occurred_at"}).
+			AddRow(88, "evt-1", "offer.accepted", "offer/5", 7, 3, "Offer accepted: 123 Main St", since.Add(time.Hour)))
+
+	repo := NewActivityRepository(db)
+	activities, err := repo.List(context.Background(), models.ActivityQuery{OrganizationID: 3,
+		Types: []string{"offer", "property.deleted"}, Since: since, BeforeID: 90, Limit: 21})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(activities) != 1 || activities[0].ID != 88 || activities[0].ActorID.Int32 != 7 {
+		t.Errorf("Unexpected activities %+v", activities)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
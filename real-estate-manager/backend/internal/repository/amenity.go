@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+)
+
+type AmenityRepository interface {
+	GetByPropertyID(ctx context.Context, propertyID int) (*models.Amenities, error)
+	Upsert(ctx context.Context, amenities *models.Amenities) error
+}
+
+type amenityRepository struct {
+	db *sql.DB
+}
+
+func NewAmenityRepository(db *sql.DB) AmenityRepository {
+	return &amenityRepository{db: db}
+}
+
+func (r *amenityRepository) GetByPropertyID(ctx context.Context, propertyID int) (*models.Amenities, error) {
+	query := `SELECT property_id, has_pool, garage_spaces, hvac_type, hoa_fee, features, updated_at 
+		FROM property_amenities WHERE property_id = ?`
+
+	var amenities models.Amenities
+	if err := r.db.QueryRowContext(ctx, query, propertyID).Scan(&amenities.PropertyID, &amenities.HasPool,
+		&amenities.GarageSpaces, &amenities.HVACType, &amenities.HOAFee, &amenities.Features,
+		&amenities.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &amenities, nil
+}
+
+func (r *amenityRepository) Upsert(ctx context.Context, amenities *models.Amenities) error {
+	query := `INSERT INTO property_amenities (property_id, has_pool, garage_spaces, hvac_type, hoa_fee, features) 
+		VALUES (?, ?, ?, ?, ?, ?) 
+		ON DUPLICATE KEY UPDATE has_pool = VALUES(has_pool), garage_spaces = VALUES(garage_spaces), 
+		hvac_type = VALUES(hvac_type), hoa_fee = VALUES(hoa_fee), features = VALUES(features), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, amenities.PropertyID, amenities.HasPool, amenities.GarageSpaces,
+		amenities.HVACType, amenities.HOAFee, amenities.Features)
+	return err
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestAmenityRepository_GetByPropertyID(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectNil     bool
+		expectedError bool
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"property_id", "has_pool", "garage_spaces", "hvac_type", "hoa_fee", "features", "updated_at"}).
+					AddRow(1, true, 2, "central", 250.0, []byte(`["Fireplace"]`), time.Now())
+				mock.ExpectQuery("SELECT (.+) FROM property_amenities WHERE property_id = ?").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM property_amenities WHERE property_id = ?").
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectNil: true,
+		},
+		{
+			name: "database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM property_amenities WHERE property_id = ?").
+					WithArgs(1).
+					WillReturnError(errors.New("database error"))
+			},
+			expectNil:     true,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewAmenityRepository(db)
+			amenities, err := repo.GetByPropertyID(context.Background(), 1)
+
+			if tt.expectedError && err == nil {
+				t.Error("Expected error but got none")
+			} else if !tt.expectedError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+			if tt.expectNil != (amenities == nil) {
+				t.Errorf("Expected nil=%v, got %+v", tt.expectNil, amenities)
+			}
+			if amenities != nil && (!amenities.HasPool || amenities.GarageSpaces.Int32 != 2 || len(amenities.Features) != 1) {
+				t.Errorf("Unexpected amenities: %+v", amenities)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAmenityRepository_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO property_amenities (.+) ON DUPLICATE KEY UPDATE").
+		WithArgs(1, true, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewAmenityRepository(db)
+	if err := repo.Upsert(context.Background(), &models.Amenities{PropertyID: 1, HasPool: true}); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ArchiveRepository moves old listings between properties and the slim
+// property_archives table
+type ArchiveRepository interface {
+	FindCandidates(ctx context.Context, cutoff time.Time, limit int) ([]models.Property, error)
+	SharedPhotos(ctx context.Context, propertyID int, localURLs []string) (map[string]bool, error)
+	Archive(ctx context.Context, archive *models.PropertyArchive, version int) (bool, error)
+	Get(ctx context.Context, id string) (*models.PropertyArchive, error)
+	List(ctx context.Context, limit int) ([]models.PropertyArchive, error)
+	Restore(ctx context.Context, property *models.Property) error
+}
+
+// archivableListing limits candidates to sold and withdrawn listings that
+// nothing in progress still points to. Deleting a property cascades to
+// offers, inspections, signature requests, showings and uploads, and
+// unlinks leads, so listings with any of them stay where they are.
+const archivableListing = `p.status IN ('` + models.PropertyStatusSold + `', '` + models.PropertyStatusWithdrawn + `')
+	AND NOT EXISTS (SELECT 1 FROM offers o WHERE o.property_id = p.id)
+	AND NOT EXISTS (SELECT 1 FROM inspections i WHERE i.property_id = p.id)
+	AND NOT EXISTS (SELECT 1 FROM signature_requests s WHERE s.property_id = p.id)
+	AND NOT EXISTS (SELECT 1 FROM showings sh WHERE sh.property_id = p.id)
+	AND NOT EXISTS (SELECT 1 FROM uploads u WHERE u.property_id = p.id)
+	AND NOT EXISTS (SELECT 1 FROM leads l WHERE l.property_id = p.id)`
+
+const archiveColumns = `property_id, public_id, organization_id, name, location, status, listing_type, price,
+		photo_count, archive_path, archive_bytes, listed_at, last_updated_at, archived_at`
+
+type archiveRepository struct {
+	db *sql.DB
+}
+
+func NewArchiveRepository(db *sql.DB) ArchiveRepository {
+	return &archiveRepository{db: db}
+}
+
+// FindCandidates returns up to limit archivable listings last changed
+// before cutoff, oldest first
+func (r *archiveRepository) FindCandidates(ctx context.Context, cutoff time.Time, limit int) ([]models.Property, error) {
+	query := `SELECT ` + propertyColumns + `
+		FROM properties p WHERE ` + archivableListing + ` AND p.updated_at < ? ORDER BY p.updated_at, p.id LIMIT ?`
+	return queryProperties(ctx, r.db, query, cutoff, limit)
+}
+
+// SharedPhotos returns which of localURLs other properties' photos also
+// use, as copies made by duplicating a listing do
+func (r *archiveRepository) SharedPhotos(ctx context.Context, propertyID int, localURLs []string) (map[string]bool, error) {
+	shared := make(map[string]bool)
+	if len(localURLs) == 0 {
+		return shared, nil
+	}
+	query, args, err := sqlBuilder.Select("DISTINCT local_url").From("property_photos").
+		Where(sq.Eq{"local_url": localURLs}).
+		Where(sq.NotEq{"property_id": propertyID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var localURL string
+		if err := rows.Scan(&localURL); err != nil {
+			return nil, err
+		}
+		shared[localURL] = true
+	}
+	return shared, rows.Err()
+}
+
+// Archive records the archive and deletes the listing, at version, in one
+// transaction. It reports false, archiving nothing, when the listing has
+// changed or is gone.
+func (r *archiveRepository) Archive(ctx context.Context, archive *models.PropertyArchive, version int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO property_archives (property_id, public_id, organization_id, name, location, status, listing_type,
+		price, photo_count, archive_path, archive_bytes, listed_at, last_updated_at, archived_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, archive.PropertyID, archive.PublicID, archive.OrganizationID, archive.Name,
+		archive.Location, archive.Status, archive.ListingType, archive.Price, archive.PhotoCount, archive.ArchivePath,
+		archive.ArchiveBytes, archive.ListedAt, archive.LastUpdatedAt, archive.ArchivedAt); err != nil {
+		return false, err
+	}
+	deleted, err := deleteProperty(ctx, tx, archive.PropertyID, version)
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Get returns an archived listing by property ID or public ID, or nil if
+// there is none the tenant in ctx may see
+func (r *archiveRepository) Get(ctx context.Context, id string) (*models.PropertyArchive, error) {
+	condition := sq.Eq{"public_id": id}
+	if propertyID, err := strconv.Atoi(id); err == nil {
+		condition = sq.Eq{"property_id": propertyID}
+	}
+	archives, err := r.selectArchives(ctx, sqlBuilder.Select(archiveColumns).From("property_archives").
+		Where(condition).Where(tenantFilter(ctx)))
+	if err != nil || len(archives) == 0 {
+		return nil, err
+	}
+	return &archives[0], nil
+}
+
+// List returns the most recently archived listings the tenant in ctx may
+// see, newest first
+func (r *archiveRepository) List(ctx context.Context, limit int) ([]models.PropertyArchive, error) {
+	return r.selectArchives(ctx, sqlBuilder.Select(archiveColumns).From("property_archives").
+		Where(tenantFilter(ctx)).OrderBy("archived_at DESC", "property_id DESC").Limit(uint64(limit)))
+}
+
+// Restore puts an archived listing back under its original ID, with its
+// photo rows, and drops its archive row, in one transaction. The listing
+// gets a new version so clients holding the old one re-read it.
+func (r *archiveRepository) Restore(ctx context.Context, property *models.Property) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO properties (id, public_id, name, location, price, description, photos, external_id, mls_number,
+		property_type, bedrooms, bathrooms, square_feet, lot_size, year_built, annual_tax, parking_spaces,
+		parking_description, listing_type, monthly_rent, security_deposit, lease_term_months, available_from,
+		category, zoning, cap_rate, noi, unit_count, acreage, utilities, living_area_sqm, lot_area_sqm, agent_id,
+		last_synced_at, stale_at, expires_at, status, organization_id, created_at, version, photos_updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	property.Version++
+	if _, err := tx.ExecContext(ctx, query,
+		property.ID, property.PublicID, property.Name, property.Location, property.Price, property.Description, property.Photos,
+		property.ExternalID, property.MLSNumber, property.PropertyType,
+		property.Bedrooms, property.Bathrooms, property.SquareFeet, property.LotSize, property.YearBuilt,
+		property.AnnualTax, property.ParkingSpaces, property.ParkingDescription, property.ListingType,
+		property.MonthlyRent, property.SecurityDeposit, property.LeaseTermMonths, property.AvailableFrom,
+		property.Category, property.Zoning, property.CapRate, property.NOI, property.UnitCount, property.Acreage,
+		property.Utilities, property.LivingAreaSqm, property.LotAreaSqm, property.AgentID, property.LastSyncedAt,
+		property.StaleAt, property.ExpiresAt, property.Status, property.OrganizationID, property.CreatedAt,
+		property.Version); err != nil {
+		return err
+	}
+	if err := insertPhotos(ctx, tx, property.ID, property.Photos); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM property_archives WHERE property_id = ?`, property.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *archiveRepository) selectArchives(ctx context.Context, builder sq.SelectBuilder) ([]models.PropertyArchive, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	archives := []models.PropertyArchive{}
+	for rows.Next() {
+		var archive models.PropertyArchive
+		if err := rows.Scan(&archive.PropertyID, &archive.PublicID, &archive.OrganizationID, &archive.Name,
+			&archive.Location, &archive.Status, &archive.ListingType, &archive.Price, &archive.PhotoCount,
+			&archive.ArchivePath, &archive.ArchiveBytes, &archive.ListedAt, &archive.LastUpdatedAt,
+			&archive.ArchivedAt); err != nil {
+			return nil, err
+		}
+		archives = append(archives, archive)
+	}
+	return archives, rows.Err()
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestArchiveRepository_Archive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	archive := &models.PropertyArchive{PropertyID: 7, PublicID: "public-7", Name: "Old House", Location: "1 Main St",
+		Status: models.PropertyStatusSold, ListingType: models.ListingTypeSale, ArchivePath: "public-7.tar.gz",
+		ListedAt: now, LastUpdatedAt: now, ArchivedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO property_archives").
+		WithArgs(7, "public-7", archive.OrganizationID, "Old House", "1 Main St", models.PropertyStatusSold,
+			models.ListingTypeSale, 0.0, 0, "public-7.tar.gz", int64(0), now, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM properties WHERE id = \\? AND \\(\\? = 0 OR version = \\?\\)").
+		WithArgs(7, 4, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO tombstones").WithArgs(models.ChangeEntityProperty, 7).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO tombstones").WithArgs(models.ChangeEntityPhotos, 7).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	// The listing changed since it was read: nothing is archived
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO property_archives").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM properties").WithArgs(7, 4, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := NewArchiveRepository(db)
+	if archived, err := repo.Archive(context.Background(), archive, 4); err != nil || !archived {
+		t.Fatalf("Expected the listing to be archived, got %v, %v", archived, err)
+	}
+	if archived, err := repo.Archive(context.Background(), archive, 4); err != nil || archived {
+		t.Fatalf("Expected the changed listing to be left alone, got %v, %v", archived, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestArchiveRepository_FindCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	cutoff := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM properties p WHERE p.status IN \\('sold', 'withdrawn'\\).*NOT EXISTS \\(SELECT 1 FROM offers.*AND p.updated_at < \\? ORDER BY p.updated_at, p.id LIMIT \\?").
+		WithArgs(cutoff, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "name", "status"}).AddRow(3, "public-3", "Old House", "sold"))
+
+	properties, err := NewArchiveRepository(db).FindCandidates(context.Background(), cutoff, 100)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(properties) != 1 || properties[0].ID != 3 || properties[0].PublicID != "public-3" {
+		t.Errorf("Unexpected candidates %+v", properties)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestArchiveRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	columns := []string{"property_id", "public_id", "organization_id", "name", "location", "status", "listing_type", "price",
+		"photo_count", "archive_path", "archive_bytes", "listed_at", "last_updated_at", "archived_at"}
+	mock.ExpectQuery("SELECT .* FROM property_archives WHERE public_id = \\? AND \\(organization_id IS NULL OR organization_id = \\?\\)").
+		WithArgs("public-7", 5).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(7, "public-7", 5, "Old House", "1 Main St", "sold", "sale", 250000.0, 3, "public-7.tar.gz", 2048, now, now, now))
+	mock.ExpectQuery("SELECT .* FROM property_archives WHERE property_id = \\?").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	repo := NewArchiveRepository(db)
+	ctx := WithTenant(context.Background(), Tenant{OrganizationID: 5})
+	archive, err := repo.Get(ctx, "public-7")
+	if err != nil || archive == nil {
+		t.Fatalf("Expected the archive, got %v, %v", archive, err)
+	}
+	if archive.PropertyID != 7 || archive.PhotoCount != 3 || archive.ArchiveBytes != 2048 || archive.OrganizationID.Int32 != 5 {
+		t.Errorf("Unexpected archive %+v", archive)
+	}
+	if archive, err := repo.Get(context.Background(), "8"); err != nil || archive != nil {
+		t.Errorf("Expected no archive, got %+v, %v", archive, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestArchiveRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	property := &models.Property{ID: 7, PublicID: "public-7", Name: "Old House", Version: 4,
+		Photos: models.PhotoList{{URL: "https://mls.example.com/front.jpg", LocalURL: "/images/front.jpg"}}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO properties \\(id, public_id, .*created_at, version, photos_updated_at\\)").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO property_photos").
+		WithArgs(7, 0, "https://mls.example.com/front.jpg", "/images/front.jpg", "", "", "", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM property_archives WHERE property_id = \\?").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewArchiveRepository(db).Restore(context.Background(), property); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if property.Version != 5 {
+		t.Errorf("Expected version 5, got %d", property.Version)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
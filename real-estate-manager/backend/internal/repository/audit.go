@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	query := `INSERT INTO audit_log (action, actor_id, impersonator_id, target_type, target_id, details, request_id) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	var details interface{}
+	if len(entry.Details) > 0 {
+		details = []byte(entry.Details)
+	}
+	result, err := r.db.ExecContext(ctx, query, entry.Action, entry.ActorID, entry.ImpersonatorID,
+		entry.TargetType, entry.TargetID, details, entry.RequestID)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	entry.ID = int(id)
+	return nil
+}
+
+// List returns matching entries, newest first
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	builder := sqlBuilder.Select("id, action, actor_id, impersonator_id, target_type, target_id, details, request_id, created_at").
+		From("audit_log").
+		OrderBy("id DESC").
+		Suffix("LIMIT ?", filter.Limit)
+	if filter.ActorID != 0 {
+		builder = builder.Where(sq.Eq{"actor_id": filter.ActorID})
+	}
+	if filter.ImpersonatorID != 0 {
+		builder = builder.Where(sq.Eq{"impersonator_id": filter.ImpersonatorID})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(sq.Eq{"action": filter.Action})
+	}
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var entry models.AuditEntry
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.ActorID, &entry.ImpersonatorID, &entry.TargetType,
+			&entry.TargetID, &details, &entry.RequestID, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.Details = details
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
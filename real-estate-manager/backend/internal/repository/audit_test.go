@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestAuditRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "action", "actor_id", "impersonator_id", "target_type", "target_id", "details", "request_id", "created_at",
+	}).AddRow(5, models.AuditImpersonatedRequest, 7, 1, nil, nil, []byte(`{"status":200}`), "req-1", time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM audit_log WHERE impersonator_id = \\? AND action = \\? ORDER BY id DESC LIMIT \\?").
+		WithArgs(1, models.AuditImpersonatedRequest, 50).
+		WillReturnRows(rows)
+
+	repo := NewAuditRepository(db)
+	entries, err := repo.List(context.Background(), models.AuditFilter{
+		ImpersonatorID: 1,
+		Action:         models.AuditImpersonatedRequest,
+		Limit:          50,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ActorID.Int32 != 7 || string(entries[0].Details) != `{"status":200}` {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+// CalendarRepository stores agents' calendar connections
+type CalendarRepository interface {
+	List(ctx context.Context, userID uint) ([]models.CalendarConnection, error)
+	Save(ctx context.Context, connection *models.CalendarConnection) error
+	Delete(ctx context.Context, userID uint, provider string) (bool, error)
+}
+
+type calendarRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewCalendarRepository creates the repository. OAuth tokens are encrypted
+// with cipher when it is not nil.
+func NewCalendarRepository(db *sql.DB, cipher *fieldcrypt.Cipher) CalendarRepository {
+	return &calendarRepository{db: db, cipher: cipher}
+}
+
+func (r *calendarRepository) List(ctx context.Context, userID uint) ([]models.CalendarConnection, error) {
+	query := `SELECT user_id, provider, access_token, refresh_token, expires_at, created_at
+		FROM calendar_connections WHERE user_id = ? ORDER BY provider`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	connections := []models.CalendarConnection{}
+	for rows.Next() {
+		var connection models.CalendarConnection
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&connection.UserID, &connection.Provider, &connection.AccessToken, &connection.RefreshToken,
+			&expiresAt, &connection.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := r.cipher.DecryptAll(&connection.AccessToken, &connection.RefreshToken); err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			connection.ExpiresAt = &expiresAt.Time
+		}
+		connections = append(connections, connection)
+	}
+	return connections, rows.Err()
+}
+
+// Save creates or replaces the connection of a user to a provider
+func (r *calendarRepository) Save(ctx context.Context, connection *models.CalendarConnection) error {
+	accessToken, err := r.cipher.Encrypt(connection.AccessToken)
+	if err != nil {
+		return err
+	}
+	refreshToken, err := r.cipher.Encrypt(connection.RefreshToken)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO calendar_connections (user_id, provider, access_token, refresh_token, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE access_token = VALUES(access_token), refresh_token = VALUES(refresh_token),
+		expires_at = VALUES(expires_at)`
+	_, err = r.db.ExecContext(ctx, query, connection.UserID, connection.Provider, accessToken,
+		refreshToken, connection.ExpiresAt)
+	return err
+}
+
+// Delete reports whether the connection existed
+func (r *calendarRepository) Delete(ctx context.Context, userID uint, provider string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM calendar_connections WHERE user_id = ? AND provider = ?`, userID, provider)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"real-estate-manager/backend/internal/models"
+	"sort"
+	"time"
+)
+
+// Sources of the changes feed, in the order changes sharing a timestamp are
+// reported. Tombstones come last so a delete follows an update made in the
+// same second.
+const (
+	changeSourceProperties = iota
+	changeSourcePhotos
+	changeSourceAgents
+	changeSourceTombstones
+)
+
+// changeSettleDelay keeps the most recent changes out of the feed until
+// transactions that started in the same second have committed, so a cursor
+// never skips a row that appears later with an earlier timestamp
+const changeSettleDelay = 2 * time.Second
+
+type ChangeRepository interface {
+	List(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error)
+	GetSyncToken(ctx context.Context, userID int, clientID string) (string, error)
+	SaveSyncToken(ctx context.Context, userID int, clientID, token string) error
+}
+
+type changeRepository struct {
+	db *sql.DB
+}
+
+func NewChangeRepository(db *sql.DB) ChangeRepository {
+	return &changeRepository{db: db}
+}
+
+// List returns up to limit changes positioned after `after`, in feed order.
+// Each source is read with the same keyset bound and the results are merged.
+func (r *changeRepository) List(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+	var changes []models.Change
+	for _, list := range []func(context.Context, models.ChangePosition, int) ([]models.Change, error){
+		r.listProperties, r.listPhotos, r.listAgents, r.listTombstones,
+	} {
+		found, err := list(ctx, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, found...)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i].Position, changes[j].Position
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Key < b.Key
+	})
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+// changedAfter builds the condition selecting rows of source positioned
+// after `after`, given the row's timestamp and key expressions, and excludes
+// rows still inside the settle delay
+func changedAfter(source int, at, key string, after models.ChangePosition) (string, []any) {
+	settled := fmt.Sprintf(" AND %s < NOW() - INTERVAL %d SECOND", at, int(changeSettleDelay.Seconds()))
+	switch {
+	case source > after.Source:
+		return at + " >= ?" + settled, []any{after.At}
+	case source == after.Source:
+		return "(" + at + " > ? OR (" + at + " = ? AND " + key + " > ?))" + settled, []any{after.At, after.At, after.Key}
+	default:
+		return at + " > ?" + settled, []any{after.At}
+	}
+}
+
+func (r *changeRepository) listProperties(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+	where, args := changedAfter(changeSourceProperties, "updated_at", "id", after)
+	properties, err := queryProperties(ctx, r.db, `SELECT `+propertyColumns+` 
+		FROM properties WHERE `+where+` ORDER BY updated_at, id LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]models.Change, 0, len(properties))
+	for i := range properties {
+		property := properties[i]
+		changes = append(changes, models.Change{
+			Entity:    models.ChangeEntityProperty,
+			ID:        property.ID,
+			Operation: models.ChangeUpsert,
+			ChangedAt: property.UpdatedAt,
+			Data:      &property,
+			Position:  models.ChangePosition{At: property.UpdatedAt, Source: changeSourceProperties, Key: property.ID},
+		})
+	}
+	return changes, nil
+}
+
+func (r *changeRepository) listPhotos(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+	where, args := changedAfter(changeSourcePhotos, "photos_updated_at", "id", after)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, photos, photos_updated_at FROM properties 
+		WHERE `+where+` ORDER BY photos_updated_at, id LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.Change
+	for rows.Next() {
+		var photos models.PropertyPhotos
+		var changedAt time.Time
+		if err := rows.Scan(&photos.PropertyID, &photos.Photos, &changedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, models.Change{
+			Entity:    models.ChangeEntityPhotos,
+			ID:        photos.PropertyID,
+			Operation: models.ChangeUpsert,
+			ChangedAt: changedAt,
+			Data:      photos,
+			Position:  models.ChangePosition{At: changedAt, Source: changeSourcePhotos, Key: photos.PropertyID},
+		})
+	}
+	return changes, rows.Err()
+}
+
+// listAgents reports users assigned to listings. An agent counts as changed
+// when their profile or any of their listings changed, so a client always
+// receives the agent of a listing it has just been sent.
+func (r *changeRepository) listAgents(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+	having, args := changedAfter(changeSourceAgents, "changed_at", "u.id", after)
+	rows, err := r.db.QueryContext(ctx, `SELECT u.id, u.username, u.email, 
+		GREATEST(u.updated_at, MAX(p.updated_at)) AS changed_at 
+		FROM users u JOIN properties p ON p.agent_id = u.id 
+		GROUP BY u.id, u.username, u.email, u.updated_at 
+		HAVING `+having+` ORDER BY changed_at, u.id LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.Change
+	for rows.Next() {
+		var agent models.Agent
+		var changedAt time.Time
+		if err := rows.Scan(&agent.ID, &agent.Username, &agent.Email, &changedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, models.Change{
+			Entity:    models.ChangeEntityAgent,
+			ID:        agent.ID,
+			Operation: models.ChangeUpsert,
+			ChangedAt: changedAt,
+			Data:      agent,
+			Position:  models.ChangePosition{At: changedAt, Source: changeSourceAgents, Key: agent.ID},
+		})
+	}
+	return changes, rows.Err()
+}
+
+func (r *changeRepository) listTombstones(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+	where, args := changedAfter(changeSourceTombstones, "deleted_at", "id", after)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, entity_type, entity_id, deleted_at FROM tombstones 
+		WHERE `+where+` ORDER BY deleted_at, id LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.Change
+	for rows.Next() {
+		var key int
+		change := models.Change{Operation: models.ChangeDelete}
+		if err := rows.Scan(&key, &change.Entity, &change.ID, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		change.Position = models.ChangePosition{At: change.ChangedAt, Source: changeSourceTombstones, Key: key}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// GetSyncToken returns the last sync token a client acknowledged, or "" when
+// the client has not synced before
+func (r *changeRepository) GetSyncToken(ctx context.Context, userID int, clientID string) (string, error) {
+	var token string
+	err := r.db.QueryRowContext(ctx, `SELECT sync_token FROM sync_clients WHERE user_id = ? AND client_id = ?`,
+		userID, clientID).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return token, err
+}
+
+func (r *changeRepository) SaveSyncToken(ctx context.Context, userID int, clientID, token string) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO sync_clients (user_id, client_id, sync_token) VALUES (?, ?, ?) 
+		ON DUPLICATE KEY UPDATE sync_token = VALUES(sync_token)`, userID, clientID, token)
+	return err
+}
+
+// insertTombstones records the deletion of an entity for the changes feed
+func insertTombstones(ctx context.Context, db dbtx, id int, entities ...string) error {
+	for _, entity := range entities {
+		if _, err := db.ExecContext(ctx, `INSERT INTO tombstones (entity_type, entity_id) VALUES (?, ?)`, entity, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
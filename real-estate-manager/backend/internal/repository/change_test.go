@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestChangeRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	after := models.ChangePosition{At: t0, Source: changeSourcePhotos, Key: 4}
+
+	// Sources ranked after the cursor's include its second, the cursor's own
+	// source resumes after its key, and earlier sources start a second later
+	mock.ExpectQuery("SELECT (.+) FROM properties WHERE updated_at > \\? AND updated_at < NOW\\(\\) - INTERVAL 2 SECOND ORDER BY updated_at, id LIMIT \\?").
+		WithArgs(t0, 3).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "location", "price", "description", "photos",
+			"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+			"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+			"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
+		}).AddRow(
+			9, "House 9", "Location 9", 500000.00,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+			7, nil, nil, "active", t0, t1, 1, nil,
+		))
+	mock.ExpectQuery("SELECT id, photos, photos_updated_at FROM properties WHERE \\(photos_updated_at > \\? OR \\(photos_updated_at = \\? AND id > \\?\\)\\)").
+		WithArgs(t0, t0, 4, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photos", "photos_updated_at"}).
+			AddRow(5, []byte(`[{"url": "a.jpg"}]`), t0).
+			AddRow(9, nil, t1))
+	mock.ExpectQuery("SELECT u.id, u.username, u.email, (.+) HAVING changed_at >= \\?").
+		WithArgs(t0, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "changed_at"}).
+			AddRow(7, "agent", "agent@example.com", t1))
+	mock.ExpectQuery("SELECT id, entity_type, entity_id, deleted_at FROM tombstones WHERE deleted_at >= \\?").
+		WithArgs(t0, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "deleted_at"}).
+			AddRow(1, models.ChangeEntityProperty, 3, t0))
+
+	repo := NewChangeRepository(db)
+	changes, err := repo.List(context.Background(), after, 3)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := []struct {
+		entity string
+		id     int
+		op     string
+	}{
+		{models.ChangeEntityPhotos, 5, models.ChangeUpsert},
+		{models.ChangeEntityProperty, 3, models.ChangeDelete},
+		{models.ChangeEntityProperty, 9, models.ChangeUpsert},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, want := range expected {
+		if changes[i].Entity != want.entity || changes[i].ID != want.id || changes[i].Operation != want.op {
+			t.Errorf("Change %d: expected %s %d %s, got %+v", i, want.entity, want.id, want.op, changes[i])
+		}
+	}
+	if photos, ok := changes[0].Data.(models.PropertyPhotos); !ok || len(photos.Photos) != 1 {
+		t.Errorf("Expected photo data, got %+v", changes[0].Data)
+	}
+	if changes[1].Data != nil {
+		t.Errorf("Expected tombstone without data, got %+v", changes[1].Data)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ContactRepository stores the contact book and the links from contacts
+// to leads, showings and deals. Contacts are scoped to the tenant in the
+// context like properties.
+type ContactRepository interface {
+	Create(ctx context.Context, contact *models.Contact) error
+	GetByID(ctx context.Context, id int) (*models.Contact, error)
+	List(ctx context.Context, filter models.ContactFilter) ([]models.Contact, error)
+	Update(ctx context.Context, contact *models.Contact) error
+	Delete(ctx context.Context, id int) (bool, error)
+	AddLink(ctx context.Context, link *models.ContactLink) error
+	RemoveLink(ctx context.Context, link models.ContactLink) (bool, error)
+}
+
+type contactRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewContactRepository creates the repository. Phone numbers are
+// encrypted with cipher when it is not nil.
+func NewContactRepository(db *sql.DB, cipher *fieldcrypt.Cipher) ContactRepository {
+	return &contactRepository{db: db, cipher: cipher}
+}
+
+const defaultContactLimit = 100
+
+// Create stores a contact. Contacts created for a tenant belong to its
+// organization, or are shared outside one.
+func (r *contactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	contact.OrganizationID = models.NullInt32{}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		contact.OrganizationID.Int32 = int32(tenant.OrganizationID)
+		contact.OrganizationID.Valid = tenant.OrganizationID != 0
+	}
+	phone, err := r.cipher.Encrypt(contact.Phone)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO contacts (organization_id, kind, name, email, phone, company, notes, tags, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, contact.OrganizationID, contact.Kind, contact.Name, contact.Email, phone,
+		contact.Company, contact.Notes, contact.Tags, contact.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	contact.ID = int(id)
+	return nil
+}
+
+// GetByID returns a contact visible to the tenant, with its links
+func (r *contactRepository) GetByID(ctx context.Context, id int) (*models.Contact, error) {
+	contacts, err := r.queryContacts(ctx, selectContacts().Where(sq.Eq{"id": id}).Where(tenantFilter(ctx)))
+	if err != nil {
+		return nil, err
+	}
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	contact := &contacts[0]
+	rows, err := r.db.QueryContext(ctx, `SELECT contact_id, entity_type, entity_id, created_at FROM contact_links
+		WHERE contact_id = ? ORDER BY created_at, entity_type, entity_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contact.Links = []models.ContactLink{}
+	for rows.Next() {
+		var link models.ContactLink
+		if err := rows.Scan(&link.ContactID, &link.EntityType, &link.EntityID, &link.CreatedAt); err != nil {
+			return nil, err
+		}
+		contact.Links = append(contact.Links, link)
+	}
+	return contact, rows.Err()
+}
+
+// List returns matching contacts by name
+func (r *contactRepository) List(ctx context.Context, filter models.ContactFilter) ([]models.Contact, error) {
+	query := selectContacts().Where(tenantFilter(ctx))
+	if filter.Kind != "" {
+		query = query.Where(sq.Eq{"kind": filter.Kind})
+	}
+	if filter.Tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", filter.Tag)
+	}
+	if filter.Q != "" {
+		pattern := "%" + escapeLike(filter.Q) + "%"
+		query = query.Where(sq.Or{sq.Like{"name": pattern}, sq.Like{"email": pattern}, sq.Like{"company": pattern}})
+	}
+	if filter.EntityType != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM contact_links l WHERE l.contact_id = contacts.id
+			AND l.entity_type = ? AND l.entity_id = ?)`, filter.EntityType, filter.EntityID)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultContactLimit
+	}
+	return r.queryContacts(ctx, query.OrderBy("name", "id").Limit(uint64(limit)))
+}
+
+// Update saves a contact's details. Its organization and creator do not
+// change.
+func (r *contactRepository) Update(ctx context.Context, contact *models.Contact) error {
+	phone, err := r.cipher.Encrypt(contact.Phone)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE contacts SET kind = ?, name = ?, email = ?, phone = ?, company = ?, notes = ?, tags = ? WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, query, contact.Kind, contact.Name, contact.Email, phone, contact.Company,
+		contact.Notes, contact.Tags, contact.ID)
+	return err
+}
+
+// Delete reports whether the contact existed; its links go with it
+func (r *contactRepository) Delete(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM contacts WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddLink links a contact to an entity. Linking it again keeps the
+// original link.
+func (r *contactRepository) AddLink(ctx context.Context, link *models.ContactLink) error {
+	query := `INSERT INTO contact_links (contact_id, entity_type, entity_id) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE contact_id = contact_id`
+	if _, err := r.db.ExecContext(ctx, query, link.ContactID, link.EntityType, link.EntityID); err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, `SELECT created_at FROM contact_links
+		WHERE contact_id = ? AND entity_type = ? AND entity_id = ?`, link.ContactID, link.EntityType, link.EntityID).
+		Scan(&link.CreatedAt)
+}
+
+// RemoveLink reports whether the link existed
+func (r *contactRepository) RemoveLink(ctx context.Context, link models.ContactLink) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM contact_links WHERE contact_id = ? AND entity_type = ? AND entity_id = ?`,
+		link.ContactID, link.EntityType, link.EntityID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func selectContacts() sq.SelectBuilder {
+	return sq.Select("id", "organization_id", "kind", "name", "email", "phone", "company", "notes", "tags",
+		"created_by", "created_at", "updated_at").From("contacts")
+}
+
+func (r *contactRepository) queryContacts(ctx context.Context, query sq.SelectBuilder) ([]models.Contact, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []models.Contact{}
+	for rows.Next() {
+		var contact models.Contact
+		if err := rows.Scan(&contact.ID, &contact.OrganizationID, &contact.Kind, &contact.Name, &contact.Email,
+			&contact.Phone, &contact.Company, &contact.Notes, &contact.Tags, &contact.CreatedBy, &contact.CreatedAt,
+			&contact.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := r.cipher.DecryptAll(&contact.Phone); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, rows.Err()
+}
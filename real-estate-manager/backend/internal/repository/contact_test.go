@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestContactRepository_CreateForTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	org := func(id int32) models.NullInt32 {
+		return models.NullInt32{NullInt32: sql.NullInt32{Int32: id, Valid: true}}
+	}
+	// The contact belongs to the tenant's organization whatever it says
+	mock.ExpectExec("INSERT INTO contacts").
+		WithArgs(org(4), "buyer", "Jane Roe", "jane@example.com", "+15551234567", "", "", models.StringList{"first-time"},
+			models.NullInt32{}).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	repo := NewContactRepository(db, nil)
+	contact := &models.Contact{OrganizationID: org(7), Kind: models.ContactBuyer, Name: "Jane Roe", Email: "jane@example.com",
+		Phone: "+15551234567", Tags: models.StringList{"first-time"}}
+	if err := repo.Create(WithTenant(context.Background(), Tenant{OrganizationID: 4}), contact); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if contact.ID != 9 || contact.OrganizationID.Int32 != 4 {
+		t.Errorf("Unexpected contact %+v", contact)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestContactRepository_List(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM contacts WHERE \\(organization_id IS NULL OR organization_id = \\?\\) AND kind = \\? "+
+		"AND JSON_CONTAINS\\(tags, JSON_QUOTE\\(\\?\\)\\) AND \\(name LIKE \\? OR email LIKE \\? OR company LIKE \\?\\) "+
+		"AND EXISTS \\(SELECT 1 FROM contact_links (.+)\\) ORDER BY name, id LIMIT 100").
+		WithArgs(4, "vendor", "plumber", "%50\\%%", "%50\\%%", "%50\\%%", "deal", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id", "kind", "name", "email", "phone", "company", "notes",
+			"tags", "created_by", "created_at", "updated_at"}).
+			AddRow(3, 4, "vendor", "Joe Pipes", "", "", "50% Plumbing", "", `["plumber"]`, 2, now, now))
+
+	repo := NewContactRepository(db, nil)
+	contacts, err := repo.List(WithTenant(context.Background(), Tenant{OrganizationID: 4}), models.ContactFilter{
+		Kind: models.ContactVendor, Tag: "plumber", Q: "50%", EntityType: models.ContactLinkDeal, EntityID: 7})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Company != "50% Plumbing" || len(contacts[0].Tags) != 1 {
+		t.Errorf("Unexpected contacts %+v", contacts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
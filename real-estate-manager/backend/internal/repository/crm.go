@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+// CRMRepository stores the CRM export state of leads and contacts
+type CRMRepository interface {
+	Get(ctx context.Context, crm, recordType, key string) (*models.CRMSyncRecord, error)
+	Save(ctx context.Context, record *models.CRMSyncRecord) error
+	List(ctx context.Context, crm, recordType, status string, limit int) ([]models.CRMSyncRecord, error)
+	CountByStatus(ctx context.Context, crm string) (map[string]int, error)
+	ListDueLeads(ctx context.Context, crm string, maxAttempts, limit int) ([]models.Lead, error)
+	ResetFailed(ctx context.Context, crm string) (int64, error)
+}
+
+type crmRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewCRMRepository creates the repository. cipher decrypts the phone
+// numbers of leads due for export.
+func NewCRMRepository(db *sql.DB, cipher *fieldcrypt.Cipher) CRMRepository {
+	return &crmRepository{db: db, cipher: cipher}
+}
+
+const crmRecordColumns = `crm, record_type, record_key, external_id, status, last_error, attempts, synced_at, updated_at`
+
+func (r *crmRepository) Get(ctx context.Context, crm, recordType, key string) (*models.CRMSyncRecord, error) {
+	query := `SELECT ` + crmRecordColumns + ` FROM crm_sync_records WHERE crm = ? AND record_type = ? AND record_key = ?`
+	var record models.CRMSyncRecord
+	if err := scanCRMRecord(r.db.QueryRowContext(ctx, query, crm, recordType, key), &record); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *crmRepository) Save(ctx context.Context, record *models.CRMSyncRecord) error {
+	lastError := record.LastError
+	if len(lastError) > 512 {
+		lastError = lastError[:512]
+	}
+	query := `INSERT INTO crm_sync_records (crm, record_type, record_key, external_id, status, last_error, attempts, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE external_id = VALUES(external_id), status = VALUES(status), last_error = VALUES(last_error),
+		attempts = VALUES(attempts), synced_at = VALUES(synced_at)`
+	_, err := r.db.ExecContext(ctx, query, record.CRM, record.RecordType, record.RecordKey, record.ExternalID, record.Status,
+		lastError, record.Attempts, record.SyncedAt)
+	return err
+}
+
+// List returns the most recently updated records, optionally of one type
+// or status
+func (r *crmRepository) List(ctx context.Context, crm, recordType, status string, limit int) ([]models.CRMSyncRecord, error) {
+	query := `SELECT ` + crmRecordColumns + ` FROM crm_sync_records WHERE crm = ?`
+	args := []any{crm}
+	if recordType != "" {
+		query += ` AND record_type = ?`
+		args = append(args, recordType)
+	}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY updated_at DESC, record_type, record_key LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.CRMSyncRecord{}
+	for rows.Next() {
+		var record models.CRMSyncRecord
+		if err := scanCRMRecord(rows, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// CountByStatus counts the records of each status. Leads that were never
+// exported are counted as "pending".
+func (r *crmRepository) CountByStatus(ctx context.Context, crm string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM crm_sync_records WHERE crm = ? GROUP BY status`, crm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var pending int
+	query := `SELECT COUNT(*) FROM leads l
+		LEFT JOIN crm_sync_records s ON s.crm = ? AND s.record_type = 'lead' AND s.record_key = CAST(l.id AS CHAR)
+		WHERE s.record_key IS NULL`
+	if err := r.db.QueryRowContext(ctx, query, crm).Scan(&pending); err != nil {
+		return nil, err
+	}
+	counts["pending"] = pending
+	return counts, nil
+}
+
+// ListDueLeads returns the oldest leads that were never exported, or whose
+// export failed fewer than maxAttempts times
+func (r *crmRepository) ListDueLeads(ctx context.Context, crm string, maxAttempts, limit int) ([]models.Lead, error) {
+	query := `SELECT l.id, l.source, l.external_id, l.name, l.email, l.phone, l.message, l.property_id, l.assigned_to, l.status, l.created_at
+		FROM leads l
+		LEFT JOIN crm_sync_records s ON s.crm = ? AND s.record_type = 'lead' AND s.record_key = CAST(l.id AS CHAR)
+		WHERE s.record_key IS NULL OR (s.status = ? AND s.attempts < ?)
+		ORDER BY l.id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, crm, models.CRMFailed, maxAttempts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leads := []models.Lead{}
+	for rows.Next() {
+		var lead models.Lead
+		if err := rows.Scan(&lead.ID, &lead.Source, &lead.ExternalID, &lead.Name, &lead.Email, &lead.Phone,
+			&lead.Message, &lead.PropertyID, &lead.AssignedTo, &lead.Status, &lead.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := r.cipher.DecryptAll(&lead.Phone); err != nil {
+			return nil, err
+		}
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+// ResetFailed gives failed records a fresh set of attempts and returns how
+// many there were
+func (r *crmRepository) ResetFailed(ctx context.Context, crm string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE crm_sync_records SET attempts = 0 WHERE crm = ? AND status = ?`, crm, models.CRMFailed)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanCRMRecord(row rowScanner, record *models.CRMSyncRecord) error {
+	var syncedAt sql.NullTime
+	if err := row.Scan(&record.CRM, &record.RecordType, &record.RecordKey, &record.ExternalID, &record.Status,
+		&record.LastError, &record.Attempts, &syncedAt, &record.UpdatedAt); err != nil {
+		return err
+	}
+	record.SyncedAt = nil
+	if syncedAt.Valid {
+		record.SyncedAt = &syncedAt.Time
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCRMRepository_ListDueLeads(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "source", "external_id", "name", "email", "phone", "message", "property_id", "assigned_to", "status", "created_at"}).
+		AddRow(30, "zillow", "z-81", "Jane", "jane@example.com", "", "", nil, 4, models.LeadStatusNew, time.Now())
+	// Leads without a record were never exported; failed ones are retried
+	// until they run out of attempts
+	mock.ExpectQuery("FROM leads l\\s+LEFT JOIN crm_sync_records s .* WHERE s.record_key IS NULL OR \\(s.status = \\? AND s.attempts < \\?\\)").
+		WithArgs("hubspot", models.CRMFailed, 5, 50).
+		WillReturnRows(rows)
+
+	repo := NewCRMRepository(db, nil)
+	leads, err := repo.ListDueLeads(context.Background(), "hubspot", 5, 50)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(leads) != 1 || leads[0].ID != 30 || leads[0].AssignedTo.Int32 != 4 || leads[0].PropertyID.Valid {
+		t.Errorf("Unexpected leads %+v", leads)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
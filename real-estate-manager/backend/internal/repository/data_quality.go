@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// DataQualityRepository runs the data-quality checks and stores their
+// reports
+type DataQualityRepository interface {
+	Find(ctx context.Context, check string, limit int) (int, []models.QualityIssue, error)
+	CreateReport(ctx context.Context, report *models.QualityReport) error
+	ListReports(ctx context.Context, limit int) ([]models.QualityReport, error)
+	DeleteReportsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// liveListing limits a check to listings on the market
+const liveListing = `p.status IN ('` + models.PropertyStatusActive + `', '` + models.PropertyStatusPending + `')`
+
+// qualityConditions are the listings each per-listing check finds.
+// Duplicate addresses are grouped and have a query of their own.
+var qualityConditions = map[string]string{
+	models.QualityZeroPrice: liveListing + ` AND ((p.listing_type = '` + models.ListingTypeRent + `' AND (p.monthly_rent IS NULL OR p.monthly_rent <= 0))
+		OR (p.listing_type <> '` + models.ListingTypeRent + `' AND p.price <= 0))`,
+	models.QualityMissingPhotos: liveListing + ` AND p.external_id IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM property_photos pp WHERE pp.property_id = p.id)`,
+	models.QualityGeocodeFailed: liveListing + ` AND EXISTS (SELECT 1 FROM property_enrichments e WHERE e.property_id = p.id AND e.latitude IS NULL)`,
+}
+
+type dataQualityRepository struct {
+	db *sql.DB
+}
+
+func NewDataQualityRepository(db *sql.DB) DataQualityRepository {
+	return &dataQualityRepository{db: db}
+}
+
+// Find runs a check and returns how many issues it found and the first
+// limit of them
+func (r *dataQualityRepository) Find(ctx context.Context, check string, limit int) (int, []models.QualityIssue, error) {
+	var query string
+	if check == models.QualityDuplicateAddress {
+		// Addresses are compared ignoring case and surrounding spaces, within
+		// an organization; shared listings count as one organization
+		query = `SELECT ids, location, COUNT(*) OVER () FROM (
+				SELECT GROUP_CONCAT(p.id ORDER BY p.id) AS ids, MIN(p.location) AS location, COUNT(*) AS listings
+				FROM properties p
+				WHERE ` + liveListing + ` AND TRIM(p.location) <> ''
+				GROUP BY IFNULL(p.organization_id, 0), LOWER(TRIM(p.location))
+				HAVING COUNT(*) > 1
+			) duplicates
+			ORDER BY listings DESC, location LIMIT ?`
+	} else {
+		condition, ok := qualityConditions[check]
+		if !ok {
+			return 0, nil, fmt.Errorf("unknown data-quality check %q", check)
+		}
+		query = `SELECT CAST(p.id AS CHAR), p.location, COUNT(*) OVER () FROM properties p WHERE ` + condition + ` ORDER BY p.id LIMIT ?`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	count := 0
+	issues := []models.QualityIssue{}
+	for rows.Next() {
+		var ids string
+		var issue models.QualityIssue
+		if err := rows.Scan(&ids, &issue.Location, &count); err != nil {
+			return 0, nil, err
+		}
+		for _, id := range strings.Split(ids, ",") {
+			propertyID, err := strconv.Atoi(id)
+			if err != nil {
+				return 0, nil, fmt.Errorf("unexpected property ID %q: %w", id, err)
+			}
+			issue.PropertyIDs = append(issue.PropertyIDs, propertyID)
+		}
+		issues = append(issues, issue)
+	}
+	return count, issues, rows.Err()
+}
+
+func (r *dataQualityRepository) CreateReport(ctx context.Context, report *models.QualityReport) error {
+	checks, err := json.Marshal(report.Checks)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `INSERT INTO data_quality_reports (checks, exceeded, created_at) VALUES (?, ?, ?)`,
+		checks, report.Exceeded, report.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	report.ID = id
+	return nil
+}
+
+// ListReports returns the most recent reports, newest first
+func (r *dataQualityRepository) ListReports(ctx context.Context, limit int) ([]models.QualityReport, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, checks, exceeded, created_at FROM data_quality_reports ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []models.QualityReport{}
+	for rows.Next() {
+		var report models.QualityReport
+		var checks []byte
+		if err := rows.Scan(&report.ID, &checks, &report.Exceeded, &report.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(checks, &report.Checks); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// DeleteReportsBefore removes reports made before cutoff and returns how
+// many were removed
+func (r *dataQualityRepository) DeleteReportsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM data_quality_reports WHERE created_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDataQualityRepository_Find(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT ids, location, COUNT\\(\\*\\) OVER \\(\\) FROM \\(.*GROUP BY IFNULL\\(p.organization_id, 0\\), LOWER\\(TRIM\\(p.location\\)\\)").
+		WithArgs(20).
+		WillReturnRows(sqlmock.NewRows([]string{"ids", "location", "count"}).
+			AddRow("4,9", "1 Main St", 2).
+			AddRow("5,6,7", "2 High St", 2))
+	mock.ExpectQuery("SELECT CAST\\(p.id AS CHAR\\), p.location, COUNT\\(\\*\\) OVER \\(\\) FROM properties p WHERE .*p.price <= 0").
+		WithArgs(20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "location", "count"}))
+
+	repo := NewDataQualityRepository(db)
+	count, issues, err := repo.Find(context.Background(), models.QualityDuplicateAddress, 20)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if count != 2 || len(issues) != 2 || len(issues[1].PropertyIDs) != 3 || issues[0].PropertyIDs[1] != 9 {
+		t.Errorf("Unexpected duplicates: %d, %+v", count, issues)
+	}
+	count, issues, err = repo.Find(context.Background(), models.QualityZeroPrice, 20)
+	if err != nil || count != 0 || len(issues) != 0 {
+		t.Errorf("Expected no issues, got (%d, %+v, %v)", count, issues, err)
+	}
+	if _, _, err := repo.Find(context.Background(), "unknown", 20); err == nil {
+		t.Error("Expected an unknown check to be rejected")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDataQualityRepository_Reports(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec("INSERT INTO data_quality_reports \\(checks, exceeded, created_at\\) VALUES \\(\\?, \\?, \\?\\)").
+		WithArgs(sqlmock.AnyArg(), true, now).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectQuery("SELECT id, checks, exceeded, created_at FROM data_quality_reports ORDER BY id DESC LIMIT \\?").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "checks", "exceeded", "created_at"}).
+			AddRow(3, `[{"check":"zero_price","count":1,"threshold":0,"exceeded":true,"samples":[]}]`, true, now))
+	mock.ExpectExec("DELETE FROM data_quality_reports WHERE created_at < \\?").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewDataQualityRepository(db)
+	report := &models.QualityReport{
+		Checks:    []models.QualityCheckResult{{Check: models.QualityZeroPrice, Count: 1, Exceeded: true}},
+		Exceeded:  true,
+		CreatedAt: now,
+	}
+	if err := repo.CreateReport(context.Background(), report); err != nil || report.ID != 3 {
+		t.Fatalf("Expected report 3, got (%d, %v)", report.ID, err)
+	}
+	reports, err := repo.ListReports(context.Background(), 10)
+	if err != nil || len(reports) != 1 || reports[0].Checks[0].Check != models.QualityZeroPrice {
+		t.Errorf("Unexpected reports: %+v, %v", reports, err)
+	}
+	if removed, err := repo.DeleteReportsBefore(context.Background(), now); err != nil || removed != 2 {
+		t.Errorf("Expected 2 reports removed, got (%d, %v)", removed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"strings"
+	"time"
+)
+
+// DealRepository stores deals, their commission splits and the pipeline
+// and revenue totals computed from them
+type DealRepository interface {
+	Create(ctx context.Context, deal *models.Deal) error
+	GetByID(ctx context.Context, id int) (*models.Deal, error)
+	List(ctx context.Context, filter models.DealFilter) ([]models.Deal, error)
+	Update(ctx context.Context, deal *models.Deal) error
+	Delete(ctx context.Context, id int) (bool, error)
+	Pipeline(ctx context.Context, agentID uint) ([]models.PipelineStage, error)
+	ClosedRevenue(ctx context.Context, from, to time.Time) ([]models.RevenuePeriod, error)
+	AgentRevenue(ctx context.Context, from, to time.Time) ([]models.AgentRevenue, error)
+	ExpectedRevenue(ctx context.Context, from, to time.Time) ([]models.RevenuePeriod, error)
+}
+
+type dealRepository struct {
+	db *sql.DB
+}
+
+func NewDealRepository(db *sql.DB) DealRepository {
+	return &dealRepository{db: db}
+}
+
+const dealColumns = `d.id, d.property_id, d.stage, d.price, d.commission_percent, d.expected_close_date, d.closed_at,
+	d.notes, d.created_by, d.created_at, d.updated_at`
+
+// dealCommission is the gross commission of a deal d in SQL
+const dealCommission = `d.price * d.commission_percent / 100`
+
+// dealTenant returns the join limiting deals d to those on properties the
+// tenant in ctx can see, with its arguments. Without a tenant it is empty,
+// so every deal is included, also those whose listing was deleted.
+func dealTenant(ctx context.Context) (string, []any, error) {
+	tenant := tenantFilterOn(ctx, "p.organization_id")
+	if tenant == nil {
+		return "", nil, nil
+	}
+	condition, args, err := tenant.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return ` JOIN properties p ON p.id = d.property_id AND ` + condition, args, nil
+}
+
+// Create inserts a deal and its splits in one transaction
+func (r *dealRepository) Create(ctx context.Context, deal *models.Deal) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO deals (property_id, stage, price, commission_percent, expected_close_date, closed_at, notes, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, query, deal.PropertyID, deal.Stage, deal.Price, deal.CommissionPercent,
+		deal.ExpectedCloseDate, deal.ClosedAt, deal.Notes, deal.CreatedBy)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := insertSplits(ctx, tx, int(id), deal.Splits); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	deal.ID = int(id)
+	return nil
+}
+
+func (r *dealRepository) GetByID(ctx context.Context, id int) (*models.Deal, error) {
+	join, args, err := dealTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row := r.db.QueryRowContext(ctx, `SELECT `+dealColumns+` FROM deals d`+join+` WHERE d.id = ?`, append(args, id)...)
+	var deal models.Deal
+	if err := scanDeal(row, &deal); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	deals := []models.Deal{deal}
+	if err := r.loadSplits(ctx, deals); err != nil {
+		return nil, err
+	}
+	return &deals[0], nil
+}
+
+// List returns matching deals by expected close date, soonest first, with
+// deals without one last
+func (r *dealRepository) List(ctx context.Context, filter models.DealFilter) ([]models.Deal, error) {
+	join, args, err := dealTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + dealColumns + ` FROM deals d` + join + ` WHERE 1 = 1`
+	if filter.Stage != "" {
+		query += ` AND d.stage = ?`
+		args = append(args, filter.Stage)
+	}
+	if filter.PropertyID != 0 {
+		query += ` AND d.property_id = ?`
+		args = append(args, filter.PropertyID)
+	}
+	if filter.AgentID != 0 {
+		query += ` AND EXISTS (SELECT 1 FROM deal_commission_splits s WHERE s.deal_id = d.id AND s.agent_id = ?)`
+		args = append(args, filter.AgentID)
+	}
+	query += ` ORDER BY d.expected_close_date IS NULL, d.expected_close_date, d.id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deals := []models.Deal{}
+	for rows.Next() {
+		var deal models.Deal
+		if err := scanDeal(rows, &deal); err != nil {
+			return nil, err
+		}
+		deals = append(deals, deal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadSplits(ctx, deals); err != nil {
+		return nil, err
+	}
+	return deals, nil
+}
+
+// Update saves a deal and replaces its splits in one transaction
+func (r *dealRepository) Update(ctx context.Context, deal *models.Deal) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE deals SET stage = ?, price = ?, commission_percent = ?, expected_close_date = ?, closed_at = ?, notes = ?
+		WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, query, deal.Stage, deal.Price, deal.CommissionPercent, deal.ExpectedCloseDate,
+		deal.ClosedAt, deal.Notes, deal.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM deal_commission_splits WHERE deal_id = ?`, deal.ID); err != nil {
+		return err
+	}
+	if err := insertSplits(ctx, tx, deal.ID, deal.Splits); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *dealRepository) Delete(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM deals WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Pipeline totals the deals in each stage, optionally only those an agent
+// has a share in. Stages without deals are left out.
+func (r *dealRepository) Pipeline(ctx context.Context, agentID uint) ([]models.PipelineStage, error) {
+	join, args, err := dealTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT d.stage, COUNT(*), COALESCE(SUM(d.price), 0), COALESCE(SUM(` + dealCommission + `), 0) FROM deals d` + join
+	if agentID != 0 {
+		query += ` WHERE EXISTS (SELECT 1 FROM deal_commission_splits s WHERE s.deal_id = d.id AND s.agent_id = ?)`
+		args = append(args, agentID)
+	}
+	query += ` GROUP BY d.stage`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := []models.PipelineStage{}
+	for rows.Next() {
+		var stage models.PipelineStage
+		if err := rows.Scan(&stage.Stage, &stage.Deals, &stage.Volume, &stage.Commission); err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+	return stages, rows.Err()
+}
+
+// ClosedRevenue totals the deals closed from from until to by month
+func (r *dealRepository) ClosedRevenue(ctx context.Context, from, to time.Time) ([]models.RevenuePeriod, error) {
+	join, args, err := dealTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT DATE_FORMAT(d.closed_at, '%Y-%m') AS period, COUNT(*), SUM(d.price), SUM(` + dealCommission + `)
+		FROM deals d` + join + ` WHERE d.stage = ? AND d.closed_at >= ? AND d.closed_at < ?
+		GROUP BY period ORDER BY period`
+	return r.queryPeriods(ctx, query, append(args, models.DealClosed, from, to)...)
+}
+
+// AgentRevenue totals each agent's share of the commission on deals closed
+// from from until to, highest first
+func (r *dealRepository) AgentRevenue(ctx context.Context, from, to time.Time) ([]models.AgentRevenue, error) {
+	join, args, err := dealTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT s.agent_id, COUNT(*), SUM(` + dealCommission + ` * s.percent / 100) AS commission
+		FROM deals d JOIN deal_commission_splits s ON s.deal_id = d.id` + join + `
+		WHERE d.stage = ? AND d.closed_at >= ? AND d.closed_at < ?
+		GROUP BY s.agent_id ORDER BY commission DESC, s.agent_id`
+	rows, err := r.db.QueryContext(ctx, query, append(args, models.DealClosed, from, to)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	agents := []models.AgentRevenue{}
+	for rows.Next() {
+		var agent models.AgentRevenue
+		if err := rows.Scan(&agent.AgentID, &agent.Deals, &agent.Commission); err != nil {
+			return nil, err
+		}
+		agents = append(agents, agent)
+	}
+	return agents, rows.Err()
+}
+
+// ExpectedRevenue totals the open deals expected to close from from until
+// to by month
+func (r *dealRepository) ExpectedRevenue(ctx context.Context, from, to time.Time) ([]models.RevenuePeriod, error) {
+	join, args, err := dealTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT DATE_FORMAT(d.expected_close_date, '%Y-%m') AS period, COUNT(*), SUM(d.price), SUM(` + dealCommission + `)
+		FROM deals d` + join + ` WHERE d.stage IN (?, ?) AND d.expected_close_date >= ? AND d.expected_close_date < ?
+		GROUP BY period ORDER BY period`
+	return r.queryPeriods(ctx, query, append(args, models.DealOffer, models.DealUnderContract,
+		from.Format(models.DateLayout), to.Format(models.DateLayout))...)
+}
+
+func (r *dealRepository) queryPeriods(ctx context.Context, query string, args ...any) ([]models.RevenuePeriod, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []models.RevenuePeriod{}
+	for rows.Next() {
+		var period models.RevenuePeriod
+		if err := rows.Scan(&period.Period, &period.Deals, &period.Volume, &period.Commission); err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	return periods, rows.Err()
+}
+
+// loadSplits fills in the commission splits of deals
+func (r *dealRepository) loadSplits(ctx context.Context, deals []models.Deal) error {
+	if len(deals) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(deals))
+	placeholders := make([]string, len(deals))
+	args := make([]any, len(deals))
+	for i := range deals {
+		deals[i].Splits = []models.CommissionSplit{}
+		index[deals[i].ID] = i
+		placeholders[i] = "?"
+		args[i] = deals[i].ID
+	}
+
+	query := `SELECT deal_id, agent_id, role, percent FROM deal_commission_splits
+		WHERE deal_id IN (` + strings.Join(placeholders, ", ") + `) ORDER BY deal_id, percent DESC, agent_id`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dealID int
+		var split models.CommissionSplit
+		if err := rows.Scan(&dealID, &split.AgentID, &split.Role, &split.Percent); err != nil {
+			return err
+		}
+		if i, ok := index[dealID]; ok {
+			deals[i].Splits = append(deals[i].Splits, split)
+		}
+	}
+	return rows.Err()
+}
+
+func insertSplits(ctx context.Context, tx *sql.Tx, dealID int, splits []models.CommissionSplit) error {
+	for _, split := range splits {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO deal_commission_splits (deal_id, agent_id, role, percent) VALUES (?, ?, ?, ?)`,
+			dealID, split.AgentID, split.Role, split.Percent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanDeal(row rowScanner, deal *models.Deal) error {
+	return row.Scan(&deal.ID, &deal.PropertyID, &deal.Stage, &deal.Price, &deal.CommissionPercent, &deal.ExpectedCloseDate,
+		&deal.ClosedAt, &deal.Notes, &deal.CreatedBy, &deal.CreatedAt, &deal.UpdatedAt)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDealRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	expected := models.NullDate{NullTime: sql.NullTime{Time: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC), Valid: true}}
+	mock.ExpectBegin()
+	// The expected close date is written as a plain date
+	mock.ExpectExec("INSERT INTO deals").
+		WithArgs(12, models.DealOffer, 400000.0, 3.0, "2024-07-15", models.NullTime{}, "", models.NullInt32{}).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO deal_commission_splits").WithArgs(7, uint(4), "listing", 60.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO deal_commission_splits").WithArgs(7, uint(5), "buyer", 40.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewDealRepository(db)
+	deal := &models.Deal{PropertyID: 12, Stage: models.DealOffer, Price: 400000, CommissionPercent: 3, ExpectedCloseDate: expected,
+		Splits: []models.CommissionSplit{{AgentID: 4, Role: "listing", Percent: 60}, {AgentID: 5, Role: "buyer", Percent: 40}}}
+	if err := repo.Create(context.Background(), deal); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if deal.ID != 7 {
+		t.Errorf("Expected deal ID 7, got %d", deal.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDealRepository_AgentRevenue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"agent_id", "deals", "commission"}).AddRow(4, 2, "16200.00")
+	// Each agent gets their percent of the deal's commission
+	mock.ExpectQuery("SUM\\(d.price \\* d.commission_percent / 100 \\* s.percent / 100\\) AS commission\\s+FROM deals d JOIN deal_commission_splits s").
+		WithArgs(models.DealClosed, from, to).
+		WillReturnRows(rows)
+
+	repo := NewDealRepository(db)
+	agents, err := repo.AgentRevenue(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(agents) != 1 || agents[0].AgentID != 4 || agents[0].Commission != 16200 {
+		t.Errorf("Unexpected revenue %+v", agents)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDealRepository_Tenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Deals are limited to the listings the tenant can see
+	ctx := WithTenant(context.Background(), Tenant{OrganizationID: 5})
+	mock.ExpectQuery("FROM deals d JOIN properties p ON p.id = d.property_id AND \\(p.organization_id IS NULL OR p.organization_id = \\?\\) WHERE d.id = \\?").
+		WithArgs(5, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("FROM deals d JOIN properties p ON p.id = d.property_id AND \\(p.organization_id IS NULL OR p.organization_id = \\?\\) "+
+		"WHERE EXISTS \\(SELECT 1 FROM deal_commission_splits s WHERE s.deal_id = d.id AND s.agent_id = \\?\\) GROUP BY d.stage").
+		WithArgs(5, uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"stage", "deals", "volume", "commission"}))
+
+	repo := NewDealRepository(db)
+	if deal, err := repo.GetByID(ctx, 7); err != nil || deal != nil {
+		t.Errorf("Expected no deal, got %+v, %v", deal, err)
+	}
+	if stages, err := repo.Pipeline(ctx, 4); err != nil || len(stages) != 0 {
+		t.Errorf("Expected no stages, got %+v, %v", stages, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+// EmailSuppressionRepository stores addresses that must not be emailed. It
+// satisfies mailer.SuppressionList.
+type EmailSuppressionRepository interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	Suppress(ctx context.Context, email, reason string) error
+	List(ctx context.Context) ([]models.EmailSuppression, error)
+	Delete(ctx context.Context, email string) (bool, error)
+}
+
+type emailSuppressionRepository struct {
+	db *sql.DB
+}
+
+func NewEmailSuppressionRepository(db *sql.DB) EmailSuppressionRepository {
+	return &emailSuppressionRepository{db: db}
+}
+
+func (r *emailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM email_suppressions WHERE email = ?`, email).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Suppress adds email to the list, keeping the original reason if it is
+// already there
+func (r *emailSuppressionRepository) Suppress(ctx context.Context, email, reason string) error {
+	if len(reason) > 512 {
+		reason = reason[:512]
+	}
+	_, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO email_suppressions (email, reason) VALUES (?, ?)`, email, reason)
+	return err
+}
+
+func (r *emailSuppressionRepository) List(ctx context.Context) ([]models.EmailSuppression, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT email, reason, created_at FROM email_suppressions ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppressions := []models.EmailSuppression{}
+	for rows.Next() {
+		var suppression models.EmailSuppression
+		if err := rows.Scan(&suppression.Email, &suppression.Reason, &suppression.CreatedAt); err != nil {
+			return nil, err
+		}
+		suppressions = append(suppressions, suppression)
+	}
+	return suppressions, rows.Err()
+}
+
+// Delete removes email from the list and reports whether it was there
+func (r *emailSuppressionRepository) Delete(ctx context.Context, email string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM email_suppressions WHERE email = ?`, email)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
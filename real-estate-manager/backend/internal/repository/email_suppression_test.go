@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestEmailSuppressionRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT IGNORE INTO email_suppressions \\(email, reason\\) VALUES \\(\\?, \\?\\)").
+		WithArgs("a@example.com", "550 no such user").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM email_suppressions WHERE email = \\?").
+		WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec("DELETE FROM email_suppressions WHERE email = \\?").
+		WithArgs("b@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewEmailSuppressionRepository(db)
+	if err := repo.Suppress(context.Background(), "a@example.com", "550 no such user"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	suppressed, err := repo.IsSuppressed(context.Background(), "a@example.com")
+	if err != nil || !suppressed {
+		t.Errorf("Expected a@example.com to be suppressed, got (%v, %v)", suppressed, err)
+	}
+	removed, err := repo.Delete(context.Background(), "b@example.com")
+	if err != nil || removed {
+		t.Errorf("Expected nothing removed, got (%v, %v)", removed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// EmailTokenRepository stores single-use tokens emailed to users. Each kind
+// of token keeps its own table with the same columns.
+type EmailTokenRepository interface {
+	Create(ctx context.Context, token *models.EmailToken) error
+	Consume(ctx context.Context, tokenHash string, now time.Time) (*models.EmailToken, error)
+	InvalidateUser(ctx context.Context, userID uint, now time.Time) error
+}
+
+type emailTokenRepository struct {
+	db    *sql.DB
+	table string
+}
+
+// NewMagicLinkRepository stores passwordless login links
+func NewMagicLinkRepository(db *sql.DB) EmailTokenRepository {
+	return &emailTokenRepository{db: db, table: "magic_links"}
+}
+
+// NewPasswordResetRepository stores password reset tokens
+func NewPasswordResetRepository(db *sql.DB) EmailTokenRepository {
+	return &emailTokenRepository{db: db, table: "password_resets"}
+}
+
+func (r *emailTokenRepository) Create(ctx context.Context, token *models.EmailToken) error {
+	query := `INSERT INTO ` + r.table + ` (token_hash, user_id, expires_at) VALUES (?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, token.TokenHash, token.UserID, token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	token.ID = int(id)
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it. The
+// conditional update makes each token single-use even under concurrent
+// requests. A missing, used or expired token is reported as (nil, nil).
+func (r *emailTokenRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (*models.EmailToken, error) {
+	update := `UPDATE ` + r.table + ` SET used_at = ? WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?`
+	result, err := r.db.ExecContext(ctx, update, now, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, token_hash, user_id, expires_at, used_at, created_at FROM ` + r.table + ` WHERE token_hash = ?`
+	var token models.EmailToken
+	err = r.db.QueryRowContext(ctx, query, tokenHash).Scan(&token.ID, &token.TokenHash, &token.UserID,
+		&token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// InvalidateUser marks the user's unused tokens as used, e.g. once the
+// password has been changed with one of them
+func (r *emailTokenRepository) InvalidateUser(ctx context.Context, userID uint, now time.Time) error {
+	query := `UPDATE ` + r.table + ` SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, now, userID)
+	return err
+}
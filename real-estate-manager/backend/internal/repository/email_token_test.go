@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var emailTokenTables = []struct {
+	table string
+	new   func(db *sql.DB) EmailTokenRepository
+}{
+	{table: "magic_links", new: NewMagicLinkRepository},
+	{table: "password_resets", new: NewPasswordResetRepository},
+}
+
+func TestEmailTokenRepository_Create(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+
+	for _, tt := range emailTokenTables {
+		t.Run(tt.table, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("INSERT INTO "+tt.table+" \\(token_hash, user_id, expires_at\\)").
+				WithArgs("hash", 7, expiresAt).
+				WillReturnResult(sqlmock.NewResult(3, 1))
+
+			token := &models.EmailToken{TokenHash: "hash", UserID: 7, ExpiresAt: expiresAt}
+			if err := tt.new(db).Create(context.Background(), token); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if token.ID != 3 {
+				t.Errorf("Expected ID 3, got %d", token.ID)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestEmailTokenRepository_Consume(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range emailTokenTables {
+		t.Run(tt.table+" unused token", func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE "+tt.table+" SET used_at = \\? WHERE token_hash = \\? AND used_at IS NULL AND expires_at > \\?").
+				WithArgs(now, "hash", now).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("SELECT (.+) FROM " + tt.table + " WHERE token_hash = ?").
+				WithArgs("hash").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "token_hash", "user_id", "expires_at", "used_at", "created_at"}).
+					AddRow(1, "hash", 7, now.Add(time.Minute), now, now))
+
+			token, err := tt.new(db).Consume(context.Background(), "hash", now)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if token == nil || token.UserID != 7 {
+				t.Errorf("Unexpected token: %+v", token)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+
+		t.Run(tt.table+" used or expired token", func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE "+tt.table).WithArgs(now, "hash", now).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			token, err := tt.new(db).Consume(context.Background(), "hash", now)
+			if err != nil || token != nil {
+				t.Errorf("Expected (nil, nil), got (%+v, %v)", token, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestEmailTokenRepository_InvalidateUser(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range emailTokenTables {
+		t.Run(tt.table, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE "+tt.table+" SET used_at = \\? WHERE user_id = \\? AND used_at IS NULL").
+				WithArgs(now, 7).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			if err := tt.new(db).InvalidateUser(context.Background(), 7, now); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
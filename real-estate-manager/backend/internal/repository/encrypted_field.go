@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+// EncryptedFieldRepository encrypts sensitive columns again with the
+// primary key, after a key rotation or once encryption is enabled on a
+// database holding plaintext
+type EncryptedFieldRepository interface {
+	Rotate(ctx context.Context, limit int) (int, error)
+}
+
+// encryptedColumn is a column encrypted by a repository, with the columns
+// identifying its rows
+type encryptedColumn struct {
+	table  string
+	keys   []string
+	column string
+}
+
+var encryptedColumns = []encryptedColumn{
+	{table: "leads", keys: []string{"id"}, column: "phone"},
+	{table: "notification_preferences", keys: []string{"user_id"}, column: "phone"},
+	{table: "calendar_connections", keys: []string{"user_id", "provider"}, column: "access_token"},
+	{table: "calendar_connections", keys: []string{"user_id", "provider"}, column: "refresh_token"},
+	{table: "offers", keys: []string{"id"}, column: "buyer_phone"},
+	{table: "contacts", keys: []string{"id"}, column: "phone"},
+}
+
+type encryptedFieldRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+func NewEncryptedFieldRepository(db *sql.DB, cipher *fieldcrypt.Cipher) EncryptedFieldRepository {
+	return &encryptedFieldRepository{db: db, cipher: cipher}
+}
+
+// Rotate encrypts up to limit values per column that are plaintext or were
+// encrypted with an older key, and returns how many it changed. A value
+// changed concurrently is left for the next call.
+func (r *encryptedFieldRepository) Rotate(ctx context.Context, limit int) (int, error) {
+	if r.cipher == nil {
+		return 0, nil
+	}
+	rotated := 0
+	for _, column := range encryptedColumns {
+		count, err := r.rotateColumn(ctx, column, limit)
+		rotated += count
+		if err != nil {
+			return rotated, err
+		}
+	}
+	return rotated, nil
+}
+
+func (r *encryptedFieldRepository) rotateColumn(ctx context.Context, column encryptedColumn, limit int) (int, error) {
+	keys := strings.Join(column.keys, ", ")
+	query := `SELECT ` + keys + `, ` + column.column + ` FROM ` + column.table +
+		` WHERE ` + column.column + ` <> '' AND ` + column.column + ` NOT LIKE ? ORDER BY ` + keys + ` LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, r.cipher.PrimaryPrefix()+"%", limit)
+	if err != nil {
+		return 0, err
+	}
+	type row struct {
+		keys  []any
+		value string
+	}
+	var stale []row
+	for rows.Next() {
+		current := row{keys: make([]any, len(column.keys))}
+		dest := make([]any, 0, len(column.keys)+1)
+		for i := range current.keys {
+			dest = append(dest, &current.keys[i])
+		}
+		if err := rows.Scan(append(dest, &current.value)...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		stale = append(stale, current)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	update := `UPDATE ` + column.table + ` SET ` + column.column + ` = ? WHERE ` +
+		strings.Join(column.keys, ` = ? AND `) + ` = ? AND ` + column.column + ` = ?`
+	rotated := 0
+	for _, current := range stale {
+		plaintext, err := r.cipher.Decrypt(current.value)
+		if err != nil {
+			return rotated, err
+		}
+		encrypted, err := r.cipher.Encrypt(plaintext)
+		if err != nil {
+			return rotated, err
+		}
+		args := append(append([]any{encrypted}, current.keys...), current.value)
+		result, err := r.db.ExecContext(ctx, update, args...)
+		if err != nil {
+			return rotated, err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			rotated++
+		}
+	}
+	return rotated, nil
+}
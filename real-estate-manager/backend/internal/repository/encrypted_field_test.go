@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"real-estate-manager/backend/pkg/fieldcrypt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestEncryptedFieldRepository_Rotate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	cipher, err := fieldcrypt.Parse("k2:" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A lead stored before encryption was enabled is encrypted in place,
+	// unless it changed since it was read
+	mock.ExpectQuery("SELECT id, phone FROM leads WHERE phone <> '' AND phone NOT LIKE \\? ORDER BY id LIMIT \\?").
+		WithArgs("enc:v1:k2:%", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "phone"}).AddRow(7, "+15551234567"))
+	mock.ExpectExec("UPDATE leads SET phone = \\? WHERE id = \\? AND phone = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(7), "+15551234567").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT user_id, phone FROM notification_preferences").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "phone"}))
+	mock.ExpectQuery("SELECT user_id, provider, access_token FROM calendar_connections").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "provider", "access_token"}))
+	mock.ExpectQuery("SELECT user_id, provider, refresh_token FROM calendar_connections").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "provider", "refresh_token"}))
+	mock.ExpectQuery("SELECT id, buyer_phone FROM offers").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "buyer_phone"}))
+	mock.ExpectQuery("SELECT id, phone FROM contacts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "phone"}))
+
+	repo := NewEncryptedFieldRepository(db, cipher)
+	rotated, err := repo.Rotate(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if rotated != 1 {
+		t.Errorf("Expected 1 rotated value, got %d", rotated)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
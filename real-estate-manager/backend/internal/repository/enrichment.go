@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"time"
+)
+
+type EnrichmentRepository interface {
+	GetByPropertyID(ctx context.Context, propertyID int) (*models.Enrichment, error)
+	Upsert(ctx context.Context, enrichment *models.Enrichment) error
+	FindDue(ctx context.Context, enrichedBefore time.Time, limit int) ([]models.Property, error)
+}
+
+type enrichmentRepository struct {
+	db *sql.DB
+}
+
+func NewEnrichmentRepository(db *sql.DB) EnrichmentRepository {
+	return &enrichmentRepository{db: db}
+}
+
+func (r *enrichmentRepository) GetByPropertyID(ctx context.Context, propertyID int) (*models.Enrichment, error) {
+	query := `SELECT property_id, latitude, longitude, school_district, walk_score, neighborhood, enriched_at 
+		FROM property_enrichments WHERE property_id = ?`
+
+	var enrichment models.Enrichment
+	if err := r.db.QueryRowContext(ctx, query, propertyID).Scan(&enrichment.PropertyID, &enrichment.Latitude,
+		&enrichment.Longitude, &enrichment.SchoolDistrict, &enrichment.WalkScore, &enrichment.Neighborhood,
+		&enrichment.EnrichedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &enrichment, nil
+}
+
+func (r *enrichmentRepository) Upsert(ctx context.Context, enrichment *models.Enrichment) error {
+	query := `INSERT INTO property_enrichments 
+		(property_id, latitude, longitude, school_district, walk_score, neighborhood, enriched_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?) 
+		ON DUPLICATE KEY UPDATE latitude = VALUES(latitude), longitude = VALUES(longitude), 
+		school_district = VALUES(school_district), walk_score = VALUES(walk_score), 
+		neighborhood = VALUES(neighborhood), enriched_at = VALUES(enriched_at)`
+	_, err := r.db.ExecContext(ctx, query, enrichment.PropertyID, enrichment.Latitude, enrichment.Longitude,
+		enrichment.SchoolDistrict, enrichment.WalkScore, enrichment.Neighborhood, enrichment.EnrichedAt)
+	return err
+}
+
+// FindDue returns properties never enriched or last enriched before
+// enrichedBefore, oldest first
+func (r *enrichmentRepository) FindDue(ctx context.Context, enrichedBefore time.Time, limit int) ([]models.Property, error) {
+	query := `SELECT ` + propertyColumns + ` 
+		FROM properties LEFT JOIN property_enrichments ON property_enrichments.property_id = properties.id 
+		WHERE enriched_at IS NULL OR enriched_at < ? 
+		ORDER BY enriched_at IS NOT NULL, enriched_at, id LIMIT ?`
+	return queryProperties(ctx, r.db, query, enrichedBefore, limit)
+}
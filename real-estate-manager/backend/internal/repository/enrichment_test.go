@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestEnrichmentRepository_FindDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "location", "price", "description", "photos",
+		"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+		"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+		"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
+	}).AddRow(
+		3, "House 3", "3 Elm St", 300000.00,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, "active", cutoff, cutoff, 1, nil,
+	)
+	mock.ExpectQuery("SELECT (.+) FROM properties LEFT JOIN property_enrichments (.+) WHERE enriched_at IS NULL OR enriched_at < ?").
+		WithArgs(cutoff, 10).
+		WillReturnRows(rows)
+
+	repo := NewEnrichmentRepository(db)
+	properties, err := repo.FindDue(context.Background(), cutoff, 10)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(properties) != 1 || properties[0].Location != "3 Elm St" {
+		t.Errorf("Unexpected properties: %+v", properties)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
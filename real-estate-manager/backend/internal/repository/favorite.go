@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+type FavoriteRepository interface {
+	Add(ctx context.Context, userID uint, propertyID int) error
+	Remove(ctx context.Context, userID uint, propertyID int) (bool, error)
+	List(ctx context.Context, userID uint) ([]models.Favorite, error)
+}
+
+type favoriteRepository struct {
+	db *sql.DB
+}
+
+func NewFavoriteRepository(db *sql.DB) FavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+// Add saves a property as a user's favorite; saving it again changes nothing
+func (r *favoriteRepository) Add(ctx context.Context, userID uint, propertyID int) error {
+	_, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO user_favorites (user_id, property_id) VALUES (?, ?)`, userID, propertyID)
+	return err
+}
+
+func (r *favoriteRepository) Remove(ctx context.Context, userID uint, propertyID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM user_favorites WHERE user_id = ? AND property_id = ?`, userID, propertyID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// List returns a user's favorites, most recently saved first
+func (r *favoriteRepository) List(ctx context.Context, userID uint) ([]models.Favorite, error) {
+	query := `SELECT ` + propertyColumns + `, f.created_at
+		FROM user_favorites f JOIN properties ON properties.id = f.property_id
+		WHERE f.user_id = ? ORDER BY f.created_at DESC, f.property_id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	favorites := []models.Favorite{}
+	for rows.Next() {
+		var favorite models.Favorite
+		if err := scanProperty(extraScanner{row: rows, extra: []any{&favorite.FavoritedAt}}, &favorite.Property); err != nil {
+			return nil, err
+		}
+		favorites = append(favorites, favorite)
+	}
+	return favorites, rows.Err()
+}
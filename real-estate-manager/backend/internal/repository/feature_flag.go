@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+type FeatureFlagRepository interface {
+	GetAll(ctx context.Context) ([]models.FeatureFlag, error)
+	Upsert(ctx context.Context, flag *models.FeatureFlag) error
+}
+
+type featureFlagRepository struct {
+	db *sql.DB
+}
+
+func NewFeatureFlagRepository(db *sql.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+func (r *featureFlagRepository) GetAll(ctx context.Context) ([]models.FeatureFlag, error) {
+	query := `SELECT name, enabled, description, updated_by, updated_at FROM feature_flags ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []models.FeatureFlag
+	for rows.Next() {
+		var flag models.FeatureFlag
+		if err := rows.Scan(&flag.Name, &flag.Enabled, &flag.Description, &flag.UpdatedBy, &flag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+func (r *featureFlagRepository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	query := `INSERT INTO feature_flags (name, enabled, description, updated_by) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), description = COALESCE(VALUES(description), description),
+		updated_by = VALUES(updated_by), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, flag.Name, flag.Enabled, flag.Description, flag.UpdatedBy)
+	return err
+}
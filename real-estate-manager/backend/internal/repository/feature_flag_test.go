@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestFeatureFlagRepository_GetAll(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedCount int
+		expectedError bool
+	}{
+		{
+			name: "successful retrieval",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"name", "enabled", "description", "updated_by", "updated_at"}).
+					AddRow("public_api", true, "Public endpoints", "admin", now).
+					AddRow("new_search", false, nil, nil, now)
+				mock.ExpectQuery("SELECT name, enabled, description, updated_by, updated_at FROM feature_flags").
+					WillReturnRows(rows)
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT name, enabled, description, updated_by, updated_at FROM feature_flags").
+					WillReturnError(errors.New("database connection failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewFeatureFlagRepository(db)
+			flags, err := repo.GetAll(context.Background())
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				if len(flags) != tt.expectedCount {
+					t.Errorf("Expected %d flags, got %d", tt.expectedCount, len(flags))
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagRepository_Upsert(t *testing.T) {
+	flag := &models.FeatureFlag{
+		Name:      "public_api",
+		Enabled:   true,
+		UpdatedBy: models.NullString{NullString: sql.NullString{String: "admin", Valid: true}},
+	}
+
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError bool
+	}{
+		{
+			name: "successful upsert",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO feature_flags").
+					WithArgs("public_api", true, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO feature_flags").
+					WillReturnError(errors.New("database connection failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewFeatureFlagRepository(db)
+			err = repo.Upsert(context.Background(), flag)
+
+			if tt.expectedError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectedError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
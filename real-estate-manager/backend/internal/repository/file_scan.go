@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// FileScanRepository records the virus scans of uploaded files
+type FileScanRepository interface {
+	Record(ctx context.Context, scan *models.FileScan) error
+	List(ctx context.Context, status string, limit int) ([]models.FileScan, error)
+}
+
+const fileScanColumns = `id, user_id, organization_id, property_id, kind, filename, size_bytes, status, signature,
+	quarantine_path, scanned_at`
+
+type fileScanRepository struct {
+	db *sql.DB
+}
+
+func NewFileScanRepository(db *sql.DB) FileScanRepository {
+	return &fileScanRepository{db: db}
+}
+
+func (r *fileScanRepository) Record(ctx context.Context, scan *models.FileScan) error {
+	query := `INSERT INTO file_scans (user_id, organization_id, property_id, kind, filename, size_bytes, status, signature,
+		quarantine_path) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, scan.UserID, scan.OrganizationID, scan.PropertyID, scan.Kind,
+		scan.Filename, scan.SizeBytes, scan.Status, scan.Signature, scan.QuarantinePath)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	scan.ID = int(id)
+	return nil
+}
+
+// List returns the most recent scans, optionally only those with a status
+func (r *fileScanRepository) List(ctx context.Context, status string, limit int) ([]models.FileScan, error) {
+	builder := sqlBuilder.Select(fileScanColumns).From("file_scans").OrderBy("scanned_at DESC", "id DESC").Limit(uint64(limit))
+	if status != "" {
+		builder = builder.Where("status = ?", status)
+	}
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scans := []models.FileScan{}
+	for rows.Next() {
+		var scan models.FileScan
+		if err := rows.Scan(&scan.ID, &scan.UserID, &scan.OrganizationID, &scan.PropertyID, &scan.Kind, &scan.Filename,
+			&scan.SizeBytes, &scan.Status, &scan.Signature, &scan.QuarantinePath, &scan.ScannedAt); err != nil {
+			return nil, err
+		}
+		scans = append(scans, scan)
+	}
+	return scans, rows.Err()
+}
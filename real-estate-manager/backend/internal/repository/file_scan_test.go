@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestFileScanRepository_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	scan := &models.FileScan{
+		UserID:         models.NullInt32{NullInt32: sql.NullInt32{Int32: 7, Valid: true}},
+		PropertyID:     models.NullInt32{NullInt32: sql.NullInt32{Int32: 3, Valid: true}},
+		Kind:           models.FileKindDocument,
+		Filename:       "disclosure.pdf",
+		SizeBytes:      4096,
+		Status:         models.FileScanInfected,
+		Signature:      "Win.Test.EICAR_HDB-1",
+		QuarantinePath: "uploads/quarantine/disclosure.pdf",
+	}
+	mock.ExpectExec("INSERT INTO file_scans").
+		WithArgs(scan.UserID, scan.OrganizationID, scan.PropertyID, scan.Kind, scan.Filename, scan.SizeBytes,
+			scan.Status, scan.Signature, scan.QuarantinePath).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	repo := NewFileScanRepository(db)
+	if err := repo.Record(context.Background(), scan); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if scan.ID != 5 {
+		t.Errorf("Expected ID 5, got %d", scan.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestFileScanRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	scannedAt := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "organization_id", "property_id", "kind", "filename", "size_bytes",
+		"status", "signature", "quarantine_path", "scanned_at"}).
+		AddRow(5, 7, nil, 3, "document", "disclosure.pdf", 4096, "infected", "Win.Test.EICAR_HDB-1", "uploads/quarantine/disclosure.pdf", scannedAt)
+	mock.ExpectQuery(`FROM file_scans WHERE status = \? ORDER BY scanned_at DESC, id DESC LIMIT 50`).
+		WithArgs(models.FileScanInfected).
+		WillReturnRows(rows)
+
+	repo := NewFileScanRepository(db)
+	scans, err := repo.List(context.Background(), models.FileScanInfected, 50)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(scans) != 1 || scans[0].Signature != "Win.Test.EICAR_HDB-1" || scans[0].OrganizationID.Valid {
+		t.Errorf("Unexpected scans: %+v", scans)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"strings"
+)
+
+// InspectionRepository stores inspections, their report documents and the
+// repairs they called for
+type InspectionRepository interface {
+	Create(ctx context.Context, inspection *models.Inspection) error
+	GetByID(ctx context.Context, id int) (*models.Inspection, error)
+	ListByProperty(ctx context.Context, propertyID int) ([]models.Inspection, error)
+	Update(ctx context.Context, inspection *models.Inspection) error
+	Delete(ctx context.Context, id int) (bool, error)
+	AddReport(ctx context.Context, inspectionID int, uploadID string) error
+	RemoveReport(ctx context.Context, inspectionID int, uploadID string) (bool, error)
+	CreateRepair(ctx context.Context, repair *models.RepairItem) error
+	GetRepair(ctx context.Context, id int) (*models.RepairItem, error)
+	UpdateRepair(ctx context.Context, repair *models.RepairItem) error
+	ListRepairs(ctx context.Context, propertyID int, filter models.RepairFilter) ([]models.RepairItem, error)
+}
+
+type inspectionRepository struct {
+	db *sql.DB
+}
+
+func NewInspectionRepository(db *sql.DB) InspectionRepository {
+	return &inspectionRepository{db: db}
+}
+
+const inspectionColumns = `id, property_id, kind, inspector, scheduled_at, status, completed_at, notes, created_by,
+	created_at, updated_at`
+
+const repairColumns = `id, inspection_id, property_id, description, status, assignee_id, due_date, completed_at,
+	created_at, updated_at`
+
+func (r *inspectionRepository) Create(ctx context.Context, inspection *models.Inspection) error {
+	query := `INSERT INTO inspections (property_id, kind, inspector, scheduled_at, status, completed_at, notes, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, inspection.PropertyID, inspection.Kind, inspection.Inspector,
+		inspection.ScheduledAt, inspection.Status, inspection.CompletedAt, inspection.Notes, inspection.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	inspection.ID = int(id)
+	return nil
+}
+
+// GetByID returns an inspection with its reports and repairs
+func (r *inspectionRepository) GetByID(ctx context.Context, id int) (*models.Inspection, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+inspectionColumns+` FROM inspections WHERE id = ?`, id)
+	var inspection models.Inspection
+	if err := scanInspection(row, &inspection); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	inspections := []models.Inspection{inspection}
+	if err := r.loadReports(ctx, inspections); err != nil {
+		return nil, err
+	}
+	repairs, err := r.queryRepairs(ctx, `SELECT `+repairColumns+` FROM inspection_repairs WHERE inspection_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	inspections[0].Repairs = repairs
+	return &inspections[0], nil
+}
+
+// ListByProperty returns a property's inspections in schedule order, with
+// their reports but not their repairs
+func (r *inspectionRepository) ListByProperty(ctx context.Context, propertyID int) ([]models.Inspection, error) {
+	query := `SELECT ` + inspectionColumns + ` FROM inspections WHERE property_id = ? ORDER BY scheduled_at, id`
+	rows, err := r.db.QueryContext(ctx, query, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inspections := []models.Inspection{}
+	for rows.Next() {
+		var inspection models.Inspection
+		if err := scanInspection(rows, &inspection); err != nil {
+			return nil, err
+		}
+		inspections = append(inspections, inspection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadReports(ctx, inspections); err != nil {
+		return nil, err
+	}
+	return inspections, nil
+}
+
+func (r *inspectionRepository) Update(ctx context.Context, inspection *models.Inspection) error {
+	query := `UPDATE inspections SET kind = ?, inspector = ?, scheduled_at = ?, status = ?, completed_at = ?, notes = ?
+		WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, inspection.Kind, inspection.Inspector, inspection.ScheduledAt, inspection.Status,
+		inspection.CompletedAt, inspection.Notes, inspection.ID)
+	return err
+}
+
+func (r *inspectionRepository) Delete(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM inspections WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddReport attaches an upload to an inspection. Attaching it again keeps
+// the original attachment.
+func (r *inspectionRepository) AddReport(ctx context.Context, inspectionID int, uploadID string) error {
+	query := `INSERT INTO inspection_reports (inspection_id, upload_id) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE upload_id = upload_id`
+	_, err := r.db.ExecContext(ctx, query, inspectionID, uploadID)
+	return err
+}
+
+func (r *inspectionRepository) RemoveReport(ctx context.Context, inspectionID int, uploadID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM inspection_reports WHERE inspection_id = ? AND upload_id = ?`,
+		inspectionID, uploadID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *inspectionRepository) CreateRepair(ctx context.Context, repair *models.RepairItem) error {
+	query := `INSERT INTO inspection_repairs (inspection_id, property_id, description, status, assignee_id, due_date, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, repair.InspectionID, repair.PropertyID, repair.Description, repair.Status,
+		repair.AssigneeID, repair.DueDate, repair.CompletedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	repair.ID = int(id)
+	return nil
+}
+
+func (r *inspectionRepository) GetRepair(ctx context.Context, id int) (*models.RepairItem, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+repairColumns+` FROM inspection_repairs WHERE id = ?`, id)
+	var repair models.RepairItem
+	if err := scanRepair(row, &repair); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &repair, nil
+}
+
+func (r *inspectionRepository) UpdateRepair(ctx context.Context, repair *models.RepairItem) error {
+	query := `UPDATE inspection_repairs SET description = ?, status = ?, assignee_id = ?, due_date = ?, completed_at = ?
+		WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, repair.Description, repair.Status, repair.AssigneeID, repair.DueDate,
+		repair.CompletedAt, repair.ID)
+	return err
+}
+
+// ListRepairs returns a property's matching repairs in punch-list order:
+// by status, then by due date with repairs without one last
+func (r *inspectionRepository) ListRepairs(ctx context.Context, propertyID int, filter models.RepairFilter) ([]models.RepairItem, error) {
+	query := `SELECT ` + repairColumns + ` FROM inspection_repairs WHERE property_id = ?`
+	args := []any{propertyID}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.AssigneeID != 0 {
+		query += ` AND assignee_id = ?`
+		args = append(args, filter.AssigneeID)
+	}
+	query += ` ORDER BY FIELD(status, ?, ?, ?, ?), due_date IS NULL, due_date, id`
+	for _, status := range models.RepairStatuses {
+		args = append(args, status)
+	}
+	return r.queryRepairs(ctx, query, args...)
+}
+
+func (r *inspectionRepository) queryRepairs(ctx context.Context, query string, args ...any) ([]models.RepairItem, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	repairs := []models.RepairItem{}
+	for rows.Next() {
+		var repair models.RepairItem
+		if err := scanRepair(rows, &repair); err != nil {
+			return nil, err
+		}
+		repairs = append(repairs, repair)
+	}
+	return repairs, rows.Err()
+}
+
+// loadReports fills in the report documents of inspections
+func (r *inspectionRepository) loadReports(ctx context.Context, inspections []models.Inspection) error {
+	if len(inspections) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(inspections))
+	placeholders := make([]string, len(inspections))
+	args := make([]any, len(inspections))
+	for i := range inspections {
+		inspections[i].Reports = []models.InspectionReport{}
+		index[inspections[i].ID] = i
+		placeholders[i] = "?"
+		args[i] = inspections[i].ID
+	}
+
+	query := `SELECT ir.inspection_id, u.id, u.filename, u.content_type, u.size_bytes, ir.created_at
+		FROM inspection_reports ir JOIN uploads u ON u.id = ir.upload_id
+		WHERE ir.inspection_id IN (` + strings.Join(placeholders, ", ") + `) ORDER BY ir.inspection_id, ir.created_at, u.id`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var inspectionID int
+		var report models.InspectionReport
+		if err := rows.Scan(&inspectionID, &report.UploadID, &report.Filename, &report.ContentType, &report.SizeBytes,
+			&report.CreatedAt); err != nil {
+			return err
+		}
+		if i, ok := index[inspectionID]; ok {
+			inspections[i].Reports = append(inspections[i].Reports, report)
+		}
+	}
+	return rows.Err()
+}
+
+func scanInspection(row rowScanner, inspection *models.Inspection) error {
+	return row.Scan(&inspection.ID, &inspection.PropertyID, &inspection.Kind, &inspection.Inspector, &inspection.ScheduledAt,
+		&inspection.Status, &inspection.CompletedAt, &inspection.Notes, &inspection.CreatedBy, &inspection.CreatedAt,
+		&inspection.UpdatedAt)
+}
+
+func scanRepair(row rowScanner, repair *models.RepairItem) error {
+	return row.Scan(&repair.ID, &repair.InspectionID, &repair.PropertyID, &repair.Description, &repair.Status,
+		&repair.AssigneeID, &repair.DueDate, &repair.CompletedAt, &repair.CreatedAt, &repair.UpdatedAt)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestInspectionRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM inspections WHERE id = ?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "property_id", "kind", "inspector", "scheduled_at", "status",
+			"completed_at", "notes", "created_by", "created_at", "updated_at"}).
+			AddRow(3, 12, models.InspectionGeneral, "Ann Lee", now, models.InspectionCompleted, now, "", nil, now, now))
+	// Reports are read through the uploads they were attached from
+	mock.ExpectQuery("FROM inspection_reports ir JOIN uploads u ON u.id = ir.upload_id\\s+WHERE ir.inspection_id IN \\(\\?\\)").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"inspection_id", "id", "filename", "content_type", "size_bytes", "created_at"}).
+			AddRow(3, "upload-1", "report.pdf", "application/pdf", 2048, now))
+	mock.ExpectQuery("SELECT (.+) FROM inspection_repairs WHERE inspection_id = ?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inspection_id", "property_id", "description", "status", "assignee_id",
+			"due_date", "completed_at", "created_at", "updated_at"}).
+			AddRow(8, 3, 12, "Replace cracked gutter", models.RepairOpen, 4, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), nil, now, now))
+
+	repo := NewInspectionRepository(db)
+	inspection, err := repo.GetByID(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(inspection.Reports) != 1 || inspection.Reports[0].Filename != "report.pdf" {
+		t.Errorf("Unexpected reports %+v", inspection.Reports)
+	}
+	if len(inspection.Repairs) != 1 || inspection.Repairs[0].AssigneeID.Int32 != 4 {
+		t.Errorf("Unexpected repairs %+v", inspection.Repairs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestInspectionRepository_ListRepairs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Outstanding repairs come first, soonest due first
+	mock.ExpectQuery("FROM inspection_repairs WHERE property_id = \\? AND assignee_id = \\? "+
+		"ORDER BY FIELD\\(status, \\?, \\?, \\?, \\?\\), due_date IS NULL, due_date, id").
+		WithArgs(12, uint(4), models.RepairOpen, models.RepairInProgress, models.RepairDone, models.RepairWaived).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inspection_id", "property_id", "description", "status", "assignee_id",
+			"due_date", "completed_at", "created_at", "updated_at"}))
+
+	repo := NewInspectionRepository(db)
+	repairs, err := repo.ListRepairs(context.Background(), 12, models.RepairFilter{AssigneeID: 4})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if repairs == nil || len(repairs) != 0 {
+		t.Errorf("Expected an empty list, got %+v", repairs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"time"
+)
+
+// JobRepository keeps the history of SimplyRETS import jobs
+type JobRepository interface {
+	Create(ctx context.Context, job *models.ProcessingJob, liveSince time.Time) error
+	GetByID(ctx context.Context, id string) (*models.ProcessingJob, error)
+	Complete(ctx context.Context, id string, status models.ProcessingStatus) error
+	List(ctx context.Context, filter models.JobFilter) ([]models.ProcessingJob, error)
+	Reopen(ctx context.Context, id string, at, liveSince time.Time) error
+	Heartbeat(ctx context.Context, id string, at time.Time) error
+	ProcessedListings(ctx context.Context, jobID string) (map[string]bool, error)
+	MarkProcessed(ctx context.Context, jobID, listingID string) error
+}
+
+const jobColumns = `id, label, description, metadata, lazy_photos, job_limit, status, total_properties, processed_count, failed_count,
+	photos_skipped, listings_skipped, COALESCE(error_message, ''), created_by, organization_id, started_at, heartbeat_at, completed_at`
+
+type jobRepository struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// ErrJobRunning is returned by Create and Reopen while another job is
+// running
+var ErrJobRunning = errors.New("another import job is running")
+
+// Create records a job started at job.StartedAt, unless another running job
+// has sent a heartbeat since liveSince
+func (r *jobRepository) Create(ctx context.Context, job *models.ProcessingJob, liveSince time.Time) error {
+	return r.exclusive(ctx, liveSince, func(tx *sql.Tx) error {
+		query := `INSERT INTO processing_jobs (id, label, description, metadata, lazy_photos, job_limit, status, created_by, organization_id, started_at, heartbeat_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, job.ID, job.Label, job.Description, job.Metadata, job.LazyPhotos, job.Limit, job.Status,
+			job.CreatedBy, job.OrganizationID, job.StartedAt, job.StartedAt)
+		return err
+	})
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id string) (*models.ProcessingJob, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM processing_jobs WHERE id = ?`, id)
+	var job models.ProcessingJob
+	if err := scanJob(row, &job); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Complete records how a job finished
+func (r *jobRepository) Complete(ctx context.Context, id string, status models.ProcessingStatus) error {
+	query := `UPDATE processing_jobs SET status = ?, total_properties = ?, processed_count = ?, failed_count = ?,
+		photos_skipped = ?, listings_skipped = ?, error_message = NULLIF(?, ''), completed_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, status.Status, status.TotalProperties, status.ProcessedCount, status.FailedCount,
+		status.PhotosSkipped, status.ListingsSkipped, status.ErrorMessage, status.CompletedAt, id)
+	return err
+}
+
+// Reopen marks a finished job as running again at at, for a resumed job,
+// unless a running job, this one included, has sent a heartbeat since
+// liveSince
+func (r *jobRepository) Reopen(ctx context.Context, id string, at, liveSince time.Time) error {
+	return r.exclusive(ctx, liveSince, func(tx *sql.Tx) error {
+		query := `UPDATE processing_jobs SET status = 'running', error_message = NULL, completed_at = NULL, heartbeat_at = ? WHERE id = ?`
+		_, err := tx.ExecContext(ctx, query, at, id)
+		return err
+	})
+}
+
+// Heartbeat records that a running job is still alive at at
+func (r *jobRepository) Heartbeat(ctx context.Context, id string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE processing_jobs SET heartbeat_at = ? WHERE id = ? AND status = 'running'`, at, id)
+	return err
+}
+
+// exclusive runs write in a transaction unless a running job has sent a
+// heartbeat since liveSince. Locking the running jobs, or the gap where
+// they would go, keeps two instances from both starting a job; one of them
+// fails instead.
+func (r *jobRepository) exclusive(ctx context.Context, liveSince time.Time, write func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var running string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM processing_jobs WHERE status = 'running' AND heartbeat_at >= ? LIMIT 1 FOR UPDATE`,
+		liveSince).Scan(&running)
+	switch {
+	case err == nil:
+		return ErrJobRunning
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+	if err := write(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ProcessedListings returns the external IDs of the listings a job has
+// saved
+func (r *jobRepository) ProcessedListings(ctx context.Context, jobID string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT listing_id FROM processing_job_listings WHERE job_id = ?`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	listings := map[string]bool{}
+	for rows.Next() {
+		var listingID string
+		if err := rows.Scan(&listingID); err != nil {
+			return nil, err
+		}
+		listings[listingID] = true
+	}
+	return listings, rows.Err()
+}
+
+// MarkProcessed adds a listing to those a job has saved. Marking it again
+// does nothing.
+func (r *jobRepository) MarkProcessed(ctx context.Context, jobID, listingID string) error {
+	_, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO processing_job_listings (job_id, listing_id) VALUES (?, ?)`, jobID, listingID)
+	return err
+}
+
+// List returns the most recently started jobs, optionally only those with
+// a label or started by a user
+func (r *jobRepository) List(ctx context.Context, filter models.JobFilter) ([]models.ProcessingJob, error) {
+	query := `SELECT ` + jobColumns + ` FROM processing_jobs WHERE 1 = 1`
+	var args []any
+	if filter.Label != "" {
+		query += ` AND label = ?`
+		args = append(args, filter.Label)
+	}
+	if filter.CreatedBy != 0 {
+		query += ` AND created_by = ?`
+		args = append(args, filter.CreatedBy)
+	}
+	query += ` ORDER BY started_at DESC, id LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.ProcessingJob{}
+	for rows.Next() {
+		var job models.ProcessingJob
+		if err := scanJob(rows, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanJob(row rowScanner, job *models.ProcessingJob) error {
+	return row.Scan(&job.ID, &job.Label, &job.Description, &job.Metadata, &job.LazyPhotos, &job.Limit, &job.Status,
+		&job.TotalProperties, &job.ProcessedCount, &job.FailedCount, &job.PhotosSkipped, &job.ListingsSkipped, &job.ErrorMessage, &job.CreatedBy,
+		&job.OrganizationID, &job.StartedAt, &job.HeartbeatAt, &job.CompletedAt)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestJobRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "label", "description", "metadata", "lazy_photos", "job_limit", "status", "total_properties",
+		"processed_count", "failed_count", "photos_skipped", "listings_skipped", "error_message", "created_by", "organization_id", "started_at", "heartbeat_at", "completed_at"}).
+		AddRow("job-1", "backfill", "March listings", []byte(`{"month": "2024-03"}`), true, 500, "completed", 480, 478, 2, 312, 6, "", 4, 3, started, started.Add(time.Hour), started.Add(time.Hour)).
+		AddRow("job-2", "backfill", "", nil, false, 50, "running", 0, 0, 0, 0, 0, "", nil, nil, started, started.Add(time.Minute), nil)
+	mock.ExpectQuery("FROM processing_jobs WHERE 1 = 1 AND label = \\? ORDER BY started_at DESC, id LIMIT \\?").
+		WithArgs("backfill", 20).
+		WillReturnRows(rows)
+
+	repo := NewJobRepository(db)
+	jobs, err := repo.List(context.Background(), models.JobFilter{Label: "backfill", Limit: 20})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].Metadata["month"] != "2024-03" || jobs[0].CompletedAt == nil || jobs[0].CreatedBy.Int32 != 4 || jobs[0].PhotosSkipped != 312 || jobs[0].ListingsSkipped != 6 || !jobs[0].LazyPhotos {
+		t.Fatalf("Unexpected jobs %+v", jobs)
+	}
+	if jobs[1].Metadata != nil || jobs[1].CompletedAt != nil || jobs[1].CreatedBy.Valid || jobs[1].HeartbeatAt == nil {
+		t.Errorf("Expected a running job without metadata, got %+v", jobs[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJobRepository_ProcessedListings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT IGNORE INTO processing_job_listings \\(job_id, listing_id\\) VALUES \\(\\?, \\?\\)").
+		WithArgs("job-1", "A1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT listing_id FROM processing_job_listings WHERE job_id = \\?").
+		WithArgs("job-1").
+		WillReturnRows(sqlmock.NewRows([]string{"listing_id"}).AddRow("A1").AddRow("B2"))
+
+	repo := NewJobRepository(db)
+	if err := repo.MarkProcessed(context.Background(), "job-1", "A1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	listings, err := repo.ProcessedListings(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(listings) != 2 || !listings["A1"] || !listings["B2"] {
+		t.Errorf("Unexpected listings %v", listings)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestJobRepository_Exclusive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	liveSince := started.Add(-2 * time.Minute)
+	running := "SELECT id FROM processing_jobs WHERE status = 'running' AND heartbeat_at >= \\? LIMIT 1 FOR UPDATE"
+
+	// No live job: the job is recorded with its first heartbeat
+	mock.ExpectBegin()
+	mock.ExpectQuery(running).WithArgs(liveSince).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO processing_jobs").
+		WithArgs("job-1", "manual", "", nil, false, 50, "running", nil, nil, started, started).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// Another instance's job is live
+	mock.ExpectBegin()
+	mock.ExpectQuery(running).WithArgs(liveSince).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("job-1"))
+	mock.ExpectRollback()
+	// Resuming a job once nothing is live
+	mock.ExpectBegin()
+	mock.ExpectQuery(running).WithArgs(liveSince).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("UPDATE processing_jobs SET status = 'running', error_message = NULL, completed_at = NULL, heartbeat_at = \\? WHERE id = \\?").
+		WithArgs(started, "job-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("UPDATE processing_jobs SET heartbeat_at = \\? WHERE id = \\? AND status = 'running'").
+		WithArgs(started, "job-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewJobRepository(db)
+	job := &models.ProcessingJob{ID: "job-1", JobDetails: models.JobDetails{Label: "manual"}, Limit: 50, Status: "running", StartedAt: started}
+	if err := repo.Create(context.Background(), job, liveSince); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	job.ID = "job-2"
+	if err := repo.Create(context.Background(), job, liveSince); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("Expected ErrJobRunning, got %v", err)
+	}
+	if err := repo.Reopen(context.Background(), "job-2", started, liveSince); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+	if err := repo.Heartbeat(context.Background(), "job-2", started); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
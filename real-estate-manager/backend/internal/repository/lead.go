@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+// LeadRepository stores leads and the rules that assign them to agents
+type LeadRepository interface {
+	Create(ctx context.Context, lead *models.Lead) (bool, error)
+	GetByID(ctx context.Context, id int) (*models.Lead, error)
+	ListAssigned(ctx context.Context, agentID uint, limit int) ([]models.Lead, error)
+	ListRoutingRules(ctx context.Context) ([]models.LeadRoutingRule, error)
+	CreateRoutingRule(ctx context.Context, rule *models.LeadRoutingRule) error
+	DeleteRoutingRule(ctx context.Context, id int) (bool, error)
+}
+
+type leadRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewLeadRepository creates the repository. Phone numbers are encrypted
+// with cipher when it is not nil.
+func NewLeadRepository(db *sql.DB, cipher *fieldcrypt.Cipher) LeadRepository {
+	return &leadRepository{db: db, cipher: cipher}
+}
+
+// Create stores a lead and reports whether it is new. A lead already
+// received from the same source is left as it was; lead.ID is set either
+// way.
+func (r *leadRepository) Create(ctx context.Context, lead *models.Lead) (bool, error) {
+	phone, err := r.cipher.Encrypt(lead.Phone)
+	if err != nil {
+		return false, err
+	}
+	query := `INSERT INTO leads (source, external_id, name, email, phone, message, property_id, assigned_to, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)`
+	result, err := r.db.ExecContext(ctx, query, lead.Source, lead.ExternalID, lead.Name, lead.Email, phone,
+		lead.Message, lead.PropertyID, lead.AssignedTo, lead.Status)
+	if err != nil {
+		return false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	lead.ID = int(id)
+	// MySQL reports no affected rows when the duplicate key update changed
+	// nothing
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+const leadColumns = `id, source, external_id, name, email, phone, message, property_id, assigned_to, status, created_at`
+
+func (r *leadRepository) GetByID(ctx context.Context, id int) (*models.Lead, error) {
+	leads, err := r.queryLeads(ctx, `SELECT `+leadColumns+` FROM leads WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(leads) == 0 {
+		return nil, nil
+	}
+	return &leads[0], nil
+}
+
+// ListAssigned returns the newest leads assigned to an agent
+func (r *leadRepository) ListAssigned(ctx context.Context, agentID uint, limit int) ([]models.Lead, error) {
+	return r.queryLeads(ctx, `SELECT `+leadColumns+` FROM leads WHERE assigned_to = ? ORDER BY id DESC LIMIT ?`, agentID, limit)
+}
+
+func (r *leadRepository) queryLeads(ctx context.Context, query string, args ...any) ([]models.Lead, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leads := []models.Lead{}
+	for rows.Next() {
+		var lead models.Lead
+		if err := rows.Scan(&lead.ID, &lead.Source, &lead.ExternalID, &lead.Name, &lead.Email, &lead.Phone,
+			&lead.Message, &lead.PropertyID, &lead.AssignedTo, &lead.Status, &lead.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := r.cipher.DecryptAll(&lead.Phone); err != nil {
+			return nil, err
+		}
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+// ListRoutingRules returns the routing rules in the order they are tried
+func (r *leadRepository) ListRoutingRules(ctx context.Context) ([]models.LeadRoutingRule, error) {
+	query := `SELECT id, priority, source, location, agent_id, created_at
+		FROM lead_routing_rules ORDER BY priority, id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.LeadRoutingRule{}
+	for rows.Next() {
+		var rule models.LeadRoutingRule
+		if err := rows.Scan(&rule.ID, &rule.Priority, &rule.Source, &rule.Location, &rule.AgentID, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *leadRepository) CreateRoutingRule(ctx context.Context, rule *models.LeadRoutingRule) error {
+	query := `INSERT INTO lead_routing_rules (priority, source, location, agent_id) VALUES (?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, rule.Priority, rule.Source, rule.Location, rule.AgentID)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rule.ID = int(id)
+	return nil
+}
+
+// DeleteRoutingRule reports whether the rule existed
+func (r *leadRepository) DeleteRoutingRule(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM lead_routing_rules WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestLeadRepository_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		affected      int64
+		expectCreated bool
+	}{
+		{name: "new lead", affected: 1, expectCreated: true},
+		{name: "redelivered lead", affected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("INSERT INTO leads .* ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID\\(id\\)").
+				WithArgs("zillow", "z-81", "Jane", "jane@example.com", "", "", models.NullInt32{}, models.NullInt32{}, models.LeadStatusNew).
+				WillReturnResult(sqlmock.NewResult(30, tt.affected))
+
+			repo := NewLeadRepository(db, nil)
+			lead := &models.Lead{Source: "zillow", ExternalID: "z-81", Name: "Jane", Email: "jane@example.com", Status: models.LeadStatusNew}
+			created, err := repo.Create(context.Background(), lead)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if created != tt.expectCreated || lead.ID != 30 {
+				t.Errorf("Expected created=%v and ID 30, got %v and %d", tt.expectCreated, created, lead.ID)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"strings"
+	"time"
+)
+
+// marketInsertBatch caps the rows of one insert when the market statistics
+// are rebuilt
+const marketInsertBatch = 500
+
+// MarketRepository reads the listings market reports are built from and
+// stores the monthly statistics and daily inventory snapshots computed
+// from them
+type MarketRepository interface {
+	ListListings(ctx context.Context) ([]models.MarketListing, error)
+	ReplaceStats(ctx context.Context, stats []models.MarketStat) error
+	ListStats(ctx context.Context, filter models.MarketStatFilter) ([]models.MarketStat, error)
+	ReplaceSnapshot(ctx context.Context, date string, snapshots []models.MarketSnapshot) error
+	ListSnapshots(ctx context.Context, filter models.MarketSnapshotFilter) ([]models.MarketSnapshot, error)
+}
+
+type marketRepository struct {
+	db *sql.DB
+}
+
+func NewMarketRepository(db *sql.DB) MarketRepository {
+	return &marketRepository{db: db}
+}
+
+// ListListings returns every listing for sale with the price and date of
+// its latest closed deal
+func (r *marketRepository) ListListings(ctx context.Context) ([]models.MarketListing, error) {
+	query := `SELECT p.id, p.location, p.status, p.price, p.square_feet, p.created_at, p.updated_at, d.price, d.closed_at
+		FROM properties p
+		LEFT JOIN deals d ON d.id = (
+			SELECT d2.id FROM deals d2 WHERE d2.property_id = p.id AND d2.stage = ? ORDER BY d2.closed_at DESC LIMIT 1)
+		WHERE p.listing_type = ? ORDER BY p.id`
+	rows, err := r.db.QueryContext(ctx, query, models.DealClosed, models.ListingTypeSale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	listings := []models.MarketListing{}
+	for rows.Next() {
+		var listing models.MarketListing
+		if err := rows.Scan(&listing.PropertyID, &listing.Location, &listing.Status, &listing.Price, &listing.SquareFeet,
+			&listing.ListedAt, &listing.UpdatedAt, &listing.SalePrice, &listing.SoldAt); err != nil {
+			return nil, err
+		}
+		listings = append(listings, listing)
+	}
+	return listings, rows.Err()
+}
+
+// ReplaceStats swaps all market statistics for stats in one transaction,
+// so reports never see a half-built table
+func (r *marketRepository) ReplaceStats(ctx context.Context, stats []models.MarketStat) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM market_stats`); err != nil {
+		return err
+	}
+	for start := 0; start < len(stats); start += marketInsertBatch {
+		batch := stats[start:min(start+marketInsertBatch, len(stats))]
+		placeholders := make([]string, len(batch))
+		args := make([]any, 0, len(batch)*10)
+		for i, stat := range batch {
+			placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+			args = append(args, stat.AreaType, stat.Area, stat.Month, stat.NewListings, stat.ActiveListings, stat.Sales,
+				stat.MedianListPrice, stat.MedianSalePrice, stat.AvgSalePricePerSqft, stat.RefreshedAt)
+		}
+		query := `INSERT INTO market_stats (area_type, area, month, new_listings, active_listings, sales,
+			median_list_price, median_sale_price, avg_sale_price_per_sqft, refreshed_at) VALUES ` + strings.Join(placeholders, ", ")
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListStats returns an area's statistics from the month filter.Since on,
+// by area and month
+func (r *marketRepository) ListStats(ctx context.Context, filter models.MarketStatFilter) ([]models.MarketStat, error) {
+	query := `SELECT area_type, area, month, new_listings, active_listings, sales, median_list_price, median_sale_price,
+			avg_sale_price_per_sqft, refreshed_at
+		FROM market_stats WHERE area_type = ? AND month >= ?`
+	args := []any{filter.AreaType, filter.Since}
+	if filter.AnyState {
+		query += ` AND (area = ? OR area LIKE ?)`
+		args = append(args, filter.Area, escapeLike(filter.Area)+", %")
+	} else {
+		query += ` AND area = ?`
+		args = append(args, filter.Area)
+	}
+	query += ` ORDER BY area, month`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.MarketStat{}
+	for rows.Next() {
+		var stat models.MarketStat
+		if err := rows.Scan(&stat.AreaType, &stat.Area, &stat.Month, &stat.NewListings, &stat.ActiveListings, &stat.Sales,
+			&stat.MedianListPrice, &stat.MedianSalePrice, &stat.AvgSalePricePerSqft, &stat.RefreshedAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// ReplaceSnapshot stores the inventory snapshots of a day, replacing any
+// taken earlier that day
+func (r *marketRepository) ReplaceSnapshot(ctx context.Context, date string, snapshots []models.MarketSnapshot) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM market_snapshots WHERE snapshot_date = ?`, date); err != nil {
+		return err
+	}
+	for start := 0; start < len(snapshots); start += marketInsertBatch {
+		batch := snapshots[start:min(start+marketInsertBatch, len(snapshots))]
+		placeholders := make([]string, len(batch))
+		args := make([]any, 0, len(batch)*5)
+		for i, snapshot := range batch {
+			placeholders[i] = "(?, ?, ?, ?, ?)"
+			args = append(args, date, snapshot.City, snapshot.Status, snapshot.Listings, snapshot.MedianPrice)
+		}
+		query := `INSERT INTO market_snapshots (snapshot_date, city, status, listings, median_price) VALUES ` + strings.Join(placeholders, ", ")
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListSnapshots returns a city's snapshots from the day filter.Since on,
+// by city, day and status
+func (r *marketRepository) ListSnapshots(ctx context.Context, filter models.MarketSnapshotFilter) ([]models.MarketSnapshot, error) {
+	query := `SELECT snapshot_date, city, status, listings, median_price FROM market_snapshots WHERE snapshot_date >= ?`
+	args := []any{filter.Since}
+	if filter.AnyState {
+		query += ` AND (city = ? OR city LIKE ?)`
+		args = append(args, filter.City, escapeLike(filter.City)+", %")
+	} else {
+		query += ` AND city = ?`
+		args = append(args, filter.City)
+	}
+	query += ` ORDER BY city, snapshot_date, status`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []models.MarketSnapshot{}
+	for rows.Next() {
+		var snapshot models.MarketSnapshot
+		var date time.Time
+		if err := rows.Scan(&date, &snapshot.City, &snapshot.Status, &snapshot.Listings, &snapshot.MedianPrice); err != nil {
+			return nil, err
+		}
+		snapshot.Date = date.Format(time.DateOnly)
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMarketRepository_ReplaceStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	refreshed := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	stats := []models.MarketStat{
+		{AreaType: models.MarketAreaCity, Area: "Austin, TX", Month: "2024-05", NewListings: 2, ActiveListings: 5, Sales: 1, RefreshedAt: refreshed},
+		{AreaType: models.MarketAreaZip, Area: "78701", Month: "2024-05", ActiveListings: 3, RefreshedAt: refreshed},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM market_stats").WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec(`INSERT INTO market_stats .* VALUES \(\?, \?, \?, \?, \?, \?, \?, \?, \?, \?\), \(`).
+		WithArgs(models.MarketAreaCity, "Austin, TX", "2024-05", 2, 5, 1, models.NullFloat64{}, models.NullFloat64{}, models.NullFloat64{}, refreshed,
+			models.MarketAreaZip, "78701", "2024-05", 0, 3, 0, models.NullFloat64{}, models.NullFloat64{}, models.NullFloat64{}, refreshed).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewMarketRepository(db)
+	if err := repo.ReplaceStats(context.Background(), stats); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMarketRepository_ListStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	refreshed := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	// A city without its state matches the city in any state
+	mock.ExpectQuery("SELECT area_type, area, month").
+		WithArgs(models.MarketAreaCity, "2024-01", "Austin", "Austin, %").
+		WillReturnRows(sqlmock.NewRows([]string{"area_type", "area", "month", "new_listings", "active_listings", "sales",
+			"median_list_price", "median_sale_price", "avg_sale_price_per_sqft", "refreshed_at"}).
+			AddRow(models.MarketAreaCity, "Austin, TX", "2024-05", 2, 5, 1, 350000.0, 480000.0, 240.0, refreshed))
+
+	repo := NewMarketRepository(db)
+	stats, err := repo.ListStats(context.Background(), models.MarketStatFilter{AreaType: models.MarketAreaCity, Area: "Austin",
+		AnyState: true, Since: "2024-01"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Area != "Austin, TX" || stats[0].MedianSalePrice.Float64 != 480000 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMarketRepository_ReplaceSnapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	snapshots := []models.MarketSnapshot{
+		{City: "Austin, TX", Status: models.PropertyStatusActive, Listings: 3},
+		{City: "Austin, TX", Status: models.PropertyStatusSold, Listings: 1},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM market_snapshots WHERE snapshot_date = \\?").
+		WithArgs("2024-06-15").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO market_snapshots .* VALUES \(\?, \?, \?, \?, \?\), \(`).
+		WithArgs("2024-06-15", "Austin, TX", models.PropertyStatusActive, 3, models.NullFloat64{},
+			"2024-06-15", "Austin, TX", models.PropertyStatusSold, 1, models.NullFloat64{}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewMarketRepository(db)
+	if err := repo.ReplaceSnapshot(context.Background(), "2024-06-15", snapshots); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMarketRepository_ListSnapshots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT snapshot_date, city, status, listings, median_price FROM market_snapshots WHERE snapshot_date >= \\? AND city = \\?").
+		WithArgs("2024-01-01", "Austin, TX").
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot_date", "city", "status", "listings", "median_price"}).
+			AddRow(time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC), "Austin, TX", models.PropertyStatusActive, 4, 350000.0))
+
+	repo := NewMarketRepository(db)
+	snapshots, err := repo.ListSnapshots(context.Background(), models.MarketSnapshotFilter{City: "Austin, TX", Since: "2024-01-01"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Date != "2024-05-31" || snapshots[0].MedianPrice.Float64 != 350000 {
+		t.Errorf("Unexpected snapshots: %+v", snapshots)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
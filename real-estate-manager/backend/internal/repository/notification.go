@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+// NotificationRepository stores the in-app notification center
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	List(ctx context.Context, userID uint, query models.NotificationQuery) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID uint) (int, error)
+	MarkRead(ctx context.Context, userID uint, id int) (bool, error)
+	MarkAllRead(ctx context.Context, userID uint) (int, error)
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	query := `INSERT INTO notifications (user_id, type, title, body, subject) VALUES (?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, notification.UserID, notification.Type, notification.Title,
+		notification.Body, notification.Subject)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	notification.ID = int(id)
+	return nil
+}
+
+// List returns up to query.Limit of a user's notifications, newest first
+func (r *notificationRepository) List(ctx context.Context, userID uint, query models.NotificationQuery) ([]models.Notification, error) {
+	sqlQuery := `SELECT id, user_id, type, title, body, subject, read_at, created_at FROM notifications WHERE user_id = ?`
+	args := []any{userID}
+	if query.BeforeID > 0 {
+		sqlQuery += ` AND id < ?`
+		args = append(args, query.BeforeID)
+	}
+	if query.UnreadOnly {
+		sqlQuery += ` AND read_at IS NULL`
+	}
+	sqlQuery += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, query.Limit)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		var readAt sql.NullTime
+		if err := rows.Scan(&notification.ID, &notification.UserID, &notification.Type, &notification.Title,
+			&notification.Body, &notification.Subject, &readAt, &notification.CreatedAt); err != nil {
+			return nil, err
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks one of a user's notifications as read and reports whether
+// it exists. Notifications that were already read keep their read time.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uint, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ? AND read_at IS NULL`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows > 0 {
+		return true, nil
+	}
+
+	var count int
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE id = ? AND user_id = ?`, id, userID).Scan(&count)
+	return count > 0, err
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns
+// how many there were
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uint) (int, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+type NotificationPreferenceRepository interface {
+	Get(ctx context.Context, userID uint) (*models.NotificationPreferences, error)
+	Save(ctx context.Context, prefs *models.NotificationPreferences) error
+}
+
+type notificationPreferenceRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewNotificationPreferenceRepository creates the repository. Phone numbers
+// are encrypted with cipher when it is not nil.
+func NewNotificationPreferenceRepository(db *sql.DB, cipher *fieldcrypt.Cipher) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db, cipher: cipher}
+}
+
+// Get returns a user's preferences, or (nil, nil) when none are saved
+func (r *notificationPreferenceRepository) Get(ctx context.Context, userID uint) (*models.NotificationPreferences, error) {
+	query := `SELECT user_id, phone, sms_opt_in, channel, quiet_start, quiet_end, timezone, updated_at
+		FROM notification_preferences WHERE user_id = ?`
+
+	var prefs models.NotificationPreferences
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&prefs.UserID, &prefs.Phone, &prefs.SMSOptIn, &prefs.Channel,
+		&prefs.QuietStart, &prefs.QuietEnd, &prefs.Timezone, &prefs.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cipher.DecryptAll(&prefs.Phone); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *notificationPreferenceRepository) Save(ctx context.Context, prefs *models.NotificationPreferences) error {
+	phone, err := r.cipher.Encrypt(prefs.Phone)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO notification_preferences (user_id, phone, sms_opt_in, channel, quiet_start, quiet_end, timezone)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE phone = VALUES(phone), sms_opt_in = VALUES(sms_opt_in), channel = VALUES(channel),
+		quiet_start = VALUES(quiet_start), quiet_end = VALUES(quiet_end), timezone = VALUES(timezone)`
+	_, err = r.db.ExecContext(ctx, query, prefs.UserID, phone, prefs.SMSOptIn, prefs.Channel,
+		prefs.QuietStart, prefs.QuietEnd, prefs.Timezone)
+	return err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestNotificationRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	readAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "type", "title", "body", "subject", "read_at", "created_at"}).
+		AddRow(41, 7, "job.completed", "Import finished", "Imported 20 listings", "job/3", nil, readAt).
+		AddRow(40, 7, "property.updated", "Listing updated", "Casa was updated", "property/12", readAt, readAt)
+	mock.ExpectQuery("SELECT id, user_id, type, title, body, subject, read_at, created_at FROM notifications "+
+		"WHERE user_id = \\? AND id < \\? AND read_at IS NULL ORDER BY id DESC LIMIT \\?").
+		WithArgs(uint(7), 42, 20).
+		WillReturnRows(rows)
+
+	repo := NewNotificationRepository(db)
+	notifications, err := repo.List(context.Background(), 7, models.NotificationQuery{BeforeID: 42, Limit: 20, UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(notifications) != 2 || notifications[0].ReadAt != nil || notifications[1].ReadAt == nil {
+		t.Errorf("Unexpected notifications: %+v", notifications)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestNotificationRepository_MarkRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		updated      int64
+		existing     int
+		expectExists bool
+	}{
+		{name: "unread", updated: 1, expectExists: true},
+		{name: "already read", existing: 1, expectExists: true},
+		{name: "missing or another user's"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE notifications SET read_at = CURRENT_TIMESTAMP").
+				WithArgs(40, uint(7)).
+				WillReturnResult(sqlmock.NewResult(0, tt.updated))
+			if tt.updated == 0 {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM notifications WHERE id = \\? AND user_id = \\?").
+					WithArgs(40, uint(7)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.existing))
+			}
+
+			repo := NewNotificationRepository(db)
+			exists, err := repo.MarkRead(context.Background(), 7, 40)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if exists != tt.expectExists {
+				t.Errorf("Expected exists %v, got %v", tt.expectExists, exists)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+// OfferRepository stores the offers made on listings
+type OfferRepository interface {
+	Create(ctx context.Context, offer *models.Offer) error
+	GetByID(ctx context.Context, id int) (*models.Offer, error)
+	ListByProperty(ctx context.Context, propertyID int) ([]models.Offer, error)
+	Update(ctx context.Context, offer *models.Offer) error
+}
+
+type offerRepository struct {
+	db     *sql.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewOfferRepository creates the repository. Buyer phone numbers are
+// encrypted with cipher when it is not nil.
+func NewOfferRepository(db *sql.DB, cipher *fieldcrypt.Cipher) OfferRepository {
+	return &offerRepository{db: db, cipher: cipher}
+}
+
+const offerColumns = `id, property_id, amount, counter_amount, buyer_name, buyer_email, buyer_phone, contingencies,
+	expires_at, status, notes, created_by, responded_at, created_at, updated_at`
+
+func (r *offerRepository) Create(ctx context.Context, offer *models.Offer) error {
+	phone, err := r.cipher.Encrypt(offer.BuyerPhone)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO offers (property_id, amount, counter_amount, buyer_name, buyer_email, buyer_phone, contingencies,
+		expires_at, status, notes, created_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, offer.PropertyID, offer.Amount, offer.CounterAmount, offer.BuyerName,
+		offer.BuyerEmail, phone, offer.Contingencies, offer.ExpiresAt, offer.Status, offer.Notes, offer.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	offer.ID = int(id)
+	return nil
+}
+
+func (r *offerRepository) GetByID(ctx context.Context, id int) (*models.Offer, error) {
+	offers, err := r.queryOffers(ctx, `SELECT `+offerColumns+` FROM offers WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(offers) == 0 {
+		return nil, nil
+	}
+	return &offers[0], nil
+}
+
+// ListByProperty returns a listing's offers, newest first
+func (r *offerRepository) ListByProperty(ctx context.Context, propertyID int) ([]models.Offer, error) {
+	return r.queryOffers(ctx, `SELECT `+offerColumns+` FROM offers WHERE property_id = ? ORDER BY created_at DESC, id DESC`,
+		propertyID)
+}
+
+// Update saves an offer's terms and status. The buyer and the listing do
+// not change.
+func (r *offerRepository) Update(ctx context.Context, offer *models.Offer) error {
+	query := `UPDATE offers SET amount = ?, counter_amount = ?, contingencies = ?, expires_at = ?, status = ?, notes = ?,
+		responded_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, offer.Amount, offer.CounterAmount, offer.Contingencies, offer.ExpiresAt,
+		offer.Status, offer.Notes, offer.RespondedAt, offer.ID)
+	return err
+}
+
+func (r *offerRepository) queryOffers(ctx context.Context, query string, args ...any) ([]models.Offer, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	offers := []models.Offer{}
+	for rows.Next() {
+		var offer models.Offer
+		if err := rows.Scan(&offer.ID, &offer.PropertyID, &offer.Amount, &offer.CounterAmount, &offer.BuyerName,
+			&offer.BuyerEmail, &offer.BuyerPhone, &offer.Contingencies, &offer.ExpiresAt, &offer.Status, &offer.Notes,
+			&offer.CreatedBy, &offer.RespondedAt, &offer.CreatedAt, &offer.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := r.cipher.DecryptAll(&offer.BuyerPhone); err != nil {
+			return nil, err
+		}
+		offers = append(offers, offer)
+	}
+	return offers, rows.Err()
+}
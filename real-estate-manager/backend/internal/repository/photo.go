@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"real-estate-manager/backend/internal/models"
+)
+
+// photoBatchSize caps the photo rows written by one INSERT
+const photoBatchSize = 500
+
+// replacePhotos swaps the photo rows of a property for photos. It runs on
+// the transaction that writes the property, so the rows and the JSON copy
+// in properties.photos never disagree.
+func replacePhotos(ctx context.Context, db dbtx, propertyID int, photos models.PhotoList) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM property_photos WHERE property_id = ?`, propertyID); err != nil {
+		return err
+	}
+	return insertPhotos(ctx, db, propertyID, photos)
+}
+
+// insertPhotos writes photos as rows positioned by their index, up to
+// photoBatchSize rows per statement
+func insertPhotos(ctx context.Context, db dbtx, propertyID int, photos models.PhotoList) error {
+	for start := 0; start < len(photos); start += photoBatchSize {
+		end := min(start+photoBatchSize, len(photos))
+		insert := sqlBuilder.Insert("property_photos").Columns("property_id", "position", "url", "local_url", "thumbnail_url", "medium_url", "caption", "alt_text")
+		for i, photo := range photos[start:end] {
+			insert = insert.Values(propertyID, start+i, photo.URL, photo.LocalURL, photo.ThumbnailURL, photo.MediumURL, photo.Caption, photo.AltText)
+		}
+		query, args, err := insert.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
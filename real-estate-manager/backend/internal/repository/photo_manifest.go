@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// PhotoManifestRepository remembers which version of each listing photo
+// imports have already downloaded
+type PhotoManifestRepository interface {
+	ListByListing(ctx context.Context, listingID string) ([]models.PhotoManifestEntry, error)
+	Upsert(ctx context.Context, entry *models.PhotoManifestEntry) error
+}
+
+type photoManifestRepository struct {
+	db *sql.DB
+}
+
+func NewPhotoManifestRepository(db *sql.DB) PhotoManifestRepository {
+	return &photoManifestRepository{db: db}
+}
+
+// ListByListing returns the photos downloaded for a listing
+func (r *photoManifestRepository) ListByListing(ctx context.Context, listingID string) ([]models.PhotoManifestEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT listing_id, url, etag, local_url, size, updated_at
+		FROM photo_manifest WHERE listing_id = ?`, listingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.PhotoManifestEntry{}
+	for rows.Next() {
+		var entry models.PhotoManifestEntry
+		if err := rows.Scan(&entry.ListingID, &entry.URL, &entry.ETag, &entry.LocalURL, &entry.Size, &entry.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// Upsert records the version of a photo just downloaded. URLs are keyed
+// by their hash, as they are too long to index.
+func (r *photoManifestRepository) Upsert(ctx context.Context, entry *models.PhotoManifestEntry) error {
+	query := `INSERT INTO photo_manifest (listing_id, url_hash, url, etag, local_url, size)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE etag = VALUES(etag), local_url = VALUES(local_url), size = VALUES(size), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, entry.ListingID, urlHash(entry.URL), entry.URL, entry.ETag, entry.LocalURL, entry.Size)
+	return err
+}
+
+func urlHash(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
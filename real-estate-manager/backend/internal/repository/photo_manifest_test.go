@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPhotoManifestRepository_ListByListing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"listing_id", "url", "etag", "local_url", "size", "updated_at"}).
+		AddRow("L-100", "https://photos.example.com/1.jpg", `"abc"`, "/images/L-100_0.jpg", 2048, updatedAt)
+	mock.ExpectQuery("SELECT (.+) FROM photo_manifest WHERE listing_id = ?").WithArgs("L-100").WillReturnRows(rows)
+
+	repo := NewPhotoManifestRepository(db)
+	entries, err := repo.ListByListing(context.Background(), "L-100")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ETag != `"abc"` || entries[0].LocalURL != "/images/L-100_0.jpg" || entries[0].Size != 2048 {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPhotoManifestRepository_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	entry := &models.PhotoManifestEntry{
+		ListingID: "L-100",
+		URL:       "https://photos.example.com/1.jpg",
+		ETag:      `"abc"`,
+		LocalURL:  "/images/L-100_0.jpg",
+		Size:      2048,
+	}
+	mock.ExpectExec("INSERT INTO photo_manifest (.+) ON DUPLICATE KEY UPDATE").
+		WithArgs(entry.ListingID, urlHash(entry.URL), entry.URL, entry.ETag, entry.LocalURL, entry.Size).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewPhotoManifestRepository(db)
+	if err := repo.Upsert(context.Background(), entry); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
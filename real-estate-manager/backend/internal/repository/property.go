@@ -3,18 +3,72 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"real-estate-manager/backend/internal/models"
+	"reflect"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
 )
 
 type PropertyRepository interface {
 	Create(ctx context.Context, property *models.Property) error
 	GetByID(ctx context.Context, id int) (*models.Property, error)
+	GetByMLSNumber(ctx context.Context, mlsNumber string) (*models.Property, error)
+	GetByExternalID(ctx context.Context, externalID string) (*models.Property, error)
+	GetByPublicID(ctx context.Context, publicID string) (*models.Property, error)
 	Update(ctx context.Context, property *models.Property) error
+	UpdateWithRevision(ctx context.Context, property *models.Property, revision *Revision) error
+	Upsert(ctx context.Context, property *models.Property) (bool, error)
 	Delete(ctx context.Context, id int) error
 	GetAll(ctx context.Context) ([]models.Property, error)
+	ListAfter(ctx context.Context, afterID, limit int) ([]models.Property, error)
+	Search(ctx context.Context, search models.PropertySearch) ([]models.Property, error)
+	Facets(ctx context.Context, search models.PropertySearch) (*models.PropertyFacets, error)
+	FindStaleCandidates(ctx context.Context, cutoff time.Time) ([]models.Property, error)
+	MarkStale(ctx context.Context, ids []int, at time.Time) error
+	FindExpiring(ctx context.Context, before time.Time) ([]models.Property, error)
+	MarkExpiryNotified(ctx context.Context, ids []int, at time.Time) error
+	FindExpired(ctx context.Context, now time.Time) ([]models.Property, error)
+	MarkExpired(ctx context.Context, ids []int, now time.Time) error
+	BulkUpdate(ctx context.Context, filter models.PropertyFilter, patch models.PropertyPatch, dryRun bool, revision *Revision) (*models.BulkUpdateResult, error)
+	ApplyBatch(ctx context.Context, mutations []models.PropertyMutation, revision *Revision) ([]models.MutationResult, []models.SyncConflict, error)
+}
+
+// Revision asks a write to snapshot each property it changes into
+// property_revisions first, in the same transaction
+type Revision struct {
+	ChangedBy models.NullInt32
+}
+
+// ErrVersionConflict is returned by Update when the property's version no
+// longer matches the one the caller read
+var ErrVersionConflict = errors.New("property version conflict")
+
+// propertySortOrders maps models.PropertySorts to ORDER BY clauses
+var propertySortOrders = map[string]string{
+	"created_at":  "created_at",
+	"-created_at": "created_at DESC",
+	"price":       "price",
+	"-price":      "price DESC",
+	"expires_at":  "expires_at IS NULL, expires_at",
+	"-expires_at": "expires_at DESC",
 }
 
+// propertyColumns is the select list shared by every property query, in the
+// order scanProperty expects
+const propertyColumns = `id, public_id, name, location, price, description, photos, external_id, mls_number, 
+		property_type, bedrooms, bathrooms, square_feet, lot_size, year_built, annual_tax, parking_spaces, 
+		parking_description, listing_type, monthly_rent, security_deposit, lease_term_months, available_from, 
+		category, zoning, cap_rate, noi, unit_count, acreage, utilities, living_area_sqm, lot_area_sqm, agent_id, 
+		last_synced_at, stale_at, expires_at, status, created_at, updated_at, version, organization_id`
+
 type propertyRepository struct {
 	db *sql.DB
 }
@@ -23,15 +77,77 @@ func NewPropertyRepository(db *sql.DB) PropertyRepository {
 	return &propertyRepository{db: db}
 }
 
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx, so a write can run on its
+// own or as part of a larger transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// propertyFields holds the index path of the Property field behind each of
+// propertyColumns, matched by db tag the way sqlx matches them
+var propertyFields = reflectx.NewMapperFunc("db", sqlx.NameMapper).
+	TraversalsByName(reflect.TypeOf(models.Property{}), strings.Split(strings.Join(strings.Fields(propertyColumns), ""), ","))
+
+// scanProperty scans a row whose leading columns are propertyColumns, for
+// queries that select more than the property. Queries selecting only the
+// property are struct scanned by queryProperties.
+func scanProperty(row rowScanner, property *models.Property) error {
+	value := reflect.ValueOf(property).Elem()
+	dest := make([]any, len(propertyFields))
+	for i, index := range propertyFields {
+		dest[i] = reflectx.FieldByIndexes(value, index).Addr().Interface()
+	}
+	return row.Scan(dest...)
+}
+
+// Create inserts a property with its photo rows in one transaction
 func (r *propertyRepository) Create(ctx context.Context, property *models.Property) error {
-	query := `INSERT INTO properties (name, location, price, description, photos, external_id, mls_number, 
-		property_type, bedrooms, bathrooms, square_feet, lot_size, year_built) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createProperty(ctx, tx, property); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// createProperty inserts a property and its photo rows, giving it a public ID
+// unless it has one. Properties created for a tenant belong to its
+// organization, or are shared outside one, whatever the property says.
+func createProperty(ctx context.Context, db dbtx, property *models.Property) error {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		property.OrganizationID.Int32 = int32(tenant.OrganizationID)
+		property.OrganizationID.Valid = tenant.OrganizationID != 0
+	}
+	if property.PublicID == "" {
+		property.PublicID = uuid.NewString()
+	}
+	query := `INSERT INTO properties (public_id, name, location, price, description, photos, external_id, mls_number, 
+		property_type, bedrooms, bathrooms, square_feet, lot_size, year_built, annual_tax, parking_spaces, 
+		parking_description, listing_type, monthly_rent, security_deposit, lease_term_months, available_from, 
+		category, zoning, cap_rate, noi, unit_count, acreage, utilities, living_area_sqm, lot_area_sqm, agent_id, 
+		last_synced_at, expires_at, status, organization_id, photos_updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
 	
-	result, err := r.db.ExecContext(ctx, query, 
-		property.Name, property.Location, property.Price, property.Description, property.Photos,
+	result, err := db.ExecContext(ctx, query, 
+		property.PublicID, property.Name, property.Location, property.Price, property.Description, property.Photos,
 		property.ExternalID, property.MLSNumber, property.PropertyType,
-		property.Bedrooms, property.Bathrooms, property.SquareFeet, property.LotSize, property.YearBuilt)
+		property.Bedrooms, property.Bathrooms, property.SquareFeet, property.LotSize, property.YearBuilt,
+		property.AnnualTax, property.ParkingSpaces, property.ParkingDescription, property.ListingType,
+		property.MonthlyRent, property.SecurityDeposit, property.LeaseTermMonths, property.AvailableFrom,
+		property.Category, property.Zoning, property.CapRate, property.NOI, property.UnitCount, property.Acreage,
+		property.Utilities, property.LivingAreaSqm, property.LotAreaSqm, property.AgentID, property.LastSyncedAt, property.ExpiresAt,
+		property.Status, property.OrganizationID)
 	
 	if err != nil {
 		return err
@@ -43,66 +159,702 @@ func (r *propertyRepository) Create(ctx context.Context, property *models.Proper
 	}
 	
 	property.ID = int(id)
-	return nil
+	property.Version = 1
+	return insertPhotos(ctx, db, property.ID, property.Photos)
 }
 
 func (r *propertyRepository) GetByID(ctx context.Context, id int) (*models.Property, error) {
-	query := `SELECT id, name, location, price, description, photos, external_id, mls_number, 
-		property_type, bedrooms, bathrooms, square_feet, lot_size, year_built, created_at, updated_at 
-		FROM properties WHERE id = ?`
-	row := r.db.QueryRowContext(ctx, query, id)
-
-	var property models.Property
-	if err := row.Scan(&property.ID, &property.Name, &property.Location, &property.Price, 
-		&property.Description, &property.Photos, &property.ExternalID, &property.MLSNumber,
-		&property.PropertyType, &property.Bedrooms, &property.Bathrooms, &property.SquareFeet,
-		&property.LotSize, &property.YearBuilt, &property.CreatedAt, &property.UpdatedAt); err != nil {
+	return getProperty(ctx, r.db, id)
+}
+
+// GetByMLSNumber returns the most recently created property with an MLS
+// number, or nil if there is none
+func (r *propertyRepository) GetByMLSNumber(ctx context.Context, mlsNumber string) (*models.Property, error) {
+	properties, err := r.selectProperties(ctx, selectProperties().
+		Where(sq.Eq{"mls_number": mlsNumber}).Where(tenantFilter(ctx)).
+		OrderBy("id DESC").Limit(1))
+	if err != nil || len(properties) == 0 {
+		return nil, err
+	}
+	return &properties[0], nil
+}
+
+// GetByExternalID returns the property imported from a feed listing by the
+// organization in ctx, or nil if it has not imported the listing. Shared
+// listings are only matched outside an organization.
+func (r *propertyRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Property, error) {
+	properties, err := r.selectProperties(ctx, selectProperties().
+		Where(sq.Eq{"external_id": externalID}).Where(ownerFilter(ctx)))
+	if err != nil || len(properties) == 0 {
+		return nil, err
+	}
+	return &properties[0], nil
+}
+
+// GetByPublicID returns the property with a public ID, or nil if there is
+// none
+func (r *propertyRepository) GetByPublicID(ctx context.Context, publicID string) (*models.Property, error) {
+	properties, err := r.selectProperties(ctx, selectProperties().
+		Where(sq.Eq{"public_id": publicID}).Where(tenantFilter(ctx)))
+	if err != nil || len(properties) == 0 {
+		return nil, err
+	}
+	return &properties[0], nil
+}
+
+func getProperty(ctx context.Context, db dbtx, id int) (*models.Property, error) {
+	query, args, err := selectProperties().Where(sq.Eq{"id": id}).Where(tenantFilter(ctx)).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	properties, err := queryProperties(ctx, db, query, args...)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &property, nil
+	if len(properties) == 0 {
+		return nil, nil
+	}
+	return &properties[0], nil
 }
 
+// Update saves a property and stores its new version in property.Version.
+// A non-zero Version is the version the caller read; if the property has
+// changed since, nothing is written and ErrVersionConflict is returned.
 func (r *propertyRepository) Update(ctx context.Context, property *models.Property) error {
-	query := `UPDATE properties SET name = ?, location = ?, price = ?, description = ?, photos = ?, 
-		external_id = ?, mls_number = ?, property_type = ?, bedrooms = ?, bathrooms = ?, 
-		square_feet = ?, lot_size = ?, year_built = ?, updated_at = NOW() WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, 
-		property.Name, property.Location, property.Price, property.Description, property.Photos,
-		property.ExternalID, property.MLSNumber, property.PropertyType,
-		property.Bedrooms, property.Bathrooms, property.SquareFeet, property.LotSize, 
-		property.YearBuilt, property.ID)
-	return err
+	return r.UpdateWithRevision(ctx, property, nil)
+}
+
+// UpdateWithRevision is Update, first snapshotting the stored property in
+// the same transaction when revision is set, so a failed update leaves no
+// revision behind
+func (r *propertyRepository) UpdateWithRevision(ctx context.Context, property *models.Property, revision *Revision) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if revision != nil {
+		if err := snapshotProperties(ctx, tx, tenantProperty(ctx, property.ID), revision); err != nil {
+			return err
+		}
+	}
+	updated, err := updateProperty(ctx, tx, property)
+	if err != nil {
+		return err
+	}
+	if !updated && property.Version != 0 {
+		return ErrVersionConflict
+	}
+	return tx.Commit()
+}
+
+// Upsert saves an imported property: the row with its ID, or else with its
+// external ID, owned by the organization in ctx is updated whatever its
+// version, or a new one is created. Listings matched by MLS number carry
+// the ID of a row that has no external ID yet. It reports whether the
+// property was created.
+func (r *propertyRepository) Upsert(ctx context.Context, property *models.Property) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Locking the row, or the gap where it would go, keeps a concurrent
+	// import of the same listing from inserting it twice
+	var match sq.Sqlizer = sq.Eq{"external_id": property.ExternalID}
+	if property.ID != 0 {
+		match = sq.Eq{"id": property.ID}
+	}
+	query, args, err := sqlBuilder.Select("id").From("properties").
+		Where(match).Where(ownerFilter(ctx)).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return false, err
+	}
+	var id int
+	switch err := tx.QueryRowContext(ctx, query, args...).Scan(&id); {
+	case errors.Is(err, sql.ErrNoRows):
+		if err := createProperty(ctx, tx, property); err != nil {
+			return false, err
+		}
+		return true, tx.Commit()
+	case err != nil:
+		return false, err
+	}
+
+	property.ID, property.Version = id, 0
+	if _, err := updateProperty(ctx, tx, property); err != nil {
+		return false, err
+	}
+	return false, tx.Commit()
+}
+
+// updateProperty reports whether a row was written. The photo rows are
+// replaced along with the property.
+func updateProperty(ctx context.Context, db dbtx, property *models.Property) (bool, error) {
+	// photos_updated_at is assigned before photos so it compares against the
+	// stored list and only moves when the photos actually change; the
+	// expiry notice is likewise only cleared when expires_at changes.
+	// LAST_INSERT_ID(expr) hands the new version back through the result.
+	query, args, err := sqlBuilder.Update("properties").
+		Set("name", property.Name).
+		Set("location", property.Location).
+		Set("price", property.Price).
+		Set("description", property.Description).
+		Set("photos_updated_at", sq.Expr("IF(photos <=> CAST(? AS JSON), photos_updated_at, NOW())", property.Photos)).
+		Set("photos", property.Photos).
+		Set("external_id", property.ExternalID).
+		Set("mls_number", property.MLSNumber).
+		Set("property_type", property.PropertyType).
+		Set("bedrooms", property.Bedrooms).
+		Set("bathrooms", property.Bathrooms).
+		Set("square_feet", property.SquareFeet).
+		Set("lot_size", property.LotSize).
+		Set("year_built", property.YearBuilt).
+		Set("annual_tax", property.AnnualTax).
+		Set("parking_spaces", property.ParkingSpaces).
+		Set("parking_description", property.ParkingDescription).
+		Set("listing_type", property.ListingType).
+		Set("monthly_rent", property.MonthlyRent).
+		Set("security_deposit", property.SecurityDeposit).
+		Set("lease_term_months", property.LeaseTermMonths).
+		Set("available_from", property.AvailableFrom).
+		Set("category", property.Category).
+		Set("zoning", property.Zoning).
+		Set("cap_rate", property.CapRate).
+		Set("noi", property.NOI).
+		Set("unit_count", property.UnitCount).
+		Set("acreage", property.Acreage).
+		Set("utilities", property.Utilities).
+		Set("living_area_sqm", property.LivingAreaSqm).
+		Set("lot_area_sqm", property.LotAreaSqm).
+		Set("agent_id", sq.Expr("COALESCE(?, agent_id)", property.AgentID)).
+		Set("status", sq.Expr("COALESCE(NULLIF(?, ''), status)", property.Status)).
+		Set("stale_at", sq.Expr("NULL")).
+		Set("expiry_notified_at", sq.Expr("IF(expires_at <=> ?, expiry_notified_at, NULL)", property.ExpiresAt)).
+		Set("expires_at", property.ExpiresAt).
+		Set("updated_at", sq.Expr("NOW()")).
+		Set("version", sq.Expr("LAST_INSERT_ID(version + 1)")).
+		Where(sq.Eq{"id": property.ID}).
+		Where("(? = 0 OR version = ?)", property.Version, property.Version).
+		Where(tenantFilter(ctx)).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil || rows == 0 {
+		return false, err
+	}
+	version, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	property.Version = int(version)
+	return true, replacePhotos(ctx, db, property.ID, property.Photos)
 }
 
+// Delete removes a property and leaves tombstones for it and its photos so
+// syncing clients learn of the deletion
 func (r *propertyRepository) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM properties WHERE id = ?"
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := deleteProperty(ctx, tx, id, 0); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteProperty deletes a property, only at version when it is non-zero,
+// and reports whether a row was removed
+func deleteProperty(ctx context.Context, db dbtx, id, version int) (bool, error) {
+	query, args, err := sqlBuilder.Delete("properties").
+		Where(sq.Eq{"id": id}).
+		Where("(? = 0 OR version = ?)", version, version).
+		Where(tenantFilter(ctx)).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil || deleted == 0 {
+		return false, err
+	}
+	return true, insertTombstones(ctx, db, id, models.ChangeEntityProperty, models.ChangeEntityPhotos)
 }
 
 func (r *propertyRepository) GetAll(ctx context.Context) ([]models.Property, error) {
-	query := `SELECT id, name, location, price, description, photos, external_id, mls_number, 
-		property_type, bedrooms, bathrooms, square_feet, lot_size, year_built, created_at, updated_at 
-		FROM properties ORDER BY created_at DESC`
-	rows, err := r.db.QueryContext(ctx, query)
+	return r.selectProperties(ctx, selectProperties().Where(tenantFilter(ctx)).OrderBy("created_at DESC"))
+}
+
+// ListAfter returns up to limit properties with an ID greater than afterID,
+// in ID order, for keyset pagination over the whole inventory
+func (r *propertyRepository) ListAfter(ctx context.Context, afterID, limit int) ([]models.Property, error) {
+	return r.selectProperties(ctx, selectProperties().
+		Where(sq.Gt{"id": afterID}).Where(tenantFilter(ctx)).
+		OrderBy("id").Suffix("LIMIT ?", limit))
+}
+
+// Search returns properties matching every criterion in search, in its
+// sort order and page
+func (r *propertyRepository) Search(ctx context.Context, search models.PropertySearch) ([]models.Property, error) {
+	order, ok := propertySortOrders[search.Sort]
+	if !ok {
+		order = "created_at DESC"
+	}
+	// id breaks ties so pages do not overlap
+	query := selectProperties().Where(tenantFilter(ctx)).OrderBy(order, "id DESC")
+	if search.Limit > 0 {
+		query = query.Suffix("LIMIT ? OFFSET ?", search.Limit, (max(search.Page, 1)-1)*search.Limit)
+	}
+	return r.selectProperties(ctx, filterProperties(query, search))
+}
+
+// propertyFacets are the columns Facets counts by
+var propertyFacets = []string{"category", "listing_type", "status", "zoning"}
+
+// Facets counts the properties matching the criteria in search by each of
+// propertyFacets
+func (r *propertyRepository) Facets(ctx context.Context, search models.PropertySearch) (*models.PropertyFacets, error) {
+	counts := make(map[string]map[string]int, len(propertyFacets))
+	for _, column := range propertyFacets {
+		query := filterProperties(sqlBuilder.Select(column, "COUNT(*)").From("properties").
+			Where(tenantFilter(ctx)).Where(sq.NotEq{column: nil}).GroupBy(column), search)
+		values, err := r.countBy(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		counts[column] = values
+	}
+	return &models.PropertyFacets{Category: counts["category"], ListingType: counts["listing_type"],
+		Status: counts["status"], Zoning: counts["zoning"]}, nil
+}
+
+// countBy runs a query selecting a value and a count, and returns the count
+// of each value
+func (r *propertyRepository) countBy(ctx context.Context, query sq.SelectBuilder) (map[string]int, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var properties []models.Property
+	counts := map[string]int{}
 	for rows.Next() {
-		var property models.Property
-		if err := rows.Scan(&property.ID, &property.Name, &property.Location, &property.Price,
-			&property.Description, &property.Photos, &property.ExternalID, &property.MLSNumber,
-			&property.PropertyType, &property.Bedrooms, &property.Bathrooms, &property.SquareFeet,
-			&property.LotSize, &property.YearBuilt, &property.CreatedAt, &property.UpdatedAt); err != nil {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		counts[value] = count
+	}
+	return counts, rows.Err()
+}
+
+// filterProperties narrows a query on properties to the criteria in search
+func filterProperties(query sq.SelectBuilder, search models.PropertySearch) sq.SelectBuilder {
+	if search.Stale {
+		query = query.Where("stale_at IS NOT NULL")
+	}
+	if search.Status != "" {
+		query = query.Where(sq.Eq{"status": search.Status})
+	}
+	if search.ExpiringWithinDays > 0 {
+		query = query.Where(sq.Eq{"status": expirableStatuses}).
+			Where(sq.LtOrEq{"expires_at": time.Now().UTC().AddDate(0, 0, search.ExpiringWithinDays)})
+	}
+	if search.ListingType != "" {
+		query = query.Where(sq.Eq{"listing_type": search.ListingType})
+	}
+	if search.MaxMonthlyRent != nil {
+		query = query.Where(sq.Eq{"listing_type": models.ListingTypeRent}).
+			Where(sq.LtOrEq{"monthly_rent": *search.MaxMonthlyRent})
+	}
+	if !search.AvailableBy.IsZero() {
+		query = query.Where(sq.Eq{"listing_type": models.ListingTypeRent}).
+			Where(sq.Or{sq.Eq{"available_from": nil}, sq.LtOrEq{"available_from": search.AvailableBy}})
+	}
+	if search.Category != "" {
+		query = query.Where(sq.Eq{"category": search.Category})
+	}
+	if search.Zoning != "" {
+		query = query.Where(sq.Eq{"zoning": search.Zoning})
+	}
+	if search.MinCapRate != nil {
+		query = query.Where(sq.Eq{"category": models.CategoryCommercial}).
+			Where(sq.GtOrEq{"cap_rate": *search.MinCapRate})
+	}
+	if search.MaxAnnualTax != nil {
+		query = query.Where(sq.LtOrEq{"COALESCE(annual_tax, 0)": *search.MaxAnnualTax})
+	}
+	if search.MinParkingSpaces != nil {
+		query = query.Where(sq.GtOrEq{"parking_spaces": *search.MinParkingSpaces})
+	}
+	if search.MinPrice != nil {
+		query = query.Where(sq.GtOrEq{"price": *search.MinPrice})
+	}
+	if search.MaxPrice != nil {
+		query = query.Where(sq.LtOrEq{"price": *search.MaxPrice})
+	}
+	if search.MinBedrooms != nil {
+		query = query.Where(sq.GtOrEq{"bedrooms": *search.MinBedrooms})
+	}
+	if search.MinBathrooms != nil {
+		query = query.Where(sq.GtOrEq{"bathrooms": *search.MinBathrooms})
+	}
+	if search.PropertyType != "" {
+		query = query.Where(sq.Eq{"property_type": search.PropertyType})
+	}
+	if search.Location != "" {
+		query = query.Where(sq.Like{"location": "%" + escapeLike(search.Location) + "%"})
+	}
+	if search.MinYearBuilt != nil {
+		query = query.Where(sq.GtOrEq{"year_built": *search.MinYearBuilt})
+	}
+	if search.MaxYearBuilt != nil {
+		query = query.Where(sq.LtOrEq{"year_built": *search.MaxYearBuilt})
+	}
+	if amenities := search.Amenities; !amenities.IsEmpty() {
+		matching := sqlBuilder.Select("property_id").From("property_amenities")
+		if amenities.HasPool != nil {
+			matching = matching.Where(sq.Eq{"has_pool": *amenities.HasPool})
+		}
+		if amenities.MinGarageSpaces != nil {
+			matching = matching.Where(sq.GtOrEq{"garage_spaces": *amenities.MinGarageSpaces})
+		}
+		if amenities.HVACType != nil {
+			matching = matching.Where(sq.Eq{"hvac_type": *amenities.HVACType})
+		}
+		if amenities.MaxHOAFee != nil {
+			matching = matching.Where(sq.LtOrEq{"COALESCE(hoa_fee, 0)": *amenities.MaxHOAFee})
+		}
+		query = query.Where(sq.Expr("id IN (?)", matching))
+	}
+	return query
+}
+
+// FindStaleCandidates returns properties not yet flagged whose last update
+// and last sync are both before cutoff
+func (r *propertyRepository) FindStaleCandidates(ctx context.Context, cutoff time.Time) ([]models.Property, error) {
+	query := `SELECT ` + propertyColumns + ` 
+		FROM properties WHERE stale_at IS NULL AND updated_at < ? 
+		AND (last_synced_at IS NULL OR last_synced_at < ?) ORDER BY id`
+	return r.queryProperties(ctx, query, cutoff, cutoff)
+}
+
+// MarkStale flags the given properties as stale without touching updated_at
+func (r *propertyRepository) MarkStale(ctx context.Context, ids []int, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// updated_at is reassigned to itself so ON UPDATE CURRENT_TIMESTAMP does not fire
+	query, args, err := sqlBuilder.Update("properties").
+		Set("stale_at", at).
+		Set("updated_at", sq.Expr("updated_at")).
+		Where(sq.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// expirableStatuses are the statuses of listings that expire
+var expirableStatuses = []string{models.PropertyStatusActive, models.PropertyStatusPending}
+
+// FindExpiring returns the active and pending listings expiring before
+// before whose agent was not yet warned, soonest first
+func (r *propertyRepository) FindExpiring(ctx context.Context, before time.Time) ([]models.Property, error) {
+	return r.selectProperties(ctx, selectProperties().
+		Where(sq.Eq{"status": expirableStatuses, "expiry_notified_at": nil}).
+		Where(sq.LtOrEq{"expires_at": before}).
+		OrderBy("expires_at", "id"))
+}
+
+// MarkExpiryNotified records that the agents of the given properties were
+// warned of their expiry, without touching updated_at
+func (r *propertyRepository) MarkExpiryNotified(ctx context.Context, ids []int, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := sqlBuilder.Update("properties").
+		Set("expiry_notified_at", at).
+		Set("updated_at", sq.Expr("updated_at")).
+		Where(sq.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// FindExpired returns the active and pending listings whose expires_at is
+// not after now
+func (r *propertyRepository) FindExpired(ctx context.Context, now time.Time) ([]models.Property, error) {
+	return r.selectProperties(ctx, selectProperties().
+		Where(sq.Eq{"status": expirableStatuses}).
+		Where(sq.LtOrEq{"expires_at": now}).
+		OrderBy("id"))
+}
+
+// MarkExpired moves the given listings to the expired status as a new
+// version. Listings renewed or taken off the market since they were found
+// are left alone.
+func (r *propertyRepository) MarkExpired(ctx context.Context, ids []int, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := sqlBuilder.Update("properties").
+		Set("status", models.PropertyStatusExpired).
+		Set("updated_at", sq.Expr("NOW()")).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": ids, "status": expirableStatuses}).
+		Where(sq.LtOrEq{"expires_at": now}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// BulkUpdate applies patch to every property matching filter in a single
+// transaction, snapshotting the matched rows first when revision is set. In
+// dry-run mode the matched count is returned and the transaction is rolled
+// back.
+func (r *propertyRepository) BulkUpdate(ctx context.Context, filter models.PropertyFilter, patch models.PropertyPatch, dryRun bool, revision *Revision) (*models.BulkUpdateResult, error) {
+	where := propertyFilterCondition(filter)
+	if tenant := tenantFilter(ctx); tenant != nil {
+		where = append(where, tenant)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &models.BulkUpdateResult{DryRun: dryRun}
+	countQuery, countArgs, err := sqlBuilder.Select("COUNT(*)").From("properties").Where(where).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&result.Matched); err != nil {
+		return nil, err
+	}
+	if dryRun || result.Matched == 0 {
+		return result, nil
+	}
+	if revision != nil {
+		if err := snapshotProperties(ctx, tx, where, revision); err != nil {
 			return nil, err
 		}
-		properties = append(properties, property)
+	}
+
+	update := sqlBuilder.Update("properties")
+	if patch.Status != nil {
+		update = update.Set("status", *patch.Status)
+	}
+	if patch.AgentID != nil {
+		update = update.Set("agent_id", *patch.AgentID)
+	}
+	query, args, err := update.
+		Set("updated_at", sq.Expr("NOW()")).
+		Set("version", sq.Expr("version + 1")).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if result.Updated, err = res.RowsAffected(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// snapshotProperties stores the properties matching where as revisions,
+// locking them until the transaction ends
+func snapshotProperties(ctx context.Context, tx dbtx, where sq.Sqlizer, revision *Revision) error {
+	query, args, err := selectProperties().Where(where).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return err
+	}
+	properties, err := queryProperties(ctx, tx, query, args...)
+	if err != nil {
+		return err
+	}
+	for _, property := range properties {
+		snapshot, err := json.Marshal(property)
+		if err != nil {
+			return err
+		}
+		if err := insertRevision(ctx, tx, &models.PropertyRevision{PropertyID: property.ID, Snapshot: snapshot, ChangedBy: revision.ChangedBy}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tenantProperty is the condition selecting the property id of the tenant
+// in ctx
+func tenantProperty(ctx context.Context, id int) sq.And {
+	where := sq.And{sq.Eq{"id": id}}
+	if tenant := tenantFilter(ctx); tenant != nil {
+		where = append(where, tenant)
+	}
+	return where
+}
+
+// ApplyBatch applies mutations in order within one transaction. Updates and
+// deletes only apply at their BaseVersion; if any mutation conflicts, the
+// transaction is rolled back and the conflicts are returned instead of
+// results. When revision is set, each property is snapshotted in the same
+// transaction before it is updated, so a batch that is rolled back leaves
+// no revisions behind.
+func (r *propertyRepository) ApplyBatch(ctx context.Context, mutations []models.PropertyMutation, revision *Revision) ([]models.MutationResult, []models.SyncConflict, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var results []models.MutationResult
+	var conflicts []models.SyncConflict
+	for i, mutation := range mutations {
+		result := models.MutationResult{Index: i, Op: mutation.Op, ClientRef: mutation.ClientRef, ID: mutation.ID}
+		applied := true
+		switch mutation.Op {
+		case models.MutationCreate:
+			if err := createProperty(ctx, tx, mutation.Property); err != nil {
+				return nil, nil, err
+			}
+			result.ID, result.Version = mutation.Property.ID, mutation.Property.Version
+		case models.MutationUpdate:
+			if revision != nil {
+				if err := snapshotProperties(ctx, tx, tenantProperty(ctx, mutation.ID), revision); err != nil {
+					return nil, nil, err
+				}
+			}
+			mutation.Property.ID, mutation.Property.Version = mutation.ID, mutation.BaseVersion
+			if applied, err = updateProperty(ctx, tx, mutation.Property); err != nil {
+				return nil, nil, err
+			}
+			result.Version = mutation.Property.Version
+		case models.MutationDelete:
+			if applied, err = deleteProperty(ctx, tx, mutation.ID, mutation.BaseVersion); err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown mutation op %q", mutation.Op)
+		}
+
+		if !applied {
+			current, err := getProperty(ctx, tx, mutation.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			// Deleting a property that is already gone is not a conflict
+			if current == nil && mutation.Op == models.MutationDelete {
+				results = append(results, result)
+				continue
+			}
+			conflicts = append(conflicts, models.SyncConflict{Index: i, ID: mutation.ID, BaseVersion: mutation.BaseVersion, Current: current})
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if len(conflicts) > 0 {
+		return nil, conflicts, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return results, nil, nil
+}
+
+// propertyFilterCondition builds the condition selecting the properties of
+// filter. An empty filter matches nothing rather than everything.
+func propertyFilterCondition(filter models.PropertyFilter) sq.And {
+	if filter.IsEmpty() {
+		return sq.And{sq.Expr("1 = 0")}
+	}
+
+	conditions := sq.And{}
+	if len(filter.IDs) > 0 {
+		conditions = append(conditions, sq.Eq{"id": filter.IDs})
+	}
+	if filter.AgentID != nil {
+		conditions = append(conditions, sq.Eq{"agent_id": *filter.AgentID})
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, sq.Eq{"status": *filter.Status})
+	}
+	if filter.PropertyType != nil {
+		conditions = append(conditions, sq.Eq{"property_type": *filter.PropertyType})
+	}
+	return conditions
+}
+
+// selectProperties runs a query built from selectProperties
+func (r *propertyRepository) selectProperties(ctx context.Context, query sq.SelectBuilder) ([]models.Property, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return r.queryProperties(ctx, sql, args...)
+}
+
+func (r *propertyRepository) queryProperties(ctx context.Context, query string, args ...any) ([]models.Property, error) {
+	return queryProperties(ctx, r.db, query, args...)
+}
+
+// queryProperties runs a query selecting propertyColumns and scans every row
+// into a Property by the columns' db tags
+func queryProperties(ctx context.Context, db dbtx, query string, args ...any) ([]models.Property, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var properties []models.Property
+	if err := sqlx.StructScan(rows, &properties); err != nil {
+		return nil, err
 	}
 	return properties, nil
 }
\ No newline at end of file
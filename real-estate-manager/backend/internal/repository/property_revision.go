@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+)
+
+type PropertyRevisionRepository interface {
+	Create(ctx context.Context, revision *models.PropertyRevision) error
+	GetByID(ctx context.Context, id int) (*models.PropertyRevision, error)
+	GetByPropertyID(ctx context.Context, propertyID int) ([]models.PropertyRevision, error)
+}
+
+type propertyRevisionRepository struct {
+	db *sql.DB
+}
+
+func NewPropertyRevisionRepository(db *sql.DB) PropertyRevisionRepository {
+	return &propertyRevisionRepository{db: db}
+}
+
+func (r *propertyRevisionRepository) Create(ctx context.Context, revision *models.PropertyRevision) error {
+	return insertRevision(ctx, r.db, revision)
+}
+
+// insertRevision stores revision on db, so a property write can snapshot
+// within its own transaction
+func insertRevision(ctx context.Context, db dbtx, revision *models.PropertyRevision) error {
+	query := `INSERT INTO property_revisions (property_id, snapshot, changed_by) VALUES (?, ?, ?)`
+	result, err := db.ExecContext(ctx, query, revision.PropertyID, []byte(revision.Snapshot), revision.ChangedBy)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	revision.ID = int(id)
+	return nil
+}
+
+func (r *propertyRevisionRepository) GetByID(ctx context.Context, id int) (*models.PropertyRevision, error) {
+	query := `SELECT id, property_id, snapshot, changed_by, created_at FROM property_revisions WHERE id = ?`
+
+	var revision models.PropertyRevision
+	var snapshot []byte
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&revision.ID, &revision.PropertyID, &snapshot,
+		&revision.ChangedBy, &revision.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	revision.Snapshot = snapshot
+	return &revision, nil
+}
+
+// GetByPropertyID returns a property's revisions, newest first
+func (r *propertyRevisionRepository) GetByPropertyID(ctx context.Context, propertyID int) ([]models.PropertyRevision, error) {
+	query := `SELECT id, property_id, snapshot, changed_by, created_at FROM property_revisions 
+		WHERE property_id = ? ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []models.PropertyRevision
+	for rows.Next() {
+		var revision models.PropertyRevision
+		var snapshot []byte
+		if err := rows.Scan(&revision.ID, &revision.PropertyID, &snapshot, &revision.ChangedBy, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revision.Snapshot = snapshot
+		revisions = append(revisions, revision)
+	}
+	return revisions, rows.Err()
+}
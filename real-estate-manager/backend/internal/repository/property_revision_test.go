@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPropertyRevisionRepository_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError bool
+		expectedID    int
+	}{
+		{
+			name: "successful creation",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO property_revisions").
+					WithArgs(1, []byte(`{"id":1}`), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(5, 1))
+			},
+			expectedID: 5,
+		},
+		{
+			name: "database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO property_revisions").
+					WillReturnError(errors.New("insert failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewPropertyRevisionRepository(db)
+			revision := &models.PropertyRevision{PropertyID: 1, Snapshot: json.RawMessage(`{"id":1}`)}
+			err = repo.Create(context.Background(), revision)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				if revision.ID != tt.expectedID {
+					t.Errorf("Expected ID %d, got %d", tt.expectedID, revision.ID)
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPropertyRevisionRepository_GetByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectNil     bool
+		expectedError bool
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "property_id", "snapshot", "changed_by", "created_at"}).
+					AddRow(5, 1, []byte(`{"id":1}`), 3, time.Now())
+				mock.ExpectQuery("SELECT (.+) FROM property_revisions WHERE id = ?").
+					WithArgs(5).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM property_revisions WHERE id = ?").
+					WithArgs(5).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewPropertyRevisionRepository(db)
+			revision, err := repo.GetByID(context.Background(), 5)
+
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if tt.expectNil {
+				if revision != nil {
+					t.Errorf("Expected nil revision, got %+v", revision)
+				}
+			} else if revision == nil || string(revision.Snapshot) != `{"id":1}` || revision.ChangedBy.Int32 != 3 {
+				t.Errorf("Unexpected revision: %+v", revision)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"real-estate-manager/backend/internal/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
 )
 
 func TestNewPropertyRepository(t *testing.T) {
@@ -25,6 +27,18 @@ func TestNewPropertyRepository(t *testing.T) {
 	}
 }
 
+func TestPropertyFields(t *testing.T) {
+	columns := strings.Split(strings.Join(strings.Fields(propertyColumns), ""), ",")
+	if len(propertyFields) != len(columns) {
+		t.Fatalf("expected %d fields, got %d", len(columns), len(propertyFields))
+	}
+	for i, index := range propertyFields {
+		if len(index) == 0 {
+			t.Errorf("column %q has no Property field with a matching db tag", columns[i])
+		}
+	}
+}
+
 func TestPropertyRepository_Create(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -48,16 +62,44 @@ func TestPropertyRepository_Create(t *testing.T) {
 				},
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO properties").
-					WithArgs("Beautiful House", "123 Main St, New York, NY", 500000.00, 
+					WithArgs(sqlmock.AnyArg(), "Beautiful House", "123 Main St, New York, NY", 500000.00, 
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 						sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: false,
 			expectedID:    1,
 		},
+		{
+			name: "photos are written as rows in the same transaction",
+			property: &models.Property{
+				Name:     "Test House",
+				Location: "456 Oak St",
+				Price:    300000.00,
+				Photos: models.PhotoList{{URL: "https://example.com/1.jpg"}, {URL: "https://example.com/2.jpg", LocalURL: "/images/L-1_1.jpg",
+					ThumbnailURL: "/images/L-1_1_thumb.jpg", MediumURL: "/images/L-1_1_medium.jpg", Caption: "Kitchen", AltText: "Kitchen with island"}},
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO properties").WillReturnResult(sqlmock.NewResult(6, 1))
+				mock.ExpectExec(`INSERT INTO property_photos \(property_id,position,url,local_url,thumbnail_url,medium_url,caption,alt_text\) VALUES \(\?,\?,\?,\?,\?,\?,\?,\?\),\(\?,\?,\?,\?,\?,\?,\?,\?\)`).
+					WithArgs(6, 0, "https://example.com/1.jpg", "", "", "", "", "", 6, 1, "https://example.com/2.jpg", "/images/L-1_1.jpg",
+						"/images/L-1_1_thumb.jpg", "/images/L-1_1_medium.jpg", "Kitchen", "Kitchen with island").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedError: false,
+			expectedID:    6,
+		},
 		{
 			name: "database error during insert",
 			property: &models.Property{
@@ -66,8 +108,10 @@ func TestPropertyRepository_Create(t *testing.T) {
 				Price:    300000.00,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO properties").
 					WillReturnError(errors.New("database connection failed"))
+				mock.ExpectRollback()
 			},
 			expectedError: true,
 			errorMessage:  "database connection failed",
@@ -80,8 +124,10 @@ func TestPropertyRepository_Create(t *testing.T) {
 				Price:    300000.00,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO properties").
 					WillReturnResult(sqlmock.NewErrorResult(errors.New("last insert id error")))
+				mock.ExpectRollback()
 			},
 			expectedError: true,
 			errorMessage:  "last insert id error",
@@ -114,6 +160,9 @@ func TestPropertyRepository_Create(t *testing.T) {
 				if tt.property.ID != tt.expectedID {
 					t.Errorf("Expected ID %d, got %d", tt.expectedID, tt.property.ID)
 				}
+				if _, err := uuid.Parse(tt.property.PublicID); err != nil {
+					t.Errorf("Expected a public ID, got %q", tt.property.PublicID)
+				}
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -123,6 +172,66 @@ func TestPropertyRepository_Create(t *testing.T) {
 	}
 }
 
+func TestPropertyRepository_TenantScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+	repo := NewPropertyRepository(db)
+	ctx := WithTenant(context.Background(), Tenant{OrganizationID: 4})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO properties").
+		WithArgs(sqlmock.AnyArg(), "Loft", "1 King St", 250000.00,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(),
+			models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+	property := &models.Property{Name: "Loft", Location: "1 King St", Price: 250000.00,
+		OrganizationID: models.NullInt32{NullInt32: sql.NullInt32{Int32: 5, Valid: true}}}
+	if err := repo.Create(ctx, property); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if property.OrganizationID.Int32 != 4 {
+		t.Errorf("expected the property to belong to organization 4, got %+v", property.OrganizationID)
+	}
+
+	mock.ExpectQuery(`FROM properties WHERE id = \? AND \(organization_id IS NULL OR organization_id = \?\)`).
+		WithArgs(9, 4).
+		WillReturnError(sql.ErrNoRows)
+	if property, err := repo.GetByID(ctx, 9); err != nil || property != nil {
+		t.Errorf("expected another organization's property to be hidden, got %v and %v", property, err)
+	}
+
+	mock.ExpectQuery(`FROM properties WHERE public_id = \? AND \(organization_id IS NULL OR organization_id = \?\)`).
+		WithArgs("6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if property, err := repo.GetByPublicID(ctx, "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b"); err != nil || property != nil {
+		t.Errorf("expected another organization's property to be hidden by its public ID, got %v and %v", property, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM properties WHERE id = \? AND \(\? = 0 OR version = \?\) AND organization_id IS NULL`).
+		WithArgs(9, 0, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	if err := repo.Delete(WithTenant(context.Background(), Tenant{}), 9); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
 func TestPropertyRepository_GetByID(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -139,7 +248,8 @@ func TestPropertyRepository_GetByID(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "location", "price", "description", "photos", 
 					"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
-					"square_feet", "lot_size", "year_built", "created_at", "updated_at",
+					"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+					"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
 				}).AddRow(
 					1, "Beautiful House", "123 Main St", 500000.00, 
 					models.NullString{NullString: sql.NullString{String: "Beautiful house", Valid: true}},
@@ -147,7 +257,8 @@ func TestPropertyRepository_GetByID(t *testing.T) {
 					models.NullString{}, models.NullString{}, models.NullString{},
 					models.NullInt32{}, models.NullInt32{}, models.NullInt32{},
 					models.NullString{}, models.NullInt32{},
-					time.Now(), time.Now(),
+					models.NullFloat64{}, models.NullFloat64{},
+					models.NullInt32{}, models.NullTime{}, models.NullTime{}, "active", time.Now(), time.Now(), 1, nil,
 				)
 				mock.ExpectQuery("SELECT (.+) FROM properties WHERE id = ?").
 					WithArgs(1).
@@ -245,6 +356,7 @@ func TestPropertyRepository_Update(t *testing.T) {
 	tests := []struct {
 		name          string
 		property      *models.Property
+		revision      *Revision
 		setupMock     func(sqlmock.Sqlmock)
 		expectedError bool
 		errorMessage  string
@@ -264,12 +376,21 @@ func TestPropertyRepository_Update(t *testing.T) {
 				},
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE properties SET").
 					WithArgs("Updated House", "456 Oak St, Boston, MA", 750000.00,
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-						sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 0, 0).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec("DELETE FROM property_photos WHERE property_id = \\?").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 			},
 			expectedError: false,
 		},
@@ -282,8 +403,10 @@ func TestPropertyRepository_Update(t *testing.T) {
 				Price:    500000.00,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE properties SET").
 					WillReturnError(errors.New("update failed"))
+				mock.ExpectRollback()
 			},
 			expectedError: true,
 			errorMessage:  "update failed",
@@ -297,11 +420,68 @@ func TestPropertyRepository_Update(t *testing.T) {
 				Price:    100000.00,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE properties SET").
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 			},
 			expectedError: false,
 		},
+		{
+			name: "stale version is rejected",
+			property: &models.Property{
+				ID:       1,
+				Name:     "Updated House",
+				Location: "456 Oak St, Boston, MA",
+				Price:    750000.00,
+				Version:  3,
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE properties SET (.+) WHERE id = \\? AND \\(\\? = 0 OR version = \\?\\)").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: true,
+			errorMessage:  "property version conflict",
+		},
+		{
+			name:     "snapshots the stored property before updating it",
+			property: &models.Property{ID: 1, Name: "Updated House", Location: "456 Oak St, Boston, MA", Price: 750000.00},
+			revision: &Revision{ChangedBy: models.NullInt32{NullInt32: sql.NullInt32{Int32: 9, Valid: true}}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM properties WHERE \\(id = \\?\\) FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "House"))
+				mock.ExpectExec("INSERT INTO property_revisions").
+					WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec("UPDATE properties SET").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec("DELETE FROM property_photos WHERE property_id = \\?").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed update leaves no revision",
+			property: &models.Property{ID: 1, Name: "Updated House", Location: "456 Oak St, Boston, MA", Price: 750000.00},
+			revision: &Revision{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM properties").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "House"))
+				mock.ExpectExec("INSERT INTO property_revisions").
+					WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec("UPDATE properties SET").
+					WillReturnError(errors.New("update failed"))
+				mock.ExpectRollback()
+			},
+			expectedError: true,
+			errorMessage:  "update failed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -315,7 +495,7 @@ func TestPropertyRepository_Update(t *testing.T) {
 			tt.setupMock(mock)
 
 			repo := NewPropertyRepository(db)
-			err = repo.Update(context.Background(), tt.property)
+			err = repo.UpdateWithRevision(context.Background(), tt.property, tt.revision)
 
 			if tt.expectedError {
 				if err == nil {
@@ -348,9 +528,17 @@ func TestPropertyRepository_Delete(t *testing.T) {
 			name: "successful property deletion",
 			id:   1,
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM properties WHERE id = ?").
-					WithArgs(1).
+					WithArgs(1, 0, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO tombstones").
+					WithArgs(models.ChangeEntityProperty, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO tombstones").
+					WithArgs(models.ChangeEntityPhotos, 1).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: false,
 		},
@@ -358,9 +546,11 @@ func TestPropertyRepository_Delete(t *testing.T) {
 			name: "database error during deletion",
 			id:   1,
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM properties WHERE id = ?").
-					WithArgs(1).
+					WithArgs(1, 0, 0).
 					WillReturnError(errors.New("delete operation failed"))
+				mock.ExpectRollback()
 			},
 			expectedError: true,
 			errorMessage:  "delete operation failed",
@@ -369,9 +559,11 @@ func TestPropertyRepository_Delete(t *testing.T) {
 			name: "property not found for deletion",
 			id:   999,
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM properties WHERE id = ?").
-					WithArgs(999).
+					WithArgs(999, 0, 0).
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 			},
 			expectedError: false,
 		},
@@ -423,21 +615,24 @@ func TestPropertyRepository_GetAll(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "location", "price", "description", "photos",
 					"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
-					"square_feet", "lot_size", "year_built", "created_at", "updated_at",
+					"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+					"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
 				}).AddRow(
 					1, "House 1", "Location 1", 500000.00,
 					models.NullString{}, models.PhotoList{},
 					models.NullString{}, models.NullString{}, models.NullString{},
 					models.NullInt32{}, models.NullInt32{}, models.NullInt32{},
 					models.NullString{}, models.NullInt32{},
-					time.Now(), time.Now(),
+					models.NullFloat64{}, models.NullFloat64{},
+					models.NullInt32{}, models.NullTime{}, models.NullTime{}, "active", time.Now(), time.Now(), 1, nil,
 				).AddRow(
 					2, "House 2", "Location 2", 750000.00,
 					models.NullString{}, models.PhotoList{},
 					models.NullString{}, models.NullString{}, models.NullString{},
 					models.NullInt32{}, models.NullInt32{}, models.NullInt32{},
 					models.NullString{}, models.NullInt32{},
-					time.Now(), time.Now(),
+					models.NullFloat64{}, models.NullFloat64{},
+					models.NullInt32{}, models.NullTime{}, models.NullTime{}, "active", time.Now(), time.Now(), 1, nil,
 				)
 				mock.ExpectQuery("SELECT (.+) FROM properties ORDER BY created_at DESC").
 					WillReturnRows(rows)
@@ -464,7 +659,8 @@ func TestPropertyRepository_GetAll(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "location", "price", "description", "photos",
 					"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
-					"square_feet", "lot_size", "year_built", "created_at", "updated_at",
+					"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+					"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
 				})
 				mock.ExpectQuery("SELECT (.+) FROM properties ORDER BY created_at DESC").
 					WillReturnRows(rows)
@@ -488,14 +684,16 @@ func TestPropertyRepository_GetAll(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "location", "price", "description", "photos",
 					"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
-					"square_feet", "lot_size", "year_built", "created_at", "updated_at",
+					"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+					"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
 				}).AddRow(
 					"invalid_id", "House 1", "Location 1", 500000.00,
 					models.NullString{}, models.PhotoList{},
 					models.NullString{}, models.NullString{}, models.NullString{},
 					models.NullInt32{}, models.NullInt32{}, models.NullInt32{},
 					models.NullString{}, models.NullInt32{},
-					time.Now(), time.Now(),
+					models.NullFloat64{}, models.NullFloat64{},
+					models.NullInt32{}, models.NullTime{}, models.NullTime{}, "active", time.Now(), time.Now(), 1, nil,
 				)
 				mock.ExpectQuery("SELECT (.+) FROM properties ORDER BY created_at DESC").
 					WillReturnRows(rows)
@@ -566,3 +764,678 @@ func TestPropertyRepository_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestPropertyRepository_MarkStale(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		ids           []int
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError bool
+	}{
+		{
+			name: "marks all given properties",
+			ids:  []int{1, 2},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE properties SET stale_at = \?, updated_at = updated_at WHERE id IN \(\?,\?\)`).
+					WithArgs(at, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name:      "no ids is a no-op",
+			ids:       nil,
+			setupMock: func(mock sqlmock.Sqlmock) {},
+		},
+		{
+			name: "database error",
+			ids:  []int{1},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE properties SET stale_at").
+					WillReturnError(errors.New("update failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewPropertyRepository(db)
+			err = repo.MarkStale(context.Background(), tt.ids, at)
+
+			if tt.expectedError && err == nil {
+				t.Error("Expected error but got none")
+			} else if !tt.expectedError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPropertyRepository_FindStaleCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "location", "price", "description", "photos",
+		"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+		"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+		"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
+	}).AddRow(
+		1, "House 1", "Location 1", 500000.00,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		7, nil, nil, "active", cutoff, cutoff, 1, nil,
+	)
+	mock.ExpectQuery("SELECT (.+) FROM properties WHERE stale_at IS NULL AND updated_at < ?").
+		WithArgs(cutoff, cutoff).
+		WillReturnRows(rows)
+
+	repo := NewPropertyRepository(db)
+	properties, err := repo.FindStaleCandidates(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(properties) != 1 || !properties[0].AgentID.Valid || properties[0].AgentID.Int32 != 7 {
+		t.Errorf("Expected one candidate assigned to agent 7, got %+v", properties)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_Expiry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := now.AddDate(0, 0, 7)
+	rows := sqlmock.NewRows([]string{
+		"id", "public_id", "name", "location", "price", "description", "photos",
+		"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+		"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+		"agent_id", "last_synced_at", "stale_at", "expires_at", "status", "created_at", "updated_at", "version", "organization_id",
+	}).AddRow(
+		1, "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", "House 1", "Location 1", 500000.00,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		7, nil, nil, before, "active", now, now, 1, nil,
+	)
+	mock.ExpectQuery(`SELECT (.+) FROM properties WHERE expiry_notified_at IS NULL AND status IN \(\?,\?\) AND expires_at <= \? ORDER BY expires_at, id`).
+		WithArgs(models.PropertyStatusActive, models.PropertyStatusPending, before).
+		WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE properties SET status = \?, updated_at = NOW\(\), version = version \+ 1 WHERE id IN \(\?,\?\) AND status IN \(\?,\?\) AND expires_at <= \?`).
+		WithArgs(models.PropertyStatusExpired, 1, 2, models.PropertyStatusActive, models.PropertyStatusPending, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewPropertyRepository(db)
+	properties, err := repo.FindExpiring(context.Background(), before)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(properties) != 1 || !properties[0].ExpiresAt.Valid || !properties[0].ExpiresAt.Time.Equal(before) {
+		t.Errorf("Unexpected expiring listings %+v", properties)
+	}
+	if err := repo.MarkExpired(context.Background(), []int{1, 2}, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_ListAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "location", "price", "description", "photos",
+		"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+		"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+		"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
+	}).AddRow(
+		11, "House 11", "Location 11", 500000.00,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, "active", now, now, 1, nil,
+	)
+	mock.ExpectQuery("SELECT (.+) FROM properties WHERE id > \\? ORDER BY id LIMIT \\?").
+		WithArgs(10, 2).
+		WillReturnRows(rows)
+
+	repo := NewPropertyRepository(db)
+	properties, err := repo.ListAfter(context.Background(), 10, 2)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(properties) != 1 || properties[0].ID != 11 {
+		t.Errorf("Expected property 11, got %+v", properties)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+	repo := NewPropertyRepository(db)
+	ctx := WithTenant(context.Background(), Tenant{OrganizationID: 4})
+	externalID := models.NullString{NullString: sql.NullString{String: "L-100", Valid: true}}
+
+	mock.ExpectQuery(`FROM properties WHERE external_id = \? AND organization_id = \?`).
+		WithArgs("L-100", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if property, err := repo.GetByExternalID(ctx, "L-100"); err != nil || property != nil {
+		t.Errorf("expected no imported property, got %v and %v", property, err)
+	}
+
+	// A listing not imported before is created
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM properties WHERE external_id = \? AND organization_id = \? FOR UPDATE`).
+		WithArgs(externalID, 4).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO properties").WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+	property := &models.Property{Name: "Loft", Location: "1 King St", Price: 250000.00, ExternalID: externalID}
+	if created, err := repo.Upsert(ctx, property); err != nil || !created || property.ID != 7 {
+		t.Fatalf("expected property 7 to be created, got %+v, %v and %v", property, created, err)
+	}
+
+	// Importing it again updates it whatever its version
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM properties WHERE external_id = \? AND organization_id = \? FOR UPDATE`).
+		WithArgs(externalID, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec(`UPDATE properties SET (.+) WHERE id = \? AND \(\? = 0 OR version = \?\)`).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("DELETE FROM property_photos WHERE property_id = \\?").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	property = &models.Property{Name: "Loft", Location: "1 King St", Price: 240000.00, ExternalID: externalID, Version: 5}
+	if created, err := repo.Upsert(ctx, property); err != nil || created || property.ID != 7 || property.Version != 2 {
+		t.Fatalf("expected property 7 to be updated to version 2, got %+v, %v and %v", property, created, err)
+	}
+
+	// A listing matched by MLS number updates that row by ID, since the row
+	// has no external ID yet
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM properties WHERE id = \? AND organization_id = \? FOR UPDATE`).
+		WithArgs(9, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectExec(`UPDATE properties SET (.+) WHERE id = \? AND \(\? = 0 OR version = \?\)`).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("DELETE FROM property_photos WHERE property_id = \\?").
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	property = &models.Property{ID: 9, PublicID: "3f1c2a9e-8b4d-4c6e-9a57-1d2e3f4a5b6c", Name: "Loft", Location: "2 King St", Price: 310000.00, ExternalID: externalID, Version: 3}
+	if created, err := repo.Upsert(ctx, property); err != nil || created || property.ID != 9 || property.Version != 4 {
+		t.Fatalf("expected property 9 to be updated to version 4, got %+v, %v and %v", property, created, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_BulkUpdate(t *testing.T) {
+	agentID := 7
+	withdrawn := "withdrawn"
+
+	tests := []struct {
+		name           string
+		filter         models.PropertyFilter
+		patch          models.PropertyPatch
+		dryRun         bool
+		revision       *Revision
+		setupMock      func(sqlmock.Sqlmock)
+		expectedResult *models.BulkUpdateResult
+		expectedError  bool
+	}{
+		{
+			name:   "updates matching rows in a transaction",
+			filter: models.PropertyFilter{AgentID: &agentID},
+			patch:  models.PropertyPatch{Status: &withdrawn},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE \(agent_id = \?\) FOR UPDATE`).
+					WithArgs(agentID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectExec(`UPDATE properties SET status = \?, updated_at = NOW\(\), version = version \+ 1 WHERE \(agent_id = \?\)`).
+					WithArgs(withdrawn, agentID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedResult: &models.BulkUpdateResult{Matched: 3, Updated: 2},
+		},
+		{
+			name:     "snapshots matched rows before updating them",
+			filter:   models.PropertyFilter{AgentID: &agentID},
+			patch:    models.PropertyPatch{Status: &withdrawn},
+			revision: &Revision{ChangedBy: models.NullInt32{NullInt32: sql.NullInt32{Int32: 9, Valid: true}}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT COUNT").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery(`SELECT (.+) FROM properties WHERE \(agent_id = \?\) FOR UPDATE`).
+					WithArgs(agentID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).
+						AddRow(1, "House 1", "active").
+						AddRow(2, "House 2", "pending"))
+				for _, id := range []int{1, 2} {
+					mock.ExpectExec("INSERT INTO property_revisions").
+						WithArgs(id, sqlmock.AnyArg(), sqlmock.AnyArg()).
+						WillReturnResult(sqlmock.NewResult(int64(id), 1))
+				}
+				mock.ExpectExec("UPDATE properties SET").
+					WithArgs(withdrawn, agentID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedResult: &models.BulkUpdateResult{Matched: 2, Updated: 2},
+		},
+		{
+			name:     "snapshot error rolls back",
+			filter:   models.PropertyFilter{AgentID: &agentID},
+			patch:    models.PropertyPatch{Status: &withdrawn},
+			revision: &Revision{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT COUNT").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT (.+) FROM properties").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO property_revisions").
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+			},
+			expectedError: true,
+		},
+		{
+			name:   "dry run only counts",
+			filter: models.PropertyFilter{IDs: []int{1, 2}, Status: &withdrawn},
+			patch:  models.PropertyPatch{AgentID: &agentID},
+			dryRun: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE \(id IN \(\?,\?\) AND status = \?\) FOR UPDATE`).
+					WithArgs(1, 2, withdrawn).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectRollback()
+			},
+			expectedResult: &models.BulkUpdateResult{Matched: 2, DryRun: true},
+		},
+		{
+			name:   "update error rolls back",
+			filter: models.PropertyFilter{AgentID: &agentID},
+			patch:  models.PropertyPatch{Status: &withdrawn},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT COUNT").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec("UPDATE properties SET").
+					WillReturnError(errors.New("update failed"))
+				mock.ExpectRollback()
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewPropertyRepository(db)
+			result, err := repo.BulkUpdate(context.Background(), tt.filter, tt.patch, tt.dryRun, tt.revision)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			} else if *result != *tt.expectedResult {
+				t.Errorf("Expected %+v, got %+v", *tt.expectedResult, *result)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPropertyRepository_Search(t *testing.T) {
+	hasPool := true
+	minGarage := 2
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "location", "price", "description", "photos",
+		"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+		"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+		"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
+	})
+	mock.ExpectQuery(`SELECT (.+) FROM properties WHERE stale_at IS NOT NULL AND id IN \(SELECT property_id FROM property_amenities WHERE has_pool = \? AND garage_spaces >= \?\) ORDER BY created_at DESC`).
+		WithArgs(true, 2).
+		WillReturnRows(rows)
+
+	repo := NewPropertyRepository(db)
+	_, err = repo.Search(context.Background(), models.PropertySearch{
+		Stale:     true,
+		Amenities: models.AmenityFilter{HasPool: &hasPool, MinGarageSpaces: &minGarage},
+	})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_SearchCarryingCosts(t *testing.T) {
+	maxTax := 5000.0
+	minParking := 2
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM properties WHERE COALESCE\(annual_tax, 0\) <= \? AND parking_spaces >= \? ORDER BY created_at DESC`).
+		WithArgs(5000.0, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewPropertyRepository(db)
+	_, err = repo.Search(context.Background(), models.PropertySearch{MaxAnnualTax: &maxTax, MinParkingSpaces: &minParking})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_SearchListingDetails(t *testing.T) {
+	minPrice, maxPrice := 300000.0, 450000.0
+	bedrooms, bathrooms := 3, 2
+	minYear, maxYear := 1990, 2010
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	// The location is matched as a substring with LIKE wildcards escaped
+	mock.ExpectQuery(`SELECT (.+) FROM properties WHERE price >= \? AND price <= \? AND bedrooms >= \? AND bathrooms >= \? ` +
+		`AND property_type = \? AND location LIKE \? AND year_built >= \? AND year_built <= \? ORDER BY created_at DESC`).
+		WithArgs(300000.0, 450000.0, 3, 2, "Residential", `%Austin\_TX%`, 1990, 2010).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewPropertyRepository(db)
+	_, err = repo.Search(context.Background(), models.PropertySearch{MinPrice: &minPrice, MaxPrice: &maxPrice,
+		MinBedrooms: &bedrooms, MinBathrooms: &bathrooms, PropertyType: "Residential", Location: "Austin_TX",
+		MinYearBuilt: &minYear, MaxYearBuilt: &maxYear})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_SearchRentals(t *testing.T) {
+	maxRent := 2500.0
+	availableBy := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM properties WHERE listing_type = \? AND monthly_rent <= \? AND listing_type = \? ` +
+		`AND \(available_from IS NULL OR available_from <= \?\) ORDER BY created_at DESC`).
+		WithArgs(models.ListingTypeRent, 2500.0, models.ListingTypeRent, availableBy).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewPropertyRepository(db)
+	_, err = repo.Search(context.Background(), models.PropertySearch{MaxMonthlyRent: &maxRent, AvailableBy: availableBy})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_Facets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	for _, column := range []string{"category", "listing_type", "status", "zoning"} {
+		rows := sqlmock.NewRows([]string{column, "COUNT(*)"})
+		if column == "zoning" {
+			rows.AddRow("C-2", 3).AddRow("M-1", 1)
+		} else if column == "category" {
+			rows.AddRow("commercial", 4)
+		}
+		mock.ExpectQuery(`SELECT ` + column + `, COUNT\(\*\) FROM properties WHERE ` + column + ` IS NOT NULL ` +
+			`AND category = \? GROUP BY ` + column).
+			WithArgs(models.CategoryCommercial).
+			WillReturnRows(rows)
+	}
+
+	repo := NewPropertyRepository(db)
+	facets, err := repo.Facets(context.Background(), models.PropertySearch{Category: models.CategoryCommercial})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if facets.Category["commercial"] != 4 || facets.Zoning["C-2"] != 3 || len(facets.Status) != 0 {
+		t.Errorf("Unexpected facets %+v", facets)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_SearchPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM properties ORDER BY price DESC, id DESC LIMIT \? OFFSET \?`).
+		WithArgs(20, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewPropertyRepository(db)
+	_, err = repo.Search(context.Background(), models.PropertySearch{Sort: "-price", Limit: 20, Page: 3})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPropertyRepository_ApplyBatch(t *testing.T) {
+	newProperty := func() *models.Property {
+		return &models.Property{Name: "House", Location: "1 Main St", Price: 100000, Status: "active"}
+	}
+
+	t.Run("applies every mutation in one transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error creating mock database: %v", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO properties").WillReturnResult(sqlmock.NewResult(12, 1))
+		mock.ExpectExec("UPDATE properties SET").WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("DELETE FROM property_photos").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM properties WHERE id = \\? AND \\(\\? = 0 OR version = \\?\\)").
+			WithArgs(8, 2, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO tombstones").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO tombstones").WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		repo := NewPropertyRepository(db)
+		results, conflicts, err := repo.ApplyBatch(context.Background(), []models.PropertyMutation{
+			{Op: models.MutationCreate, ClientRef: "tmp-1", Property: newProperty()},
+			{Op: models.MutationUpdate, ID: 5, BaseVersion: 3, Property: newProperty()},
+			{Op: models.MutationDelete, ID: 8, BaseVersion: 2},
+		}, nil)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if len(conflicts) != 0 || len(results) != 3 {
+			t.Fatalf("Expected 3 results and no conflicts, got %+v / %+v", results, conflicts)
+		}
+		if results[0].ID != 12 || results[0].ClientRef != "tmp-1" || results[0].Version != 1 {
+			t.Errorf("Unexpected create result: %+v", results[0])
+		}
+		if results[1].ID != 5 || results[1].Version != 4 {
+			t.Errorf("Unexpected update result: %+v", results[1])
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+
+	t.Run("rolls back on a stale base version", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error creating mock database: %v", err)
+		}
+		defer db.Close()
+
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO properties").WillReturnResult(sqlmock.NewResult(12, 1))
+		mock.ExpectExec("UPDATE properties SET").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT (.+) FROM properties WHERE id = ?").
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "name", "location", "price", "description", "photos",
+				"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+				"square_feet", "lot_size", "year_built", "living_area_sqm", "lot_area_sqm",
+				"agent_id", "last_synced_at", "stale_at", "status", "created_at", "updated_at", "version", "organization_id",
+			}).AddRow(
+				5, "House 5", "Location 5", 500000.00,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, "active", now, now, 4, nil,
+			))
+		mock.ExpectExec("DELETE FROM properties").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT (.+) FROM properties WHERE id = ?").
+			WithArgs(9).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		repo := NewPropertyRepository(db)
+		results, conflicts, err := repo.ApplyBatch(context.Background(), []models.PropertyMutation{
+			{Op: models.MutationCreate, Property: newProperty()},
+			{Op: models.MutationUpdate, ID: 5, BaseVersion: 3, Property: newProperty()},
+			// Already deleted elsewhere, which is not a conflict
+			{Op: models.MutationDelete, ID: 9, BaseVersion: 1},
+		}, nil)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if results != nil {
+			t.Errorf("Expected no results for a rolled back batch, got %+v", results)
+		}
+		if len(conflicts) != 1 || conflicts[0].Index != 1 || conflicts[0].Current == nil || conflicts[0].Current.Version != 4 {
+			t.Errorf("Expected a conflict for mutation 1 at version 4, got %+v", conflicts)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+	t.Run("snapshots in the batch's transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error creating mock database: %v", err)
+		}
+		defer db.Close()
+
+		// The revision is rolled back with the failed update
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM properties WHERE \\(id = \\?\\) FOR UPDATE").
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(5, "House"))
+		mock.ExpectExec("INSERT INTO property_revisions").
+			WithArgs(5, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec("UPDATE properties SET").WillReturnError(errors.New("update failed"))
+		mock.ExpectRollback()
+
+		repo := NewPropertyRepository(db)
+		_, _, err = repo.ApplyBatch(context.Background(), []models.PropertyMutation{
+			{Op: models.MutationUpdate, ID: 5, BaseVersion: 3, Property: newProperty()},
+		}, &Revision{})
+		if err == nil {
+			t.Error("Expected the update error")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %v", err)
+		}
+	})
+}
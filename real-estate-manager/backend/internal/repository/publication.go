@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// ListingPublicationRepository stores the publish state of listings
+type ListingPublicationRepository interface {
+	Get(ctx context.Context, propertyID int) (*models.ListingPublication, error)
+	Save(ctx context.Context, publication *models.ListingPublication) error
+	ListPending(ctx context.Context, limit int) ([]models.ListingReview, error)
+}
+
+const publicationColumns = `property_id, state, submitted_by, submitted_at, reviewed_by, reviewed_at, review_comment`
+
+type listingPublicationRepository struct {
+	db *sql.DB
+}
+
+func NewListingPublicationRepository(db *sql.DB) ListingPublicationRepository {
+	return &listingPublicationRepository{db: db}
+}
+
+// Get returns a listing's publication, or (nil, nil) for a draft that was
+// never submitted
+func (r *listingPublicationRepository) Get(ctx context.Context, propertyID int) (*models.ListingPublication, error) {
+	query := `SELECT ` + publicationColumns + ` FROM listing_publications WHERE property_id = ?`
+	var publication models.ListingPublication
+	err := r.db.QueryRowContext(ctx, query, propertyID).Scan(publicationFields(&publication)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &publication, nil
+}
+
+func (r *listingPublicationRepository) Save(ctx context.Context, publication *models.ListingPublication) error {
+	query := `INSERT INTO listing_publications (` + publicationColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE state = VALUES(state), submitted_by = VALUES(submitted_by),
+		submitted_at = VALUES(submitted_at), reviewed_by = VALUES(reviewed_by), reviewed_at = VALUES(reviewed_at),
+		review_comment = VALUES(review_comment)`
+	_, err := r.db.ExecContext(ctx, query, publication.PropertyID, publication.State, publication.SubmittedBy,
+		publication.SubmittedAt, publication.ReviewedBy, publication.ReviewedAt, publication.ReviewComment)
+	return err
+}
+
+// ListPending returns the listings waiting for review with their
+// properties, longest waiting first
+func (r *listingPublicationRepository) ListPending(ctx context.Context, limit int) ([]models.ListingReview, error) {
+	query, args, err := sqlBuilder.Select(propertyColumns+", "+publicationColumns).
+		From("listing_publications").
+		Join("properties ON properties.id = listing_publications.property_id").
+		Where("state = ?", models.PublishPendingReview).
+		Where(tenantFilter(ctx)).
+		OrderBy("submitted_at", "property_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.ListingReview{}
+	for rows.Next() {
+		var review models.ListingReview
+		scanner := extraScanner{row: rows, extra: publicationFields(&review.ListingPublication)}
+		if err := scanProperty(scanner, &review.Property); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// publicationFields lists the destinations of publicationColumns
+func publicationFields(publication *models.ListingPublication) []any {
+	return []any{&publication.PropertyID, &publication.State, &publication.SubmittedBy, &publication.SubmittedAt,
+		&publication.ReviewedBy, &publication.ReviewedAt, &publication.ReviewComment}
+}
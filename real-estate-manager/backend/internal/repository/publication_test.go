@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestListingPublicationRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM listing_publications WHERE property_id = \?`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}))
+
+	repo := NewListingPublicationRepository(db)
+	publication, err := repo.Get(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if publication != nil {
+		t.Errorf("Expected no publication for a draft, got %+v", publication)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestListingPublicationRepository_ListPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	submittedAt := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "public_id", "name", "location", "price", "description", "photos",
+		"external_id", "mls_number", "property_type", "bedrooms", "bathrooms",
+		"square_feet", "lot_size", "year_built", "annual_tax", "parking_spaces", "parking_description",
+		"listing_type", "monthly_rent", "security_deposit", "lease_term_months", "available_from",
+		"category", "zoning", "cap_rate", "noi", "unit_count", "acreage", "utilities", "living_area_sqm", "lot_area_sqm", "agent_id", "last_synced_at", "stale_at", "expires_at", "status", "created_at", "updated_at", "version", "organization_id",
+		"property_id", "state", "submitted_by", "submitted_at", "reviewed_by", "reviewed_at", "review_comment",
+	}).AddRow(
+		3, "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", "Lake House", "Austin, TX", 500000.00,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "sale", nil, nil, nil, nil, "residential", nil, nil, nil, nil, nil, nil, nil, nil,
+		7, nil, nil, nil, "active", submittedAt, submittedAt, 2, nil,
+		3, "pending_review", 7, submittedAt, nil, nil, "",
+	)
+	mock.ExpectQuery(`FROM listing_publications JOIN properties ON properties.id = listing_publications.property_id ` +
+		`WHERE state = \? ORDER BY submitted_at, property_id LIMIT 50`).
+		WithArgs(models.PublishPendingReview).
+		WillReturnRows(rows)
+
+	repo := NewListingPublicationRepository(db)
+	reviews, err := repo.ListPending(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].Property.Name != "Lake House" || reviews[0].State != models.PublishPendingReview ||
+		reviews[0].SubmittedBy.Int32 != 7 {
+		t.Errorf("Unexpected reviews %+v", reviews)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
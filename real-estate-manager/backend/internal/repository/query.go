@@ -0,0 +1,14 @@
+package repository
+
+import sq "github.com/Masterminds/squirrel"
+
+// sqlBuilder builds the queries assembled from optional filters, so
+// conditions compose without hand-joined strings and placeholders follow
+// one format. MySQL uses "?".
+var sqlBuilder = sq.StatementBuilder.PlaceholderFormat(sq.Question)
+
+// selectProperties starts a query selecting propertyColumns, in the order
+// scanProperty expects
+func selectProperties() sq.SelectBuilder {
+	return sqlBuilder.Select(propertyColumns).From("properties")
+}
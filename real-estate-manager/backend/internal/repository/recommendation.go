@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"strings"
+)
+
+// RecommendationRepository stores the listings recommended to each user
+// and finds the users and listings they are computed from
+type RecommendationRepository interface {
+	ListUsers(ctx context.Context) ([]uint, error)
+	ListCandidates(ctx context.Context) ([]models.Property, error)
+	Replace(ctx context.Context, userID uint, recommendations []models.Recommendation) error
+	List(ctx context.Context, userID uint, limit int) ([]models.Recommendation, error)
+}
+
+type recommendationRepository struct {
+	db *sql.DB
+}
+
+func NewRecommendationRepository(db *sql.DB) RecommendationRepository {
+	return &recommendationRepository{db: db}
+}
+
+// ListUsers returns the users with favorites, recently viewed properties or
+// saved searches to base recommendations on, and those with
+// recommendations that may have to be cleared
+func (r *recommendationRepository) ListUsers(ctx context.Context) ([]uint, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT user_id FROM user_favorites
+		UNION SELECT user_id FROM recently_viewed_properties
+		UNION SELECT user_id FROM saved_searches
+		UNION SELECT user_id FROM user_recommendations
+		ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []uint{}
+	for rows.Next() {
+		var userID uint
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		users = append(users, userID)
+	}
+	return users, rows.Err()
+}
+
+// ListCandidates returns the listings that can be recommended: those still
+// on the market
+func (r *recommendationRepository) ListCandidates(ctx context.Context) ([]models.Property, error) {
+	query := `SELECT ` + propertyColumns + ` FROM properties WHERE status = ? ORDER BY id`
+	properties, err := queryProperties(ctx, r.db, query, models.PropertyStatusActive)
+	if properties == nil {
+		properties = []models.Property{}
+	}
+	return properties, err
+}
+
+// Replace swaps a user's recommendations in one transaction
+func (r *recommendationRepository) Replace(ctx context.Context, userID uint, recommendations []models.Recommendation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recommendations WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	if len(recommendations) > 0 {
+		placeholders := make([]string, len(recommendations))
+		args := make([]any, 0, len(recommendations)*5)
+		for i, recommendation := range recommendations {
+			placeholders[i] = "(?, ?, ?, ?, ?)"
+			args = append(args, userID, recommendation.PropertyID, recommendation.Score, recommendation.Reasons, recommendation.ComputedAt)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_recommendations (user_id, property_id, score, reasons, computed_at)
+			VALUES `+strings.Join(placeholders, ", "), args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// List returns a user's recommendations with their listings, best first.
+// Listings that left the market since they were computed are skipped.
+func (r *recommendationRepository) List(ctx context.Context, userID uint, limit int) ([]models.Recommendation, error) {
+	query := `SELECT ` + propertyColumns + `, rec.property_id, rec.score, rec.reasons, rec.computed_at
+		FROM user_recommendations rec JOIN properties ON properties.id = rec.property_id
+		WHERE rec.user_id = ? AND properties.status = ?
+		ORDER BY rec.score DESC, rec.property_id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, userID, models.PropertyStatusActive, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recommendations := []models.Recommendation{}
+	for rows.Next() {
+		var recommendation models.Recommendation
+		recommendation.Property = &models.Property{}
+		if err := scanProperty(extraScanner{row: rows, extra: []any{&recommendation.PropertyID, &recommendation.Score,
+			&recommendation.Reasons, &recommendation.ComputedAt}}, recommendation.Property); err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRecommendationRepository_Replace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	computed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	recommendations := []models.Recommendation{
+		{PropertyID: 3, Score: 0.9, Reasons: models.StringList{"Similar to your favorite"}, ComputedAt: computed},
+		{PropertyID: 5, Score: 0.4, Reasons: models.StringList{}, ComputedAt: computed},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM user_recommendations").WithArgs(uint(4)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO user_recommendations").
+		WithArgs(uint(4), 3, 0.9, []byte(`["Similar to your favorite"]`), computed, uint(4), 5, 0.4, []byte(`[]`), computed).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewRecommendationRepository(db)
+	if err := repo.Replace(context.Background(), 4, recommendations); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecommendationRepository_ReplaceWithNone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM user_recommendations").WithArgs(uint(4)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	repo := NewRecommendationRepository(db)
+	if err := repo.Replace(context.Background(), 4, nil); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
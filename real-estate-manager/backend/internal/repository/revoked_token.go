@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"time"
+)
+
+// RevokedTokenRepository stores the tokens revoked before they expire
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *models.RevokedToken) error
+	ListActive(ctx context.Context, now time.Time) ([]models.RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+	RevokeUser(ctx context.Context, userID uint, at time.Time) error
+	ListRevokedUsers(ctx context.Context) (map[uint]time.Time, error)
+}
+
+type revokedTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRevokedTokenRepository(db *sql.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+// Create records a revocation. Revoking a token twice keeps the first
+// revocation.
+func (r *revokedTokenRepository) Create(ctx context.Context, token *models.RevokedToken) error {
+	query := `INSERT INTO revoked_tokens (token_hash, user_id, expires_at, revoked_by) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE token_hash = token_hash`
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.UserID, token.ExpiresAt, token.RevokedBy)
+	return err
+}
+
+// ListActive returns the revoked tokens that have not expired yet
+func (r *revokedTokenRepository) ListActive(ctx context.Context, now time.Time) ([]models.RevokedToken, error) {
+	query := `SELECT token_hash, user_id, expires_at, revoked_by, revoked_at FROM revoked_tokens WHERE expires_at > ?`
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []models.RevokedToken{}
+	for rows.Next() {
+		var token models.RevokedToken
+		if err := rows.Scan(&token.TokenHash, &token.UserID, &token.ExpiresAt, &token.RevokedBy, &token.RevokedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// DeleteExpired removes revocations of tokens that expired, which would be
+// refused anyway
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RevokeUser refuses every token issued to the user up to at, by storing
+// the time in users.tokens_valid_after
+func (r *revokedTokenRepository) RevokeUser(ctx context.Context, userID uint, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET tokens_valid_after = ? WHERE id = ?`, at, userID)
+	return err
+}
+
+// ListRevokedUsers returns the time up to which each user's tokens are
+// refused, for the users who have one
+func (r *revokedTokenRepository) ListRevokedUsers(ctx context.Context) (map[uint]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, tokens_valid_after FROM users WHERE tokens_valid_after IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := map[uint]time.Time{}
+	for rows.Next() {
+		var userID uint
+		var validAfter time.Time
+		if err := rows.Scan(&userID, &validAfter); err != nil {
+			return nil, err
+		}
+		users[userID] = validAfter
+	}
+	return users, rows.Err()
+}
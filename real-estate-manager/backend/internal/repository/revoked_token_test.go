@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRevokedTokenRepository(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	token := &models.RevokedToken{TokenHash: "hash", ExpiresAt: now.Add(time.Hour)}
+	token.UserID.Int32, token.UserID.Valid = 7, true
+	mock.ExpectExec("INSERT INTO revoked_tokens (.+) ON DUPLICATE KEY UPDATE").
+		WithArgs("hash", token.UserID, now.Add(time.Hour), token.RevokedBy).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM revoked_tokens WHERE expires_at > ?").
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"token_hash", "user_id", "expires_at", "revoked_by", "revoked_at"}).
+			AddRow("hash", 7, now.Add(time.Hour), nil, now))
+	mock.ExpectExec("DELETE FROM revoked_tokens WHERE expires_at <= ?").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE users SET tokens_valid_after = \\? WHERE id = \\?").
+		WithArgs(now, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, tokens_valid_after FROM users WHERE tokens_valid_after IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tokens_valid_after"}).AddRow(7, now))
+
+	repo := NewRevokedTokenRepository(db)
+	if err := repo.Create(context.Background(), token); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	tokens, err := repo.ListActive(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].TokenHash != "hash" || tokens[0].UserID.Int32 != 7 || tokens[0].RevokedBy.Valid {
+		t.Errorf("Unexpected tokens: %+v", tokens)
+	}
+	if count, err := repo.DeleteExpired(context.Background(), now); err != nil || count != 3 {
+		t.Errorf("Unexpected delete result %d, %v", count, err)
+	}
+	if err := repo.RevokeUser(context.Background(), 7, now); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	users, err := repo.ListRevokedUsers(context.Background())
+	if err != nil || len(users) != 1 || !users[7].Equal(now) {
+		t.Errorf("Unexpected revoked users %v, %v", users, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+type RolePermissionRepository interface {
+	GetAll(ctx context.Context) ([]models.RolePermissions, error)
+	Upsert(ctx context.Context, role *models.RolePermissions) error
+	Delete(ctx context.Context, role string, organizationID int) error
+}
+
+type rolePermissionRepository struct {
+	db *sql.DB
+}
+
+func NewRolePermissionRepository(db *sql.DB) RolePermissionRepository {
+	return &rolePermissionRepository{db: db}
+}
+
+func (r *rolePermissionRepository) GetAll(ctx context.Context) ([]models.RolePermissions, error) {
+	query := `SELECT role, organization_id, permissions, updated_by, updated_at FROM role_permissions 
+		ORDER BY organization_id, role`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []models.RolePermissions
+	for rows.Next() {
+		var role models.RolePermissions
+		if err := rows.Scan(&role.Role, &role.OrganizationID, &role.Permissions, &role.UpdatedBy, &role.UpdatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *rolePermissionRepository) Upsert(ctx context.Context, role *models.RolePermissions) error {
+	query := `INSERT INTO role_permissions (role, organization_id, permissions, updated_by) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE permissions = VALUES(permissions), updated_by = VALUES(updated_by), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, role.Role, role.OrganizationID, role.Permissions, role.UpdatedBy)
+	return err
+}
+
+func (r *rolePermissionRepository) Delete(ctx context.Context, role string, organizationID int) error {
+	query := `DELETE FROM role_permissions WHERE role = ? AND organization_id = ?`
+	_, err := r.db.ExecContext(ctx, query, role, organizationID)
+	return err
+}
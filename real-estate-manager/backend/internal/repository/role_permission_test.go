@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRolePermissionRepository_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"role", "organization_id", "permissions", "updated_by", "updated_at"}).
+		AddRow("viewer", 0, []byte(`["properties:read"]`), nil, now).
+		AddRow("office-manager", 3, []byte(`["properties:*","jobs:run"]`), "admin", now)
+	mock.ExpectQuery("SELECT role, organization_id, permissions, updated_by, updated_at FROM role_permissions").
+		WillReturnRows(rows)
+
+	repo := NewRolePermissionRepository(db)
+	roles, err := repo.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(roles) != 2 {
+		t.Fatalf("Expected 2 roles, got %d", len(roles))
+	}
+	if roles[1].OrganizationID != 3 || len(roles[1].Permissions) != 2 || roles[1].UpdatedBy.String != "admin" {
+		t.Errorf("Unexpected role: %+v", roles[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *models.SavedSearch) error
+	List(ctx context.Context, userID uint) ([]models.SavedSearch, error)
+	Count(ctx context.Context, userID uint) (int, error)
+	Delete(ctx context.Context, userID uint, id int) (bool, error)
+}
+
+type savedSearchRepository struct {
+	db *sql.DB
+}
+
+func NewSavedSearchRepository(db *sql.DB) SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) Create(ctx context.Context, search *models.SavedSearch) error {
+	result, err := r.db.ExecContext(ctx, `INSERT INTO saved_searches (user_id, name, criteria) VALUES (?, ?, ?)`,
+		search.UserID, search.Name, search.Criteria)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	search.ID = int(id)
+	return nil
+}
+
+// List returns a user's saved searches, oldest first
+func (r *savedSearchRepository) List(ctx context.Context, userID uint) ([]models.SavedSearch, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, user_id, name, criteria, created_at FROM saved_searches
+		WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		var search models.SavedSearch
+		if err := rows.Scan(&search.ID, &search.UserID, &search.Name, &search.Criteria, &search.CreatedAt); err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+	return searches, rows.Err()
+}
+
+func (r *savedSearchRepository) Count(ctx context.Context, userID uint) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM saved_searches WHERE user_id = ?`, userID).Scan(&count)
+	return count, err
+}
+
+// Delete removes one of a user's saved searches; other users' searches
+// are reported as missing
+func (r *savedSearchRepository) Delete(ctx context.Context, userID uint, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
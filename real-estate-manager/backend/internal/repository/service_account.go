@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, account *models.ServiceAccount, passwordHash string) error
+	GetByUserID(ctx context.Context, userID int) (*models.ServiceAccount, error)
+	List(ctx context.Context) ([]models.ServiceAccount, error)
+}
+
+type serviceAccountRepository struct {
+	db *sql.DB
+}
+
+func NewServiceAccountRepository(db *sql.DB) ServiceAccountRepository {
+	return &serviceAccountRepository{db: db}
+}
+
+// Create inserts the account's user row and its service_accounts entry in
+// one transaction. passwordHash should be unguessable so the account cannot
+// log in with a password.
+func (r *serviceAccountRepository) Create(ctx context.Context, account *models.ServiceAccount, passwordHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO users (public_id, username, password, email, role, organization_id, created_at, updated_at) 
+		VALUES (?, ?, ?, '', ?, ?, NOW(), NOW())`, uuid.NewString(), account.Username, passwordHash, account.Role, account.OrganizationID)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO service_accounts (user_id, description, created_by) VALUES (?, ?, ?)`,
+		id, account.Description, account.CreatedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	account.UserID = int(id)
+	return nil
+}
+
+const serviceAccountColumns = `s.user_id, u.username, u.role, u.organization_id, s.description, s.created_by, s.created_at`
+
+func (r *serviceAccountRepository) GetByUserID(ctx context.Context, userID int) (*models.ServiceAccount, error) {
+	query := `SELECT ` + serviceAccountColumns + ` FROM service_accounts s JOIN users u ON u.id = s.user_id 
+		WHERE s.user_id = ?`
+
+	var account models.ServiceAccount
+	if err := scanServiceAccount(r.db.QueryRowContext(ctx, query, userID), &account); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *serviceAccountRepository) List(ctx context.Context) ([]models.ServiceAccount, error) {
+	query := `SELECT ` + serviceAccountColumns + ` FROM service_accounts s JOIN users u ON u.id = s.user_id 
+		ORDER BY u.username`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.ServiceAccount
+	for rows.Next() {
+		var account models.ServiceAccount
+		if err := scanServiceAccount(rows, &account); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func scanServiceAccount(row rowScanner, account *models.ServiceAccount) error {
+	return row.Scan(&account.UserID, &account.Username, &account.Role, &account.OrganizationID,
+		&account.Description, &account.CreatedBy, &account.CreatedAt)
+}
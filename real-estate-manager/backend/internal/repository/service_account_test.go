@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestServiceAccountRepository_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock.Sqlmock)
+		expectError bool
+	}{
+		{
+			name: "creates user and service account",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO users").
+					WithArgs(sqlmock.AnyArg(), "nightly-export", "hash", "user", nil).
+					WillReturnResult(sqlmock.NewResult(12, 1))
+				mock.ExpectExec("INSERT INTO service_accounts").
+					WithArgs(int64(12), "Nightly CSV export", "admin").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "rolls back when the service account insert fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(12, 1))
+				mock.ExpectExec("INSERT INTO service_accounts").WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			account := &models.ServiceAccount{Username: "nightly-export", Role: "user",
+				Description: "Nightly CSV export", CreatedBy: "admin"}
+			repo := NewServiceAccountRepository(db)
+			err = repo.Create(context.Background(), account, "hash")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				if account.UserID != 12 {
+					t.Errorf("Expected user ID 12, got %d", account.UserID)
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestServiceAccountRepository_GetByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"user_id", "username", "role", "organization_id", "description", "created_by", "created_at"}
+	mock.ExpectQuery("SELECT (.+) FROM service_accounts s JOIN users u ON u.id = s.user_id WHERE s.user_id = ?").
+		WithArgs(12).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(12, "nightly-export", "user", 3, "", "admin", time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM service_accounts").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	repo := NewServiceAccountRepository(db)
+	account, err := repo.GetByUserID(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if account == nil || account.Username != "nightly-export" || account.OrganizationID.Int32 != 3 {
+		t.Errorf("Unexpected account: %+v", account)
+	}
+
+	account, err = repo.GetByUserID(context.Background(), 7)
+	if err != nil || account != nil {
+		t.Errorf("Expected (nil, nil) for a regular user, got (%+v, %v)", account, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
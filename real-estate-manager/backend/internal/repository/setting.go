@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+type SettingRepository interface {
+	GetAll(ctx context.Context) ([]models.Setting, error)
+	Upsert(ctx context.Context, setting *models.Setting) error
+}
+
+type settingRepository struct {
+	db *sql.DB
+}
+
+func NewSettingRepository(db *sql.DB) SettingRepository {
+	return &settingRepository{db: db}
+}
+
+func (r *settingRepository) GetAll(ctx context.Context) ([]models.Setting, error) {
+	query := `SELECT name, value, updated_by, updated_at FROM settings ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []models.Setting
+	for rows.Next() {
+		var setting models.Setting
+		if err := rows.Scan(&setting.Name, &setting.Value, &setting.UpdatedBy, &setting.UpdatedAt); err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+	return settings, rows.Err()
+}
+
+func (r *settingRepository) Upsert(ctx context.Context, setting *models.Setting) error {
+	query := `INSERT INTO settings (name, value, updated_by) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE value = VALUES(value), updated_by = VALUES(updated_by), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, setting.Name, setting.Value, setting.UpdatedBy)
+	return err
+}
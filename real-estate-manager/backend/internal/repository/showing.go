@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"time"
+)
+
+// ShowingRepository stores showings and open houses
+type ShowingRepository interface {
+	Create(ctx context.Context, showing *models.Showing) error
+	GetByID(ctx context.Context, id int) (*models.Showing, error)
+	ListByProperty(ctx context.Context, propertyID int) ([]models.Showing, error)
+	ListConfirmedBetween(ctx context.Context, agentID uint, start, end time.Time) ([]models.Showing, error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+}
+
+type showingRepository struct {
+	db *sql.DB
+}
+
+func NewShowingRepository(db *sql.DB) ShowingRepository {
+	return &showingRepository{db: db}
+}
+
+const showingColumns = `id, property_id, agent_id, kind, starts_at, ends_at, status, contact_name, contact_email, notes, created_at`
+
+func (r *showingRepository) Create(ctx context.Context, showing *models.Showing) error {
+	query := `INSERT INTO showings (property_id, agent_id, kind, starts_at, ends_at, status, contact_name, contact_email, notes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, showing.PropertyID, showing.AgentID, showing.Kind, showing.StartsAt,
+		showing.EndsAt, showing.Status, showing.ContactName, showing.ContactEmail, showing.Notes)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	showing.ID = int(id)
+	return nil
+}
+
+func (r *showingRepository) GetByID(ctx context.Context, id int) (*models.Showing, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+showingColumns+` FROM showings WHERE id = ?`, id)
+	var showing models.Showing
+	if err := scanShowing(row, &showing); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &showing, nil
+}
+
+// ListByProperty returns a listing's showings in start order
+func (r *showingRepository) ListByProperty(ctx context.Context, propertyID int) ([]models.Showing, error) {
+	return r.queryShowings(ctx, `SELECT `+showingColumns+` FROM showings WHERE property_id = ? ORDER BY starts_at, id`, propertyID)
+}
+
+// ListConfirmedBetween returns an agent's confirmed showings overlapping
+// the period from start to end
+func (r *showingRepository) ListConfirmedBetween(ctx context.Context, agentID uint, start, end time.Time) ([]models.Showing, error) {
+	query := `SELECT ` + showingColumns + ` FROM showings
+		WHERE agent_id = ? AND status = ? AND starts_at < ? AND ends_at > ? ORDER BY starts_at, id`
+	return r.queryShowings(ctx, query, agentID, models.ShowingConfirmed, end, start)
+}
+
+func (r *showingRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE showings SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+func (r *showingRepository) queryShowings(ctx context.Context, query string, args ...any) ([]models.Showing, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	showings := []models.Showing{}
+	for rows.Next() {
+		var showing models.Showing
+		if err := scanShowing(rows, &showing); err != nil {
+			return nil, err
+		}
+		showings = append(showings, showing)
+	}
+	return showings, rows.Err()
+}
+
+func scanShowing(row rowScanner, showing *models.Showing) error {
+	return row.Scan(&showing.ID, &showing.PropertyID, &showing.AgentID, &showing.Kind, &showing.StartsAt, &showing.EndsAt,
+		&showing.Status, &showing.ContactName, &showing.ContactEmail, &showing.Notes, &showing.CreatedAt)
+}
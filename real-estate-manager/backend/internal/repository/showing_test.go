@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestShowingRepository_ListConfirmedBetween(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	start := time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	rows := sqlmock.NewRows([]string{"id", "property_id", "agent_id", "kind", "starts_at", "ends_at", "status", "contact_name", "contact_email", "notes", "created_at"}).
+		AddRow(5, 12, 4, models.ShowingPrivate, start.Add(-30*time.Minute), start.Add(30*time.Minute), models.ShowingConfirmed, "Jane", "", "", start)
+	// A showing overlaps when it starts before the period ends and ends
+	// after it starts
+	mock.ExpectQuery("SELECT .* FROM showings\\s+WHERE agent_id = \\? AND status = \\? AND starts_at < \\? AND ends_at > \\?").
+		WithArgs(uint(4), models.ShowingConfirmed, end, start).
+		WillReturnRows(rows)
+
+	repo := NewShowingRepository(db)
+	showings, err := repo.ListConfirmedBetween(context.Background(), 4, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(showings) != 1 || showings[0].ID != 5 || showings[0].AgentID != 4 {
+		t.Errorf("Unexpected showings %+v", showings)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+// SignatureRepository stores the documents sent out for e-signature
+type SignatureRepository interface {
+	Create(ctx context.Context, request *models.SignatureRequest) error
+	GetByID(ctx context.Context, id int) (*models.SignatureRequest, error)
+	GetByEnvelope(ctx context.Context, provider, envelopeID string) (*models.SignatureRequest, error)
+	ListByProperty(ctx context.Context, propertyID int) ([]models.SignatureRequest, error)
+	UpdateStatus(ctx context.Context, request *models.SignatureRequest) error
+}
+
+type signatureRepository struct {
+	db *sql.DB
+}
+
+func NewSignatureRepository(db *sql.DB) SignatureRepository {
+	return &signatureRepository{db: db}
+}
+
+const signatureColumns = `id, property_id, offer_id, kind, upload_id, provider, envelope_id, subject, message, signers,
+	status, sent_by, completed_at, created_at, updated_at`
+
+func (r *signatureRepository) Create(ctx context.Context, request *models.SignatureRequest) error {
+	query := `INSERT INTO signature_requests (property_id, offer_id, kind, upload_id, provider, envelope_id, subject, message,
+		signers, status, sent_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, request.PropertyID, request.OfferID, request.Kind, request.UploadID,
+		request.Provider, request.EnvelopeID, request.Subject, request.Message, request.Signers, request.Status, request.SentBy)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	request.ID = int(id)
+	return nil
+}
+
+func (r *signatureRepository) GetByID(ctx context.Context, id int) (*models.SignatureRequest, error) {
+	return r.getOne(ctx, `SELECT `+signatureColumns+` FROM signature_requests WHERE id = ?`, id)
+}
+
+// GetByEnvelope finds the request a provider's callback is about
+func (r *signatureRepository) GetByEnvelope(ctx context.Context, provider, envelopeID string) (*models.SignatureRequest, error) {
+	return r.getOne(ctx, `SELECT `+signatureColumns+` FROM signature_requests WHERE provider = ? AND envelope_id = ?`,
+		provider, envelopeID)
+}
+
+// ListByProperty returns a property's signature requests, newest first
+func (r *signatureRepository) ListByProperty(ctx context.Context, propertyID int) ([]models.SignatureRequest, error) {
+	return r.query(ctx, `SELECT `+signatureColumns+` FROM signature_requests WHERE property_id = ?
+		ORDER BY created_at DESC, id DESC`, propertyID)
+}
+
+// UpdateStatus saves a request's status and completion time
+func (r *signatureRepository) UpdateStatus(ctx context.Context, request *models.SignatureRequest) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE signature_requests SET status = ?, completed_at = ? WHERE id = ?`,
+		request.Status, request.CompletedAt, request.ID)
+	return err
+}
+
+func (r *signatureRepository) getOne(ctx context.Context, query string, args ...any) (*models.SignatureRequest, error) {
+	requests, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	return &requests[0], nil
+}
+
+func (r *signatureRepository) query(ctx context.Context, query string, args ...any) ([]models.SignatureRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []models.SignatureRequest{}
+	for rows.Next() {
+		var request models.SignatureRequest
+		if err := rows.Scan(&request.ID, &request.PropertyID, &request.OfferID, &request.Kind, &request.UploadID,
+			&request.Provider, &request.EnvelopeID, &request.Subject, &request.Message, &request.Signers, &request.Status,
+			&request.SentBy, &request.CompletedAt, &request.CreatedAt, &request.UpdatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
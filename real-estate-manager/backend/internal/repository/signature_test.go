@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSignatureRepository_GetByEnvelopeAndUpdate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM signature_requests WHERE provider = \\? AND envelope_id = \\?").
+		WithArgs("docusign", "env-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "property_id", "offer_id", "kind", "upload_id", "provider", "envelope_id",
+			"subject", "message", "signers", "status", "sent_by", "completed_at", "created_at", "updated_at"}).
+			AddRow(3, 12, 5, "offer", "doc-1", "docusign", "env-1", "Please sign: offer.pdf", "",
+				`[{"name": "Jane Roe", "email": "jane@example.com"}]`, "sent", 4, nil, now, now))
+	mock.ExpectExec("UPDATE signature_requests SET status = \\?, completed_at = \\? WHERE id = \\?").
+		WithArgs("completed", sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewSignatureRepository(db)
+	request, err := repo.GetByEnvelope(context.Background(), "docusign", "env-1")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if request == nil || request.OfferID.Int32 != 5 || len(request.Signers) != 1 || request.Signers[0].Email != "jane@example.com" {
+		t.Fatalf("Unexpected request: %+v", request)
+	}
+
+	request.Status = models.SignatureCompleted
+	request.CompletedAt.Time, request.CompletedAt.Valid = now, true
+	if err := repo.UpdateStatus(context.Background(), request); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
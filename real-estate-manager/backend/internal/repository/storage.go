@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+)
+
+type StorageRepository interface {
+	Record(ctx context.Context, file *models.StoredFile) error
+	UsageByUser(ctx context.Context, userID int) (int64, error)
+	UsageByOrganization(ctx context.Context, organizationID int) (int64, error)
+	UsageSummary(ctx context.Context) ([]models.StorageUsage, error)
+	GetOrganization(ctx context.Context, id int) (*models.Organization, error)
+}
+
+type storageRepository struct {
+	db *sql.DB
+}
+
+func NewStorageRepository(db *sql.DB) StorageRepository {
+	return &storageRepository{db: db}
+}
+
+func (r *storageRepository) Record(ctx context.Context, file *models.StoredFile) error {
+	query := `INSERT INTO stored_files (user_id, organization_id, property_id, kind, path, size_bytes) 
+		VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, file.UserID, file.OrganizationID, file.PropertyID,
+		file.Kind, file.Path, file.SizeBytes)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	file.ID = int(id)
+	return nil
+}
+
+func (r *storageRepository) UsageByUser(ctx context.Context, userID int) (int64, error) {
+	var used int64
+	query := `SELECT COALESCE(SUM(size_bytes), 0) FROM stored_files WHERE user_id = ?`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&used)
+	return used, err
+}
+
+func (r *storageRepository) UsageByOrganization(ctx context.Context, organizationID int) (int64, error) {
+	var used int64
+	query := `SELECT COALESCE(SUM(size_bytes), 0) FROM stored_files WHERE organization_id = ?`
+	err := r.db.QueryRowContext(ctx, query, organizationID).Scan(&used)
+	return used, err
+}
+
+// UsageSummary returns usage for every organization and user that has
+// stored files, largest first. QuotaBytes holds an organization's own
+// override, if any; callers fill in defaults.
+func (r *storageRepository) UsageSummary(ctx context.Context) ([]models.StorageUsage, error) {
+	query := `SELECT 'organization', o.id, o.name, COUNT(f.id), COALESCE(SUM(f.size_bytes), 0), 
+			COALESCE(o.storage_quota_mb, 0) * 1048576 
+		FROM stored_files f JOIN organizations o ON o.id = f.organization_id 
+		GROUP BY o.id, o.name, o.storage_quota_mb 
+		UNION ALL 
+		SELECT 'user', u.id, u.username, COUNT(f.id), COALESCE(SUM(f.size_bytes), 0), 0 
+		FROM stored_files f JOIN users u ON u.id = f.user_id 
+		GROUP BY u.id, u.username 
+		ORDER BY 5 DESC`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []models.StorageUsage
+	for rows.Next() {
+		var u models.StorageUsage
+		if err := rows.Scan(&u.OwnerType, &u.OwnerID, &u.Name, &u.Files, &u.UsedBytes, &u.QuotaBytes); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+func (r *storageRepository) GetOrganization(ctx context.Context, id int) (*models.Organization, error) {
+	query := `SELECT id, name, storage_quota_mb, created_at FROM organizations WHERE id = ?`
+
+	var org models.Organization
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&org.ID, &org.Name, &org.StorageQuotaMB, &org.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &org, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestStorageRepository_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	file := &models.StoredFile{
+		UserID:     models.NullInt32{NullInt32: sql.NullInt32{Int32: 7, Valid: true}},
+		PropertyID: models.NullInt32{NullInt32: sql.NullInt32{Int32: 3, Valid: true}},
+		Kind:       models.FileKindPhoto,
+		Path:       "/uploads/images/photo.jpg",
+		SizeBytes:  2048,
+	}
+
+	mock.ExpectExec("INSERT INTO stored_files").
+		WithArgs(file.UserID, file.OrganizationID, file.PropertyID, file.Kind, file.Path, file.SizeBytes).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	repo := NewStorageRepository(db)
+	if err := repo.Record(context.Background(), file); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if file.ID != 12 {
+		t.Errorf("Expected ID 12, got %d", file.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStorageRepository_UsageSummary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"owner_type", "owner_id", "name", "files", "used_bytes", "quota_bytes"}).
+		AddRow("organization", 1, "Acme Realty", 4, 8192, 1048576).
+		AddRow("user", 7, "alice", 2, 4096, 0)
+	mock.ExpectQuery("SELECT 'organization', (.+) UNION ALL SELECT 'user', (.+)").WillReturnRows(rows)
+
+	repo := NewStorageRepository(db)
+	usage, err := repo.UsageSummary(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(usage))
+	}
+	if usage[0].OwnerType != "organization" || usage[0].QuotaBytes != 1048576 {
+		t.Errorf("Unexpected organization usage: %+v", usage[0])
+	}
+	if usage[1].Name != "alice" || usage[1].UsedBytes != 4096 {
+		t.Errorf("Unexpected user usage: %+v", usage[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
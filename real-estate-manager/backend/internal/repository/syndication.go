@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+)
+
+// SyndicationRepository stores the per-portal publish state of listings
+type SyndicationRepository interface {
+	List(ctx context.Context, propertyID int) ([]models.SyndicationListing, error)
+	Save(ctx context.Context, listing *models.SyndicationListing) error
+	DeleteAll(ctx context.Context, propertyID int) error
+	ListDue(ctx context.Context, limit int) ([]int, error)
+}
+
+type syndicationRepository struct {
+	db *sql.DB
+}
+
+func NewSyndicationRepository(db *sql.DB) SyndicationRepository {
+	return &syndicationRepository{db: db}
+}
+
+func (r *syndicationRepository) List(ctx context.Context, propertyID int) ([]models.SyndicationListing, error) {
+	query := `SELECT property_id, portal, listing_id, enabled, status, last_error, synced_at, updated_at
+		FROM syndication_listings WHERE property_id = ? ORDER BY portal`
+	rows, err := r.db.QueryContext(ctx, query, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	listings := []models.SyndicationListing{}
+	for rows.Next() {
+		var listing models.SyndicationListing
+		var syncedAt sql.NullTime
+		if err := rows.Scan(&listing.PropertyID, &listing.Portal, &listing.ListingID, &listing.Enabled, &listing.Status, &listing.LastError,
+			&syncedAt, &listing.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if syncedAt.Valid {
+			listing.SyncedAt = &syncedAt.Time
+		}
+		listings = append(listings, listing)
+	}
+	return listings, rows.Err()
+}
+
+func (r *syndicationRepository) Save(ctx context.Context, listing *models.SyndicationListing) error {
+	lastError := listing.LastError
+	if len(lastError) > 512 {
+		lastError = lastError[:512]
+	}
+	query := `INSERT INTO syndication_listings (property_id, portal, listing_id, enabled, status, last_error, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE listing_id = VALUES(listing_id), enabled = VALUES(enabled), status = VALUES(status), last_error = VALUES(last_error),
+		synced_at = VALUES(synced_at)`
+	_, err := r.db.ExecContext(ctx, query, listing.PropertyID, listing.Portal, listing.ListingID, listing.Enabled, listing.Status,
+		lastError, listing.SyncedAt)
+	return err
+}
+
+// DeleteAll forgets a property's syndication state, once it has been
+// removed from every portal
+func (r *syndicationRepository) DeleteAll(ctx context.Context, propertyID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM syndication_listings WHERE property_id = ?`, propertyID)
+	return err
+}
+
+// ListDue returns properties whose portal state is out of date: a push or
+// removal that failed or has not happened yet, or an enabled portal whose
+// property changed or was deleted since the last sync
+func (r *syndicationRepository) ListDue(ctx context.Context, limit int) ([]int, error) {
+	query := `SELECT DISTINCT s.property_id FROM syndication_listings s
+		LEFT JOIN properties p ON p.id = s.property_id
+		WHERE s.status IN (?, ?)
+		OR (s.enabled AND (s.synced_at IS NULL OR p.id IS NULL OR p.updated_at > s.synced_at))
+		ORDER BY s.property_id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, models.SyndicationPending, models.SyndicationFailed, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSyndicationRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	syncedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO syndication_listings \\(property_id, portal, listing_id, enabled, status, last_error, synced_at\\)").
+		WithArgs(12, "zillow", "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", true, models.SyndicationFailed, strings.Repeat("x", 512), &syncedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewSyndicationRepository(db)
+	listing := &models.SyndicationListing{PropertyID: 12, Portal: "zillow", ListingID: "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", Enabled: true, Status: models.SyndicationFailed,
+		LastError: strings.Repeat("x", 600), SyncedAt: &syncedAt}
+	if err := repo.Save(context.Background(), listing); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSyndicationRepository_ListDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT s.property_id FROM syndication_listings s LEFT JOIN properties p").
+		WithArgs(models.SyndicationPending, models.SyndicationFailed, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}).AddRow(3).AddRow(12))
+
+	repo := NewSyndicationRepository(db)
+	ids, err := repo.ListDue(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 12 {
+		t.Errorf("Unexpected IDs %v", ids)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type tenantKey struct{}
+
+// Tenant limits property queries to the properties one organization may
+// see and change: its own and shared ones, such as listings imported by an
+// admin. OrganizationID 0 is a user outside any organization, who only
+// sees shared properties.
+type Tenant struct {
+	OrganizationID int
+}
+
+// WithTenant returns a context whose property queries are limited to
+// tenant. Queries without a tenant, such as those of admins and background
+// jobs, see every property.
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored by WithTenant
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
+
+// tenantFilter returns the condition limiting properties to the tenant in
+// ctx, or nil without a tenant
+func tenantFilter(ctx context.Context) sq.Sqlizer {
+	return tenantFilterOn(ctx, "organization_id")
+}
+
+// tenantFilterOn is tenantFilter on a qualified organization column, for
+// queries joining properties to other tables
+func tenantFilterOn(ctx context.Context, column string) sq.Sqlizer {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if tenant.OrganizationID == 0 {
+		return sq.Eq{column: nil}
+	}
+	return sq.Or{sq.Eq{column: nil}, sq.Eq{column: tenant.OrganizationID}}
+}
+
+// ownerFilter returns the condition limiting properties to those owned by
+// the tenant in ctx: its organization's, or shared ones outside an
+// organization and without a tenant
+func ownerFilter(ctx context.Context) sq.Sqlizer {
+	if tenant, ok := TenantFromContext(ctx); ok && tenant.OrganizationID != 0 {
+		return sq.Eq{"organization_id": tenant.OrganizationID}
+	}
+	return sq.Eq{"organization_id": nil}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"time"
+)
+
+type UploadRepository interface {
+	Create(ctx context.Context, upload *models.Upload) error
+	GetByID(ctx context.Context, id string) (*models.Upload, error)
+	MarkCompleted(ctx context.Context, id string, size int64, at time.Time) error
+}
+
+type uploadRepository struct {
+	db *sql.DB
+}
+
+func NewUploadRepository(db *sql.DB) UploadRepository {
+	return &uploadRepository{db: db}
+}
+
+func (r *uploadRepository) Create(ctx context.Context, upload *models.Upload) error {
+	query := `INSERT INTO uploads (id, user_id, organization_id, property_id, kind, object_key, filename, 
+		content_type, caption, size_bytes, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, upload.ID, upload.UserID, upload.OrganizationID, upload.PropertyID,
+		upload.Kind, upload.ObjectKey, upload.Filename, upload.ContentType, upload.Caption, upload.SizeBytes, upload.Status)
+	return err
+}
+
+func (r *uploadRepository) GetByID(ctx context.Context, id string) (*models.Upload, error) {
+	query := `SELECT id, user_id, organization_id, property_id, kind, object_key, filename, content_type, 
+		caption, size_bytes, status, created_at, completed_at FROM uploads WHERE id = ?`
+
+	var upload models.Upload
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&upload.ID, &upload.UserID, &upload.OrganizationID,
+		&upload.PropertyID, &upload.Kind, &upload.ObjectKey, &upload.Filename, &upload.ContentType,
+		&upload.Caption, &upload.SizeBytes, &upload.Status, &upload.CreatedAt, &upload.CompletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// MarkCompleted records the confirmed object size. Only pending uploads
+// are updated, so a second confirm finds no rows and is rejected.
+func (r *uploadRepository) MarkCompleted(ctx context.Context, id string, size int64, at time.Time) error {
+	query := `UPDATE uploads SET status = ?, size_bytes = ?, completed_at = ? WHERE id = ? AND status = ?`
+	result, err := r.db.ExecContext(ctx, query, models.UploadStatusCompleted, size, at, id, models.UploadStatusPending)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+type UploadSessionRepository interface {
+	Create(ctx context.Context, session *models.UploadSession) error
+	GetByID(ctx context.Context, id string) (*models.UploadSession, error)
+	Advance(ctx context.Context, id string, from, to int64, expiresAt time.Time) (bool, error)
+	MarkCompleted(ctx context.Context, id, url string, at time.Time) (bool, error)
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]models.UploadSession, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type uploadSessionRepository struct {
+	db *sql.DB
+}
+
+func NewUploadSessionRepository(db *sql.DB) UploadSessionRepository {
+	return &uploadSessionRepository{db: db}
+}
+
+const uploadSessionColumns = `id, user_id, organization_id, property_id, kind, filename, content_type, caption,
+	size_bytes, received_bytes, status, url, expires_at, created_at, completed_at`
+
+func (r *uploadSessionRepository) Create(ctx context.Context, session *models.UploadSession) error {
+	query := `INSERT INTO upload_sessions (id, user_id, organization_id, property_id, kind, filename, content_type,
+		caption, size_bytes, status, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.OrganizationID, session.PropertyID,
+		session.Kind, session.Filename, session.ContentType, session.Caption, session.SizeBytes, session.Status, session.ExpiresAt)
+	return err
+}
+
+func (r *uploadSessionRepository) GetByID(ctx context.Context, id string) (*models.UploadSession, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+uploadSessionColumns+` FROM upload_sessions WHERE id = ?`, id)
+	session, err := scanUploadSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Advance moves an active session's offset from one received size to
+// another and extends its expiry. It reports false when the offset is no
+// longer from, as when another chunk got there first.
+func (r *uploadSessionRepository) Advance(ctx context.Context, id string, from, to int64, expiresAt time.Time) (bool, error) {
+	query := `UPDATE upload_sessions SET received_bytes = ?, expires_at = ?
+		WHERE id = ? AND status = ? AND received_bytes = ?`
+	result, err := r.db.ExecContext(ctx, query, to, expiresAt, id, models.UploadSessionActive, from)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// MarkCompleted records where a session's file was stored. Only active
+// sessions are updated, so a second completion reports false.
+func (r *uploadSessionRepository) MarkCompleted(ctx context.Context, id, url string, at time.Time) (bool, error) {
+	query := `UPDATE upload_sessions SET status = ?, url = ?, completed_at = ? WHERE id = ? AND status = ?`
+	result, err := r.db.ExecContext(ctx, query, models.UploadSessionCompleted, url, at, id, models.UploadSessionActive)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// ListExpired returns up to limit sessions, of any status, that expired
+// before the given time, oldest first
+func (r *uploadSessionRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]models.UploadSession, error) {
+	query := `SELECT ` + uploadSessionColumns + ` FROM upload_sessions WHERE expires_at < ? ORDER BY expires_at LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.UploadSession{}
+	for rows.Next() {
+		session, err := scanUploadSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *uploadSessionRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM upload_sessions WHERE id = ?`, id)
+	return err
+}
+
+func scanUploadSession(row rowScanner) (*models.UploadSession, error) {
+	var session models.UploadSession
+	err := row.Scan(&session.ID, &session.UserID, &session.OrganizationID, &session.PropertyID, &session.Kind,
+		&session.Filename, &session.ContentType, &session.Caption, &session.SizeBytes, &session.ReceivedBytes,
+		&session.Status, &session.URL, &session.ExpiresAt, &session.CreatedAt, &session.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
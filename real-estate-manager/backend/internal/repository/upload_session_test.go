@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestUploadSessionRepository_Advance(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		affected int64
+		expected bool
+	}{
+		{name: "offset matches", affected: 1, expected: true},
+		{name: "another chunk got there first", affected: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE upload_sessions SET received_bytes = (.+) WHERE id = \\? AND status = \\? AND received_bytes = \\?").
+				WithArgs(int64(2048), expiresAt, "s1", models.UploadSessionActive, int64(1024)).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := NewUploadSessionRepository(db)
+			advanced, err := repo.Advance(context.Background(), "s1", 1024, 2048, expiresAt)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if advanced != tt.expected {
+				t.Errorf("Expected advanced %v, got %v", tt.expected, advanced)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestUploadSessionRepository_GetByIDMissing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM upload_sessions WHERE id = \\?").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewUploadSessionRepository(db)
+	session, err := repo.GetByID(context.Background(), "missing")
+	if err != nil || session != nil {
+		t.Errorf("Expected no session, got %+v, %v", session, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestUploadRepository_MarkCompleted(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		affected    int64
+		expectError error
+	}{
+		{name: "pending upload", affected: 1},
+		{name: "already completed", affected: 0, expectError: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE uploads SET status = (.+) WHERE id = \\? AND status = \\?").
+				WithArgs(models.UploadStatusCompleted, int64(2048), at, "u1", models.UploadStatusPending).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := NewUploadRepository(db)
+			err = repo.MarkCompleted(context.Background(), "u1", 2048, at)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("Expected error %v, got %v", tt.expectError, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -1,36 +1,48 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"real-estate-manager/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByID(id uint) (*models.User, error)
-	GetByUsername(username string) (*models.User, error)
-	Update(user *models.User) error
-	Delete(id uint) error
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByPublicID(ctx context.Context, publicID string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateRole(ctx context.Context, id uint, role string) error
+	Delete(ctx context.Context, id uint) error
 }
 
 type userRepository struct {
-	db *sql.DB
+	db *sqlx.DB
 }
 
-// NewUserRepository creates a new instance of UserRepository
+// NewUserRepository creates a new instance of UserRepository. Users are
+// scanned into their struct by the db tags of models.User.
 func NewUserRepository(db *sql.DB) UserRepository {
 	return &userRepository{
-		db: db,
+		db: sqlx.NewDb(db, "mysql"),
 	}
 }
 
-func (r *userRepository) Create(user *models.User) error {
+// Create inserts a user, giving them a public ID unless they have one
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	if user.PublicID == "" {
+		user.PublicID = uuid.NewString()
+	}
 	query := `
-        INSERT INTO users (username, password, email, created_at, updated_at) 
-        VALUES (?, ?, ?, NOW(), NOW())
+        INSERT INTO users (public_id, username, password, email, created_at, updated_at) 
+        VALUES (?, ?, ?, ?, NOW(), NOW())
     `
 
-	result, err := r.db.Exec(query, user.Username, user.Password, user.Email)
+	result, err := r.db.ExecContext(ctx, query, user.PublicID, user.Username, user.Password, user.Email)
 	if err != nil {
 		return err
 	}
@@ -45,67 +57,105 @@ func (r *userRepository) Create(user *models.User) error {
 	return nil
 }
 
-func (r *userRepository) GetByID(id uint) (*models.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	query := `
-        SELECT id, username, password, email, created_at, updated_at 
+        SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at 
         FROM users 
         WHERE id = ?
     `
 
 	user := &models.User{}
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Password,
-		&user.Email,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	if err := r.db.GetContext(ctx, user, query, id); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	return user, nil
+}
+
+func (r *userRepository) GetByPublicID(ctx context.Context, publicID string) (*models.User, error) {
+	query := `
+        SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at 
+        FROM users 
+        WHERE public_id = ?
+    `
+
+	user := &models.User{}
+	if err := r.db.GetContext(ctx, user, query, publicID); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (r *userRepository) GetByUsername(username string) (*models.User, error) {
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-        SELECT id, username, password, email, created_at, updated_at 
+        SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at 
         FROM users 
         WHERE username = ?
     `
 
 	user := &models.User{}
-	err := r.db.QueryRow(query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Password,
-		&user.Email,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	if err := r.db.GetContext(ctx, user, query, username); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	return user, nil
+}
+
+// GetByEmail returns the oldest account registered with email
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `
+        SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at 
+        FROM users 
+        WHERE email = ? 
+        ORDER BY id 
+        LIMIT 1
+    `
+
+	user := &models.User{}
+	if err := r.db.GetContext(ctx, user, query, email); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (r *userRepository) Update(user *models.User) error {
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
         UPDATE users 
         SET username = ?, password = ?, email = ?, updated_at = NOW() 
         WHERE id = ?
     `
 
-	_, err := r.db.Exec(query, user.Username, user.Password, user.Email, user.ID)
+	_, err := r.db.ExecContext(ctx, query, user.Username, user.Password, user.Email, user.ID)
 	return err
 }
 
-func (r *userRepository) Delete(id uint) error {
-	query := `DELETE FROM users WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+func (r *userRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	query := `UPDATE users SET role = ?, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, role, id)
 	return err
+}
+
+// Delete removes a user. Users assigned to listings leave an agent tombstone
+// for syncing clients.
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tombstone := `
+        INSERT INTO tombstones (entity_type, entity_id) 
+        SELECT ?, ? FROM DUAL WHERE EXISTS (SELECT 1 FROM properties WHERE agent_id = ?)
+    `
+	if _, err := tx.ExecContext(ctx, tombstone, models.ChangeEntityAgent, id, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -29,7 +30,7 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs("testuser", "hashedpassword", "test@example.com").
+					WithArgs(sqlmock.AnyArg(), "testuser", "hashedpassword", "test@example.com").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedError: false,
@@ -44,7 +45,7 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs("testuser", "hashedpassword", "test@example.com").
+					WithArgs(sqlmock.AnyArg(), "testuser", "hashedpassword", "test@example.com").
 					WillReturnError(errors.New("database connection failed"))
 			},
 			expectedError: true,
@@ -59,7 +60,7 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs("testuser", "hashedpassword", "test@example.com").
+					WithArgs(sqlmock.AnyArg(), "testuser", "hashedpassword", "test@example.com").
 					WillReturnResult(sqlmock.NewErrorResult(errors.New("last insert id error")))
 			},
 			expectedError: true,
@@ -74,7 +75,7 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec("INSERT INTO users").
-					WithArgs("existinguser", "hashedpassword", "existing@example.com").
+					WithArgs(sqlmock.AnyArg(), "existinguser", "hashedpassword", "existing@example.com").
 					WillReturnError(errors.New("UNIQUE constraint failed: users.username"))
 			},
 			expectedError: true,
@@ -93,7 +94,7 @@ func TestUserRepository_Create(t *testing.T) {
 			tt.setupMock(mock)
 
 			userRepo := NewUserRepository(db)
-			err = userRepo.Create(tt.user)
+			err = userRepo.Create(context.Background(), tt.user)
 
 			if tt.expectedError {
 				if err == nil {
@@ -134,9 +135,9 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name:   "successful user retrieval",
 			userID: 1,
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "created_at", "updated_at"}).
-					AddRow(1, "testuser", "hashedpassword", "test@example.com", now, now)
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE id = ?").
+				rows := sqlmock.NewRows([]string{"id", "public_id", "username", "password", "email", "role", "organization_id", "created_at", "updated_at"}).
+					AddRow(1, "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", "testuser", "hashedpassword", "test@example.com", "user", nil, now, now)
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(rows)
 			},
@@ -154,7 +155,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name:   "user not found",
 			userID: 999,
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE id = ?").
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE id = ?").
 					WithArgs(999).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -166,7 +167,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name:   "database error",
 			userID: 1,
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE id = ?").
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnError(errors.New("database connection failed"))
 			},
@@ -178,9 +179,9 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name:   "scan error",
 			userID: 1,
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "created_at", "updated_at"}).
-					AddRow("invalid_id", "testuser", "hashedpassword", "test@example.com", now, now)
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE id = ?").
+				rows := sqlmock.NewRows([]string{"id", "public_id", "username", "password", "email", "role", "organization_id", "created_at", "updated_at"}).
+					AddRow("invalid_id", "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", "testuser", "hashedpassword", "test@example.com", "user", nil, now, now)
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(rows)
 			},
@@ -200,7 +201,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			tt.setupMock(mock)
 
 			userRepo := NewUserRepository(db)
-			user, err := userRepo.GetByID(tt.userID)
+			user, err := userRepo.GetByID(context.Background(), tt.userID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -246,9 +247,9 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 			name:     "successful user retrieval by username",
 			username: "testuser",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "created_at", "updated_at"}).
-					AddRow(1, "testuser", "hashedpassword", "test@example.com", now, now)
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE username = ?").
+				rows := sqlmock.NewRows([]string{"id", "public_id", "username", "password", "email", "role", "organization_id", "created_at", "updated_at"}).
+					AddRow(1, "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b", "testuser", "hashedpassword", "test@example.com", "user", nil, now, now)
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE username = ?").
 					WithArgs("testuser").
 					WillReturnRows(rows)
 			},
@@ -266,7 +267,7 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 			name:     "user not found by username",
 			username: "nonexistent",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE username = ?").
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE username = ?").
 					WithArgs("nonexistent").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -278,7 +279,7 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 			name:     "database error during username query",
 			username: "testuser",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT id, username, password, email, created_at, updated_at FROM users WHERE username = ?").
+				mock.ExpectQuery("SELECT id, public_id, username, password, email, role, organization_id, created_at, updated_at FROM users WHERE username = ?").
 					WithArgs("testuser").
 					WillReturnError(errors.New("database connection failed"))
 			},
@@ -299,7 +300,7 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 			tt.setupMock(mock)
 
 			userRepo := NewUserRepository(db)
-			user, err := userRepo.GetByUsername(tt.username)
+			user, err := userRepo.GetByUsername(context.Background(), tt.username)
 
 			if tt.expectedError {
 				if err == nil {
@@ -397,7 +398,7 @@ func TestUserRepository_Update(t *testing.T) {
 			tt.setupMock(mock)
 
 			userRepo := NewUserRepository(db)
-			err = userRepo.Update(tt.user)
+			err = userRepo.Update(context.Background(), tt.user)
 
 			if tt.expectedError {
 				if err == nil {
@@ -432,9 +433,14 @@ func TestUserRepository_Delete(t *testing.T) {
 			name:   "successful user deletion",
 			userID: 1,
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO tombstones").
+					WithArgs(models.ChangeEntityAgent, uint(1), uint(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectExec("DELETE FROM users WHERE id = ?").
 					WithArgs(uint(1)).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			expectedError: false,
 		},
@@ -442,9 +448,14 @@ func TestUserRepository_Delete(t *testing.T) {
 			name:   "database error during deletion",
 			userID: 1,
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO tombstones").
+					WithArgs(models.ChangeEntityAgent, uint(1), uint(1)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectExec("DELETE FROM users WHERE id = ?").
 					WithArgs(uint(1)).
 					WillReturnError(errors.New("database connection failed"))
+				mock.ExpectRollback()
 			},
 			expectedError: true,
 			errorMessage:  "database connection failed",
@@ -453,9 +464,14 @@ func TestUserRepository_Delete(t *testing.T) {
 			name:   "user not found for deletion",
 			userID: 999,
 			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO tombstones").
+					WithArgs(models.ChangeEntityAgent, uint(999), uint(999)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectExec("DELETE FROM users WHERE id = ?").
 					WithArgs(uint(999)).
 					WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
+				mock.ExpectCommit()
 			},
 			expectedError: false, // Delete doesn't return error for 0 affected rows
 		},
@@ -472,7 +488,7 @@ func TestUserRepository_Delete(t *testing.T) {
 			tt.setupMock(mock)
 
 			userRepo := NewUserRepository(db)
-			err = userRepo.Delete(tt.userID)
+			err = userRepo.Delete(context.Background(), tt.userID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"real-estate-manager/backend/internal/models"
+	"strings"
+)
+
+// ValuationRepository finds the comparable properties estimates are based
+// on
+type ValuationRepository interface {
+	ListComparables(ctx context.Context, criteria models.ComparableCriteria) ([]models.Comparable, error)
+}
+
+type valuationRepository struct {
+	db *sql.DB
+}
+
+func NewValuationRepository(db *sql.DB) ValuationRepository {
+	return &valuationRepository{db: db}
+}
+
+// ListComparables returns priced properties with a living area that match
+// the criteria, most recently observed first. Rentals are left out. A property's latest closed
+// deal gives its sale price and date.
+func (r *valuationRepository) ListComparables(ctx context.Context, criteria models.ComparableCriteria) ([]models.Comparable, error) {
+	query := `SELECT p.id, p.name, p.location, p.status, p.square_feet, p.bedrooms,
+			COALESCE(d.price, p.price), COALESCE(d.closed_at, p.updated_at) AS observed_at, d.id IS NOT NULL,
+			e.latitude, e.longitude
+		FROM properties p
+		LEFT JOIN deals d ON d.id = (
+			SELECT d2.id FROM deals d2 WHERE d2.property_id = p.id AND d2.stage = ? ORDER BY d2.closed_at DESC LIMIT 1)
+		LEFT JOIN property_enrichments e ON e.property_id = p.id
+		WHERE p.id <> ? AND p.listing_type = ? AND p.status IN (?, ?, ?) AND p.square_feet BETWEEN ? AND ?
+		AND COALESCE(d.price, p.price) > 0 AND COALESCE(d.closed_at, p.updated_at) >= ?`
+	args := []any{models.DealClosed, criteria.ExcludeID, models.ListingTypeSale, models.PropertyStatusActive, models.PropertyStatusPending,
+		models.PropertyStatusSold, criteria.MinSqft, criteria.MaxSqft, criteria.Since}
+	if criteria.PropertyType != "" {
+		query += ` AND p.property_type = ?`
+		args = append(args, criteria.PropertyType)
+	}
+	if criteria.UseBounds {
+		query += ` AND e.latitude BETWEEN ? AND ? AND e.longitude BETWEEN ? AND ?`
+		args = append(args, criteria.MinLatitude, criteria.MaxLatitude, criteria.MinLongitude, criteria.MaxLongitude)
+	} else {
+		query += ` AND p.location LIKE ?`
+		args = append(args, "%"+escapeLike(criteria.Locality))
+	}
+	query += ` ORDER BY observed_at DESC, p.id LIMIT ?`
+	args = append(args, criteria.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comps := []models.Comparable{}
+	for rows.Next() {
+		var comp models.Comparable
+		var sold bool
+		if err := rows.Scan(&comp.PropertyID, &comp.Name, &comp.Location, &comp.Status, &comp.SquareFeet, &comp.Bedrooms,
+			&comp.Price, &comp.ObservedAt, &sold, &comp.Latitude, &comp.Longitude); err != nil {
+			return nil, err
+		}
+		comp.Source = models.ComparableListing
+		if sold || comp.Status == models.PropertyStatusSold {
+			comp.Source = models.ComparableSale
+		}
+		comps = append(comps, comp)
+	}
+	return comps, rows.Err()
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestValuationRepository_ListComparables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	since := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	observed := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	// Wildcards in the locality are matched literally
+	mock.ExpectQuery("SELECT p.id, p.name").
+		WithArgs(models.DealClosed, 12, models.ListingTypeSale, models.PropertyStatusActive, models.PropertyStatusPending, models.PropertyStatusSold,
+			1000, 3000, since, "Condo", `%100\% Main, Springfield`, 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "location", "status", "square_feet", "bedrooms", "price",
+			"observed_at", "sold", "latitude", "longitude"}).
+			AddRow(3, "Elm", "3 Elm St, Springfield", models.PropertyStatusActive, 2000, 3, 390000.0, observed, true, nil, nil).
+			AddRow(4, "Oak", "4 Oak St, Springfield", models.PropertyStatusActive, 1800, nil, 350000.0, observed, false, 39.8, -89.6))
+
+	repo := NewValuationRepository(db)
+	comps, err := repo.ListComparables(context.Background(), models.ComparableCriteria{ExcludeID: 12, PropertyType: "Condo",
+		MinSqft: 1000, MaxSqft: 3000, Since: since, Locality: "100% Main, Springfield", Limit: 200})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(comps) != 2 {
+		t.Fatalf("Expected 2 comparables, got %d", len(comps))
+	}
+	// A closed deal makes an active listing a sale
+	if comps[0].Source != models.ComparableSale || comps[1].Source != models.ComparableListing {
+		t.Errorf("Expected a sale and a listing, got %s and %s", comps[0].Source, comps[1].Source)
+	}
+	if !comps[1].Latitude.Valid || comps[0].Bedrooms.Int32 != 3 {
+		t.Errorf("Unexpected comparables: %+v", comps)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
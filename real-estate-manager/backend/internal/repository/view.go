@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"real-estate-manager/backend/internal/models"
+	"time"
+)
+
+// ViewRepository tracks the properties users viewed and the view counters
+// of each property
+type ViewRepository interface {
+	LastViewed(ctx context.Context, userID uint, propertyID int) (*time.Time, error)
+	Record(ctx context.Context, userID uint, propertyID int, at time.Time, keep int, count bool) error
+	ListRecent(ctx context.Context, userID uint, limit int) ([]models.RecentlyViewed, error)
+	GetStats(ctx context.Context, propertyID int) (*models.PropertyViewStats, error)
+}
+
+type viewRepository struct {
+	db *sql.DB
+}
+
+func NewViewRepository(db *sql.DB) ViewRepository {
+	return &viewRepository{db: db}
+}
+
+// LastViewed returns when a user last viewed a property, or nil if they
+// did not or the view has been trimmed since
+func (r *viewRepository) LastViewed(ctx context.Context, userID uint, propertyID int) (*time.Time, error) {
+	var viewedAt time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT viewed_at FROM recently_viewed_properties WHERE user_id = ? AND property_id = ?`,
+		userID, propertyID).Scan(&viewedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &viewedAt, nil
+}
+
+// Record moves a property to the front of a user's recently viewed list
+// and trims the list to its newest keep entries, in one transaction. With
+// count the property's view counter goes up too.
+func (r *viewRepository) Record(ctx context.Context, userID uint, propertyID int, at time.Time, keep int, count bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO recently_viewed_properties (user_id, property_id, viewed_at) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE viewed_at = VALUES(viewed_at), view_count = view_count + 1`, userID, propertyID, at); err != nil {
+		return err
+	}
+	// MySQL cannot LIMIT a subquery of the table being deleted from, so the
+	// rows to keep are read through a derived table
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recently_viewed_properties WHERE user_id = ? AND property_id NOT IN (
+			SELECT property_id FROM (
+				SELECT property_id FROM recently_viewed_properties WHERE user_id = ? ORDER BY viewed_at DESC, property_id LIMIT ?
+			) AS newest)`, userID, userID, keep); err != nil {
+		return err
+	}
+	if count {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO property_view_stats (property_id, views, last_viewed_at) VALUES (?, 1, ?)
+			ON DUPLICATE KEY UPDATE views = views + 1, last_viewed_at = VALUES(last_viewed_at)`, propertyID, at); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListRecent returns the properties a user viewed, most recent first
+func (r *viewRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]models.RecentlyViewed, error) {
+	query := `SELECT ` + propertyColumns + `, v.viewed_at, v.view_count
+		FROM recently_viewed_properties v JOIN properties ON properties.id = v.property_id
+		WHERE v.user_id = ? ORDER BY v.viewed_at DESC, v.property_id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	viewed := []models.RecentlyViewed{}
+	for rows.Next() {
+		var view models.RecentlyViewed
+		if err := scanProperty(extraScanner{row: rows, extra: []any{&view.ViewedAt, &view.Views}}, &view.Property); err != nil {
+			return nil, err
+		}
+		viewed = append(viewed, view)
+	}
+	return viewed, rows.Err()
+}
+
+// GetStats returns a property's view counters, which are zero until it is
+// first viewed
+func (r *viewRepository) GetStats(ctx context.Context, propertyID int) (*models.PropertyViewStats, error) {
+	stats := &models.PropertyViewStats{PropertyID: propertyID}
+	var lastViewedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `SELECT views, last_viewed_at FROM property_view_stats WHERE property_id = ?`, propertyID).
+		Scan(&stats.Views, &lastViewedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if lastViewedAt.Valid {
+		stats.LastViewedAt = &lastViewedAt.Time
+	}
+	return stats, nil
+}
+
+// extraScanner scans the columns selected after a shared column list into
+// extra
+type extraScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (s extraScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestViewRepository_Record(t *testing.T) {
+	tests := []struct {
+		name  string
+		count bool
+	}{
+		{name: "counted", count: true},
+		{name: "repeat view", count: false},
+	}
+
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec("INSERT INTO recently_viewed_properties").WithArgs(uint(4), 12, at).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("DELETE FROM recently_viewed_properties").WithArgs(uint(4), uint(4), 50).WillReturnResult(sqlmock.NewResult(0, 1))
+			if tt.count {
+				mock.ExpectExec("INSERT INTO property_view_stats").WithArgs(12, at).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			repo := NewViewRepository(db)
+			if err := repo.Record(context.Background(), 4, 12, at, 50, tt.count); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestViewRepository_GetStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	// A property nobody viewed has zero views
+	mock.ExpectQuery("SELECT views, last_viewed_at FROM property_view_stats").WithArgs(12).
+		WillReturnRows(sqlmock.NewRows([]string{"views", "last_viewed_at"}))
+
+	repo := NewViewRepository(db)
+	stats, err := repo.GetStats(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if stats.PropertyID != 12 || stats.Views != 0 || stats.LastViewedAt != nil {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
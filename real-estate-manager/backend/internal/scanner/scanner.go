@@ -0,0 +1,192 @@
+// Package scanner checks uploaded files for malware. The only engine is a
+// ClamAV daemon (clamd), streamed files with its INSTREAM command, chosen
+// by setting CLAMAV_ADDRESS.
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxBytes matches clamd's default StreamMaxLength
+	DefaultMaxBytes = 25 << 20
+	defaultTimeout  = 30 * time.Second
+	chunkSize       = 64 << 10
+)
+
+// ErrTooLarge is returned for files longer than the scanner accepts. They
+// have not been scanned.
+var ErrTooLarge = errors.New("file exceeds the scanner's size limit")
+
+// Result is the verdict on one file
+type Result struct {
+	Infected bool
+	// Signature names the malware found in an infected file
+	Signature string
+}
+
+// Scanner checks file contents for malware
+type Scanner interface {
+	Scan(ctx context.Context, content io.Reader) (*Result, error)
+	// Check verifies the scanner is reachable, for health reporting
+	Check(ctx context.Context) error
+}
+
+// NewFromEnv returns a ClamAV client for CLAMAV_ADDRESS, either host:port
+// or unix:/path/to/clamd.sock, accepting files up to CLAMAV_MAX_BYTES. It
+// returns nil when CLAMAV_ADDRESS is unset.
+func NewFromEnv() (Scanner, error) {
+	address := os.Getenv("CLAMAV_ADDRESS")
+	if address == "" {
+		return nil, nil
+	}
+	maxBytes := int64(DefaultMaxBytes)
+	if value := os.Getenv("CLAMAV_MAX_BYTES"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid CLAMAV_MAX_BYTES %q", value)
+		}
+		maxBytes = n
+	}
+	network := "tcp"
+	if path, ok := strings.CutPrefix(address, "unix:"); ok {
+		network, address = "unix", path
+	}
+	return NewClamAV(network, address, maxBytes), nil
+}
+
+// ClamAV streams files to a clamd daemon
+type ClamAV struct {
+	network  string
+	address  string
+	maxBytes int64
+	timeout  time.Duration
+}
+
+func NewClamAV(network, address string, maxBytes int64) *ClamAV {
+	return &ClamAV{network: network, address: address, maxBytes: maxBytes, timeout: defaultTimeout}
+}
+
+// Scan streams content to clamd in chunks. Content longer than the size
+// limit is not sent in full and yields ErrTooLarge.
+func (c *ClamAV) Scan(ctx context.Context, content io.Reader) (*Result, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("failed to start scan: %w", err)
+	}
+	buf := make([]byte, chunkSize)
+	var sent int64
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			sent += int64(n)
+			if sent > c.maxBytes {
+				return nil, ErrTooLarge
+			}
+			if err := writeChunk(conn, buf[:n]); err != nil {
+				return nil, fmt.Errorf("failed to send file to scanner: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read file: %w", readErr)
+		}
+	}
+	// A zero-length chunk ends the stream
+	if err := writeChunk(conn, nil); err != nil {
+		return nil, fmt.Errorf("failed to send file to scanner: %w", err)
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseReply(reply)
+}
+
+// Check sends PING and expects PONG
+func (c *ClamAV) Check(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zPING\x00")); err != nil {
+		return fmt.Errorf("failed to ping scanner: %w", err)
+	}
+	reply, err := readReply(conn)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected scanner reply %q", reply)
+	}
+	return nil
+}
+
+func (c *ClamAV) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to scanner: %w", err)
+	}
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+	return conn, nil
+}
+
+func writeChunk(w io.Writer, chunk []byte) error {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(chunk)))
+	if _, err := w.Write(size[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(chunk)
+	return err
+}
+
+// readReply reads clamd's NUL-terminated reply
+func readReply(r io.Reader) (string, error) {
+	reply, err := io.ReadAll(io.LimitReader(r, 4096))
+	if err != nil && len(reply) == 0 {
+		return "", fmt.Errorf("failed to read scanner reply: %w", err)
+	}
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+// parseReply reads an INSTREAM verdict: "stream: OK",
+// "stream: <signature> FOUND" or "<message> ERROR"
+func parseReply(reply string) (*Result, error) {
+	verdict := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case verdict == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	case strings.Contains(verdict, "size limit exceeded"):
+		return nil, ErrTooLarge
+	default:
+		return nil, fmt.Errorf("scanner error: %s", verdict)
+	}
+}
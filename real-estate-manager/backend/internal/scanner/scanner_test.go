@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeClamd accepts one connection, reads an INSTREAM request and replies
+// with reply(content)
+func fakeClamd(t *testing.T, reply func(content []byte) string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		command := make([]byte, len("zINSTREAM\x00"))
+		if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+			return
+		}
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, conn, int64(size)); err != nil {
+				return
+			}
+		}
+		conn.Write([]byte(reply(content.Bytes()) + "\x00"))
+	}()
+	return listener.Addr().String()
+}
+
+func TestClamAV_Scan(t *testing.T) {
+	reply := func(content []byte) string {
+		if bytes.Contains(content, []byte("EICAR")) {
+			return "stream: Win.Test.EICAR_HDB-1 FOUND"
+		}
+		return "stream: OK"
+	}
+
+	tests := []struct {
+		name              string
+		content           string
+		maxBytes          int64
+		expectedInfected  bool
+		expectedSignature string
+		expectedErr       error
+	}{
+		{name: "clean file", content: strings.Repeat("listing photo ", 10000), maxBytes: DefaultMaxBytes},
+		{name: "infected file", content: "X5O!P%@AP EICAR test", maxBytes: DefaultMaxBytes, expectedInfected: true, expectedSignature: "Win.Test.EICAR_HDB-1"},
+		{name: "too large", content: strings.Repeat("a", 2048), maxBytes: 1024, expectedErr: ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamav := NewClamAV("tcp", fakeClamd(t, reply), tt.maxBytes)
+			result, err := clamav.Scan(context.Background(), strings.NewReader(tt.content))
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Infected != tt.expectedInfected || result.Signature != tt.expectedSignature {
+				t.Errorf("expected infected=%v signature=%q, got %+v", tt.expectedInfected, tt.expectedSignature, result)
+			}
+		})
+	}
+}
+
+func TestClamAV_ScanError(t *testing.T) {
+	clamav := NewClamAV("tcp", fakeClamd(t, func([]byte) string { return "INSTREAM size limit exceeded. ERROR" }), DefaultMaxBytes)
+	if _, err := clamav.Scan(context.Background(), strings.NewReader("document")); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+
+	clamav = NewClamAV("tcp", fakeClamd(t, func([]byte) string { return "Can't allocate memory ERROR" }), DefaultMaxBytes)
+	if _, err := clamav.Scan(context.Background(), strings.NewReader("document")); err == nil {
+		t.Error("expected a scanner error")
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("CLAMAV_ADDRESS", "")
+	if scanner, err := NewFromEnv(); scanner != nil || err != nil {
+		t.Errorf("expected no scanner without CLAMAV_ADDRESS, got %v, %v", scanner, err)
+	}
+
+	t.Setenv("CLAMAV_ADDRESS", "unix:/var/run/clamav/clamd.ctl")
+	scanner, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clamav := scanner.(*ClamAV)
+	if clamav.network != "unix" || clamav.address != "/var/run/clamav/clamd.ctl" || clamav.maxBytes != DefaultMaxBytes {
+		t.Errorf("unexpected client %+v", clamav)
+	}
+
+	t.Setenv("CLAMAV_MAX_BYTES", "lots")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected an invalid size limit to be rejected")
+	}
+}
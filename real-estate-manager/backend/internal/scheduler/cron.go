@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds how far ahead Next looks for a matching minute
+const cronSearchYears = 5
+
+// cronMacros are the shorthands accepted in place of the five fields
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// Cron is a parsed cron expression: minute, hour, day of month, month and
+// day of week, like "0 2 * * *" for 02:00 every day
+type Cron struct {
+	spec     string
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// As in cron, a restricted day of month and day of week match either
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseCron parses a five-field cron expression. Fields take *, numbers,
+// ranges (1-5), steps (*/15, 0-30/10) and comma-separated lists; day of
+// week runs from 0 (Sunday) to 6, with 7 also Sunday. @hourly, @daily,
+// @midnight, @weekly and @monthly are accepted too.
+func ParseCron(spec string) (*Cron, error) {
+	spec = strings.TrimSpace(spec)
+	expanded := spec
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		expanded = macro
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields: minute, hour, day of month, month and day of week", spec)
+	}
+
+	c := &Cron{spec: spec, anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	var err error
+	if c.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if c.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if c.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if c.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if c.weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays |= 1
+	}
+
+	if c.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches a date", spec)
+	}
+	return c, nil
+}
+
+// String returns the expression as it was parsed
+func (c *Cron) String() string {
+	return c.spec
+}
+
+// Next returns the first minute after after that the expression matches,
+// in after's location, or the zero time when there is none within a few
+// years
+func (c *Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	day := c.days&(1<<uint(t.Day())) != 0
+	weekday := c.weekdays&(1<<uint(t.Weekday())) != 0
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// parseCronField returns the values a field allows, as bits
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		first, last := min, max
+		switch {
+		case valueRange == "*":
+		case strings.Contains(valueRange, "-"):
+			from, to, _ := strings.Cut(valueRange, "-")
+			var err error
+			if first, err = cronValue(from, min, max); err != nil {
+				return 0, err
+			}
+			if last, err = cronValue(to, min, max); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range %q", valueRange)
+			}
+		default:
+			var err error
+			if first, err = cronValue(valueRange, min, max); err != nil {
+				return 0, err
+			}
+			// "5/15" runs from 5 to the end of the range
+			if !hasStep {
+				last = first
+			}
+		}
+
+		for value := first; value <= last; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+func cronValue(text string, min, max int) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("%q is not a number from %d to %d", text, min, max)
+	}
+	return value, nil
+}
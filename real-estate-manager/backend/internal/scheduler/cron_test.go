@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	// A Saturday
+	from := time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{spec: "0 2 * * *", next: time.Date(2024, 6, 16, 2, 0, 0, 0, time.UTC)},
+		{spec: "*/20 * * * *", next: time.Date(2024, 6, 15, 10, 40, 0, 0, time.UTC)},
+		{spec: "15,45 9-17 * * 1-5", next: time.Date(2024, 6, 17, 9, 15, 0, 0, time.UTC)},
+		{spec: "0 0 1 * *", next: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 6 * * 7", next: time.Date(2024, 6, 16, 6, 0, 0, 0, time.UTC)},
+		// A restricted day of month and day of week match either
+		{spec: "0 0 20 * 1", next: time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", next: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", next: time.Date(2024, 6, 15, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			cron, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if next := cron.Next(from); !next.Equal(tt.next) {
+				t.Errorf("Expected %v, got %v", tt.next, next)
+			}
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "0 2 * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "5-1 * * * *", "*/0 * * * *", "0 0 31 2 *", "x * * * *"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
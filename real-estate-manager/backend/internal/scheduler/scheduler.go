@@ -0,0 +1,119 @@
+// Package scheduler runs periodic background tasks.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// TaskFunc is a unit of periodic work
+type TaskFunc func(ctx context.Context) error
+
+// IntervalFunc returns the delay until the next run, so intervals can follow
+// runtime settings
+type IntervalFunc func() time.Duration
+
+type task struct {
+	name     string
+	interval IntervalFunc
+	run      TaskFunc
+	wake     chan struct{}
+}
+
+// Scheduler runs registered tasks on their own goroutines until stopped
+type Scheduler struct {
+	mu      sync.Mutex
+	tasks   []task
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running bool
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers fn to run every interval. Tasks registered after Start are
+// picked up on the next Start.
+func (s *Scheduler) Every(name string, interval time.Duration, fn TaskFunc) {
+	s.EveryFunc(name, func() time.Duration { return interval }, fn)
+}
+
+// EveryFunc registers fn with an interval that is re-evaluated after each run
+func (s *Scheduler) EveryFunc(name string, interval IntervalFunc, fn TaskFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, task{name: name, interval: interval, run: fn, wake: make(chan struct{}, 1)})
+}
+
+// Wake makes the named task evaluate its interval again now instead of
+// after the current wait, e.g. when the setting it follows changes. The
+// task does not run until the new interval has elapsed.
+func (s *Scheduler) Wake(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tasks {
+		if t.name == name {
+			select {
+			case t.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Start launches every registered task. The first run happens after one
+// interval has elapsed.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(ctx, t)
+	}
+}
+
+// Stop cancels all tasks and waits for in-flight runs to finish
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.cancel()
+	s.running = false
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, t task) {
+	defer s.wg.Done()
+	for {
+		timer := time.NewTimer(t.interval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-t.wake:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		start := time.Now()
+		if err := t.run(ctx); err != nil {
+			log.Printf("Scheduled task %s failed: %v", t.name, err)
+			continue
+		}
+		log.Printf("Scheduled task %s completed in %v", t.name, time.Since(start))
+	}
+}
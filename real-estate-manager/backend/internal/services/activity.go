@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Page sizes of the activity feed, and how long activity is kept
+const (
+	DefaultActivityLimit = 50
+	MaxActivityLimit     = 200
+	activityRetention    = 90 * 24 * time.Hour
+)
+
+var activityTypePattern = regexp.MustCompile(`^[a-z_]+(\.[a-z_]+)?$`)
+
+// activityLabels describe each event type in the feed
+var activityLabels = map[string]string{
+	events.PropertyCreated:       "Listing created",
+	events.PropertyUpdated:       "Listing updated",
+	events.PropertyDeleted:       "Listing deleted",
+	events.PropertiesBulkUpdated: "Listings updated in bulk",
+	events.JobStarted:            "Import started",
+	events.JobCompleted:          "Import finished",
+	events.JobFailed:             "Import failed",
+	events.JobCancelled:          "Import cancelled",
+	events.LeadCreated:           "New lead",
+	events.ListingSubmitted:      "Listing submitted for review",
+	events.ListingApproved:       "Listing approved",
+	events.ListingRejected:       "Listing rejected",
+	events.ListingUnpublished:    "Listing unpublished",
+	events.ListingExpiring:       "Listing expiring soon",
+	events.ListingExpired:        "Listing expired",
+	events.OfferSubmitted:        "New offer",
+	events.OfferCountered:        "Offer countered",
+	events.OfferAccepted:         "Offer accepted",
+	events.OfferRejected:         "Offer rejected",
+}
+
+// ActivityService records domain events in a queryable activity feed, so
+// users can catch up on what they and their organization did. Its entries
+// are created by HandleEvent, subscribed to the event bus.
+type ActivityService struct {
+	repo  repository.ActivityRepository
+	users repository.UserRepository
+	now   func() time.Time
+}
+
+func NewActivityService(repo repository.ActivityRepository, users repository.UserRepository) *ActivityService {
+	return &ActivityService{repo: repo, users: users, now: time.Now}
+}
+
+// List returns a page of the feed, newest first: the caller's own activity
+// for the "me" scope, or their organization's for "org"
+func (s *ActivityService) List(ctx context.Context, userID uint, scope string, query models.ActivityQuery) (*models.ActivityPage, error) {
+	query.ActorID, query.OrganizationID = 0, 0
+	switch scope {
+	case "", models.ActivityScopeMe:
+		query.ActorID = userID
+	case models.ActivityScopeOrg:
+		principal, _ := PrincipalFromContext(ctx)
+		if principal.OrganizationID == 0 {
+			return nil, apperrors.Validation("the org scope needs an organization")
+		}
+		query.OrganizationID = principal.OrganizationID
+	default:
+		return nil, apperrors.Validationf("scope must be one of %s, %s", models.ActivityScopeMe, models.ActivityScopeOrg)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultActivityLimit
+	}
+	if query.Limit < 1 || query.Limit > MaxActivityLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxActivityLimit)
+	}
+	if query.BeforeID < 0 {
+		return nil, apperrors.Validation("before must be an activity ID")
+	}
+	for _, eventType := range query.Types {
+		if !activityTypePattern.MatchString(eventType) {
+			return nil, apperrors.Validationf("unknown event type %q", eventType)
+		}
+	}
+
+	// One extra row tells whether another page follows
+	limit := query.Limit
+	query.Limit++
+	activities, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ActivityPage{Activities: activities}
+	if len(activities) > limit {
+		page.Activities = activities[:limit]
+		page.HasMore = true
+		page.Cursor = strconv.FormatInt(page.Activities[limit-1].ID, 10)
+	}
+	return page, nil
+}
+
+// HandleEvent records an event in the feed. The event concerns the
+// organization of the listing it is about or, failing that, of the user
+// who caused it.
+func (s *ActivityService) HandleEvent(ctx context.Context, event events.Event) {
+	activity := &models.Activity{
+		EventID:    event.ID,
+		Type:       event.Type,
+		Subject:    event.Subject,
+		Summary:    activitySummary(event),
+		OccurredAt: event.OccurredAt,
+	}
+	if event.ActorID != 0 {
+		activity.ActorID = nullID(event.ActorID)
+	}
+	if property := eventProperty(event); property != nil {
+		activity.OrganizationID = property.OrganizationID
+	} else if event.ActorID != 0 {
+		if user, err := s.users.GetByID(ctx, uint(event.ActorID)); err == nil {
+			activity.OrganizationID = user.OrganizationID
+		}
+	}
+	if err := s.repo.Create(ctx, activity); err != nil {
+		log.Printf("Failed to record %s activity: %v", event.Type, err)
+	}
+}
+
+// Prune removes activity older than the retention period and returns how
+// much was removed
+func (s *ActivityService) Prune(ctx context.Context) (int64, error) {
+	return s.repo.DeleteBefore(ctx, s.now().Add(-activityRetention))
+}
+
+// eventProperty returns the listing an event is about, when its data
+// carries one
+func eventProperty(event events.Event) *models.Property {
+	switch data := event.Data.(type) {
+	case *models.Property:
+		return data
+	case *models.ListingReview:
+		return &data.Property
+	case *models.OfferActivity:
+		return &data.Property
+	}
+	return nil
+}
+
+// activitySummary describes an event in one line, e.g. "Listing updated:
+// 123 Main St"
+func activitySummary(event events.Event) string {
+	label, ok := activityLabels[event.Type]
+	if !ok {
+		label = event.Type
+	}
+	var detail string
+	switch data := event.Data.(type) {
+	case *models.OfferActivity:
+		detail = fmt.Sprintf("%s, %s from %s", data.Property.Name, formatPrice(data.Amount), data.BuyerName)
+	case *models.Lead:
+		detail = leadContact(data)
+	default:
+		if property := eventProperty(event); property != nil {
+			detail = property.Name
+		}
+	}
+	if detail = strings.TrimSpace(detail); detail == "" {
+		return label
+	}
+	// The column holds 255 characters
+	summary := []rune(label + ": " + detail)
+	if len(summary) > 255 {
+		summary = summary[:255]
+	}
+	return string(summary)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestActivityService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockActivityRepository(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), models.ActivityQuery{OrganizationID: 3, Types: []string{"offer"}, BeforeID: 90, Limit: 3}).
+		Return([]models.Activity{{ID: 88}, {ID: 80}, {ID: 79}}, nil)
+	mockRepo.EXPECT().List(gomock.Any(), models.ActivityQuery{ActorID: 7, Limit: DefaultActivityLimit + 1}).
+		Return([]models.Activity{{ID: 12}}, nil)
+
+	service := NewActivityService(mockRepo, nil)
+	ctx := WithPrincipal(context.Background(), Principal{Role: models.RoleUser, OrganizationID: 3})
+	page, err := service.List(ctx, 7, models.ActivityScopeOrg, models.ActivityQuery{Types: []string{"offer"}, BeforeID: 90, Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Activities) != 2 || !page.HasMore || page.Cursor != "80" {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	// The caller's own activity is the default
+	page, err = service.List(ctx, 7, "", models.ActivityQuery{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Activities) != 1 || page.HasMore || page.Cursor != "" {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	outsider := WithPrincipal(context.Background(), Principal{Role: models.RoleUser})
+	for name, list := range map[string]func() error{
+		"org scope outside an organization": func() error {
+			_, err := service.List(outsider, 7, models.ActivityScopeOrg, models.ActivityQuery{})
+			return err
+		},
+		"unknown scope": func() error {
+			_, err := service.List(ctx, 7, "everyone", models.ActivityQuery{})
+			return err
+		},
+		"invalid type": func() error {
+			_, err := service.List(ctx, 7, models.ActivityScopeMe, models.ActivityQuery{Types: []string{"offer.%"}})
+			return err
+		},
+		"oversized page": func() error {
+			_, err := service.List(ctx, 7, models.ActivityScopeMe, models.ActivityQuery{Limit: MaxActivityLimit + 1})
+			return err
+		},
+	} {
+		if err := list(); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+}
+
+func TestActivityService_HandleEvent(t *testing.T) {
+	org := func(id int32) models.NullInt32 {
+		return models.NullInt32{NullInt32: sql.NullInt32{Int32: id, Valid: true}}
+	}
+
+	tests := []struct {
+		name          string
+		event         events.Event
+		actorOrg      models.NullInt32
+		expectOrg     models.NullInt32
+		expectSummary string
+	}{
+		{name: "listing of an organization", event: events.Event{Type: events.PropertyUpdated, ActorID: 7,
+			Data: &models.Property{ID: 12, Name: "123 Main St", OrganizationID: org(3)}},
+			expectOrg: org(3), expectSummary: "Listing updated: 123 Main St"},
+		{name: "offer", event: events.Event{Type: events.OfferAccepted, ActorID: 7,
+			Data: &models.OfferActivity{Offer: models.Offer{Amount: 395000, BuyerName: "Jane Roe"}, Property: models.Property{Name: "123 Main St"}}},
+			expectSummary: "Offer accepted: 123 Main St, $395,000 from Jane Roe"},
+		{name: "deletion falls back to the actor's organization", event: events.Event{Type: events.PropertyDeleted, ActorID: 7,
+			Data: map[string]int{"id": 12}}, actorOrg: org(5), expectOrg: org(5), expectSummary: "Listing deleted"},
+		{name: "lead from a webhook", event: events.Event{Type: events.LeadCreated, Data: &models.Lead{Name: "Sam Lee"}},
+			expectSummary: "New lead: Sam Lee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			if eventProperty(tt.event) == nil && tt.event.ActorID != 0 {
+				mockUsers.EXPECT().GetByID(gomock.Any(), uint(tt.event.ActorID)).Return(&models.User{OrganizationID: tt.actorOrg}, nil)
+			}
+			var recorded *models.Activity
+			mockRepo := mocks.NewMockActivityRepository(ctrl)
+			mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, activity *models.Activity) error {
+				recorded = activity
+				return nil
+			})
+
+			tt.event.ID, tt.event.Subject = "evt-1", "property/12"
+			NewActivityService(mockRepo, mockUsers).HandleEvent(context.Background(), tt.event)
+			if recorded == nil || recorded.EventID != "evt-1" || recorded.OrganizationID != tt.expectOrg || recorded.Summary != tt.expectSummary {
+				t.Fatalf("Unexpected activity %+v", recorded)
+			}
+			if recorded.ActorID.Valid != (tt.event.ActorID != 0) {
+				t.Errorf("Unexpected actor %+v", recorded.ActorID)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the ID of the user performing the
+// request, for recording who made a change
+func WithActor(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext returns the user ID stored by WithActor
+func ActorFromContext(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(actorKey{}).(uint)
+	return userID, ok
+}
+
+type principalKey struct{}
+
+// Principal is the role a request is acting under, used for permission
+// checks
+type Principal struct {
+	Role           string
+	OrganizationID int
+	// Scopes limits a service-account token; nil means the role alone decides
+	Scopes []string
+}
+
+// WithPrincipal returns a context carrying the caller's role and
+// organization
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/alerts"
+)
+
+// alertRepeatInterval suppresses repeats of the same alert so a flapping
+// dependency does not flood the channel
+const alertRepeatInterval = 10 * time.Minute
+
+// Alerter raises operational alerts
+type Alerter interface {
+	Raise(alertType, title string, fields map[string]string)
+}
+
+// AlertService posts operational alerts to the ops webhook. Alert types can
+// be switched off with the ops_alert_events setting.
+type AlertService struct {
+	notifier alerts.Notifier
+	settings SettingsProvider
+	now      func() time.Time
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+	wg       sync.WaitGroup
+}
+
+func NewAlertService(notifier alerts.Notifier, settings SettingsProvider) *AlertService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &AlertService{notifier: notifier, settings: settings, now: time.Now, lastSent: make(map[string]time.Time)}
+}
+
+// Raise posts an alert in the background. Alerts of a disabled type, and
+// repeats of the same alert within alertRepeatInterval, are dropped.
+func (s *AlertService) Raise(alertType, title string, fields map[string]string) {
+	if s == nil || s.notifier == nil {
+		return
+	}
+	if !slices.Contains(splitList(s.settings.GetString(SettingAlertEvents)), alertType) {
+		return
+	}
+
+	now := s.now()
+	key := alertType + "|" + title
+	s.mu.Lock()
+	if last, ok := s.lastSent[key]; ok && now.Sub(last) < alertRepeatInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSent[key] = now
+	s.mu.Unlock()
+
+	alert := alerts.Alert{Type: alertType, Title: title, Fields: fields, At: now}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := s.notifier.Notify(ctx, alert); err != nil {
+			log.Printf("Failed to post %s alert %q: %v", alertType, title, err)
+		}
+	}()
+}
+
+// Wait blocks until alerts being posted have been sent
+func (s *AlertService) Wait() {
+	s.wg.Wait()
+}
+
+// raiseAlert is Raise for optional alerters
+func raiseAlert(alerter Alerter, alertType, title string, fields map[string]string) {
+	if alerter != nil {
+		alerter.Raise(alertType, title, fields)
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/alerts"
+)
+
+// recordingNotifier keeps posted alerts in memory
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []alerts.Alert
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert alerts.Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, alert)
+	return nil
+}
+
+// recordingAlerter keeps raised alert types in memory
+type recordingAlerter struct {
+	raised []string
+}
+
+func (a *recordingAlerter) Raise(alertType, title string, fields map[string]string) {
+	a.raised = append(a.raised, alertType)
+}
+
+func TestAlertService_Raise(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	service := NewAlertService(notifier, staticSettings{SettingAlertEvents: "job_failed, circuit_open"})
+	service.now = func() time.Time { return now }
+
+	service.Raise(alerts.JobFailed, "Import failed", map[string]string{"job": "1"})
+	service.Raise(alerts.JobFailed, "Import failed", map[string]string{"job": "2"})
+	service.Raise(alerts.AuthLockout, "Locked out", nil)
+	service.Raise(alerts.CircuitOpen, "Circuit opened", nil)
+	now = now.Add(alertRepeatInterval)
+	service.Raise(alerts.JobFailed, "Import failed", map[string]string{"job": "3"})
+	service.Wait()
+
+	if len(notifier.sent) != 3 {
+		t.Fatalf("Expected 3 alerts, got %d: %+v", len(notifier.sent), notifier.sent)
+	}
+	jobs := map[string]bool{}
+	for _, alert := range notifier.sent {
+		if alert.Type == alerts.AuthLockout {
+			t.Error("Expected disabled alert types to be dropped")
+		}
+		if alert.Type == alerts.JobFailed {
+			jobs[alert.Fields["job"]] = true
+		}
+	}
+	if !jobs["1"] || jobs["2"] || !jobs["3"] {
+		t.Errorf("Expected repeats within the interval to be dropped, got jobs %v", jobs)
+	}
+
+	// Without a webhook alerts are ignored
+	NewAlertService(nil, nil).Raise(alerts.JobFailed, "Import failed", nil)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	opened := 0
+	breaker := newCircuitBreaker(2, time.Minute, func(failures int) { opened++ })
+	breaker.now = func() time.Time { return now }
+	failure := errors.New("upstream returned status 503")
+
+	breaker.Record(failure)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Expected the breaker to stay closed below the threshold, got %v", err)
+	}
+	breaker.Record(failure)
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) || opened != 1 {
+		t.Fatalf("Expected the breaker to open once, got %v after %d opens", err, opened)
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Expected a trial request after the cooldown, got %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected only one trial request, got %v", err)
+	}
+	breaker.Record(failure)
+	if opened != 2 {
+		t.Errorf("Expected a failed trial to reopen the breaker, got %d opens", opened)
+	}
+
+	now = now.Add(time.Minute)
+	breaker.Allow()
+	breaker.Record(nil)
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("Expected a successful trial to close the breaker, got %v", err)
+	}
+}
+
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) PingContext(ctx context.Context) error {
+	return p.err
+}
+
+func TestReadinessService_Check(t *testing.T) {
+	db := &fakePinger{}
+	alerter := &recordingAlerter{}
+	service := NewReadinessService(db, alerter)
+
+	if err := service.Check(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	db.err = errors.New("connection refused")
+	service.Check(context.Background())
+	service.Check(context.Background())
+	if len(alerter.raised) != 1 || alerter.raised[0] != alerts.ReadinessFailed {
+		t.Errorf("Expected one alert when the probe starts failing, got %v", alerter.raised)
+	}
+
+	db.err = nil
+	service.Check(context.Background())
+	db.err = errors.New("connection refused")
+	if err := service.Check(context.Background()); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(alerter.raised) != 2 {
+		t.Errorf("Expected a new alert after recovering, got %v", alerter.raised)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+type AmenityService struct {
+	repo         repository.AmenityRepository
+	propertyRepo repository.PropertyRepository
+}
+
+func NewAmenityService(repo repository.AmenityRepository, propertyRepo repository.PropertyRepository) *AmenityService {
+	return &AmenityService{repo: repo, propertyRepo: propertyRepo}
+}
+
+// GetAmenities returns a property's amenities. Properties without a stored
+// row get an empty set rather than an error.
+func (s *AmenityService) GetAmenities(ctx context.Context, propertyID int) (*models.Amenities, error) {
+	if err := s.ensureProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+
+	amenities, err := s.repo.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if amenities == nil {
+		amenities = &models.Amenities{PropertyID: propertyID}
+	}
+	return amenities, nil
+}
+
+// UpdateAmenities replaces a property's amenities
+func (s *AmenityService) UpdateAmenities(ctx context.Context, amenities *models.Amenities) error {
+	if err := validateAmenities(amenities); err != nil {
+		return err
+	}
+	if err := s.ensureProperty(ctx, amenities.PropertyID); err != nil {
+		return err
+	}
+	return s.repo.Upsert(ctx, amenities)
+}
+
+func (s *AmenityService) ensureProperty(ctx context.Context, propertyID int) error {
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property == nil {
+		return apperrors.NotFound("property not found")
+	}
+	return nil
+}
+
+func validateAmenities(amenities *models.Amenities) error {
+	if amenities.GarageSpaces.Valid && amenities.GarageSpaces.Int32 < 0 {
+		return apperrors.Validation("garage_spaces must not be negative")
+	}
+	if amenities.HOAFee.Valid && amenities.HOAFee.Float64 < 0 {
+		return apperrors.Validation("hoa_fee must not be negative")
+	}
+	if amenities.HVACType.Valid && !models.IsValidHVACType(amenities.HVACType.String) {
+		return apperrors.Validation("invalid hvac_type")
+	}
+	return nil
+}
+
+// AmenitiesFromSimplyRETS maps the free-text MLS feature fields onto the
+// structured amenities model
+func AmenitiesFromSimplyRETS(simplyProperty models.SimplyRETSProperty) models.Amenities {
+	details := simplyProperty.Property
+	amenities := models.Amenities{
+		HasPool:  hasPool(details.Pool),
+		HVACType: nullString(classifyHVAC(details.Cooling, details.Heating)),
+		Features: splitFeatures(details.InteriorFeatures, details.ExteriorFeatures),
+	}
+	if details.GarageSpaces > 0 {
+		amenities.GarageSpaces = nullInt32(int(details.GarageSpaces))
+	}
+	if fee := monthlyFee(simplyProperty.Association); fee > 0 {
+		amenities.HOAFee = models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: fee, Valid: true}}
+	}
+	return amenities
+}
+
+// monthlyFee converts an HOA fee to the monthly amount hoa_fee holds. Fees
+// without a known frequency are taken as monthly, the most common one.
+func monthlyFee(association models.SimplyRETSAssociation) float64 {
+	frequency := strings.ToLower(association.Frequency)
+	switch {
+	case strings.Contains(frequency, "semi") && strings.Contains(frequency, "annual"):
+		return math.Round(association.Fee/6*100) / 100
+	case strings.Contains(frequency, "quarter"):
+		return math.Round(association.Fee/3*100) / 100
+	case strings.Contains(frequency, "annual"), strings.Contains(frequency, "year"):
+		return math.Round(association.Fee/12*100) / 100
+	}
+	return association.Fee
+}
+
+func hasPool(pool string) bool {
+	pool = strings.ToLower(strings.TrimSpace(pool))
+	return pool != "" && pool != "none" && pool != "no"
+}
+
+// classifyHVAC normalizes MLS cooling/heating descriptions; cooling wins
+// because it is the more distinguishing field
+func classifyHVAC(cooling, heating string) string {
+	for _, text := range []string{cooling, heating} {
+		text = strings.ToLower(text)
+		switch {
+		case text == "":
+			continue
+		case strings.Contains(text, "heat pump"):
+			return models.HVACHeatPump
+		case strings.Contains(text, "central"):
+			return models.HVACCentral
+		case strings.Contains(text, "window"), strings.Contains(text, "wall"):
+			return models.HVACWindow
+		case strings.Contains(text, "radiant"):
+			return models.HVACRadiant
+		case strings.Contains(text, "none"):
+			return models.HVACNone
+		default:
+			return models.HVACOther
+		}
+	}
+	return ""
+}
+
+func splitFeatures(lists ...string) models.StringList {
+	var features models.StringList
+	for _, list := range lists {
+		for _, feature := range strings.Split(list, ",") {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				features = append(features, feature)
+			}
+		}
+	}
+	return features
+}
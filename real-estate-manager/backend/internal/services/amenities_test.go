@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAmenitiesFromSimplyRETS(t *testing.T) {
+	simplyProperty := models.SimplyRETSProperty{
+		Property: models.SimplyRETSPropertyDetails{
+			GarageSpaces:     2,
+			Pool:             "Private, In Ground",
+			Cooling:          "Central Air, Ceiling Fans",
+			InteriorFeatures: "Fireplace, Wet Bar",
+			ExteriorFeatures: " Deck ,",
+		},
+		Association: models.SimplyRETSAssociation{Fee: 275},
+	}
+
+	amenities := AmenitiesFromSimplyRETS(simplyProperty)
+
+	if !amenities.HasPool {
+		t.Error("Expected pool to be detected")
+	}
+	if !amenities.GarageSpaces.Valid || amenities.GarageSpaces.Int32 != 2 {
+		t.Errorf("Expected 2 garage spaces, got %+v", amenities.GarageSpaces)
+	}
+	if amenities.HVACType.String != models.HVACCentral {
+		t.Errorf("Expected central HVAC, got %+v", amenities.HVACType)
+	}
+	if !amenities.HOAFee.Valid || amenities.HOAFee.Float64 != 275 {
+		t.Errorf("Expected HOA fee 275, got %+v", amenities.HOAFee)
+	}
+	if len(amenities.Features) != 3 || amenities.Features[2] != "Deck" {
+		t.Errorf("Expected 3 trimmed features, got %v", amenities.Features)
+	}
+}
+
+func TestMonthlyFee(t *testing.T) {
+	tests := []struct {
+		association models.SimplyRETSAssociation
+		expected    float64
+	}{
+		{association: models.SimplyRETSAssociation{Fee: 250, Frequency: "Monthly"}, expected: 250},
+		{association: models.SimplyRETSAssociation{Fee: 250}, expected: 250},
+		{association: models.SimplyRETSAssociation{Fee: 600, Frequency: "Quarterly"}, expected: 200},
+		{association: models.SimplyRETSAssociation{Fee: 1200, Frequency: "Semi-Annually"}, expected: 200},
+		{association: models.SimplyRETSAssociation{Fee: 1000, Frequency: "Annually"}, expected: 83.33},
+	}
+
+	for _, tt := range tests {
+		if fee := monthlyFee(tt.association); fee != tt.expected {
+			t.Errorf("monthlyFee(%+v) = %v, expected %v", tt.association, fee, tt.expected)
+		}
+	}
+}
+
+func TestClassifyHVAC(t *testing.T) {
+	tests := []struct {
+		cooling  string
+		heating  string
+		expected string
+	}{
+		{cooling: "Central Air", expected: models.HVACCentral},
+		{cooling: "Heat Pump", expected: models.HVACHeatPump},
+		{cooling: "Window Unit(s)", expected: models.HVACWindow},
+		{heating: "Radiant Floor", expected: models.HVACRadiant},
+		{cooling: "None", expected: models.HVACNone},
+		{cooling: "Evaporative", expected: models.HVACOther},
+		{expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := classifyHVAC(tt.cooling, tt.heating); got != tt.expected {
+			t.Errorf("classifyHVAC(%q, %q) = %q, expected %q", tt.cooling, tt.heating, got, tt.expected)
+		}
+	}
+}
+
+func TestAmenityService_UpdateAmenities(t *testing.T) {
+	tests := []struct {
+		name       string
+		amenities  *models.Amenities
+		setupMock  func(repo *mocks.MockAmenityRepository, propertyRepo *mocks.MockPropertyRepository)
+		expectKind error
+	}{
+		{
+			name:      "saves valid amenities",
+			amenities: &models.Amenities{PropertyID: 1, HasPool: true},
+			setupMock: func(repo *mocks.MockAmenityRepository, propertyRepo *mocks.MockPropertyRepository) {
+				propertyRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1}, nil)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "rejects unknown hvac type",
+			amenities: &models.Amenities{PropertyID: 1,
+				HVACType: models.NullString{NullString: sql.NullString{String: "geothermal", Valid: true}}},
+			setupMock:  func(repo *mocks.MockAmenityRepository, propertyRepo *mocks.MockPropertyRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name: "rejects negative garage spaces",
+			amenities: &models.Amenities{PropertyID: 1,
+				GarageSpaces: models.NullInt32{NullInt32: sql.NullInt32{Int32: -1, Valid: true}}},
+			setupMock:  func(repo *mocks.MockAmenityRepository, propertyRepo *mocks.MockPropertyRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:      "missing property",
+			amenities: &models.Amenities{PropertyID: 99},
+			setupMock: func(repo *mocks.MockAmenityRepository, propertyRepo *mocks.MockPropertyRepository) {
+				propertyRepo.EXPECT().GetByID(gomock.Any(), 99).Return(nil, nil)
+			},
+			expectKind: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockAmenityRepository(ctrl)
+			mockPropertyRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo, mockPropertyRepo)
+
+			service := NewAmenityService(mockRepo, mockPropertyRepo)
+			err := service.UpdateAmenities(context.Background(), tt.amenities)
+
+			if tt.expectKind == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			} else if !errors.Is(err, tt.expectKind) {
+				t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+			}
+		})
+	}
+}
+
+func TestAmenityService_GetAmenitiesDefaultsToEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAmenityRepository(ctrl)
+	mockPropertyRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockPropertyRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1}, nil)
+	mockRepo.EXPECT().GetByPropertyID(gomock.Any(), 1).Return(nil, nil)
+
+	service := NewAmenityService(mockRepo, mockPropertyRepo)
+	amenities, err := service.GetAmenities(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if amenities.PropertyID != 1 || amenities.HasPool {
+		t.Errorf("Expected empty amenities for property 1, got %+v", amenities)
+	}
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Listings archived per batch, and archived listings listed at once
+const (
+	archiveBatchSize   = 100
+	DefaultArchiveList = 50
+	MaxArchiveList     = 500
+)
+
+// An archive holds the listing as JSON and its photos under photos/
+const (
+	archiveListingEntry = "listing.json"
+	archivePhotoDir     = "photos/"
+)
+
+// ArchiveService moves sold and withdrawn listings that have not changed
+// in archive_after_years into cold storage: each listing, with its
+// amenities and locally stored photos, goes into a gzipped tarball under
+// the archive directory, and only a slim row in property_archives stays
+// behind. Unarchive puts a listing back as it was, under its original ID.
+type ArchiveService struct {
+	repo       repository.ArchiveRepository
+	amenities  repository.AmenityRepository
+	settings   SettingsProvider
+	events     EventPublisher
+	imagesDir  string
+	archiveDir string
+	now        func() time.Time
+}
+
+func NewArchiveService(repo repository.ArchiveRepository, amenities repository.AmenityRepository, settings SettingsProvider,
+	publisher EventPublisher, imagesDir, archiveDir string) *ArchiveService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &ArchiveService{
+		repo:       repo,
+		amenities:  amenities,
+		settings:   settings,
+		events:     publisher,
+		imagesDir:  imagesDir,
+		archiveDir: archiveDir,
+		now:        time.Now,
+	}
+}
+
+// ArchiveDue archives every listing due for it. Listings changed since
+// they were read are skipped and picked up by a later run if still due.
+func (s *ArchiveService) ArchiveDue(ctx context.Context) (*models.ArchiveRun, error) {
+	cutoff := s.now().AddDate(-s.settings.GetInt(SettingArchiveAfterYears), 0, 0)
+	run := &models.ArchiveRun{}
+	for {
+		properties, err := s.repo.FindCandidates(ctx, cutoff, archiveBatchSize)
+		if err != nil {
+			return run, fmt.Errorf("failed to find listings to archive: %w", err)
+		}
+		archived := 0
+		for i := range properties {
+			size, err := s.archive(ctx, &properties[i])
+			if err != nil {
+				return run, fmt.Errorf("failed to archive listing %d: %w", properties[i].ID, err)
+			}
+			if size == 0 {
+				run.Skipped++
+				continue
+			}
+			archived++
+			run.Archived++
+			run.Bytes += size
+		}
+		if len(properties) < archiveBatchSize || archived == 0 {
+			return run, nil
+		}
+	}
+}
+
+// archive archives one listing and returns the size of its archive, or 0
+// when the listing changed before it could be archived
+func (s *ArchiveService) archive(ctx context.Context, property *models.Property) (int64, error) {
+	amenities, err := s.amenities.GetByPropertyID(ctx, property.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get amenities: %w", err)
+	}
+	document := models.ArchivedListing{
+		Property:      *property,
+		LivingAreaSqm: property.LivingAreaSqm,
+		LotAreaSqm:    property.LotAreaSqm,
+		Amenities:     amenities,
+	}
+
+	name := property.PublicID + ".tar.gz"
+	path := filepath.Join(s.archiveDir, name)
+	localURLs, size, err := s.writeArchive(path, &document)
+	if err != nil {
+		return 0, err
+	}
+
+	archive := &models.PropertyArchive{
+		PropertyID:     property.ID,
+		PublicID:       property.PublicID,
+		OrganizationID: property.OrganizationID,
+		Name:           property.Name,
+		Location:       property.Location,
+		Status:         property.Status,
+		ListingType:    property.ListingType,
+		Price:          property.Price,
+		PhotoCount:     len(property.Photos),
+		ArchivePath:    name,
+		ArchiveBytes:   size,
+		ListedAt:       property.CreatedAt,
+		LastUpdatedAt:  property.UpdatedAt,
+		ArchivedAt:     s.now(),
+	}
+	archived, err := s.repo.Archive(ctx, archive, property.Version)
+	if err != nil || !archived {
+		os.Remove(path)
+		return 0, err
+	}
+
+	s.removePhotos(ctx, property.ID, localURLs)
+	publishEvent(ctx, s.events, events.PropertyDeleted, propertySubject(property.ID),
+		map[string]any{"id": property.ID, "archived": true})
+	return size, nil
+}
+
+// removePhotos removes an archived listing's photo files and their
+// thumbnails, except those another listing still uses. They are in the
+// archive, so a failure only leaves files behind.
+func (s *ArchiveService) removePhotos(ctx context.Context, propertyID int, localURLs []string) {
+	shared, err := s.repo.SharedPhotos(ctx, propertyID, localURLs)
+	if err != nil {
+		log.Printf("Failed to check shared photos of archived listing %d: %v", propertyID, err)
+		return
+	}
+	for _, localURL := range localURLs {
+		if shared[localURL] {
+			continue
+		}
+		for _, file := range append([]string{localURL}, thumbnailURLs(localURL)...) {
+			if err := os.Remove(filepath.Join(s.imagesDir, filepath.Base(file))); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("Failed to remove photo %s of archived listing %d: %v", file, propertyID, err)
+			}
+		}
+	}
+}
+
+// writeArchive writes the listing and its local photo files to path and
+// returns the photos' local URLs and the archive's size. It is written to
+// a temporary file first so a partial archive is never left behind.
+func (s *ArchiveService) writeArchive(path string, document *models.ArchivedListing) ([]string, int64, error) {
+	if err := os.MkdirAll(s.archiveDir, 0755); err != nil {
+		return nil, 0, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.archiveDir, "archive-*.tmp")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create archive: %w", err)
+	}
+	localURLs, err := s.writeEntries(tmp, document)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	info, err := os.Stat(tmp.Name())
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, 0, fmt.Errorf("failed to save archive: %w", err)
+	}
+	return localURLs, info.Size(), nil
+}
+
+func (s *ArchiveService) writeEntries(w io.Writer, document *models.ArchivedListing) ([]string, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	listing, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	header := &tar.Header{Name: archiveListingEntry, Mode: 0644, Size: int64(len(listing)), ModTime: s.now()}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(listing); err != nil {
+		return nil, err
+	}
+
+	var localURLs []string
+	added := make(map[string]bool)
+	for _, photo := range document.Property.Photos {
+		if !strings.HasPrefix(photo.LocalURL, "/images/") || added[photo.LocalURL] {
+			continue
+		}
+		ok, err := addFile(tw, filepath.Join(s.imagesDir, filepath.Base(photo.LocalURL)), archivePhotoDir+filepath.Base(photo.LocalURL))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		added[photo.LocalURL] = true
+		localURLs = append(localURLs, photo.LocalURL)
+		for _, thumbnail := range thumbnailURLs(photo.LocalURL) {
+			if _, err := addFile(tw, filepath.Join(s.imagesDir, filepath.Base(thumbnail)), archivePhotoDir+filepath.Base(thumbnail)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return localURLs, gz.Close()
+}
+
+// addFile copies the file at path into the archive as name, and reports
+// false if there is no such file
+func addFile(tw *tar.Writer, path, name string) (bool, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	header := &tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime()}
+	if err := tw.WriteHeader(header); err != nil {
+		return false, err
+	}
+	_, err = io.Copy(tw, file)
+	return err == nil, err
+}
+
+// Unarchive restores an archived listing, by property ID or public ID,
+// with its photos and amenities, and removes its archive
+func (s *ArchiveService) Unarchive(ctx context.Context, id string) (*models.Property, error) {
+	archive, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived listing: %w", err)
+	}
+	if archive == nil {
+		return nil, apperrors.NotFound("archived listing not found")
+	}
+
+	path := filepath.Join(s.archiveDir, filepath.Base(archive.ArchivePath))
+	document, err := s.readArchive(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive of listing %d: %w", archive.PropertyID, err)
+	}
+	property := document.Property
+	property.LivingAreaSqm, property.LotAreaSqm = document.LivingAreaSqm, document.LotAreaSqm
+	if err := s.repo.Restore(ctx, &property); err != nil {
+		return nil, fmt.Errorf("failed to restore listing %d: %w", archive.PropertyID, err)
+	}
+	if document.Amenities != nil {
+		document.Amenities.PropertyID = property.ID
+		if err := s.amenities.Upsert(ctx, document.Amenities); err != nil {
+			log.Printf("Failed to restore amenities of listing %d: %v", property.ID, err)
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		log.Printf("Failed to remove archive of listing %d: %v", property.ID, err)
+	}
+
+	publishEvent(ctx, s.events, events.PropertyCreated, propertySubject(property.ID), &property)
+	return &property, nil
+}
+
+// readArchive reads an archive's listing and puts its photos back in the
+// images directory; photos already there are kept
+func (s *ArchiveService) readArchive(path string) (*models.ArchivedListing, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var document *models.ArchivedListing
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case header.Name == archiveListingEntry:
+			document = &models.ArchivedListing{}
+			if err := json.NewDecoder(tr).Decode(document); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(header.Name, archivePhotoDir):
+			if err := s.restorePhoto(tr, filepath.Base(header.Name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if document == nil {
+		return nil, errors.New("archive has no listing")
+	}
+	return document, nil
+}
+
+func (s *ArchiveService) restorePhoto(r io.Reader, name string) error {
+	path := filepath.Join(s.imagesDir, name)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// List returns the most recently archived listings, newest first
+func (s *ArchiveService) List(ctx context.Context, limit int) ([]models.PropertyArchive, error) {
+	if limit == 0 {
+		limit = DefaultArchiveList
+	}
+	if limit < 1 || limit > MaxArchiveList {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxArchiveList)
+	}
+	return s.repo.List(ctx, limit)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func newArchiveTestService(t *testing.T, ctrl *gomock.Controller) (*ArchiveService, *mocks.MockArchiveRepository, *mocks.MockAmenityRepository, *recordingPublisher) {
+	repo := mocks.NewMockArchiveRepository(ctrl)
+	amenities := mocks.NewMockAmenityRepository(ctrl)
+	publisher := &recordingPublisher{}
+	service := NewArchiveService(repo, amenities, staticSettings{SettingArchiveAfterYears: "3"}, publisher,
+		t.TempDir(), filepath.Join(t.TempDir(), "archive"))
+	return service, repo, amenities, publisher
+}
+
+func TestArchiveService_ArchiveAndUnarchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo, amenities, publisher := newArchiveTestService(t, ctrl)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	for name, content := range map[string]string{"front.jpg": "front", "shared.jpg": "shared"} {
+		if err := os.WriteFile(filepath.Join(service.imagesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	property := models.Property{
+		ID: 7, PublicID: "0b5c7f4e-8d4a-4a53-9a0e-4b9a4f6f1c11", Name: "Old House", Location: "1 Main St, Austin, TX",
+		Price: 250000, Status: models.PropertyStatusSold, ListingType: models.ListingTypeSale, Version: 4,
+		Photos: models.PhotoList{
+			{URL: "https://mls.example.com/front.jpg", LocalURL: "/images/front.jpg"},
+			{URL: "https://mls.example.com/shared.jpg", LocalURL: "/images/shared.jpg"},
+			{URL: "https://mls.example.com/missing.jpg", LocalURL: "/images/missing.jpg"},
+		},
+	}
+	property.LivingAreaSqm.Float64, property.LivingAreaSqm.Valid = 120, true
+	hvac := models.NullString{}
+	hvac.String, hvac.Valid = "central", true
+	stored := &models.Amenities{PropertyID: 7, HasPool: true, HVACType: hvac}
+
+	var archived *models.PropertyArchive
+	repo.EXPECT().FindCandidates(gomock.Any(), now.AddDate(-3, 0, 0), archiveBatchSize).Return([]models.Property{property}, nil)
+	amenities.EXPECT().GetByPropertyID(gomock.Any(), 7).Return(stored, nil)
+	repo.EXPECT().Archive(gomock.Any(), gomock.Any(), 4).DoAndReturn(func(ctx context.Context, archive *models.PropertyArchive, version int) (bool, error) {
+		archived = archive
+		return true, nil
+	})
+	repo.EXPECT().SharedPhotos(gomock.Any(), 7, []string{"/images/front.jpg", "/images/shared.jpg"}).
+		Return(map[string]bool{"/images/shared.jpg": true}, nil)
+
+	run, err := service.ArchiveDue(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if run.Archived != 1 || run.Skipped != 0 || run.Bytes == 0 || run.Bytes != archived.ArchiveBytes {
+		t.Fatalf("Unexpected run %+v for archive %+v", run, archived)
+	}
+	if archived.PhotoCount != 3 || archived.ArchivedAt != now || archived.Status != models.PropertyStatusSold {
+		t.Errorf("Unexpected archive row %+v", archived)
+	}
+	if _, err := os.Stat(filepath.Join(service.imagesDir, "front.jpg")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the archived photo to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(service.imagesDir, "shared.jpg")); err != nil {
+		t.Errorf("Expected the shared photo to stay, got %v", err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.PropertyDeleted {
+		t.Fatalf("Expected a property.deleted event, got %+v", publisher.events)
+	}
+
+	var restored *models.Property
+	repo.EXPECT().Get(gomock.Any(), "7").Return(archived, nil)
+	repo.EXPECT().Restore(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, property *models.Property) error {
+		restored = property
+		return nil
+	})
+	amenities.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, got *models.Amenities) error {
+		if got.PropertyID != 7 || !got.HasPool || got.HVACType.String != "central" {
+			t.Errorf("Unexpected amenities %+v", got)
+		}
+		return nil
+	})
+
+	if _, err := service.Unarchive(context.Background(), "7"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if restored.ID != 7 || restored.PublicID != property.PublicID || len(restored.Photos) != 3 ||
+		restored.LivingAreaSqm.Float64 != 120 {
+		t.Errorf("Unexpected restored listing %+v", restored)
+	}
+	if content, err := os.ReadFile(filepath.Join(service.imagesDir, "front.jpg")); err != nil || string(content) != "front" {
+		t.Errorf("Expected the photo to be restored, got %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(service.archiveDir, archived.ArchivePath)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the archive to be removed, got %v", err)
+	}
+	if len(publisher.events) != 2 || publisher.events[1].Type != events.PropertyCreated {
+		t.Errorf("Expected a property.created event, got %+v", publisher.events)
+	}
+}
+
+func TestArchiveService_ArchiveSkipsChangedListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo, amenities, publisher := newArchiveTestService(t, ctrl)
+	property := models.Property{ID: 3, PublicID: "5f0e2a8c-3b1d-4a57-8c1e-2d7b9e4f6a22", Status: models.PropertyStatusWithdrawn, Version: 2}
+
+	repo.EXPECT().FindCandidates(gomock.Any(), gomock.Any(), archiveBatchSize).Return([]models.Property{property}, nil)
+	amenities.EXPECT().GetByPropertyID(gomock.Any(), 3).Return(nil, nil)
+	repo.EXPECT().Archive(gomock.Any(), gomock.Any(), 2).Return(false, nil)
+
+	run, err := service.ArchiveDue(context.Background())
+	if err != nil || run.Archived != 0 || run.Skipped != 1 {
+		t.Fatalf("Expected one skipped listing, got %+v, %v", run, err)
+	}
+	if entries, _ := os.ReadDir(service.archiveDir); len(entries) != 0 {
+		t.Errorf("Expected the unused archive to be removed, got %d files", len(entries))
+	}
+	if len(publisher.events) != 0 {
+		t.Errorf("Expected no events, got %+v", publisher.events)
+	}
+}
+
+func TestArchiveService_UnarchiveNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo, _, _ := newArchiveTestService(t, ctrl)
+	repo.EXPECT().Get(gomock.Any(), "99").Return(nil, nil)
+
+	if _, err := service.Unarchive(context.Background(), "99"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected a not-found error, got %v", err)
+	}
+}
+
+func TestArchiveService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo, _, _ := newArchiveTestService(t, ctrl)
+	repo.EXPECT().List(gomock.Any(), DefaultArchiveList).Return([]models.PropertyArchive{}, nil)
+
+	if _, err := service.List(context.Background(), 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.List(context.Background(), MaxArchiveList+1); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
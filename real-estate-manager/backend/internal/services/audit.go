@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/auditlog"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// auditSinkTimeout bounds forwarding an entry to one sink
+const auditSinkTimeout = 10 * time.Second
+
+// AuditService records security-relevant actions such as impersonation and
+// logins
+type AuditService struct {
+	repo  repository.AuditRepository
+	sinks []auditlog.Sink
+	// forwarding tracks entries still being sent to sinks
+	forwarding sync.WaitGroup
+}
+
+// NewAuditService creates the service. Stored entries are also forwarded
+// to sinks, such as a SIEM.
+func NewAuditService(repo repository.AuditRepository, sinks ...auditlog.Sink) *AuditService {
+	return &AuditService{repo: repo, sinks: sinks}
+}
+
+// Record stores an audit entry and forwards it to the sinks in the
+// background, so a slow sink does not delay the request. Sink failures are
+// logged; the audit_log table remains the record.
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditEntry) error {
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return err
+	}
+	if len(s.sinks) == 0 {
+		return nil
+	}
+
+	forwarded := *entry
+	if forwarded.CreatedAt.IsZero() {
+		forwarded.CreatedAt = time.Now()
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, sink := range s.sinks {
+		s.forwarding.Add(1)
+		go func(sink auditlog.Sink) {
+			defer s.forwarding.Done()
+			sinkCtx, cancel := context.WithTimeout(ctx, auditSinkTimeout)
+			defer cancel()
+			if err := sink.Write(sinkCtx, forwarded); err != nil {
+				log.Printf("Failed to forward audit entry %d (%s) to %T: %v", forwarded.ID, forwarded.Action, sink, err)
+			}
+		}(sink)
+	}
+	return nil
+}
+
+// Flush waits for entries still being forwarded, e.g. before shutting down
+func (s *AuditService) Flush() {
+	s.forwarding.Wait()
+}
+
+// List returns matching entries, newest first
+func (s *AuditService) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	return s.repo.List(ctx, filter)
+}
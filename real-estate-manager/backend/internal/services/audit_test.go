@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+// recordingSink keeps the entries forwarded to it
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []models.AuditEntry
+	err     error
+}
+
+func (s *recordingSink) Write(ctx context.Context, entry models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return s.err
+}
+
+func TestAuditService_RecordForwardsToSinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuditRepository(ctrl)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.AuditEntry) error {
+		entry.ID = 42
+		return nil
+	})
+	siem := &recordingSink{}
+	failing := &recordingSink{err: errors.New("connection refused")}
+	service := NewAuditService(repo, siem, failing)
+
+	if err := service.Record(context.Background(), &models.AuditEntry{Action: models.AuditLoginFailed}); err != nil {
+		t.Fatalf("Expected a failing sink not to fail the record, got %v", err)
+	}
+	service.Flush()
+
+	if len(siem.entries) != 1 || siem.entries[0].ID != 42 || siem.entries[0].CreatedAt.IsZero() {
+		t.Errorf("Expected the stored entry to be forwarded with its ID and time, got %+v", siem.entries)
+	}
+	if len(failing.entries) != 1 {
+		t.Errorf("Expected every sink to receive the entry, got %d", len(failing.entries))
+	}
+}
+
+func TestAuditService_RecordNotForwardedWhenStoreFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuditRepository(ctrl)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database is down"))
+	siem := &recordingSink{}
+	service := NewAuditService(repo, siem)
+
+	if err := service.Record(context.Background(), &models.AuditEntry{Action: models.AuditLoginSucceeded}); err == nil {
+		t.Fatal("Expected the store error")
+	}
+	service.Flush()
+	if len(siem.entries) != 0 {
+		t.Errorf("Expected nothing to be forwarded, got %+v", siem.entries)
+	}
+}
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"os"
 	"time"
 
+	"real-estate-manager/backend/internal/apperrors"
 	"real-estate-manager/backend/internal/models"
 	"real-estate-manager/backend/internal/repository"
 
@@ -15,26 +17,72 @@ import (
 type AuthService struct {
 	userRepo  repository.UserRepository
 	jwtSecret []byte
+	blacklist *TokenBlacklist
 }
 
+// AuthServiceOption configures optional AuthService dependencies
+type AuthServiceOption func(*AuthService)
+
+// WithTokenBlacklist refuses revoked tokens and lets tokens be revoked
+func WithTokenBlacklist(blacklist *TokenBlacklist) AuthServiceOption {
+	return func(s *AuthService) {
+		s.blacklist = blacklist
+	}
+}
+
+// sessionTTL is how long a login token is valid
+const sessionTTL = 24 * time.Hour
+
+// DevJWTSecret is published with the source, so it is only used when no
+// secret is configured and ALLOW_DEV_JWT_SECRET=true opts into it
+const DevJWTSecret = "your_default_secret_key_change_this_in_production"
+
+// ErrJWTSecretMissing is returned when no JWT secret is configured and the
+// development secret was not opted into
+var ErrJWTSecretMissing = errors.New("JWT_SECRET is not set; set ALLOW_DEV_JWT_SECRET=true to use the insecure development secret")
+
+// ResolveJWTSecret returns the configured secret, or DevJWTSecret when none
+// is configured and allowDev is set
+func ResolveJWTSecret(configured string, allowDev bool) (string, error) {
+	switch {
+	case configured != "":
+		return configured, nil
+	case allowDev:
+		return DevJWTSecret, nil
+	default:
+		return "", ErrJWTSecretMissing
+	}
+}
+
+// NewAuthService signs tokens with JWT_SECRET from the environment and
+// panics when it is missing, unless ALLOW_DEV_JWT_SECRET=true
 func NewAuthService(userRepo repository.UserRepository) *AuthService {
-	// Get JWT secret from environment variable
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		panic("JWT_SECRET environment variable is required")
+	jwtSecret, err := ResolveJWTSecret(os.Getenv("JWT_SECRET"), os.Getenv("ALLOW_DEV_JWT_SECRET") == "true")
+	if err != nil {
+		panic(err)
 	}
 
-	return &AuthService{
+	return NewAuthServiceWithSecret(userRepo, jwtSecret)
+}
+
+// NewAuthServiceWithSecret creates an AuthService signing tokens with a secret
+// resolved by the caller, e.g. from the configured secrets provider
+func NewAuthServiceWithSecret(userRepo repository.UserRepository, jwtSecret string, opts ...AuthServiceOption) *AuthService {
+	s := &AuthService{
 		userRepo:  userRepo,
 		jwtSecret: []byte(jwtSecret),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *AuthService) Register(user models.User) error {
+func (s *AuthService) Register(ctx context.Context, user models.User) error {
 	// Check if user already exists
-	existingUser, _ := s.userRepo.GetByUsername(user.Username)
+	existingUser, _ := s.userRepo.GetByUsername(ctx, user.Username)
 	if existingUser != nil {
-		return errors.New("user already exists")
+		return apperrors.Conflict("user already exists")
 	}
 
 	// Hash password
@@ -45,38 +93,115 @@ func (s *AuthService) Register(user models.User) error {
 	user.Password = string(hashedPassword)
 
 	// Save user
-	return s.userRepo.Create(&user)
+	return s.userRepo.Create(ctx, &user)
 }
 
-func (s *AuthService) Login(username, password string) (string, error) {
+func (s *AuthService) Login(ctx context.Context, username, password string) (string, error) {
 	// Get user by username
-	user, err := s.userRepo.GetByUsername(username)
+	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		// An abandoned request is not a failed login
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", apperrors.Unauthorized("invalid credentials")
 	}
 
 	// Check password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", errors.New("invalid credentials")
+		return "", apperrors.Unauthorized("invalid credentials")
+	}
+
+	return s.signToken(userClaims(user, sessionTTL))
+}
+
+// userClaims builds the identity claims for user, expiring after ttl
+func userClaims(user *models.User, ttl time.Duration) jwt.MapClaims {
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
 	}
 
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"user_id":  user.ID,
 		"username": user.Username,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
+		"role":     role,
+		"exp":      time.Now().Add(ttl).Unix(),
 		"iat":      time.Now().Unix(),
-	})
+	}
+	if user.OrganizationID.Valid {
+		claims["org_id"] = user.OrganizationID.Int32
+	}
+	return claims
+}
 
-	tokenString, err := token.SignedString(s.jwtSecret)
+func (s *AuthService) signToken(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.jwtSecret)
+}
+
+// ValidateToken checks a token's signature and expiry, and that it was not
+// revoked
+func (s *AuthService) ValidateToken(tokenString string) (*jwt.MapClaims, error) {
+	claims, err := s.parseToken(tokenString)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if s.blacklist != nil && s.blacklist.Contains(tokenString) {
+		return nil, apperrors.Unauthorized("token has been revoked")
+	}
+	// A token without an issue time counts as issued before any revocation
+	// of its user's tokens
+	if userID, ok := (*claims)["user_id"].(float64); ok && s.blacklist != nil {
+		issuedAt, _ := (*claims)["iat"].(float64)
+		if s.blacklist.ContainsUser(uint(userID), int64(issuedAt)) {
+			return nil, apperrors.Unauthorized("token has been revoked")
+		}
 	}
+	return claims, nil
+}
 
-	return tokenString, nil
+// RevokeUserTokens refuses every token issued to the user so far, e.g. once
+// their password was reset. Tokens issued afterwards are unaffected.
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userID uint) error {
+	if s.blacklist == nil {
+		return errors.New("token revocation is not configured")
+	}
+	return s.blacklist.RevokeUser(ctx, userID)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*jwt.MapClaims, error) {
+// RevokeToken blacklists a valid token until it expires, for logging out
+// or killing a compromised token. The caller in ctx is recorded as having
+// revoked it. Revoking a token twice is not an error.
+func (s *AuthService) RevokeToken(ctx context.Context, tokenString string) (*models.RevokedToken, error) {
+	if s.blacklist == nil {
+		return nil, errors.New("token revocation is not configured")
+	}
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, apperrors.Validation("the token is invalid or has expired")
+	}
+	// Tokens are always issued with an expiry
+	exp, ok := (*claims)["exp"].(float64)
+	if !ok {
+		return nil, apperrors.Validation("the token has no expiry")
+	}
+
+	revoked := &models.RevokedToken{TokenHash: hashToken(tokenString), ExpiresAt: time.Unix(int64(exp), 0).UTC()}
+	if userID, ok := (*claims)["user_id"].(float64); ok && userID > 0 {
+		revoked.UserID = nullID(int(userID))
+	}
+	if actorID, ok := ActorFromContext(ctx); ok {
+		revoked.RevokedBy = nullID(int(actorID))
+	}
+	if err := s.blacklist.Add(ctx, revoked); err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// parseToken checks a token's signature and expiry
+func (s *AuthService) parseToken(tokenString string) (*jwt.MapClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -86,12 +211,12 @@ func (s *AuthService) ValidateToken(tokenString string) (*jwt.MapClaims, error)
 	})
 
 	if err != nil || !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, apperrors.Unauthorized("invalid token")
 	}
 
 	claims, ok := token.Claims.(*jwt.MapClaims)
 	if !ok {
-		return nil, errors.New("invalid token claims")
+		return nil, apperrors.Unauthorized("invalid token claims")
 	}
 
 	return claims, nil
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"os"
 	"testing"
@@ -41,12 +42,12 @@ func TestAuthService_Register(t *testing.T) {
 			setupMock: func() {
 				// User doesn't exist
 				mockUserRepo.EXPECT().
-					GetByUsername("testuser").
+					GetByUsername(gomock.Any(), "testuser").
 					Return(nil, errors.New("user not found"))
 				
 				// Create user successfully
 				mockUserRepo.EXPECT().
-					Create(gomock.Any()).
+					Create(gomock.Any(), gomock.Any()).
 					Return(nil)
 			},
 			expectedError: false,
@@ -65,7 +66,7 @@ func TestAuthService_Register(t *testing.T) {
 					Email:    "existing@example.com",
 				}
 				mockUserRepo.EXPECT().
-					GetByUsername("existinguser").
+					GetByUsername(gomock.Any(), "existinguser").
 					Return(existingUser, nil)
 			},
 			expectedError: true,
@@ -81,12 +82,12 @@ func TestAuthService_Register(t *testing.T) {
 			setupMock: func() {
 				// User doesn't exist
 				mockUserRepo.EXPECT().
-					GetByUsername("testuser").
+					GetByUsername(gomock.Any(), "testuser").
 					Return(nil, errors.New("user not found"))
 				
 				// Create user fails
 				mockUserRepo.EXPECT().
-					Create(gomock.Any()).
+					Create(gomock.Any(), gomock.Any()).
 					Return(errors.New("database error"))
 			},
 			expectedError: true,
@@ -99,7 +100,7 @@ func TestAuthService_Register(t *testing.T) {
 			tt.setupMock()
 			
 			authService := NewAuthService(mockUserRepo)
-			err := authService.Register(tt.user)
+			err := authService.Register(context.Background(), tt.user)
 
 			if tt.expectedError {
 				if err == nil {
@@ -152,7 +153,7 @@ func TestAuthService_Login(t *testing.T) {
 					Email:    "test@example.com",
 				}
 				mockUserRepo.EXPECT().
-					GetByUsername("testuser").
+					GetByUsername(gomock.Any(), "testuser").
 					Return(user, nil)
 			},
 			expectedError: false,
@@ -164,7 +165,7 @@ func TestAuthService_Login(t *testing.T) {
 			password: "password123",
 			setupMock: func() {
 				mockUserRepo.EXPECT().
-					GetByUsername("nonexistent").
+					GetByUsername(gomock.Any(), "nonexistent").
 					Return(nil, errors.New("user not found"))
 			},
 			expectedError: true,
@@ -182,7 +183,7 @@ func TestAuthService_Login(t *testing.T) {
 					Email:    "test@example.com",
 				}
 				mockUserRepo.EXPECT().
-					GetByUsername("testuser").
+					GetByUsername(gomock.Any(), "testuser").
 					Return(user, nil)
 			},
 			expectedError: true,
@@ -195,7 +196,7 @@ func TestAuthService_Login(t *testing.T) {
 			tt.setupMock()
 			
 			authService := NewAuthService(mockUserRepo)
-			token, err := authService.Login(tt.username, tt.password)
+			token, err := authService.Login(context.Background(), tt.username, tt.password)
 
 			if tt.expectedError {
 				if err == nil {
@@ -217,6 +218,21 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
+func TestAuthService_Login_Abandoned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockUserRepo.EXPECT().GetByUsername(gomock.Any(), "alice").Return(nil, context.Canceled)
+
+	_, err := NewAuthServiceWithSecret(mockUserRepo, "test_secret_key_for_testing_purposes").Login(ctx, "alice", "secret")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected an abandoned login to return context.Canceled, not invalid credentials; got %v", err)
+	}
+}
+
 func TestAuthService_ValidateToken(t *testing.T) {
 	// Set up test JWT secret
 	testSecret := "test_secret_key_for_testing_purposes"
@@ -333,6 +349,7 @@ func TestNewAuthService(t *testing.T) {
 		setupEnv      func()
 		cleanupEnv    func()
 		expectedSecret string
+		expectPanic   bool
 	}{
 		{
 			name: "with JWT_SECRET environment variable",
@@ -350,7 +367,18 @@ func TestNewAuthService(t *testing.T) {
 				os.Unsetenv("JWT_SECRET")
 			},
 			cleanupEnv: func() {},
-			expectedSecret: "your_default_secret_key_change_this_in_production",
+			expectPanic: true,
+		},
+		{
+			name: "development secret opted into",
+			setupEnv: func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Setenv("ALLOW_DEV_JWT_SECRET", "true")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("ALLOW_DEV_JWT_SECRET")
+			},
+			expectedSecret: DevJWTSecret,
 		},
 	}
 
@@ -359,6 +387,13 @@ func TestNewAuthService(t *testing.T) {
 			tt.setupEnv()
 			defer tt.cleanupEnv()
 
+			if tt.expectPanic {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("expected NewAuthService to panic without a secret")
+					}
+				}()
+			}
 			authService := NewAuthService(mockUserRepo)
 
 			if authService == nil {
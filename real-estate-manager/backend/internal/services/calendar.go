@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/calendar"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// calendarStateTTL is how long an agent has to grant calendar access
+const calendarStateTTL = 10 * time.Minute
+
+// calendarRefreshDelta refreshes access tokens this long before they expire
+const calendarRefreshDelta = time.Minute
+
+// CalendarService connects agents' Google and Outlook calendars and reads
+// and writes them on their behalf
+type CalendarService struct {
+	providers map[string]calendar.Provider
+	names     []string
+	repo      repository.CalendarRepository
+	secret    []byte
+	// baseURL is the backend's public address, which providers redirect
+	// back to
+	baseURL string
+	now     func() time.Time
+}
+
+// NewCalendarService creates the service. stateSecret signs the OAuth state
+// so a callback can only complete a flow the backend started.
+func NewCalendarService(providers []calendar.Provider, repo repository.CalendarRepository, stateSecret, baseURL string) *CalendarService {
+	s := &CalendarService{
+		providers: make(map[string]calendar.Provider),
+		names:     []string{},
+		repo:      repo,
+		secret:    []byte(stateSecret),
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		now:       time.Now,
+	}
+	for _, provider := range providers {
+		s.providers[provider.Name()] = provider
+		s.names = append(s.names, provider.Name())
+	}
+	sort.Strings(s.names)
+	return s
+}
+
+// Providers returns the names of the configured calendar providers
+func (s *CalendarService) Providers() []string {
+	return s.names
+}
+
+// Connections returns the calendars a user connected
+func (s *CalendarService) Connections(ctx context.Context, userID uint) ([]models.CalendarConnection, error) {
+	return s.repo.List(ctx, userID)
+}
+
+// AuthURL returns the provider page where the user grants calendar access
+func (s *CalendarService) AuthURL(userID uint, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", apperrors.Validationf("unknown calendar provider %q", providerName)
+	}
+	return provider.AuthCodeURL(s.signState(userID, providerName), s.redirectURL(providerName)), nil
+}
+
+// Connect completes the authorization the provider redirected back with and
+// stores the user's tokens. It returns the user the calendar belongs to.
+func (s *CalendarService) Connect(ctx context.Context, providerName, code, state string) (uint, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return 0, apperrors.NotFound("unknown calendar provider")
+	}
+	userID, ok := s.verifyState(state, providerName)
+	if !ok {
+		return 0, apperrors.Validation("invalid or expired authorization state; connect the calendar again")
+	}
+	if code == "" {
+		return 0, apperrors.Validation("calendar access was not granted")
+	}
+
+	token, err := provider.Exchange(ctx, code, s.redirectURL(providerName))
+	if errors.Is(err, calendar.ErrUnauthorized) {
+		return 0, apperrors.Validation("the authorization code was rejected; connect the calendar again")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect %s calendar: %w", providerName, err)
+	}
+	connection := &models.CalendarConnection{UserID: userID, Provider: providerName}
+	setToken(connection, token)
+	if err := s.repo.Save(ctx, connection); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// Disconnect forgets a user's calendar connection
+func (s *CalendarService) Disconnect(ctx context.Context, userID uint, providerName string) error {
+	exists, err := s.repo.Delete(ctx, userID, providerName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("calendar not connected")
+	}
+	return nil
+}
+
+// Busy returns the events in a user's connected calendars that overlap the
+// period from start to end. A calendar that cannot be read fails the check
+// rather than risk a double booking.
+func (s *CalendarService) Busy(ctx context.Context, userID uint, start, end time.Time) ([]models.ShowingConflict, error) {
+	connections, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.ShowingConflict
+	for i := range connections {
+		connection := &connections[i]
+		provider, ok := s.providers[connection.Provider]
+		if !ok {
+			continue
+		}
+		token, err := s.accessToken(ctx, provider, connection)
+		if err != nil {
+			return nil, err
+		}
+		busy, err := provider.Busy(ctx, token, start, end)
+		if errors.Is(err, calendar.ErrUnauthorized) {
+			return nil, apperrors.Conflictf("access to the %s calendar expired; connect it again", connection.Provider)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s calendar: %w", connection.Provider, err)
+		}
+		for _, period := range busy {
+			if period.Start.Before(end) && period.End.After(start) {
+				conflicts = append(conflicts, models.ShowingConflict{Source: connection.Provider, Start: period.Start, End: period.End})
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// AddEvent adds an event to every calendar the user connected and returns
+// the providers it was added to. Failures are logged; the event is still
+// in the other calendars.
+func (s *CalendarService) AddEvent(ctx context.Context, userID uint, event calendar.Event) []string {
+	connections, err := s.repo.List(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get calendars of user %d: %v", userID, err)
+		return nil
+	}
+
+	added := []string{}
+	for i := range connections {
+		connection := &connections[i]
+		provider, ok := s.providers[connection.Provider]
+		if !ok {
+			continue
+		}
+		token, err := s.accessToken(ctx, provider, connection)
+		if err == nil {
+			_, err = provider.CreateEvent(ctx, token, event)
+		}
+		if err != nil {
+			log.Printf("Failed to add event to %s calendar of user %d: %v", connection.Provider, userID, err)
+			continue
+		}
+		added = append(added, connection.Provider)
+	}
+	return added
+}
+
+// accessToken returns a connection's access token, refreshing and saving
+// it first when it is about to expire
+func (s *CalendarService) accessToken(ctx context.Context, provider calendar.Provider, connection *models.CalendarConnection) (string, error) {
+	if connection.ExpiresAt == nil || connection.RefreshToken == "" || s.now().Add(calendarRefreshDelta).Before(*connection.ExpiresAt) {
+		return connection.AccessToken, nil
+	}
+
+	token, err := provider.Refresh(ctx, connection.RefreshToken)
+	if errors.Is(err, calendar.ErrUnauthorized) {
+		return "", apperrors.Conflictf("access to the %s calendar expired; connect it again", connection.Provider)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh %s calendar access: %w", connection.Provider, err)
+	}
+	setToken(connection, token)
+	if err := s.repo.Save(ctx, connection); err != nil {
+		return "", err
+	}
+	return connection.AccessToken, nil
+}
+
+// setToken stores a token on a connection, keeping the refresh token when
+// the provider did not issue a new one
+func setToken(connection *models.CalendarConnection, token *calendar.Token) {
+	connection.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		connection.RefreshToken = token.RefreshToken
+	}
+	connection.ExpiresAt = nil
+	if !token.Expiry.IsZero() {
+		expiry := token.Expiry
+		connection.ExpiresAt = &expiry
+	}
+}
+
+func (s *CalendarService) redirectURL(providerName string) string {
+	return s.baseURL + "/api/calendar/" + providerName + "/callback"
+}
+
+// signState encodes the user, provider and expiry of an authorization
+// request with an HMAC, as "<payload>.<signature>"
+func (s *CalendarService) signState(userID uint, providerName string) string {
+	payload := fmt.Sprintf("%d:%s:%d", userID, providerName, s.now().Add(calendarStateTTL).Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.stateSignature(encoded)
+}
+
+func (s *CalendarService) verifyState(state, providerName string) (uint, bool) {
+	encoded, signature, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.stateSignature(encoded))) {
+		return 0, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, false
+	}
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 3 || parts[1] != providerName {
+		return 0, false
+	}
+	userID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	expiry, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || s.now().Unix() > expiry {
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func (s *CalendarService) stateSignature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("calendar-state:" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
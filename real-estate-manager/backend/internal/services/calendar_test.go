@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/calendar"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakeCalendar is a calendar provider backed by a list of busy periods
+type fakeCalendar struct {
+	busy      []calendar.Interval
+	busyErr   error
+	refreshed string
+	created   []calendar.Event
+	tokens    []string
+}
+
+func (p *fakeCalendar) Name() string { return calendar.Google }
+
+func (p *fakeCalendar) AuthCodeURL(state, redirectURL string) string {
+	return "https://auth.example.com/?" + url.Values{"state": {state}, "redirect_uri": {redirectURL}}.Encode()
+}
+
+func (p *fakeCalendar) Exchange(ctx context.Context, code, redirectURL string) (*calendar.Token, error) {
+	if code != "code-1" {
+		return nil, calendar.ErrUnauthorized
+	}
+	return &calendar.Token{AccessToken: "access-1", RefreshToken: "refresh-1"}, nil
+}
+
+func (p *fakeCalendar) Refresh(ctx context.Context, refreshToken string) (*calendar.Token, error) {
+	p.refreshed = refreshToken
+	return &calendar.Token{AccessToken: "access-2"}, nil
+}
+
+func (p *fakeCalendar) Busy(ctx context.Context, accessToken string, start, end time.Time) ([]calendar.Interval, error) {
+	p.tokens = append(p.tokens, accessToken)
+	return p.busy, p.busyErr
+}
+
+func (p *fakeCalendar) CreateEvent(ctx context.Context, accessToken string, event calendar.Event) (string, error) {
+	p.created = append(p.created, event)
+	return "event-1", nil
+}
+
+func TestCalendarService_Connect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepository(ctrl)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, connection *models.CalendarConnection) error {
+		if connection.UserID != 7 || connection.Provider != calendar.Google || connection.AccessToken != "access-1" || connection.RefreshToken != "refresh-1" {
+			t.Errorf("Unexpected connection %+v", connection)
+		}
+		return nil
+	})
+
+	service := NewCalendarService([]calendar.Provider{&fakeCalendar{}}, mockRepo, "secret", "https://api.example.com/")
+	authURL, err := service.AuthURL(7, calendar.Google)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	state := parsed.Query().Get("state")
+	if redirect := parsed.Query().Get("redirect_uri"); redirect != "https://api.example.com/api/calendar/google/callback" {
+		t.Errorf("Unexpected redirect URL %q", redirect)
+	}
+
+	userID, err := service.Connect(context.Background(), calendar.Google, "code-1", state)
+	if err != nil || userID != 7 {
+		t.Fatalf("Expected user 7 to be connected, got %d, %v", userID, err)
+	}
+
+	tampered := state[:len(state)-1] + "x"
+	if _, err := service.Connect(context.Background(), calendar.Google, "code-1", tampered); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a tampered state to be rejected, got %v", err)
+	}
+	service.now = func() time.Time { return time.Now().Add(calendarStateTTL + time.Minute) }
+	if _, err := service.Connect(context.Background(), calendar.Google, "code-1", state); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected an expired state to be rejected, got %v", err)
+	}
+}
+
+func TestCalendarService_Busy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start := time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC)
+	expired := start.Add(-time.Hour)
+	provider := &fakeCalendar{busy: []calendar.Interval{
+		{Start: start.Add(30 * time.Minute), End: start.Add(90 * time.Minute)},
+		// Ends exactly when the showing starts
+		{Start: start.Add(-time.Hour), End: start},
+	}}
+
+	mockRepo := mocks.NewMockCalendarRepository(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), uint(7)).Return([]models.CalendarConnection{
+		{UserID: 7, Provider: calendar.Google, AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: &expired},
+		{UserID: 7, Provider: calendar.Outlook, AccessToken: "unconfigured"},
+	}, nil)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, connection *models.CalendarConnection) error {
+		if connection.AccessToken != "access-2" || connection.RefreshToken != "refresh-1" || connection.ExpiresAt != nil {
+			t.Errorf("Expected the refreshed token to keep the refresh token, got %+v", connection)
+		}
+		return nil
+	})
+
+	service := NewCalendarService([]calendar.Provider{provider}, mockRepo, "secret", "https://api.example.com")
+	service.now = func() time.Time { return start.Add(-30 * time.Minute) }
+	conflicts, err := service.Busy(context.Background(), 7, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if provider.refreshed != "refresh-1" || len(provider.tokens) != 1 || provider.tokens[0] != "access-2" {
+		t.Errorf("Expected the expired token to be refreshed before use, got refresh %q and tokens %v", provider.refreshed, provider.tokens)
+	}
+	if len(conflicts) != 1 || conflicts[0].Source != calendar.Google || !conflicts[0].Start.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("Expected one overlapping event, got %+v", conflicts)
+	}
+}
+
+func TestCalendarService_Busy_Revoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCalendarRepository(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), uint(7)).Return([]models.CalendarConnection{
+		{UserID: 7, Provider: calendar.Google, AccessToken: "revoked"},
+	}, nil)
+
+	service := NewCalendarService([]calendar.Provider{&fakeCalendar{busyErr: calendar.ErrUnauthorized}}, mockRepo, "secret", "")
+	_, err := service.Busy(context.Background(), 7, time.Now(), time.Now().Add(time.Hour))
+	if apperrors.HTTPStatus(err) != 409 {
+		t.Errorf("Expected a conflict asking to reconnect, got %v", err)
+	}
+}
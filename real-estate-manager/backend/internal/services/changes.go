@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Page sizes of the changes feed
+const (
+	DefaultChangesLimit = 500
+	MaxChangesLimit     = 1000
+)
+
+// ChangeService serves the incremental sync feed of properties, photos and
+// agents, including tombstones for deletions
+type ChangeService struct {
+	repo repository.ChangeRepository
+}
+
+func NewChangeService(repo repository.ChangeRepository) *ChangeService {
+	return &ChangeService{repo: repo}
+}
+
+// maxClientIDLength matches sync_clients.client_id
+const maxClientIDLength = 64
+
+// ChangesQuery selects where a page of the feed starts: after an opaque
+// Cursor from a previous page, at a Since timestamp, or from the beginning
+// when neither is set.
+//
+// ClientID names the calling device. A cursor sent with it is stored as the
+// device's sync token, acknowledging everything before it; a later request
+// with neither Cursor nor Since resumes from that token, so a reinstalled
+// or restored app does not need to start over.
+type ChangesQuery struct {
+	Cursor   string
+	Since    *time.Time
+	Limit    int
+	ClientID string
+}
+
+// List returns the next page of changes. The returned cursor resumes after
+// the last change, or where this page started when it is empty, so clients
+// can always store it for the next sync.
+func (s *ChangeService) List(ctx context.Context, query ChangesQuery) (*models.ChangesPage, error) {
+	if query.Cursor != "" && query.Since != nil {
+		return nil, apperrors.Validation("use either cursor or since, not both")
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultChangesLimit
+	}
+	if query.Limit < 1 || query.Limit > MaxChangesLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxChangesLimit)
+	}
+
+	if query.ClientID != "" {
+		if err := s.syncClientToken(ctx, &query); err != nil {
+			return nil, err
+		}
+	}
+
+	var after models.ChangePosition
+	switch {
+	case query.Cursor != "":
+		position, err := decodeChangeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = position
+	case query.Since != nil:
+		// A source below every real one includes changes made exactly at Since
+		after = models.ChangePosition{At: *query.Since, Source: -1}
+	}
+
+	// One extra change tells whether another page follows
+	changes, err := s.repo.List(ctx, after, query.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ChangesPage{Changes: changes}
+	if len(changes) > query.Limit {
+		page.Changes = changes[:query.Limit]
+		page.HasMore = true
+	}
+	if page.Changes == nil {
+		page.Changes = []models.Change{}
+	}
+	if n := len(page.Changes); n > 0 {
+		after = page.Changes[n-1].Position
+	}
+	page.Cursor = encodeChangeCursor(after)
+	return page, nil
+}
+
+// syncClientToken stores the cursor a client acknowledged, or fills in its
+// stored token when the query does not say where to start
+func (s *ChangeService) syncClientToken(ctx context.Context, query *ChangesQuery) error {
+	if len(query.ClientID) > maxClientIDLength {
+		return apperrors.Validationf("client_id must be at most %d characters", maxClientIDLength)
+	}
+	userID, ok := ActorFromContext(ctx)
+	if !ok {
+		return apperrors.Unauthorized("client sync tokens require an authenticated user")
+	}
+
+	switch {
+	case query.Cursor != "":
+		if _, err := decodeChangeCursor(query.Cursor); err != nil {
+			return err
+		}
+		return s.repo.SaveSyncToken(ctx, int(userID), query.ClientID, query.Cursor)
+	case query.Since == nil:
+		token, err := s.repo.GetSyncToken(ctx, int(userID), query.ClientID)
+		if err != nil {
+			return err
+		}
+		query.Cursor = token
+	}
+	return nil
+}
+
+// encodeChangeCursor serializes a feed position as an opaque token
+func encodeChangeCursor(position models.ChangePosition) string {
+	raw := fmt.Sprintf("1:%d:%d:%d", position.At.UnixMicro(), position.Source, position.Key)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeChangeCursor(cursor string) (models.ChangePosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return models.ChangePosition{}, apperrors.Validation("invalid cursor")
+	}
+
+	var micros int64
+	var position models.ChangePosition
+	if _, err := fmt.Sscanf(string(raw), "1:%d:%d:%d", &micros, &position.Source, &position.Key); err != nil {
+		return models.ChangePosition{}, apperrors.Validation("invalid cursor")
+	}
+	position.At = time.UnixMicro(micros)
+	return position, nil
+}
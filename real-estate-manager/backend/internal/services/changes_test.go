@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangeService_List(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	change := func(key int) models.Change {
+		position := models.ChangePosition{At: at, Source: 0, Key: key}
+		return models.Change{Entity: models.ChangeEntityProperty, ID: key, Operation: models.ChangeUpsert, Position: position}
+	}
+	resume := encodeChangeCursor(models.ChangePosition{At: at, Source: 3, Key: 8})
+
+	tests := []struct {
+		name         string
+		query        ChangesQuery
+		setupMock    func(mock *mocks.MockChangeRepository)
+		expectKind   error
+		expectCount  int
+		expectMore   bool
+		expectCursor models.ChangePosition
+	}{
+		{
+			name:  "full sync with more pages",
+			query: ChangesQuery{Limit: 2},
+			setupMock: func(mock *mocks.MockChangeRepository) {
+				mock.EXPECT().List(gomock.Any(), models.ChangePosition{}, 3).
+					Return([]models.Change{change(1), change(2), change(3)}, nil)
+			},
+			expectCount:  2,
+			expectMore:   true,
+			expectCursor: models.ChangePosition{At: at, Key: 2},
+		},
+		{
+			name:  "resumes from cursor",
+			query: ChangesQuery{Cursor: resume},
+			setupMock: func(mock *mocks.MockChangeRepository) {
+				mock.EXPECT().List(gomock.Any(), gomock.Any(), DefaultChangesLimit+1).
+					DoAndReturn(func(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+						if !after.At.Equal(at) || after.Source != 3 || after.Key != 8 {
+							t.Errorf("Unexpected position %+v", after)
+						}
+						return nil, nil
+					})
+			},
+			expectCursor: models.ChangePosition{At: at, Source: 3, Key: 8},
+		},
+		{
+			name:  "since includes changes at that time",
+			query: ChangesQuery{Since: &at},
+			setupMock: func(mock *mocks.MockChangeRepository) {
+				mock.EXPECT().List(gomock.Any(), models.ChangePosition{At: at, Source: -1}, DefaultChangesLimit+1).
+					Return([]models.Change{change(4)}, nil)
+			},
+			expectCount:  1,
+			expectCursor: models.ChangePosition{At: at, Key: 4},
+		},
+		{
+			name:       "cursor and since are exclusive",
+			query:      ChangesQuery{Cursor: resume, Since: &at},
+			setupMock:  func(*mocks.MockChangeRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:       "malformed cursor",
+			query:      ChangesQuery{Cursor: "not-a-cursor"},
+			setupMock:  func(*mocks.MockChangeRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:       "limit too large",
+			query:      ChangesQuery{Limit: MaxChangesLimit + 1},
+			setupMock:  func(*mocks.MockChangeRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockChangeRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			page, err := NewChangeService(mockRepo).List(context.Background(), tt.query)
+			if tt.expectKind != nil {
+				if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(page.Changes) != tt.expectCount || page.HasMore != tt.expectMore {
+				t.Errorf("Expected %d changes (more: %v), got %d (more: %v)", tt.expectCount, tt.expectMore, len(page.Changes), page.HasMore)
+			}
+			cursor, err := decodeChangeCursor(page.Cursor)
+			if err != nil {
+				t.Fatalf("Returned cursor does not decode: %v", err)
+			}
+			if !cursor.At.Equal(tt.expectCursor.At) || cursor.Source != tt.expectCursor.Source || cursor.Key != tt.expectCursor.Key {
+				t.Errorf("Expected cursor %+v, got %+v", tt.expectCursor, cursor)
+			}
+		})
+	}
+}
+
+func TestChangeService_ListClientSyncToken(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	stored := encodeChangeCursor(models.ChangePosition{At: at, Source: 1, Key: 5})
+	ctx := WithActor(context.Background(), 7)
+
+	t.Run("cursor is stored as the client's sync token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockChangeRepository(ctrl)
+		mockRepo.EXPECT().SaveSyncToken(gomock.Any(), 7, "ipad", stored).Return(nil)
+		mockRepo.EXPECT().List(gomock.Any(), gomock.Any(), DefaultChangesLimit+1).Return(nil, nil)
+
+		if _, err := NewChangeService(mockRepo).List(ctx, ChangesQuery{Cursor: stored, ClientID: "ipad"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("resumes from the stored sync token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockChangeRepository(ctrl)
+		mockRepo.EXPECT().GetSyncToken(gomock.Any(), 7, "ipad").Return(stored, nil)
+		mockRepo.EXPECT().List(gomock.Any(), gomock.Any(), DefaultChangesLimit+1).
+			DoAndReturn(func(ctx context.Context, after models.ChangePosition, limit int) ([]models.Change, error) {
+				if !after.At.Equal(at) || after.Source != 1 || after.Key != 5 {
+					t.Errorf("Expected to resume from the stored token, got %+v", after)
+				}
+				return nil, nil
+			})
+
+		if _, err := NewChangeService(mockRepo).List(ctx, ChangesQuery{ClientID: "ipad"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("requires a user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, err := NewChangeService(mocks.NewMockChangeRepository(ctrl)).List(context.Background(), ChangesQuery{ClientID: "ipad"})
+		if !errors.Is(err, apperrors.ErrUnauthorized) {
+			t.Errorf("Expected unauthorized, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting an upstream whose breaker
+// is open
+var ErrCircuitOpen = errors.New("upstream unavailable: circuit open")
+
+// Breaker settings for upstream providers
+const (
+	upstreamFailureThreshold = 5
+	upstreamCooldown         = time.Minute
+)
+
+// circuitBreaker stops calling an upstream after threshold consecutive
+// failures. Once cooldown has passed a single trial request is let through;
+// its outcome closes the breaker or opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	onOpen    func(failures int)
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration, onOpen func(failures int)) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, onOpen: onOpen, now: time.Now}
+}
+
+// Allow reports whether a request may be sent
+func (b *circuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// Record reports the outcome of an allowed request
+func (b *circuitBreaker) Record(err error) {
+	b.mu.Lock()
+	if err == nil {
+		b.failures, b.trial = 0, false
+		b.mu.Unlock()
+		return
+	}
+
+	b.failures++
+	opened := b.failures == b.threshold || b.trial
+	if opened {
+		b.openedAt, b.trial = b.now(), false
+	}
+	failures := b.failures
+	b.mu.Unlock()
+
+	if opened && b.onOpen != nil {
+		b.onOpen(failures)
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+const maxContactTags = 20
+
+// ContactService manages the contact book: the buyers, sellers and vendors
+// agents work with, and their links to leads, showings and deals
+type ContactService struct {
+	repo       repository.ContactRepository
+	leads      repository.LeadRepository
+	showings   repository.ShowingRepository
+	deals      repository.DealRepository
+	properties *PropertyService
+}
+
+func NewContactService(repo repository.ContactRepository, leads repository.LeadRepository, showings repository.ShowingRepository,
+	deals repository.DealRepository, properties *PropertyService) *ContactService {
+	return &ContactService{repo: repo, leads: leads, showings: showings, deals: deals, properties: properties}
+}
+
+// List returns the contacts matching filter, by name
+func (s *ContactService) List(ctx context.Context, filter models.ContactFilter) ([]models.Contact, error) {
+	if filter.Kind != "" && !slices.Contains(models.ContactKinds, filter.Kind) {
+		return nil, apperrors.Validationf("kind must be one of %s", strings.Join(models.ContactKinds, ", "))
+	}
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	filter.Q = strings.TrimSpace(filter.Q)
+	if (filter.EntityType == "") != (filter.EntityID == 0) {
+		return nil, apperrors.Validation("entity_type and entity_id must be given together")
+	}
+	if filter.EntityType != "" && !slices.Contains(models.ContactLinkTypes, filter.EntityType) {
+		return nil, apperrors.Validationf("entity_type must be one of %s", strings.Join(models.ContactLinkTypes, ", "))
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// Get returns a contact with its links
+func (s *ContactService) Get(ctx context.Context, id int) (*models.Contact, error) {
+	contact, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, apperrors.NotFound("contact not found")
+	}
+	return contact, nil
+}
+
+func (s *ContactService) Create(ctx context.Context, contact *models.Contact) error {
+	if err := validateContact(contact); err != nil {
+		return err
+	}
+	contact.ID = 0
+	contact.Links = nil
+	contact.CreatedBy = models.NullInt32{}
+	if userID, ok := ActorFromContext(ctx); ok {
+		contact.CreatedBy = nullID(int(userID))
+	}
+	return s.repo.Create(ctx, contact)
+}
+
+// Update replaces a contact's details and tags
+func (s *ContactService) Update(ctx context.Context, id int, changes *models.Contact) (*models.Contact, error) {
+	contact, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateContact(changes); err != nil {
+		return nil, err
+	}
+	contact.Kind, contact.Name, contact.Email, contact.Phone = changes.Kind, changes.Name, changes.Email, changes.Phone
+	contact.Company, contact.Notes, contact.Tags = changes.Company, changes.Notes, changes.Tags
+	if err := s.repo.Update(ctx, contact); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+func (s *ContactService) Delete(ctx context.Context, id int) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	deleted, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.NotFound("contact not found")
+	}
+	return nil
+}
+
+// Link ties a contact to a lead, showing or deal. Showings and deals must
+// be on a listing the caller can see.
+func (s *ContactService) Link(ctx context.Context, contactID int, link *models.ContactLink) error {
+	if _, err := s.Get(ctx, contactID); err != nil {
+		return err
+	}
+	if err := s.checkEntity(ctx, link.EntityType, link.EntityID); err != nil {
+		return err
+	}
+	link.ContactID = contactID
+	return s.repo.AddLink(ctx, link)
+}
+
+func (s *ContactService) Unlink(ctx context.Context, link models.ContactLink) error {
+	if _, err := s.Get(ctx, link.ContactID); err != nil {
+		return err
+	}
+	removed, err := s.repo.RemoveLink(ctx, link)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return apperrors.NotFound("contact link not found")
+	}
+	return nil
+}
+
+// checkEntity returns NotFound unless the linked entity exists and, when
+// it is on a listing, the listing is visible
+func (s *ContactService) checkEntity(ctx context.Context, entityType string, entityID int) error {
+	var propertyID int
+	switch entityType {
+	case models.ContactLinkLead:
+		lead, err := s.leads.GetByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		if lead == nil {
+			return apperrors.NotFound("lead not found")
+		}
+		propertyID = int(lead.PropertyID.Int32)
+	case models.ContactLinkShowing:
+		showing, err := s.showings.GetByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		if showing == nil {
+			return apperrors.NotFound("showing not found")
+		}
+		propertyID = showing.PropertyID
+	case models.ContactLinkDeal:
+		deal, err := s.deals.GetByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		if deal == nil {
+			return apperrors.NotFound("deal not found")
+		}
+		propertyID = deal.PropertyID
+	default:
+		return apperrors.Validationf("entity_type must be one of %s", strings.Join(models.ContactLinkTypes, ", "))
+	}
+	if propertyID == 0 {
+		return nil
+	}
+	_, err := s.properties.GetProperty(ctx, propertyID)
+	return err
+}
+
+// validateContact checks a contact's kind, name and email and normalizes
+// its tags: trimmed, lowercase and deduplicated
+func validateContact(contact *models.Contact) error {
+	if !slices.Contains(models.ContactKinds, contact.Kind) {
+		return apperrors.Validationf("kind must be one of %s", strings.Join(models.ContactKinds, ", "))
+	}
+	contact.Name = strings.TrimSpace(contact.Name)
+	if contact.Name == "" {
+		return apperrors.Validation("name is required")
+	}
+	contact.Email = strings.TrimSpace(contact.Email)
+	if contact.Email != "" {
+		if _, err := mail.ParseAddress(contact.Email); err != nil {
+			return apperrors.Validation("email must be a valid email")
+		}
+	}
+	contact.Phone = strings.TrimSpace(contact.Phone)
+	contact.Company = strings.TrimSpace(contact.Company)
+
+	tags := models.StringList{}
+	for _, tag := range contact.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len(tag) > 50 {
+			return apperrors.Validation("tags must be at most 50 characters")
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxContactTags {
+		return apperrors.Validationf("a contact can have at most %d tags", maxContactTags)
+	}
+	contact.Tags = tags
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestContactService_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		contact     models.Contact
+		expectTags  models.StringList
+		expectError bool
+	}{
+		{name: "buyer with tags", contact: models.Contact{Kind: models.ContactBuyer, Name: " Jane Roe ", Email: "jane@example.com",
+			Tags: models.StringList{"First-Time", " pre-approved", "first-time", ""}}, expectTags: models.StringList{"first-time", "pre-approved"}},
+		{name: "vendor without tags", contact: models.Contact{Kind: models.ContactVendor, Name: "Joe Pipes"}, expectTags: models.StringList{}},
+		{name: "unknown kind", contact: models.Contact{Kind: "landlord", Name: "Jane Roe"}, expectError: true},
+		{name: "no name", contact: models.Contact{Kind: models.ContactSeller, Name: " "}, expectError: true},
+		{name: "invalid email", contact: models.Contact{Kind: models.ContactSeller, Name: "Jane Roe", Email: "jane"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockContactRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, contact *models.Contact) error {
+					contact.ID = 3
+					return nil
+				})
+			}
+
+			service := NewContactService(mockRepo, nil, nil, nil, nil)
+			contact := tt.contact
+			err := service.Create(WithActor(context.Background(), 4), &contact)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if contact.ID != 3 || contact.CreatedBy.Int32 != 4 || contact.Name != strings.TrimSpace(tt.contact.Name) {
+				t.Errorf("Unexpected contact %+v", contact)
+			}
+			if !slices.Equal(contact.Tags, tt.expectTags) {
+				t.Errorf("Expected tags %v, got %v", tt.expectTags, contact.Tags)
+			}
+		})
+	}
+}
+
+// contactMocks are the repositories holding the entities contacts link to
+type contactMocks struct {
+	leads      *mocks.MockLeadRepository
+	showings   *mocks.MockShowingRepository
+	deals      *mocks.MockDealRepository
+	properties *mocks.MockPropertyRepository
+}
+
+func TestContactService_Link(t *testing.T) {
+	tests := []struct {
+		name      string
+		link      models.ContactLink
+		setup     func(m contactMocks)
+		expectErr error
+	}{
+		{name: "lead without a listing", link: models.ContactLink{EntityType: models.ContactLinkLead, EntityID: 8},
+			setup: func(m contactMocks) {
+				m.leads.EXPECT().GetByID(gomock.Any(), 8).Return(&models.Lead{ID: 8}, nil)
+			}},
+		{name: "deal", link: models.ContactLink{EntityType: models.ContactLinkDeal, EntityID: 7},
+			setup: func(m contactMocks) {
+				m.deals.EXPECT().GetByID(gomock.Any(), 7).Return(&models.Deal{ID: 7, PropertyID: 12}, nil)
+				m.properties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+			}},
+		{name: "showing on a listing the caller cannot see", link: models.ContactLink{EntityType: models.ContactLinkShowing, EntityID: 5},
+			setup: func(m contactMocks) {
+				m.showings.EXPECT().GetByID(gomock.Any(), 5).Return(&models.Showing{ID: 5, PropertyID: 12}, nil)
+				m.properties.EXPECT().GetByID(gomock.Any(), 12).Return(nil, nil)
+			}, expectErr: apperrors.ErrNotFound},
+		{name: "missing deal", link: models.ContactLink{EntityType: models.ContactLinkDeal, EntityID: 7},
+			setup: func(m contactMocks) {
+				m.deals.EXPECT().GetByID(gomock.Any(), 7).Return(nil, nil)
+			}, expectErr: apperrors.ErrNotFound},
+		{name: "unknown entity", link: models.ContactLink{EntityType: "offer", EntityID: 7}, expectErr: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockContactRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 3).Return(&models.Contact{ID: 3, Kind: models.ContactBuyer}, nil)
+			m := contactMocks{leads: mocks.NewMockLeadRepository(ctrl), showings: mocks.NewMockShowingRepository(ctrl),
+				deals: mocks.NewMockDealRepository(ctrl), properties: mocks.NewMockPropertyRepository(ctrl)}
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			if tt.expectErr == nil {
+				mockRepo.EXPECT().AddLink(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewContactService(mockRepo, m.leads, m.showings, m.deals, NewPropertyService(m.properties))
+			link := tt.link
+			err := service.Link(context.Background(), 3, &link)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if link.ContactID != 3 {
+				t.Errorf("Expected the link to be on contact 3, got %+v", link)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/crm"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// crmBatchSize caps how many leads one scheduled export handles
+const crmBatchSize = 50
+
+// crmMaxAttempts is how often a failing record is tried before it waits
+// for an admin to retry it
+const crmMaxAttempts = 5
+
+// CRMSummary describes the configured CRM and how far the export got
+type CRMSummary struct {
+	Enabled bool           `json:"enabled"`
+	CRM     string         `json:"crm,omitempty"`
+	Mapping crm.Mapping    `json:"mapping,omitempty"`
+	Counts  map[string]int `json:"counts,omitempty"`
+}
+
+// CRMService exports leads and their contacts to an external CRM on a
+// schedule and records the sync status of every record
+type CRMService struct {
+	connector  crm.Connector
+	repo       repository.CRMRepository
+	properties repository.PropertyRepository
+	users      repository.UserRepository
+	settings   SettingsProvider
+	now        func() time.Time
+}
+
+// NewCRMService creates the service; connector may be nil when no CRM is
+// configured
+func NewCRMService(connector crm.Connector, repo repository.CRMRepository, properties repository.PropertyRepository, users repository.UserRepository, settings SettingsProvider) *CRMService {
+	return &CRMService{
+		connector:  connector,
+		repo:       repo,
+		properties: properties,
+		users:      users,
+		settings:   settings,
+		now:        time.Now,
+	}
+}
+
+// Enabled reports whether a CRM is configured
+func (s *CRMService) Enabled() bool {
+	return s.connector != nil
+}
+
+// Summary returns the CRM, the field mapping in effect and the number of
+// records of each sync status
+func (s *CRMService) Summary(ctx context.Context) (*CRMSummary, error) {
+	if !s.Enabled() {
+		return &CRMSummary{}, nil
+	}
+	counts, err := s.repo.CountByStatus(ctx, s.connector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &CRMSummary{Enabled: true, CRM: s.connector.Name(), Mapping: s.mapping(), Counts: counts}, nil
+}
+
+// Records returns the most recently synced records, optionally of one
+// record type or status
+func (s *CRMService) Records(ctx context.Context, recordType, status string, limit int) ([]models.CRMSyncRecord, error) {
+	if err := s.requireEnabled(); err != nil {
+		return nil, err
+	}
+	if recordType != "" && recordType != crm.Contact && recordType != crm.Lead {
+		return nil, apperrors.Validationf("type must be %q or %q", crm.Contact, crm.Lead)
+	}
+	if status != "" && status != models.CRMSynced && status != models.CRMFailed {
+		return nil, apperrors.Validationf("status must be %q or %q", models.CRMSynced, models.CRMFailed)
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	return s.repo.List(ctx, s.connector.Name(), recordType, status, limit)
+}
+
+// Retry gives records that ran out of attempts another round and returns
+// how many failed records there were
+func (s *CRMService) Retry(ctx context.Context) (int64, error) {
+	if err := s.requireEnabled(); err != nil {
+		return 0, err
+	}
+	return s.repo.ResetFailed(ctx, s.connector.Name())
+}
+
+// SyncDue exports leads that were never exported or whose export failed,
+// with their contacts, and returns how many were exported
+func (s *CRMService) SyncDue(ctx context.Context) (int, error) {
+	if err := s.requireEnabled(); err != nil {
+		return 0, err
+	}
+	leads, err := s.repo.ListDueLeads(ctx, s.connector.Name(), crmMaxAttempts, crmBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	mapping := s.mapping()
+	synced := 0
+	for i := range leads {
+		ok, err := s.syncLead(ctx, mapping, &leads[i])
+		if err != nil {
+			return synced, err
+		}
+		if ok {
+			synced++
+		}
+	}
+	return synced, nil
+}
+
+// syncLead exports a lead's contact and then the lead itself and reports
+// whether both made it. Only failures to read or save local state are
+// returned as errors; CRM failures are recorded on the records.
+func (s *CRMService) syncLead(ctx context.Context, mapping crm.Mapping, lead *models.Lead) (bool, error) {
+	fields := s.leadFields(ctx, lead)
+
+	contact, err := s.record(ctx, crm.Contact, contactKey(lead))
+	if err != nil {
+		return false, err
+	}
+	if contact.Status != models.CRMSynced {
+		s.upsert(ctx, contact, crm.Record{Kind: crm.Contact, ID: contact.ExternalID, Properties: mapping.Apply(crm.Contact, fields)})
+		if err := s.repo.Save(ctx, contact); err != nil {
+			return false, fmt.Errorf("failed to save CRM sync status: %w", err)
+		}
+	}
+
+	record, err := s.record(ctx, crm.Lead, strconv.Itoa(lead.ID))
+	if err != nil {
+		return false, err
+	}
+	if contact.Status == models.CRMSynced {
+		s.upsert(ctx, record, crm.Record{Kind: crm.Lead, ID: record.ExternalID, ContactID: contact.ExternalID,
+			Properties: mapping.Apply(crm.Lead, fields)})
+	} else {
+		s.fail(record, fmt.Errorf("contact was not exported: %s", contact.LastError))
+	}
+	if err := s.repo.Save(ctx, record); err != nil {
+		return false, fmt.Errorf("failed to save CRM sync status: %w", err)
+	}
+	return record.Status == models.CRMSynced, nil
+}
+
+// record returns the sync state of a record, or a new one if it was never
+// exported
+func (s *CRMService) record(ctx context.Context, recordType, key string) (*models.CRMSyncRecord, error) {
+	record, err := s.repo.Get(ctx, s.connector.Name(), recordType, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CRM sync status: %w", err)
+	}
+	if record == nil {
+		record = &models.CRMSyncRecord{CRM: s.connector.Name(), RecordType: recordType, RecordKey: key}
+	}
+	return record, nil
+}
+
+func (s *CRMService) upsert(ctx context.Context, record *models.CRMSyncRecord, data crm.Record) {
+	id, err := s.connector.Upsert(ctx, data)
+	if err != nil {
+		s.fail(record, err)
+		return
+	}
+	now := s.now()
+	record.ExternalID = id
+	record.Status = models.CRMSynced
+	record.LastError = ""
+	record.Attempts = 0
+	record.SyncedAt = &now
+}
+
+func (s *CRMService) fail(record *models.CRMSyncRecord, err error) {
+	log.Printf("Failed to export %s %s to %s: %v", record.RecordType, record.RecordKey, record.CRM, err)
+	record.Status = models.CRMFailed
+	record.LastError = err.Error()
+	record.Attempts++
+}
+
+// leadFields returns the values of crm.LeadFields for a lead; contacts use
+// the subset in crm.ContactFields
+func (s *CRMService) leadFields(ctx context.Context, lead *models.Lead) map[string]any {
+	first, last := splitName(lead.Name)
+	fields := map[string]any{
+		"name":       lead.Name,
+		"first_name": first,
+		"last_name":  last,
+		"email":      lead.Email,
+		"phone":      lead.Phone,
+		"message":    lead.Message,
+		"source":     lead.Source,
+		"created_at": lead.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if lead.PropertyID.Valid {
+		fields["property_id"] = lead.PropertyID.Int32
+		property, err := s.properties.GetByID(ctx, int(lead.PropertyID.Int32))
+		if err != nil {
+			log.Printf("Failed to get property %d of lead %d: %v", lead.PropertyID.Int32, lead.ID, err)
+		} else if property != nil {
+			fields["property_name"] = property.Name
+			fields["property_address"] = property.Location
+		}
+	}
+	if lead.AssignedTo.Valid {
+		if agent, err := s.users.GetByID(ctx, uint(lead.AssignedTo.Int32)); err == nil {
+			fields["agent_email"] = agent.Email
+		}
+	}
+	return fields
+}
+
+// mapping returns the configured field mapping over the connector's
+// defaults. The setting is validated when it is saved.
+func (s *CRMService) mapping() crm.Mapping {
+	configured, err := crm.ParseMapping(s.settings.GetString(SettingCRMFieldMap))
+	if err != nil {
+		log.Printf("Ignoring invalid %s: %v", SettingCRMFieldMap, err)
+		configured = crm.Mapping{}
+	}
+	return configured.Merge(s.connector.DefaultMapping())
+}
+
+func (s *CRMService) requireEnabled() error {
+	if !s.Enabled() {
+		return apperrors.NotFound("no CRM is configured")
+	}
+	return nil
+}
+
+// contactKey identifies the person behind a lead: their email address, or
+// their phone number when they left none
+func contactKey(lead *models.Lead) string {
+	if lead.Email != "" {
+		return strings.ToLower(strings.TrimSpace(lead.Email))
+	}
+	return strings.TrimSpace(lead.Phone)
+}
+
+// splitName splits a full name at its last space into first and last name.
+// A single word is taken as the last name, which CRMs tend to require.
+func splitName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	index := strings.LastIndex(name, " ")
+	if index < 0 {
+		return "", name
+	}
+	return strings.TrimSpace(name[:index]), name[index+1:]
+}
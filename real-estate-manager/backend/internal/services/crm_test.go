@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/crm"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakeCRM records upserts and fails the kinds listed in failing
+type fakeCRM struct {
+	records []crm.Record
+	failing map[string]bool
+}
+
+func (c *fakeCRM) Name() string { return crm.HubSpot }
+
+func (c *fakeCRM) DefaultMapping() crm.Mapping {
+	return crm.Mapping{
+		crm.Contact: {"first_name": "firstname", "last_name": "lastname", "email": "email"},
+		crm.Lead:    {"name": "hs_lead_name"},
+	}
+}
+
+func (c *fakeCRM) Upsert(ctx context.Context, record crm.Record) (string, error) {
+	c.records = append(c.records, record)
+	if c.failing[record.Kind] {
+		return "", errors.New("rate limited")
+	}
+	return record.Kind + "-1", nil
+}
+
+func TestCRMService_SyncDue(t *testing.T) {
+	lead := models.Lead{ID: 30, Source: "zillow", Name: "Jane Q Doe", Email: "Jane@Example.com", Message: "Call me",
+		PropertyID: models.NullInt32{NullInt32: sql.NullInt32{Int32: 12, Valid: true}}, CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	syncedContact := &models.CRMSyncRecord{CRM: crm.HubSpot, RecordType: crm.Contact, RecordKey: "jane@example.com", ExternalID: "77", Status: models.CRMSynced}
+
+	tests := []struct {
+		name          string
+		contact       *models.CRMSyncRecord
+		failing       map[string]bool
+		expectSynced  int
+		expectUpserts int
+		expectStatus  string
+	}{
+		{name: "new contact and lead", expectSynced: 1, expectUpserts: 2, expectStatus: models.CRMSynced},
+		{name: "contact exported with an earlier lead", contact: syncedContact, expectSynced: 1, expectUpserts: 1, expectStatus: models.CRMSynced},
+		{name: "contact fails", failing: map[string]bool{crm.Contact: true}, expectUpserts: 1, expectStatus: models.CRMFailed},
+		{name: "lead fails", failing: map[string]bool{crm.Lead: true}, expectUpserts: 2, expectStatus: models.CRMFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockCRMRepository(ctrl)
+			mockRepo.EXPECT().ListDueLeads(gomock.Any(), crm.HubSpot, crmMaxAttempts, crmBatchSize).Return([]models.Lead{lead}, nil)
+			mockRepo.EXPECT().Get(gomock.Any(), crm.HubSpot, crm.Contact, "jane@example.com").Return(tt.contact, nil)
+			mockRepo.EXPECT().Get(gomock.Any(), crm.HubSpot, crm.Lead, "30").Return(nil, nil)
+			var saved []models.CRMSyncRecord
+			mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, record *models.CRMSyncRecord) error {
+				saved = append(saved, *record)
+				return nil
+			}).Times(tt.expectUpserts)
+			if tt.failing[crm.Contact] {
+				// The lead is recorded as failed along with its contact
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, record *models.CRMSyncRecord) error {
+					saved = append(saved, *record)
+					return nil
+				})
+			}
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12, Name: "Casa Verde", Location: "Houston, TX"}, nil)
+
+			connector := &fakeCRM{failing: tt.failing}
+			settings := staticSettings{SettingCRMFieldMap: `{"lead": {"message": "hs_lead_message", "property_name": "listing"}}`}
+			service := NewCRMService(connector, mockRepo, mockProperties, nil, settings)
+			synced, err := service.SyncDue(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if synced != tt.expectSynced || len(connector.records) != tt.expectUpserts {
+				t.Fatalf("Expected %d synced with %d upserts, got %d with %+v", tt.expectSynced, tt.expectUpserts, synced, connector.records)
+			}
+
+			leadRecord := saved[len(saved)-1]
+			if leadRecord.RecordType != crm.Lead || leadRecord.RecordKey != "30" || leadRecord.Status != tt.expectStatus {
+				t.Errorf("Unexpected lead status %+v", leadRecord)
+			}
+			if tt.expectStatus == models.CRMFailed && (leadRecord.Attempts != 1 || leadRecord.LastError == "") {
+				t.Errorf("Expected a failed attempt with its error, got %+v", leadRecord)
+			}
+			if tt.expectStatus != models.CRMSynced {
+				return
+			}
+
+			if tt.contact == nil {
+				contact := connector.records[0].Properties
+				if contact["firstname"] != "Jane Q" || contact["lastname"] != "Doe" || contact["email"] != "Jane@Example.com" {
+					t.Errorf("Unexpected contact properties %v", contact)
+				}
+			}
+			sent := connector.records[len(connector.records)-1]
+			if sent.ContactID == "" || sent.Properties["hs_lead_message"] != "Call me" || sent.Properties["listing"] != "Casa Verde" {
+				t.Errorf("Expected the configured lead mapping and contact link, got %+v", sent)
+			}
+			if _, ok := sent.Properties["hs_lead_name"]; ok {
+				t.Errorf("Expected the configured lead mapping to replace the default, got %v", sent.Properties)
+			}
+		})
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := map[string][2]string{
+		"Jane Q Doe": {"Jane Q", "Doe"},
+		"Jane":       {"", "Jane"},
+		"":           {"", ""},
+	}
+	for name, expected := range tests {
+		if first, last := splitName(name); first != expected[0] || last != expected[1] {
+			t.Errorf("splitName(%q) = %q, %q; expected %q, %q", name, first, last, expected[0], expected[1])
+		}
+	}
+}
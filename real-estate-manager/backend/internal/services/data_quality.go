@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Listings sampled per check, reports listed at once, and how long
+// reports are kept
+const (
+	qualitySampleSize      = 20
+	DefaultQualityReports  = 10
+	MaxQualityReports      = 100
+	qualityReportRetention = 90 * 24 * time.Hour
+)
+
+// qualityThresholds names the setting holding each check's threshold
+var qualityThresholds = map[string]string{
+	models.QualityZeroPrice:        SettingQualityZeroPrice,
+	models.QualityDuplicateAddress: SettingQualityDuplicate,
+	models.QualityMissingPhotos:    SettingQualityNoPhotos,
+	models.QualityGeocodeFailed:    SettingQualityGeocode,
+}
+
+// DataQualityService checks listings for anomalies, such as missing prices
+// and duplicate addresses, on a schedule. Every run is kept as a report;
+// checks that find more issues than their threshold raise an ops alert.
+type DataQualityService struct {
+	repo     repository.DataQualityRepository
+	settings SettingsProvider
+	alerts   Alerter
+	now      func() time.Time
+}
+
+// NewDataQualityService creates the service; alerter may be nil
+func NewDataQualityService(repo repository.DataQualityRepository, settings SettingsProvider, alerter Alerter) *DataQualityService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &DataQualityService{repo: repo, settings: settings, alerts: alerter, now: time.Now}
+}
+
+// Run runs every check, stores the report and alerts about the checks
+// over their threshold
+func (s *DataQualityService) Run(ctx context.Context) (*models.QualityReport, error) {
+	report := &models.QualityReport{CreatedAt: s.now()}
+	fields := make(map[string]string)
+	for _, check := range models.QualityChecks {
+		count, samples, err := s.repo.Find(ctx, check, qualitySampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("data-quality check %s: %w", check, err)
+		}
+		result := models.QualityCheckResult{
+			Check:     check,
+			Count:     count,
+			Threshold: s.settings.GetInt(qualityThresholds[check]),
+			Samples:   samples,
+		}
+		if result.Count > result.Threshold {
+			result.Exceeded = true
+			report.Exceeded = true
+			fields[check] = fmt.Sprintf("%d (threshold %d), e.g. listings %s", result.Count, result.Threshold, sampleIDs(samples, 5))
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+	if report.Exceeded {
+		fields["report_id"] = fmt.Sprint(report.ID)
+		raiseAlert(s.alerts, alerts.DataQuality, "Listing data-quality checks over threshold", fields)
+	}
+	return report, nil
+}
+
+// List returns the most recent reports, newest first
+func (s *DataQualityService) List(ctx context.Context, limit int) ([]models.QualityReport, error) {
+	if limit == 0 {
+		limit = DefaultQualityReports
+	}
+	if limit < 1 || limit > MaxQualityReports {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxQualityReports)
+	}
+	return s.repo.ListReports(ctx, limit)
+}
+
+// Prune removes reports older than the retention period and returns how
+// many were removed
+func (s *DataQualityService) Prune(ctx context.Context) (int64, error) {
+	return s.repo.DeleteReportsBefore(ctx, s.now().Add(-qualityReportRetention))
+}
+
+// sampleIDs lists the property IDs of up to max issues, e.g. "12, 15"
+func sampleIDs(samples []models.QualityIssue, max int) string {
+	ids := make([]string, 0, max)
+	for _, sample := range samples {
+		for _, id := range sample.PropertyIDs {
+			if len(ids) == max {
+				return strings.Join(ids, ", ")
+			}
+			ids = append(ids, fmt.Sprint(id))
+		}
+	}
+	return strings.Join(ids, ", ")
+}
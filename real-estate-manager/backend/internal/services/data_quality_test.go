@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDataQualityService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	alerter := &recordingAlerter{}
+	service := NewDataQualityService(repo, staticSettings{SettingQualityNoPhotos: "2"}, alerter)
+
+	duplicates := []models.QualityIssue{{PropertyIDs: []int{4, 9}, Location: "1 Main St"}}
+	repo.EXPECT().Find(gomock.Any(), models.QualityZeroPrice, qualitySampleSize).Return(0, []models.QualityIssue{}, nil)
+	repo.EXPECT().Find(gomock.Any(), models.QualityDuplicateAddress, qualitySampleSize).Return(1, duplicates, nil)
+	repo.EXPECT().Find(gomock.Any(), models.QualityMissingPhotos, qualitySampleSize).Return(2, []models.QualityIssue{}, nil)
+	repo.EXPECT().Find(gomock.Any(), models.QualityGeocodeFailed, qualitySampleSize).Return(0, []models.QualityIssue{}, nil)
+	repo.EXPECT().CreateReport(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, report *models.QualityReport) error {
+		report.ID = 7
+		return nil
+	})
+
+	report, err := service.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !report.Exceeded || len(report.Checks) != len(models.QualityChecks) {
+		t.Fatalf("Expected an exceeded report with every check, got %+v", report)
+	}
+	for _, result := range report.Checks {
+		// Only the duplicate is over its threshold; the missing photos are at it
+		if result.Exceeded != (result.Check == models.QualityDuplicateAddress) {
+			t.Errorf("Unexpected result for %s: %+v", result.Check, result)
+		}
+	}
+	if len(alerter.raised) != 1 || alerter.raised[0] != alerts.DataQuality {
+		t.Errorf("Expected one data-quality alert, got %v", alerter.raised)
+	}
+	if ids := sampleIDs(duplicates, 5); ids != "4, 9" {
+		t.Errorf("Expected sample IDs 4, 9, got %q", ids)
+	}
+}
+
+func TestDataQualityService_RunWithinThresholds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	alerter := &recordingAlerter{}
+	service := NewDataQualityService(repo, nil, alerter)
+
+	repo.EXPECT().Find(gomock.Any(), gomock.Any(), qualitySampleSize).Return(0, []models.QualityIssue{}, nil).Times(len(models.QualityChecks))
+	repo.EXPECT().CreateReport(gomock.Any(), gomock.Any()).Return(nil)
+
+	report, err := service.Run(context.Background())
+	if err != nil || report.Exceeded {
+		t.Fatalf("Expected a clean report, got %+v, %v", report, err)
+	}
+	if len(alerter.raised) != 0 {
+		t.Errorf("Expected no alert, got %v", alerter.raised)
+	}
+}
+
+func TestDataQualityService_RunCheckFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	service := NewDataQualityService(repo, nil, nil)
+
+	repo.EXPECT().Find(gomock.Any(), models.QualityZeroPrice, qualitySampleSize).Return(0, nil, errors.New("db down"))
+
+	if _, err := service.Run(context.Background()); err == nil {
+		t.Fatal("Expected the check error")
+	}
+}
+
+func TestDataQualityService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	service := NewDataQualityService(repo, nil, nil)
+
+	repo.EXPECT().ListReports(gomock.Any(), DefaultQualityReports).Return([]models.QualityReport{}, nil)
+	if _, err := service.List(context.Background(), 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.List(context.Background(), MaxQualityReports+1); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestDataQualityService_Prune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	service := NewDataQualityService(repo, nil, nil)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	repo.EXPECT().DeleteReportsBefore(gomock.Any(), now.Add(-qualityReportRetention)).Return(int64(3), nil)
+
+	if removed, err := service.Prune(context.Background()); err != nil || removed != 3 {
+		t.Errorf("Expected 3 reports removed, got %d, %v", removed, err)
+	}
+}
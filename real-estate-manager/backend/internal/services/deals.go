@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// maxReportSpan caps the period a revenue report covers
+const maxReportSpan = 5 * 366 * 24 * time.Hour
+
+// DealService tracks property sales through the pipeline and the
+// commissions they bring in
+type DealService struct {
+	repo       repository.DealRepository
+	properties *PropertyService
+	users      repository.UserRepository
+	now        func() time.Time
+}
+
+func NewDealService(repo repository.DealRepository, properties *PropertyService, users repository.UserRepository) *DealService {
+	return &DealService{repo: repo, properties: properties, users: users, now: time.Now}
+}
+
+// List returns the deals matching filter, soonest expected close first
+func (s *DealService) List(ctx context.Context, filter models.DealFilter) ([]models.Deal, error) {
+	if filter.Stage != "" && !slices.Contains(models.DealStages, filter.Stage) {
+		return nil, invalidStage()
+	}
+	deals, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deals {
+		computeCommission(&deals[i])
+	}
+	return deals, nil
+}
+
+func (s *DealService) Get(ctx context.Context, id int) (*models.Deal, error) {
+	deal, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	computeCommission(deal)
+	return deal, nil
+}
+
+// get loads a deal. Deals are only visible to those who can see the
+// listing.
+func (s *DealService) get(ctx context.Context, id int) (*models.Deal, error) {
+	deal, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deal == nil {
+		return nil, apperrors.NotFound("deal not found")
+	}
+	if _, err := s.properties.GetProperty(ctx, deal.PropertyID); err != nil {
+		return nil, err
+	}
+	return deal, nil
+}
+
+// Create opens a deal on a property, as an offer unless another stage is
+// given
+func (s *DealService) Create(ctx context.Context, deal *models.Deal) error {
+	if deal.PropertyID == 0 {
+		return apperrors.Validation("property_id is required")
+	}
+	if _, err := s.properties.GetProperty(ctx, deal.PropertyID); err != nil {
+		return err
+	}
+	if deal.Stage == "" {
+		deal.Stage = models.DealOffer
+	}
+	if err := s.validate(ctx, deal); err != nil {
+		return err
+	}
+
+	deal.ClosedAt = models.NullTime{}
+	if deal.Stage == models.DealClosed {
+		deal.ClosedAt = models.NullTime{NullTime: sql.NullTime{Time: s.now(), Valid: true}}
+	}
+	deal.CreatedBy = models.NullInt32{}
+	if userID, ok := ActorFromContext(ctx); ok {
+		deal.CreatedBy = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(userID), Valid: true}}
+	}
+	if err := s.repo.Create(ctx, deal); err != nil {
+		return err
+	}
+	computeCommission(deal)
+	return nil
+}
+
+// Update changes a deal's stage, terms and splits. The property cannot
+// change. A deal is stamped closed when it moves to the closed stage, and
+// the stamp is cleared if it moves out again.
+func (s *DealService) Update(ctx context.Context, id int, changes *models.Deal) (*models.Deal, error) {
+	deal, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes.PropertyID != 0 && changes.PropertyID != deal.PropertyID {
+		return nil, apperrors.Validation("a deal cannot move to another property")
+	}
+	if changes.Stage == "" {
+		changes.Stage = deal.Stage
+	}
+	if err := s.validate(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case changes.Stage == models.DealClosed && deal.Stage != models.DealClosed:
+		deal.ClosedAt = models.NullTime{NullTime: sql.NullTime{Time: s.now(), Valid: true}}
+	case changes.Stage != models.DealClosed:
+		deal.ClosedAt = models.NullTime{}
+	}
+	deal.Stage = changes.Stage
+	deal.Price = changes.Price
+	deal.CommissionPercent = changes.CommissionPercent
+	deal.ExpectedCloseDate = changes.ExpectedCloseDate
+	deal.Notes = changes.Notes
+	deal.Splits = changes.Splits
+	if err := s.repo.Update(ctx, deal); err != nil {
+		return nil, err
+	}
+	computeCommission(deal)
+	return deal, nil
+}
+
+func (s *DealService) Delete(ctx context.Context, id int) error {
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	exists, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("deal not found")
+	}
+	return nil
+}
+
+// Pipeline totals the deals in every stage, in pipeline order, optionally
+// only those an agent has a share in
+func (s *DealService) Pipeline(ctx context.Context, agentID uint) ([]models.PipelineStage, error) {
+	totals, err := s.repo.Pipeline(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	stages := make([]models.PipelineStage, len(models.DealStages))
+	for i, name := range models.DealStages {
+		stages[i] = models.PipelineStage{Stage: name}
+		for _, total := range totals {
+			if total.Stage == name {
+				stages[i] = total
+				stages[i].Volume = roundCents(total.Volume)
+				stages[i].Commission = roundCents(total.Commission)
+			}
+		}
+	}
+	return stages, nil
+}
+
+// RevenueReport reports the commission of deals closed between from and
+// to, inclusive dates as "2006-01-02" in location. They default to the
+// start of the year and today there.
+func (s *DealService) RevenueReport(ctx context.Context, from, to string, location *time.Location) (*models.RevenueReport, error) {
+	now := s.now().In(location)
+	start := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, location)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	var err error
+	if from != "" {
+		if start, err = time.ParseInLocation(models.DateLayout, from, location); err != nil {
+			return nil, apperrors.Validation("from must be a date like 2024-01-31")
+		}
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation(models.DateLayout, to, location); err != nil {
+			return nil, apperrors.Validation("to must be a date like 2024-01-31")
+		}
+	}
+	if end.Before(start) {
+		return nil, apperrors.Validation("to must not be before from")
+	}
+	if end.Sub(start) > maxReportSpan {
+		return nil, apperrors.Validation("a report cannot cover more than five years")
+	}
+	// The end date is included
+	until := end.AddDate(0, 0, 1)
+
+	closed, err := s.repo.ClosedRevenue(ctx, start, until)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.repo.AgentRevenue(ctx, start, until)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := s.repo.ExpectedRevenue(ctx, start, until)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RevenueReport{
+		From:     start.Format(models.DateLayout),
+		To:       end.Format(models.DateLayout),
+		Timezone: location.String(),
+		Closed:   closed,
+		Agents:   agents,
+		Expected: expected,
+	}
+	for i := range closed {
+		closed[i].Volume = roundCents(closed[i].Volume)
+		closed[i].Commission = roundCents(closed[i].Commission)
+		report.Commission += closed[i].Commission
+	}
+	for i := range expected {
+		expected[i].Volume = roundCents(expected[i].Volume)
+		expected[i].Commission = roundCents(expected[i].Commission)
+	}
+	for i := range agents {
+		agents[i].Commission = roundCents(agents[i].Commission)
+	}
+	report.Commission = roundCents(report.Commission)
+	return report, nil
+}
+
+// validate checks a deal's stage, terms and splits. Splits may add up to
+// less than 100%; the rest is the brokerage's.
+func (s *DealService) validate(ctx context.Context, deal *models.Deal) error {
+	if !slices.Contains(models.DealStages, deal.Stage) {
+		return invalidStage()
+	}
+	if deal.Price <= 0 {
+		return apperrors.Validation("price must be greater than 0")
+	}
+	if deal.CommissionPercent < 0 || deal.CommissionPercent > 100 {
+		return apperrors.Validation("commission_percent must be between 0 and 100")
+	}
+
+	total := 0.0
+	seen := map[uint]bool{}
+	for i := range deal.Splits {
+		split := &deal.Splits[i]
+		split.Role = strings.TrimSpace(split.Role)
+		if split.Percent <= 0 || split.Percent > 100 {
+			return apperrors.Validation("split percent must be between 0 and 100")
+		}
+		if len(split.Role) > 32 {
+			return apperrors.Validation("split role must be at most 32 characters")
+		}
+		if seen[split.AgentID] {
+			return apperrors.Validationf("agent %d has more than one split", split.AgentID)
+		}
+		seen[split.AgentID] = true
+		if _, err := s.users.GetByID(ctx, split.AgentID); errors.Is(err, sql.ErrNoRows) {
+			return apperrors.Validationf("agent %d not found", split.AgentID)
+		} else if err != nil {
+			return err
+		}
+		total += split.Percent
+	}
+	if total > 100.0001 {
+		return apperrors.Validation("splits add up to more than 100%")
+	}
+	if deal.Splits == nil {
+		deal.Splits = []models.CommissionSplit{}
+	}
+	return nil
+}
+
+// computeCommission fills in the gross commission and each split's amount
+func computeCommission(deal *models.Deal) {
+	commission := deal.Price * deal.CommissionPercent / 100
+	deal.Commission = roundCents(commission)
+	for i := range deal.Splits {
+		deal.Splits[i].Amount = roundCents(commission * deal.Splits[i].Percent / 100)
+	}
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func invalidStage() error {
+	return apperrors.Validationf("stage must be one of %s", strings.Join(models.DealStages, ", "))
+}
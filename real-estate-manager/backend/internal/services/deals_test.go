@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDealService_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		deal        models.Deal
+		expectError bool
+	}{
+		{name: "offer with splits", deal: models.Deal{PropertyID: 12, Price: 400000, CommissionPercent: 3,
+			Splits: []models.CommissionSplit{{AgentID: 4, Role: "listing", Percent: 60}, {AgentID: 5, Role: "buyer", Percent: 30}}}},
+		{name: "closed on creation", deal: models.Deal{PropertyID: 12, Stage: models.DealClosed, Price: 400000, CommissionPercent: 3}},
+		{name: "unknown stage", deal: models.Deal{PropertyID: 12, Stage: "pending", Price: 400000}, expectError: true},
+		{name: "no price", deal: models.Deal{PropertyID: 12}, expectError: true},
+		{name: "commission over 100%", deal: models.Deal{PropertyID: 12, Price: 400000, CommissionPercent: 120}, expectError: true},
+		{name: "splits over 100%", deal: models.Deal{PropertyID: 12, Price: 400000, CommissionPercent: 3,
+			Splits: []models.CommissionSplit{{AgentID: 4, Percent: 60}, {AgentID: 5, Percent: 50}}}, expectError: true},
+		{name: "agent split twice", deal: models.Deal{PropertyID: 12, Price: 400000, CommissionPercent: 3,
+			Splits: []models.CommissionSplit{{AgentID: 4, Percent: 50}, {AgentID: 4, Percent: 50}}}, expectError: true},
+		{name: "unknown agent", deal: models.Deal{PropertyID: 12, Price: 400000, CommissionPercent: 3,
+			Splits: []models.CommissionSplit{{AgentID: 9, Percent: 50}}}, expectError: true},
+	}
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockUsers.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id uint) (*models.User, error) {
+				if id == 9 {
+					return nil, sql.ErrNoRows
+				}
+				return &models.User{ID: id}, nil
+			}).AnyTimes()
+			mockRepo := mocks.NewMockDealRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, deal *models.Deal) error {
+					deal.ID = 7
+					return nil
+				})
+			}
+
+			service := NewDealService(mockRepo, NewPropertyService(mockProperties), mockUsers)
+			service.now = func() time.Time { return now }
+			deal := tt.deal
+			err := service.Create(WithActor(context.Background(), 4), &deal)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if deal.ID != 7 || deal.Commission != 12000 || deal.CreatedBy.Int32 != 4 {
+				t.Errorf("Unexpected deal %+v", deal)
+			}
+			if deal.ClosedAt.Valid != (deal.Stage == models.DealClosed) {
+				t.Errorf("Expected only closed deals to be stamped, got stage %s and closed_at %v", deal.Stage, deal.ClosedAt)
+			}
+			if len(deal.Splits) == 2 && (deal.Splits[0].Amount != 7200 || deal.Splits[1].Amount != 3600) {
+				t.Errorf("Unexpected split amounts %+v", deal.Splits)
+			}
+		})
+	}
+}
+
+func TestDealService_Update_Stage(t *testing.T) {
+	closedAt := models.NullTime{NullTime: sql.NullTime{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		stage         string
+		closedAt      models.NullTime
+		to            string
+		expectClosed  bool
+		expectStampAt time.Time
+	}{
+		{name: "closing", stage: models.DealUnderContract, to: models.DealClosed, expectClosed: true, expectStampAt: now},
+		{name: "still closed keeps the date", stage: models.DealClosed, closedAt: closedAt, to: models.DealClosed, expectClosed: true, expectStampAt: closedAt.Time},
+		{name: "reopened", stage: models.DealClosed, closedAt: closedAt, to: models.DealUnderContract},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDealRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 7).Return(&models.Deal{ID: 7, PropertyID: 12, Stage: tt.stage, Price: 1, ClosedAt: tt.closedAt}, nil)
+			mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+
+			service := NewDealService(mockRepo, NewPropertyService(mockProperties), nil)
+			service.now = func() time.Time { return now }
+			deal, err := service.Update(context.Background(), 7, &models.Deal{Stage: tt.to, Price: 500000, CommissionPercent: 2.5})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if deal.Stage != tt.to || deal.ClosedAt.Valid != tt.expectClosed || (tt.expectClosed && !deal.ClosedAt.Time.Equal(tt.expectStampAt)) {
+				t.Errorf("Unexpected deal %+v", deal)
+			}
+			if deal.Price != 500000 || deal.Commission != 12500 {
+				t.Errorf("Expected the new terms to apply, got %+v", deal)
+			}
+		})
+	}
+}
+
+func TestDealService_OtherOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The deal is on a listing of another organization, which the tenant
+	// cannot see
+	mockRepo := mocks.NewMockDealRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 7).Return(&models.Deal{ID: 7, PropertyID: 12, Stage: models.DealOffer, Price: 1}, nil).Times(3)
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(nil, nil).Times(3)
+
+	service := NewDealService(mockRepo, NewPropertyService(mockProperties), nil)
+	if _, err := service.Get(context.Background(), 7); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found on get, got %v", err)
+	}
+	if _, err := service.Update(context.Background(), 7, &models.Deal{Price: 2}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found on update, got %v", err)
+	}
+	if err := service.Delete(context.Background(), 7); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found on delete, got %v", err)
+	}
+}
+
+func TestDealService_Pipeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDealRepository(ctrl)
+	mockRepo.EXPECT().Pipeline(gomock.Any(), uint(4)).Return([]models.PipelineStage{
+		{Stage: models.DealClosed, Deals: 2, Volume: 900000, Commission: 27000.004},
+	}, nil)
+
+	stages, err := NewDealService(mockRepo, nil, nil).Pipeline(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(stages) != len(models.DealStages) || stages[0].Stage != models.DealOffer || stages[0].Deals != 0 {
+		t.Fatalf("Expected every stage in pipeline order, got %+v", stages)
+	}
+	if stages[2].Deals != 2 || stages[2].Commission != 27000 {
+		t.Errorf("Unexpected closed stage %+v", stages[2])
+	}
+}
+
+func TestDealService_RevenueReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The dates are midnights in the report's time zone
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, saoPaulo)
+	until := time.Date(2024, 7, 1, 0, 0, 0, 0, saoPaulo)
+	mockRepo := mocks.NewMockDealRepository(ctrl)
+	mockRepo.EXPECT().ClosedRevenue(gomock.Any(), from, until).Return([]models.RevenuePeriod{
+		{Period: "2024-02", Deals: 1, Volume: 400000, Commission: 12000},
+		{Period: "2024-05", Deals: 1, Volume: 500000, Commission: 15000},
+	}, nil)
+	mockRepo.EXPECT().AgentRevenue(gomock.Any(), from, until).Return([]models.AgentRevenue{{AgentID: 4, Deals: 2, Commission: 16200}}, nil)
+	mockRepo.EXPECT().ExpectedRevenue(gomock.Any(), from, until).Return([]models.RevenuePeriod{}, nil)
+
+	service := NewDealService(mockRepo, nil, nil)
+	report, err := service.RevenueReport(context.Background(), "2024-01-01", "2024-06-30", saoPaulo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Commission != 27000 || report.From != "2024-01-01" || report.To != "2024-06-30" || len(report.Agents) != 1 ||
+		report.Timezone != "America/Sao_Paulo" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	for _, dates := range [][2]string{{"2024-06-30", "2024-01-01"}, {"January", ""}, {"2010-01-01", "2024-01-01"}} {
+		if _, err := service.RevenueReport(context.Background(), dates[0], dates[1], time.UTC); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected %v to be rejected, got %v", dates, err)
+		}
+	}
+}
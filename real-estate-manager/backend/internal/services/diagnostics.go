@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Startup diagnostic modes. In DiagnosticsFailFast mode the server refuses
+// to start when a required check fails and starts degraded when another one
+// does; DiagnosticsStrict refuses on any failure and DiagnosticsWarn never
+// refuses.
+const (
+	DiagnosticsFailFast = "fail"
+	DiagnosticsStrict   = "strict"
+	DiagnosticsWarn     = "warn"
+	DiagnosticsOff      = "off"
+)
+
+// diagnosticTimeout bounds each startup check. It is longer than health
+// checks because a cold provider or bucket can be slow to answer first.
+const diagnosticTimeout = 5 * time.Second
+
+// minJWTSecretLength matches the HS256 key size
+const minJWTSecretLength = 32
+
+// Diagnostic is one check run before the server starts. The server cannot
+// work correctly without a required one.
+type Diagnostic struct {
+	Name     string
+	Required bool
+	Check    DependencyChecker
+}
+
+// DiagnosticResult is the outcome of one startup check
+type DiagnosticResult struct {
+	Name     string
+	Required bool
+	Duration time.Duration
+	Err      error
+}
+
+// DiagnosticReport is the outcome of every startup check, in the order
+// they ran
+type DiagnosticReport struct {
+	Results []DiagnosticResult
+}
+
+// RunDiagnostics runs the checks one after another so the report reads in
+// a predictable order
+func RunDiagnostics(ctx context.Context, diagnostics []Diagnostic) DiagnosticReport {
+	report := DiagnosticReport{Results: make([]DiagnosticResult, 0, len(diagnostics))}
+	for _, diagnostic := range diagnostics {
+		checkCtx, cancel := context.WithTimeout(ctx, diagnosticTimeout)
+		started := time.Now()
+		err := diagnostic.Check.Check(checkCtx)
+		cancel()
+		report.Results = append(report.Results, DiagnosticResult{
+			Name:     diagnostic.Name,
+			Required: diagnostic.Required,
+			Duration: time.Since(started),
+			Err:      err,
+		})
+	}
+	return report
+}
+
+// Refuse reports whether the server should refuse to start in mode
+func (r DiagnosticReport) Refuse(mode string) bool {
+	if mode == DiagnosticsWarn || mode == DiagnosticsOff {
+		return false
+	}
+	for _, result := range r.Results {
+		if result.Err != nil && (result.Required || mode == DiagnosticsStrict) {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists every check with its outcome, one per line
+func (r DiagnosticReport) String() string {
+	var b strings.Builder
+	for _, result := range r.Results {
+		outcome := "ok"
+		if result.Err != nil && result.Required {
+			outcome = "FAIL"
+		} else if result.Err != nil {
+			outcome = "WARN"
+		}
+		fmt.Fprintf(&b, "  %-4s  %-14s %6dms", outcome, result.Name, result.Duration.Milliseconds())
+		if result.Err != nil {
+			fmt.Fprintf(&b, "  %v", result.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// JWTSecretCheck fails for the built-in development secret and for secrets too
+// short or repetitive to resist guessing
+func JWTSecretCheck(secret string) DependencyChecker {
+	return CheckFunc(func(ctx context.Context) error {
+		if secret == "" || secret == DevJWTSecret {
+			return errors.New("JWT_SECRET is not set, the insecure development secret is in use")
+		}
+		if len(secret) < minJWTSecretLength {
+			return fmt.Errorf("JWT_SECRET is %d characters, at least %d are required", len(secret), minJWTSecretLength)
+		}
+		distinct := make(map[rune]bool)
+		for _, r := range secret {
+			distinct[r] = true
+		}
+		if len(distinct) < 8 {
+			return errors.New("JWT_SECRET uses too few distinct characters to be random")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunDiagnostics_Refuse(t *testing.T) {
+	failing := CheckFunc(func(ctx context.Context) error { return errors.New("unreachable") })
+	passing := CheckFunc(func(ctx context.Context) error { return nil })
+
+	optionalFailure := RunDiagnostics(context.Background(), []Diagnostic{
+		{Name: "migrations", Required: true, Check: passing},
+		{Name: "simplyrets", Check: failing},
+	})
+	requiredFailure := RunDiagnostics(context.Background(), []Diagnostic{
+		{Name: "migrations", Required: true, Check: failing},
+	})
+
+	tests := []struct {
+		name   string
+		report DiagnosticReport
+		mode   string
+		want   bool
+	}{
+		{"optional failure starts degraded", optionalFailure, DiagnosticsFailFast, false},
+		{"optional failure refused when strict", optionalFailure, DiagnosticsStrict, true},
+		{"required failure refused", requiredFailure, DiagnosticsFailFast, true},
+		{"required failure tolerated when warning", requiredFailure, DiagnosticsWarn, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Refuse(tt.mode); got != tt.want {
+				t.Errorf("Expected Refuse(%q) to be %v", tt.mode, tt.want)
+			}
+		})
+	}
+
+	report := optionalFailure.String()
+	if !strings.Contains(report, "ok    migrations") || !strings.Contains(report, "WARN  simplyrets") || !strings.Contains(report, "unreachable") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+	if !strings.Contains(requiredFailure.String(), "FAIL  migrations") {
+		t.Errorf("Expected a failed required check, got:\n%s", requiredFailure)
+	}
+}
+
+func TestJWTSecretCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"development", DevJWTSecret, true},
+		{"too short", "s3cr3t-but-short", true},
+		{"repetitive", strings.Repeat("ab", 20), true},
+		{"strong", "k7Qz!pL2#vR9wXe4$Tn8mB1@cY6hJ3dF", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JWTSecretCheck(tt.secret).Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
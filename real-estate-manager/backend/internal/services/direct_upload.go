@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/pkg/objectstore"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxDirectUploadBytes is the largest object S3 accepts in a single PUT
+	MaxDirectUploadBytes = 5 << 30
+	presignExpiry        = 15 * time.Minute
+)
+
+// ObjectStore is the bucket direct uploads are written to
+type ObjectStore interface {
+	URL(key string) string
+	PresignPut(key string, expires time.Duration) (string, error)
+	Size(ctx context.Context, key string) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// DirectUploadService issues pre-signed URLs so large photos and documents
+// go straight to object storage, then registers them once confirmed and
+// scanned for viruses
+type DirectUploadService struct {
+	store      ObjectStore
+	uploads    repository.UploadRepository
+	properties *PropertyService
+	storage    *StorageService
+	scans      *VirusScanService
+}
+
+func NewDirectUploadService(store ObjectStore, uploads repository.UploadRepository, properties *PropertyService, storage *StorageService, scans *VirusScanService) *DirectUploadService {
+	return &DirectUploadService{store: store, uploads: uploads, properties: properties, storage: storage, scans: scans}
+}
+
+// Presign reserves an upload and returns the URL the client PUTs the file to
+func (s *DirectUploadService) Presign(ctx context.Context, req models.PresignUploadRequest) (*models.PresignedUpload, error) {
+	kind, ext, err := checkUploadFile(req.Kind, req.Filename, req.SizeBytes, MaxDirectUploadBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.Kind = kind
+
+	if _, err := s.properties.GetProperty(ctx, req.PropertyID); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.storage.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.CheckQuota(ctx, owner, req.SizeBytes); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	upload := models.Upload{
+		ID:             id,
+		UserID:         nullID(owner.UserID),
+		OrganizationID: nullID(owner.OrganizationID),
+		PropertyID:     req.PropertyID,
+		Kind:           req.Kind,
+		ObjectKey:      fmt.Sprintf("properties/%d/%s%s", req.PropertyID, id, ext),
+		Filename:       filepath.Base(req.Filename),
+		ContentType:    req.ContentType,
+		Caption:        req.Caption,
+		SizeBytes:      req.SizeBytes,
+		Status:         models.UploadStatusPending,
+	}
+
+	url, err := s.store.PresignPut(upload.ObjectKey, presignExpiry)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.uploads.Create(ctx, &upload); err != nil {
+		return nil, err
+	}
+
+	presigned := &models.PresignedUpload{
+		UploadID:  id,
+		Method:    "PUT",
+		URL:       url,
+		ExpiresAt: time.Now().Add(presignExpiry).UTC(),
+	}
+	if req.ContentType != "" {
+		presigned.Headers = map[string]string{"Content-Type": req.ContentType}
+	}
+	return presigned, nil
+}
+
+// Confirm registers an uploaded object: its actual size is charged to the
+// uploader and photos are added to the property
+func (s *DirectUploadService) Confirm(ctx context.Context, id string) (*models.Upload, error) {
+	upload, err := s.uploads.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil {
+		return nil, apperrors.NotFound("upload not found")
+	}
+	if err := checkUploader(ctx, upload.UserID); err != nil {
+		return nil, err
+	}
+	if upload.Status != models.UploadStatusPending {
+		return nil, apperrors.Conflict("upload already confirmed")
+	}
+
+	size, err := s.store.Size(ctx, upload.ObjectKey)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, apperrors.Validation("file has not been uploaded yet")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The declared size was checked when presigning; the real object may be
+	// larger and other uploads may have landed since
+	owner := StorageOwner{UserID: int(upload.UserID.Int32), OrganizationID: int(upload.OrganizationID.Int32)}
+	if err := s.storage.CheckQuota(ctx, owner, size); err != nil {
+		s.store.Delete(ctx, upload.ObjectKey)
+		return nil, err
+	}
+	if err := s.scan(ctx, upload, owner, size); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.uploads.MarkCompleted(ctx, upload.ID, size, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.Conflict("upload already confirmed")
+		}
+		return nil, err
+	}
+	upload.Status = models.UploadStatusCompleted
+	upload.SizeBytes = size
+	upload.CompletedAt = nullTime(now)
+
+	url := s.store.URL(upload.ObjectKey)
+	if err := s.storage.Record(ctx, owner, upload.PropertyID, upload.Kind, url, size); err != nil {
+		return nil, err
+	}
+
+	if upload.Kind == models.FileKindPhoto {
+		property, err := s.properties.GetProperty(ctx, upload.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		if err := addPhoto(ctx, s.properties, property, models.Photo{URL: url, Caption: upload.Caption}); err != nil {
+			return nil, err
+		}
+	}
+	return upload, nil
+}
+
+// checkUploadFile validates an upload's kind, photo when empty, its file
+// extension and its size against limit, and returns the kind and the
+// lower-cased extension
+func checkUploadFile(kind, filename string, size, limit int64) (string, string, error) {
+	if kind == "" {
+		kind = models.FileKindPhoto
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch kind {
+	case models.FileKindPhoto:
+		if !photoExtensions[ext] {
+			return "", "", apperrors.Validation("photo must be a JPEG, PNG or WebP image")
+		}
+	case models.FileKindDocument:
+	default:
+		return "", "", apperrors.Validationf("kind must be %q or %q", models.FileKindPhoto, models.FileKindDocument)
+	}
+	if size <= 0 {
+		return "", "", apperrors.Validation("size_bytes must be positive")
+	}
+	if size > limit {
+		return "", "", apperrors.TooLargef("file is %s, the limit is %s", formatMB(size), formatMB(limit))
+	}
+	return kind, ext, nil
+}
+
+// checkUploader rejects an upload started by another user
+func checkUploader(ctx context.Context, userID models.NullInt32) error {
+	if actor, ok := ActorFromContext(ctx); ok && userID.Valid && uint(userID.Int32) != actor {
+		return apperrors.Forbidden("upload belongs to another user")
+	}
+	return nil
+}
+
+// scan checks an uploaded object for viruses. Infected objects are removed
+// from the bucket once the scanner's copy is quarantined.
+func (s *DirectUploadService) scan(ctx context.Context, upload *models.Upload, owner StorageOwner, size int64) error {
+	if !s.scans.Enabled() {
+		return nil
+	}
+	content, err := s.store.Open(ctx, upload.ObjectKey)
+	if err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	defer content.Close()
+
+	scanned := ScannedFile{Owner: owner, PropertyID: upload.PropertyID, Kind: upload.Kind, Filename: upload.Filename, SizeBytes: size}
+	err = s.scans.ScanStream(ctx, scanned, content)
+	if errors.Is(err, apperrors.ErrValidation) {
+		s.store.Delete(ctx, upload.ObjectKey)
+	}
+	return err
+}
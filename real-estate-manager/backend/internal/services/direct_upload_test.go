@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/scanner"
+	"real-estate-manager/backend/pkg/objectstore"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakeObjectStore keeps object sizes in memory
+type fakeObjectStore struct {
+	sizes   map[string]int64
+	deleted []string
+}
+
+func (f *fakeObjectStore) URL(key string) string {
+	return "https://bucket.example.com/" + key
+}
+
+func (f *fakeObjectStore) PresignPut(key string, expires time.Duration) (string, error) {
+	return f.URL(key) + "?X-Amz-Signature=test", nil
+}
+
+func (f *fakeObjectStore) Size(ctx context.Context, key string) (int64, error) {
+	size, ok := f.sizes[key]
+	if !ok {
+		return 0, objectstore.ErrNotFound
+	}
+	return size, nil
+}
+
+func (f *fakeObjectStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	size, ok := f.sizes[key]
+	if !ok {
+		return nil, objectstore.ErrNotFound
+	}
+	return io.NopCloser(strings.NewReader(strings.Repeat("x", int(size)))), nil
+}
+
+func (f *fakeObjectStore) Delete(ctx context.Context, key string) error {
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func TestDirectUploadService_Presign(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         models.PresignUploadRequest
+		setupMock   func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name: "photo within quota",
+			req:  models.PresignUploadRequest{PropertyID: 1, Filename: "front.jpg", ContentType: "image/jpeg", SizeBytes: 50 * bytesPerMB},
+			setupMock: func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository) {
+				properties.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1}, nil)
+				uploads.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, upload *models.Upload) error {
+					if upload.Kind != models.FileKindPhoto || upload.Status != models.UploadStatusPending {
+						t.Errorf("Unexpected upload: %+v", upload)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:        "unsupported photo type",
+			req:         models.PresignUploadRequest{PropertyID: 1, Filename: "plan.pdf", SizeBytes: 1024},
+			setupMock:   func(*mocks.MockPropertyRepository, *mocks.MockUploadRepository, *mocks.MockStorageRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "file over the single PUT limit",
+			req:         models.PresignUploadRequest{PropertyID: 1, Kind: models.FileKindDocument, Filename: "plan.pdf", SizeBytes: MaxDirectUploadBytes + 1},
+			setupMock:   func(*mocks.MockPropertyRepository, *mocks.MockUploadRepository, *mocks.MockStorageRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrTooLarge,
+		},
+		{
+			name: "quota exceeded",
+			req:  models.PresignUploadRequest{PropertyID: 1, Kind: models.FileKindDocument, Filename: "plan.pdf", SizeBytes: 2000 * bytesPerMB},
+			setupMock: func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository) {
+				properties.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1}, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			propertyRepo := mocks.NewMockPropertyRepository(ctrl)
+			uploadRepo := mocks.NewMockUploadRepository(ctrl)
+			storageRepo := mocks.NewMockStorageRepository(ctrl)
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7}, nil).AnyTimes()
+			storageRepo.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(0), nil).AnyTimes()
+			tt.setupMock(propertyRepo, uploadRepo, storageRepo)
+
+			service := NewDirectUploadService(&fakeObjectStore{}, uploadRepo, NewPropertyService(propertyRepo),
+				NewStorageService(storageRepo, userRepo, staticSettings{}), nil)
+			presigned, err := service.Presign(WithActor(context.Background(), 7), tt.req)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if presigned.Method != "PUT" || presigned.URL == "" || presigned.UploadID == "" {
+				t.Errorf("Unexpected presigned upload: %+v", presigned)
+			}
+		})
+	}
+}
+
+func TestDirectUploadService_Confirm(t *testing.T) {
+	pending := func() *models.Upload {
+		return &models.Upload{
+			ID:         "u1",
+			UserID:     models.NullInt32{NullInt32: sql.NullInt32{Int32: 7, Valid: true}},
+			PropertyID: 1,
+			Kind:       models.FileKindPhoto,
+			ObjectKey:  "properties/1/u1.jpg",
+			SizeBytes:  1024,
+			Status:     models.UploadStatusPending,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		upload      *models.Upload
+		sizes       map[string]int64
+		verdict     *scanner.Result
+		setupMock   func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository)
+		expectKind  error
+		expectError bool
+		expectPurge bool
+	}{
+		{
+			name:   "photo is recorded and attached",
+			upload: pending(),
+			sizes:  map[string]int64{"properties/1/u1.jpg": 2048},
+			setupMock: func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository) {
+				storage.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(0), nil)
+				uploads.EXPECT().MarkCompleted(gomock.Any(), "u1", int64(2048), gomock.Any()).Return(nil)
+				storage.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+				properties.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1, Name: "House", Location: "1 Main St", Price: 1}, nil)
+				properties.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, property *models.Property) error {
+					if len(property.Photos) != 1 || property.Photos[0].URL != "https://bucket.example.com/properties/1/u1.jpg" {
+						t.Errorf("Unexpected photos: %+v", property.Photos)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:        "object not uploaded",
+			upload:      pending(),
+			setupMock:   func(*mocks.MockPropertyRepository, *mocks.MockUploadRepository, *mocks.MockStorageRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:   "actual size exceeds quota",
+			upload: pending(),
+			sizes:  map[string]int64{"properties/1/u1.jpg": 2048 * bytesPerMB},
+			setupMock: func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository) {
+				storage.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(0), nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrTooLarge,
+			expectPurge: true,
+		},
+		{
+			name:    "infected object is removed",
+			upload:  pending(),
+			sizes:   map[string]int64{"properties/1/u1.jpg": 2048},
+			verdict: &scanner.Result{Infected: true, Signature: "Win.Test.EICAR_HDB-1"},
+			setupMock: func(properties *mocks.MockPropertyRepository, uploads *mocks.MockUploadRepository, storage *mocks.MockStorageRepository) {
+				storage.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(0), nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+			expectPurge: true,
+		},
+		{
+			name: "already confirmed",
+			upload: func() *models.Upload {
+				u := pending()
+				u.Status = models.UploadStatusCompleted
+				return u
+			}(),
+			setupMock:   func(*mocks.MockPropertyRepository, *mocks.MockUploadRepository, *mocks.MockStorageRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrConflict,
+		},
+		{
+			name:        "unknown upload",
+			setupMock:   func(*mocks.MockPropertyRepository, *mocks.MockUploadRepository, *mocks.MockStorageRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			propertyRepo := mocks.NewMockPropertyRepository(ctrl)
+			uploadRepo := mocks.NewMockUploadRepository(ctrl)
+			storageRepo := mocks.NewMockStorageRepository(ctrl)
+			uploadRepo.EXPECT().GetByID(gomock.Any(), "u1").Return(tt.upload, nil)
+			tt.setupMock(propertyRepo, uploadRepo, storageRepo)
+
+			var scans *VirusScanService
+			if tt.verdict != nil {
+				scanRepo := mocks.NewMockFileScanRepository(ctrl)
+				scanRepo.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+				scans = NewVirusScanService(&fakeScanner{result: tt.verdict}, scanRepo, t.TempDir())
+			}
+
+			store := &fakeObjectStore{sizes: tt.sizes}
+			service := NewDirectUploadService(store, uploadRepo, NewPropertyService(propertyRepo),
+				NewStorageService(storageRepo, mocks.NewMockUserRepository(ctrl), staticSettings{}), scans)
+			_, err := service.Confirm(WithActor(context.Background(), 7), "u1")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.expectPurge != (len(store.deleted) == 1) {
+				t.Errorf("Expected purge=%v, deleted %v", tt.expectPurge, store.deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// EmailSuppressionService lets administrators review addresses the mailer
+// skips and release ones that can receive email again
+type EmailSuppressionService struct {
+	repo repository.EmailSuppressionRepository
+}
+
+func NewEmailSuppressionService(repo repository.EmailSuppressionRepository) *EmailSuppressionService {
+	return &EmailSuppressionService{repo: repo}
+}
+
+func (s *EmailSuppressionService) List(ctx context.Context) ([]models.EmailSuppression, error) {
+	return s.repo.List(ctx)
+}
+
+// Remove takes email off the suppression list
+func (s *EmailSuppressionService) Remove(ctx context.Context, email string) error {
+	removed, err := s.repo.Delete(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return apperrors.NotFound("email is not suppressed")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mailer"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// emailTokenFlow emails single-use tokens to registered users and redeems
+// them, the part shared by magic links and password resets
+type emailTokenFlow struct {
+	users    repository.UserRepository
+	tokens   repository.EmailTokenRepository
+	mailer   mailer.Mailer
+	template string
+	ttl      func() time.Duration
+	limiter  *windowLimiter
+	// limited and invalid are the messages for a rate-limited request and
+	// for a token that cannot be redeemed
+	limited string
+	invalid string
+	now     func() time.Time
+}
+
+// send emails a token to the account registered with email; link turns the
+// token into the URL put in the email. Unknown addresses succeed silently
+// so the endpoint cannot be used to discover accounts.
+func (f *emailTokenFlow) send(ctx context.Context, email string, link func(token string) string) error {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return apperrors.Validation("a valid email is required")
+	}
+	email = strings.ToLower(address.Address)
+
+	if !f.limiter.Allow(email, f.now()) {
+		return apperrors.RateLimited(f.limited)
+	}
+
+	user, err := f.users.GetByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	ttl := f.ttl()
+	stored := models.EmailToken{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: f.now().Add(ttl),
+	}
+	if err := f.tokens.Create(ctx, &stored); err != nil {
+		return err
+	}
+
+	msg, err := mailer.Render(user.Email, f.template, map[string]any{
+		"Username":  user.Username,
+		"ExpiresIn": ttl,
+		"URL":       link(token),
+	})
+	if err != nil {
+		return err
+	}
+	return f.mailer.Send(ctx, msg)
+}
+
+// redeem consumes a token and returns the user it was sent to
+func (f *emailTokenFlow) redeem(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, apperrors.Validation("token is required")
+	}
+
+	stored, err := f.tokens.Consume(ctx, hashToken(token), f.now())
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, apperrors.Unauthorized(f.invalid)
+	}
+
+	user, err := f.users.GetByID(ctx, stored.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.Unauthorized(f.invalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
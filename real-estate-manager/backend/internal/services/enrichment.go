@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/enrichment"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// enrichmentBatchSize caps how many properties one refresh run enriches
+const enrichmentBatchSize = 25
+
+// EnrichmentService geocodes properties and runs them through the configured
+// enrichment providers
+type EnrichmentService struct {
+	repo      repository.EnrichmentRepository
+	geocoder  enrichment.Geocoder
+	providers []enrichment.Provider
+	settings  SettingsProvider
+	// throttle spaces out properties in a refresh run to respect the
+	// public geocoding rate limits
+	throttle time.Duration
+	now      func() time.Time
+}
+
+func NewEnrichmentService(repo repository.EnrichmentRepository, geocoder enrichment.Geocoder, providers []enrichment.Provider, settings SettingsProvider) *EnrichmentService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &EnrichmentService{
+		repo:      repo,
+		geocoder:  geocoder,
+		providers: providers,
+		settings:  settings,
+		throttle:  time.Second,
+		now:       time.Now,
+	}
+}
+
+// Enabled reports whether a geocoder is configured
+func (s *EnrichmentService) Enabled() bool {
+	return s.geocoder != nil
+}
+
+// GetEnrichment returns the stored enrichment of a property
+func (s *EnrichmentService) GetEnrichment(ctx context.Context, propertyID int) (*models.Enrichment, error) {
+	enriched, err := s.repo.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if enriched == nil {
+		return nil, apperrors.NotFound("property has not been enriched yet")
+	}
+	return enriched, nil
+}
+
+// EnrichProperty geocodes a property, runs every provider and stores the
+// result. Provider failures are logged and leave their fields empty; an
+// address that cannot be geocoded is stored empty so it is not retried
+// until the next refresh.
+func (s *EnrichmentService) EnrichProperty(ctx context.Context, property models.Property) (*models.Enrichment, error) {
+	if !s.Enabled() {
+		return nil, errors.New("enrichment is disabled")
+	}
+	enriched := &models.Enrichment{PropertyID: property.ID, EnrichedAt: s.now()}
+
+	location, err := s.geocoder.Geocode(ctx, property.Location)
+	switch {
+	case errors.Is(err, enrichment.ErrNoMatch):
+		log.Printf("Could not geocode property %d (%q)", property.ID, property.Location)
+	case err != nil:
+		return nil, err
+	default:
+		enriched.Latitude = models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: location.Latitude, Valid: true}}
+		enriched.Longitude = models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: location.Longitude, Valid: true}}
+		for _, provider := range s.providers {
+			if err := provider.Enrich(ctx, location, enriched); err != nil && !errors.Is(err, enrichment.ErrNoMatch) {
+				log.Printf("Enrichment provider %s failed for property %d: %v", provider.Name(), property.ID, err)
+			}
+		}
+	}
+
+	if err := s.repo.Upsert(ctx, enriched); err != nil {
+		return nil, err
+	}
+	return enriched, nil
+}
+
+// RefreshDue enriches properties that were never enriched or whose data is
+// older than the enrichment_refresh_days setting. It returns how many
+// properties were enriched.
+func (s *EnrichmentService) RefreshDue(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+	cutoff := s.now().AddDate(0, 0, -s.settings.GetInt(SettingEnrichmentDays))
+	properties, err := s.repo.FindDue(ctx, cutoff, enrichmentBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	enriched := 0
+	for i, property := range properties {
+		if i > 0 && s.throttle > 0 {
+			select {
+			case <-ctx.Done():
+				return enriched, ctx.Err()
+			case <-time.After(s.throttle):
+			}
+		}
+		if _, err := s.EnrichProperty(ctx, property); err != nil {
+			log.Printf("Failed to enrich property %d: %v", property.ID, err)
+			continue
+		}
+		enriched++
+	}
+	return enriched, nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/enrichment"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+type fakeGeocoder struct {
+	err error
+}
+
+func (g fakeGeocoder) Geocode(ctx context.Context, address string) (enrichment.Location, error) {
+	if g.err != nil {
+		return enrichment.Location{}, g.err
+	}
+	return enrichment.Location{Address: address, Latitude: 40.7, Longitude: -74.0}, nil
+}
+
+type fakeProvider struct {
+	name string
+	fill func(e *models.Enrichment)
+	err  error
+}
+
+func (p fakeProvider) Name() string { return p.name }
+
+func (p fakeProvider) Enrich(ctx context.Context, location enrichment.Location, e *models.Enrichment) error {
+	if p.fill != nil {
+		p.fill(e)
+	}
+	return p.err
+}
+
+func TestEnrichmentService_EnrichProperty(t *testing.T) {
+	schools := fakeProvider{name: "schools", fill: func(e *models.Enrichment) {
+		e.SchoolDistrict = models.NullString{NullString: sql.NullString{String: "Springfield USD", Valid: true}}
+	}}
+	broken := fakeProvider{name: "broken", err: errors.New("provider down")}
+
+	tests := []struct {
+		name        string
+		geocoder    fakeGeocoder
+		providers   []enrichment.Provider
+		setupMock   func(mock *mocks.MockEnrichmentRepository)
+		expectError bool
+		verify      func(t *testing.T, e *models.Enrichment)
+	}{
+		{
+			name:      "provider failure does not block others",
+			providers: []enrichment.Provider{broken, schools},
+			setupMock: func(mock *mocks.MockEnrichmentRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			verify: func(t *testing.T, e *models.Enrichment) {
+				if !e.Latitude.Valid || e.Latitude.Float64 != 40.7 {
+					t.Errorf("Expected latitude from geocoder, got %+v", e.Latitude)
+				}
+				if e.SchoolDistrict.String != "Springfield USD" {
+					t.Errorf("Expected school district, got %+v", e.SchoolDistrict)
+				}
+			},
+		},
+		{
+			name:      "ungeocodable address is stored empty",
+			geocoder:  fakeGeocoder{err: enrichment.ErrNoMatch},
+			providers: []enrichment.Provider{schools},
+			setupMock: func(mock *mocks.MockEnrichmentRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			verify: func(t *testing.T, e *models.Enrichment) {
+				if e.Latitude.Valid || e.SchoolDistrict.Valid {
+					t.Errorf("Expected empty enrichment, got %+v", e)
+				}
+			},
+		},
+		{
+			name:        "geocoder error is returned",
+			geocoder:    fakeGeocoder{err: errors.New("timeout")},
+			setupMock:   func(mock *mocks.MockEnrichmentRepository) {},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockEnrichmentRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewEnrichmentService(mockRepo, tt.geocoder, tt.providers, nil)
+			e, err := service.EnrichProperty(context.Background(), models.Property{ID: 1, Location: "1 Main St"})
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.verify(t, e)
+		})
+	}
+}
+
+func TestEnrichmentService_RefreshDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo := mocks.NewMockEnrichmentRepository(ctrl)
+	mockRepo.EXPECT().FindDue(gomock.Any(), now.AddDate(0, 0, -30), enrichmentBatchSize).
+		Return([]models.Property{{ID: 1}, {ID: 2}}, nil)
+	mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+
+	service := NewEnrichmentService(mockRepo, fakeGeocoder{}, nil, nil)
+	service.now = func() time.Time { return now }
+	service.throttle = 0
+
+	count, err := service.RefreshDue(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 property enriched, got %d", count)
+	}
+}
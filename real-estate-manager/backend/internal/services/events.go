@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"real-estate-manager/backend/internal/events"
+)
+
+// EventPublisher emits domain events to downstream consumers; *events.Bus
+// implements it
+type EventPublisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+// publishEvent emits an event attributed to the acting user, if any. A nil
+// publisher means events are disabled.
+func publishEvent(ctx context.Context, publisher EventPublisher, eventType, subject string, data any) {
+	if publisher == nil {
+		return
+	}
+	event := events.New(eventType, subject, data)
+	if userID, ok := ActorFromContext(ctx); ok {
+		event.ActorID = int(userID)
+	}
+	publisher.Publish(ctx, event)
+}
+
+func propertySubject(id int) string {
+	return fmt.Sprintf("property/%d", id)
+}
+
+func leadSubject(id int) string {
+	return fmt.Sprintf("lead/%d", id)
+}
+
+func offerSubject(id int) string {
+	return fmt.Sprintf("offer/%d", id)
+}
+
+func jobSubject(id string) string {
+	return "job/" + id
+}
+
+// jobEventType maps a job's final status to its event type
+func jobEventType(status string) string {
+	switch status {
+	case "failed":
+		return events.JobFailed
+	case "cancelled":
+		return events.JobCancelled
+	default:
+		return events.JobCompleted
+	}
+}
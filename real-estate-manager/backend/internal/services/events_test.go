@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestPropertyService_PublishesEvents(t *testing.T) {
+	property := &models.Property{ID: 12, Name: "House", Location: "Main St", Price: 1000}
+
+	tests := []struct {
+		name          string
+		run           func(s *PropertyService) error
+		setupMock     func(mock *mocks.MockPropertyRepository)
+		expectType    string
+		expectSubject string
+	}{
+		{
+			name: "create",
+			run:  func(s *PropertyService) error { return s.CreateProperty(WithActor(context.Background(), 5), property) },
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().Create(gomock.Any(), property).Return(nil)
+			},
+			expectType:    events.PropertyCreated,
+			expectSubject: "property/12",
+		},
+		{
+			name: "update",
+			run:  func(s *PropertyService) error { return s.UpdateProperty(WithActor(context.Background(), 5), property) },
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().Update(gomock.Any(), property).Return(nil)
+			},
+			expectType:    events.PropertyUpdated,
+			expectSubject: "property/12",
+		},
+		{
+			name: "delete",
+			run:  func(s *PropertyService) error { return s.DeleteProperty(WithActor(context.Background(), 5), 12) },
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().Delete(gomock.Any(), 12).Return(nil)
+			},
+			expectType:    events.PropertyDeleted,
+			expectSubject: "property/12",
+		},
+		{
+			name: "failed write publishes nothing",
+			run:  func(s *PropertyService) error { return s.DeleteProperty(WithActor(context.Background(), 5), 12) },
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().Delete(gomock.Any(), 12).Return(errors.New("database error"))
+			},
+		},
+		{
+			name: "dry-run bulk update publishes nothing",
+			run: func(s *PropertyService) error {
+				status := models.PropertyStatusSold
+				_, err := s.BulkUpdate(WithActor(context.Background(), 5), models.BulkUpdateRequest{
+					Filter: models.PropertyFilter{Status: &status},
+					Patch:  models.PropertyPatch{Status: &status},
+					DryRun: true,
+				})
+				return err
+			},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().BulkUpdate(gomock.Any(), gomock.Any(), gomock.Any(), true, nil).
+					Return(&models.BulkUpdateResult{Matched: 3, DryRun: true}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo)
+			publisher := &recordingPublisher{}
+			service := NewPropertyService(mockRepo, WithPropertyEvents(publisher))
+
+			err := tt.run(service)
+			if tt.expectType == "" {
+				if len(publisher.events) != 0 {
+					t.Errorf("Expected no events, got %+v", publisher.events)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(publisher.events) != 1 {
+				t.Fatalf("Expected one event, got %d", len(publisher.events))
+			}
+			event := publisher.events[0]
+			if event.Type != tt.expectType || event.Subject != tt.expectSubject || event.ActorID != 5 || event.SchemaVersion != events.SchemaVersion {
+				t.Errorf("Unexpected event: %+v", event)
+			}
+		})
+	}
+}
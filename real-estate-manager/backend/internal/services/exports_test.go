@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportService_Start(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, Username: "jdoe"}, nil)
+
+	ctx := WithActor(context.Background(), 7)
+	service := NewExportService(nil, mockUsers, staticSettings{SettingExportWatermark: "true"})
+	service.now = func() time.Time { return now }
+
+	export := &Export{Kind: "properties", Format: "csv"}
+	if err := service.Start(ctx, export); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "Exported by jdoe (user 7) at 2024-06-01T12:00:00Z, export " + export.ID
+	if export.ID == "" || export.UserID != 7 || export.Watermark != expected {
+		t.Errorf("Unexpected export %+v", export)
+	}
+
+	// Watermarking is off by default
+	export = &Export{Kind: "properties", Format: "ndjson"}
+	if err := NewExportService(nil, mockUsers, nil).Start(ctx, export); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if export.ID == "" || export.Watermark != "" {
+		t.Errorf("Unexpected export %+v", export)
+	}
+
+	if err := service.Start(context.Background(), &Export{}); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected an unauthorized error without a user, got %v", err)
+	}
+}
+
+func TestExportService_Finish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var recorded *models.AuditEntry
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.AuditEntry) error {
+		recorded = entry
+		return nil
+	})
+
+	service := NewExportService(NewAuditService(mockAudit), nil, nil)
+	export := &Export{Kind: "properties", Format: "csv", Filter: url.Values{"after": {"100"}}, RequestID: "req-1",
+		ID: "exp-1", UserID: 7, Watermark: "Exported by jdoe", StartedAt: time.Now()}
+	service.Finish(context.Background(), export, 250, errors.New("connection reset"))
+
+	if recorded == nil || recorded.Action != models.AuditDataExported || recorded.ActorID.Int32 != 7 ||
+		recorded.TargetType.String != "properties" || recorded.TargetID.String != "exp-1" || recorded.RequestID.String != "req-1" {
+		t.Fatalf("Unexpected audit entry %+v", recorded)
+	}
+	var details struct {
+		Format      string              `json:"format"`
+		Filter      map[string][]string `json:"filter"`
+		Rows        int                 `json:"rows"`
+		Watermarked bool                `json:"watermarked"`
+		Error       string              `json:"error"`
+	}
+	if err := json.Unmarshal(recorded.Details, &details); err != nil {
+		t.Fatalf("Unexpected details %s: %v", recorded.Details, err)
+	}
+	if details.Format != "csv" || details.Filter["after"][0] != "100" || details.Rows != 250 ||
+		!details.Watermarked || !strings.Contains(details.Error, "connection reset") {
+		t.Errorf("Unexpected details %s", recorded.Details)
+	}
+}
+
+func TestPropertyCSVRecord(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	property := models.Property{ID: 12, PublicID: "abc", Name: "123 Main St", Location: "Springfield", Price: 395000.5,
+		Status: "active", ListingType: "sale", Category: "residential", Bedrooms: models.NullInt32{NullInt32: sql.NullInt32{Int32: 3, Valid: true}},
+		CreatedAt: created, UpdatedAt: created}
+
+	record := PropertyCSVRecord(property)
+	if len(record) != len(PropertyCSVHeader) {
+		t.Fatalf("Expected %d columns, got %d", len(PropertyCSVHeader), len(record))
+	}
+	expected := "12,abc,123 Main St,Springfield,395000.5,active,sale,residential,,3,,,,2024-06-01T12:00:00Z,2024-06-01T12:00:00Z"
+	if got := strings.Join(record, ","); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// maxSavedSearches caps how many searches a user can save
+const maxSavedSearches = 20
+
+// FavoriteService manages the properties and searches users save
+type FavoriteService struct {
+	favorites  repository.FavoriteRepository
+	searches   repository.SavedSearchRepository
+	properties *PropertyService
+}
+
+func NewFavoriteService(favorites repository.FavoriteRepository, searches repository.SavedSearchRepository, properties *PropertyService) *FavoriteService {
+	return &FavoriteService{favorites: favorites, searches: searches, properties: properties}
+}
+
+// Favorites returns a user's favorite properties, most recently saved first
+func (s *FavoriteService) Favorites(ctx context.Context, userID uint) ([]models.Favorite, error) {
+	return s.favorites.List(ctx, userID)
+}
+
+func (s *FavoriteService) AddFavorite(ctx context.Context, userID uint, propertyID int) error {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return err
+	}
+	return s.favorites.Add(ctx, userID, propertyID)
+}
+
+func (s *FavoriteService) RemoveFavorite(ctx context.Context, userID uint, propertyID int) error {
+	exists, err := s.favorites.Remove(ctx, userID, propertyID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("favorite not found")
+	}
+	return nil
+}
+
+// SavedSearches returns a user's saved searches, oldest first
+func (s *FavoriteService) SavedSearches(ctx context.Context, userID uint) ([]models.SavedSearch, error) {
+	return s.searches.List(ctx, userID)
+}
+
+// SaveSearch saves listing criteria under a name for a user
+func (s *FavoriteService) SaveSearch(ctx context.Context, userID uint, search *models.SavedSearch) error {
+	search.Name = strings.TrimSpace(search.Name)
+	search.Criteria.Location = strings.TrimSpace(search.Criteria.Location)
+	search.Criteria.PropertyType = strings.TrimSpace(search.Criteria.PropertyType)
+	criteria := search.Criteria
+	switch {
+	case search.Name == "":
+		return apperrors.Validation("name is required")
+	case len(search.Name) > 100:
+		return apperrors.Validation("name must be at most 100 characters")
+	case criteria.IsEmpty():
+		return apperrors.Validation("a saved search needs at least one criterion")
+	case criteria.MinPrice != nil && criteria.MaxPrice != nil && *criteria.MinPrice > *criteria.MaxPrice:
+		return apperrors.Validation("min_price must not be more than max_price")
+	case criteria.MinBedrooms != nil && *criteria.MinBedrooms < 0:
+		return apperrors.Validation("min_bedrooms must not be negative")
+	}
+
+	count, err := s.searches.Count(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= maxSavedSearches {
+		return apperrors.Validationf("a user can save at most %d searches", maxSavedSearches)
+	}
+	search.UserID = userID
+	return s.searches.Create(ctx, search)
+}
+
+func (s *FavoriteService) DeleteSavedSearch(ctx context.Context, userID uint, id int) error {
+	exists, err := s.searches.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("saved search not found")
+	}
+	return nil
+}
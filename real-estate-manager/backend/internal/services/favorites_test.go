@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestFavoriteService_SaveSearch(t *testing.T) {
+	low, high, bedrooms := 500000.0, 300000.0, 2
+	tests := []struct {
+		name        string
+		search      models.SavedSearch
+		count       int
+		expectError bool
+	}{
+		{name: "valid", search: models.SavedSearch{Name: " Downtown ", Criteria: models.SavedSearchCriteria{Location: "Austin", MinBedrooms: &bedrooms}}},
+		{name: "no name", search: models.SavedSearch{Criteria: models.SavedSearchCriteria{Location: "Austin"}}, expectError: true},
+		{name: "no criteria", search: models.SavedSearch{Name: "Anything"}, expectError: true},
+		{name: "inverted price range", search: models.SavedSearch{Name: "Mid",
+			Criteria: models.SavedSearchCriteria{MinPrice: &low, MaxPrice: &high}}, expectError: true},
+		{name: "too many", search: models.SavedSearch{Name: "More", Criteria: models.SavedSearchCriteria{Location: "Austin"}},
+			count: maxSavedSearches, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearches := mocks.NewMockSavedSearchRepository(ctrl)
+			mockSearches.EXPECT().Count(gomock.Any(), uint(4)).Return(tt.count, nil).AnyTimes()
+			if !tt.expectError {
+				mockSearches.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewFavoriteService(nil, mockSearches, nil)
+			err := service.SaveSearch(context.Background(), 4, &tt.search)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if tt.search.Name != "Downtown" || tt.search.UserID != 4 {
+				t.Errorf("Expected a trimmed name owned by the user, got %+v", tt.search)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Known feature flags. Defaults come from FEATURE_<NAME> environment
+// variables and can be overridden at runtime through the admin API.
+const (
+	FlagPublicAPI        = "public_api"
+	FlagImageTranscoding = "image_transcoding"
+)
+
+var ErrUnknownFeatureFlag = apperrors.NotFound("unknown feature flag")
+
+var knownFlags = map[string]string{
+	FlagPublicAPI:        "Expose unauthenticated public endpoints",
+	FlagImageTranscoding: "Resize and transcode imported property photos",
+}
+
+type FeatureFlagService struct {
+	repo  repository.FeatureFlagRepository
+	mu    sync.RWMutex
+	flags map[string]models.FeatureFlag
+}
+
+func NewFeatureFlagService(repo repository.FeatureFlagRepository) *FeatureFlagService {
+	return &FeatureFlagService{repo: repo, flags: envFlags()}
+}
+
+// Load applies the flag overrides stored in the database on top of the
+// environment defaults. The defaults are read again, so calling it after
+// FEATURE_<NAME> variables change picks them up.
+func (s *FeatureFlagService) Load(ctx context.Context) error {
+	stored, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	flags := envFlags()
+	for _, flag := range stored {
+		// Overrides of retired flags are left in the database but ignored
+		if _, known := knownFlags[flag.Name]; !known {
+			continue
+		}
+		if !flag.Description.Valid {
+			flag.Description = flags[flag.Name].Description
+		}
+		flags[flag.Name] = flag
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags = flags
+	return nil
+}
+
+// IsEnabled reports whether a flag is on. Unknown flags are off.
+func (s *FeatureFlagService) IsEnabled(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.flags[name].Enabled
+}
+
+// List returns all flags sorted by name
+func (s *FeatureFlagService) List() []models.FeatureFlag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flags := make([]models.FeatureFlag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// SetFlag persists a flag override and applies it immediately
+func (s *FeatureFlagService) SetFlag(ctx context.Context, name string, enabled bool, updatedBy string) (*models.FeatureFlag, error) {
+	if _, known := knownFlags[name]; !known {
+		return nil, ErrUnknownFeatureFlag
+	}
+
+	s.mu.RLock()
+	flag := s.flags[name]
+	s.mu.RUnlock()
+
+	flag.Enabled = enabled
+	flag.UpdatedBy = models.NullString{NullString: sql.NullString{String: updatedBy, Valid: updatedBy != ""}}
+	flag.UpdatedAt = time.Now()
+	if err := s.repo.Upsert(ctx, &flag); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.flags[name] = flag
+	s.mu.Unlock()
+	return &flag, nil
+}
+
+func envFlags() map[string]models.FeatureFlag {
+	flags := make(map[string]models.FeatureFlag, len(knownFlags))
+	for name, description := range knownFlags {
+		flags[name] = models.FeatureFlag{
+			Name:        name,
+			Enabled:     envFlagDefault(name),
+			Description: nullString(description),
+		}
+	}
+	return flags
+}
+
+func envFlagDefault(name string) bool {
+	enabled, err := strconv.ParseBool(os.Getenv("FEATURE_" + strings.ToUpper(name)))
+	return err == nil && enabled
+}
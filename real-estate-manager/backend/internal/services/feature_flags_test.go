@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewFeatureFlagService_EnvDefaults(t *testing.T) {
+	os.Setenv("FEATURE_PUBLIC_API", "true")
+	defer os.Unsetenv("FEATURE_PUBLIC_API")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewFeatureFlagService(mocks.NewMockFeatureFlagRepository(ctrl))
+
+	if !service.IsEnabled(FlagPublicAPI) {
+		t.Error("Expected public_api to be enabled from environment")
+	}
+	if service.IsEnabled(FlagImageTranscoding) {
+		t.Error("Expected image_transcoding to be disabled by default")
+	}
+	if service.IsEnabled("does_not_exist") {
+		t.Error("Expected unknown flag to be disabled")
+	}
+}
+
+func TestFeatureFlagService_Load(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockFeatureFlagRepository(ctrl)
+	mockRepo.EXPECT().
+		GetAll(gomock.Any()).
+		Return([]models.FeatureFlag{{Name: FlagImageTranscoding, Enabled: true}, {Name: "new_search", Enabled: true}}, nil)
+
+	service := NewFeatureFlagService(mockRepo)
+	if err := service.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !service.IsEnabled(FlagImageTranscoding) {
+		t.Error("Expected database override to enable image_transcoding")
+	}
+	for _, flag := range service.List() {
+		if flag.Name == FlagImageTranscoding && !flag.Description.Valid {
+			t.Error("Expected description to be kept from defaults")
+		}
+		if flag.Name == "new_search" {
+			t.Error("Expected the override of a retired flag to be ignored")
+		}
+	}
+}
+
+func TestFeatureFlagService_LoadRereadsEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockFeatureFlagRepository(ctrl)
+	mockRepo.EXPECT().GetAll(gomock.Any()).Return(nil, nil)
+
+	service := NewFeatureFlagService(mockRepo)
+	os.Setenv("FEATURE_IMAGE_TRANSCODING", "true")
+	defer os.Unsetenv("FEATURE_IMAGE_TRANSCODING")
+
+	if err := service.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !service.IsEnabled(FlagImageTranscoding) {
+		t.Error("Expected a reload to pick up the changed environment default")
+	}
+}
+
+func TestFeatureFlagService_SetFlag(t *testing.T) {
+	tests := []struct {
+		name        string
+		flagName    string
+		setupMock   func(mock *mocks.MockFeatureFlagRepository)
+		expectError error
+		expectState bool
+	}{
+		{
+			name:     "enable known flag",
+			flagName: FlagImageTranscoding,
+			setupMock: func(mock *mocks.MockFeatureFlagRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectState: true,
+		},
+		{
+			name:        "unknown flag",
+			flagName:    "unknown",
+			setupMock:   func(mock *mocks.MockFeatureFlagRepository) {},
+			expectError: ErrUnknownFeatureFlag,
+		},
+		{
+			name:     "repository error leaves flag unchanged",
+			flagName: FlagImageTranscoding,
+			setupMock: func(mock *mocks.MockFeatureFlagRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectError: errors.New("database error"),
+			expectState: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockFeatureFlagRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewFeatureFlagService(mockRepo)
+			flag, err := service.SetFlag(context.Background(), tt.flagName, true, "admin")
+
+			if tt.expectError != nil {
+				if err == nil || err.Error() != tt.expectError.Error() {
+					t.Errorf("Expected error '%v', got '%v'", tt.expectError, err)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if flag == nil || flag.UpdatedBy.String != "admin" {
+					t.Errorf("Expected flag updated by admin, got %+v", flag)
+				}
+			}
+
+			if service.IsEnabled(tt.flagName) != tt.expectState {
+				t.Errorf("Expected flag state %v, got %v", tt.expectState, service.IsEnabled(tt.flagName))
+			}
+		})
+	}
+}
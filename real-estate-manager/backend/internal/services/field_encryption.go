@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/pkg/fieldcrypt"
+)
+
+// fieldRotationBatch is how many values per column are encrypted again in
+// one pass
+const fieldRotationBatch = 500
+
+// FieldEncryptionService keeps encrypted columns on the primary key once
+// FIELD_ENCRYPTION_KEYS changes
+type FieldEncryptionService struct {
+	repo    repository.EncryptedFieldRepository
+	enabled bool
+}
+
+// NewFieldEncryptionService creates the service; cipher is nil when no
+// keys are configured
+func NewFieldEncryptionService(repo repository.EncryptedFieldRepository, cipher *fieldcrypt.Cipher) *FieldEncryptionService {
+	return &FieldEncryptionService{repo: repo, enabled: cipher != nil}
+}
+
+// Enabled reports whether encryption keys are configured
+func (s *FieldEncryptionService) Enabled() bool {
+	return s.enabled
+}
+
+// RotateAll encrypts every plaintext value and every value encrypted with
+// an older key with the primary key, in batches, and returns how many
+// values changed. Once it has run, older keys can be removed.
+func (s *FieldEncryptionService) RotateAll(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		rotated, err := s.repo.Rotate(ctx, fieldRotationBatch)
+		total += rotated
+		if err != nil || rotated == 0 {
+			return total, err
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
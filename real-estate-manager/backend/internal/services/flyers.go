@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/worker"
+	"real-estate-manager/backend/pkg/imaging"
+	"real-estate-manager/backend/pkg/pdf"
+	"real-estate-manager/backend/pkg/qrcode"
+	"real-estate-manager/backend/pkg/units"
+)
+
+// ErrFlyerPending is returned while a flyer is being generated
+var ErrFlyerPending = errors.New("flyer is being generated")
+
+// Flyer is a generated listing flyer ready to be served
+type Flyer struct {
+	Path string
+	ETag string
+}
+
+// FlyerConfig brands the flyers. ListingURL is the public page of a
+// listing, with "{id}" replaced by its public ID; it is linked from the
+// QR code.
+type FlyerConfig struct {
+	BrandName  string
+	ListingURL string
+}
+
+var (
+	flyerBrand = color.RGBA{24, 52, 92, 255}
+	flyerMuted = color.RGBA{100, 110, 120, 255}
+	flyerText  = color.RGBA{30, 30, 30, 255}
+	flyerPanel = color.RGBA{240, 243, 247, 255}
+)
+
+// flyerPhotoQuality is the JPEG quality of photos embedded in flyers
+const flyerPhotoQuality = 80
+
+// FlyerService renders one-page PDF listing flyers. Rendering happens on
+// the image worker pool on first request and the result is cached on disk
+// until the listing changes.
+type FlyerService struct {
+	properties *PropertyService
+	users      repository.UserRepository
+	workers    *worker.Pool
+	imagesDir  string
+	cacheDir   string
+	config     FlyerConfig
+
+	mu      sync.Mutex
+	pending map[string]bool
+	failed  map[string]error
+	wg      sync.WaitGroup
+}
+
+func NewFlyerService(properties *PropertyService, users repository.UserRepository, workers *worker.Pool, imagesDir, cacheDir string, config FlyerConfig) *FlyerService {
+	os.MkdirAll(cacheDir, 0755)
+	if config.BrandName == "" {
+		config.BrandName = "Real Estate Manager"
+	}
+	return &FlyerService{
+		properties: properties,
+		users:      users,
+		workers:    workers,
+		imagesDir:  imagesDir,
+		cacheDir:   cacheDir,
+		config:     config,
+		pending:    make(map[string]bool),
+		failed:     make(map[string]error),
+	}
+}
+
+// Flyer returns the cached flyer of a property in the given unit system.
+// When there is none yet, it starts rendering one and returns
+// ErrFlyerPending; the caller should retry shortly. A failed render is
+// reported once and retried on the next request.
+func (s *FlyerService) Flyer(ctx context.Context, propertyID int, system units.System) (*Flyer, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%d|%s|%s|%s", property.ID, property.Version, property.AgentID.Int32,
+		system, s.config.BrandName, s.listingURL(property))))
+	key := hex.EncodeToString(sum[:16])
+	flyer := &Flyer{Path: filepath.Join(s.cacheDir, key+".pdf"), ETag: `"` + key + `"`}
+	if _, err := os.Stat(flyer.Path); err == nil {
+		return flyer, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err, ok := s.failed[key]; ok {
+		delete(s.failed, key)
+		return nil, err
+	}
+	if !s.pending[key] {
+		// Rendering outlives the request that started it, so it keeps the
+		// request's values but not its cancellation
+		renderCtx := context.WithoutCancel(ctx)
+		s.wg.Add(1)
+		err := s.workers.Submit(func(context.Context) error {
+			s.generate(renderCtx, key, flyer.Path, property, system)
+			return nil
+		})
+		if err != nil {
+			s.wg.Done()
+			return nil, apperrors.Unavailable("flyer renderer is busy, try again shortly")
+		}
+		s.pending[key] = true
+	}
+	return nil, ErrFlyerPending
+}
+
+// Wait blocks until flyers being rendered are done
+func (s *FlyerService) Wait() {
+	s.wg.Wait()
+}
+
+func (s *FlyerService) generate(ctx context.Context, key, path string, property *models.Property, system units.System) {
+	defer s.wg.Done()
+	err := s.render(ctx, path, property, system)
+	if err != nil {
+		log.Printf("Failed to render flyer for property %d: %v", property.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, key)
+	if err != nil {
+		s.failed[key] = err
+	}
+}
+
+func (s *FlyerService) listingURL(property *models.Property) string {
+	return expandListingURL(s.config.ListingURL, property)
+}
+
+// expandListingURL fills the property's public ID into a public listing
+// URL template
+func expandListingURL(template string, property *models.Property) string {
+	return strings.ReplaceAll(template, "{id}", property.PublicID)
+}
+
+// render lays out the flyer: a branded header, the name and price, the
+// photos, specs and description, and a footer with the agent's contact
+// details and a QR code linking to the public listing
+func (s *FlyerService) render(ctx context.Context, path string, property *models.Property, system units.System) error {
+	const margin = 36.0
+	const contentWidth = pdf.LetterWidth - 2*margin
+
+	doc := pdf.New()
+	page := doc.AddPage(pdf.LetterWidth, pdf.LetterHeight)
+
+	page.Rect(0, 0, pdf.LetterWidth, 64, flyerBrand)
+	page.Text(margin, 40, pdf.HelveticaBold, 18, color.White, s.config.BrandName)
+	status := strings.ToUpper(flyerStatusLabel(property.Status))
+	page.Text(pdf.LetterWidth-margin-pdf.TextWidth(pdf.HelveticaBold, 14, status), 39, pdf.HelveticaBold, 14, color.White, status)
+
+	y := 98.0
+	price := formatPrice(property.Price)
+	priceWidth := pdf.TextWidth(pdf.HelveticaBold, 22, price)
+	page.Text(pdf.LetterWidth-margin-priceWidth, y, pdf.HelveticaBold, 22, flyerBrand, price)
+	page.Text(margin, y, pdf.HelveticaBold, 22, flyerText, pdf.Truncate(pdf.HelveticaBold, 22, property.Name, contentWidth-priceWidth-16))
+	y += 20
+	page.Text(margin, y, pdf.Helvetica, 12, flyerMuted, pdf.Truncate(pdf.Helvetica, 12, property.Location, contentWidth))
+	y += 14
+
+	photos := s.loadPhotos(property, 3)
+	if len(photos) > 0 {
+		if err := placePhoto(page, photos[0], margin, y, contentWidth, 240); err != nil {
+			return err
+		}
+		y += 240 + 8
+		width := (contentWidth - 8) / 2
+		for i, photo := range photos[1:] {
+			if err := placePhoto(page, photo, margin+float64(i)*(width+8), y, width, 100); err != nil {
+				return err
+			}
+		}
+		if len(photos) > 1 {
+			y += 100 + 8
+		}
+	}
+
+	specs := flyerSpecs(property, system)
+	if len(specs) > 0 {
+		page.Rect(margin, y, contentWidth, 44, flyerPanel)
+		column := contentWidth / float64(len(specs))
+		for i, spec := range specs {
+			x := margin + 12 + float64(i)*column
+			page.Text(x, y+18, pdf.HelveticaBold, 12, flyerText, pdf.Truncate(pdf.HelveticaBold, 12, spec[1], column-16))
+			page.Text(x, y+33, pdf.Helvetica, 9, flyerMuted, strings.ToUpper(spec[0]))
+		}
+		y += 44
+	}
+
+	const footerTop = pdf.LetterHeight - 128.0
+	if property.Description.Valid {
+		y += 22
+		lines := pdf.WrapText(pdf.Helvetica, 10.5, property.Description.String, contentWidth)
+		maxLines := int((footerTop - 12 - y) / 14)
+		if len(lines) > maxLines && maxLines > 0 {
+			lines = lines[:maxLines]
+			lines[maxLines-1] = pdf.Truncate(pdf.Helvetica, 10.5, lines[maxLines-1]+" …", contentWidth)
+		}
+		for _, line := range lines {
+			if y > footerTop-12 {
+				break
+			}
+			page.Text(margin, y, pdf.Helvetica, 10.5, flyerText, line)
+			y += 14
+		}
+	}
+
+	page.Line(margin, footerTop, pdf.LetterWidth-margin, footerTop, 0.75, flyerPanel)
+	if err := s.drawAgent(ctx, page, property, margin, footerTop+28); err != nil {
+		return err
+	}
+
+	url := s.listingURL(property)
+	if url != "" {
+		code, err := qrcode.Encode([]byte(url))
+		if err != nil {
+			return fmt.Errorf("failed to encode listing URL: %w", err)
+		}
+		const qrSize = 88.0
+		qrX := pdf.LetterWidth - margin - qrSize
+		drawQRCode(page, code, qrX, footerTop+14, qrSize)
+		page.Text(qrX-8-pdf.TextWidth(pdf.Helvetica, 9, "Scan for photos and details"), footerTop+58, pdf.Helvetica, 9, flyerMuted, "Scan for photos and details")
+	}
+
+	// Written to a temporary file first so a partially written flyer is
+	// never served
+	tmp, err := os.CreateTemp(s.cacheDir, "flyer-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create flyer: %w", err)
+	}
+	if err := doc.Write(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write flyer: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save flyer: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save flyer: %w", err)
+	}
+	return nil
+}
+
+// loadPhotos decodes up to limit of the property's locally stored photos.
+// Photos that are missing or in a format the standard library cannot
+// decode are skipped.
+func (s *FlyerService) loadPhotos(property *models.Property, limit int) []*image.RGBA {
+	var photos []*image.RGBA
+	for _, photo := range property.Photos {
+		if len(photos) == limit {
+			break
+		}
+		if !strings.HasPrefix(photo.LocalURL, "/images/") {
+			continue
+		}
+		file, err := os.Open(filepath.Join(s.imagesDir, filepath.Base(photo.LocalURL)))
+		if err != nil {
+			continue
+		}
+		decoded, _, err := image.Decode(file)
+		file.Close()
+		if err != nil {
+			continue
+		}
+		photos = append(photos, imaging.Flatten(decoded))
+	}
+	return photos
+}
+
+// placePhoto fills the frame with the photo, cropped to its aspect ratio,
+// at twice the frame's point size for print
+func placePhoto(page *pdf.Page, photo *image.RGBA, x, y, width, height float64) error {
+	return page.Image(imaging.Fill(photo, int(width*2), int(height*2)), x, y, width, height, flyerPhotoQuality)
+}
+
+func (s *FlyerService) drawAgent(ctx context.Context, page *pdf.Page, property *models.Property, x, y float64) error {
+	page.Text(x, y, pdf.Helvetica, 9, flyerMuted, "CONTACT")
+	if !property.AgentID.Valid {
+		page.Text(x, y+20, pdf.HelveticaBold, 14, flyerText, s.config.BrandName)
+		return nil
+	}
+
+	agent, err := s.users.GetByID(ctx, uint(property.AgentID.Int32))
+	if err != nil {
+		return fmt.Errorf("failed to get agent: %w", err)
+	}
+	if agent == nil {
+		page.Text(x, y+20, pdf.HelveticaBold, 14, flyerText, s.config.BrandName)
+		return nil
+	}
+	page.Text(x, y+20, pdf.HelveticaBold, 14, flyerText, agent.Username)
+	page.Text(x, y+38, pdf.Helvetica, 11, flyerText, agent.Email)
+	return nil
+}
+
+// drawQRCode draws code as dark squares in a size x size area, inside the
+// four-module quiet zone the standard requires
+func drawQRCode(page *pdf.Page, code *qrcode.Code, x, y, size float64) {
+	module := size / float64(code.Size+8)
+	page.Rect(x, y, size, size, color.White)
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; col++ {
+			if code.Dark(row, col) {
+				// Slightly oversized so neighbouring modules leave no seams
+				page.Rect(x+float64(col+4)*module, y+float64(row+4)*module, module*1.02, module*1.02, color.Black)
+			}
+		}
+	}
+}
+
+// flyerSpecs returns the label and value of each known fact about the
+// listing
+func flyerSpecs(property *models.Property, system units.System) [][2]string {
+	var specs [][2]string
+	if property.Bedrooms.Valid {
+		specs = append(specs, [2]string{"Bedrooms", strconv.Itoa(int(property.Bedrooms.Int32))})
+	}
+	if property.Bathrooms.Valid {
+		specs = append(specs, [2]string{"Bathrooms", strconv.Itoa(int(property.Bathrooms.Int32))})
+	}
+	property.ApplyUnits(system)
+	if property.Area != nil {
+		specs = append(specs, [2]string{"Living area", formatMeasurement(*property.Area)})
+	}
+	if property.Lot != nil {
+		specs = append(specs, [2]string{"Lot", formatMeasurement(*property.Lot)})
+	}
+	if property.YearBuilt.Valid {
+		specs = append(specs, [2]string{"Built", strconv.Itoa(int(property.YearBuilt.Int32))})
+	}
+	if property.Zoning != nil {
+		specs = append(specs, [2]string{"Zoning", *property.Zoning})
+	}
+	if len(property.Utilities) > 0 {
+		specs = append(specs, [2]string{"Utilities", strings.Join(property.Utilities, ", ")})
+	}
+	if property.MLSNumber.Valid && property.MLSNumber.String != "" {
+		specs = append(specs, [2]string{"MLS #", property.MLSNumber.String})
+	}
+	return specs
+}
+
+var measurementLabels = map[string]string{units.SquareFeet: "sq ft", units.SquareMeters: "m²", units.Acres: "acres", units.Hectares: "ha"}
+
+func formatMeasurement(m models.Measurement) string {
+	value := strconv.FormatFloat(m.Value, 'f', -1, 64)
+	if m.Value >= 1000 {
+		value = groupThousands(int64(m.Value + 0.5))
+	}
+	return value + " " + measurementLabels[m.Unit]
+}
+
+// formatPrice formats a price in whole dollars, e.g. $1,250,000
+func formatPrice(price float64) string {
+	return "$" + groupThousands(int64(price+0.5))
+}
+
+func groupThousands(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+func flyerStatusLabel(status string) string {
+	switch status {
+	case models.PropertyStatusPending:
+		return "Sale pending"
+	case models.PropertyStatusSold:
+		return "Sold"
+	case models.PropertyStatusWithdrawn:
+		return "Off market"
+	default:
+		return "For sale"
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/units"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestFlyerService_Flyer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imagesDir := t.TempDir()
+	writeTestPNG(t, filepath.Join(imagesDir, "front.png"), 1200, 800)
+	writeTestPNG(t, filepath.Join(imagesDir, "kitchen.png"), 600, 900)
+
+	property := &models.Property{
+		ID: 12, Name: "Riverside Loft", Location: "12 Quay St, Lisbon", Price: 1250000, Status: models.PropertyStatusActive, Version: 3,
+		Description: models.NullString{NullString: sql.NullString{String: strings.Repeat("Bright corner unit with river views. ", 60), Valid: true}},
+		Bedrooms:    models.NullInt32{NullInt32: sql.NullInt32{Int32: 3, Valid: true}},
+		AgentID:     models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}},
+		Photos: models.PhotoList{
+			{URL: "/images/front.png", LocalURL: "/images/front.png"},
+			{URL: "https://mls.example.com/remote.jpg"},
+			{URL: "/images/kitchen.png", LocalURL: "/images/kitchen.png"},
+		},
+	}
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(property, nil).Times(2)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 13).Return(nil, nil)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().GetByID(gomock.Any(), uint(4)).Return(&models.User{ID: 4, Username: "Ana Silva", Email: "ana@example.com"}, nil)
+
+	service := NewFlyerService(NewPropertyService(mockProperties), mockUsers, testWorkers(t), imagesDir, t.TempDir(),
+		FlyerConfig{ListingURL: "https://listings.example.com/properties/{id}"})
+
+	if _, err := service.Flyer(context.Background(), 12, units.Imperial); !errors.Is(err, ErrFlyerPending) {
+		t.Fatalf("Expected the first request to start rendering, got %v", err)
+	}
+	service.Wait()
+
+	flyer, err := service.Flyer(context.Background(), 12, units.Imperial)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(flyer.Path)
+	if err != nil {
+		t.Fatalf("Expected the flyer to be cached: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "%PDF-") {
+		t.Error("Expected a PDF")
+	}
+	if images := strings.Count(string(content), "/Subtype /Image"); images != 2 {
+		t.Errorf("Expected both local photos to be embedded, got %d images", images)
+	}
+
+	if _, err := service.Flyer(context.Background(), 13, units.Imperial); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found for a missing property, got %v", err)
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	for price, expected := range map[float64]string{0: "$0", 950: "$950", 1250000: "$1,250,000", 349999.6: "$350,000"} {
+		if got := formatPrice(price); got != expected {
+			t.Errorf("formatPrice(%v) = %q, expected %q", price, got, expected)
+		}
+	}
+}
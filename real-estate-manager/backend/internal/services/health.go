@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// Dependency states reported by the health service
+const (
+	HealthUp       = "up"
+	HealthDown     = "down"
+	HealthDegraded = "degraded"
+)
+
+// Health check timing: a check taking longer than dependencyCheckTimeout
+// is down, and results are reused for dependencyCheckTTL so frequent
+// dashboard polling does not hammer the dependencies
+const (
+	dependencyCheckTimeout = 2 * time.Second
+	dependencyCheckTTL     = 30 * time.Second
+)
+
+// DependencyChecker checks that one dependency is reachable and working
+type DependencyChecker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc adapts a function to a DependencyChecker
+type CheckFunc func(ctx context.Context) error
+
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// PingCheck checks a connection such as *sql.DB
+func PingCheck(p Pinger) DependencyChecker {
+	return CheckFunc(p.PingContext)
+}
+
+// DirCheck checks that files can be written to dir
+func DirCheck(dir string) DependencyChecker {
+	return CheckFunc(func(ctx context.Context) error {
+		file, err := os.CreateTemp(dir, ".health-*")
+		if err != nil {
+			return err
+		}
+		file.Close()
+		return os.Remove(file.Name())
+	})
+}
+
+// DependencyStatus is the outcome of a dependency's latest check
+type DependencyStatus struct {
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Critical    bool      `json:"critical"`
+	LatencyMS   int64     `json:"latency_ms"`
+	LastChecked time.Time `json:"last_checked"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// HealthReport is the state of every registered dependency. The server is
+// down when a critical dependency is, and degraded when another one is.
+type HealthReport struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+type dependency struct {
+	name     string
+	critical bool
+	checker  DependencyChecker
+}
+
+// HealthService is a registry of the dependencies the server relies on,
+// checked on demand for ops dashboards
+type HealthService struct {
+	mu           sync.Mutex
+	dependencies []dependency
+	results      map[string]DependencyStatus
+	now          func() time.Time
+}
+
+func NewHealthService() *HealthService {
+	return &HealthService{results: make(map[string]DependencyStatus), now: time.Now}
+}
+
+// Register adds a dependency. Critical dependencies are those requests
+// cannot be served without.
+func (s *HealthService) Register(name string, critical bool, checker DependencyChecker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dependencies = append(s.dependencies, dependency{name: name, critical: critical, checker: checker})
+}
+
+// Details checks the dependencies whose last result is older than
+// dependencyCheckTTL, concurrently, and reports all of them in the order
+// they were registered
+func (s *HealthService) Details(ctx context.Context) HealthReport {
+	s.mu.Lock()
+	dependencies := s.dependencies
+	now := s.now()
+	var stale []dependency
+	for _, dep := range dependencies {
+		if result, ok := s.results[dep.name]; !ok || now.Sub(result.LastChecked) >= dependencyCheckTTL {
+			stale = append(stale, dep)
+		}
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, dep := range stale {
+		wg.Add(1)
+		go func(dep dependency) {
+			defer wg.Done()
+			result := s.check(ctx, dep)
+			s.mu.Lock()
+			s.results[dep.name] = result
+			s.mu.Unlock()
+		}(dep)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := HealthReport{Status: HealthUp, Dependencies: make([]DependencyStatus, 0, len(dependencies))}
+	for _, dep := range dependencies {
+		result := s.results[dep.name]
+		report.Dependencies = append(report.Dependencies, result)
+		if result.Status == HealthUp {
+			continue
+		}
+		if dep.critical {
+			report.Status = HealthDown
+		} else if report.Status == HealthUp {
+			report.Status = HealthDegraded
+		}
+	}
+	return report
+}
+
+func (s *HealthService) check(ctx context.Context, dep dependency) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+
+	started := s.now()
+	err := dep.checker.Check(ctx)
+	result := DependencyStatus{
+		Name:        dep.name,
+		Status:      HealthUp,
+		Critical:    dep.critical,
+		LatencyMS:   s.now().Sub(started).Milliseconds(),
+		LastChecked: started,
+	}
+	if err != nil {
+		result.Status = HealthDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingCheck fails with err and counts how often it was checked
+type countingCheck struct {
+	err   error
+	calls int
+}
+
+func (c *countingCheck) Check(ctx context.Context) error {
+	c.calls++
+	return c.err
+}
+
+func TestHealthService_Details(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	service := NewHealthService()
+	service.now = func() time.Time { return now }
+
+	database := &countingCheck{}
+	mail := &countingCheck{err: errors.New("connection refused")}
+	service.Register("mysql", true, database)
+	service.Register("mailer", false, mail)
+
+	report := service.Details(context.Background())
+	if report.Status != HealthDegraded {
+		t.Errorf("Expected a failing optional dependency to degrade the server, got %s", report.Status)
+	}
+	if len(report.Dependencies) != 2 || report.Dependencies[0].Name != "mysql" || report.Dependencies[1].Name != "mailer" {
+		t.Fatalf("Expected dependencies in registration order, got %+v", report.Dependencies)
+	}
+	if got := report.Dependencies[1]; got.Status != HealthDown || got.Error != "connection refused" || !got.LastChecked.Equal(now) {
+		t.Errorf("Unexpected mailer status %+v", got)
+	}
+
+	// Results are reused until they are dependencyCheckTTL old
+	now = now.Add(dependencyCheckTTL / 2)
+	service.Details(context.Background())
+	if database.calls != 1 || mail.calls != 1 {
+		t.Errorf("Expected cached results, got %d and %d checks", database.calls, mail.calls)
+	}
+
+	now = now.Add(dependencyCheckTTL)
+	database.err = errors.New("too many connections")
+	mail.err = nil
+	report = service.Details(context.Background())
+	if database.calls != 2 || mail.calls != 2 {
+		t.Errorf("Expected stale results to be checked again, got %d and %d checks", database.calls, mail.calls)
+	}
+	if report.Status != HealthDown {
+		t.Errorf("Expected a failing critical dependency to take the server down, got %s", report.Status)
+	}
+}
+
+func TestHealthService_DetailsTimeout(t *testing.T) {
+	service := NewHealthService()
+	service.Register("simplyrets", false, CheckFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := service.Details(ctx)
+	if report.Status != HealthDegraded || report.Dependencies[0].Error == "" {
+		t.Errorf("Expected a cancelled check to be down, got %+v", report)
+	}
+}
+
+func TestDirCheck(t *testing.T) {
+	if err := DirCheck(t.TempDir()).Check(context.Background()); err != nil {
+		t.Errorf("Expected a writable directory to pass, got %v", err)
+	}
+	if err := DirCheck("/nonexistent/uploads").Check(context.Background()); err == nil {
+		t.Error("Expected a missing directory to fail")
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// ImpersonationTTL is how long an impersonation token is valid
+const ImpersonationTTL = 30 * time.Minute
+
+// ImpersonationToken is issued to a support admin acting as another user
+type ImpersonationToken struct {
+	Token        string       `json:"token"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	User         *models.User `json:"user"`
+	Impersonator *models.User `json:"impersonator"`
+}
+
+// ImpersonationService lets admins act as another user to reproduce
+// user-specific issues. Every token issued is audited, as is every request
+// made with one.
+type ImpersonationService struct {
+	auth  *AuthService
+	users repository.UserRepository
+	audit *AuditService
+}
+
+func NewImpersonationService(auth *AuthService, users repository.UserRepository, audit *AuditService) *ImpersonationService {
+	return &ImpersonationService{auth: auth, users: users, audit: audit}
+}
+
+// Impersonate issues a short-lived token carrying the target's identity
+// plus impersonator_id and impersonator claims for the admin. Admins cannot
+// be impersonated, so the token never grants more than the target has.
+func (s *ImpersonationService) Impersonate(ctx context.Context, adminID, targetID uint, reason, requestID string) (*ImpersonationToken, error) {
+	if adminID == targetID {
+		return nil, apperrors.Validation("cannot impersonate yourself")
+	}
+
+	admin, err := s.loadUser(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.loadUser(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == models.RoleAdmin {
+		return nil, apperrors.Forbidden("admins cannot be impersonated")
+	}
+
+	// The token is only issued once the audit entry is stored
+	details, _ := json.Marshal(map[string]string{"reason": reason, "target_username": target.Username})
+	entry := &models.AuditEntry{
+		Action:         models.AuditImpersonationStarted,
+		ActorID:        nullID(int(adminID)),
+		ImpersonatorID: nullID(int(adminID)),
+		TargetType:     nullString("user"),
+		TargetID:       nullString(strconv.Itoa(int(targetID))),
+		Details:        details,
+		RequestID:      nullString(requestID),
+	}
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	claims := userClaims(target, ImpersonationTTL)
+	claims["impersonator_id"] = admin.ID
+	claims["impersonator"] = admin.Username
+	token, err := s.auth.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	target.Password = ""
+	admin.Password = ""
+	return &ImpersonationToken{
+		Token:        token,
+		ExpiresAt:    time.Unix(claims["exp"].(int64), 0).UTC(),
+		User:         target,
+		Impersonator: admin,
+	}, nil
+}
+
+func (s *ImpersonationService) loadUser(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("user not found")
+	}
+	return user, err
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	admin := &models.User{ID: 1, Username: "support", Role: models.RoleAdmin}
+
+	tests := []struct {
+		name        string
+		targetID    uint
+		setupMock   func(users *mocks.MockUserRepository, audit *mocks.MockAuditRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:     "issues audited token",
+			targetID: 7,
+			setupMock: func(users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(1)).Return(admin, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, Username: "alice", Role: models.RoleUser}, nil)
+				audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.AuditEntry) error {
+					if entry.Action != models.AuditImpersonationStarted || entry.TargetID.String != "7" || entry.ImpersonatorID.Int32 != 1 {
+						t.Errorf("Unexpected audit entry: %+v", entry)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:     "admins cannot be impersonated",
+			targetID: 2,
+			setupMock: func(users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(1)).Return(admin, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(2)).Return(&models.User{ID: 2, Role: models.RoleAdmin}, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrForbidden,
+		},
+		{
+			name:        "cannot impersonate yourself",
+			targetID:    1,
+			setupMock:   func(*mocks.MockUserRepository, *mocks.MockAuditRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:     "unknown user",
+			targetID: 9,
+			setupMock: func(users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(1)).Return(admin, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(9)).Return(nil, sql.ErrNoRows)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrNotFound,
+		},
+		{
+			name:     "no token without an audit entry",
+			targetID: 7,
+			setupMock: func(users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(1)).Return(admin, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, Role: models.RoleUser}, nil)
+				audit.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockAudit := mocks.NewMockAuditRepository(ctrl)
+			tt.setupMock(mockUsers, mockAudit)
+
+			auth := NewAuthServiceWithSecret(mockUsers, "test-secret-that-is-long-enough-123")
+			service := NewImpersonationService(auth, mockUsers, NewAuditService(mockAudit))
+			result, err := service.Impersonate(context.Background(), 1, tt.targetID, "ticket 42", "req-1")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			claims, err := auth.ValidateToken(result.Token)
+			if err != nil {
+				t.Fatalf("Expected a valid token, got %v", err)
+			}
+			if (*claims)["user_id"].(float64) != 7 || (*claims)["impersonator_id"].(float64) != 1 || (*claims)["role"] != models.RoleUser {
+				t.Errorf("Unexpected claims: %v", *claims)
+			}
+			if result.User.Password != "" || result.Impersonator.Password != "" {
+				t.Error("Expected passwords to be cleared")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+)
+
+// QuotaWindow is a user's usage of one import quota window. A limit of 0
+// means the window is unlimited, in which case Remaining is nil.
+type QuotaWindow struct {
+	Limit     int        `json:"limit"`
+	Used      int        `json:"used"`
+	Remaining *int       `json:"remaining"`
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+}
+
+func (w QuotaWindow) exceeded() bool {
+	return w.Remaining != nil && *w.Remaining == 0
+}
+
+// ImportQuotaStatus is how many SimplyRETS import jobs a user has started
+// in the last hour and day against their limits
+type ImportQuotaStatus struct {
+	Exempt bool        `json:"exempt"`
+	Hourly QuotaWindow `json:"hourly"`
+	Daily  QuotaWindow `json:"daily"`
+}
+
+// RetryAfter is how long until the user may start another job, zero if
+// they may now
+func (s *ImportQuotaStatus) RetryAfter(now time.Time) time.Duration {
+	var wait time.Duration
+	for _, w := range []QuotaWindow{s.Hourly, s.Daily} {
+		if w.exceeded() && w.ResetAt != nil && w.ResetAt.Sub(now) > wait {
+			wait = w.ResetAt.Sub(now)
+		}
+	}
+	return wait
+}
+
+// RetryAfterHeader formats RetryAfter in whole seconds for a Retry-After
+// header
+func (s *ImportQuotaStatus) RetryAfterHeader(now time.Time) string {
+	return strconv.Itoa(int(math.Ceil(s.RetryAfter(now).Seconds())))
+}
+
+// ImportQuotaService limits how many SimplyRETS import jobs a user may
+// start per hour and per day, so nobody hammers the feed by accident.
+// Admins are exempt, and can reset a user's usage. Usage is kept in
+// memory, so the quotas apply per server instance and restart with it.
+type ImportQuotaService struct {
+	hourly *windowLimiter
+	daily  *windowLimiter
+	now    func() time.Time
+}
+
+func NewImportQuotaService(settings SettingsProvider) *ImportQuotaService {
+	return &ImportQuotaService{
+		hourly: newWindowLimiter(time.Hour, func() int {
+			return settings.GetInt(SettingImportJobsHourly)
+		}),
+		daily: newWindowLimiter(24*time.Hour, func() int {
+			return settings.GetInt(SettingImportJobsDaily)
+		}),
+		now: time.Now,
+	}
+}
+
+// Status reports a user's usage of their import quotas
+func (s *ImportQuotaService) Status(userID uint, exempt bool) *ImportQuotaStatus {
+	now := s.now()
+	key := strconv.FormatUint(uint64(userID), 10)
+	return &ImportQuotaStatus{
+		Exempt: exempt,
+		Hourly: quotaWindow(s.hourly, key, now, exempt),
+		Daily:  quotaWindow(s.daily, key, now, exempt),
+	}
+}
+
+// Check returns a user's quota status and a rate limited error if they may
+// not start another import job now
+func (s *ImportQuotaService) Check(userID uint, exempt bool) (*ImportQuotaStatus, error) {
+	status := s.Status(userID, exempt)
+	if status.Hourly.exceeded() {
+		return status, apperrors.RateLimited("hourly import job quota reached, try again later")
+	}
+	if status.Daily.exceeded() {
+		return status, apperrors.RateLimited("daily import job quota reached, try again later")
+	}
+	return status, nil
+}
+
+// Record counts a job a user started against their quotas
+func (s *ImportQuotaService) Record(userID uint) {
+	now := s.now()
+	key := strconv.FormatUint(uint64(userID), 10)
+	s.hourly.Add(key, now)
+	s.daily.Add(key, now)
+}
+
+// Reset clears a user's usage so they may start jobs again right away
+func (s *ImportQuotaService) Reset(userID uint) {
+	key := strconv.FormatUint(uint64(userID), 10)
+	s.hourly.Reset(key)
+	s.daily.Reset(key)
+}
+
+func quotaWindow(limiter *windowLimiter, key string, now time.Time, exempt bool) QuotaWindow {
+	used, resetAt := limiter.Usage(key, now)
+	window := QuotaWindow{Used: used}
+	if !resetAt.IsZero() {
+		window.ResetAt = &resetAt
+	}
+	if exempt {
+		return window
+	}
+	window.Limit = limiter.limit()
+	if window.Limit > 0 {
+		remaining := max(window.Limit-used, 0)
+		window.Remaining = &remaining
+	}
+	return window
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+)
+
+func TestImportQuotaService_Check(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	service := NewImportQuotaService(staticSettings{SettingImportJobsHourly: "2", SettingImportJobsDaily: "3"})
+	service.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.Check(4, false); err != nil {
+			t.Fatalf("job %d: unexpected error %v", i+1, err)
+		}
+		service.Record(4)
+		now = now.Add(10 * time.Minute)
+	}
+	status, err := service.Check(4, false)
+	if !errors.Is(err, apperrors.ErrRateLimited) {
+		t.Fatalf("Expected the hourly quota to be reached, got %v", err)
+	}
+	if status.Hourly.Used != 2 || *status.Hourly.Remaining != 0 || *status.Daily.Remaining != 1 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	// The first job leaves the hour 40 minutes from now
+	if wait := status.RetryAfter(now); wait != 40*time.Minute {
+		t.Errorf("Expected to retry in 40 minutes, got %v", wait)
+	}
+
+	// Admins are exempt and other users keep their own quota
+	if _, err := service.Check(4, true); err != nil {
+		t.Errorf("Expected admins to be exempt, got %v", err)
+	}
+	if _, err := service.Check(5, false); err != nil {
+		t.Errorf("Expected another user to have their own quota, got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := service.Check(4, false); err != nil {
+		t.Fatalf("Expected the hourly quota to be free again, got %v", err)
+	}
+	service.Record(4)
+	if _, err := service.Check(4, false); !errors.Is(err, apperrors.ErrRateLimited) {
+		t.Fatalf("Expected the daily quota to be reached, got %v", err)
+	}
+
+	service.Reset(4)
+	if status, err := service.Check(4, false); err != nil || status.Daily.Used != 0 {
+		t.Errorf("Expected a reset to clear the usage, got %+v and %v", status, err)
+	}
+}
+
+func TestImportQuotaService_Unlimited(t *testing.T) {
+	service := NewImportQuotaService(staticSettings{SettingImportJobsHourly: "0", SettingImportJobsDaily: "0"})
+	for i := 0; i < 50; i++ {
+		service.Record(4)
+	}
+	status, err := service.Check(4, false)
+	if err != nil {
+		t.Fatalf("Expected no limit, got %v", err)
+	}
+	if status.Hourly.Remaining != nil || status.Hourly.Used != 50 {
+		t.Errorf("Unexpected status %+v", status.Hourly)
+	}
+}
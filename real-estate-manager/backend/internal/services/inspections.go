@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// maxRepairDescription caps the length of a repair's description
+const maxRepairDescription = 500
+
+// InspectionService schedules property inspections, keeps their report
+// documents and tracks the repairs they call for until they are done
+type InspectionService struct {
+	repo       repository.InspectionRepository
+	properties *PropertyService
+	uploads    repository.UploadRepository
+	users      repository.UserRepository
+	now        func() time.Time
+}
+
+func NewInspectionService(repo repository.InspectionRepository, properties *PropertyService, uploads repository.UploadRepository, users repository.UserRepository) *InspectionService {
+	return &InspectionService{repo: repo, properties: properties, uploads: uploads, users: users, now: time.Now}
+}
+
+// List returns a property's inspections in schedule order
+func (s *InspectionService) List(ctx context.Context, propertyID int) ([]models.Inspection, error) {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByProperty(ctx, propertyID)
+}
+
+// Get returns an inspection with its reports and repairs
+func (s *InspectionService) Get(ctx context.Context, id int) (*models.Inspection, error) {
+	inspection, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inspection == nil {
+		return nil, apperrors.NotFound("inspection not found")
+	}
+	// Inspections are only visible to those who can see the property
+	if _, err := s.properties.GetProperty(ctx, inspection.PropertyID); err != nil {
+		return nil, err
+	}
+	return inspection, nil
+}
+
+// Create schedules an inspection of a property. An inspection still to
+// take place must be scheduled in the future; one recorded as completed
+// is stamped completed now.
+func (s *InspectionService) Create(ctx context.Context, propertyID int, inspection *models.Inspection) error {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return err
+	}
+	if inspection.Kind == "" {
+		inspection.Kind = models.InspectionGeneral
+	}
+	if inspection.Status == "" {
+		inspection.Status = models.InspectionScheduled
+	}
+	if err := validateInspection(inspection); err != nil {
+		return err
+	}
+	if inspection.Status == models.InspectionScheduled && !inspection.ScheduledAt.After(s.now()) {
+		return apperrors.Validation("scheduled_at must be in the future")
+	}
+
+	inspection.ID = 0
+	inspection.PropertyID = propertyID
+	inspection.ScheduledAt = inspection.ScheduledAt.UTC()
+	inspection.CompletedAt = models.NullTime{}
+	if inspection.Status == models.InspectionCompleted {
+		inspection.CompletedAt = nullTime(s.now())
+	}
+	inspection.CreatedBy = models.NullInt32{}
+	if userID, ok := ActorFromContext(ctx); ok {
+		inspection.CreatedBy = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(userID), Valid: true}}
+	}
+	if err := s.repo.Create(ctx, inspection); err != nil {
+		return err
+	}
+	inspection.Reports = []models.InspectionReport{}
+	return nil
+}
+
+// Update reschedules an inspection or changes its kind, inspector, status
+// and notes. An inspection is stamped completed when it moves to the
+// completed status, and the stamp is cleared if it moves out again.
+func (s *InspectionService) Update(ctx context.Context, id int, changes *models.Inspection) (*models.Inspection, error) {
+	inspection, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes.Kind == "" {
+		changes.Kind = inspection.Kind
+	}
+	if changes.Status == "" {
+		changes.Status = inspection.Status
+	}
+	if err := validateInspection(changes); err != nil {
+		return nil, err
+	}
+	rescheduled := !changes.ScheduledAt.Equal(inspection.ScheduledAt)
+	if rescheduled && changes.Status == models.InspectionScheduled && !changes.ScheduledAt.After(s.now()) {
+		return nil, apperrors.Validation("scheduled_at must be in the future")
+	}
+
+	switch {
+	case changes.Status == models.InspectionCompleted && inspection.Status != models.InspectionCompleted:
+		inspection.CompletedAt = nullTime(s.now())
+	case changes.Status != models.InspectionCompleted:
+		inspection.CompletedAt = models.NullTime{}
+	}
+	inspection.Kind = changes.Kind
+	inspection.Inspector = changes.Inspector
+	inspection.ScheduledAt = changes.ScheduledAt.UTC()
+	inspection.Status = changes.Status
+	inspection.Notes = changes.Notes
+	if err := s.repo.Update(ctx, inspection); err != nil {
+		return nil, err
+	}
+	return inspection, nil
+}
+
+// Delete removes an inspection with its repairs. The report documents stay
+// with the property.
+func (s *InspectionService) Delete(ctx context.Context, id int) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	exists, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("inspection not found")
+	}
+	return nil
+}
+
+// AttachReport attaches a confirmed document upload of the inspected
+// property to an inspection
+func (s *InspectionService) AttachReport(ctx context.Context, id int, uploadID string) (*models.Inspection, error) {
+	inspection, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if uploadID == "" {
+		return nil, apperrors.Validation("upload_id is required")
+	}
+	upload, err := s.uploads.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case upload == nil || upload.PropertyID != inspection.PropertyID:
+		return nil, apperrors.Validationf("upload %s not found for this property", uploadID)
+	case upload.Kind != models.FileKindDocument:
+		return nil, apperrors.Validation("a report must be uploaded as a document")
+	case upload.Status != models.UploadStatusCompleted:
+		return nil, apperrors.Validation("the report upload has not been confirmed yet")
+	}
+
+	if err := s.repo.AddReport(ctx, id, uploadID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
+
+// DetachReport removes a report from an inspection. The document itself
+// stays with the property.
+func (s *InspectionService) DetachReport(ctx context.Context, id int, uploadID string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	removed, err := s.repo.RemoveReport(ctx, id, uploadID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return apperrors.NotFound("report not found")
+	}
+	return nil
+}
+
+// AddRepair records a repair an inspection called for, open unless
+// another status is given
+func (s *InspectionService) AddRepair(ctx context.Context, inspectionID int, repair *models.RepairItem) error {
+	inspection, err := s.Get(ctx, inspectionID)
+	if err != nil {
+		return err
+	}
+	if repair.Status == "" {
+		repair.Status = models.RepairOpen
+	}
+	if err := s.validateRepair(ctx, repair); err != nil {
+		return err
+	}
+
+	repair.ID = 0
+	repair.InspectionID = inspection.ID
+	repair.PropertyID = inspection.PropertyID
+	repair.CompletedAt = models.NullTime{}
+	if repairResolved(repair.Status) {
+		repair.CompletedAt = nullTime(s.now())
+	}
+	return s.repo.CreateRepair(ctx, repair)
+}
+
+// UpdateRepair replaces a repair's description, status, assignee and due
+// date. A repair is stamped completed when it is done or waived, and the
+// stamp is cleared if it is reopened.
+func (s *InspectionService) UpdateRepair(ctx context.Context, id int, changes *models.RepairItem) (*models.RepairItem, error) {
+	repair, err := s.repo.GetRepair(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if repair == nil {
+		return nil, apperrors.NotFound("repair not found")
+	}
+	if _, err := s.properties.GetProperty(ctx, repair.PropertyID); err != nil {
+		return nil, err
+	}
+	if changes.Status == "" {
+		changes.Status = repair.Status
+	}
+	if err := s.validateRepair(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case repairResolved(changes.Status) && !repairResolved(repair.Status):
+		repair.CompletedAt = nullTime(s.now())
+	case !repairResolved(changes.Status):
+		repair.CompletedAt = models.NullTime{}
+	}
+	repair.Description = changes.Description
+	repair.Status = changes.Status
+	repair.AssigneeID = changes.AssigneeID
+	repair.DueDate = changes.DueDate
+	if err := s.repo.UpdateRepair(ctx, repair); err != nil {
+		return nil, err
+	}
+	return repair, nil
+}
+
+// PunchList returns a property's repairs from all its inspections,
+// outstanding ones first, with the number in each status and how many are
+// past their due date
+func (s *InspectionService) PunchList(ctx context.Context, propertyID int, filter models.RepairFilter) (*models.PunchList, error) {
+	if filter.Status != "" && !slices.Contains(models.RepairStatuses, filter.Status) {
+		return nil, invalidRepairStatus()
+	}
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	repairs, err := s.repo.ListRepairs(ctx, propertyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	list := &models.PunchList{PropertyID: propertyID, Counts: make(map[string]int, len(models.RepairStatuses)), Items: repairs}
+	for _, status := range models.RepairStatuses {
+		list.Counts[status] = 0
+	}
+	for _, repair := range repairs {
+		list.Counts[repair.Status]++
+		if !repairResolved(repair.Status) && repair.DueDate.Valid && repair.DueDate.Time.Before(today) {
+			list.Overdue++
+		}
+	}
+	return list, nil
+}
+
+// validateInspection checks an inspection's kind and status and trims its
+// inspector
+func validateInspection(inspection *models.Inspection) error {
+	if !slices.Contains(models.InspectionKinds, inspection.Kind) {
+		return apperrors.Validationf("kind must be one of %s", strings.Join(models.InspectionKinds, ", "))
+	}
+	if !slices.Contains(models.InspectionStatuses, inspection.Status) {
+		return apperrors.Validationf("status must be one of %s", strings.Join(models.InspectionStatuses, ", "))
+	}
+	if inspection.ScheduledAt.IsZero() {
+		return apperrors.Validation("scheduled_at is required")
+	}
+	inspection.Inspector = strings.TrimSpace(inspection.Inspector)
+	if len(inspection.Inspector) > 255 {
+		return apperrors.Validation("inspector must be at most 255 characters")
+	}
+	return nil
+}
+
+// validateRepair checks a repair's description, status and assignee
+func (s *InspectionService) validateRepair(ctx context.Context, repair *models.RepairItem) error {
+	repair.Description = strings.TrimSpace(repair.Description)
+	if repair.Description == "" {
+		return apperrors.Validation("description is required")
+	}
+	if len(repair.Description) > maxRepairDescription {
+		return apperrors.Validationf("description must be at most %d characters", maxRepairDescription)
+	}
+	if !slices.Contains(models.RepairStatuses, repair.Status) {
+		return invalidRepairStatus()
+	}
+	if !repair.AssigneeID.Valid {
+		return nil
+	}
+	if _, err := s.users.GetByID(ctx, uint(repair.AssigneeID.Int32)); errors.Is(err, sql.ErrNoRows) {
+		return apperrors.Validationf("assignee %d not found", repair.AssigneeID.Int32)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// repairResolved reports whether a repair in status is off the open list
+func repairResolved(status string) bool {
+	return status == models.RepairDone || status == models.RepairWaived
+}
+
+func invalidRepairStatus() error {
+	return apperrors.Validationf("status must be one of %s", strings.Join(models.RepairStatuses, ", "))
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestInspectionService_Create(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		inspection  models.Inspection
+		expectError bool
+	}{
+		{name: "scheduled", inspection: models.Inspection{Inspector: " Ann Lee ", ScheduledAt: now.Add(48 * time.Hour)}},
+		{name: "recorded as completed", inspection: models.Inspection{Kind: models.InspectionRoof, Status: models.InspectionCompleted,
+			ScheduledAt: now.Add(-48 * time.Hour)}},
+		{name: "scheduled in the past", inspection: models.Inspection{ScheduledAt: now.Add(-time.Hour)}, expectError: true},
+		{name: "no schedule", inspection: models.Inspection{}, expectError: true},
+		{name: "unknown kind", inspection: models.Inspection{Kind: "mold", ScheduledAt: now.Add(time.Hour)}, expectError: true},
+		{name: "unknown status", inspection: models.Inspection{Status: "done", ScheduledAt: now.Add(time.Hour)}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+			mockRepo := mocks.NewMockInspectionRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, inspection *models.Inspection) error {
+					inspection.ID = 3
+					return nil
+				})
+			}
+
+			service := NewInspectionService(mockRepo, NewPropertyService(mockProperties), nil, nil)
+			service.now = func() time.Time { return now }
+			inspection := tt.inspection
+			err := service.Create(WithActor(context.Background(), 4), 12, &inspection)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if inspection.ID != 3 || inspection.PropertyID != 12 || inspection.CreatedBy.Int32 != 4 || inspection.Kind == "" {
+				t.Errorf("Unexpected inspection %+v", inspection)
+			}
+			if inspection.Inspector != "" && inspection.Inspector != "Ann Lee" {
+				t.Errorf("Expected the inspector to be trimmed, got %q", inspection.Inspector)
+			}
+			if inspection.CompletedAt.Valid != (inspection.Status == models.InspectionCompleted) {
+				t.Errorf("Expected only completed inspections to be stamped, got status %s and completed_at %v",
+					inspection.Status, inspection.CompletedAt)
+			}
+		})
+	}
+}
+
+func TestInspectionService_AttachReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		upload      *models.Upload
+		expectError bool
+	}{
+		{name: "confirmed document", upload: &models.Upload{ID: "u1", PropertyID: 12, Kind: models.FileKindDocument,
+			Status: models.UploadStatusCompleted}},
+		{name: "unknown upload", expectError: true},
+		{name: "another property's document", upload: &models.Upload{ID: "u1", PropertyID: 13, Kind: models.FileKindDocument,
+			Status: models.UploadStatusCompleted}, expectError: true},
+		{name: "photo", upload: &models.Upload{ID: "u1", PropertyID: 12, Kind: models.FileKindPhoto,
+			Status: models.UploadStatusCompleted}, expectError: true},
+		{name: "not confirmed", upload: &models.Upload{ID: "u1", PropertyID: 12, Kind: models.FileKindDocument,
+			Status: models.UploadStatusPending}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil).AnyTimes()
+			mockUploads := mocks.NewMockUploadRepository(ctrl)
+			mockUploads.EXPECT().GetByID(gomock.Any(), "u1").Return(tt.upload, nil)
+			mockRepo := mocks.NewMockInspectionRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 3).Return(&models.Inspection{ID: 3, PropertyID: 12}, nil).AnyTimes()
+			if !tt.expectError {
+				mockRepo.EXPECT().AddReport(gomock.Any(), 3, "u1").Return(nil)
+			}
+
+			service := NewInspectionService(mockRepo, NewPropertyService(mockProperties), mockUploads, nil)
+			_, err := service.AttachReport(context.Background(), 3, "u1")
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInspectionService_UpdateRepair(t *testing.T) {
+	completedAt := models.NullTime{NullTime: sql.NullTime{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		status          string
+		completedAt     models.NullTime
+		to              string
+		assignee        int32
+		expectError     bool
+		expectCompleted bool
+		expectStampAt   time.Time
+	}{
+		{name: "done", status: models.RepairInProgress, to: models.RepairDone, expectCompleted: true, expectStampAt: now},
+		{name: "waived keeps the date", status: models.RepairDone, completedAt: completedAt, to: models.RepairWaived,
+			expectCompleted: true, expectStampAt: completedAt.Time},
+		{name: "reopened", status: models.RepairDone, completedAt: completedAt, to: models.RepairOpen},
+		{name: "assigned", status: models.RepairOpen, to: models.RepairInProgress, assignee: 4},
+		{name: "unknown assignee", status: models.RepairOpen, assignee: 9, expectError: true},
+		{name: "unknown status", status: models.RepairOpen, to: "fixed", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockUsers.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id uint) (*models.User, error) {
+				if id == 9 {
+					return nil, sql.ErrNoRows
+				}
+				return &models.User{ID: id}, nil
+			}).AnyTimes()
+			mockRepo := mocks.NewMockInspectionRepository(ctrl)
+			mockRepo.EXPECT().GetRepair(gomock.Any(), 8).Return(&models.RepairItem{ID: 8, InspectionID: 3, PropertyID: 12,
+				Description: "Fix gutter", Status: tt.status, CompletedAt: tt.completedAt}, nil)
+			if !tt.expectError {
+				mockRepo.EXPECT().UpdateRepair(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewInspectionService(mockRepo, NewPropertyService(mockProperties), nil, mockUsers)
+			service.now = func() time.Time { return now }
+			changes := &models.RepairItem{Description: "Fix gutter", Status: tt.to}
+			if tt.assignee != 0 {
+				changes.AssigneeID = models.NullInt32{NullInt32: sql.NullInt32{Int32: tt.assignee, Valid: true}}
+			}
+			repair, err := service.UpdateRepair(context.Background(), 8, changes)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if repair.CompletedAt.Valid != tt.expectCompleted || (tt.expectCompleted && !repair.CompletedAt.Time.Equal(tt.expectStampAt)) {
+				t.Errorf("Unexpected repair %+v", repair)
+			}
+			if repair.AssigneeID.Int32 != tt.assignee {
+				t.Errorf("Expected assignee %d, got %+v", tt.assignee, repair.AssigneeID)
+			}
+		})
+	}
+}
+
+func TestInspectionService_PunchList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := func(day int) models.NullDate {
+		return models.NullDate{NullTime: sql.NullTime{Time: time.Date(2024, 6, day, 0, 0, 0, 0, time.UTC), Valid: true}}
+	}
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+	mockRepo := mocks.NewMockInspectionRepository(ctrl)
+	mockRepo.EXPECT().ListRepairs(gomock.Any(), 12, models.RepairFilter{}).Return([]models.RepairItem{
+		{ID: 1, Status: models.RepairOpen, DueDate: date(1)},
+		{ID: 2, Status: models.RepairInProgress, DueDate: date(10)},
+		{ID: 3, Status: models.RepairOpen},
+		{ID: 4, Status: models.RepairDone, DueDate: date(1)},
+	}, nil)
+
+	service := NewInspectionService(mockRepo, NewPropertyService(mockProperties), nil, nil)
+	service.now = func() time.Time { return time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC) }
+	list, err := service.PunchList(context.Background(), 12, models.RepairFilter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Done repairs are never overdue
+	if list.Overdue != 1 {
+		t.Errorf("Expected 1 overdue repair, got %d", list.Overdue)
+	}
+	if list.Counts[models.RepairOpen] != 2 || list.Counts[models.RepairInProgress] != 1 || list.Counts[models.RepairDone] != 1 {
+		t.Errorf("Unexpected counts %v", list.Counts)
+	}
+	if count, ok := list.Counts[models.RepairWaived]; !ok || count != 0 {
+		t.Errorf("Expected every status to be counted, got %v", list.Counts)
+	}
+}
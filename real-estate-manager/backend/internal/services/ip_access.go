@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"real-estate-manager/backend/internal/apperrors"
+)
+
+// ipRanges is a parsed IP rule setting, kept with the value it was parsed
+// from
+type ipRanges struct {
+	value    string
+	prefixes []netip.Prefix
+}
+
+// IPAccessService evaluates the IP rules kept in runtime settings:
+// ip_denylist blocks its ranges from every route, and admin_ip_allowlist,
+// when set, limits admin routes to its ranges. A rule is parsed again
+// whenever its setting changes.
+type IPAccessService struct {
+	settings SettingsProvider
+	mu       sync.Mutex
+	rules    map[string]ipRanges
+}
+
+func NewIPAccessService(settings SettingsProvider) *IPAccessService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &IPAccessService{settings: settings, rules: make(map[string]ipRanges)}
+}
+
+// Denied reports whether ip is in the denylist
+func (s *IPAccessService) Denied(ip string) bool {
+	return containsIP(s.ranges(SettingIPDenylist), ip)
+}
+
+// AdminAllowed reports whether ip may reach admin routes: any address
+// without an allowlist, otherwise only those in it
+func (s *IPAccessService) AdminAllowed(ip string) bool {
+	prefixes := s.ranges(SettingAdminIPAllowlist)
+	return len(prefixes) == 0 || containsIP(prefixes, ip)
+}
+
+// CheckUpdate rejects setting updates that would lock the admin making
+// them, from ip, out of the admin API. Malformed rules are left to the
+// settings' own validation.
+func (s *IPAccessService) CheckUpdate(updates map[string]string, ip string) error {
+	if value, ok := updates[SettingAdminIPAllowlist]; ok {
+		if prefixes, err := parseIPRanges(value); err == nil && len(prefixes) > 0 && !containsIP(prefixes, ip) {
+			return apperrors.Validationf("%s must include your own address %s", SettingAdminIPAllowlist, ip)
+		}
+	}
+	if value, ok := updates[SettingIPDenylist]; ok {
+		if prefixes, err := parseIPRanges(value); err == nil && containsIP(prefixes, ip) {
+			return apperrors.Validationf("%s may not include your own address %s", SettingIPDenylist, ip)
+		}
+	}
+	return nil
+}
+
+// ranges returns the parsed ranges of a rule setting. Values are
+// validated when set, so a malformed one can only be a stored value from
+// an older version; it is treated as empty.
+func (s *IPAccessService) ranges(name string) []netip.Prefix {
+	value := s.settings.GetString(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rule, ok := s.rules[name]; ok && rule.value == value {
+		return rule.prefixes
+	}
+	prefixes, _ := parseIPRanges(value)
+	s.rules[name] = ipRanges{value: value, prefixes: prefixes}
+	return prefixes
+}
+
+// parseIPRanges parses a comma-separated list of CIDR ranges and single
+// addresses, such as "10.0.0.0/8, 203.0.113.7"
+func parseIPRanges(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range splitList(value) {
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a CIDR range", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP address or CIDR range", entry)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// containsIP reports whether ip is in any of prefixes. An address that
+// cannot be parsed is in none.
+func containsIP(prefixes []netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateIPRanges(value string) error {
+	_, err := parseIPRanges(value)
+	return err
+}
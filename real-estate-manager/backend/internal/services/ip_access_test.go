@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+)
+
+func TestIPAccessService(t *testing.T) {
+	settings := staticSettings{
+		SettingIPDenylist:       "198.51.100.0/24, 2001:db8::/32",
+		SettingAdminIPAllowlist: "10.0.0.0/8,203.0.113.7",
+	}
+	access := NewIPAccessService(settings)
+
+	for ip, denied := range map[string]bool{
+		"198.51.100.23":        true,
+		"::ffff:198.51.100.23": true,
+		"2001:db8::1":          true,
+		"198.51.101.1":         false,
+		"not an address":       false,
+	} {
+		if access.Denied(ip) != denied {
+			t.Errorf("Expected Denied(%q) to be %v", ip, denied)
+		}
+	}
+	for ip, allowed := range map[string]bool{
+		"10.20.30.40":  true,
+		"203.0.113.7":  true,
+		"203.0.113.8":  false,
+		"192.168.1.10": false,
+	} {
+		if access.AdminAllowed(ip) != allowed {
+			t.Errorf("Expected AdminAllowed(%q) to be %v", ip, allowed)
+		}
+	}
+
+	// Changes to the settings apply to the next request
+	settings[SettingAdminIPAllowlist] = ""
+	if !access.AdminAllowed("192.168.1.10") {
+		t.Error("Expected any address to reach admin routes without an allowlist")
+	}
+}
+
+func TestIPAccessService_CheckUpdate(t *testing.T) {
+	access := NewIPAccessService(nil)
+
+	for name, updates := range map[string]map[string]string{
+		"allowlist without the caller": {SettingAdminIPAllowlist: "10.0.0.0/8"},
+		"denylist with the caller":     {SettingIPDenylist: "192.168.0.0/16"},
+	} {
+		if err := access.CheckUpdate(updates, "192.168.1.10"); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+	for name, updates := range map[string]map[string]string{
+		"allowlist with the caller": {SettingAdminIPAllowlist: "192.168.1.0/24"},
+		"cleared allowlist":         {SettingAdminIPAllowlist: ""},
+		"other settings":            {SettingImageQuality: "80"},
+	} {
+		if err := access.CheckUpdate(updates, "192.168.1.10"); err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+	}
+
+	if err := validateIPRanges("10.0.0.0/33"); err == nil {
+		t.Error("Expected an invalid CIDR range to be rejected")
+	}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+)
+
+// Artifacts every job with artifacts enabled leaves behind. Raw pages are
+// archived next to them as page-001.json and so on.
+const (
+	ArtifactErrors  = "errors.csv"
+	ArtifactChanges = "changes.csv"
+	ArtifactPages   = "pages.json"
+)
+
+// artifactName and artifactJobID match the names artifacts and job
+// directories are written under, which keeps downloads inside them
+var (
+	artifactName  = regexp.MustCompile(`^[a-z0-9-]+\.(csv|json)$`)
+	artifactJobID = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// ArchivedPage describes one raw response page fetched from the provider
+type ArchivedPage struct {
+	Page       int       `json:"page"`
+	URL        string    `json:"url"`
+	File       string    `json:"file"`
+	Bytes      int       `json:"bytes"`
+	SHA256     string    `json:"sha256"`
+	Properties int       `json:"properties"`
+	FetchedAt  time.Time `json:"fetched_at"`
+}
+
+// jobReport collects what a job did for its artifacts in dir. Batches
+// record into it concurrently.
+type jobReport struct {
+	mu       sync.Mutex
+	dir      string
+	failures [][]string
+	changes  [][]string
+	pages    []ArchivedPage
+}
+
+// pageSpool is a response page downloaded to disk, so its listings can be
+// decoded one at a time instead of from a copy held in memory
+type pageSpool struct {
+	*os.File
+	page      int
+	temporary bool
+}
+
+type jobReportKey struct{}
+
+func withJobReport(ctx context.Context, report *jobReport) context.Context {
+	return context.WithValue(ctx, jobReportKey{}, report)
+}
+
+func jobReportFromContext(ctx context.Context) *jobReport {
+	report, _ := ctx.Value(jobReportKey{}).(*jobReport)
+	return report
+}
+
+func (r *jobReport) fail(property models.SimplyRETSProperty, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, []string{property.MLSNumber.String(), property.ListingID, property.Address.Full, err.Error()})
+}
+
+func (r *jobReport) change(property models.SimplyRETSProperty, propertyID int, action string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, []string{property.MLSNumber.String(), property.ListingID, strconv.Itoa(propertyID), action})
+}
+
+// spool downloads a page fetched from url. The job's pages are spooled
+// straight into its artifacts, archived as the provider sent them; without
+// a report the page goes to a temporary file removed when it is closed.
+func (r *jobReport) spool(url string, body io.Reader) (*pageSpool, error) {
+	if r == nil {
+		file, err := os.CreateTemp("", "simplyrets-page-*.json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to spool response: %w", err)
+		}
+		spool := &pageSpool{File: file, temporary: true}
+		if _, err := spool.fill(body, io.Discard); err != nil {
+			spool.Close()
+			return nil, err
+		}
+		return spool, nil
+	}
+
+	r.mu.Lock()
+	number := len(r.pages) + 1
+	r.mu.Unlock()
+	name := fmt.Sprintf("page-%03d.json", number)
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to spool response: %w", err)
+	}
+	file, err := os.Create(filepath.Join(r.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool response: %w", err)
+	}
+	spool := &pageSpool{File: file, page: number}
+	sum := sha256.New()
+	size, err := spool.fill(body, sum)
+	if err != nil {
+		spool.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages = append(r.pages, ArchivedPage{
+		Page:      number,
+		URL:       url,
+		File:      name,
+		Bytes:     int(size),
+		SHA256:    hex.EncodeToString(sum.Sum(nil)),
+		FetchedAt: time.Now(),
+	})
+	return spool, nil
+}
+
+// decoded records how many listings a spooled page held
+func (r *jobReport) decoded(spool *pageSpool, properties int) {
+	if r == nil || spool.temporary {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages[spool.page-1].Properties = properties
+}
+
+// fill copies body into the spool, also writing it to sum, and rewinds the
+// spool for decoding
+func (p *pageSpool) fill(body io.Reader, sum io.Writer) (int64, error) {
+	size, err := io.Copy(io.MultiWriter(p.File, sum), body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	if _, err := p.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to spool response: %w", err)
+	}
+	return size, nil
+}
+
+// Close closes the spool, removing it unless it is archived
+func (p *pageSpool) Close() error {
+	err := p.File.Close()
+	if p.temporary {
+		os.Remove(p.Name())
+	}
+	return err
+}
+
+// write stores the report's artifacts in its dir, next to the pages already
+// spooled there, and returns their names and total size
+func (r *jobReport) write() ([]string, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errorsCSV, err := encodeCSV([]string{"mls_id", "listing_id", "address", "error"}, r.failures)
+	if err != nil {
+		return nil, 0, err
+	}
+	changesCSV, err := encodeCSV([]string{"mls_id", "listing_id", "property_id", "action"}, r.changes)
+	if err != nil {
+		return nil, 0, err
+	}
+	index, err := json.MarshalIndent(r.pages, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	names := []string{ArtifactErrors, ArtifactChanges, ArtifactPages}
+	contents := [][]byte{errorsCSV, changesCSV, index}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return nil, 0, err
+	}
+	var size int64
+	for i, name := range names {
+		if err := os.WriteFile(filepath.Join(r.dir, name), contents[i], 0644); err != nil {
+			return nil, 0, err
+		}
+		size += int64(len(contents[i]))
+	}
+	for _, page := range r.pages {
+		names = append(names, page.File)
+		size += int64(page.Bytes)
+	}
+	return names, size, nil
+}
+
+func encodeCSV(header []string, records [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeArtifacts stores a finished job's artifacts and charges them to the
+// owner the job's photos are charged to. Failing to store them does not
+// fail the job.
+func (s *SimplyRETSService) writeArtifacts(ctx context.Context, jobID string) []string {
+	report := jobReportFromContext(ctx)
+	if report == nil || s.artifactsDir == "" {
+		return nil
+	}
+	names, size, err := report.write()
+	if err != nil {
+		log.Printf("Failed to write artifacts of job %s: %v", jobID, err)
+		return nil
+	}
+	if owner, ok := storageOwnerFromContext(ctx); ok && s.storage != nil {
+		if err := s.storage.Record(ctx, owner, 0, models.FileKindJobArtifact, report.dir, size); err != nil {
+			log.Printf("Failed to record artifact storage of job %s: %v", jobID, err)
+		}
+	}
+	return names
+}
+
+// JobArtifact returns the path of one of the artifacts of a job scope may
+// access
+func (s *SimplyRETSService) JobArtifact(ctx context.Context, jobID, name string, scope JobScope) (string, error) {
+	if s.artifactsDir == "" || !artifactName.MatchString(name) || !artifactJobID.MatchString(jobID) {
+		return "", apperrors.NotFound("artifact not found")
+	}
+	if err := s.authorizeJob(ctx, jobID, scope); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.artifactsDir, jobID, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", apperrors.NotFound("artifact not found")
+	}
+	return path, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// jobIndex is the set of listings a job has saved, kept in the job history
+// so that a resumed job skips them instead of saving them twice. Listings
+// are claimed before they are processed, so one that appears twice in a
+// page is processed once too.
+type jobIndex struct {
+	jobID    string
+	repo     repository.JobRepository
+	mu       sync.Mutex
+	done     map[string]bool
+	inFlight map[string]bool
+	skipped  int
+}
+
+type jobIndexKey struct{}
+
+func withJobIndex(ctx context.Context, index *jobIndex) context.Context {
+	return context.WithValue(ctx, jobIndexKey{}, index)
+}
+
+func jobIndexFromContext(ctx context.Context) *jobIndex {
+	index, _ := ctx.Value(jobIndexKey{}).(*jobIndex)
+	return index
+}
+
+func newJobIndex(jobID string, repo repository.JobRepository, done map[string]bool) *jobIndex {
+	if done == nil {
+		done = map[string]bool{}
+	}
+	return &jobIndex{jobID: jobID, repo: repo, done: done, inFlight: map[string]bool{}}
+}
+
+// claim reports whether a listing still has to be processed, counting it
+// as skipped when the job already saved it. Listings without an ID are
+// always processed.
+func (x *jobIndex) claim(property models.SimplyRETSProperty) bool {
+	if x == nil || property.ListingID == "" {
+		return true
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.done[property.ListingID] || x.inFlight[property.ListingID] {
+		x.skipped++
+		return false
+	}
+	x.inFlight[property.ListingID] = true
+	return true
+}
+
+// finish records how a claimed listing went. Saved listings are added to
+// the index; failed ones may be claimed again.
+func (x *jobIndex) finish(ctx context.Context, property models.SimplyRETSProperty, err error) {
+	if x == nil || property.ListingID == "" {
+		return
+	}
+	x.mu.Lock()
+	delete(x.inFlight, property.ListingID)
+	if err == nil {
+		x.done[property.ListingID] = true
+	}
+	x.mu.Unlock()
+	if err != nil {
+		return
+	}
+	if err := x.repo.MarkProcessed(ctx, x.jobID, property.ListingID); err != nil {
+		log.Printf("Failed to record listing %s as processed by job %s: %v", property.ListingID, x.jobID, err)
+	}
+}
+
+func (x *jobIndex) count() int {
+	if x == nil {
+		return 0
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.skipped
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/leads"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Page sizes of an agent's lead list
+const (
+	DefaultLeadLimit = 50
+	MaxLeadLimit     = 200
+)
+
+// UrgentNotifier texts a user about a time-sensitive event;
+// *NotificationService implements it
+type UrgentNotifier interface {
+	NotifyUrgent(ctx context.Context, userID uint, kind, text string) (bool, error)
+}
+
+// LeadService ingests leads posted by portals and landing pages and assigns
+// them to agents: a lead about one of our listings goes to its agent, any
+// other lead to the first matching routing rule
+type LeadService struct {
+	repo       repository.LeadRepository
+	properties repository.PropertyRepository
+	users      repository.UserRepository
+	secrets    map[string]string
+	notifier   UrgentNotifier
+	events     EventPublisher
+}
+
+// NewLeadService creates the service. secrets holds the webhook signing
+// secret of each enabled source; notifier and publisher may be nil.
+func NewLeadService(repo repository.LeadRepository, properties repository.PropertyRepository, users repository.UserRepository,
+	secrets map[string]string, notifier UrgentNotifier, publisher EventPublisher) *LeadService {
+	return &LeadService{
+		repo:       repo,
+		properties: properties,
+		users:      users,
+		secrets:    secrets,
+		notifier:   notifier,
+		events:     publisher,
+	}
+}
+
+// Ingest verifies and stores a lead webhook from source. It reports whether
+// the lead is new; a redelivered lead is returned as first stored.
+func (s *LeadService) Ingest(ctx context.Context, source string, body []byte, signature string) (*models.Lead, bool, error) {
+	secret, ok := s.secrets[source]
+	if !ok {
+		return nil, false, apperrors.NotFound("unknown lead source")
+	}
+	if !leads.Verify(secret, body, signature) {
+		return nil, false, apperrors.Unauthorized("invalid signature")
+	}
+	parsed, err := leads.Parse(source, body)
+	if err != nil {
+		return nil, false, apperrors.Validation(err.Error())
+	}
+
+	lead := &models.Lead{
+		Source:     source,
+		ExternalID: parsed.ExternalID,
+		Name:       parsed.Name,
+		Email:      parsed.Email,
+		Phone:      parsed.Phone,
+		Message:    parsed.Message,
+		Status:     models.LeadStatusNew,
+	}
+	property, err := s.findProperty(ctx, parsed)
+	if err != nil {
+		return nil, false, err
+	}
+	location := parsed.Address
+	if property != nil {
+		lead.PropertyID = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(property.ID), Valid: true}}
+		lead.AssignedTo = property.AgentID
+		location = property.Location
+	}
+	if !lead.AssignedTo.Valid {
+		rule, err := s.route(ctx, source, location)
+		if err != nil {
+			return nil, false, err
+		}
+		if rule != nil {
+			lead.AssignedTo = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(rule.AgentID), Valid: true}}
+		}
+	}
+
+	created, err := s.repo.Create(ctx, lead)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to save lead: %w", err)
+	}
+	if created {
+		publishEvent(ctx, s.events, events.LeadCreated, leadSubject(lead.ID), lead)
+		s.notifyAgent(ctx, lead, property)
+	}
+	return lead, created, nil
+}
+
+// findProperty returns the listing a lead is about, by our public or
+// numeric ID or its MLS number, or nil when it is not one of ours
+func (s *LeadService) findProperty(ctx context.Context, lead *leads.Lead) (*models.Property, error) {
+	var property *models.Property
+	var err error
+	switch {
+	case lead.PropertyPublicID != "":
+		property, err = s.properties.GetByPublicID(ctx, lead.PropertyPublicID)
+	case lead.PropertyID > 0:
+		property, err = s.properties.GetByID(ctx, lead.PropertyID)
+	case lead.MLSNumber != "":
+		property, err = s.properties.GetByMLSNumber(ctx, lead.MLSNumber)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property: %w", err)
+	}
+	return property, nil
+}
+
+// route returns the first rule matching a lead from source about location
+func (s *LeadService) route(ctx context.Context, source, location string) (*models.LeadRoutingRule, error) {
+	rules, err := s.repo.ListRoutingRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead routing rules: %w", err)
+	}
+	location = strings.ToLower(location)
+	for _, rule := range rules {
+		if rule.Source != "" && rule.Source != source {
+			continue
+		}
+		if rule.Location != "" && !strings.Contains(location, strings.ToLower(rule.Location)) {
+			continue
+		}
+		return &rule, nil
+	}
+	return nil, nil
+}
+
+// notifyAgent texts the assigned agent about a new lead, if they opted in
+func (s *LeadService) notifyAgent(ctx context.Context, lead *models.Lead, property *models.Property) {
+	if s.notifier == nil || !lead.AssignedTo.Valid {
+		return
+	}
+	text := fmt.Sprintf("New %s lead: %s", lead.Source, leadContact(lead))
+	if property != nil {
+		text += " about " + property.Name
+	}
+	if _, err := s.notifier.NotifyUrgent(ctx, uint(lead.AssignedTo.Int32), NotifyNewLead, text); err != nil {
+		log.Printf("Failed to text agent %d about lead %d: %v", lead.AssignedTo.Int32, lead.ID, err)
+	}
+}
+
+// ListAssigned returns the newest leads assigned to an agent
+func (s *LeadService) ListAssigned(ctx context.Context, agentID uint, limit int) ([]models.Lead, error) {
+	if limit == 0 {
+		limit = DefaultLeadLimit
+	}
+	if limit < 1 || limit > MaxLeadLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxLeadLimit)
+	}
+	return s.repo.ListAssigned(ctx, agentID, limit)
+}
+
+// ListRoutingRules returns the routing rules in the order they are tried
+func (s *LeadService) ListRoutingRules(ctx context.Context) ([]models.LeadRoutingRule, error) {
+	return s.repo.ListRoutingRules(ctx)
+}
+
+// CreateRoutingRule validates and adds a routing rule
+func (s *LeadService) CreateRoutingRule(ctx context.Context, rule *models.LeadRoutingRule) error {
+	rule.Source = strings.ToLower(strings.TrimSpace(rule.Source))
+	rule.Location = strings.TrimSpace(rule.Location)
+	if rule.Source != "" && !slices.Contains(leads.Sources, rule.Source) {
+		return apperrors.Validationf("source must be one of %s", strings.Join(leads.Sources, ", "))
+	}
+	if rule.AgentID == 0 {
+		return apperrors.Validation("agent_id is required")
+	}
+	if _, err := s.users.GetByID(ctx, rule.AgentID); errors.Is(err, sql.ErrNoRows) {
+		return apperrors.Validation("agent not found")
+	} else if err != nil {
+		return err
+	}
+	return s.repo.CreateRoutingRule(ctx, rule)
+}
+
+// DeleteRoutingRule removes a routing rule
+func (s *LeadService) DeleteRoutingRule(ctx context.Context, id int) error {
+	exists, err := s.repo.DeleteRoutingRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("routing rule not found")
+	}
+	return nil
+}
+
+// leadContact names a lead by whatever contact details it has
+func leadContact(lead *models.Lead) string {
+	for _, value := range []string{lead.Name, lead.Email, lead.Phone} {
+		if value != "" {
+			return value
+		}
+	}
+	return "unknown contact"
+}
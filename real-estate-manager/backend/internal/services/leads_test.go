@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/leads"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+type recordingTexter struct {
+	texts map[uint]string
+}
+
+func (n *recordingTexter) NotifyUrgent(ctx context.Context, userID uint, kind, text string) (bool, error) {
+	n.texts[userID] = text
+	return true, nil
+}
+
+func TestLeadService_Ingest(t *testing.T) {
+	agent := models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}
+	rules := []models.LeadRoutingRule{
+		{ID: 1, Source: leads.Facebook, AgentID: 7},
+		{ID: 2, Location: "houston", AgentID: 8},
+		{ID: 3, AgentID: 9},
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		property     *models.Property
+		expectAgent  int32
+		expectListed bool
+	}{
+		{name: "listing with an agent", body: `{"email": "jane@example.com", "property_id": 12}`,
+			property: &models.Property{ID: 12, Name: "Casa", Location: "Houston, TX", AgentID: agent}, expectAgent: 4, expectListed: true},
+		{name: "unassigned listing routed by location", body: `{"email": "jane@example.com", "property_id": 12}`,
+			property: &models.Property{ID: 12, Name: "Casa", Location: "Houston, TX"}, expectAgent: 8, expectListed: true},
+		{name: "listing by public ID", body: `{"email": "jane@example.com", "property_id": "` + publicID + `"}`,
+			property: &models.Property{ID: 12, PublicID: publicID, Name: "Casa", Location: "Houston, TX", AgentID: agent}, expectAgent: 4, expectListed: true},
+		{name: "unknown listing routed by fallback rule", body: `{"email": "jane@example.com"}`, expectAgent: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockLeadRepository(ctrl)
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			if tt.property != nil && tt.property.PublicID != "" {
+				mockProperties.EXPECT().GetByPublicID(gomock.Any(), publicID).Return(tt.property, nil)
+			} else if tt.property != nil {
+				mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(tt.property, nil)
+			}
+			if tt.property == nil || !tt.property.AgentID.Valid {
+				mockRepo.EXPECT().ListRoutingRules(gomock.Any()).Return(rules, nil)
+			}
+			mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, lead *models.Lead) (bool, error) {
+				lead.ID = 30
+				return true, nil
+			})
+
+			publisher := &recordingPublisher{}
+			notifier := &recordingTexter{texts: map[uint]string{}}
+			service := NewLeadService(mockRepo, mockProperties, nil, map[string]string{leads.Website: "secret"}, notifier, publisher)
+			body := []byte(tt.body)
+			lead, created, err := service.Ingest(context.Background(), leads.Website, body, leads.Sign("secret", body))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !created || lead.AssignedTo.Int32 != tt.expectAgent || lead.PropertyID.Valid != tt.expectListed || lead.Status != models.LeadStatusNew {
+				t.Errorf("Unexpected lead %+v", lead)
+			}
+			if len(publisher.events) != 1 || publisher.events[0].Type != events.LeadCreated || publisher.events[0].Subject != "lead/30" {
+				t.Errorf("Expected a lead.created event, got %+v", publisher.events)
+			}
+			if notifier.texts[uint(tt.expectAgent)] == "" {
+				t.Errorf("Expected agent %d to be texted, got %v", tt.expectAgent, notifier.texts)
+			}
+		})
+	}
+}
+
+func TestLeadService_IngestRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewLeadService(mocks.NewMockLeadRepository(ctrl), mocks.NewMockPropertyRepository(ctrl), nil,
+		map[string]string{leads.Website: "secret"}, nil, nil)
+	body := []byte(`{"email": "jane@example.com"}`)
+
+	if _, _, err := service.Ingest(context.Background(), leads.Zillow, body, leads.Sign("secret", body)); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found for a source without a secret, got %v", err)
+	}
+	if _, _, err := service.Ingest(context.Background(), leads.Website, body, leads.Sign("wrong", body)); apperrors.HTTPStatus(err) != 401 {
+		t.Errorf("Expected unauthorized for a bad signature, got %v", err)
+	}
+	invalid := []byte(`{"name": "Jane"}`)
+	if _, _, err := service.Ingest(context.Background(), leads.Website, invalid, leads.Sign("secret", invalid)); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for a lead without contact details, got %v", err)
+	}
+}
+
+func TestLeadService_IngestRedelivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockLeadRepository(ctrl)
+	mockRepo.EXPECT().ListRoutingRules(gomock.Any()).Return([]models.LeadRoutingRule{}, nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(false, nil)
+
+	publisher := &recordingPublisher{}
+	service := NewLeadService(mockRepo, mocks.NewMockPropertyRepository(ctrl), nil, map[string]string{leads.Website: "secret"}, nil, publisher)
+	body := []byte(`{"id": "w-1", "email": "jane@example.com"}`)
+	if _, created, err := service.Ingest(context.Background(), leads.Website, body, leads.Sign("secret", body)); err != nil || created {
+		t.Errorf("Expected a redelivered lead to be accepted as existing, got created=%v err=%v", created, err)
+	}
+	if len(publisher.events) != 0 {
+		t.Errorf("Expected no event for a redelivered lead, got %+v", publisher.events)
+	}
+}
+
+func TestLeadService_CreateRoutingRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockLeadRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7}, nil)
+	mockUsers.EXPECT().GetByID(gomock.Any(), uint(99)).Return(nil, sql.ErrNoRows)
+	mockRepo.EXPECT().CreateRoutingRule(gomock.Any(), gomock.Any()).Return(nil)
+
+	service := NewLeadService(mockRepo, nil, mockUsers, nil, nil, nil)
+	rule := &models.LeadRoutingRule{Source: " Zillow ", Location: "Austin", AgentID: 7}
+	if err := service.CreateRoutingRule(context.Background(), rule); err != nil || rule.Source != leads.Zillow {
+		t.Errorf("Unexpected result %+v, %v", rule, err)
+	}
+	if err := service.CreateRoutingRule(context.Background(), &models.LeadRoutingRule{AgentID: 99}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for an unknown agent, got %v", err)
+	}
+	if err := service.CreateRoutingRule(context.Background(), &models.LeadRoutingRule{Source: "craigslist", AgentID: 7}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for an unknown source, got %v", err)
+	}
+}
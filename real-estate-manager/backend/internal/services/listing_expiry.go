@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// ListingExpiryService expires active and pending listings once their
+// expires_at passes, and warns their agents expiry_notice_days before.
+// Agents hear about both through listing.expiring and listing.expired
+// events, which the notification center turns into notifications.
+type ListingExpiryService struct {
+	repo     repository.PropertyRepository
+	settings SettingsProvider
+	events   EventPublisher
+	now      func() time.Time
+}
+
+func NewListingExpiryService(repo repository.PropertyRepository, settings SettingsProvider, publisher EventPublisher) *ListingExpiryService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &ListingExpiryService{repo: repo, settings: settings, events: publisher, now: time.Now}
+}
+
+// ExpireListings moves listings past their expiry date to the expired
+// status and returns how many were expired
+func (s *ListingExpiryService) ExpireListings(ctx context.Context) (int, error) {
+	now := s.now()
+	properties, err := s.repo.FindExpired(ctx, now)
+	if err != nil || len(properties) == 0 {
+		return 0, err
+	}
+
+	ids := make([]int, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+	if err := s.repo.MarkExpired(ctx, ids, now); err != nil {
+		return 0, err
+	}
+	for i := range properties {
+		properties[i].Status = models.PropertyStatusExpired
+		properties[i].Version++
+		publishEvent(ctx, s.events, events.ListingExpired, propertySubject(properties[i].ID), &properties[i])
+	}
+	return len(properties), nil
+}
+
+// NotifyExpiring warns the agents of listings expiring within the notice
+// period, once per expiry date, and returns how many listings they were
+// warned about
+func (s *ListingExpiryService) NotifyExpiring(ctx context.Context) (int, error) {
+	now := s.now()
+	properties, err := s.repo.FindExpiring(ctx, now.AddDate(0, 0, s.settings.GetInt(SettingExpiryNoticeDays)))
+	if err != nil || len(properties) == 0 {
+		return 0, err
+	}
+
+	ids := make([]int, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+	if err := s.repo.MarkExpiryNotified(ctx, ids, now); err != nil {
+		return 0, err
+	}
+	for i := range properties {
+		publishEvent(ctx, s.events, events.ListingExpiring, propertySubject(properties[i].ID), &properties[i])
+	}
+	return len(properties), nil
+}
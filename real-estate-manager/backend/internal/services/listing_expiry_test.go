@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestListingExpiryService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := func(t time.Time) models.NullTime {
+		return models.NullTime{NullTime: sql.NullTime{Time: t, Valid: true}}
+	}
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().FindExpired(gomock.Any(), now).Return([]models.Property{
+		{ID: 3, Status: models.PropertyStatusActive, ExpiresAt: expiresAt(now.Add(-time.Hour)), Version: 2},
+	}, nil)
+	mockRepo.EXPECT().MarkExpired(gomock.Any(), []int{3}, now).Return(nil)
+	mockRepo.EXPECT().FindExpiring(gomock.Any(), now.AddDate(0, 0, 14)).Return([]models.Property{
+		{ID: 5, Status: models.PropertyStatusPending, ExpiresAt: expiresAt(now.AddDate(0, 0, 10))},
+		{ID: 6, Status: models.PropertyStatusActive, ExpiresAt: expiresAt(now.AddDate(0, 0, 12))},
+	}, nil)
+	mockRepo.EXPECT().MarkExpiryNotified(gomock.Any(), []int{5, 6}, now).Return(nil)
+
+	publisher := &recordingPublisher{}
+	service := NewListingExpiryService(mockRepo, staticSettings{SettingExpiryNoticeDays: "14"}, publisher)
+	service.now = func() time.Time { return now }
+
+	expired, err := service.ExpireListings(context.Background())
+	if err != nil || expired != 1 {
+		t.Fatalf("Expected 1 listing expired, got %d (%v)", expired, err)
+	}
+	notified, err := service.NotifyExpiring(context.Background())
+	if err != nil || notified != 2 {
+		t.Fatalf("Expected 2 listings announced, got %d (%v)", notified, err)
+	}
+
+	if len(publisher.events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", publisher.events)
+	}
+	first := publisher.events[0]
+	if property, ok := first.Data.(*models.Property); first.Type != events.ListingExpired || first.Subject != "property/3" ||
+		!ok || property.Status != models.PropertyStatusExpired || property.Version != 3 {
+		t.Errorf("Unexpected expiry event %+v", first)
+	}
+	if publisher.events[1].Type != events.ListingExpiring || publisher.events[2].Subject != "property/6" {
+		t.Errorf("Unexpected notice events %+v", publisher.events[1:])
+	}
+}
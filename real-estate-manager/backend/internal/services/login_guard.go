@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/captcha"
+)
+
+// captchaFailureWindow is how long a failed attempt counts towards the
+// CAPTCHA threshold
+const captchaFailureWindow = 15 * time.Minute
+
+var (
+	ErrCaptchaRequired = apperrors.Forbidden("CAPTCHA required")
+	ErrCaptchaInvalid  = apperrors.Forbidden("CAPTCHA verification failed")
+)
+
+// LoginGuard slows down credential guessing: once an IP has too many failed
+// logins or registrations, further attempts need a solved CAPTCHA. Without a
+// verifier they are refused until the failures age out.
+type LoginGuard struct {
+	verifier captcha.Verifier
+	failures *windowLimiter
+	alerts   Alerter
+}
+
+// NewLoginGuard creates the guard; alerter, which may be nil, is told when
+// an IP first crosses the failure threshold
+func NewLoginGuard(verifier captcha.Verifier, settings SettingsProvider, alerter Alerter) *LoginGuard {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &LoginGuard{
+		verifier: verifier,
+		alerts:   alerter,
+		failures: newWindowLimiter(captchaFailureWindow, func() int {
+			return settings.GetInt(SettingCaptchaFailures)
+		}),
+	}
+}
+
+// Check lets an attempt from ip through, verifying token when the IP is
+// over the failure threshold
+func (g *LoginGuard) Check(ctx context.Context, ip, token string) error {
+	if !g.failures.Exceeded(ip, time.Now()) {
+		return nil
+	}
+	if g.verifier == nil {
+		return apperrors.RateLimited("too many failed attempts, try again later")
+	}
+	if token == "" {
+		return ErrCaptchaRequired
+	}
+
+	ok, err := g.verifier.Verify(ctx, token, ip)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCaptchaInvalid
+	}
+	return nil
+}
+
+// Failed records a failed attempt from ip. Successful attempts do not clear
+// earlier failures, so a valid account cannot be used to reset the count.
+func (g *LoginGuard) Failed(ip string) {
+	now := time.Now()
+	locked := g.failures.Exceeded(ip, now)
+	g.failures.Add(ip, now)
+	if !locked && g.failures.Exceeded(ip, now) {
+		raiseAlert(g.alerts, alerts.AuthLockout, "Repeated failed logins locked out a client", map[string]string{
+			"ip":     ip,
+			"window": captchaFailureWindow.String(),
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/captcha"
+)
+
+// fakeVerifier accepts a single known token
+type fakeVerifier struct {
+	valid string
+	err   error
+}
+
+func (v fakeVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	return token == v.valid, v.err
+}
+
+func TestLoginGuard_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		verifier   captcha.Verifier
+		failures   int
+		token      string
+		expectKind error
+		expectErr  error
+	}{
+		{name: "below threshold", verifier: fakeVerifier{valid: "ok"}, failures: 1},
+		{name: "captcha required", verifier: fakeVerifier{valid: "ok"}, failures: 2, expectErr: ErrCaptchaRequired},
+		{name: "invalid captcha", verifier: fakeVerifier{valid: "ok"}, failures: 2, token: "bad", expectErr: ErrCaptchaInvalid},
+		{name: "valid captcha", verifier: fakeVerifier{valid: "ok"}, failures: 3, token: "ok"},
+		{name: "no verifier configured", failures: 2, token: "ok", expectKind: apperrors.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := NewLoginGuard(tt.verifier, staticSettings{SettingCaptchaFailures: "2"}, nil)
+			for i := 0; i < tt.failures; i++ {
+				guard.Failed("10.0.0.1")
+			}
+
+			err := guard.Check(context.Background(), "10.0.0.1", tt.token)
+			switch {
+			case tt.expectErr != nil:
+				if err != tt.expectErr {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				if !errors.Is(err, apperrors.ErrForbidden) {
+					t.Errorf("Expected a forbidden error, got %v", err)
+				}
+			case tt.expectKind != nil:
+				if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+			case err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			// Other addresses are unaffected
+			if err := guard.Check(context.Background(), "10.0.0.2", ""); err != nil {
+				t.Errorf("Expected other IPs to pass, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoginGuard_AlertsOnLockout(t *testing.T) {
+	alerter := &recordingAlerter{}
+	guard := NewLoginGuard(nil, staticSettings{SettingCaptchaFailures: "2"}, alerter)
+	for i := 0; i < 4; i++ {
+		guard.Failed("10.0.0.1")
+	}
+
+	if len(alerter.raised) != 1 || alerter.raised[0] != alerts.AuthLockout {
+		t.Errorf("Expected one lockout alert, got %v", alerter.raised)
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/mailer"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// MagicLinkService implements passwordless login: a single-use link is
+// emailed and exchanged for a regular JWT
+type MagicLinkService struct {
+	auth    *AuthService
+	flow    *emailTokenFlow
+	baseURL string
+}
+
+// NewMagicLinkService creates the service; baseURL is the public API origin
+// used to build links, e.g. https://api.example.com
+func NewMagicLinkService(auth *AuthService, users repository.UserRepository, links repository.EmailTokenRepository,
+	m mailer.Mailer, settings SettingsProvider, baseURL string) *MagicLinkService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &MagicLinkService{
+		auth: auth,
+		flow: &emailTokenFlow{
+			users:    users,
+			tokens:   links,
+			mailer:   m,
+			template: "magic_link",
+			ttl:      func() time.Duration { return settings.GetDuration(SettingMagicLinkTTL) },
+			limiter: newWindowLimiter(time.Hour, func() int {
+				return settings.GetInt(SettingMagicLinkLimit)
+			}),
+			limited: "too many login links requested for this email; try again later",
+			invalid: "invalid or expired login link",
+			now:     time.Now,
+		},
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// RequestLink emails a login link to the account registered with email.
+// Unknown addresses succeed silently so the endpoint cannot be used to
+// discover accounts.
+func (s *MagicLinkService) RequestLink(ctx context.Context, email string) error {
+	return s.flow.send(ctx, email, func(token string) string {
+		return s.baseURL + "/api/auth/magic/callback?token=" + url.QueryEscape(token)
+	})
+}
+
+// Exchange consumes a login link token and returns a JWT for its user
+func (s *MagicLinkService) Exchange(ctx context.Context, token string) (string, error) {
+	user, err := s.flow.redeem(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return s.auth.signToken(userClaims(user, sessionTTL))
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mailer"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+// recordingMailer keeps sent messages in memory
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestMagicLinkService_RequestLink(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		setupMock   func(users *mocks.MockUserRepository, links *mocks.MockEmailTokenRepository)
+		expectSent  int
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:  "known email gets a link",
+			email: " Alice@Example.com ",
+			setupMock: func(users *mocks.MockUserRepository, links *mocks.MockEmailTokenRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(&models.User{ID: 7, Username: "alice", Email: "alice@example.com"}, nil)
+				links.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, link *models.EmailToken) error {
+					if link.UserID != 7 || len(link.TokenHash) != 64 || !link.ExpiresAt.After(time.Now()) {
+						t.Errorf("Unexpected link: %+v", link)
+					}
+					return nil
+				})
+			},
+			expectSent: 1,
+		},
+		{
+			name:  "unknown email succeeds silently",
+			email: "nobody@example.com",
+			setupMock: func(users *mocks.MockUserRepository, links *mocks.MockEmailTokenRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(nil, sql.ErrNoRows)
+			},
+		},
+		{
+			name:        "invalid email",
+			email:       "not-an-email",
+			setupMock:   func(*mocks.MockUserRepository, *mocks.MockEmailTokenRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockLinks := mocks.NewMockEmailTokenRepository(ctrl)
+			tt.setupMock(mockUsers, mockLinks)
+
+			m := &recordingMailer{}
+			service := NewMagicLinkService(NewAuthServiceWithSecret(mockUsers, "secret"), mockUsers, mockLinks, m,
+				staticSettings{}, "https://api.example.com/")
+			err := service.RequestLink(context.Background(), tt.email)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(m.sent) != tt.expectSent {
+				t.Fatalf("Expected %d emails, got %d", tt.expectSent, len(m.sent))
+			}
+			if tt.expectSent > 0 && !strings.Contains(m.sent[0].Body, "https://api.example.com/api/auth/magic/callback?token=") {
+				t.Errorf("Expected login link in body, got %q", m.sent[0].Body)
+			}
+		})
+	}
+}
+
+func TestMagicLinkService_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().GetByEmail(gomock.Any(), "bob@example.com").Return(nil, sql.ErrNoRows).Times(2)
+
+	service := NewMagicLinkService(NewAuthServiceWithSecret(mockUsers, "secret"), mockUsers,
+		mocks.NewMockEmailTokenRepository(ctrl), &recordingMailer{}, staticSettings{SettingMagicLinkLimit: "2"}, "")
+
+	for i := 0; i < 2; i++ {
+		if err := service.RequestLink(context.Background(), "bob@example.com"); err != nil {
+			t.Fatalf("Request %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if err := service.RequestLink(context.Background(), "BOB@example.com"); !errors.Is(err, apperrors.ErrRateLimited) {
+		t.Errorf("Expected rate limit error, got %v", err)
+	}
+}
+
+func TestMagicLinkService_Exchange(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		setupMock   func(users *mocks.MockUserRepository, links *mocks.MockEmailTokenRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:  "valid link",
+			token: "abc",
+			setupMock: func(users *mocks.MockUserRepository, links *mocks.MockEmailTokenRepository) {
+				links.EXPECT().Consume(gomock.Any(), hashToken("abc"), gomock.Any()).Return(&models.EmailToken{UserID: 7}, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, Username: "alice"}, nil)
+			},
+		},
+		{
+			name:  "used or expired link",
+			token: "abc",
+			setupMock: func(users *mocks.MockUserRepository, links *mocks.MockEmailTokenRepository) {
+				links.EXPECT().Consume(gomock.Any(), hashToken("abc"), gomock.Any()).Return(nil, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrUnauthorized,
+		},
+		{
+			name:        "missing token",
+			setupMock:   func(*mocks.MockUserRepository, *mocks.MockEmailTokenRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockLinks := mocks.NewMockEmailTokenRepository(ctrl)
+			tt.setupMock(mockUsers, mockLinks)
+
+			auth := NewAuthServiceWithSecret(mockUsers, "secret")
+			service := NewMagicLinkService(auth, mockUsers, mockLinks, &recordingMailer{}, staticSettings{}, "")
+			token, err := service.Exchange(context.Background(), tt.token)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := auth.ValidateToken(token); err != nil {
+				t.Errorf("Expected a valid JWT, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// marketHistoryMonths is how many months of statistics a refresh builds,
+// the current month included
+const marketHistoryMonths = 24
+
+// monthLayout formats the months market statistics are kept by
+const monthLayout = "2006-01"
+
+// maxInventoryMonths caps the months of an inventory trend. Snapshots are
+// kept for good, so this only bounds the response.
+const maxInventoryMonths = 60
+
+var (
+	zipPattern      = regexp.MustCompile(`^\d{5}$`)
+	stateZipPattern = regexp.MustCompile(`^([A-Za-z]{2})?\s*(\d{5})?(-\d{4})?$`)
+)
+
+// MarketService builds monthly price and inventory statistics by city and
+// ZIP code and reports them per area
+type MarketService struct {
+	repo repository.MarketRepository
+	now  func() time.Time
+}
+
+func NewMarketService(repo repository.MarketRepository) *MarketService {
+	return &MarketService{repo: repo, now: time.Now}
+}
+
+// Refresh rebuilds the market statistics of the last marketHistoryMonths
+// from the listings and closed deals and returns how many area-months it
+// stored. Listings whose location has no recognizable city or ZIP code are
+// left out.
+func (s *MarketService) Refresh(ctx context.Context) (int, error) {
+	listings, err := s.repo.ListListings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	months := make([]time.Time, marketHistoryMonths)
+	for i := range months {
+		months[i] = current.AddDate(0, i-marketHistoryMonths+1, 0)
+	}
+
+	byArea := map[[2]string][]*models.MarketListing{}
+	for i := range listings {
+		city, zip := marketAreas(listings[i].Location)
+		if city != "" {
+			key := [2]string{models.MarketAreaCity, city}
+			byArea[key] = append(byArea[key], &listings[i])
+		}
+		if zip != "" {
+			key := [2]string{models.MarketAreaZip, zip}
+			byArea[key] = append(byArea[key], &listings[i])
+		}
+	}
+
+	stats := []models.MarketStat{}
+	for key, areaListings := range byArea {
+		for _, month := range months {
+			stat := monthStat(areaListings, month, month.AddDate(0, 1, 0))
+			if stat.NewListings == 0 && stat.ActiveListings == 0 && stat.Sales == 0 {
+				continue
+			}
+			stat.AreaType, stat.Area, stat.RefreshedAt = key[0], key[1], now
+			stats = append(stats, stat)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].AreaType != stats[j].AreaType {
+			return stats[i].AreaType < stats[j].AreaType
+		}
+		if stats[i].Area != stats[j].Area {
+			return stats[i].Area < stats[j].Area
+		}
+		return stats[i].Month < stats[j].Month
+	})
+
+	if err := s.repo.ReplaceStats(ctx, stats); err != nil {
+		return 0, err
+	}
+	return len(stats), nil
+}
+
+// Snapshot stores today's inventory, in UTC, by city and status: how many
+// listings for sale are in each status and their median price. Running it
+// again the same day replaces the day's snapshot. It returns how many
+// city-statuses it stored.
+func (s *MarketService) Snapshot(ctx context.Context) (int, error) {
+	listings, err := s.repo.ListListings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	prices := map[[2]string][]float64{}
+	for _, listing := range listings {
+		city, _ := marketAreas(listing.Location)
+		if city == "" {
+			continue
+		}
+		key := [2]string{city, listing.Status}
+		prices[key] = append(prices[key], listing.Price)
+	}
+
+	snapshots := make([]models.MarketSnapshot, 0, len(prices))
+	for key, cityPrices := range prices {
+		snapshots = append(snapshots, models.MarketSnapshot{
+			City:        key[0],
+			Status:      key[1],
+			Listings:    len(cityPrices),
+			MedianPrice: median(cityPrices),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].City != snapshots[j].City {
+			return snapshots[i].City < snapshots[j].City
+		}
+		return snapshots[i].Status < snapshots[j].Status
+	})
+
+	if err := s.repo.ReplaceSnapshot(ctx, s.now().UTC().Format(time.DateOnly), snapshots); err != nil {
+		return 0, err
+	}
+	return len(snapshots), nil
+}
+
+// Inventory returns a city's inventory by status over the last months,
+// oldest first, as of the last snapshot of each month. city is like
+// "Austin, TX"; without its state it is accepted while it names one city
+// only. The last month is the current one in location.
+func (s *MarketService) Inventory(ctx context.Context, city string, months int, location *time.Location) (*models.InventoryTrend, error) {
+	city = strings.Join(strings.Fields(city), " ")
+	if city == "" || zipPattern.MatchString(city) {
+		return nil, apperrors.Validation("city is required, like \"Austin, TX\"")
+	}
+	if months <= 0 {
+		months = 12
+	}
+	if months > maxInventoryMonths {
+		return nil, apperrors.Validationf("months must be at most %d", maxInventoryMonths)
+	}
+
+	now := s.now().In(location)
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location).AddDate(0, 1-months, 0)
+	filter := models.MarketSnapshotFilter{City: city, AnyState: !strings.Contains(city, ","), Since: first.Format(time.DateOnly)}
+	snapshots, err := s.repo.ListSnapshots(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshots come by day, so a later day of a month replaces the earlier
+	trend := &models.InventoryTrend{City: city, Months: make([]models.InventoryMonth, months), Timezone: location.String()}
+	byMonth := map[string]*models.InventoryMonth{}
+	for _, snapshot := range snapshots {
+		if !strings.EqualFold(snapshot.City, snapshots[0].City) {
+			return nil, apperrors.Validationf("%q matches more than one city; add the state, like %q", city, snapshot.City)
+		}
+		trend.City = snapshot.City
+		month := snapshot.Date[:len(monthLayout)]
+		entry, ok := byMonth[month]
+		if !ok || *entry.SnapshotDate != snapshot.Date {
+			date := snapshot.Date
+			entry = &models.InventoryMonth{Month: month, SnapshotDate: &date, Statuses: map[string]models.InventoryStatus{}}
+			byMonth[month] = entry
+		}
+		entry.Statuses[snapshot.Status] = models.InventoryStatus{Listings: snapshot.Listings, MedianPrice: snapshot.MedianPrice}
+	}
+
+	var previous *models.InventoryMonth
+	for i := range trend.Months {
+		month := first.AddDate(0, i, 0).Format(monthLayout)
+		entry, ok := byMonth[month]
+		if !ok {
+			trend.Months[i] = models.InventoryMonth{Month: month, Statuses: map[string]models.InventoryStatus{}}
+			previous = nil
+			continue
+		}
+		if previous != nil {
+			// A status missing from a snapshot had no listings that day
+			for status := range previous.Statuses {
+				if _, ok := entry.Statuses[status]; !ok {
+					entry.Statuses[status] = models.InventoryStatus{}
+				}
+			}
+			for status, inventory := range entry.Statuses {
+				change := inventory.Listings - previous.Statuses[status].Listings
+				inventory.ListingsChange = &change
+				entry.Statuses[status] = inventory
+			}
+		}
+		trend.Months[i] = *entry
+		previous = entry
+	}
+	return trend, nil
+}
+
+// Report returns the last months of an area's market, oldest first. area
+// is a five-digit ZIP code or a city, like "Austin, TX"; a city without
+// its state is accepted while it names one city only. Months without
+// activity are reported with zeros. The last month is the current one in
+// location.
+func (s *MarketService) Report(ctx context.Context, area string, months int, location *time.Location) (*models.MarketReport, error) {
+	area = strings.Join(strings.Fields(area), " ")
+	if area == "" {
+		return nil, apperrors.Validation("area is required, like a city (\"Austin, TX\") or a ZIP code (\"78701\")")
+	}
+	if months <= 0 {
+		months = 12
+	}
+	if months > marketHistoryMonths {
+		return nil, apperrors.Validationf("months must be at most %d", marketHistoryMonths)
+	}
+
+	now := s.now().In(location)
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location).AddDate(0, 1-months, 0)
+	filter := models.MarketStatFilter{AreaType: models.MarketAreaCity, Area: area, Since: first.Format(monthLayout)}
+	if zipPattern.MatchString(area) {
+		filter.AreaType = models.MarketAreaZip
+	} else if !strings.Contains(area, ",") {
+		filter.AnyState = true
+	}
+
+	stats, err := s.repo.ListStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.MarketReport{
+		AreaType: filter.AreaType,
+		Area:     area,
+		Months:   make([]models.MarketStat, months),
+		Timezone: location.String(),
+	}
+	byMonth := map[string]models.MarketStat{}
+	for _, stat := range stats {
+		if !strings.EqualFold(stat.Area, stats[0].Area) {
+			return nil, apperrors.Validationf("%q matches more than one city; add the state, like %q", area, stat.Area)
+		}
+		report.Area = stat.Area
+		if report.RefreshedAt == nil || stat.RefreshedAt.After(*report.RefreshedAt) {
+			refreshed := stat.RefreshedAt
+			report.RefreshedAt = &refreshed
+		}
+		byMonth[stat.Month] = stat
+	}
+
+	for i := range report.Months {
+		month := first.AddDate(0, i, 0).Format(monthLayout)
+		stat, ok := byMonth[month]
+		if !ok {
+			stat = models.MarketStat{Month: month}
+		}
+		if stat.Sales > 0 {
+			supply := math.Round(float64(stat.ActiveListings)/float64(stat.Sales)*10) / 10
+			stat.MonthsOfSupply = &supply
+		}
+		report.Months[i] = stat
+	}
+	return report, nil
+}
+
+// monthStat computes the statistics of the listings of one area for the
+// month from start until end
+func monthStat(listings []*models.MarketListing, start, end time.Time) models.MarketStat {
+	stat := models.MarketStat{Month: start.Format(monthLayout)}
+	var listPrices, salePrices []float64
+	var pricePerSqft float64
+	var sqftSales int
+	for _, listing := range listings {
+		leftAt, soldAt, salePrice := marketExit(listing)
+		if !listing.ListedAt.Before(start) && listing.ListedAt.Before(end) {
+			stat.NewListings++
+		}
+		if listing.ListedAt.Before(end) && (leftAt == nil || !leftAt.Before(end)) {
+			stat.ActiveListings++
+			listPrices = append(listPrices, listing.Price)
+		}
+		if soldAt != nil && !soldAt.Before(start) && soldAt.Before(end) && salePrice > 0 {
+			stat.Sales++
+			salePrices = append(salePrices, salePrice)
+			if listing.SquareFeet.Valid && listing.SquareFeet.Int32 > 0 {
+				pricePerSqft += salePrice / float64(listing.SquareFeet.Int32)
+				sqftSales++
+			}
+		}
+	}
+	stat.MedianListPrice = median(listPrices)
+	stat.MedianSalePrice = median(salePrices)
+	if sqftSales > 0 {
+		stat.AvgSalePricePerSqft = models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: roundCents(pricePerSqft / float64(sqftSales)), Valid: true}}
+	}
+	return stat
+}
+
+// marketExit returns when a listing left the market and, if it sold, when
+// and for how much. A closed deal gives the sale; a listing marked sold or
+// withdrawn without one is taken to have left when it was last updated.
+func marketExit(listing *models.MarketListing) (*time.Time, *time.Time, float64) {
+	if listing.SoldAt.Valid {
+		soldAt := listing.SoldAt.Time
+		price := listing.Price
+		if listing.SalePrice.Valid {
+			price = listing.SalePrice.Float64
+		}
+		return &soldAt, &soldAt, price
+	}
+	switch listing.Status {
+	case models.PropertyStatusSold:
+		updatedAt := listing.UpdatedAt
+		return &updatedAt, &updatedAt, listing.Price
+	case models.PropertyStatusWithdrawn:
+		updatedAt := listing.UpdatedAt
+		return &updatedAt, nil, 0
+	}
+	return nil, nil, 0
+}
+
+// marketAreas reads the city, as "City, ST", and the ZIP code from the end
+// of an address like "12 Elm St, Austin, TX 78701". Either is empty when
+// the address does not have it.
+func marketAreas(location string) (string, string) {
+	parts := strings.Split(location, ",")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	last := strings.TrimSpace(parts[len(parts)-1])
+	match := stateZipPattern.FindStringSubmatch(last)
+	if match == nil || (match[1] == "" && match[2] == "") {
+		return "", ""
+	}
+	zip := match[2]
+
+	// In "Austin, TX" the city is the first part; in "12 Elm St, TX" it is
+	// missing
+	city := ""
+	name := strings.Join(strings.Fields(parts[len(parts)-2]), " ")
+	if match[1] != "" && name != "" && (len(parts) >= 3 || !strings.ContainsAny(name, "0123456789")) {
+		city = name + ", " + strings.ToUpper(match[1])
+	}
+	return city, zip
+}
+
+func median(values []float64) models.NullFloat64 {
+	if len(values) == 0 {
+		return models.NullFloat64{}
+	}
+	sort.Float64s(values)
+	middle := len(values) / 2
+	value := values[middle]
+	if len(values)%2 == 0 {
+		value = (values[middle-1] + values[middle]) / 2
+	}
+	return models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: roundCents(value), Valid: true}}
+}
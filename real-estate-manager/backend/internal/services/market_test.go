@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestMarketAreas(t *testing.T) {
+	tests := []struct {
+		location string
+		city     string
+		zip      string
+	}{
+		{location: "12 Elm St, Austin, TX 78701", city: "Austin, TX", zip: "78701"},
+		{location: "12 Elm St, San  Antonio, tx 78205-1234", city: "San Antonio, TX", zip: "78205"},
+		{location: "Austin, TX", city: "Austin, TX"},
+		{location: "12 Elm St, TX 78701", zip: "78701"},
+		{location: "12 Elm St, Springfield", city: ""},
+		{location: "Downtown loft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			city, zip := marketAreas(tt.location)
+			if city != tt.city || zip != tt.zip {
+				t.Errorf("Expected %q and %q, got %q and %q", tt.city, tt.zip, city, zip)
+			}
+		})
+	}
+}
+
+func TestMarketService_Refresh(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	sqft := models.NullInt32{NullInt32: sql.NullInt32{Int32: 2000, Valid: true}}
+	listings := []models.MarketListing{
+		// Listed in April, still active
+		{PropertyID: 1, Location: "1 Elm St, Austin, TX 78701", Status: models.PropertyStatusActive, Price: 300000,
+			ListedAt: time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)},
+		// Listed in April, sold through a deal in May
+		{PropertyID: 2, Location: "2 Elm St, Austin, TX 78701", Status: models.PropertyStatusSold, Price: 500000, SquareFeet: sqft,
+			ListedAt:  time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+			SalePrice: models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 480000, Valid: true}},
+			SoldAt:    models.NullTime{NullTime: sql.NullTime{Time: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), Valid: true}}},
+		// Withdrawn in May
+		{PropertyID: 3, Location: "3 Oak St, Austin, TX 78702", Status: models.PropertyStatusWithdrawn, Price: 400000,
+			ListedAt: time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		// No recognizable area
+		{PropertyID: 4, Location: "Lake house", Price: 900000, ListedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var stored []models.MarketStat
+	mockRepo := mocks.NewMockMarketRepository(ctrl)
+	mockRepo.EXPECT().ListListings(gomock.Any()).Return(listings, nil)
+	mockRepo.EXPECT().ReplaceStats(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, stats []models.MarketStat) error {
+		stored = stats
+		return nil
+	})
+
+	service := NewMarketService(mockRepo)
+	service.now = func() time.Time { return now }
+	count, err := service.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if count != len(stored) {
+		t.Errorf("Expected the count of stored rows, got %d for %d", count, len(stored))
+	}
+
+	stats := map[string]models.MarketStat{}
+	for _, stat := range stored {
+		stats[stat.AreaType+"/"+stat.Area+"/"+stat.Month] = stat
+	}
+	april := stats["city/Austin, TX/2024-04"]
+	if april.NewListings != 3 || april.ActiveListings != 3 || april.MedianListPrice.Float64 != 400000 {
+		t.Errorf("Unexpected April: %+v", april)
+	}
+	may := stats["city/Austin, TX/2024-05"]
+	if may.ActiveListings != 1 || may.Sales != 1 || may.MedianSalePrice.Float64 != 480000 || may.AvgSalePricePerSqft.Float64 != 240 {
+		t.Errorf("Unexpected May: %+v", may)
+	}
+	if zip := stats["zip/78702/2024-05"]; zip.ActiveListings != 0 || stats["zip/78702/2024-04"].ActiveListings != 1 {
+		t.Errorf("Expected the withdrawn listing to leave 78702 in May, got %+v", zip)
+	}
+	if _, ok := stats["city/Austin, TX/2024-03"]; ok {
+		t.Error("Expected no row for a month without activity")
+	}
+}
+
+func TestMarketService_Report(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	refreshed := now.Add(-time.Hour)
+
+	t.Run("city without state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockMarketRepository(ctrl)
+		mockRepo.EXPECT().ListStats(gomock.Any(), models.MarketStatFilter{AreaType: models.MarketAreaCity, Area: "Austin",
+			AnyState: true, Since: "2024-04"}).Return([]models.MarketStat{
+			{Area: "Austin, TX", Month: "2024-05", ActiveListings: 6, Sales: 2, RefreshedAt: refreshed},
+		}, nil)
+
+		service := NewMarketService(mockRepo)
+		service.now = func() time.Time { return now }
+		report, err := service.Report(context.Background(), " Austin ", 3, time.UTC)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if report.Area != "Austin, TX" || len(report.Months) != 3 || report.Months[0].Month != "2024-04" {
+			t.Fatalf("Unexpected report: %+v", report)
+		}
+		if supply := report.Months[1].MonthsOfSupply; supply == nil || *supply != 3 {
+			t.Errorf("Expected 3 months of supply in May, got %v", supply)
+		}
+		if report.Months[2].Sales != 0 || report.Months[2].MonthsOfSupply != nil {
+			t.Errorf("Expected an empty June, got %+v", report.Months[2])
+		}
+	})
+
+	t.Run("city in two states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockMarketRepository(ctrl)
+		mockRepo.EXPECT().ListStats(gomock.Any(), gomock.Any()).Return([]models.MarketStat{
+			{Area: "Springfield, IL", Month: "2024-05"}, {Area: "Springfield, MO", Month: "2024-05"},
+		}, nil)
+
+		service := NewMarketService(mockRepo)
+		if _, err := service.Report(context.Background(), "Springfield", 0, time.UTC); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	})
+
+	t.Run("zip code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockMarketRepository(ctrl)
+		mockRepo.EXPECT().ListStats(gomock.Any(), models.MarketStatFilter{AreaType: models.MarketAreaZip, Area: "78701",
+			Since: "2023-07"}).Return([]models.MarketStat{}, nil)
+
+		service := NewMarketService(mockRepo)
+		service.now = func() time.Time { return now }
+		report, err := service.Report(context.Background(), "78701", 0, time.UTC)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if len(report.Months) != 12 || report.RefreshedAt != nil {
+			t.Errorf("Expected 12 empty months, got %+v", report)
+		}
+	})
+
+	t.Run("no area", func(t *testing.T) {
+		service := NewMarketService(nil)
+		if _, err := service.Report(context.Background(), " ", 0, time.UTC); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	})
+}
+
+func TestMarketService_Snapshot(t *testing.T) {
+	now := time.Date(2024, 6, 15, 23, 30, 0, 0, time.FixedZone("CDT", -5*60*60))
+	listings := []models.MarketListing{
+		{Location: "1 Elm St, Austin, TX 78701", Status: models.PropertyStatusActive, Price: 300000},
+		{Location: "2 Elm St, Austin, TX 78701", Status: models.PropertyStatusActive, Price: 500000},
+		{Location: "3 Oak St, Austin, TX 78702", Status: models.PropertyStatusSold, Price: 400000},
+		{Location: "Lake house", Status: models.PropertyStatusActive, Price: 900000},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var stored []models.MarketSnapshot
+	mockRepo := mocks.NewMockMarketRepository(ctrl)
+	mockRepo.EXPECT().ListListings(gomock.Any()).Return(listings, nil)
+	// Snapshots are dated in UTC
+	mockRepo.EXPECT().ReplaceSnapshot(gomock.Any(), "2024-06-16", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, date string, snapshots []models.MarketSnapshot) error {
+			stored = snapshots
+			return nil
+		})
+
+	service := NewMarketService(mockRepo)
+	service.now = func() time.Time { return now }
+	count, err := service.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if count != 2 || len(stored) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d: %+v", count, stored)
+	}
+	if active := stored[0]; active.Status != models.PropertyStatusActive || active.Listings != 2 || active.MedianPrice.Float64 != 400000 {
+		t.Errorf("Unexpected active inventory: %+v", active)
+	}
+	if sold := stored[1]; sold.City != "Austin, TX" || sold.Status != models.PropertyStatusSold || sold.Listings != 1 {
+		t.Errorf("Unexpected sold inventory: %+v", sold)
+	}
+}
+
+func TestMarketService_Inventory(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("monthly changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockMarketRepository(ctrl)
+		mockRepo.EXPECT().ListSnapshots(gomock.Any(), models.MarketSnapshotFilter{City: "Austin", AnyState: true, Since: "2024-03-01"}).
+			Return([]models.MarketSnapshot{
+				{Date: "2024-04-10", City: "Austin, TX", Status: models.PropertyStatusActive, Listings: 9},
+				{Date: "2024-04-30", City: "Austin, TX", Status: models.PropertyStatusActive, Listings: 5},
+				{Date: "2024-04-30", City: "Austin, TX", Status: models.PropertyStatusPending, Listings: 2},
+				{Date: "2024-05-31", City: "Austin, TX", Status: models.PropertyStatusActive, Listings: 8},
+			}, nil)
+
+		service := NewMarketService(mockRepo)
+		service.now = func() time.Time { return now }
+		trend, err := service.Inventory(context.Background(), "Austin", 4, time.UTC)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if trend.City != "Austin, TX" || len(trend.Months) != 4 {
+			t.Fatalf("Unexpected trend: %+v", trend)
+		}
+		if march := trend.Months[0]; march.SnapshotDate != nil || len(march.Statuses) != 0 {
+			t.Errorf("Expected no inventory in March, got %+v", march)
+		}
+		april := trend.Months[1]
+		if *april.SnapshotDate != "2024-04-30" || april.Statuses[models.PropertyStatusActive].Listings != 5 ||
+			april.Statuses[models.PropertyStatusActive].ListingsChange != nil {
+			t.Errorf("Expected April's last snapshot without a change, got %+v", april)
+		}
+		may := trend.Months[2]
+		if change := may.Statuses[models.PropertyStatusActive].ListingsChange; change == nil || *change != 3 {
+			t.Errorf("Expected 3 more active listings in May, got %v", change)
+		}
+		if change := may.Statuses[models.PropertyStatusPending].ListingsChange; change == nil || *change != -2 {
+			t.Errorf("Expected 2 fewer pending listings in May, got %v", change)
+		}
+	})
+
+	t.Run("invalid city", func(t *testing.T) {
+		service := NewMarketService(nil)
+		for _, city := range []string{" ", "78701"} {
+			if _, err := service.Inventory(context.Background(), city, 0, time.UTC); !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("Expected a validation error for %q, got %v", city, err)
+			}
+		}
+		if _, err := service.Inventory(context.Background(), "Austin, TX", maxInventoryMonths+1, time.UTC); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected a validation error for too many months, got %v", err)
+		}
+	})
+}
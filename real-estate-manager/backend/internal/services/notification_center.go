@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Page sizes of the notification center
+const (
+	DefaultNotificationLimit = 20
+	MaxNotificationLimit     = 100
+)
+
+// NotificationCenterService is the in-app notification center. Its entries
+// are created by HandleEvent, subscribed to the event bus.
+type NotificationCenterService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationCenterService(repo repository.NotificationRepository) *NotificationCenterService {
+	return &NotificationCenterService{repo: repo}
+}
+
+// List returns a page of the user's notifications, newest first, with the
+// number of unread ones
+func (s *NotificationCenterService) List(ctx context.Context, userID uint, query models.NotificationQuery) (*models.NotificationPage, error) {
+	if query.Limit == 0 {
+		query.Limit = DefaultNotificationLimit
+	}
+	if query.Limit < 1 || query.Limit > MaxNotificationLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxNotificationLimit)
+	}
+	if query.BeforeID < 0 {
+		return nil, apperrors.Validation("before must be a notification ID")
+	}
+
+	// One extra row tells whether another page follows
+	limit := query.Limit
+	query.Limit++
+	notifications, err := s.repo.List(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.NotificationPage{Notifications: notifications, UnreadCount: unread}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		page.HasMore = true
+		page.Cursor = strconv.Itoa(page.Notifications[limit-1].ID)
+	}
+	return page, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationCenterService) MarkRead(ctx context.Context, userID uint, id int) error {
+	exists, err := s.repo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("notification not found")
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of the user as read and returns how
+// many were unread
+func (s *NotificationCenterService) MarkAllRead(ctx context.Context, userID uint) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// HandleEvent turns domain events into notifications: agents hear about
+// new leads assigned to them, changes others make to their listings,
+// reviews of the listings they submitted, listings expiring and offers on
+// their listings, and users about the import jobs they started and the
+// answers to offers they entered
+func (s *NotificationCenterService) HandleEvent(ctx context.Context, event events.Event) {
+	notification := notificationFor(event)
+	if notification == nil {
+		return
+	}
+	if err := s.repo.Create(ctx, notification); err != nil {
+		log.Printf("Failed to store %s notification for user %d: %v", event.Type, notification.UserID, err)
+	}
+}
+
+// notificationFor returns the notification an event produces, if any
+func notificationFor(event events.Event) *models.Notification {
+	switch event.Type {
+	case events.PropertyUpdated:
+		property, ok := event.Data.(*models.Property)
+		if !ok || !property.AgentID.Valid || int(property.AgentID.Int32) == event.ActorID {
+			return nil
+		}
+		return &models.Notification{
+			UserID:  uint(property.AgentID.Int32),
+			Type:    event.Type,
+			Title:   "Listing updated",
+			Body:    fmt.Sprintf("%s was updated by another user", property.Name),
+			Subject: event.Subject,
+		}
+
+	case events.ListingApproved, events.ListingRejected:
+		review, ok := event.Data.(*models.ListingReview)
+		if !ok || !review.Property.AgentID.Valid || int(review.Property.AgentID.Int32) == event.ActorID {
+			return nil
+		}
+		notification := &models.Notification{
+			UserID:  uint(review.Property.AgentID.Int32),
+			Type:    event.Type,
+			Title:   "Listing approved",
+			Body:    fmt.Sprintf("%s was approved and is now public", review.Property.Name),
+			Subject: event.Subject,
+		}
+		if event.Type == events.ListingRejected {
+			notification.Title = "Listing rejected"
+			notification.Body = fmt.Sprintf("%s was not approved: %s", review.Property.Name, review.ReviewComment)
+		}
+		return notification
+
+	case events.ListingExpiring, events.ListingExpired:
+		property, ok := event.Data.(*models.Property)
+		if !ok || !property.AgentID.Valid {
+			return nil
+		}
+		notification := &models.Notification{
+			UserID:  uint(property.AgentID.Int32),
+			Type:    event.Type,
+			Title:   "Listing expiring soon",
+			Body:    fmt.Sprintf("%s expires on %s", property.Name, property.ExpiresAt.Time.UTC().Format("Jan 2, 2006")),
+			Subject: event.Subject,
+		}
+		if event.Type == events.ListingExpired {
+			notification.Title = "Listing expired"
+			notification.Body = fmt.Sprintf("%s expired and was taken off public feeds", property.Name)
+		}
+		return notification
+
+	case events.OfferSubmitted, events.OfferCountered, events.OfferAccepted, events.OfferRejected:
+		activity, ok := event.Data.(*models.OfferActivity)
+		if !ok {
+			return nil
+		}
+		return offerNotification(event, activity)
+
+	case events.LeadCreated:
+		lead, ok := event.Data.(*models.Lead)
+		if !ok || !lead.AssignedTo.Valid {
+			return nil
+		}
+		return &models.Notification{
+			UserID:  uint(lead.AssignedTo.Int32),
+			Type:    event.Type,
+			Title:   "New lead",
+			Body:    fmt.Sprintf("%s sent an enquiry through %s", leadContact(lead), lead.Source),
+			Subject: event.Subject,
+		}
+
+	case events.JobCompleted, events.JobFailed, events.JobCancelled:
+		status, ok := event.Data.(models.ProcessingStatus)
+		if !ok || event.ActorID == 0 {
+			return nil
+		}
+		notification := &models.Notification{UserID: uint(event.ActorID), Type: event.Type, Subject: event.Subject}
+		switch event.Type {
+		case events.JobFailed:
+			notification.Title = "Import failed"
+			notification.Body = fmt.Sprintf("The SimplyRETS import failed after %d of %d listings: %s",
+				status.ProcessedCount, status.TotalProperties, status.ErrorMessage)
+		case events.JobCancelled:
+			notification.Title = "Import cancelled"
+			notification.Body = fmt.Sprintf("The SimplyRETS import was cancelled after %d of %d listings",
+				status.ProcessedCount, status.TotalProperties)
+		default:
+			notification.Title = "Import finished"
+			notification.Body = fmt.Sprintf("The SimplyRETS import processed %d listings, %d failed",
+				status.ProcessedCount, status.FailedCount)
+		}
+		return notification
+	}
+	return nil
+}
+
+// offerNotification tells the listing agent about offers submitted on
+// their listing, and whoever entered an offer about the seller's answer.
+// Nobody is notified of their own actions.
+func offerNotification(event events.Event, activity *models.OfferActivity) *models.Notification {
+	var recipient models.NullInt32
+	notification := &models.Notification{Type: event.Type, Subject: event.Subject}
+	switch event.Type {
+	case events.OfferSubmitted:
+		recipient = activity.Property.AgentID
+		notification.Title = "New offer"
+		notification.Body = fmt.Sprintf("%s offered %s on %s", activity.BuyerName, formatPrice(activity.Amount), activity.Property.Name)
+		if activity.CounterAmount.Valid {
+			notification.Title = "Offer revised"
+		}
+	case events.OfferCountered:
+		recipient = activity.CreatedBy
+		notification.Title = "Offer countered"
+		notification.Body = fmt.Sprintf("The seller of %s countered %s's offer at %s", activity.Property.Name,
+			activity.BuyerName, formatPrice(activity.CounterAmount.Float64))
+	case events.OfferAccepted:
+		recipient = activity.CreatedBy
+		notification.Title = "Offer accepted"
+		notification.Body = fmt.Sprintf("%s's offer of %s on %s was accepted", activity.BuyerName, formatPrice(activity.Amount),
+			activity.Property.Name)
+	default:
+		recipient = activity.CreatedBy
+		notification.Title = "Offer rejected"
+		notification.Body = fmt.Sprintf("%s's offer on %s was rejected", activity.BuyerName, activity.Property.Name)
+	}
+	if !recipient.Valid || int(recipient.Int32) == event.ActorID {
+		return nil
+	}
+	notification.UserID = uint(recipient.Int32)
+	return notification
+}
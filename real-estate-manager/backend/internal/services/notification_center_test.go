@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotificationCenterService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), uint(7), models.NotificationQuery{BeforeID: 50, Limit: 3}).
+		Return([]models.Notification{{ID: 49}, {ID: 45}, {ID: 44}}, nil)
+	mockRepo.EXPECT().CountUnread(gomock.Any(), uint(7)).Return(5, nil)
+
+	service := NewNotificationCenterService(mockRepo)
+	page, err := service.List(context.Background(), 7, models.NotificationQuery{BeforeID: 50, Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Notifications) != 2 || !page.HasMore || page.Cursor != "45" || page.UnreadCount != 5 {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	if _, err := service.List(context.Background(), 7, models.NotificationQuery{Limit: MaxNotificationLimit + 1}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for an oversized page, got %v", err)
+	}
+}
+
+func TestNotificationCenterService_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockRepo.EXPECT().MarkRead(gomock.Any(), uint(7), 40).Return(true, nil)
+	mockRepo.EXPECT().MarkRead(gomock.Any(), uint(7), 41).Return(false, nil)
+
+	service := NewNotificationCenterService(mockRepo)
+	if err := service.MarkRead(context.Background(), 7, 40); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := service.MarkRead(context.Background(), 7, 41); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found for another user's notification, got %v", err)
+	}
+}
+
+func TestNotificationCenterService_HandleEvent(t *testing.T) {
+	agent := models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}
+	event := func(eventType string, actorID int, data any) events.Event {
+		e := events.New(eventType, "subject/1", data)
+		e.ActorID = actorID
+		return e
+	}
+
+	tests := []struct {
+		name        string
+		event       events.Event
+		expectUser  uint
+		expectTitle string
+	}{
+		{name: "listing updated by someone else", event: event(events.PropertyUpdated, 9, &models.Property{Name: "Casa", AgentID: agent}),
+			expectUser: 4, expectTitle: "Listing updated"},
+		{name: "listing updated by its agent", event: event(events.PropertyUpdated, 4, &models.Property{Name: "Casa", AgentID: agent})},
+		{name: "unassigned listing updated", event: event(events.PropertyUpdated, 9, &models.Property{Name: "Casa"})},
+		{name: "job failed", event: event(events.JobFailed, 9, models.ProcessingStatus{Status: "failed", ErrorMessage: "timeout"}),
+			expectUser: 9, expectTitle: "Import failed"},
+		{name: "job completed without a starter", event: event(events.JobCompleted, 0, models.ProcessingStatus{Status: "completed"})},
+		{name: "lead assigned", event: event(events.LeadCreated, 0, &models.Lead{Name: "Jane", Source: "zillow", AssignedTo: agent}),
+			expectUser: 4, expectTitle: "New lead"},
+		{name: "unassigned lead", event: event(events.LeadCreated, 0, &models.Lead{Name: "Jane", Source: "zillow"})},
+		{name: "listing rejected", event: event(events.ListingRejected, 9, &models.ListingReview{
+			ListingPublication: models.ListingPublication{State: models.PublishRejected, ReviewComment: "Add photos"},
+			Property:           models.Property{Name: "Casa", AgentID: agent},
+		}), expectUser: 4, expectTitle: "Listing rejected"},
+		{name: "listing expiring", event: event(events.ListingExpiring, 0, &models.Property{Name: "Casa", AgentID: agent}),
+			expectUser: 4, expectTitle: "Listing expiring soon"},
+		{name: "listing expired", event: event(events.ListingExpired, 0, &models.Property{Name: "Casa", AgentID: agent}),
+			expectUser: 4, expectTitle: "Listing expired"},
+		{name: "offer submitted", event: event(events.OfferSubmitted, 9, &models.OfferActivity{
+			Offer: models.Offer{BuyerName: "Jane", Amount: 395000}, Property: models.Property{Name: "Casa", AgentID: agent},
+		}), expectUser: 4, expectTitle: "New offer"},
+		{name: "offer countered", event: event(events.OfferCountered, 4, &models.OfferActivity{
+			Offer:    models.Offer{BuyerName: "Jane", CreatedBy: models.NullInt32{NullInt32: sql.NullInt32{Int32: 9, Valid: true}}},
+			Property: models.Property{Name: "Casa", AgentID: agent},
+		}), expectUser: 9, expectTitle: "Offer countered"},
+		{name: "offer accepted by whoever entered it", event: event(events.OfferAccepted, 9, &models.OfferActivity{
+			Offer: models.Offer{BuyerName: "Jane", CreatedBy: models.NullInt32{NullInt32: sql.NullInt32{Int32: 9, Valid: true}}},
+		})},
+		{name: "unrelated event", event: event(events.JobStarted, 9, map[string]any{"limit": 10})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockNotificationRepository(ctrl)
+			var created *models.Notification
+			if tt.expectUser != 0 {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, n *models.Notification) error {
+					created = n
+					return nil
+				})
+			}
+
+			NewNotificationCenterService(mockRepo).HandleEvent(context.Background(), tt.event)
+			if tt.expectUser != 0 && (created.UserID != tt.expectUser || created.Title != tt.expectTitle || created.Subject != "subject/1") {
+				t.Errorf("Unexpected notification: %+v", created)
+			}
+		})
+	}
+}
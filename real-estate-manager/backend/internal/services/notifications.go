@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/sms"
+)
+
+// Time-sensitive notifications that may be sent as text messages
+const (
+	NotifyNewLead          = "new_lead"
+	NotifyShowingConfirmed = "showing_confirmed"
+	NotifyShowingCancelled = "showing_cancelled"
+)
+
+// maxNotificationText caps a text message at two SMS segments
+const maxNotificationText = 320
+
+var (
+	phonePattern     = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	clockTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// NotificationService delivers time-sensitive notifications, such as new
+// leads and showing confirmations, by SMS or WhatsApp to users who opted in
+type NotificationService struct {
+	prefs  repository.NotificationPreferenceRepository
+	sender sms.Sender
+	now    func() time.Time
+}
+
+// NewNotificationService creates the service; sender may be nil when text
+// messages are disabled, in which case notifications are dropped
+func NewNotificationService(prefs repository.NotificationPreferenceRepository, sender sms.Sender) *NotificationService {
+	return &NotificationService{prefs: prefs, sender: sender, now: time.Now}
+}
+
+// GetPreferences returns a user's preferences, or the defaults (opted out)
+// when none are saved
+func (s *NotificationService) GetPreferences(ctx context.Context, userID uint) (*models.NotificationPreferences, error) {
+	prefs, err := s.prefs.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = &models.NotificationPreferences{UserID: userID, Channel: sms.ChannelSMS, Timezone: "UTC"}
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences validates and saves a user's preferences
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uint, prefs *models.NotificationPreferences) error {
+	prefs.UserID = userID
+	prefs.Phone = strings.ReplaceAll(strings.TrimSpace(prefs.Phone), " ", "")
+	if prefs.Channel == "" {
+		prefs.Channel = sms.ChannelSMS
+	}
+	if prefs.Timezone == "" {
+		prefs.Timezone = "UTC"
+	}
+
+	if prefs.Phone != "" && !phonePattern.MatchString(prefs.Phone) {
+		return apperrors.Validation("phone must be in international format, e.g. +15551234567")
+	}
+	if prefs.SMSOptIn && prefs.Phone == "" {
+		return apperrors.Validation("a phone number is required to opt in to text messages")
+	}
+	if prefs.Channel != sms.ChannelSMS && prefs.Channel != sms.ChannelWhatsApp {
+		return apperrors.Validation("channel must be sms or whatsapp")
+	}
+	if (prefs.QuietStart == "") != (prefs.QuietEnd == "") {
+		return apperrors.Validation("quiet_start and quiet_end must be set together")
+	}
+	for _, value := range []string{prefs.QuietStart, prefs.QuietEnd} {
+		if value != "" && !clockTimePattern.MatchString(value) {
+			return apperrors.Validation("quiet hours must be HH:MM")
+		}
+	}
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+		return apperrors.Validationf("unknown timezone %q", prefs.Timezone)
+	}
+
+	if err := s.prefs.Save(ctx, prefs); err != nil {
+		return err
+	}
+	prefs.UpdatedAt = s.now()
+	return nil
+}
+
+// Location resolves the time zone a user's reports and digests are
+// rendered in: tz when given, such as a ?tz= parameter, otherwise the
+// timezone of the user's preferences, which defaults to UTC
+func (s *NotificationService) Location(ctx context.Context, userID uint, tz string) (*time.Location, error) {
+	if tz != "" {
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, apperrors.Validationf("unknown timezone %q", tz)
+		}
+		return location, nil
+	}
+	prefs, err := s.prefs.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil || prefs.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
+// NotifyUrgent texts userID about a time-sensitive event. It reports
+// whether a message was sent; users who have not opted in or are in their
+// quiet hours are skipped without error.
+func (s *NotificationService) NotifyUrgent(ctx context.Context, userID uint, kind, text string) (bool, error) {
+	if s.sender == nil {
+		return false, nil
+	}
+	prefs, err := s.prefs.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if prefs == nil || !prefs.SMSOptIn || prefs.Phone == "" {
+		return false, nil
+	}
+	if inQuietHours(prefs, s.now()) {
+		log.Printf("Skipping %s text to user %d during quiet hours", kind, userID)
+		return false, nil
+	}
+
+	if runes := []rune(text); len(runes) > maxNotificationText {
+		text = string(runes[:maxNotificationText-3]) + "..."
+	}
+	if err := s.sender.Send(ctx, sms.Message{To: prefs.Phone, Body: text, Channel: prefs.Channel}); err != nil {
+		return false, fmt.Errorf("failed to send %s notification: %w", kind, err)
+	}
+	return true, nil
+}
+
+// inQuietHours reports whether now falls in the user's quiet hours, which
+// may span midnight (e.g. 22:00-07:00)
+func inQuietHours(prefs *models.NotificationPreferences, now time.Time) bool {
+	if prefs.QuietStart == "" || prefs.QuietEnd == "" {
+		return false
+	}
+	location, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	clock := now.In(location).Format("15:04")
+	if prefs.QuietStart <= prefs.QuietEnd {
+		return clock >= prefs.QuietStart && clock < prefs.QuietEnd
+	}
+	return clock >= prefs.QuietStart || clock < prefs.QuietEnd
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/sms"
+
+	"go.uber.org/mock/gomock"
+)
+
+// recordingSender keeps sent text messages in memory
+type recordingSender struct {
+	sent []sms.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg sms.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestNotificationService_NotifyUrgent(t *testing.T) {
+	// 23:30 in Chicago
+	now := time.Date(2024, 6, 2, 4, 30, 0, 0, time.UTC)
+	optedIn := func(quietStart, quietEnd string) *models.NotificationPreferences {
+		return &models.NotificationPreferences{UserID: 7, Phone: "+15551234567", SMSOptIn: true, Channel: sms.ChannelWhatsApp,
+			QuietStart: quietStart, QuietEnd: quietEnd, Timezone: "America/Chicago"}
+	}
+
+	tests := []struct {
+		name       string
+		prefs      *models.NotificationPreferences
+		expectSent bool
+	}{
+		{name: "opted in", prefs: optedIn("", ""), expectSent: true},
+		{name: "outside quiet hours", prefs: optedIn("08:00", "12:00"), expectSent: true},
+		{name: "quiet hours spanning midnight", prefs: optedIn("22:00", "07:00")},
+		{name: "not opted in", prefs: &models.NotificationPreferences{UserID: 7, Phone: "+15551234567", Channel: sms.ChannelSMS}},
+		{name: "no preferences saved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPrefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+			mockPrefs.EXPECT().Get(gomock.Any(), uint(7)).Return(tt.prefs, nil)
+
+			sender := &recordingSender{}
+			service := NewNotificationService(mockPrefs, sender)
+			service.now = func() time.Time { return now }
+
+			sent, err := service.NotifyUrgent(context.Background(), 7, NotifyNewLead, "New lead for "+strings.Repeat("x", 400))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if sent != tt.expectSent || sent != (len(sender.sent) == 1) {
+				t.Fatalf("Expected sent %v, got %v with %d messages", tt.expectSent, sent, len(sender.sent))
+			}
+			if sent {
+				msg := sender.sent[0]
+				if msg.To != "+15551234567" || msg.Channel != sms.ChannelWhatsApp || len([]rune(msg.Body)) != maxNotificationText {
+					t.Errorf("Unexpected message: %+v", msg)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefs       models.NotificationPreferences
+		expectSaved bool
+	}{
+		{name: "valid", prefs: models.NotificationPreferences{Phone: "+1 555 123 4567", SMSOptIn: true, QuietStart: "22:00", QuietEnd: "07:00", Timezone: "Europe/Lisbon"}, expectSaved: true},
+		{name: "opt out without phone", prefs: models.NotificationPreferences{}, expectSaved: true},
+		{name: "local phone format", prefs: models.NotificationPreferences{Phone: "555-1234", SMSOptIn: true}},
+		{name: "opt in without phone", prefs: models.NotificationPreferences{SMSOptIn: true}},
+		{name: "unknown channel", prefs: models.NotificationPreferences{Phone: "+15551234567", Channel: "pager"}},
+		{name: "half-open quiet hours", prefs: models.NotificationPreferences{QuietStart: "22:00"}},
+		{name: "invalid clock time", prefs: models.NotificationPreferences{QuietStart: "25:00", QuietEnd: "07:00"}},
+		{name: "unknown timezone", prefs: models.NotificationPreferences{Timezone: "Mars/Olympus"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPrefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+			if tt.expectSaved {
+				mockPrefs.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewNotificationService(mockPrefs, nil)
+			prefs := tt.prefs
+			err := service.UpdatePreferences(context.Background(), 7, &prefs)
+			if tt.expectSaved {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if prefs.UserID != 7 || prefs.Channel != sms.ChannelSMS || strings.Contains(prefs.Phone, " ") {
+					t.Errorf("Expected normalized preferences, got %+v", prefs)
+				}
+			} else if !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNotificationService_Location(t *testing.T) {
+	tests := []struct {
+		name        string
+		tz          string
+		prefs       *models.NotificationPreferences
+		expected    string
+		expectError bool
+	}{
+		{name: "explicit zone wins", tz: "Asia/Tokyo", expected: "Asia/Tokyo"},
+		{name: "user preference", prefs: &models.NotificationPreferences{Timezone: "Europe/Lisbon"}, expected: "Europe/Lisbon"},
+		{name: "no preferences", expected: "UTC"},
+		{name: "unknown zone", tz: "Mars/Olympus", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPrefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+			if tt.tz == "" {
+				mockPrefs.EXPECT().Get(gomock.Any(), uint(7)).Return(tt.prefs, nil)
+			}
+
+			location, err := NewNotificationService(mockPrefs, nil).Location(context.Background(), 7, tt.tz)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if location.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// offerEventTypes maps the status an offer moves to to the event published
+var offerEventTypes = map[string]string{
+	models.OfferSubmitted: events.OfferSubmitted,
+	models.OfferCountered: events.OfferCountered,
+	models.OfferAccepted:  events.OfferAccepted,
+	models.OfferRejected:  events.OfferRejected,
+}
+
+// OfferService takes offers on listings through submission, counters and
+// the seller's answer. Every step publishes an offer event, which the
+// notification center turns into notifications.
+type OfferService struct {
+	repo       repository.OfferRepository
+	properties *PropertyService
+	events     EventPublisher
+	now        func() time.Time
+}
+
+func NewOfferService(repo repository.OfferRepository, properties *PropertyService, publisher EventPublisher) *OfferService {
+	return &OfferService{repo: repo, properties: properties, events: publisher, now: time.Now}
+}
+
+// Dashboard returns a listing's offers, newest first, with the number in
+// each status and the best open offer
+func (s *OfferService) Dashboard(ctx context.Context, propertyID int) (*models.OfferDashboard, error) {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	offers, err := s.repo.ListByProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	dashboard := &models.OfferDashboard{PropertyID: propertyID, Counts: make(map[string]int, len(models.OfferStatuses)), Offers: offers}
+	for _, status := range models.OfferStatuses {
+		dashboard.Counts[status] = 0
+	}
+	for i := range offers {
+		offer := &offers[i]
+		offer.Expired = offerExpired(offer, now)
+		dashboard.Counts[offer.Status]++
+		switch {
+		case offer.Expired:
+			dashboard.Expired++
+		case offer.IsOpen():
+			dashboard.Open++
+			if dashboard.HighestOpen == nil || offer.Amount > *dashboard.HighestOpen {
+				amount := offer.Amount
+				dashboard.HighestOpen = &amount
+			}
+			if offer.ExpiresAt.Valid && (dashboard.NextExpiry == nil || offer.ExpiresAt.Time.Before(*dashboard.NextExpiry)) {
+				expiry := offer.ExpiresAt.Time
+				dashboard.NextExpiry = &expiry
+			}
+		}
+	}
+	return dashboard, nil
+}
+
+func (s *OfferService) Get(ctx context.Context, id int) (*models.Offer, error) {
+	offer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer == nil {
+		return nil, apperrors.NotFound("offer not found")
+	}
+	// Offers are only visible to those who can see the listing
+	if _, err := s.properties.GetProperty(ctx, offer.PropertyID); err != nil {
+		return nil, err
+	}
+	offer.Expired = offerExpired(offer, s.now())
+	return offer, nil
+}
+
+// Create submits an offer on a listing for sale that is still on the
+// market
+func (s *OfferService) Create(ctx context.Context, propertyID int, offer *models.Offer) error {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property.IsRental() {
+		return apperrors.Validation("offers can only be made on listings for sale")
+	}
+	switch property.Status {
+	case models.PropertyStatusSold, models.PropertyStatusWithdrawn, models.PropertyStatusExpired:
+		return apperrors.Validationf("the listing is %s and takes no offers", property.Status)
+	}
+	if err := s.validate(offer); err != nil {
+		return err
+	}
+
+	offer.ID = 0
+	offer.PropertyID = propertyID
+	offer.Status = models.OfferSubmitted
+	offer.CounterAmount = models.NullFloat64{}
+	offer.RespondedAt = models.NullTime{}
+	offer.CreatedBy = models.NullInt32{}
+	if userID, ok := ActorFromContext(ctx); ok {
+		offer.CreatedBy = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(userID), Valid: true}}
+	}
+	if err := s.repo.Create(ctx, offer); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, events.OfferSubmitted, offerSubject(offer.ID), &models.OfferActivity{Offer: *offer, Property: *property})
+	return nil
+}
+
+// Transition moves an open offer to another status. The seller counters,
+// accepts or rejects; the buyer answers a counter by accepting it, at the
+// counter amount, or by submitting a new amount. An
+// expired offer can only be rejected, and only one offer per listing can
+// be accepted.
+func (s *OfferService) Transition(ctx context.Context, id int, transition models.OfferTransition) (*models.Offer, error) {
+	offer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer == nil {
+		return nil, apperrors.NotFound("offer not found")
+	}
+	property, err := s.properties.GetProperty(ctx, offer.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !slices.Contains(models.OfferStatuses, transition.Status) {
+		return nil, apperrors.Validationf("status must be one of %s", strings.Join(models.OfferStatuses, ", "))
+	}
+	if !offer.IsOpen() {
+		return nil, apperrors.Conflictf("the offer was already %s", offer.Status)
+	}
+	if !models.CanTransitionOffer(offer.Status, transition.Status) {
+		return nil, apperrors.Validationf("a %s offer cannot be %s", offer.Status, transition.Status)
+	}
+	if offerExpired(offer, now) && transition.Status != models.OfferRejected {
+		return nil, apperrors.Validation("the offer has expired")
+	}
+	if transition.ExpiresAt.Valid && !transition.ExpiresAt.Time.After(now) {
+		return nil, apperrors.Validation("expires_at must be in the future")
+	}
+
+	switch transition.Status {
+	case models.OfferCountered:
+		if transition.CounterAmount <= 0 {
+			return nil, apperrors.Validation("counter_amount must be greater than 0")
+		}
+		offer.CounterAmount = models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: transition.CounterAmount, Valid: true}}
+	case models.OfferSubmitted:
+		if transition.Amount <= 0 {
+			return nil, apperrors.Validation("amount must be greater than 0")
+		}
+		offer.Amount = transition.Amount
+	case models.OfferAccepted:
+		offers, err := s.repo.ListByProperty(ctx, offer.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range offers {
+			if other.ID != offer.ID && other.Status == models.OfferAccepted {
+				return nil, apperrors.Conflict("another offer on this listing was already accepted")
+			}
+		}
+		// Accepting a counter agrees to the seller's price
+		if offer.Status == models.OfferCountered {
+			offer.Amount = offer.CounterAmount.Float64
+		}
+	}
+
+	if transition.Status != models.OfferSubmitted {
+		offer.RespondedAt = nullTime(now)
+	}
+	if transition.ExpiresAt.Valid {
+		offer.ExpiresAt = models.NullTime{NullTime: sql.NullTime{Time: transition.ExpiresAt.Time.UTC(), Valid: true}}
+	}
+	if transition.Notes != nil {
+		offer.Notes = *transition.Notes
+	}
+	offer.Status = transition.Status
+	if err := s.repo.Update(ctx, offer); err != nil {
+		return nil, err
+	}
+	offer.Expired = offerExpired(offer, now)
+	publishEvent(ctx, s.events, offerEventTypes[offer.Status], offerSubject(offer.ID), &models.OfferActivity{Offer: *offer, Property: *property})
+	return offer, nil
+}
+
+// validate checks a new offer's amount, buyer, contingencies and expiry.
+// Contingencies are deduplicated.
+func (s *OfferService) validate(offer *models.Offer) error {
+	if offer.Amount <= 0 {
+		return apperrors.Validation("amount must be greater than 0")
+	}
+	offer.BuyerName = strings.TrimSpace(offer.BuyerName)
+	if offer.BuyerName == "" {
+		return apperrors.Validation("buyer_name is required")
+	}
+	offer.BuyerEmail = strings.TrimSpace(offer.BuyerEmail)
+	if offer.BuyerEmail != "" {
+		if _, err := mail.ParseAddress(offer.BuyerEmail); err != nil {
+			return apperrors.Validation("buyer_email must be a valid email")
+		}
+	}
+	offer.BuyerPhone = strings.TrimSpace(offer.BuyerPhone)
+
+	contingencies := models.StringList{}
+	for _, contingency := range offer.Contingencies {
+		if !models.IsValidContingency(contingency) {
+			return apperrors.Validationf("unknown contingency %q", contingency)
+		}
+		if !slices.Contains(contingencies, contingency) {
+			contingencies = append(contingencies, contingency)
+		}
+	}
+	offer.Contingencies = contingencies
+
+	if offer.ExpiresAt.Valid {
+		if !offer.ExpiresAt.Time.After(s.now()) {
+			return apperrors.Validation("expires_at must be in the future")
+		}
+		offer.ExpiresAt.Time = offer.ExpiresAt.Time.UTC()
+	}
+	return nil
+}
+
+// offerExpired reports whether an open offer is past its expiry
+func offerExpired(offer *models.Offer, now time.Time) bool {
+	return offer.IsOpen() && offer.ExpiresAt.Valid && !offer.ExpiresAt.Time.After(now)
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestOfferService_Create(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiry := models.NullTime{NullTime: sql.NullTime{Time: now.Add(48 * time.Hour), Valid: true}}
+	past := models.NullTime{NullTime: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}}
+
+	tests := []struct {
+		name        string
+		property    models.Property
+		offer       models.Offer
+		expectError bool
+	}{
+		{name: "offer with contingencies", property: models.Property{ID: 12, Status: models.PropertyStatusActive},
+			offer: models.Offer{Amount: 395000, BuyerName: " Jane Roe ", BuyerEmail: "jane@example.com", ExpiresAt: expiry,
+				Contingencies: models.StringList{"financing", "inspection", "financing"}}},
+		{name: "backup offer on a pending listing", property: models.Property{ID: 12, Status: models.PropertyStatusPending},
+			offer: models.Offer{Amount: 380000, BuyerName: "Jane Roe"}},
+		{name: "sold listing", property: models.Property{ID: 12, Status: models.PropertyStatusSold},
+			offer: models.Offer{Amount: 395000, BuyerName: "Jane Roe"}, expectError: true},
+		{name: "rental", property: models.Property{ID: 12, ListingType: models.ListingTypeRent},
+			offer: models.Offer{Amount: 395000, BuyerName: "Jane Roe"}, expectError: true},
+		{name: "no amount", property: models.Property{ID: 12}, offer: models.Offer{BuyerName: "Jane Roe"}, expectError: true},
+		{name: "no buyer", property: models.Property{ID: 12}, offer: models.Offer{Amount: 395000}, expectError: true},
+		{name: "invalid email", property: models.Property{ID: 12},
+			offer: models.Offer{Amount: 395000, BuyerName: "Jane Roe", BuyerEmail: "jane"}, expectError: true},
+		{name: "unknown contingency", property: models.Property{ID: 12},
+			offer: models.Offer{Amount: 395000, BuyerName: "Jane Roe", Contingencies: models.StringList{"weather"}}, expectError: true},
+		{name: "already expired", property: models.Property{ID: 12},
+			offer: models.Offer{Amount: 395000, BuyerName: "Jane Roe", ExpiresAt: past}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			property := tt.property
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&property, nil)
+			mockRepo := mocks.NewMockOfferRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, offer *models.Offer) error {
+					offer.ID = 5
+					return nil
+				})
+			}
+
+			publisher := &recordingPublisher{}
+			service := NewOfferService(mockRepo, NewPropertyService(mockProperties), publisher)
+			service.now = func() time.Time { return now }
+			offer := tt.offer
+			err := service.Create(WithActor(context.Background(), 4), 12, &offer)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				if len(publisher.events) != 0 {
+					t.Errorf("Expected no events, got %+v", publisher.events)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if offer.ID != 5 || offer.Status != models.OfferSubmitted || offer.BuyerName != "Jane Roe" || offer.CreatedBy.Int32 != 4 {
+				t.Errorf("Unexpected offer %+v", offer)
+			}
+			if len(offer.Contingencies) == 3 {
+				t.Errorf("Expected contingencies to be deduplicated, got %v", offer.Contingencies)
+			}
+			if len(publisher.events) != 1 || publisher.events[0].Type != events.OfferSubmitted || publisher.events[0].Subject != "offer/5" {
+				t.Errorf("Unexpected events %+v", publisher.events)
+			}
+		})
+	}
+}
+
+func TestOfferService_Transition(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expired := models.NullTime{NullTime: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}}
+	counter := models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 405000, Valid: true}}
+
+	tests := []struct {
+		name         string
+		offer        models.Offer
+		others       []models.Offer
+		transition   models.OfferTransition
+		expectErr    error
+		expectAmount float64
+	}{
+		{name: "countered", offer: models.Offer{Status: models.OfferSubmitted},
+			transition: models.OfferTransition{Status: models.OfferCountered, CounterAmount: 405000}, expectAmount: 395000},
+		{name: "counter without an amount", offer: models.Offer{Status: models.OfferSubmitted},
+			transition: models.OfferTransition{Status: models.OfferCountered}, expectErr: apperrors.ErrValidation},
+		{name: "buyer answers a counter", offer: models.Offer{Status: models.OfferCountered, CounterAmount: counter},
+			transition: models.OfferTransition{Status: models.OfferSubmitted, Amount: 400000}, expectAmount: 400000},
+		{name: "counter accepted at its amount", offer: models.Offer{Status: models.OfferCountered, CounterAmount: counter},
+			transition: models.OfferTransition{Status: models.OfferAccepted}, expectAmount: 405000},
+		{name: "accepted", offer: models.Offer{Status: models.OfferSubmitted},
+			others:     []models.Offer{{ID: 6, Status: models.OfferRejected}},
+			transition: models.OfferTransition{Status: models.OfferAccepted}, expectAmount: 395000},
+		{name: "another offer was accepted", offer: models.Offer{Status: models.OfferSubmitted},
+			others:     []models.Offer{{ID: 6, Status: models.OfferAccepted}},
+			transition: models.OfferTransition{Status: models.OfferAccepted}, expectErr: apperrors.ErrConflict},
+		{name: "submitted again without a counter", offer: models.Offer{Status: models.OfferSubmitted},
+			transition: models.OfferTransition{Status: models.OfferSubmitted, Amount: 400000}, expectErr: apperrors.ErrValidation},
+		{name: "already rejected", offer: models.Offer{Status: models.OfferRejected},
+			transition: models.OfferTransition{Status: models.OfferAccepted}, expectErr: apperrors.ErrConflict},
+		{name: "expired offer accepted", offer: models.Offer{Status: models.OfferSubmitted, ExpiresAt: expired},
+			transition: models.OfferTransition{Status: models.OfferAccepted}, expectErr: apperrors.ErrValidation},
+		{name: "expired offer rejected", offer: models.Offer{Status: models.OfferSubmitted, ExpiresAt: expired},
+			transition: models.OfferTransition{Status: models.OfferRejected}, expectAmount: 395000},
+		{name: "unknown status", offer: models.Offer{Status: models.OfferSubmitted},
+			transition: models.OfferTransition{Status: "withdrawn"}, expectErr: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			offer := tt.offer
+			offer.ID, offer.PropertyID, offer.Amount = 5, 12, 395000
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+			mockRepo := mocks.NewMockOfferRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 5).Return(&offer, nil)
+			mockRepo.EXPECT().ListByProperty(gomock.Any(), 12).Return(append([]models.Offer{offer}, tt.others...), nil).AnyTimes()
+			if tt.expectErr == nil {
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			publisher := &recordingPublisher{}
+			service := NewOfferService(mockRepo, NewPropertyService(mockProperties), publisher)
+			service.now = func() time.Time { return now }
+			updated, err := service.Transition(context.Background(), 5, tt.transition)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if updated.Status != tt.transition.Status || updated.Amount != tt.expectAmount {
+				t.Errorf("Unexpected offer %+v", updated)
+			}
+			if updated.RespondedAt.Valid != (tt.transition.Status != models.OfferSubmitted) {
+				t.Errorf("Expected only the seller's answers to be stamped, got %+v", updated.RespondedAt)
+			}
+			if len(publisher.events) != 1 || publisher.events[0].Type != "offer."+tt.transition.Status {
+				t.Errorf("Unexpected events %+v", publisher.events)
+			}
+		})
+	}
+}
+
+func TestOfferService_Dashboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) models.NullTime {
+		return models.NullTime{NullTime: sql.NullTime{Time: now.Add(time.Duration(hours) * time.Hour), Valid: true}}
+	}
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+	mockRepo := mocks.NewMockOfferRepository(ctrl)
+	mockRepo.EXPECT().ListByProperty(gomock.Any(), 12).Return([]models.Offer{
+		{ID: 1, Status: models.OfferSubmitted, Amount: 390000, ExpiresAt: at(24)},
+		{ID: 2, Status: models.OfferCountered, Amount: 385000, ExpiresAt: at(6)},
+		{ID: 3, Status: models.OfferSubmitted, Amount: 420000, ExpiresAt: at(-1)},
+		{ID: 4, Status: models.OfferRejected, Amount: 350000},
+	}, nil)
+
+	service := NewOfferService(mockRepo, NewPropertyService(mockProperties), nil)
+	service.now = func() time.Time { return now }
+	dashboard, err := service.Dashboard(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The expired offer is neither open nor the highest
+	if dashboard.Open != 2 || dashboard.Expired != 1 || *dashboard.HighestOpen != 390000 {
+		t.Errorf("Unexpected dashboard %+v", dashboard)
+	}
+	if !dashboard.NextExpiry.Equal(at(6).Time) {
+		t.Errorf("Expected the counter's expiry next, got %v", dashboard.NextExpiry)
+	}
+	if dashboard.Counts[models.OfferSubmitted] != 2 || dashboard.Counts[models.OfferAccepted] != 0 || !dashboard.Offers[2].Expired {
+		t.Errorf("Unexpected counts %v or offers %+v", dashboard.Counts, dashboard.Offers)
+	}
+}
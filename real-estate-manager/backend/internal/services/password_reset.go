@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mailer"
+	"real-estate-manager/backend/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinPasswordLength is the shortest password a reset accepts
+	MinPasswordLength = 8
+	// bcrypt only hashes the first 72 bytes of a password
+	maxPasswordBytes = 72
+)
+
+// PasswordResetService lets users who forgot their password set a new one
+// through a single-use link emailed to their account's address
+type PasswordResetService struct {
+	auth     *AuthService
+	users    repository.UserRepository
+	resets   repository.EmailTokenRepository
+	flow     *emailTokenFlow
+	resetURL string
+}
+
+// NewPasswordResetService creates the service. resetURL is the page users
+// choose their new password on, with {token} standing for the token, e.g.
+// https://app.example.com/reset-password?token={token}
+func NewPasswordResetService(auth *AuthService, users repository.UserRepository, resets repository.EmailTokenRepository,
+	m mailer.Mailer, settings SettingsProvider, resetURL string) *PasswordResetService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &PasswordResetService{
+		auth:   auth,
+		users:  users,
+		resets: resets,
+		flow: &emailTokenFlow{
+			users:    users,
+			tokens:   resets,
+			mailer:   m,
+			template: "password_reset",
+			ttl:      func() time.Duration { return settings.GetDuration(SettingPasswordResetTTL) },
+			limiter: newWindowLimiter(time.Hour, func() int {
+				return settings.GetInt(SettingPasswordResetRate)
+			}),
+			limited: "too many password resets requested for this email; try again later",
+			invalid: "invalid or expired password reset link",
+			now:     time.Now,
+		},
+		resetURL: resetURL,
+	}
+}
+
+// RequestReset emails a reset link to the account registered with email.
+// Unknown addresses succeed silently so the endpoint cannot be used to
+// discover accounts.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	return s.flow.send(ctx, email, func(token string) string {
+		return strings.ReplaceAll(s.resetURL, "{token}", url.QueryEscape(token))
+	})
+}
+
+// ResetPassword sets the password of the user a reset token was sent to.
+// The token and any others the user was sent stop working, and so do the
+// user's login tokens, so whoever knew the old password is signed out.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, password string) error {
+	if token == "" {
+		return apperrors.Validation("token is required")
+	}
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	user, err := s.flow.redeem(ctx, token)
+	if err != nil {
+		return err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.auth.RevokeUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.resets.InvalidateUser(ctx, user.ID, s.flow.now())
+}
+
+func validatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return apperrors.Validationf("password must be at least %d characters", MinPasswordLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return apperrors.Validationf("password must be at most %d bytes", maxPasswordBytes)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordResetService_RequestReset(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		setupMock   func(users *mocks.MockUserRepository, resets *mocks.MockEmailTokenRepository)
+		expectSent  int
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:  "known email gets a link",
+			email: " Alice@Example.com ",
+			setupMock: func(users *mocks.MockUserRepository, resets *mocks.MockEmailTokenRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(&models.User{ID: 7, Username: "alice", Email: "alice@example.com"}, nil)
+				resets.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, reset *models.EmailToken) error {
+					if reset.UserID != 7 || len(reset.TokenHash) != 64 {
+						t.Errorf("Unexpected reset: %+v", reset)
+					}
+					return nil
+				})
+			},
+			expectSent: 1,
+		},
+		{
+			name:  "unknown email succeeds silently",
+			email: "nobody@example.com",
+			setupMock: func(users *mocks.MockUserRepository, resets *mocks.MockEmailTokenRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(nil, sql.ErrNoRows)
+			},
+		},
+		{
+			name:        "invalid email",
+			email:       "not-an-email",
+			setupMock:   func(*mocks.MockUserRepository, *mocks.MockEmailTokenRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockResets := mocks.NewMockEmailTokenRepository(ctrl)
+			tt.setupMock(mockUsers, mockResets)
+
+			m := &recordingMailer{}
+			service := NewPasswordResetService(NewAuthServiceWithSecret(mockUsers, "secret"), mockUsers, mockResets, m, staticSettings{},
+				"https://app.example.com/reset-password?token={token}")
+			err := service.RequestReset(context.Background(), tt.email)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(m.sent) != tt.expectSent {
+				t.Fatalf("Expected %d emails, got %d", tt.expectSent, len(m.sent))
+			}
+			if tt.expectSent > 0 && !strings.Contains(m.sent[0].Body, "https://app.example.com/reset-password?token=") {
+				t.Errorf("Expected reset link in body, got %q", m.sent[0].Body)
+			}
+		})
+	}
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		password   string
+		setupMock  func(users *mocks.MockUserRepository, resets *mocks.MockEmailTokenRepository, revoked *mocks.MockRevokedTokenRepository)
+		expectKind error
+	}{
+		{
+			name:     "valid token",
+			token:    "abc",
+			password: "correct horse",
+			setupMock: func(users *mocks.MockUserRepository, resets *mocks.MockEmailTokenRepository, revoked *mocks.MockRevokedTokenRepository) {
+				resets.EXPECT().Consume(gomock.Any(), hashToken("abc"), gomock.Any()).Return(&models.EmailToken{UserID: 7}, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, Username: "alice", Password: "old"}, nil)
+				users.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *models.User) error {
+					if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("correct horse")) != nil {
+						t.Errorf("Expected the new password to be hashed, got %q", user.Password)
+					}
+					return nil
+				})
+				revoked.EXPECT().RevokeUser(gomock.Any(), uint(7), gomock.Any()).Return(nil)
+				resets.EXPECT().InvalidateUser(gomock.Any(), uint(7), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "used or expired token",
+			token:    "abc",
+			password: "correct horse",
+			setupMock: func(users *mocks.MockUserRepository, resets *mocks.MockEmailTokenRepository, revoked *mocks.MockRevokedTokenRepository) {
+				resets.EXPECT().Consume(gomock.Any(), hashToken("abc"), gomock.Any()).Return(nil, nil)
+			},
+			expectKind: apperrors.ErrUnauthorized,
+		},
+		{
+			name:       "short password keeps the token",
+			token:      "abc",
+			password:   "short",
+			setupMock:  func(*mocks.MockUserRepository, *mocks.MockEmailTokenRepository, *mocks.MockRevokedTokenRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:       "missing token",
+			password:   "correct horse",
+			setupMock:  func(*mocks.MockUserRepository, *mocks.MockEmailTokenRepository, *mocks.MockRevokedTokenRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockResets := mocks.NewMockEmailTokenRepository(ctrl)
+			mockRevoked := mocks.NewMockRevokedTokenRepository(ctrl)
+			tt.setupMock(mockUsers, mockResets, mockRevoked)
+
+			auth := NewAuthServiceWithSecret(mockUsers, "secret", WithTokenBlacklist(NewTokenBlacklist(mockRevoked)))
+			service := NewPasswordResetService(auth, mockUsers, mockResets, &recordingMailer{}, staticSettings{}, "")
+			err := service.ResetPassword(context.Background(), tt.token, tt.password)
+			if tt.expectKind != nil {
+				if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Permissions are resource:action grants. A role may also hold "*" (all
+// permissions) or "resource:*" (every action on a resource).
+const (
+	PermPropertiesRead       = "properties:read"
+	PermPropertiesCreate     = "properties:create"
+	PermPropertiesUpdate     = "properties:update"
+	PermPropertiesDelete     = "properties:delete"
+	PermPropertiesBulkUpdate = "properties:bulk_update"
+	PermPropertiesSyndicate  = "properties:syndicate"
+	PermPropertiesExport     = "properties:export"
+	PermJobsRead             = "jobs:read"
+	PermJobsRun              = "jobs:run"
+	PermJobsCancel           = "jobs:cancel"
+	PermDealsRead            = "deals:read"
+	PermDealsWrite           = "deals:write"
+	PermInspectionsRead      = "inspections:read"
+	PermInspectionsWrite     = "inspections:write"
+	PermContactsRead         = "contacts:read"
+	PermContactsWrite        = "contacts:write"
+)
+
+var knownPermissions = map[string]string{
+	PermPropertiesRead:       "View listings and their details",
+	PermPropertiesCreate:     "Create listings",
+	PermPropertiesUpdate:     "Edit listings, amenities and photos, and revert revisions",
+	PermPropertiesDelete:     "Delete listings",
+	PermPropertiesBulkUpdate: "Update many listings at once",
+	PermPropertiesSyndicate:  "Publish listings to third-party portals",
+	PermPropertiesExport:     "Export every listing in bulk",
+	PermJobsRead:             "View import job status",
+	PermJobsRun:              "Start SimplyRETS imports",
+	PermJobsCancel:           "Cancel import jobs",
+	PermDealsRead:            "View deals, offers, signature requests, the pipeline and revenue reports",
+	PermDealsWrite:           "Create, update and delete deals and their commission splits, take offers and send documents for signature",
+	PermInspectionsRead:      "View inspections, their reports and repair punch lists",
+	PermInspectionsWrite:     "Schedule inspections, attach reports and track repairs",
+	PermContactsRead:         "View the contact book and contacts' links to leads, showings and deals",
+	PermContactsWrite:        "Add, edit and delete contacts and link them to leads, showings and deals",
+}
+
+const (
+	RoleViewer    = "viewer"
+	allPermission = "*"
+)
+
+// builtInRoles are the global defaults; stored mappings override them
+var builtInRoles = map[string][]string{
+	models.RoleAdmin: {allPermission},
+	models.RoleUser: {
+		PermPropertiesRead, PermPropertiesCreate, PermPropertiesUpdate, PermPropertiesDelete,
+		PermPropertiesBulkUpdate, PermPropertiesSyndicate, PermPropertiesExport, PermJobsRead, PermJobsRun, PermJobsCancel,
+		PermDealsRead, PermDealsWrite, PermInspectionsRead, PermInspectionsWrite, PermContactsRead, PermContactsWrite,
+	},
+	RoleViewer: {PermPropertiesRead, PermJobsRead, PermDealsRead, PermInspectionsRead, PermContactsRead},
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,19}$`)
+
+// Authorizer checks whether the caller in ctx holds a permission
+type Authorizer interface {
+	Authorize(ctx context.Context, permission string) error
+}
+
+type roleKey struct {
+	role           string
+	organizationID int
+}
+
+// PermissionService resolves role→permission mappings. Mappings are kept in
+// memory and changed through the admin API.
+type PermissionService struct {
+	repo  repository.RolePermissionRepository
+	users repository.UserRepository
+	mu    sync.RWMutex
+	roles map[roleKey]models.RolePermissions
+}
+
+func NewPermissionService(repo repository.RolePermissionRepository, users repository.UserRepository) *PermissionService {
+	roles := make(map[roleKey]models.RolePermissions, len(builtInRoles))
+	for name, permissions := range builtInRoles {
+		roles[roleKey{role: name}] = models.RolePermissions{Role: name, Permissions: permissions, BuiltIn: true}
+	}
+	return &PermissionService{repo: repo, users: users, roles: roles}
+}
+
+// Load applies the mappings stored in the database on top of the built-in
+// roles
+func (s *PermissionService) Load(ctx context.Context) error {
+	stored, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, role := range stored {
+		if role.Role == models.RoleAdmin && role.OrganizationID == 0 {
+			continue
+		}
+		role.BuiltIn = isBuiltIn(role.Role, role.OrganizationID)
+		s.roles[roleKey{role.Role, role.OrganizationID}] = role
+	}
+	return nil
+}
+
+// Allowed reports whether role grants permission. A role defined for the
+// user's organization takes precedence over the global role of that name.
+func (s *PermissionService) Allowed(role string, organizationID int, permission string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mapping, ok := s.roles[roleKey{role, organizationID}]
+	if !ok {
+		mapping, ok = s.roles[roleKey{role: role}]
+	}
+	if !ok {
+		return false
+	}
+	return grants(mapping.Permissions, permission)
+}
+
+// Authorize returns apperrors.ErrForbidden unless the principal in ctx holds
+// permission. Calls without a principal, such as scheduled jobs, are allowed.
+func (s *PermissionService) Authorize(ctx context.Context, permission string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if !s.Allowed(principal.Role, principal.OrganizationID, permission) {
+		return apperrors.Forbiddenf("missing permission %s", permission)
+	}
+	if principal.Scopes != nil && !ScopesAllow(principal.Scopes, permission) {
+		return apperrors.Forbiddenf("token scope does not allow %s", permission)
+	}
+	return nil
+}
+
+// RoleExists reports whether role is defined globally or for organizationID
+func (s *PermissionService) RoleExists(role string, organizationID int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, global := s.roles[roleKey{role: role}]
+	_, scoped := s.roles[roleKey{role, organizationID}]
+	return global || scoped
+}
+
+// List returns all role mappings, global roles first
+func (s *PermissionService) List() []models.RolePermissions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roles := make([]models.RolePermissions, 0, len(s.roles))
+	for _, role := range s.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].OrganizationID != roles[j].OrganizationID {
+			return roles[i].OrganizationID < roles[j].OrganizationID
+		}
+		return roles[i].Role < roles[j].Role
+	})
+	return roles
+}
+
+// Permissions lists the known permissions and their descriptions
+func (s *PermissionService) Permissions() map[string]string {
+	return knownPermissions
+}
+
+// SetRole creates or replaces a role's permissions
+func (s *PermissionService) SetRole(ctx context.Context, role string, organizationID int, permissions []string, updatedBy string) (*models.RolePermissions, error) {
+	if !roleNamePattern.MatchString(role) {
+		return nil, apperrors.Validation("role must be 2-20 lowercase letters, digits, '-' or '_'")
+	}
+	if role == models.RoleAdmin && organizationID == 0 {
+		return nil, apperrors.Validation("the global admin role always has every permission")
+	}
+	if organizationID < 0 {
+		return nil, apperrors.Validation("organization_id must not be negative")
+	}
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	mapping := models.RolePermissions{
+		Role:           role,
+		OrganizationID: organizationID,
+		Permissions:    normalizePermissions(permissions),
+		BuiltIn:        isBuiltIn(role, organizationID),
+		UpdatedBy:      models.NullString{NullString: sql.NullString{String: updatedBy, Valid: updatedBy != ""}},
+		UpdatedAt:      time.Now(),
+	}
+	if err := s.repo.Upsert(ctx, &mapping); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.roles[roleKey{role, organizationID}] = mapping
+	s.mu.Unlock()
+	return &mapping, nil
+}
+
+// DeleteRole removes a stored mapping. Built-in roles revert to their
+// defaults rather than disappearing.
+func (s *PermissionService) DeleteRole(ctx context.Context, role string, organizationID int) error {
+	key := roleKey{role, organizationID}
+
+	s.mu.RLock()
+	_, exists := s.roles[key]
+	s.mu.RUnlock()
+	if !exists {
+		return apperrors.NotFound("role not found")
+	}
+
+	if err := s.repo.Delete(ctx, role, organizationID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if permissions, builtIn := builtInRoles[role]; builtIn && organizationID == 0 {
+		s.roles[key] = models.RolePermissions{Role: role, Permissions: permissions, BuiltIn: true}
+	} else {
+		delete(s.roles, key)
+	}
+	return nil
+}
+
+// AssignRole changes a user's role. The role must exist globally or for the
+// user's organization; it takes effect the next time the user logs in.
+func (s *PermissionService) AssignRole(ctx context.Context, userID uint, role string) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	organizationID := 0
+	if user.OrganizationID.Valid {
+		organizationID = int(user.OrganizationID.Int32)
+	}
+	if !s.RoleExists(role, organizationID) {
+		return nil, apperrors.Validationf("unknown role %q", role)
+	}
+
+	if err := s.users.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	user.Password = ""
+	return user, nil
+}
+
+func isBuiltIn(role string, organizationID int) bool {
+	_, builtIn := builtInRoles[role]
+	return builtIn && organizationID == 0
+}
+
+func grants(granted []string, permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, g := range granted {
+		if g == allPermission || g == permission || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if p == allPermission {
+			continue
+		}
+		if _, known := knownPermissions[p]; known {
+			continue
+		}
+		if resource, action, _ := strings.Cut(p, ":"); action == "*" && knownResource(resource) {
+			continue
+		}
+		return apperrors.Validationf("unknown permission %q", p)
+	}
+	return nil
+}
+
+func knownResource(resource string) bool {
+	for p := range knownPermissions {
+		if strings.HasPrefix(p, resource+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePermissions(permissions []string) models.StringList {
+	seen := make(map[string]bool, len(permissions))
+	normalized := make(models.StringList, 0, len(permissions))
+	for _, p := range permissions {
+		if !seen[p] {
+			seen[p] = true
+			normalized = append(normalized, p)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPermissionService_Allowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRolePermissionRepository(ctrl)
+	mockRepo.EXPECT().GetAll(gomock.Any()).Return([]models.RolePermissions{
+		{Role: "coordinator", Permissions: models.StringList{"properties:*"}},
+		{Role: models.RoleUser, OrganizationID: 3, Permissions: models.StringList{PermPropertiesRead}},
+		{Role: models.RoleAdmin, Permissions: models.StringList{PermPropertiesRead}},
+	}, nil)
+
+	service := NewPermissionService(mockRepo, mocks.NewMockUserRepository(ctrl))
+	if err := service.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		role           string
+		organizationID int
+		permission     string
+		expected       bool
+	}{
+		{name: "admin has everything", role: models.RoleAdmin, permission: PermJobsRun, expected: true},
+		{name: "built-in user", role: models.RoleUser, permission: PermPropertiesDelete, expected: true},
+		{name: "viewer is read only", role: RoleViewer, permission: PermPropertiesUpdate, expected: false},
+		{name: "resource wildcard", role: "coordinator", permission: PermPropertiesBulkUpdate, expected: true},
+		{name: "resource wildcard is scoped", role: "coordinator", permission: PermJobsRun, expected: false},
+		{name: "organization override", role: models.RoleUser, organizationID: 3, permission: PermPropertiesDelete, expected: false},
+		{name: "other organizations use global role", role: models.RoleUser, organizationID: 4, permission: PermPropertiesDelete, expected: true},
+		{name: "unknown role", role: "intern", permission: PermPropertiesRead, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.Allowed(tt.role, tt.organizationID, tt.permission); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPermissionService_Authorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewPermissionService(mocks.NewMockRolePermissionRepository(ctrl), mocks.NewMockUserRepository(ctrl))
+
+	if err := service.Authorize(context.Background(), PermPropertiesDelete); err != nil {
+		t.Errorf("Expected calls without a principal to be allowed, got %v", err)
+	}
+
+	ctx := WithPrincipal(context.Background(), Principal{Role: RoleViewer})
+	if err := service.Authorize(ctx, PermPropertiesDelete); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden, got %v", err)
+	}
+}
+
+func TestPermissionService_SetRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           string
+		organizationID int
+		permissions    []string
+		setupMock      func(mock *mocks.MockRolePermissionRepository)
+		expectKind     error
+		expectError    bool
+	}{
+		{
+			name:           "custom brokerage role",
+			role:           "office-manager",
+			organizationID: 3,
+			permissions:    []string{PermPropertiesRead, "jobs:*", PermPropertiesRead},
+			setupMock: func(mock *mocks.MockRolePermissionRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, role *models.RolePermissions) error {
+					if len(role.Permissions) != 2 {
+						t.Errorf("Expected duplicates removed, got %v", role.Permissions)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:        "unknown permission",
+			role:        "auditor",
+			permissions: []string{"billing:read"},
+			setupMock:   func(mock *mocks.MockRolePermissionRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "global admin is immutable",
+			role:        models.RoleAdmin,
+			permissions: []string{PermPropertiesRead},
+			setupMock:   func(mock *mocks.MockRolePermissionRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "invalid role name",
+			role:        "Office Manager",
+			permissions: []string{PermPropertiesRead},
+			setupMock:   func(mock *mocks.MockRolePermissionRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockRolePermissionRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewPermissionService(mockRepo, mocks.NewMockUserRepository(ctrl))
+			_, err := service.SetRole(context.Background(), tt.role, tt.organizationID, tt.permissions, "admin")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !service.RoleExists(tt.role, tt.organizationID) {
+				t.Error("Expected role to exist after SetRole")
+			}
+		})
+	}
+}
+
+func TestPermissionService_DeleteBuiltInRoleRestoresDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRolePermissionRepository(ctrl)
+	mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), RoleViewer, 0).Return(nil)
+
+	service := NewPermissionService(mockRepo, mocks.NewMockUserRepository(ctrl))
+	if _, err := service.SetRole(context.Background(), RoleViewer, 0, []string{PermPropertiesUpdate}, "admin"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.DeleteRole(context.Background(), RoleViewer, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if service.Allowed(RoleViewer, 0, PermPropertiesUpdate) || !service.Allowed(RoleViewer, 0, PermPropertiesRead) {
+		t.Error("Expected viewer to revert to its built-in permissions")
+	}
+}
+
+func TestPermissionService_AssignRole(t *testing.T) {
+	org := models.NullInt32{NullInt32: sql.NullInt32{Int32: 3, Valid: true}}
+
+	tests := []struct {
+		name        string
+		role        string
+		setupMock   func(users *mocks.MockUserRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name: "role defined for the user's organization",
+			role: "office-manager",
+			setupMock: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, OrganizationID: org}, nil)
+				users.EXPECT().UpdateRole(gomock.Any(), uint(7), "office-manager").Return(nil)
+			},
+		},
+		{
+			name: "role from another organization",
+			role: "office-manager",
+			setupMock: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7}, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name: "unknown user",
+			role: RoleViewer,
+			setupMock: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetByID(gomock.Any(), uint(7)).Return(nil, sql.ErrNoRows)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockRolePermissionRepository(ctrl)
+			mockRepo.EXPECT().GetAll(gomock.Any()).Return([]models.RolePermissions{
+				{Role: "office-manager", OrganizationID: 3, Permissions: models.StringList{PermPropertiesRead}},
+			}, nil)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			tt.setupMock(mockUsers)
+
+			service := NewPermissionService(mockRepo, mockUsers)
+			if err := service.Load(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			user, err := service.AssignRole(context.Background(), 7, tt.role)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if user.Role != tt.role {
+				t.Errorf("Expected role %s, got %s", tt.role, user.Role)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// photoBackfillAttempts bounds how often attaching downloaded photos is
+// retried when the listing is edited at the same time
+const photoBackfillAttempts = 3
+
+type lazyPhotosKey struct{}
+
+// withLazyPhotos marks a job whose listings are saved before their photos
+// are downloaded
+func withLazyPhotos(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lazyPhotosKey{}, true)
+}
+
+func lazyPhotosFromContext(ctx context.Context) bool {
+	lazy, _ := ctx.Value(lazyPhotosKey{}).(bool)
+	return lazy
+}
+
+// remotePhotos lists a listing's photos by their provider URLs only
+func remotePhotos(imageURLs []string) models.PhotoList {
+	photos := make(models.PhotoList, len(imageURLs))
+	for i, url := range imageURLs {
+		photos[i] = models.Photo{URL: url, Caption: fmt.Sprintf("Property image %d", i+1)}
+	}
+	return photos
+}
+
+// queuePhotoBackfill queues the download of a saved listing's photos. The
+// downloads outlive the job, so cancelling it does not leave listings
+// without photos. When the queue is full they run right away instead,
+// slowing the job rather than dropping them.
+func (s *SimplyRETSService) queuePhotoBackfill(ctx context.Context, propertyID int, simplyProperty models.SimplyRETSProperty) {
+	ctx = context.WithoutCancel(ctx)
+	task := func(context.Context) error {
+		return s.backfillPhotos(ctx, propertyID, simplyProperty.ListingID, simplyProperty.Photos)
+	}
+	if err := s.backfill.Submit(task); err != nil {
+		log.Printf("Photo backfill queue unavailable (%v), downloading photos of property %d now", err, propertyID)
+		if err := task(ctx); err != nil {
+			log.Printf("Photo backfill failed: %v", err)
+		}
+	}
+}
+
+// backfillPhotos downloads a listing's photos and attaches the local copies
+// to the saved property. Photos that fail to download keep their provider
+// URL.
+func (s *SimplyRETSService) backfillPhotos(ctx context.Context, propertyID int, listingID string, imageURLs []string) error {
+	downloaded, downloadErr := s.downloadImages(ctx, imageURLs, listingID)
+	local := make(map[string]models.Photo, len(downloaded))
+	for _, photo := range downloaded {
+		local[photo.URL] = photo
+	}
+
+	for attempt := 1; ; attempt++ {
+		property, err := s.propertyRepo.GetByID(ctx, propertyID)
+		if err != nil {
+			return fmt.Errorf("failed to get property %d: %w", propertyID, err)
+		}
+		// Deleted since it was imported
+		if property == nil {
+			return nil
+		}
+		changed := false
+		for i, photo := range property.Photos {
+			if stored, ok := local[photo.URL]; ok && photo.LocalURL == "" {
+				property.Photos[i].LocalURL = stored.LocalURL
+				property.Photos[i].ThumbnailURL, property.Photos[i].MediumURL = stored.ThumbnailURL, stored.MediumURL
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		err = s.propertyRepo.Update(ctx, property)
+		if errors.Is(err, repository.ErrVersionConflict) && attempt < photoBackfillAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to attach photos to property %d: %w", propertyID, err)
+		}
+		publishEvent(ctx, s.events, events.PropertyUpdated, propertySubject(property.ID), property)
+		break
+	}
+
+	if downloadErr != nil {
+		return fmt.Errorf("failed to download images for property %s: %w", listingID, downloadErr)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// photoVersionLength is how many hex digits of a photo's content hash its
+// CDN URL carries
+const photoVersionLength = 12
+
+// PhotoCDN rewrites the local URLs of photos, under /images, to URLs on a
+// CDN in API responses. Each URL carries a hash of the photo's content, as
+// ?v=, so a photo replaced under the same name is fetched again instead of
+// served stale from the CDN's cache. Stored photos keep their local URLs;
+// only responses change.
+type PhotoCDN struct {
+	baseURL   string
+	imagesDir string
+
+	mu       sync.Mutex
+	versions map[string]photoVersion
+}
+
+// photoVersion is a photo file's content hash, valid while the file keeps
+// the same size and modification time
+type photoVersion struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// NewPhotoCDN creates the rewriter; an empty baseURL leaves URLs alone
+func NewPhotoCDN(baseURL, imagesDir string) (*PhotoCDN, error) {
+	if baseURL != "" {
+		parsed, err := url.Parse(baseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("CDN base URL %q must be an absolute http or https URL", baseURL)
+		}
+	}
+	return &PhotoCDN{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		imagesDir: imagesDir,
+		versions:  make(map[string]photoVersion),
+	}, nil
+}
+
+// Enabled reports whether photo URLs are rewritten
+func (c *PhotoCDN) Enabled() bool {
+	return c != nil && c.baseURL != ""
+}
+
+// Rewrite points the property's photos at the CDN. Photos are copied, not
+// changed in place, so a cached property keeps its local URLs.
+func (c *PhotoCDN) Rewrite(property *models.Property) {
+	if !c.Enabled() || len(property.Photos) == 0 {
+		return
+	}
+	photos := make(models.PhotoList, len(property.Photos))
+	for i, photo := range property.Photos {
+		// Uploaded photos have no other URL than their local one
+		if photo.URL == photo.LocalURL {
+			photo.URL = c.URL(photo.URL)
+		}
+		photo.LocalURL = c.URL(photo.LocalURL)
+		photo.ThumbnailURL, photo.MediumURL = c.URL(photo.ThumbnailURL), c.URL(photo.MediumURL)
+		photos[i] = photo
+	}
+	property.Photos = photos
+}
+
+// URL returns the CDN URL of a local photo URL; other URLs are returned
+// unchanged. A photo whose file cannot be read gets no version.
+func (c *PhotoCDN) URL(localURL string) string {
+	if !c.Enabled() || !strings.HasPrefix(localURL, "/images/") {
+		return localURL
+	}
+	cdnURL := c.baseURL + localURL
+	if hash := c.version(filepath.Base(localURL)); hash != "" {
+		cdnURL += "?v=" + hash
+	}
+	return cdnURL
+}
+
+// version returns the content hash of a photo file, hashing it only when
+// it is new or has changed since it was last hashed
+func (c *PhotoCDN) version(name string) string {
+	path := filepath.Join(c.imagesDir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+
+	c.mu.Lock()
+	cached, ok := c.versions[name]
+	c.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.hash
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return ""
+	}
+	hash := hex.EncodeToString(digest.Sum(nil))[:photoVersionLength]
+
+	c.mu.Lock()
+	c.versions[name] = photoVersion{size: info.Size(), modTime: info.ModTime(), hash: hash}
+	c.mu.Unlock()
+	return hash
+}
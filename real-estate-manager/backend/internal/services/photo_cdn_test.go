@@ -0,0 +1,82 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+func TestNewPhotoCDN(t *testing.T) {
+	for _, baseURL := range []string{"cdn.example.com", "ftp://cdn.example.com", "https://"} {
+		if _, err := NewPhotoCDN(baseURL, t.TempDir()); err == nil {
+			t.Errorf("Expected %q to be rejected", baseURL)
+		}
+	}
+	cdn, err := NewPhotoCDN("", t.TempDir())
+	if err != nil || cdn.Enabled() {
+		t.Errorf("Expected a disabled CDN, got %v", err)
+	}
+}
+
+func TestPhotoCDN_Rewrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "front.jpg")
+	if err := os.WriteFile(path, []byte("front"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cdn, err := NewPhotoCDN("https://cdn.example.com/", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	photos := models.PhotoList{
+		{URL: "https://mls.example.com/front.jpg", LocalURL: "/images/front.jpg"},
+		{URL: "/images/upload_1.jpg", LocalURL: "/images/upload_1.jpg"},
+		{URL: "https://mls.example.com/remote.jpg"},
+	}
+	property := &models.Property{Photos: photos}
+	cdn.Rewrite(property)
+
+	front := property.Photos[0].LocalURL
+	if !strings.HasPrefix(front, "https://cdn.example.com/images/front.jpg?v=") || len(front) != len("https://cdn.example.com/images/front.jpg?v=")+photoVersionLength {
+		t.Errorf("Unexpected CDN URL %q", front)
+	}
+	if property.Photos[0].URL != "https://mls.example.com/front.jpg" {
+		t.Errorf("Expected the source URL to stay, got %q", property.Photos[0].URL)
+	}
+	// A missing file gets no version
+	if upload := property.Photos[1]; upload.URL != "https://cdn.example.com/images/upload_1.jpg" || upload.LocalURL != upload.URL {
+		t.Errorf("Unexpected upload URLs %+v", upload)
+	}
+	if property.Photos[2].LocalURL != "" {
+		t.Errorf("Expected no local URL, got %q", property.Photos[2].LocalURL)
+	}
+	if photos[0].LocalURL != "/images/front.jpg" {
+		t.Errorf("Expected the stored photos to keep their local URLs, got %q", photos[0].LocalURL)
+	}
+
+	// Replacing the photo changes its version
+	if err := os.WriteFile(path, []byte("new front"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if updated := cdn.URL("/images/front.jpg"); updated == front {
+		t.Errorf("Expected a new version after the photo changed, got %q", updated)
+	}
+}
+
+func TestPhotoCDN_Disabled(t *testing.T) {
+	var cdn *PhotoCDN
+	property := &models.Property{Photos: models.PhotoList{{URL: "/images/a.jpg", LocalURL: "/images/a.jpg"}}}
+	cdn.Rewrite(property)
+	if property.Photos[0].LocalURL != "/images/a.jpg" {
+		t.Errorf("Expected URLs to stay without a CDN, got %q", property.Photos[0].LocalURL)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"log"
+	"path"
+	"sync/atomic"
+
+	"real-estate-manager/backend/internal/models"
+)
+
+// photoTally counts the photos a job kept from an earlier sync. Batches
+// record into it concurrently.
+type photoTally struct {
+	skipped atomic.Int64
+}
+
+type photoTallyKey struct{}
+
+func withPhotoTally(ctx context.Context, tally *photoTally) context.Context {
+	return context.WithValue(ctx, photoTallyKey{}, tally)
+}
+
+func photoTallyFromContext(ctx context.Context) *photoTally {
+	tally, _ := ctx.Value(photoTallyKey{}).(*photoTally)
+	return tally
+}
+
+func (t *photoTally) skip() {
+	if t != nil {
+		t.skipped.Add(1)
+	}
+}
+
+func (t *photoTally) count() int {
+	if t == nil {
+		return 0
+	}
+	return int(t.skipped.Load())
+}
+
+// loadPhotoManifest returns the photos earlier syncs downloaded for a
+// listing, by source URL. The manifest only saves downloads, so when it
+// cannot be read every photo is downloaded again.
+func (s *SimplyRETSService) loadPhotoManifest(ctx context.Context, listingID string) map[string]*models.PhotoManifestEntry {
+	if s.manifest == nil {
+		return nil
+	}
+	entries, err := s.manifest.ListByListing(ctx, listingID)
+	if err != nil {
+		log.Printf("Failed to load photo manifest for listing %s: %v", listingID, err)
+		return nil
+	}
+	manifest := make(map[string]*models.PhotoManifestEntry, len(entries))
+	for i := range entries {
+		manifest[entries[i].URL] = &entries[i]
+	}
+	return manifest
+}
+
+// cachedPhoto returns entry when its copy is still stored
+func (s *SimplyRETSService) cachedPhoto(ctx context.Context, entry *models.PhotoManifestEntry) *models.PhotoManifestEntry {
+	if entry == nil || entry.ETag == "" {
+		return nil
+	}
+	if _, err := s.images.Size(ctx, path.Base(entry.LocalURL)); err != nil {
+		return nil
+	}
+	return entry
+}
+
+// recordPhoto adds a downloaded photo to the manifest. Photos served
+// without an ETag cannot be checked for changes and are left out.
+func (s *SimplyRETSService) recordPhoto(ctx context.Context, entry models.PhotoManifestEntry) {
+	if s.manifest == nil || entry.ETag == "" {
+		return
+	}
+	if err := s.manifest.Upsert(ctx, &entry); err != nil {
+		log.Printf("Failed to record photo %s of listing %s: %v", entry.URL, entry.ListingID, err)
+	}
+}
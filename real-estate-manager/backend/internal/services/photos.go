@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/imagestore"
+
+	"github.com/google/uuid"
+)
+
+// MaxPhotoBytes caps a single uploaded photo
+const MaxPhotoBytes = 20 << 20
+
+var photoExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+// PhotoService stores photos uploaded for a property, once scanned for
+// viruses, and charges them to the uploader's storage quota. Uploads are
+// scanned in imagesDir before they are stored in images.
+type PhotoService struct {
+	properties *PropertyService
+	storage    *StorageService
+	scans      *VirusScanService
+	imagesDir  string
+	images     imagestore.Storage
+}
+
+// NewPhotoService stores photos in imagesDir when images is nil
+func NewPhotoService(properties *PropertyService, storage *StorageService, scans *VirusScanService, imagesDir string, images imagestore.Storage) *PhotoService {
+	if images == nil {
+		images = imagestore.NewLocal(imagesDir)
+	}
+	return &PhotoService{properties: properties, storage: storage, scans: scans, imagesDir: imagesDir, images: images}
+}
+
+// UploadPhoto saves an uploaded image and appends it to the property's
+// photos. size is the declared upload size, checked against the quota
+// before anything is written.
+func (s *PhotoService) UploadPhoto(ctx context.Context, propertyID int, filename string, size int64, content io.Reader, caption string) (*models.Property, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !photoExtensions[ext] {
+		return nil, apperrors.Validation("photo must be a JPEG, PNG or WebP image")
+	}
+	if size > MaxPhotoBytes {
+		return nil, apperrors.TooLargef("photo is %s, the limit is %s", formatMB(size), formatMB(MaxPhotoBytes))
+	}
+
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.storage.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.CheckQuota(ctx, owner, size); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("upload_%d_%s%s", propertyID, uuid.New().String(), ext)
+	path := filepath.Join(s.imagesDir, ".scan-"+name)
+	written, err := writeFile(path, io.LimitReader(content, MaxPhotoBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	// Infected photos have already been moved to quarantine
+	defer os.Remove(path)
+	if written > MaxPhotoBytes {
+		return nil, apperrors.TooLargef("photo exceeds the %s limit", formatMB(MaxPhotoBytes))
+	}
+	scanned := ScannedFile{Owner: owner, PropertyID: propertyID, Kind: models.FileKindPhoto, Filename: filename, SizeBytes: written}
+	if err := s.scans.ScanFile(ctx, scanned, path); err != nil {
+		return nil, err
+	}
+
+	if err := s.store(ctx, name, path); err != nil {
+		return nil, err
+	}
+	localURL := s.images.URL(name)
+	if err := s.storage.Record(ctx, owner, propertyID, models.FileKindPhoto, localURL, written); err != nil {
+		s.images.Delete(ctx, name)
+		return nil, err
+	}
+
+	photo := models.Photo{URL: localURL, LocalURL: localURL, Caption: caption}
+	if err := addPhoto(ctx, s.properties, property, photo); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// store copies a scanned upload at path into the image storage as name
+func (s *PhotoService) store(ctx context.Context, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open scanned photo: %w", err)
+	}
+	defer file.Close()
+	_, err = s.images.Put(ctx, name, file)
+	return err
+}
+
+// ReorderPhotos rearranges a property's photos. order lists their current
+// positions in the new order, so [2, 0, 1] moves the third photo first,
+// where it is the cover. A non-zero version is the version the positions
+// were read at; if the property changed since, it is a conflict.
+func (s *PhotoService) ReorderPhotos(ctx context.Context, propertyID int, order []int, version int) (*models.Property, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyOrder(ctx, property, order, version); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// SetCoverPhoto moves the photo at index to the front, making it the cover
+// shown in lists, flyers and syndicated listings. The other photos keep
+// their order. version works as for ReorderPhotos.
+func (s *PhotoService) SetCoverPhoto(ctx context.Context, propertyID, index, version int) (*models.Property, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(property.Photos) {
+		return nil, apperrors.NotFound("photo not found")
+	}
+
+	order := []int{index}
+	for i := range property.Photos {
+		if i != index {
+			order = append(order, i)
+		}
+	}
+	if err := s.applyOrder(ctx, property, order, version); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// UpdatePhoto edits the alt text and caption of the photo at index. Alt
+// text set to blank is generated again from the listing's details. version
+// works as for ReorderPhotos.
+func (s *PhotoService) UpdatePhoto(ctx context.Context, propertyID, index int, update models.PhotoUpdate) (*models.Property, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(property.Photos) {
+		return nil, apperrors.NotFound("photo not found")
+	}
+
+	photo := &property.Photos[index]
+	if update.AltText != nil {
+		photo.AltText = strings.TrimSpace(*update.AltText)
+	}
+	if update.Caption != nil {
+		photo.Caption = strings.TrimSpace(*update.Caption)
+	}
+	if update.Version != 0 {
+		property.Version = update.Version
+	}
+	if err := s.properties.UpdateProperty(ctx, property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// applyOrder saves property with its photos in order, a permutation of
+// their positions
+func (s *PhotoService) applyOrder(ctx context.Context, property *models.Property, order []int, version int) error {
+	if len(order) != len(property.Photos) {
+		return apperrors.Validationf("order must list each of the %d photos once", len(property.Photos))
+	}
+	photos := make(models.PhotoList, len(order))
+	placed := make([]bool, len(order))
+	for i, position := range order {
+		if position < 0 || position >= len(order) || placed[position] {
+			return apperrors.Validationf("order must list each of the %d photos once", len(property.Photos))
+		}
+		placed[position] = true
+		photos[i] = property.Photos[position]
+	}
+
+	property.Photos = photos
+	if version != 0 {
+		property.Version = version
+	}
+	return s.properties.UpdateProperty(ctx, property)
+}
+
+// addPhoto appends a stored photo to the property and saves it
+func addPhoto(ctx context.Context, properties *PropertyService, property *models.Property, photo models.Photo) error {
+	if photo.Caption == "" {
+		photo.Caption = fmt.Sprintf("Property image %d", len(property.Photos)+1)
+	}
+	property.Photos = append(property.Photos, photo)
+	return properties.UpdateProperty(ctx, property)
+}
+
+func writeFile(path string, content io.Reader) (int64, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		os.Remove(path)
+		return 0, fmt.Errorf("failed to save file: %w", err)
+	}
+	return written, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func photoProperty() *models.Property {
+	return &models.Property{ID: 1, Name: "Lake House", Location: "Austin, TX", Price: 500000, Version: 3, Photos: models.PhotoList{
+		{URL: "/images/a.jpg"}, {URL: "/images/b.jpg"}, {URL: "/images/c.jpg"},
+	}}
+}
+
+func photoURLs(photos models.PhotoList) []string {
+	urls := make([]string, len(photos))
+	for i, photo := range photos {
+		urls[i] = photo.URL
+	}
+	return urls
+}
+
+func TestPhotoService_ReorderPhotos(t *testing.T) {
+	tests := []struct {
+		name          string
+		order         []int
+		expectedURLs  []string
+		expectedError error
+	}{
+		{name: "reorder", order: []int{2, 0, 1}, expectedURLs: []string{"/images/c.jpg", "/images/a.jpg", "/images/b.jpg"}},
+		{name: "missing photo", order: []int{2, 0}, expectedError: apperrors.ErrValidation},
+		{name: "repeated photo", order: []int{0, 0, 1}, expectedError: apperrors.ErrValidation},
+		{name: "unknown position", order: []int{0, 1, 3}, expectedError: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(photoProperty(), nil)
+			if tt.expectedError == nil {
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewPhotoService(NewPropertyService(mockRepo), nil, nil, t.TempDir(), nil)
+			property, err := service.ReorderPhotos(context.Background(), 1, tt.order, 0)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if urls := photoURLs(property.Photos); !slices.Equal(urls, tt.expectedURLs) {
+				t.Errorf("Expected photos %v, got %v", tt.expectedURLs, urls)
+			}
+		})
+	}
+}
+
+func TestPhotoService_SetCoverPhoto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(photoProperty(), nil).Times(2)
+	// The version the client read is checked on save
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, property *models.Property) error {
+		if property.Version != 2 {
+			t.Errorf("Expected the client's version 2, got %d", property.Version)
+		}
+		return nil
+	})
+
+	service := NewPhotoService(NewPropertyService(mockRepo), nil, nil, t.TempDir(), nil)
+	property, err := service.SetCoverPhoto(context.Background(), 1, 2, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"/images/c.jpg", "/images/a.jpg", "/images/b.jpg"}
+	if urls := photoURLs(property.Photos); !slices.Equal(urls, expected) {
+		t.Errorf("Expected photos %v, got %v", expected, urls)
+	}
+
+	if _, err := service.SetCoverPhoto(context.Background(), 1, 3, 0); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected a missing photo to be not found, got %v", err)
+	}
+}
+
+func TestPhotoService_UpdatePhoto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(photoProperty(), nil).Times(3)
+	var saved models.PhotoList
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, property *models.Property) error {
+		saved = property.Photos
+		return nil
+	}).Times(2)
+
+	service := NewPhotoService(NewPropertyService(mockRepo), nil, nil, t.TempDir(), nil)
+	alt, caption := " Kitchen with island ", "Kitchen"
+	if _, err := service.UpdatePhoto(context.Background(), 1, 1, models.PhotoUpdate{AltText: &alt, Caption: &caption}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if saved[1].AltText != "Kitchen with island" || saved[1].Caption != "Kitchen" || saved[1].Position != 1 {
+		t.Errorf("Expected the photo's text to be edited, got %+v", saved[1])
+	}
+	// Photos without alt text get a description of the listing
+	if saved[0].AltText != "Lake House, a home in Austin, TX" {
+		t.Errorf("Expected generated alt text, got %q", saved[0].AltText)
+	}
+
+	blank := " "
+	if _, err := service.UpdatePhoto(context.Background(), 1, 1, models.PhotoUpdate{AltText: &blank}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if saved[1].AltText != "Lake House, a home in Austin, TX" {
+		t.Errorf("Expected blank alt text to be generated again, got %q", saved[1].AltText)
+	}
+
+	if _, err := service.UpdatePhoto(context.Background(), 1, 3, models.PhotoUpdate{AltText: &alt}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected a missing photo to be not found, got %v", err)
+	}
+}
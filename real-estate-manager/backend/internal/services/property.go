@@ -2,48 +2,569 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
 	"real-estate-manager/backend/internal/models"
 	"real-estate-manager/backend/internal/repository"
 )
 
 type PropertyService struct {
-	repo repository.PropertyRepository
+	repo       repository.PropertyRepository
+	revisions  repository.PropertyRevisionRepository
+	authorizer Authorizer
+	events     EventPublisher
 }
 
-func NewPropertyService(repo repository.PropertyRepository) *PropertyService {
-	return &PropertyService{repo: repo}
+// PropertyServiceOption configures optional PropertyService dependencies
+type PropertyServiceOption func(*PropertyService)
+
+// WithRevisions snapshots each property before it is updated
+func WithRevisions(revisions repository.PropertyRevisionRepository) PropertyServiceOption {
+	return func(s *PropertyService) {
+		s.revisions = revisions
+	}
+}
+
+// WithAuthorizer checks destructive operations against the caller's
+// permissions, in addition to the route middleware
+func WithAuthorizer(authorizer Authorizer) PropertyServiceOption {
+	return func(s *PropertyService) {
+		s.authorizer = authorizer
+	}
+}
+
+// WithPropertyEvents publishes an event whenever a property is created,
+// updated or deleted
+func WithPropertyEvents(publisher EventPublisher) PropertyServiceOption {
+	return func(s *PropertyService) {
+		s.events = publisher
+	}
+}
+
+func NewPropertyService(repo repository.PropertyRepository, opts ...PropertyServiceOption) *PropertyService {
+	s := &PropertyService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *PropertyService) CreateProperty(ctx context.Context, property *models.Property) error {
-	if err := validateProperty(property); err != nil {
+	if err := prepareNewProperty(property); err != nil {
 		return err
 	}
-	return s.repo.Create(ctx, property)
+	if err := s.repo.Create(ctx, property); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, events.PropertyCreated, propertySubject(property.ID), property)
+	property.Warnings = propertyWarnings(property)
+	return nil
 }
 
 func (s *PropertyService) GetProperty(ctx context.Context, id int) (*models.Property, error) {
-	return s.repo.GetByID(ctx, id)
+	property, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The repository reports a missing row as (nil, nil)
+	if property == nil {
+		return nil, apperrors.NotFound("property not found")
+	}
+	return property, nil
 }
 
 func (s *PropertyService) UpdateProperty(ctx context.Context, property *models.Property) error {
 	if err := validateProperty(property); err != nil {
 		return err
 	}
+	property.NormalizeMeasurements()
+	property.NormalizePhotos()
+	if err := s.update(ctx, property); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apperrors.Conflictf("property was modified since version %d; reload it and retry", property.Version)
+		}
+		return err
+	}
+	publishEvent(ctx, s.events, events.PropertyUpdated, propertySubject(property.ID), property)
+	property.Warnings = propertyWarnings(property)
+	return nil
+}
+
+// update saves property, snapshotting its stored state in the same
+// transaction when revisions are enabled
+func (s *PropertyService) update(ctx context.Context, property *models.Property) error {
+	if revision := s.revision(ctx); revision != nil {
+		return s.repo.UpdateWithRevision(ctx, property, revision)
+	}
 	return s.repo.Update(ctx, property)
 }
 
+// revision asks repository writes to snapshot what they change as the
+// actor in ctx, or is nil when revisions are not enabled
+func (s *PropertyService) revision(ctx context.Context) *repository.Revision {
+	if s.revisions == nil {
+		return nil
+	}
+	revision := &repository.Revision{}
+	if userID, ok := ActorFromContext(ctx); ok {
+		revision.ChangedBy = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(userID), Valid: true}}
+	}
+	return revision
+}
+
+// GetRevisions lists a property's snapshots, newest first
+func (s *PropertyService) GetRevisions(ctx context.Context, id int) ([]models.PropertyRevision, error) {
+	if s.revisions == nil {
+		return nil, apperrors.NotFound("revisions are not enabled")
+	}
+	if _, err := s.GetProperty(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.revisions.GetByPropertyID(ctx, id)
+}
+
+// RevertProperty restores a property to a stored snapshot. The state being
+// replaced is itself snapshotted, so a revert can be undone.
+func (s *PropertyService) RevertProperty(ctx context.Context, id, revisionID int) (*models.Property, error) {
+	if err := s.authorize(ctx, PermPropertiesUpdate); err != nil {
+		return nil, err
+	}
+	if s.revisions == nil {
+		return nil, apperrors.NotFound("revisions are not enabled")
+	}
+
+	revision, err := s.revisions.GetByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil || revision.PropertyID != id {
+		return nil, apperrors.NotFound("revision not found")
+	}
+
+	var property models.Property
+	if err := json.Unmarshal(revision.Snapshot, &property); err != nil {
+		return nil, err
+	}
+	property.ID = id
+	// A revert overwrites whatever the current version is
+	property.Version = 0
+
+	if err := s.UpdateProperty(ctx, &property); err != nil {
+		return nil, err
+	}
+	return s.GetProperty(ctx, id)
+}
+
 func (s *PropertyService) DeleteProperty(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+	if err := s.authorize(ctx, PermPropertiesDelete); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, events.PropertyDeleted, propertySubject(id), map[string]int{"id": id})
+	return nil
+}
+
+func (s *PropertyService) authorize(ctx context.Context, permission string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	return s.authorizer.Authorize(ctx, permission)
 }
 
 func (s *PropertyService) GetAllProperties(ctx context.Context) ([]models.Property, error) {
 	return s.repo.GetAll(ctx)
 }
 
+// ExportPageSize is how many properties ExportProperties reads per query
+const ExportPageSize = 500
+
+// ExportProperties calls fn for every property with an ID greater than
+// afterID, in ID order. Properties are read a page at a time so the whole
+// inventory is never held in memory; an error from fn stops the export, as
+// does ctx being cancelled when the client goes away.
+func (s *PropertyService) ExportProperties(ctx context.Context, afterID int, fn func(models.Property) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := s.repo.ListAfter(ctx, afterID, ExportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, property := range page {
+			if err := fn(property); err != nil {
+				return err
+			}
+			afterID = property.ID
+		}
+		if len(page) < ExportPageSize {
+			return nil
+		}
+	}
+}
+
+// SearchProperties returns properties matching search, or all properties,
+// newest first, when search is empty
+func (s *PropertyService) SearchProperties(ctx context.Context, search models.PropertySearch) ([]models.Property, error) {
+	if search.Sort != "" && !slices.Contains(models.PropertySorts, search.Sort) {
+		return nil, apperrors.Validationf("sort must be one of %s", strings.Join(models.PropertySorts, ", "))
+	}
+	if search.Limit < 0 || search.Page < 0 || (search.Page > 1 && search.Limit == 0) {
+		return nil, apperrors.Validation("page requires a positive limit")
+	}
+	if search.IsEmpty() && search.Sort == "" && search.Limit == 0 {
+		return s.repo.GetAll(ctx)
+	}
+	if err := validateSearch(search); err != nil {
+		return nil, err
+	}
+	return s.repo.Search(ctx, search)
+}
+
+// Facets counts the properties matching search by category, listing type,
+// status and zoning, for clients to show next to the search filters
+func (s *PropertyService) Facets(ctx context.Context, search models.PropertySearch) (*models.PropertyFacets, error) {
+	if err := validateSearch(search); err != nil {
+		return nil, err
+	}
+	return s.repo.Facets(ctx, search)
+}
+
+// validateSearch checks the search criteria that name a known value
+func validateSearch(search models.PropertySearch) error {
+	if hvac := search.Amenities.HVACType; hvac != nil && !models.IsValidHVACType(*hvac) {
+		return apperrors.Validation("invalid hvac_type")
+	}
+	if search.Status != "" && !models.IsValidPropertyStatus(search.Status) {
+		return apperrors.Validation("invalid property status")
+	}
+	if search.ListingType != "" && !models.IsValidListingType(search.ListingType) {
+		return apperrors.Validation("listing_type must be sale or rent")
+	}
+	if search.Category != "" && !models.IsValidCategory(search.Category) {
+		return apperrors.Validation("category must be residential, commercial or land")
+	}
+	if search.MinPrice != nil && search.MaxPrice != nil && *search.MinPrice > *search.MaxPrice {
+		return apperrors.Validation("min_price must not be greater than max_price")
+	}
+	if search.MinYearBuilt != nil && search.MaxYearBuilt != nil && *search.MinYearBuilt > *search.MaxYearBuilt {
+		return apperrors.Validation("min_year_built must not be greater than max_year_built")
+	}
+	return nil
+}
+
+// BulkUpdate applies req.Patch to every property matching req.Filter,
+// snapshotting each one it changes, or only counts the matches when
+// req.DryRun is set
+func (s *PropertyService) BulkUpdate(ctx context.Context, req models.BulkUpdateRequest) (*models.BulkUpdateResult, error) {
+	if err := s.authorize(ctx, PermPropertiesBulkUpdate); err != nil {
+		return nil, err
+	}
+	if req.Filter.IsEmpty() {
+		return nil, apperrors.Validation("filter must set at least one field")
+	}
+	if req.Patch.IsEmpty() {
+		return nil, apperrors.Validation("patch must set at least one field")
+	}
+	if req.Filter.Status != nil && !models.IsValidPropertyStatus(*req.Filter.Status) {
+		return nil, apperrors.Validation("invalid status in filter")
+	}
+	if req.Patch.Status != nil && !models.IsValidPropertyStatus(*req.Patch.Status) {
+		return nil, apperrors.Validation("invalid status in patch")
+	}
+	result, err := s.repo.BulkUpdate(ctx, req.Filter, req.Patch, req.DryRun, s.revision(ctx))
+	if err != nil {
+		return nil, err
+	}
+	// Consumers re-read the matching properties; individual IDs are not known
+	if !result.DryRun && result.Updated > 0 {
+		publishEvent(ctx, s.events, events.PropertiesBulkUpdated, "property/*", map[string]any{
+			"filter":  req.Filter,
+			"patch":   req.Patch,
+			"updated": result.Updated,
+		})
+	}
+	return result, nil
+}
+
+// MaxSyncMutations is the largest batch Sync accepts
+const MaxSyncMutations = 100
+
+// Sync applies a batch of offline mutations atomically. Every mutation is
+// validated and authorized first; updates and deletes must name the version
+// they were based on, and if any of them is stale nothing is applied and the
+// conflicts are returned for the client to resolve.
+func (s *PropertyService) Sync(ctx context.Context, req models.SyncRequest) (*models.SyncResult, error) {
+	if len(req.Mutations) == 0 || len(req.Mutations) > MaxSyncMutations {
+		return nil, apperrors.Validationf("a sync batch must have between 1 and %d mutations", MaxSyncMutations)
+	}
+
+	for i, mutation := range req.Mutations {
+		if err := s.prepareMutation(ctx, mutation); err != nil {
+			return nil, fmt.Errorf("mutation %d: %w", i, err)
+		}
+	}
+	results, conflicts, err := s.repo.ApplyBatch(ctx, req.Mutations, s.revision(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return &models.SyncResult{Conflicts: conflicts}, nil
+	}
+
+	for _, result := range results {
+		mutation := req.Mutations[result.Index]
+		switch result.Op {
+		case models.MutationCreate:
+			publishEvent(ctx, s.events, events.PropertyCreated, propertySubject(result.ID), mutation.Property)
+		case models.MutationUpdate:
+			publishEvent(ctx, s.events, events.PropertyUpdated, propertySubject(result.ID), mutation.Property)
+		case models.MutationDelete:
+			publishEvent(ctx, s.events, events.PropertyDeleted, propertySubject(result.ID), map[string]int{"id": result.ID})
+		}
+	}
+	return &models.SyncResult{Results: results}, nil
+}
+
+// prepareMutation validates a mutation, checks the caller may perform it and
+// normalizes its property
+func (s *PropertyService) prepareMutation(ctx context.Context, mutation models.PropertyMutation) error {
+	switch mutation.Op {
+	case models.MutationCreate:
+		if err := s.authorize(ctx, PermPropertiesCreate); err != nil {
+			return err
+		}
+		return prepareNewProperty(mutation.Property)
+	case models.MutationUpdate:
+		if err := s.authorize(ctx, PermPropertiesUpdate); err != nil {
+			return err
+		}
+		if mutation.ID <= 0 || mutation.BaseVersion <= 0 {
+			return apperrors.Validation("update needs id and base_version")
+		}
+		if err := validateProperty(mutation.Property); err != nil {
+			return err
+		}
+		mutation.Property.NormalizeMeasurements()
+		mutation.Property.NormalizePhotos()
+		return nil
+	case models.MutationDelete:
+		if err := s.authorize(ctx, PermPropertiesDelete); err != nil {
+			return err
+		}
+		if mutation.ID <= 0 || mutation.BaseVersion <= 0 {
+			return apperrors.Validation("delete needs id and base_version")
+		}
+		return nil
+	default:
+		return apperrors.Validationf("unknown op %q", mutation.Op)
+	}
+}
+
+// prepareNewProperty validates a property about to be created and applies
+// the server-maintained defaults
+func prepareNewProperty(property *models.Property) error {
+	if err := validateProperty(property); err != nil {
+		return err
+	}
+	property.NormalizeMeasurements()
+	property.NormalizePhotos()
+	// Sync and staleness timestamps are maintained by the server
+	property.LastSyncedAt = models.NullTime{}
+	property.StaleAt = models.NullTime{}
+	if property.Status == "" {
+		property.Status = models.PropertyStatusActive
+	}
+	return nil
+}
+
+// validateProperty checks a property about to be written. Properties without
+// a listing type are taken to be for sale, and those without a category to
+// be residential.
 func validateProperty(property *models.Property) error {
-	if property == nil || property.Name == "" || property.Location == "" || property.Price <= 0 {
-		return errors.New("invalid property data")
+	if property == nil || property.Name == "" || property.Location == "" || property.Price < 0 {
+		return apperrors.Validation("invalid property data")
+	}
+	if property.ListingType == "" {
+		property.ListingType = models.ListingTypeSale
+	}
+	if !models.IsValidListingType(property.ListingType) {
+		return apperrors.Validation("listing_type must be sale or rent")
+	}
+	if property.IsRental() {
+		if err := validateRental(property); err != nil {
+			return err
+		}
+	} else {
+		if property.Price == 0 {
+			return apperrors.Validation("invalid property data")
+		}
+		if property.MonthlyRent.Valid || property.SecurityDeposit.Valid || property.LeaseTermMonths.Valid ||
+			property.AvailableFrom.Valid {
+			return apperrors.Validation("monthly_rent, security_deposit, lease_term_months and available_from only apply to rentals")
+		}
+	}
+	if property.Category == "" {
+		property.Category = models.CategoryResidential
+	}
+	if err := validateCategory(property); err != nil {
+		return err
+	}
+	if property.Status != "" && !models.IsValidPropertyStatus(property.Status) {
+		return apperrors.Validation("invalid property status")
+	}
+	if property.AnnualTax.Valid && property.AnnualTax.Float64 < 0 {
+		return apperrors.Validation("annual_tax must not be negative")
+	}
+	if property.ParkingSpaces.Valid && property.ParkingSpaces.Int32 < 0 {
+		return apperrors.Validation("parking_spaces must not be negative")
+	}
+	for _, photo := range property.Photos {
+		if utf8.RuneCountInString(photo.AltText) > models.MaxPhotoAltTextLength {
+			return apperrors.Validationf("alt_text must be at most %d characters", models.MaxPhotoAltTextLength)
+		}
+	}
+	return nil
+}
+
+// maxLeaseTermMonths caps lease_term_months at ten years
+const maxLeaseTermMonths = 120
+
+// validateRental checks the rental terms. Rentals are priced by their
+// monthly rent, so their price may be left at 0.
+func validateRental(property *models.Property) error {
+	if !property.MonthlyRent.Valid || property.MonthlyRent.Float64 <= 0 {
+		return apperrors.Validation("rentals need a positive monthly_rent")
+	}
+	if property.SecurityDeposit.Valid && property.SecurityDeposit.Float64 < 0 {
+		return apperrors.Validation("security_deposit must not be negative")
+	}
+	if property.LeaseTermMonths.Valid && (property.LeaseTermMonths.Int32 < 1 || property.LeaseTermMonths.Int32 > maxLeaseTermMonths) {
+		return apperrors.Validationf("lease_term_months must be between 1 and %d", maxLeaseTermMonths)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// validateCategory checks that a property only has the fields of its
+// category, and that they are valid
+func validateCategory(property *models.Property) error {
+	switch property.Category {
+	case models.CategoryResidential:
+		if property.HasCommercialFields() || property.Acreage != nil || len(property.Utilities) > 0 {
+			return apperrors.Validation("zoning, cap_rate, noi, unit_count, acreage and utilities do not apply to residential listings")
+		}
+		return nil
+	case models.CategoryCommercial:
+		if property.Acreage != nil || len(property.Utilities) > 0 {
+			return apperrors.Validation("acreage and utilities only apply to land")
+		}
+		return validateCommercial(property)
+	case models.CategoryLand:
+		if property.HasStructureFields() {
+			return apperrors.Validation("land listings have no bedrooms, bathrooms, square_feet or year_built")
+		}
+		if property.CapRate != nil || property.NOI != nil || property.UnitCount != nil {
+			return apperrors.Validation("cap_rate, noi and unit_count only apply to commercial listings")
+		}
+		return validateLand(property)
+	default:
+		return apperrors.Validation("category must be residential, commercial or land")
+	}
+}
+
+// validateCommercial checks the commercial fields
+func validateCommercial(property *models.Property) error {
+	if err := normalizeZoning(property); err != nil {
+		return err
+	}
+	if property.CapRate != nil && (*property.CapRate <= 0 || *property.CapRate > 100) {
+		return apperrors.Validation("cap_rate must be a percentage above 0 and up to 100")
+	}
+	if property.UnitCount != nil && *property.UnitCount < 1 {
+		return apperrors.Validation("unit_count must be at least 1")
+	}
+	return nil
+}
+
+// validateLand checks the land fields. Utilities are deduplicated.
+func validateLand(property *models.Property) error {
+	if err := normalizeZoning(property); err != nil {
+		return err
+	}
+	if property.Acreage != nil && *property.Acreage <= 0 {
+		return apperrors.Validation("acreage must be positive")
+	}
+	var utilities models.StringList
+	for _, utility := range property.Utilities {
+		if !models.IsValidUtility(utility) {
+			return apperrors.Validationf("unknown utility %q", utility)
+		}
+		if !slices.Contains(utilities, utility) {
+			utilities = append(utilities, utility)
+		}
+	}
+	property.Utilities = utilities
+	return nil
+}
+
+// normalizeZoning trims the zoning and drops a blank one
+func normalizeZoning(property *models.Property) error {
+	if property.Zoning == nil {
+		return nil
+	}
+	zoning := strings.TrimSpace(*property.Zoning)
+	switch {
+	case zoning == "":
+		property.Zoning = nil
+	case len(zoning) > 50:
+		return apperrors.Validation("zoning must be at most 50 characters")
+	default:
+		property.Zoning = &zoning
+	}
+	return nil
+}
+
+// Thresholds of the property warnings. Prices per square foot outside
+// minPricePerSqft and maxPricePerSqft are more likely a typo in the price
+// or the square footage than a real listing.
+const (
+	minDescriptionLength = 100
+	minPricePerSqft      = 20
+	maxPricePerSqft      = 5000
+)
+
+// propertyWarnings returns the problems with a valid property that are
+// worth prompting about but do not stop it from being saved
+func propertyWarnings(property *models.Property) []models.PropertyWarning {
+	var warnings []models.PropertyWarning
+	if len(property.Photos) == 0 {
+		warnings = append(warnings, models.PropertyWarning{Code: models.WarningMissingPhotos, Field: "photos",
+			Message: "The listing has no photos"})
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(property.Description.String)) < minDescriptionLength {
+		warnings = append(warnings, models.PropertyWarning{Code: models.WarningShortDescription, Field: "description",
+			Message: "The description is short; a few sentences about the listing draw more interest"})
+	}
+	if property.Category == models.CategoryLand && property.Acreage == nil && !property.LotAreaSqm.Valid {
+		warnings = append(warnings, models.PropertyWarning{Code: models.WarningMissingAcreage, Field: "acreage",
+			Message: "The land listing has no acreage or lot size"})
+	}
+	// Rents are not comparable to sale prices per square foot
+	if !property.IsRental() && property.SquareFeet.Valid && property.SquareFeet.Int32 > 0 {
+		perSqft := property.Price / float64(property.SquareFeet.Int32)
+		if perSqft < minPricePerSqft || perSqft > maxPricePerSqft {
+			warnings = append(warnings, models.PropertyWarning{Code: models.WarningUnusualPricePerSqft, Field: "price",
+				Message: "The price per square foot is unusually low or high; check the price and square footage"})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPropertyService_UpdatePropertySnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().UpdateWithRevision(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, property *models.Property, revision *repository.Revision) error {
+			if property.Name != "New Name" {
+				t.Errorf("Expected the new state to be written, got name %q", property.Name)
+			}
+			if revision == nil || !revision.ChangedBy.Valid || revision.ChangedBy.Int32 != 9 {
+				t.Errorf("Expected the previous state to be snapshotted as changed by 9, got %+v", revision)
+			}
+			return nil
+		})
+
+	service := NewPropertyService(mockRepo, WithRevisions(mocks.NewMockPropertyRevisionRepository(ctrl)))
+	ctx := WithActor(context.Background(), 9)
+	err := service.UpdateProperty(ctx, &models.Property{ID: 1, Name: "New Name", Location: "123 Main St", Price: 100000})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestPropertyService_BulkUpdateSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	agentID := 7
+	withdrawn := models.PropertyStatusWithdrawn
+	mockRepo.EXPECT().BulkUpdate(gomock.Any(), gomock.Any(), gomock.Any(), false, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, filter models.PropertyFilter, patch models.PropertyPatch, dryRun bool, revision *repository.Revision) (*models.BulkUpdateResult, error) {
+			if revision == nil || !revision.ChangedBy.Valid || revision.ChangedBy.Int32 != 9 {
+				t.Errorf("Expected the matched rows to be snapshotted as changed by 9, got %+v", revision)
+			}
+			return &models.BulkUpdateResult{Matched: 2, Updated: 2}, nil
+		})
+
+	service := NewPropertyService(mockRepo, WithRevisions(mocks.NewMockPropertyRevisionRepository(ctrl)))
+	ctx := WithActor(context.Background(), 9)
+	_, err := service.BulkUpdate(ctx, models.BulkUpdateRequest{
+		Filter: models.PropertyFilter{AgentID: &agentID},
+		Patch:  models.PropertyPatch{Status: &withdrawn},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestPropertyService_RevertProperty(t *testing.T) {
+	snapshot := json.RawMessage(`{"id":1,"name":"Old Name","location":"123 Main St","price":100000,"status":"active"}`)
+
+	tests := []struct {
+		name        string
+		setupMock   func(repo *mocks.MockPropertyRepository, revisions *mocks.MockPropertyRevisionRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name: "restores snapshot",
+			setupMock: func(repo *mocks.MockPropertyRepository, revisions *mocks.MockPropertyRevisionRepository) {
+				revisions.EXPECT().GetByID(gomock.Any(), 5).Return(&models.PropertyRevision{ID: 5, PropertyID: 1, Snapshot: snapshot}, nil)
+				restored := &models.Property{ID: 1, Name: "Old Name", Location: "123 Main St", Price: 100000}
+				gomock.InOrder(
+					repo.EXPECT().UpdateWithRevision(gomock.Any(), gomock.Any(), gomock.Not(gomock.Nil())).DoAndReturn(
+						func(ctx context.Context, property *models.Property, revision *repository.Revision) error {
+							if property.Name != "Old Name" || property.Price != 100000 {
+								t.Errorf("Expected snapshot values to be written, got %+v", property)
+							}
+							return nil
+						}),
+					repo.EXPECT().GetByID(gomock.Any(), 1).Return(restored, nil),
+				)
+			},
+		},
+		{
+			name: "revision belongs to another property",
+			setupMock: func(repo *mocks.MockPropertyRepository, revisions *mocks.MockPropertyRevisionRepository) {
+				revisions.EXPECT().GetByID(gomock.Any(), 5).Return(&models.PropertyRevision{ID: 5, PropertyID: 2, Snapshot: snapshot}, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrNotFound,
+		},
+		{
+			name: "revision not found",
+			setupMock: func(repo *mocks.MockPropertyRepository, revisions *mocks.MockPropertyRevisionRepository) {
+				revisions.EXPECT().GetByID(gomock.Any(), 5).Return(nil, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			mockRevisions := mocks.NewMockPropertyRevisionRepository(ctrl)
+			tt.setupMock(mockRepo, mockRevisions)
+
+			service := NewPropertyService(mockRepo, WithRevisions(mockRevisions))
+			property, err := service.RevertProperty(context.Background(), 1, 5)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if property.Name != "Old Name" {
+				t.Errorf("Expected restored property, got %+v", property)
+			}
+		})
+	}
+}
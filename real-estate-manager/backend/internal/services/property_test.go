@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"real-estate-manager/backend/internal/apperrors"
 	"real-estate-manager/backend/internal/mocks"
 	"real-estate-manager/backend/internal/models"
 
@@ -157,6 +160,10 @@ func TestPropertyService_CreateProperty(t *testing.T) {
 				if err != nil {
 					t.Errorf("Expected no error but got: %v", err)
 				}
+				// A listing without photos is saved, with a warning
+				if len(tt.property.Warnings) == 0 || tt.property.Warnings[0].Code != models.WarningMissingPhotos {
+					t.Errorf("Expected a missing photos warning, got %+v", tt.property.Warnings)
+				}
 			}
 		})
 	}
@@ -170,6 +177,7 @@ func TestPropertyService_GetProperty(t *testing.T) {
 		expectedProp  *models.Property
 		expectError   bool
 		errorMsg      string
+		errorKind     error
 	}{
 		{
 			name: "successful retrieval",
@@ -202,12 +210,13 @@ func TestPropertyService_GetProperty(t *testing.T) {
 			setupMock: func(mock *mocks.MockPropertyRepository) {
 				mock.EXPECT().
 					GetByID(gomock.Any(), 999).
-					Return(nil, errors.New("property not found")).
+					Return(nil, nil).
 					Times(1)
 			},
 			expectedProp: nil,
 			expectError:  true,
 			errorMsg:     "property not found",
+			errorKind:    apperrors.ErrNotFound,
 		},
 		{
 			name: "repository error",
@@ -241,6 +250,9 @@ func TestPropertyService_GetProperty(t *testing.T) {
 				} else if err.Error() != tt.errorMsg {
 					t.Errorf("Expected error message '%s', got '%s'", tt.errorMsg, err.Error())
 				}
+				if tt.errorKind != nil && !errors.Is(err, tt.errorKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.errorKind, err)
+				}
 				if prop != nil {
 					t.Error("Expected nil property on error")
 				}
@@ -585,7 +597,43 @@ func TestPropertyService_GetAllProperties(t *testing.T) {
 	}
 }
 
+func TestPropertyService_SearchProperties_Ranges(t *testing.T) {
+	low, high := 300000.0, 450000.0
+	oldest, newest := 1990, 2010
+
+	tests := []struct {
+		name        string
+		search      models.PropertySearch
+		expectError bool
+	}{
+		{name: "price and year ranges", search: models.PropertySearch{MinPrice: &low, MaxPrice: &high, MinYearBuilt: &oldest, MaxYearBuilt: &newest}},
+		{name: "only a minimum price", search: models.PropertySearch{MinPrice: &high}},
+		{name: "inverted price range", search: models.PropertySearch{MinPrice: &high, MaxPrice: &low}, expectError: true},
+		{name: "inverted year range", search: models.PropertySearch{MinYearBuilt: &newest, MaxYearBuilt: &oldest}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Search(gomock.Any(), tt.search).Return([]models.Property{}, nil)
+			}
+
+			_, err := NewPropertyService(mockRepo).SearchProperties(context.Background(), tt.search)
+			if tt.expectError != errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("Expected validation error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
 func TestValidateProperty(t *testing.T) {
+	capRate, badCapRate, unitCount := 6.5, 120.0, 8
+	zoning, acreage := "AG-1", 12.5
+
 	tests := []struct {
 		name        string
 		property    *models.Property
@@ -647,6 +695,155 @@ func TestValidateProperty(t *testing.T) {
 			expectError: true,
 			errorMsg:    "invalid property data",
 		},
+		{
+			name: "unknown status",
+			property: &models.Property{
+				Name:     "Valid House",
+				Location: "123 Main St",
+				Price:    100000.00,
+				Status:   "demolished",
+			},
+			expectError: true,
+			errorMsg:    "invalid property status",
+		},
+		{
+			name: "negative annual tax",
+			property: &models.Property{
+				Name:      "Valid House",
+				Location:  "123 Main St",
+				Price:     100000.00,
+				AnnualTax: models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: -1, Valid: true}},
+			},
+			expectError: true,
+			errorMsg:    "annual_tax must not be negative",
+		},
+		{
+			name: "valid rental without a price",
+			property: &models.Property{
+				Name:            "Valid Flat",
+				Location:        "123 Main St",
+				ListingType:     models.ListingTypeRent,
+				MonthlyRent:     models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 2100, Valid: true}},
+				LeaseTermMonths: models.NullInt32{NullInt32: sql.NullInt32{Int32: 12, Valid: true}},
+			},
+			expectError: false,
+		},
+		{
+			name: "rental without rent",
+			property: &models.Property{
+				Name:        "Valid Flat",
+				Location:    "123 Main St",
+				ListingType: models.ListingTypeRent,
+			},
+			expectError: true,
+			errorMsg:    "rentals need a positive monthly_rent",
+		},
+		{
+			name: "rental with too long a lease",
+			property: &models.Property{
+				Name:            "Valid Flat",
+				Location:        "123 Main St",
+				ListingType:     models.ListingTypeRent,
+				MonthlyRent:     models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 2100, Valid: true}},
+				LeaseTermMonths: models.NullInt32{NullInt32: sql.NullInt32{Int32: 240, Valid: true}},
+			},
+			expectError: true,
+			errorMsg:    "lease_term_months must be between 1 and 120",
+		},
+		{
+			name: "sale with rental terms",
+			property: &models.Property{
+				Name:        "Valid House",
+				Location:    "123 Main St",
+				Price:       100000.00,
+				MonthlyRent: models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 2100, Valid: true}},
+			},
+			expectError: true,
+			errorMsg:    "monthly_rent, security_deposit, lease_term_months and available_from only apply to rentals",
+		},
+		{
+			name: "unknown listing type",
+			property: &models.Property{
+				Name:        "Valid House",
+				Location:    "123 Main St",
+				Price:       100000.00,
+				ListingType: "lease",
+			},
+			expectError: true,
+			errorMsg:    "listing_type must be sale or rent",
+		},
+		{
+			name: "commercial with income figures",
+			property: &models.Property{
+				Name:      "Valid Plaza",
+				Location:  "123 Main St",
+				Price:     2500000.00,
+				Category:  models.CategoryCommercial,
+				CapRate:   &capRate,
+				UnitCount: &unitCount,
+			},
+			expectError: false,
+		},
+		{
+			name: "commercial with an impossible cap rate",
+			property: &models.Property{
+				Name:     "Valid Plaza",
+				Location: "123 Main St",
+				Price:    2500000.00,
+				Category: models.CategoryCommercial,
+				CapRate:  &badCapRate,
+			},
+			expectError: true,
+			errorMsg:    "cap_rate must be a percentage above 0 and up to 100",
+		},
+		{
+			name: "zoned land with utilities",
+			property: &models.Property{
+				Name:      "Valid Parcel",
+				Location:  "Ranch Rd",
+				Price:     180000.00,
+				Category:  models.CategoryLand,
+				Zoning:    &zoning,
+				Acreage:   &acreage,
+				Utilities: models.StringList{models.UtilityWater, models.UtilityElectricity},
+			},
+			expectError: false,
+		},
+		{
+			name: "land with bedrooms",
+			property: &models.Property{
+				Name:     "Valid Parcel",
+				Location: "Ranch Rd",
+				Price:    180000.00,
+				Category: models.CategoryLand,
+				Bedrooms: models.NullInt32{NullInt32: sql.NullInt32{Int32: 2, Valid: true}},
+			},
+			expectError: true,
+			errorMsg:    "land listings have no bedrooms, bathrooms, square_feet or year_built",
+		},
+		{
+			name: "land with an unknown utility",
+			property: &models.Property{
+				Name:      "Valid Parcel",
+				Location:  "Ranch Rd",
+				Price:     180000.00,
+				Category:  models.CategoryLand,
+				Utilities: models.StringList{"cable"},
+			},
+			expectError: true,
+			errorMsg:    `unknown utility "cable"`,
+		},
+		{
+			name: "residential with commercial fields",
+			property: &models.Property{
+				Name:     "Valid House",
+				Location: "123 Main St",
+				Price:    100000.00,
+				CapRate:  &capRate,
+			},
+			expectError: true,
+			errorMsg:    "zoning, cap_rate, noi, unit_count, acreage and utilities do not apply to residential listings",
+		},
 	}
 
 	for _, tt := range tests {
@@ -658,6 +855,8 @@ func TestValidateProperty(t *testing.T) {
 					t.Error("Expected error but got none")
 				} else if err.Error() != tt.errorMsg {
 					t.Errorf("Expected error message '%s', got '%s'", tt.errorMsg, err.Error())
+				} else if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected validation error kind, got %v", err)
 				}
 			} else {
 				if err != nil {
@@ -667,3 +866,208 @@ func TestValidateProperty(t *testing.T) {
 		})
 	}
 }
+
+func TestPropertyService_BulkUpdate(t *testing.T) {
+	agentID := 7
+	withdrawn := models.PropertyStatusWithdrawn
+	unknown := "demolished"
+
+	tests := []struct {
+		name        string
+		req         models.BulkUpdateRequest
+		setupMock   func(mock *mocks.MockPropertyRepository)
+		expectError bool
+	}{
+		{
+			name: "delegates to repository",
+			req: models.BulkUpdateRequest{
+				Filter: models.PropertyFilter{AgentID: &agentID},
+				Patch:  models.PropertyPatch{Status: &withdrawn},
+				DryRun: true,
+			},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().BulkUpdate(gomock.Any(), gomock.Any(), gomock.Any(), true, nil).
+					Return(&models.BulkUpdateResult{Matched: 4, DryRun: true}, nil)
+			},
+		},
+		{
+			name:        "empty filter is rejected",
+			req:         models.BulkUpdateRequest{Patch: models.PropertyPatch{Status: &withdrawn}},
+			setupMock:   func(mock *mocks.MockPropertyRepository) {},
+			expectError: true,
+		},
+		{
+			name:        "empty patch is rejected",
+			req:         models.BulkUpdateRequest{Filter: models.PropertyFilter{AgentID: &agentID}},
+			setupMock:   func(mock *mocks.MockPropertyRepository) {},
+			expectError: true,
+		},
+		{
+			name: "unknown status is rejected",
+			req: models.BulkUpdateRequest{
+				Filter: models.PropertyFilter{AgentID: &agentID},
+				Patch:  models.PropertyPatch{Status: &unknown},
+			},
+			setupMock:   func(mock *mocks.MockPropertyRepository) {},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewPropertyService(mockRepo)
+			_, err := service.BulkUpdate(context.Background(), tt.req)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected validation error kind, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPropertyService_ExportProperties(t *testing.T) {
+	page := func(from, count int) []models.Property {
+		properties := make([]models.Property, count)
+		for i := range properties {
+			properties[i].ID = from + i
+		}
+		return properties
+	}
+
+	tests := []struct {
+		name        string
+		afterID     int
+		setupMock   func(mock *mocks.MockPropertyRepository)
+		expectCount int
+		expectError bool
+	}{
+		{
+			name: "follows the cursor until a short page",
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				gomock.InOrder(
+					mock.EXPECT().ListAfter(gomock.Any(), 0, ExportPageSize).Return(page(1, ExportPageSize), nil),
+					mock.EXPECT().ListAfter(gomock.Any(), ExportPageSize, ExportPageSize).Return(page(ExportPageSize+1, 3), nil),
+				)
+			},
+			expectCount: ExportPageSize + 3,
+		},
+		{
+			name:    "resumes after a given ID",
+			afterID: 42,
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().ListAfter(gomock.Any(), 42, ExportPageSize).Return(nil, nil)
+			},
+		},
+		{
+			name: "repository error",
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().ListAfter(gomock.Any(), 0, ExportPageSize).Return(nil, errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewPropertyService(mockRepo)
+			count, lastID := 0, tt.afterID
+			err := service.ExportProperties(context.Background(), tt.afterID, func(property models.Property) error {
+				if property.ID <= lastID {
+					t.Errorf("Expected IDs in ascending order, got %d after %d", property.ID, lastID)
+				}
+				count, lastID = count+1, property.ID
+				return nil
+			})
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if count != tt.expectCount {
+				t.Errorf("Expected %d properties, got %d", tt.expectCount, count)
+			}
+		})
+	}
+}
+
+func TestPropertyService_ExportProperties_Cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	properties := make([]models.Property, ExportPageSize)
+	for i := range properties {
+		properties[i].ID = i + 1
+	}
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().ListAfter(gomock.Any(), 0, ExportPageSize).Return(properties, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := NewPropertyService(mockRepo)
+	err := service.ExportProperties(ctx, 0, func(property models.Property) error {
+		// The client goes away during the first page
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the export to stop with context.Canceled, got %v", err)
+	}
+}
+
+func TestPropertyWarnings(t *testing.T) {
+	description := models.NullString{NullString: sql.NullString{String: strings.Repeat("Bright corner unit. ", 6), Valid: true}}
+	photos := models.PhotoList{{URL: "https://mls.example.com/a.jpg"}}
+	sqft := func(n int32) models.NullInt32 {
+		return models.NullInt32{NullInt32: sql.NullInt32{Int32: n, Valid: true}}
+	}
+
+	tests := []struct {
+		name     string
+		property models.Property
+		expected []string
+	}{
+		{name: "complete listing", property: models.Property{Price: 350000, Photos: photos, Description: description, SquareFeet: sqft(1850)}},
+		{name: "no photos or description", property: models.Property{Price: 350000},
+			expected: []string{models.WarningMissingPhotos, models.WarningShortDescription}},
+		{name: "price missing a digit", property: models.Property{Price: 35000, Photos: photos, Description: description, SquareFeet: sqft(1850)},
+			expected: []string{models.WarningUnusualPricePerSqft}},
+		{name: "square footage missing digits", property: models.Property{Price: 350000, Photos: photos, Description: description, SquareFeet: sqft(18)},
+			expected: []string{models.WarningUnusualPricePerSqft}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := propertyWarnings(&tt.property)
+			codes := make([]string, len(warnings))
+			for i, warning := range warnings {
+				codes[i] = warning.Code
+			}
+			if !slices.Equal(codes, tt.expected) {
+				t.Errorf("Expected warnings %v, got %v", tt.expected, codes)
+			}
+		})
+	}
+}
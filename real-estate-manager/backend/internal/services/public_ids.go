@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// PublicIDService maps the public IDs of properties and users to their
+// numeric IDs, so routes can accept either while clients move over
+type PublicIDService struct {
+	properties repository.PropertyRepository
+	users      repository.UserRepository
+}
+
+func NewPublicIDService(properties repository.PropertyRepository, users repository.UserRepository) *PublicIDService {
+	return &PublicIDService{properties: properties, users: users}
+}
+
+// PropertyID returns the ID of the property with a public ID, among the
+// properties the caller's organization can see
+func (s *PublicIDService) PropertyID(ctx context.Context, publicID string) (int, error) {
+	property, err := s.properties.GetByPublicID(ctx, publicID)
+	if err != nil {
+		return 0, err
+	}
+	if property == nil {
+		return 0, apperrors.NotFound("property not found")
+	}
+	return property.ID, nil
+}
+
+// UserID returns the ID of the user with a public ID
+func (s *PublicIDService) UserID(ctx context.Context, publicID string) (uint, error) {
+	user, err := s.users.GetByPublicID(ctx, publicID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, apperrors.NotFound("user not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublicIDService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProperties.EXPECT().GetByPublicID(gomock.Any(), publicID).Return(&models.Property{ID: 12, PublicID: publicID}, nil)
+	mockProperties.EXPECT().GetByPublicID(gomock.Any(), "unknown").Return(nil, nil)
+	mockUsers.EXPECT().GetByPublicID(gomock.Any(), publicID).Return(&models.User{ID: 5, PublicID: publicID}, nil)
+	mockUsers.EXPECT().GetByPublicID(gomock.Any(), "unknown").Return(nil, sql.ErrNoRows)
+
+	service := NewPublicIDService(mockProperties, mockUsers)
+	ctx := context.Background()
+	if id, err := service.PropertyID(ctx, publicID); err != nil || id != 12 {
+		t.Errorf("Unexpected property ID %d (%v)", id, err)
+	}
+	if _, err := service.PropertyID(ctx, "unknown"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown property, got %v", err)
+	}
+	if id, err := service.UserID(ctx, publicID); err != nil || id != 5 {
+		t.Errorf("Unexpected user ID %d (%v)", id, err)
+	}
+	if _, err := service.UserID(ctx, "unknown"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown user, got %v", err)
+	}
+}
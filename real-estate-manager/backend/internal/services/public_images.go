@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/worker"
+	"real-estate-manager/backend/pkg/imaging"
+)
+
+// PublicImageSizes are the widths of the public photo variants
+var PublicImageSizes = map[string]int{"small": 320, "medium": 800, "large": 1600}
+
+// DefaultPublicImageSize is served when no size is requested
+const DefaultPublicImageSize = "medium"
+
+// PublicImage is a rendered variant ready to be served
+type PublicImage struct {
+	Path string
+	ETag string
+}
+
+// PublicImageService renders resized, watermarked variants of listing photos
+// for public sites. Only photos stored locally on listings that are on the
+// market are served, and variants are cached on disk. Rendering runs on the
+// image worker pool so it never takes more CPU than the pool allows.
+type PublicImageService struct {
+	properties   repository.PropertyRepository
+	publications repository.ListingPublicationRepository
+	settings     SettingsProvider
+	imagesDir    string
+	cacheDir     string
+	watermark    string
+	limiter      *windowLimiter
+	workers      *worker.Pool
+
+	mu        sync.Mutex
+	rendering map[string]*variantRender
+}
+
+// variantRender is a variant being rendered; done is closed once err is set
+type variantRender struct {
+	done chan struct{}
+	err  error
+}
+
+func NewPublicImageService(properties repository.PropertyRepository, publications repository.ListingPublicationRepository, settings SettingsProvider, workers *worker.Pool, imagesDir, cacheDir, watermark string) *PublicImageService {
+	os.MkdirAll(cacheDir, 0755)
+	return &PublicImageService{
+		properties:   properties,
+		publications: publications,
+		settings:     settings,
+		imagesDir:    imagesDir,
+		cacheDir:     cacheDir,
+		watermark:    watermark,
+		limiter:      newPublicImageLimiter(settings),
+		workers:      workers,
+		rendering:    make(map[string]*variantRender),
+	}
+}
+
+// publicImageLimiterKeys bounds the client IPs the unauthenticated image
+// route tracks, so requests from many addresses cannot grow it unbounded
+const publicImageLimiterKeys = 10000
+
+func newPublicImageLimiter(settings SettingsProvider) *windowLimiter {
+	limiter := newWindowLimiter(time.Minute, func() int {
+		return settings.GetInt(SettingPublicImageRate)
+	})
+	limiter.maxKeys = publicImageLimiterKeys
+	return limiter
+}
+
+// Variant returns the cached variant of a property's photo at index,
+// rendering it on first request
+func (s *PublicImageService) Variant(ctx context.Context, clientIP string, propertyID, index int, size string) (*PublicImage, error) {
+	if !s.limiter.Allow(clientIP, time.Now()) {
+		return nil, apperrors.RateLimited("too many image requests, try again later")
+	}
+	if size == "" {
+		size = DefaultPublicImageSize
+	}
+	width, ok := PublicImageSizes[size]
+	if !ok {
+		return nil, apperrors.Validation("size must be small, medium or large")
+	}
+
+	property, err := s.properties.GetByID(ctx, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property: %w", err)
+	}
+	if property == nil {
+		return nil, apperrors.NotFound("photo not found")
+	}
+	listed, err := publiclyListed(ctx, s.publications, property)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get publication: %w", err)
+	}
+	if !listed {
+		return nil, apperrors.NotFound("photo not found")
+	}
+	if index < 0 || index >= len(property.Photos) {
+		return nil, apperrors.NotFound("photo not found")
+	}
+	localURL := property.Photos[index].LocalURL
+	if !strings.HasPrefix(localURL, "/images/") {
+		return nil, apperrors.NotFound("photo not found")
+	}
+	source := filepath.Join(s.imagesDir, filepath.Base(localURL))
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, apperrors.NotFound("photo not found")
+	}
+
+	quality := s.settings.GetInt(SettingImageQuality)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%s|%d", filepath.Base(source), width, quality, s.watermark, info.ModTime().UnixNano())))
+	key := hex.EncodeToString(sum[:16])
+	variant := &PublicImage{Path: filepath.Join(s.cacheDir, key+".jpg"), ETag: `"` + key + `"`}
+
+	if _, err := os.Stat(variant.Path); err == nil {
+		return variant, nil
+	}
+	job, err := s.startRender(key, source, variant.Path, width, quality)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if job.err != nil {
+		return nil, job.err
+	}
+	return variant, nil
+}
+
+// startRender queues the render of a variant, or joins the one already
+// queued, so concurrent requests for a new variant decode the source once
+func (s *PublicImageService) startRender(key, source, path string, width, quality int) (*variantRender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.rendering[key]; ok {
+		return job, nil
+	}
+	job := &variantRender{done: make(chan struct{})}
+	err := s.workers.Submit(func(ctx context.Context) error {
+		job.err = s.render(source, path, width, quality)
+		s.mu.Lock()
+		delete(s.rendering, key)
+		s.mu.Unlock()
+		close(job.done)
+		// Reported to the requests waiting on it rather than logged
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Unavailable("image service is busy, try again shortly")
+	}
+	s.rendering[key] = job
+	return job, nil
+}
+
+// render writes the resized, watermarked JPEG for source to path
+func (s *PublicImageService) render(source, path string, width, quality int) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return apperrors.NotFound("photo not found")
+	}
+	defer file.Close()
+
+	decoded, _, err := image.Decode(file)
+	if err != nil {
+		// WebP and other formats the standard library cannot decode are
+		// not published
+		return apperrors.NotFound("photo not available")
+	}
+	img := imaging.Resize(imaging.Flatten(decoded), width)
+	if s.watermark != "" {
+		imaging.Watermark(img, s.watermark)
+	}
+
+	// Written to a temporary file first so a partially written variant is
+	// never served
+	tmp, err := os.CreateTemp(s.cacheDir, "variant-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create variant: %w", err)
+	}
+	if err := jpeg.Encode(tmp, img, &jpeg.Options{Quality: quality}); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to encode variant: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save variant: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save variant: %w", err)
+	}
+	return nil
+}
+
+// isPublicStatus reports whether a listing in status may be shown publicly
+func isPublicStatus(status string) bool {
+	return status == models.PropertyStatusActive || status == models.PropertyStatusPending
+}
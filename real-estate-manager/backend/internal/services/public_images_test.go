@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/worker"
+
+	"go.uber.org/mock/gomock"
+)
+
+func writeTestPNG(t *testing.T, path string, width, height int) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 40, G: 90, B: 160, A: 255})
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testWorkers returns an image worker pool stopped when the test ends
+func testWorkers(t *testing.T) *worker.Pool {
+	t.Helper()
+	pool := worker.New("images", 2, 8)
+	t.Cleanup(func() { pool.Stop(context.Background()) })
+	return pool
+}
+
+func TestPublicImageService_Variant(t *testing.T) {
+	imagesDir := t.TempDir()
+	writeTestPNG(t, filepath.Join(imagesDir, "house.png"), 1000, 500)
+
+	listing := func(status string) *models.Property {
+		return &models.Property{ID: 1, Status: status, Photos: models.PhotoList{
+			{URL: "/images/house.png", LocalURL: "/images/house.png"},
+			{URL: "https://mls.example.com/remote.jpg"},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		property      *models.Property
+		index         int
+		size          string
+		expectedWidth int
+		unapproved    bool
+		expectedErr   error
+	}{
+		{name: "default size", property: listing(models.PropertyStatusActive), expectedWidth: 800},
+		{name: "small pending listing", property: listing(models.PropertyStatusPending), size: "small", expectedWidth: 320},
+		{name: "never upscales", property: listing(models.PropertyStatusActive), size: "large", expectedWidth: 1000},
+		{name: "unknown size", property: listing(models.PropertyStatusActive), size: "huge", expectedErr: apperrors.ErrValidation},
+		{name: "sold listing", property: listing(models.PropertyStatusSold), expectedErr: apperrors.ErrNotFound},
+		{name: "listing not approved", property: listing(models.PropertyStatusActive), unapproved: true, expectedErr: apperrors.ErrNotFound},
+		{name: "missing property", expectedErr: apperrors.ErrNotFound},
+		{name: "remote photo", property: listing(models.PropertyStatusActive), index: 1, expectedErr: apperrors.ErrNotFound},
+		{name: "index out of range", property: listing(models.PropertyStatusActive), index: 5, expectedErr: apperrors.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(tt.property, nil).AnyTimes()
+			publication := &models.ListingPublication{PropertyID: 1, State: models.PublishApproved}
+			if tt.unapproved {
+				publication.State = models.PublishDraft
+			}
+			mockPublications := mocks.NewMockListingPublicationRepository(ctrl)
+			mockPublications.EXPECT().Get(gomock.Any(), 1).Return(publication, nil).AnyTimes()
+
+			service := NewPublicImageService(mockRepo, mockPublications, staticSettings{}, testWorkers(t), imagesDir, t.TempDir(), "Acme Realty")
+			variant, err := service.Variant(context.Background(), "203.0.113.1", 1, tt.index, tt.size)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			file, err := os.Open(variant.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			img, err := jpeg.Decode(file)
+			if err != nil {
+				t.Fatalf("variant is not a JPEG: %v", err)
+			}
+			if width := img.Bounds().Dx(); width != tt.expectedWidth {
+				t.Errorf("expected width %d, got %d", tt.expectedWidth, width)
+			}
+		})
+	}
+}
+
+func TestPublicImageService_CachesVariants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imagesDir, cacheDir := t.TempDir(), t.TempDir()
+	writeTestPNG(t, filepath.Join(imagesDir, "house.png"), 400, 300)
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1, Status: models.PropertyStatusActive, Photos: models.PhotoList{
+		{URL: "/images/house.png", LocalURL: "/images/house.png"},
+	}}, nil).Times(2)
+	mockPublications := mocks.NewMockListingPublicationRepository(ctrl)
+	mockPublications.EXPECT().Get(gomock.Any(), 1).Return(&models.ListingPublication{PropertyID: 1, State: models.PublishApproved}, nil).Times(2)
+
+	service := NewPublicImageService(mockRepo, mockPublications, staticSettings{}, testWorkers(t), imagesDir, cacheDir, "")
+	first, err := service.Variant(context.Background(), "203.0.113.1", 1, 0, "small")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err := os.Stat(first.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := service.Variant(context.Background(), "203.0.113.1", 1, 0, "small")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.ETag != first.ETag || second.Path != first.Path {
+		t.Errorf("expected the cached variant, got %+v and %+v", first, second)
+	}
+	again, _ := os.Stat(second.Path)
+	if !again.ModTime().Equal(info.ModTime()) {
+		t.Error("expected the cached variant not to be rendered again")
+	}
+
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Errorf("expected 1 cached file, got %d", len(entries))
+	}
+}
+
+func TestPublicImageService_WorkersBusy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imagesDir := t.TempDir()
+	writeTestPNG(t, filepath.Join(imagesDir, "house.png"), 400, 300)
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1, Status: models.PropertyStatusActive, Photos: models.PhotoList{
+		{URL: "/images/house.png", LocalURL: "/images/house.png"},
+	}}, nil)
+	mockPublications := mocks.NewMockListingPublicationRepository(ctrl)
+	mockPublications.EXPECT().Get(gomock.Any(), 1).Return(&models.ListingPublication{PropertyID: 1, State: models.PublishApproved}, nil)
+
+	// A stopped pool turns every render away, as a full queue does
+	workers := worker.New("images", 1, 1)
+	workers.Stop(context.Background())
+	service := NewPublicImageService(mockRepo, mockPublications, staticSettings{}, workers, imagesDir, t.TempDir(), "")
+	if _, err := service.Variant(context.Background(), "203.0.113.1", 1, 0, "small"); !errors.Is(err, apperrors.ErrUnavailable) {
+		t.Fatalf("expected unavailable, got %v", err)
+	}
+}
+
+func TestPublicImageService_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(nil, nil).Times(3)
+
+	service := NewPublicImageService(mockRepo, nil, staticSettings{SettingPublicImageRate: "2"}, testWorkers(t), t.TempDir(), t.TempDir(), "")
+	for i := 0; i < 2; i++ {
+		if _, err := service.Variant(context.Background(), "203.0.113.1", 1, 0, ""); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Fatalf("request %d: expected not found, got %v", i+1, err)
+		}
+	}
+	if _, err := service.Variant(context.Background(), "203.0.113.1", 1, 0, ""); !errors.Is(err, apperrors.ErrRateLimited) {
+		t.Fatalf("expected rate limited, got %v", err)
+	}
+	// Other clients keep their own budget
+	if _, err := service.Variant(context.Background(), "198.51.100.7", 1, 0, ""); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("expected not found for another client, got %v", err)
+	}
+}
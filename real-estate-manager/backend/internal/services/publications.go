@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Page sizes of the review queue
+const (
+	DefaultReviewLimit = 50
+	MaxReviewLimit     = 200
+)
+
+// maxReviewComment caps a reviewer's comment
+const maxReviewComment = 1000
+
+// PublicationService runs the review workflow that decides which listings
+// are public: agents submit listings for review, admins approve or reject
+// them with a comment, and only approved listings appear in public feeds
+// such as syndicated portals and public images.
+type PublicationService struct {
+	repo       repository.ListingPublicationRepository
+	properties *PropertyService
+	events     EventPublisher
+	now        func() time.Time
+}
+
+// NewPublicationService creates the service; publisher may be nil
+func NewPublicationService(repo repository.ListingPublicationRepository, properties *PropertyService, publisher EventPublisher) *PublicationService {
+	return &PublicationService{repo: repo, properties: properties, events: publisher, now: time.Now}
+}
+
+// Get returns a listing's publication; listings never submitted are drafts
+func (s *PublicationService) Get(ctx context.Context, propertyID int) (*models.ListingPublication, error) {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, propertyID)
+}
+
+// Submit puts a draft or rejected listing in the review queue
+func (s *PublicationService) Submit(ctx context.Context, propertyID int) (*models.ListingPublication, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	publication, err := s.get(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	switch publication.State {
+	case models.PublishPendingReview:
+		return nil, apperrors.Conflict("the listing is already waiting for review")
+	case models.PublishApproved:
+		return nil, apperrors.Conflict("the listing is already published")
+	}
+
+	actorID, _ := ActorFromContext(ctx)
+	*publication = models.ListingPublication{
+		PropertyID:  propertyID,
+		State:       models.PublishPendingReview,
+		SubmittedBy: nullID(int(actorID)),
+		SubmittedAt: nullTime(s.now()),
+	}
+	return publication, s.save(ctx, events.ListingSubmitted, publication, property)
+}
+
+// Approve publishes a listing waiting for review
+func (s *PublicationService) Approve(ctx context.Context, propertyID int, comment string) (*models.ListingPublication, error) {
+	return s.review(ctx, propertyID, models.PublishApproved, comment)
+}
+
+// Reject sends a listing waiting for review back to its agent with a
+// comment saying why
+func (s *PublicationService) Reject(ctx context.Context, propertyID int, comment string) (*models.ListingPublication, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, apperrors.Validation("a comment is required to reject a listing")
+	}
+	return s.review(ctx, propertyID, models.PublishRejected, comment)
+}
+
+// Unpublish takes a listing out of public feeds, or out of the review
+// queue, making it a draft again
+func (s *PublicationService) Unpublish(ctx context.Context, propertyID int) (*models.ListingPublication, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	publication, err := s.get(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if publication.State != models.PublishApproved && publication.State != models.PublishPendingReview {
+		return publication, nil
+	}
+
+	publication.State = models.PublishDraft
+	return publication, s.save(ctx, events.ListingUnpublished, publication, property)
+}
+
+// Queue lists the listings waiting for review, longest waiting first
+func (s *PublicationService) Queue(ctx context.Context, limit int) ([]models.ListingReview, error) {
+	if limit <= 0 {
+		limit = DefaultReviewLimit
+	}
+	if limit > MaxReviewLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", MaxReviewLimit)
+	}
+	return s.repo.ListPending(ctx, limit)
+}
+
+// review records an admin's decision on a listing waiting for review
+func (s *PublicationService) review(ctx context.Context, propertyID int, state, comment string) (*models.ListingPublication, error) {
+	comment = strings.TrimSpace(comment)
+	if len(comment) > maxReviewComment {
+		return nil, apperrors.Validationf("comment must be at most %d characters", maxReviewComment)
+	}
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	publication, err := s.get(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if publication.State != models.PublishPendingReview {
+		return nil, apperrors.Conflict("the listing is not waiting for review")
+	}
+
+	actorID, _ := ActorFromContext(ctx)
+	publication.State = state
+	publication.ReviewedBy = nullID(int(actorID))
+	publication.ReviewedAt = nullTime(s.now())
+	publication.ReviewComment = comment
+	eventType := events.ListingApproved
+	if state == models.PublishRejected {
+		eventType = events.ListingRejected
+	}
+	return publication, s.save(ctx, eventType, publication, property)
+}
+
+func (s *PublicationService) get(ctx context.Context, propertyID int) (*models.ListingPublication, error) {
+	publication, err := s.repo.Get(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if publication == nil {
+		publication = &models.ListingPublication{PropertyID: propertyID, State: models.PublishDraft}
+	}
+	return publication, nil
+}
+
+// save stores publication and announces the change with the listing
+func (s *PublicationService) save(ctx context.Context, eventType string, publication *models.ListingPublication, property *models.Property) error {
+	if err := s.repo.Save(ctx, publication); err != nil {
+		return err
+	}
+	review := &models.ListingReview{ListingPublication: *publication, Property: *property}
+	publishEvent(ctx, s.events, eventType, propertySubject(property.ID), review)
+	return nil
+}
+
+// publiclyListed reports whether a property may appear in public feeds: it
+// is active or pending and its publication was approved
+func publiclyListed(ctx context.Context, publications repository.ListingPublicationRepository, property *models.Property) (bool, error) {
+	if !isPublicStatus(property.Status) {
+		return false, nil
+	}
+	publication, err := publications.Get(ctx, property.ID)
+	if err != nil {
+		return false, err
+	}
+	return publication != nil && publication.State == models.PublishApproved, nil
+}
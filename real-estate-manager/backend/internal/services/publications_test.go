@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublicationService_Submit(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       *models.ListingPublication
+		expectedError error
+	}{
+		{name: "never submitted"},
+		{name: "rejected", current: &models.ListingPublication{PropertyID: 1, State: models.PublishRejected, ReviewComment: "Add photos"}},
+		{name: "already waiting", current: &models.ListingPublication{PropertyID: 1, State: models.PublishPendingReview}, expectedError: apperrors.ErrConflict},
+		{name: "already published", current: &models.ListingPublication{PropertyID: 1, State: models.PublishApproved}, expectedError: apperrors.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1, Name: "Casa"}, nil)
+			mockRepo := mocks.NewMockListingPublicationRepository(ctrl)
+			mockRepo.EXPECT().Get(gomock.Any(), 1).Return(tt.current, nil)
+			if tt.expectedError == nil {
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewPublicationService(mockRepo, NewPropertyService(mockProperties), nil)
+			publication, err := service.Submit(WithActor(context.Background(), 4), 1)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if publication.State != models.PublishPendingReview || publication.SubmittedBy.Int32 != 4 ||
+				!publication.SubmittedAt.Valid || publication.ReviewComment != "" {
+				t.Errorf("Unexpected publication %+v", publication)
+			}
+		})
+	}
+}
+
+func TestPublicationService_Review(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pending := func() *models.ListingPublication {
+		return &models.ListingPublication{PropertyID: 1, State: models.PublishPendingReview}
+	}
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 1).Return(&models.Property{ID: 1, Name: "Casa"}, nil).AnyTimes()
+	mockRepo := mocks.NewMockListingPublicationRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().Get(gomock.Any(), 1).Return(pending(), nil),
+		mockRepo.EXPECT().Get(gomock.Any(), 1).Return(&models.ListingPublication{PropertyID: 1, State: models.PublishApproved}, nil),
+	)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	publisher := &recordingPublisher{}
+	service := NewPublicationService(mockRepo, NewPropertyService(mockProperties), publisher)
+	ctx := WithActor(context.Background(), 2)
+
+	if _, err := service.Reject(ctx, 1, "  "); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for a rejection without a comment, got %v", err)
+	}
+	publication, err := service.Approve(ctx, 1, " Looks good ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if publication.State != models.PublishApproved || publication.ReviewedBy.Int32 != 2 || publication.ReviewComment != "Looks good" {
+		t.Errorf("Unexpected publication %+v", publication)
+	}
+	if _, err := service.Approve(ctx, 1, ""); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected conflict approving a published listing, got %v", err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.ListingApproved || publisher.events[0].Subject != "property/1" {
+		t.Errorf("Expected one approval event, got %+v", publisher.events)
+	}
+}
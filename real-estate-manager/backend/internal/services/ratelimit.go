@@ -0,0 +1,132 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// windowLimiter allows up to limit() events per key within a sliding
+// window. State is kept in memory, so limits apply per server instance.
+// Keys whose events have all left the window are swept at most once per
+// window, so keys seen only once do not accumulate.
+type windowLimiter struct {
+	limit     func() int
+	window    time.Duration
+	mu        sync.Mutex
+	events    map[string][]time.Time
+	lastSweep time.Time
+	// maxKeys bounds how many keys are tracked, 0 for no bound. Once full,
+	// the key idle the longest is forgotten to make room.
+	maxKeys int
+}
+
+func newWindowLimiter(window time.Duration, limit func() int) *windowLimiter {
+	return &windowLimiter{limit: limit, window: window, events: make(map[string][]time.Time)}
+}
+
+// Allow records an event for key and reports whether it is within the limit
+func (l *windowLimiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	recent := l.prune(key, now)
+	if len(recent) >= l.limit() {
+		return false
+	}
+	l.makeRoom(key, now)
+	l.events[key] = append(recent, now)
+	return true
+}
+
+// Exceeded reports whether key has reached the limit without recording an event
+func (l *windowLimiter) Exceeded(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.prune(key, now)) >= l.limit()
+}
+
+// Add records an event for key regardless of the limit
+func (l *windowLimiter) Add(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	recent := l.prune(key, now)
+	l.makeRoom(key, now)
+	l.events[key] = append(recent, now)
+}
+
+// makeRoom evicts the key idle the longest when a new key would exceed
+// maxKeys; callers hold mu
+func (l *windowLimiter) makeRoom(key string, now time.Time) {
+	if _, tracked := l.events[key]; tracked || l.maxKeys <= 0 || len(l.events) < l.maxKeys {
+		return
+	}
+	l.lastSweep = time.Time{}
+	l.sweep(now)
+	if len(l.events) < l.maxKeys {
+		return
+	}
+
+	var idlest string
+	var idleSince time.Time
+	for candidate, events := range l.events {
+		if last := events[len(events)-1]; idlest == "" || last.Before(idleSince) {
+			idlest, idleSince = candidate, last
+		}
+	}
+	delete(l.events, idlest)
+}
+
+// sweep prunes every key once a window has passed since the last sweep;
+// callers hold mu
+func (l *windowLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for key := range l.events {
+		l.prune(key, now)
+	}
+}
+
+// prune drops events older than the window; callers hold mu
+func (l *windowLimiter) prune(key string, now time.Time) []time.Time {
+	cutoff := now.Add(-l.window)
+	recent := l.events[key][:0]
+	for _, at := range l.events[key] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+
+	if len(recent) == 0 {
+		delete(l.events, key)
+		return nil
+	}
+	l.events[key] = recent
+	return recent
+}
+
+// Usage returns how many events key has within the window and when the
+// oldest of them leaves it, which is zero without events
+func (l *windowLimiter) Usage(key string, now time.Time) (int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.prune(key, now)
+	if len(recent) == 0 {
+		return 0, time.Time{}
+	}
+	return len(recent), recent[0].Add(l.window)
+}
+
+// Reset forgets the events of key
+func (l *windowLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.events, key)
+}
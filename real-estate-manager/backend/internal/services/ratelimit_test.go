@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWindowLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newWindowLimiter(time.Minute, func() int { return 2 })
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		expect  bool
+	}{
+		{name: "first event", expect: true},
+		{name: "second event", advance: 10 * time.Second, expect: true},
+		{name: "over the limit", advance: 10 * time.Second, expect: false},
+		{name: "first event left the window", advance: 41 * time.Second, expect: true},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if got := limiter.Allow("key", now); got != step.expect {
+			t.Errorf("%s: expected %v, got %v", step.name, step.expect, got)
+		}
+	}
+}
+
+func TestWindowLimiter_Sweep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newWindowLimiter(time.Minute, func() int { return 5 })
+
+	for i := 0; i < 100; i++ {
+		limiter.Allow(fmt.Sprintf("10.0.0.%d", i), now)
+	}
+	if len(limiter.events) != 100 {
+		t.Fatalf("Expected 100 keys, got %d", len(limiter.events))
+	}
+
+	// Keys not seen again are dropped once their events leave the window
+	limiter.Allow("10.0.1.1", now.Add(30*time.Second))
+	if len(limiter.events) != 101 {
+		t.Errorf("Expected no sweep within the window, got %d keys", len(limiter.events))
+	}
+	limiter.Add("10.0.1.2", now.Add(2*time.Minute))
+	if len(limiter.events) != 1 {
+		t.Errorf("Expected only the latest key to be kept, got %d keys", len(limiter.events))
+	}
+}
+
+func TestWindowLimiter_MaxKeys(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newWindowLimiter(time.Minute, func() int { return 1 })
+	limiter.maxKeys = 3
+
+	for i, key := range []string{"a", "b", "c"} {
+		limiter.Allow(key, now.Add(time.Duration(i)*time.Second))
+	}
+	if limiter.Allow("b", now.Add(5*time.Second)) {
+		t.Error("Expected a tracked key to stay limited")
+	}
+
+	// A new key evicts the one idle the longest instead of growing the map
+	if !limiter.Allow("d", now.Add(10*time.Second)) {
+		t.Error("Expected a new key to be allowed")
+	}
+	if len(limiter.events) != 3 {
+		t.Fatalf("Expected 3 keys, got %d", len(limiter.events))
+	}
+	if _, tracked := limiter.events["a"]; tracked {
+		t.Errorf("Expected the idlest key to be evicted, got %v", limiter.events)
+	}
+	if limiter.Allow("c", now.Add(11*time.Second)) {
+		t.Error("Expected keys kept to stay limited")
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"real-estate-manager/backend/internal/alerts"
+)
+
+// Pinger checks a connection; *sql.DB satisfies it
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// ReadinessService reports whether the server can handle requests. The
+// first failed check after a healthy one raises an ops alert.
+type ReadinessService struct {
+	db      Pinger
+	alerts  Alerter
+	mu      sync.Mutex
+	failing bool
+}
+
+func NewReadinessService(db Pinger, alerter Alerter) *ReadinessService {
+	return &ReadinessService{db: db, alerts: alerter}
+}
+
+// Check pings the dependencies requests cannot be served without
+func (s *ReadinessService) Check(ctx context.Context) error {
+	err := s.db.PingContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("database unreachable: %w", err)
+	}
+
+	s.mu.Lock()
+	wasFailing := s.failing
+	s.failing = err != nil
+	s.mu.Unlock()
+
+	if err != nil && !wasFailing {
+		raiseAlert(s.alerts, alerts.ReadinessFailed, "Readiness probe failing", map[string]string{"error": err.Error()})
+	}
+	return err
+}
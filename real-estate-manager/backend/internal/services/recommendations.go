@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Recommendation tuning. Up to recommendationSeedViews recently viewed
+// properties count, each less than a favorite; listings scoring below
+// recommendationMinScore are not recommended.
+const (
+	recommendationLimit     = 20
+	recommendationSeedViews = 20
+	recommendationViewScale = 0.8
+	recommendationMinScore  = 0.3
+	// With both similar listings and saved searches to go by, this share
+	// of the score comes from matching a saved search
+	recommendationSearchShare = 0.4
+)
+
+// RecommendationService recommends listings to users that are similar to
+// their favorites and the properties they viewed, or that match their
+// saved searches. Recommendations are computed ahead of time by Refresh.
+type RecommendationService struct {
+	repo      repository.RecommendationRepository
+	favorites repository.FavoriteRepository
+	views     repository.ViewRepository
+	searches  repository.SavedSearchRepository
+	now       func() time.Time
+}
+
+func NewRecommendationService(repo repository.RecommendationRepository, favorites repository.FavoriteRepository, views repository.ViewRepository, searches repository.SavedSearchRepository) *RecommendationService {
+	return &RecommendationService{repo: repo, favorites: favorites, views: views, searches: searches, now: time.Now}
+}
+
+// For returns a user's recommendations, best first. A user the scheduled
+// refresh has not reached yet gets theirs computed now.
+func (s *RecommendationService) For(ctx context.Context, userID uint, limit int) ([]models.Recommendation, error) {
+	if limit <= 0 {
+		limit = recommendationLimit
+	}
+	if limit > recommendationLimit {
+		return nil, apperrors.Validationf("limit must be at most %d", recommendationLimit)
+	}
+	recommendations, err := s.repo.List(ctx, userID, limit)
+	if err != nil || len(recommendations) > 0 {
+		return recommendations, err
+	}
+
+	computed, err := s.refreshUser(ctx, userID, func() ([]models.Property, error) {
+		return s.repo.ListCandidates(ctx)
+	})
+	if err != nil || computed == 0 {
+		return recommendations, err
+	}
+	return s.repo.List(ctx, userID, limit)
+}
+
+// Refresh recomputes the recommendations of every user with favorites,
+// viewed properties, saved searches or earlier recommendations and returns
+// how many users it updated. A user whose recommendations fail is logged
+// and skipped.
+func (s *RecommendationService) Refresh(ctx context.Context) (int, error) {
+	users, err := s.repo.ListUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	candidates, err := s.repo.ListCandidates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, userID := range users {
+		if err := ctx.Err(); err != nil {
+			return refreshed, err
+		}
+		if _, err := s.refreshUser(ctx, userID, func() ([]models.Property, error) { return candidates, nil }); err != nil {
+			log.Printf("Failed to refresh recommendations of user %d: %v", userID, err)
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// recommendationSeed is a property the user showed interest in
+type recommendationSeed struct {
+	property *models.Property
+	weight   float64
+	reason   string
+}
+
+// refreshUser computes and stores a user's recommendations from the
+// candidate listings and returns how many there are. Candidates are only
+// loaded when the user has something to base recommendations on; without
+// that, their recommendations are cleared.
+func (s *RecommendationService) refreshUser(ctx context.Context, userID uint, loadCandidates func() ([]models.Property, error)) (int, error) {
+	favorites, err := s.favorites.List(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	viewed, err := s.views.ListRecent(ctx, userID, recommendationSeedViews)
+	if err != nil {
+		return 0, err
+	}
+	searches, err := s.searches.List(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := map[int]bool{}
+	var seeds []recommendationSeed
+	for i := range favorites {
+		seen[favorites[i].Property.ID] = true
+		seeds = append(seeds, recommendationSeed{property: &favorites[i].Property, weight: 1,
+			reason: fmt.Sprintf("Similar to your favorite %q", favorites[i].Property.Name)})
+	}
+	for i := range viewed {
+		if seen[viewed[i].Property.ID] {
+			continue
+		}
+		seen[viewed[i].Property.ID] = true
+		seeds = append(seeds, recommendationSeed{property: &viewed[i].Property, weight: recommendationViewScale,
+			reason: fmt.Sprintf("Similar to %q, which you viewed", viewed[i].Property.Name)})
+	}
+
+	var candidates []models.Property
+	if len(seeds) > 0 || len(searches) > 0 {
+		if candidates, err = loadCandidates(); err != nil {
+			return 0, err
+		}
+	}
+
+	now := s.now()
+	recommendations := []models.Recommendation{}
+	for i := range candidates {
+		candidate := &candidates[i]
+		if seen[candidate.ID] {
+			continue
+		}
+		score, reasons := recommendationScore(candidate, seeds, searches)
+		if score < recommendationMinScore {
+			continue
+		}
+		recommendations = append(recommendations, models.Recommendation{PropertyID: candidate.ID,
+			Score: math.Round(score*10000) / 10000, Reasons: reasons, ComputedAt: now})
+	}
+	// Newer listings first among equal scores
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].PropertyID > recommendations[j].PropertyID
+	})
+	if len(recommendations) > recommendationLimit {
+		recommendations = recommendations[:recommendationLimit]
+	}
+
+	if err := s.repo.Replace(ctx, userID, recommendations); err != nil {
+		return 0, err
+	}
+	return len(recommendations), nil
+}
+
+// recommendationScore rates a listing for a user from 0 to 1: its best
+// weighted similarity to a seed, blended with whether it matches one of
+// their saved searches
+func recommendationScore(candidate *models.Property, seeds []recommendationSeed, searches []models.SavedSearch) (float64, models.StringList) {
+	reasons := models.StringList{}
+	similarity, closest := 0.0, ""
+	for _, seed := range seeds {
+		if score := listingSimilarity(seed.property, candidate) * seed.weight; score > similarity {
+			similarity, closest = score, seed.reason
+		}
+	}
+	if similarity >= recommendationMinScore {
+		reasons = append(reasons, closest)
+	}
+
+	matched := 0.0
+	for _, search := range searches {
+		if search.Criteria.Matches(candidate) {
+			matched = 1
+			reasons = append(reasons, fmt.Sprintf("Matches your saved search %q", search.Name))
+		}
+	}
+
+	switch {
+	case len(seeds) > 0 && len(searches) > 0:
+		return (1-recommendationSearchShare)*similarity + recommendationSearchShare*matched, reasons
+	case len(seeds) > 0:
+		return similarity, reasons
+	default:
+		return matched, reasons
+	}
+}
+
+// listingSimilarity rates from 0 to 1 how alike two listings are by area,
+// price, type and bedrooms. Land is compared by acreage instead, and is not
+// alike any building. Unknown types, bedrooms and acreages count half.
+func listingSimilarity(a, b *models.Property) float64 {
+	area := 0.0
+	cityA, zipA := marketAreas(a.Location)
+	cityB, zipB := marketAreas(b.Location)
+	switch {
+	case zipA != "" && zipA == zipB:
+		area = 1
+	case cityA != "" && strings.EqualFold(cityA, cityB):
+		area = 0.7
+	case locality(a.Location) != "" && strings.EqualFold(locality(a.Location), locality(b.Location)):
+		area = 0.7
+	}
+
+	price := 0.0
+	if a.Price > 0 {
+		price = math.Max(0, 1-math.Abs(b.Price-a.Price)/a.Price)
+	}
+
+	propertyType := 0.5
+	if a.PropertyType.Valid && b.PropertyType.Valid {
+		propertyType = 0
+		if strings.EqualFold(a.PropertyType.String, b.PropertyType.String) {
+			propertyType = 1
+		}
+	}
+
+	size := 0.5
+	switch landA := a.Category == models.CategoryLand; {
+	case landA != (b.Category == models.CategoryLand):
+		size = 0
+	case landA:
+		if a.Acreage != nil && b.Acreage != nil && *a.Acreage > 0 && *b.Acreage > 0 {
+			size = math.Min(*a.Acreage, *b.Acreage) / math.Max(*a.Acreage, *b.Acreage)
+		}
+	case a.Bedrooms.Valid && b.Bedrooms.Valid:
+		size = 1 / (1 + math.Abs(float64(a.Bedrooms.Int32-b.Bedrooms.Int32)))
+	}
+
+	return 0.3*area + 0.3*price + 0.2*propertyType + 0.2*size
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func listing(id int, location string, price float64, bedrooms int32, propertyType string) models.Property {
+	return models.Property{ID: id, Name: location, Location: location, Price: price, Status: models.PropertyStatusActive,
+		Bedrooms:     models.NullInt32{NullInt32: sql.NullInt32{Int32: bedrooms, Valid: true}},
+		PropertyType: models.NullString{NullString: sql.NullString{String: propertyType, Valid: true}}}
+}
+
+func TestRecommendationService_For(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	favorite := listing(1, "1 Elm St, Austin, TX 78701", 400000, 3, "Residential")
+	viewed := listing(2, "2 Elm St, Austin, TX 78701", 420000, 3, "Residential")
+	candidates := []models.Property{
+		favorite, viewed,
+		listing(3, "3 Oak St, Austin, TX 78701", 410000, 3, "Residential"),
+		listing(4, "4 Pine St, Dallas, TX 75201", 1500000, 6, "Commercial"),
+		listing(5, "5 Bay St, Miami, FL 33101", 200000, 1, "Condo"),
+	}
+	maxPrice := 250000.0
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRecommendationRepository(ctrl)
+	mockFavorites := mocks.NewMockFavoriteRepository(ctrl)
+	mockViews := mocks.NewMockViewRepository(ctrl)
+	mockSearches := mocks.NewMockSavedSearchRepository(ctrl)
+
+	// Nothing computed yet, so the recommendations are computed on demand
+	gomock.InOrder(
+		mockRepo.EXPECT().List(gomock.Any(), uint(4), 20).Return([]models.Recommendation{}, nil),
+		mockRepo.EXPECT().List(gomock.Any(), uint(4), 20).Return([]models.Recommendation{{PropertyID: 3}}, nil),
+	)
+	mockFavorites.EXPECT().List(gomock.Any(), uint(4)).Return([]models.Favorite{{Property: favorite}}, nil)
+	mockViews.EXPECT().ListRecent(gomock.Any(), uint(4), recommendationSeedViews).Return([]models.RecentlyViewed{{Property: viewed}}, nil)
+	mockSearches.EXPECT().List(gomock.Any(), uint(4)).Return([]models.SavedSearch{
+		{Name: "Cheap condos", Criteria: models.SavedSearchCriteria{PropertyType: "condo", MaxPrice: &maxPrice}},
+	}, nil)
+	mockRepo.EXPECT().ListCandidates(gomock.Any()).Return(candidates, nil)
+	var stored []models.Recommendation
+	mockRepo.EXPECT().Replace(gomock.Any(), uint(4), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userID uint, recommendations []models.Recommendation) error {
+			stored = recommendations
+			return nil
+		})
+
+	service := NewRecommendationService(mockRepo, mockFavorites, mockViews, mockSearches)
+	service.now = func() time.Time { return now }
+	recommendations, err := service.For(context.Background(), 4, 0)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(recommendations) != 1 {
+		t.Errorf("Expected the stored recommendations, got %+v", recommendations)
+	}
+
+	// The favorite and the viewed property are not recommended back, and
+	// the listing like neither nor the saved search is left out
+	if len(stored) != 2 || stored[0].PropertyID != 3 || stored[1].PropertyID != 5 {
+		t.Fatalf("Expected properties 3 and 5, got %+v", stored)
+	}
+	if stored[0].Reasons[0] != `Similar to your favorite "1 Elm St, Austin, TX 78701"` {
+		t.Errorf("Unexpected reasons: %v", stored[0].Reasons)
+	}
+	if len(stored[1].Reasons) != 1 || stored[1].Reasons[0] != `Matches your saved search "Cheap condos"` {
+		t.Errorf("Unexpected reasons: %v", stored[1].Reasons)
+	}
+}
+
+func TestRecommendationService_RefreshClearsUsersWithoutSignals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRecommendationRepository(ctrl)
+	mockFavorites := mocks.NewMockFavoriteRepository(ctrl)
+	mockViews := mocks.NewMockViewRepository(ctrl)
+	mockSearches := mocks.NewMockSavedSearchRepository(ctrl)
+
+	mockRepo.EXPECT().ListUsers(gomock.Any()).Return([]uint{7}, nil)
+	mockRepo.EXPECT().ListCandidates(gomock.Any()).Return([]models.Property{listing(3, "3 Oak St, Austin, TX", 1, 1, "")}, nil)
+	mockFavorites.EXPECT().List(gomock.Any(), uint(7)).Return([]models.Favorite{}, nil)
+	mockViews.EXPECT().ListRecent(gomock.Any(), uint(7), recommendationSeedViews).Return([]models.RecentlyViewed{}, nil)
+	mockSearches.EXPECT().List(gomock.Any(), uint(7)).Return([]models.SavedSearch{}, nil)
+	mockRepo.EXPECT().Replace(gomock.Any(), uint(7), []models.Recommendation{}).Return(nil)
+
+	service := NewRecommendationService(mockRepo, mockFavorites, mockViews, mockSearches)
+	count, err := service.Refresh(context.Background())
+	if err != nil || count != 1 {
+		t.Errorf("Expected 1 user refreshed, got %d, %v", count, err)
+	}
+}
+
+func TestListingSimilarity_Land(t *testing.T) {
+	parcel := func(acreage float64) *models.Property {
+		return &models.Property{Location: "Ranch Rd, Austin, TX 78737", Price: 200000, Category: models.CategoryLand, Acreage: &acreage}
+	}
+	house := listing(1, "1 Elm St, Austin, TX 78737", 200000, 3, "")
+
+	same := listingSimilarity(parcel(10), parcel(10))
+	larger := listingSimilarity(parcel(10), parcel(40))
+	if same <= larger {
+		t.Errorf("Expected parcels of the same acreage to be more alike, got %v and %v", same, larger)
+	}
+	if building := listingSimilarity(parcel(10), &house); building >= larger {
+		t.Errorf("Expected land to be less alike a house than any parcel, got %v", building)
+	}
+}
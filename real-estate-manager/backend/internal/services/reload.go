@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// ReloadFunc re-reads one part of the configuration and applies it
+type ReloadFunc func(ctx context.Context) error
+
+// ReloadResult is the outcome of reloading one part of the configuration
+type ReloadResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+type reloadPart struct {
+	name   string
+	reload ReloadFunc
+}
+
+// ReloadService reloads the configuration that can change while the
+// server runs, on SIGHUP or through the admin API. Nothing is restarted,
+// so requests and import jobs in flight are unaffected.
+type ReloadService struct {
+	mu    sync.Mutex
+	parts []reloadPart
+}
+
+func NewReloadService() *ReloadService {
+	return &ReloadService{}
+}
+
+// Register adds a part of the configuration. Parts are reloaded in the
+// order they were registered.
+func (s *ReloadService) Register(name string, reload ReloadFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parts = append(s.parts, reloadPart{name: name, reload: reload})
+}
+
+// Reload reloads every part. A part that fails keeps its previous
+// configuration and does not stop the others from reloading. Reloads never
+// overlap.
+func (s *ReloadService) Reload(ctx context.Context) []ReloadResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]ReloadResult, 0, len(s.parts))
+	for _, part := range s.parts {
+		result := ReloadResult{Name: part.name}
+		if err := part.reload(ctx); err != nil {
+			log.Printf("Failed to reload %s: %v", part.name, err)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReloadService_Reload(t *testing.T) {
+	service := NewReloadService()
+	var order []string
+	service.Register("log_level", func(ctx context.Context) error {
+		order = append(order, "log_level")
+		return errors.New(`invalid log level "loud"`)
+	})
+	service.Register("settings", func(ctx context.Context) error {
+		order = append(order, "settings")
+		return nil
+	})
+
+	results := service.Reload(context.Background())
+	if len(order) != 2 || order[0] != "log_level" || order[1] != "settings" {
+		t.Errorf("Expected every part to reload in registration order, got %v", order)
+	}
+	if len(results) != 2 || results[0].Error == "" || results[1].Error != "" {
+		t.Errorf("Expected only the failing part to report an error, got %+v", results)
+	}
+}
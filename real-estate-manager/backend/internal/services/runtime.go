@@ -0,0 +1,86 @@
+package services
+
+import (
+	"database/sql"
+	"runtime"
+
+	"real-estate-manager/backend/internal/worker"
+)
+
+// RuntimeStats is a snapshot of the process for profiling in production
+type RuntimeStats struct {
+	Goroutines int            `json:"goroutines"`
+	ImportJobs int            `json:"import_jobs"`
+	Heap       HeapStats      `json:"heap"`
+	Database   DatabaseStats  `json:"database"`
+	Workers    []worker.Stats `json:"workers"`
+}
+
+// HeapStats is the memory allocated by the process and the garbage
+// collector's work
+type HeapStats struct {
+	AllocBytes      uint64  `json:"alloc_bytes"`
+	SysBytes        uint64  `json:"sys_bytes"`
+	Objects         uint64  `json:"objects"`
+	GCCycles        uint32  `json:"gc_cycles"`
+	GCPauseTotalMs  float64 `json:"gc_pause_total_ms"`
+	NextGCBytes     uint64  `json:"next_gc_bytes"`
+	TotalAllocBytes uint64  `json:"total_alloc_bytes"`
+}
+
+// DatabaseStats is the state of the database connection pool. WaitCount
+// and WaitMs grow while requests queue for a connection.
+type DatabaseStats struct {
+	MaxOpen   int     `json:"max_open"`
+	Open      int     `json:"open"`
+	InUse     int     `json:"in_use"`
+	Idle      int     `json:"idle"`
+	WaitCount int64   `json:"wait_count"`
+	WaitMs    float64 `json:"wait_ms"`
+}
+
+// RuntimeService reports goroutines, heap, database pool and worker pool
+// usage, e.g. to see what a slow import job is doing
+type RuntimeService struct {
+	db    *sql.DB
+	jobs  *JobManager
+	pools []*worker.Pool
+}
+
+func NewRuntimeService(db *sql.DB, jobs *JobManager, pools ...*worker.Pool) *RuntimeService {
+	return &RuntimeService{db: db, jobs: jobs, pools: pools}
+}
+
+// Stats takes a snapshot. Reading the heap statistics briefly stops the
+// world, so it is not meant to be polled often.
+func (s *RuntimeService) Stats() *RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := &RuntimeStats{
+		Goroutines: runtime.NumGoroutine(),
+		ImportJobs: s.jobs.Running(),
+		Heap: HeapStats{
+			AllocBytes:      mem.HeapAlloc,
+			SysBytes:        mem.Sys,
+			Objects:         mem.HeapObjects,
+			GCCycles:        mem.NumGC,
+			GCPauseTotalMs:  float64(mem.PauseTotalNs) / 1e6,
+			NextGCBytes:     mem.NextGC,
+			TotalAllocBytes: mem.TotalAlloc,
+		},
+		Workers: make([]worker.Stats, len(s.pools)),
+	}
+	db := s.db.Stats()
+	stats.Database = DatabaseStats{
+		MaxOpen:   db.MaxOpenConnections,
+		Open:      db.OpenConnections,
+		InUse:     db.InUse,
+		Idle:      db.Idle,
+		WaitCount: db.WaitCount,
+		WaitMs:    float64(db.WaitDuration.Microseconds()) / 1000,
+	}
+	for i, pool := range s.pools {
+		stats.Workers[i] = pool.Stats()
+	}
+	return stats
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/worker"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRuntimeService_Stats(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(10)
+
+	jobs := NewJobManager()
+	jobs.AddJob("running", &ProcessingJob{ID: "running", Status: make(chan models.ProcessingStatus, 1)})
+	jobs.AddJob("done", &ProcessingJob{ID: "done", Status: make(chan models.ProcessingStatus, 1)})
+	jobs.MarkJobCompleted("done", models.ProcessingStatus{Status: "completed"})
+
+	pool := worker.New("images", 2, 8)
+	defer pool.Stop(t.Context())
+
+	stats := NewRuntimeService(db, jobs, pool).Stats()
+	if stats.Goroutines == 0 || stats.Heap.AllocBytes == 0 {
+		t.Errorf("Expected goroutines and heap usage, got %+v", stats)
+	}
+	if stats.ImportJobs != 1 {
+		t.Errorf("Expected 1 running import job, got %d", stats.ImportJobs)
+	}
+	if stats.Database.MaxOpen != 10 || stats.Database.InUse != 0 {
+		t.Errorf("Unexpected database stats %+v", stats.Database)
+	}
+	if len(stats.Workers) != 1 || stats.Workers[0].Name != "images" || stats.Workers[0].Workers != 2 {
+		t.Errorf("Unexpected worker stats %+v", stats.Workers)
+	}
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Scopes limit what a service-account token may do, on top of the
+// account's role
+const (
+	ScopeReadProperties = "read:properties"
+	ScopeRunSync        = "run:sync"
+)
+
+// scopePermissions maps each scope to the permissions it unlocks
+var scopePermissions = map[string][]string{
+	ScopeReadProperties: {PermPropertiesRead},
+	ScopeRunSync:        {PermJobsRun, PermJobsRead},
+}
+
+const (
+	// DefaultServiceTokenTTL applies when no expiry is requested
+	DefaultServiceTokenTTL = 90 * 24 * time.Hour
+	MaxServiceTokenTTL     = 365 * 24 * time.Hour
+)
+
+var serviceAccountNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{2,49}$`)
+
+// ScopesAllow reports whether any of scopes unlocks permission
+func ScopesAllow(scopes []string, permission string) bool {
+	for _, scope := range scopes {
+		for _, granted := range scopePermissions[scope] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServiceAccountService manages automation accounts and issues their scoped
+// tokens. Every token issued is audited.
+type ServiceAccountService struct {
+	repo        repository.ServiceAccountRepository
+	users       repository.UserRepository
+	auth        *AuthService
+	permissions *PermissionService
+	audit       *AuditService
+}
+
+func NewServiceAccountService(repo repository.ServiceAccountRepository, users repository.UserRepository, auth *AuthService,
+	permissions *PermissionService, audit *AuditService) *ServiceAccountService {
+	return &ServiceAccountService{repo: repo, users: users, auth: auth, permissions: permissions, audit: audit}
+}
+
+// Scopes lists the scopes a token can carry and the permissions each unlocks
+func (s *ServiceAccountService) Scopes() map[string][]string {
+	return scopePermissions
+}
+
+func (s *ServiceAccountService) List(ctx context.Context) ([]models.ServiceAccount, error) {
+	accounts, err := s.repo.List(ctx)
+	if accounts == nil {
+		accounts = []models.ServiceAccount{}
+	}
+	return accounts, err
+}
+
+// Create adds a service account. It gets a random password nobody knows, so
+// it can only authenticate with tokens issued by IssueToken. Role defaults
+// to user; admin is refused.
+func (s *ServiceAccountService) Create(ctx context.Context, request models.CreateServiceAccountRequest, createdBy string) (*models.ServiceAccount, error) {
+	if !serviceAccountNamePattern.MatchString(request.Username) {
+		return nil, apperrors.Validation("username must be 3-50 lowercase letters, digits, '.', '-' or '_'")
+	}
+	if request.OrganizationID < 0 {
+		return nil, apperrors.Validation("organization_id must not be negative")
+	}
+	role := request.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+	if role == models.RoleAdmin {
+		return nil, apperrors.Validation("service accounts cannot have the admin role")
+	}
+	if !s.permissions.RoleExists(role, request.OrganizationID) {
+		return nil, apperrors.Validationf("unknown role %q", role)
+	}
+	if existing, _ := s.users.GetByUsername(ctx, request.Username); existing != nil {
+		return nil, apperrors.Conflict("user already exists")
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.ServiceAccount{
+		Username:       request.Username,
+		Role:           role,
+		OrganizationID: nullID(request.OrganizationID),
+		Description:    request.Description,
+		CreatedBy:      createdBy,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.repo.Create(ctx, account, string(hash)); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// IssueToken signs a token for the service account carrying scopes, valid
+// for ttl (DefaultServiceTokenTTL when zero). The token also carries the
+// account's current role, which still applies.
+func (s *ServiceAccountService) IssueToken(ctx context.Context, userID int, scopes []string, ttl time.Duration, adminID uint, requestID string) (*models.ServiceToken, error) {
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		ttl = DefaultServiceTokenTTL
+	}
+	if ttl < time.Minute || ttl > MaxServiceTokenTTL {
+		return nil, apperrors.Validation("expires_in must be between 1m and 8760h")
+	}
+
+	account, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("service account not found")
+	}
+	user, err := s.users.GetByID(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == models.RoleAdmin {
+		return nil, apperrors.Forbidden("service accounts with the admin role cannot be issued tokens")
+	}
+
+	claims := userClaims(user, ttl)
+	claims["scopes"] = scopes
+	claims["service_account"] = true
+	expiresAt := time.Unix(claims["exp"].(int64), 0).UTC()
+
+	details, _ := json.Marshal(map[string]interface{}{"scopes": scopes, "expires_at": expiresAt})
+	entry := &models.AuditEntry{
+		Action:     models.AuditServiceTokenIssued,
+		ActorID:    nullID(int(adminID)),
+		TargetType: nullString("user"),
+		TargetID:   nullString(strconv.Itoa(userID)),
+		Details:    details,
+		RequestID:  nullString(requestID),
+	}
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	token, err := s.auth.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ServiceToken{Token: token, Scopes: scopes, ExpiresAt: expiresAt}, nil
+}
+
+// normalizeScopes validates scopes and returns them sorted without duplicates
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, apperrors.Validation("at least one scope is required")
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if _, ok := scopePermissions[scope]; !ok {
+			return nil, apperrors.Validationf("unknown scope %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
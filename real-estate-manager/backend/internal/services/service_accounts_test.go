@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestServiceAccountService_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     models.CreateServiceAccountRequest
+		setupMock   func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository)
+		expectRole  string
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:    "defaults to the user role",
+			request: models.CreateServiceAccountRequest{Username: "nightly-export"},
+			setupMock: func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository) {
+				users.EXPECT().GetByUsername(gomock.Any(), "nightly-export").Return(nil, sql.ErrNoRows)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, account *models.ServiceAccount, passwordHash string) error {
+						if passwordHash == "" {
+							t.Error("Expected a password hash")
+						}
+						account.UserID = 12
+						return nil
+					})
+			},
+			expectRole: models.RoleUser,
+		},
+		{
+			name:        "admin role refused",
+			request:     models.CreateServiceAccountRequest{Username: "bi-pull", Role: models.RoleAdmin},
+			setupMock:   func(*mocks.MockServiceAccountRepository, *mocks.MockUserRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "unknown role",
+			request:     models.CreateServiceAccountRequest{Username: "bi-pull", Role: "auditor"},
+			setupMock:   func(*mocks.MockServiceAccountRepository, *mocks.MockUserRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "invalid username",
+			request:     models.CreateServiceAccountRequest{Username: "Nightly Export"},
+			setupMock:   func(*mocks.MockServiceAccountRepository, *mocks.MockUserRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:    "username taken",
+			request: models.CreateServiceAccountRequest{Username: "alice"},
+			setupMock: func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository) {
+				users.EXPECT().GetByUsername(gomock.Any(), "alice").Return(&models.User{ID: 3}, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockServiceAccountRepository(ctrl)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			tt.setupMock(mockRepo, mockUsers)
+
+			service := NewServiceAccountService(mockRepo, mockUsers, NewAuthServiceWithSecret(mockUsers, "secret"),
+				NewPermissionService(mocks.NewMockRolePermissionRepository(ctrl), mockUsers), nil)
+			account, err := service.Create(context.Background(), tt.request, "admin")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if account.UserID != 12 || account.Role != tt.expectRole || account.CreatedBy != "admin" {
+				t.Errorf("Unexpected account: %+v", account)
+			}
+		})
+	}
+}
+
+func TestServiceAccountService_IssueToken(t *testing.T) {
+	account := &models.ServiceAccount{UserID: 12, Username: "nightly-export", Role: models.RoleUser}
+
+	tests := []struct {
+		name        string
+		scopes      []string
+		ttl         time.Duration
+		setupMock   func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository, audit *mocks.MockAuditRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:   "issues audited scoped token",
+			scopes: []string{ScopeRunSync, ScopeReadProperties, ScopeRunSync},
+			setupMock: func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				repo.EXPECT().GetByUserID(gomock.Any(), 12).Return(account, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(12)).Return(&models.User{ID: 12, Username: "nightly-export", Role: models.RoleUser}, nil)
+				audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.AuditEntry) error {
+					if entry.Action != models.AuditServiceTokenIssued || entry.TargetID.String != "12" || entry.ActorID.Int32 != 1 {
+						t.Errorf("Unexpected audit entry: %+v", entry)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:        "unknown scope",
+			scopes:      []string{"delete:everything"},
+			setupMock:   func(*mocks.MockServiceAccountRepository, *mocks.MockUserRepository, *mocks.MockAuditRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "expiry too long",
+			scopes:      []string{ScopeReadProperties},
+			ttl:         2 * MaxServiceTokenTTL,
+			setupMock:   func(*mocks.MockServiceAccountRepository, *mocks.MockUserRepository, *mocks.MockAuditRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:   "not a service account",
+			scopes: []string{ScopeReadProperties},
+			setupMock: func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				repo.EXPECT().GetByUserID(gomock.Any(), 12).Return(nil, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrNotFound,
+		},
+		{
+			name:   "account promoted to admin",
+			scopes: []string{ScopeReadProperties},
+			setupMock: func(repo *mocks.MockServiceAccountRepository, users *mocks.MockUserRepository, audit *mocks.MockAuditRepository) {
+				repo.EXPECT().GetByUserID(gomock.Any(), 12).Return(account, nil)
+				users.EXPECT().GetByID(gomock.Any(), uint(12)).Return(&models.User{ID: 12, Role: models.RoleAdmin}, nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockServiceAccountRepository(ctrl)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockAudit := mocks.NewMockAuditRepository(ctrl)
+			tt.setupMock(mockRepo, mockUsers, mockAudit)
+
+			auth := NewAuthServiceWithSecret(mockUsers, "secret")
+			service := NewServiceAccountService(mockRepo, mockUsers, auth,
+				NewPermissionService(mocks.NewMockRolePermissionRepository(ctrl), mockUsers), NewAuditService(mockAudit))
+			token, err := service.IssueToken(context.Background(), 12, tt.scopes, tt.ttl, 1, "req-1")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expectScopes := []string{ScopeReadProperties, ScopeRunSync}
+			if !reflect.DeepEqual(token.Scopes, expectScopes) {
+				t.Errorf("Expected scopes %v, got %v", expectScopes, token.Scopes)
+			}
+			if time.Until(token.ExpiresAt) < DefaultServiceTokenTTL-time.Minute {
+				t.Errorf("Expected default expiry, got %v", token.ExpiresAt)
+			}
+			claims, err := auth.ValidateToken(token.Token)
+			if err != nil {
+				t.Fatalf("Expected a valid JWT, got %v", err)
+			}
+			if (*claims)["service_account"] != true || len((*claims)["scopes"].([]interface{})) != 2 {
+				t.Errorf("Unexpected claims: %v", *claims)
+			}
+		})
+	}
+}
+
+func TestPermissionService_AuthorizeScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewPermissionService(mocks.NewMockRolePermissionRepository(ctrl), mocks.NewMockUserRepository(ctrl))
+	ctx := WithPrincipal(context.Background(), Principal{Role: models.RoleUser, Scopes: []string{ScopeReadProperties}})
+
+	if err := service.Authorize(ctx, PermPropertiesRead); err != nil {
+		t.Errorf("Expected read to be allowed, got %v", err)
+	}
+	if err := service.Authorize(ctx, PermPropertiesDelete); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected delete outside the token scope to be forbidden, got %v", err)
+	}
+
+	// Scopes never widen the role
+	viewer := WithPrincipal(context.Background(), Principal{Role: RoleViewer, Scopes: []string{ScopeRunSync}})
+	if err := service.Authorize(viewer, PermJobsRun); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected the viewer role to still apply, got %v", err)
+	}
+}
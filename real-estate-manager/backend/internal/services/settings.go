@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/crm"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Runtime settings that can be tuned through the admin API
+const (
+	SettingSyncSchedule      = "sync_schedule"
+	SettingSyncDefaultLimit  = "sync_default_limit"
+	SettingImportBatchSize   = "import_batch_size"
+	SettingImageQuality      = "image_quality"
+	SettingJobRetention      = "job_retention"
+	SettingStaleAfterDays    = "stale_after_days"
+	SettingStaleNotify       = "stale_notify_agents"
+	SettingEnrichmentDays    = "enrichment_refresh_days"
+	SettingUserQuotaMB       = "storage_quota_user_mb"
+	SettingOrgQuotaMB        = "storage_quota_org_mb"
+	SettingMagicLinkTTL      = "magic_link_ttl"
+	SettingMagicLinkLimit    = "magic_link_hourly_limit"
+	SettingPasswordResetTTL  = "password_reset_ttl"
+	SettingPasswordResetRate = "password_reset_hourly_limit"
+	SettingCaptchaFailures   = "captcha_after_failures"
+	SettingPublicImageRate   = "public_images_per_minute"
+	SettingAlertEvents       = "ops_alert_events"
+	SettingCRMFieldMap       = "crm_field_map"
+	SettingImportJobsHourly  = "import_jobs_per_hour"
+	SettingImportJobsDaily   = "import_jobs_per_day"
+	SettingExpiryNoticeDays  = "expiry_notice_days"
+	SettingExportWatermark   = "export_watermark"
+	SettingIPDenylist        = "ip_denylist"
+	SettingAdminIPAllowlist  = "admin_ip_allowlist"
+	SettingQualityZeroPrice  = "quality_zero_price_max"
+	SettingQualityDuplicate  = "quality_duplicate_address_max"
+	SettingQualityNoPhotos   = "quality_missing_photos_max"
+	SettingQualityGeocode    = "quality_geocode_failed_max"
+	SettingArchiveAfterYears = "archive_after_years"
+)
+
+// SettingsProvider is the read side of runtime settings used by services
+type SettingsProvider interface {
+	GetString(name string) string
+	GetInt(name string) int
+	GetDuration(name string) time.Duration
+	GetBool(name string) bool
+}
+
+type settingDefinition struct {
+	defaultValue string
+	validate     func(value string) error
+}
+
+var settingDefinitions = map[string]settingDefinition{
+	// Cron expression of automatic imports; empty falls back to
+	// SIMPLYRETS_SYNC_CRON and "off" disables them
+	SettingSyncSchedule:     {defaultValue: "", validate: validateSyncSchedule},
+	SettingSyncDefaultLimit: {defaultValue: "50", validate: validateIntRange(1, 500)},
+	SettingImportBatchSize:  {defaultValue: "10", validate: validateIntRange(1, 100)},
+	SettingImageQuality:     {defaultValue: "85", validate: validateIntRange(1, 100)},
+	SettingJobRetention:     {defaultValue: "5m", validate: validateDurationRange(time.Minute, 7*24*time.Hour)},
+	SettingStaleAfterDays:   {defaultValue: "30", validate: validateIntRange(1, 365)},
+	SettingStaleNotify:      {defaultValue: "false", validate: validateBool},
+	SettingEnrichmentDays:   {defaultValue: "30", validate: validateIntRange(1, 365)},
+	// Storage quotas in megabytes; 0 disables the quota
+	SettingUserQuotaMB: {defaultValue: "1024", validate: validateIntRange(0, 10_000_000)},
+	SettingOrgQuotaMB:  {defaultValue: "10240", validate: validateIntRange(0, 10_000_000)},
+	// Passwordless login links: lifetime and requests allowed per email per hour
+	SettingMagicLinkTTL:   {defaultValue: "15m", validate: validateDurationRange(time.Minute, 24*time.Hour)},
+	SettingMagicLinkLimit: {defaultValue: "5", validate: validateIntRange(1, 100)},
+	// Password reset emails: token lifetime and requests allowed per email per hour
+	SettingPasswordResetTTL:  {defaultValue: "1h", validate: validateDurationRange(5*time.Minute, 24*time.Hour)},
+	SettingPasswordResetRate: {defaultValue: "5", validate: validateIntRange(1, 100)},
+	// Failed logins or registrations from one IP before a CAPTCHA is required
+	SettingCaptchaFailures: {defaultValue: "5", validate: validateIntRange(1, 1000)},
+	// Public photo proxy requests allowed per client IP per minute
+	SettingPublicImageRate: {defaultValue: "120", validate: validateIntRange(1, 10000)},
+	// Comma-separated alert types posted to the ops webhook; empty disables all
+	SettingAlertEvents: {defaultValue: strings.Join(alerts.Types, ","), validate: validateAlertEvents},
+	// JSON mapping of lead and contact fields to CRM fields; kinds left
+	// out use the connector's defaults
+	SettingCRMFieldMap: {defaultValue: "", validate: validateCRMFieldMap},
+	// SimplyRETS import jobs a non-admin user may start per hour and per
+	// day; 0 removes the limit
+	SettingImportJobsHourly: {defaultValue: "5", validate: validateIntRange(0, 1000)},
+	SettingImportJobsDaily:  {defaultValue: "20", validate: validateIntRange(0, 10000)},
+	// Days before a listing expires that its agent is warned
+	SettingExpiryNoticeDays: {defaultValue: "7", validate: validateIntRange(1, 90)},
+	// Stamp bulk exports with the exporting user, to trace leaked copies
+	SettingExportWatermark: {defaultValue: "false", validate: validateBool},
+	// Comma-separated CIDR ranges or addresses blocked from every route, and
+	// the only ones admin routes accept; an empty allowlist allows any
+	SettingIPDenylist:       {defaultValue: "", validate: validateIPRanges},
+	SettingAdminIPAllowlist: {defaultValue: "", validate: validateIPRanges},
+	// Issues each data-quality check tolerates before it alerts
+	SettingQualityZeroPrice: {defaultValue: "0", validate: validateIntRange(0, 1_000_000)},
+	SettingQualityDuplicate: {defaultValue: "0", validate: validateIntRange(0, 1_000_000)},
+	SettingQualityNoPhotos:  {defaultValue: "5", validate: validateIntRange(0, 1_000_000)},
+	SettingQualityGeocode:   {defaultValue: "10", validate: validateIntRange(0, 1_000_000)},
+	// Years after their last change that sold and withdrawn listings are
+	// archived; at least two, so market reports keep their listings
+	SettingArchiveAfterYears: {defaultValue: "3", validate: validateIntRange(2, 50)},
+}
+
+// SettingChangeFunc is called after a setting changes value
+type SettingChangeFunc func(name, value string)
+
+type SettingsService struct {
+	repo        repository.SettingRepository
+	mu          sync.RWMutex
+	values      map[string]models.Setting
+	subscribers []SettingChangeFunc
+}
+
+func NewSettingsService(repo repository.SettingRepository) *SettingsService {
+	values := make(map[string]models.Setting, len(settingDefinitions))
+	for name, definition := range settingDefinitions {
+		values[name] = models.Setting{Name: name, Value: definition.defaultValue}
+	}
+	return &SettingsService{repo: repo, values: values}
+}
+
+// Load refreshes the cache from the database and notifies subscribers of any
+// value that changed since the last load
+func (s *SettingsService) Load(ctx context.Context) error {
+	stored, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var changed []models.Setting
+	s.mu.Lock()
+	for _, setting := range stored {
+		definition, known := settingDefinitions[setting.Name]
+		if !known || definition.validate(setting.Value) != nil {
+			log.Printf("Ignoring unknown or invalid setting %s=%q", setting.Name, setting.Value)
+			continue
+		}
+		if s.values[setting.Name].Value != setting.Value {
+			changed = append(changed, setting)
+		}
+		s.values[setting.Name] = setting
+	}
+	s.mu.Unlock()
+
+	for _, setting := range changed {
+		s.notify(setting.Name, setting.Value)
+	}
+	return nil
+}
+
+// Watch reloads settings every interval so changes made through another
+// instance are picked up. It returns when ctx is cancelled.
+func (s *SettingsService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil {
+				log.Printf("Failed to reload settings: %v", err)
+			}
+		}
+	}
+}
+
+// Subscribe registers fn to be called whenever a setting changes
+func (s *SettingsService) Subscribe(fn SettingChangeFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// List returns all settings sorted by name
+func (s *SettingsService) List() []models.Setting {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings := make([]models.Setting, 0, len(s.values))
+	for _, setting := range s.values {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+	return settings
+}
+
+// Update validates and persists a batch of settings. Nothing is saved if any
+// value is invalid.
+func (s *SettingsService) Update(ctx context.Context, updates map[string]string, updatedBy string) ([]models.Setting, error) {
+	for name, value := range updates {
+		definition, known := settingDefinitions[name]
+		if !known {
+			return nil, apperrors.Validationf("unknown setting %s", name)
+		}
+		if err := definition.validate(value); err != nil {
+			return nil, apperrors.Validationf("invalid value for %s: %v", name, err)
+		}
+	}
+
+	for name, value := range updates {
+		setting := models.Setting{Name: name, Value: value, UpdatedBy: nullString(updatedBy), UpdatedAt: time.Now()}
+		if err := s.repo.Upsert(ctx, &setting); err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		previous := s.values[name].Value
+		s.values[name] = setting
+		s.mu.Unlock()
+
+		if previous != value {
+			s.notify(name, value)
+		}
+	}
+
+	return s.List(), nil
+}
+
+func (s *SettingsService) GetString(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[name].Value
+}
+
+func (s *SettingsService) GetInt(name string) int {
+	value, err := strconv.Atoi(s.GetString(name))
+	if err != nil {
+		value, _ = strconv.Atoi(settingDefinitions[name].defaultValue)
+	}
+	return value
+}
+
+func (s *SettingsService) GetDuration(name string) time.Duration {
+	value, err := time.ParseDuration(s.GetString(name))
+	if err != nil {
+		value, _ = time.ParseDuration(settingDefinitions[name].defaultValue)
+	}
+	return value
+}
+
+func (s *SettingsService) GetBool(name string) bool {
+	value, err := strconv.ParseBool(s.GetString(name))
+	if err != nil {
+		value, _ = strconv.ParseBool(settingDefinitions[name].defaultValue)
+	}
+	return value
+}
+
+func (s *SettingsService) notify(name, value string) {
+	s.mu.RLock()
+	subscribers := append([]SettingChangeFunc(nil), s.subscribers...)
+	s.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(name, value)
+	}
+}
+
+// defaultSettings serves the built-in defaults when no SettingsService is wired
+type defaultSettings struct{}
+
+func (defaultSettings) GetString(name string) string {
+	return settingDefinitions[name].defaultValue
+}
+
+func (defaultSettings) GetInt(name string) int {
+	value, _ := strconv.Atoi(settingDefinitions[name].defaultValue)
+	return value
+}
+
+func (defaultSettings) GetDuration(name string) time.Duration {
+	value, _ := time.ParseDuration(settingDefinitions[name].defaultValue)
+	return value
+}
+
+func (defaultSettings) GetBool(name string) bool {
+	value, _ := strconv.ParseBool(settingDefinitions[name].defaultValue)
+	return value
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("must be true or false")
+	}
+	return nil
+}
+
+func validateIntRange(min, max int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		if n < min || n > max {
+			return fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+func validateAlertEvents(value string) error {
+	for _, name := range splitList(value) {
+		if !slices.Contains(alerts.Types, name) {
+			return fmt.Errorf("unknown alert type %q; expected any of %s", name, strings.Join(alerts.Types, ", "))
+		}
+	}
+	return nil
+}
+
+func validateCRMFieldMap(value string) error {
+	_, err := crm.ParseMapping(value)
+	return err
+}
+
+// splitList parses a comma-separated setting, ignoring blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func validateDurationRange(min, max time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 5m or 24h")
+		}
+		if d < min || d > max {
+			return fmt.Errorf("must be between %s and %s", min, max)
+		}
+		return nil
+	}
+}
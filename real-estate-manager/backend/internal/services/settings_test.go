@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSettingsService_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewSettingsService(mocks.NewMockSettingRepository(ctrl))
+
+	if got := service.GetInt(SettingImportBatchSize); got != 10 {
+		t.Errorf("Expected default batch size 10, got %d", got)
+	}
+	if got := service.GetDuration(SettingJobRetention); got != 5*time.Minute {
+		t.Errorf("Expected default job retention 5m, got %v", got)
+	}
+	// Automatic imports are off unless SIMPLYRETS_SYNC_CRON is set
+	if got := service.GetString(SettingSyncSchedule); got != "" {
+		t.Errorf("Expected no default sync schedule, got %q", got)
+	}
+}
+
+func TestSettingsService_Load(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSettingRepository(ctrl)
+	mockRepo.EXPECT().GetAll(gomock.Any()).Return([]models.Setting{
+		{Name: SettingImportBatchSize, Value: "25"},
+		{Name: SettingImageQuality, Value: "not-a-number"},
+		{Name: "unknown", Value: "1"},
+	}, nil)
+
+	service := NewSettingsService(mockRepo)
+
+	var changed []string
+	service.Subscribe(func(name, value string) {
+		changed = append(changed, name+"="+value)
+	})
+
+	if err := service.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := service.GetInt(SettingImportBatchSize); got != 25 {
+		t.Errorf("Expected batch size 25, got %d", got)
+	}
+	if got := service.GetInt(SettingImageQuality); got != 85 {
+		t.Errorf("Expected invalid stored value to be ignored, got %d", got)
+	}
+	if len(changed) != 1 || changed[0] != "import_batch_size=25" {
+		t.Errorf("Expected one change notification, got %v", changed)
+	}
+}
+
+func TestSettingsService_Update(t *testing.T) {
+	tests := []struct {
+		name        string
+		updates     map[string]string
+		setupMock   func(mock *mocks.MockSettingRepository)
+		expectKind  error
+		expectError bool
+		verify      func(t *testing.T, service *SettingsService, changed []string)
+	}{
+		{
+			name:    "valid update notifies subscribers",
+			updates: map[string]string{SettingJobRetention: "30m"},
+			setupMock: func(mock *mocks.MockSettingRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			verify: func(t *testing.T, service *SettingsService, changed []string) {
+				if got := service.GetDuration(SettingJobRetention); got != 30*time.Minute {
+					t.Errorf("Expected retention 30m, got %v", got)
+				}
+				if len(changed) != 1 {
+					t.Errorf("Expected one change notification, got %v", changed)
+				}
+			},
+		},
+		{
+			name:        "unknown setting",
+			updates:     map[string]string{"nope": "1"},
+			setupMock:   func(mock *mocks.MockSettingRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+		},
+		{
+			name:        "out of range value rejects whole batch",
+			updates:     map[string]string{SettingImportBatchSize: "20", SettingImageQuality: "150"},
+			setupMock:   func(mock *mocks.MockSettingRepository) {},
+			expectError: true,
+			expectKind:  apperrors.ErrValidation,
+			verify: func(t *testing.T, service *SettingsService, changed []string) {
+				if got := service.GetInt(SettingImportBatchSize); got != 10 {
+					t.Errorf("Expected batch size to stay 10, got %d", got)
+				}
+			},
+		},
+		{
+			name:    "repository error",
+			updates: map[string]string{SettingImportBatchSize: "20"},
+			setupMock: func(mock *mocks.MockSettingRepository) {
+				mock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockSettingRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewSettingsService(mockRepo)
+			var changed []string
+			service.Subscribe(func(name, value string) {
+				changed = append(changed, name+"="+value)
+			})
+
+			_, err := service.Update(context.Background(), tt.updates, "admin")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if tt.verify != nil {
+				tt.verify(t, service, changed)
+			}
+		})
+	}
+}
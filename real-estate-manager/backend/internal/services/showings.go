@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/calendar"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// maxShowingLength caps how long a showing or open house may run
+const maxShowingLength = 12 * time.Hour
+
+type ShowingService struct {
+	repo       repository.ShowingRepository
+	properties *PropertyService
+	calendars  *CalendarService
+	now        func() time.Time
+}
+
+func NewShowingService(repo repository.ShowingRepository, properties *PropertyService, calendars *CalendarService) *ShowingService {
+	return &ShowingService{repo: repo, properties: properties, calendars: calendars, now: time.Now}
+}
+
+// List returns a listing's showings and open houses in start order
+func (s *ShowingService) List(ctx context.Context, propertyID int) ([]models.Showing, error) {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByProperty(ctx, propertyID)
+}
+
+// Create requests a showing slot for a listing. It is held by the
+// listing's agent, or the caller when the listing has none, and stays
+// requested until it is confirmed.
+func (s *ShowingService) Create(ctx context.Context, propertyID int, showing *models.Showing) error {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+
+	if showing.Kind == "" {
+		showing.Kind = models.ShowingPrivate
+	}
+	if showing.Kind != models.ShowingPrivate && showing.Kind != models.ShowingOpenHouse {
+		return apperrors.Validationf("kind must be %q or %q", models.ShowingPrivate, models.ShowingOpenHouse)
+	}
+	if showing.StartsAt.IsZero() || !showing.EndsAt.After(showing.StartsAt) {
+		return apperrors.Validation("ends_at must be after starts_at")
+	}
+	if showing.EndsAt.Sub(showing.StartsAt) > maxShowingLength {
+		return apperrors.Validationf("a showing cannot be longer than %s", maxShowingLength)
+	}
+	if !showing.StartsAt.After(s.now()) {
+		return apperrors.Validation("starts_at must be in the future")
+	}
+
+	switch userID, ok := ActorFromContext(ctx); {
+	case property.AgentID.Valid:
+		showing.AgentID = uint(property.AgentID.Int32)
+	case ok:
+		showing.AgentID = userID
+	default:
+		return apperrors.Validation("the property has no agent to hold the showing")
+	}
+
+	showing.PropertyID = propertyID
+	showing.StartsAt = showing.StartsAt.UTC()
+	showing.EndsAt = showing.EndsAt.UTC()
+	showing.Status = models.ShowingRequested
+	return s.repo.Create(ctx, showing)
+}
+
+// Confirm checks a requested slot against the agent's other confirmed
+// showings and connected calendars. Without conflicts the showing is
+// confirmed and added to those calendars; otherwise the conflicts are
+// returned and the showing stays requested.
+func (s *ShowingService) Confirm(ctx context.Context, id int) (*models.Showing, []models.ShowingConflict, error) {
+	showing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if showing == nil {
+		return nil, nil, apperrors.NotFound("showing not found")
+	}
+	if showing.Status == models.ShowingConfirmed {
+		return showing, nil, nil
+	}
+
+	conflicts, err := s.conflicts(ctx, showing)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(conflicts) > 0 {
+		return showing, conflicts, nil
+	}
+
+	if err := s.repo.UpdateStatus(ctx, showing.ID, models.ShowingConfirmed); err != nil {
+		return nil, nil, err
+	}
+	showing.Status = models.ShowingConfirmed
+
+	property, err := s.properties.GetProperty(ctx, showing.PropertyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.calendars.AddEvent(ctx, showing.AgentID, showingEvent(showing, property))
+	return showing, nil, nil
+}
+
+func (s *ShowingService) conflicts(ctx context.Context, showing *models.Showing) ([]models.ShowingConflict, error) {
+	booked, err := s.repo.ListConfirmedBetween(ctx, showing.AgentID, showing.StartsAt, showing.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []models.ShowingConflict{}
+	for _, other := range booked {
+		if other.ID == showing.ID {
+			continue
+		}
+		conflicts = append(conflicts, models.ShowingConflict{Source: "showing", ShowingID: other.ID, Start: other.StartsAt, End: other.EndsAt})
+	}
+
+	busy, err := s.calendars.Busy(ctx, showing.AgentID, showing.StartsAt, showing.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	return append(conflicts, busy...), nil
+}
+
+// showingEvent describes a confirmed showing as a calendar event
+func showingEvent(showing *models.Showing, property *models.Property) calendar.Event {
+	title := "Showing: " + property.Name
+	if showing.Kind == models.ShowingOpenHouse {
+		title = "Open house: " + property.Name
+	}
+
+	var lines []string
+	if showing.ContactName != "" || showing.ContactEmail != "" {
+		lines = append(lines, strings.TrimSpace("Contact: "+showing.ContactName+" "+angleBracketed(showing.ContactEmail)))
+	}
+	if showing.Notes != "" {
+		lines = append(lines, showing.Notes)
+	}
+	return calendar.Event{
+		Title:       title,
+		Description: strings.Join(lines, "\n\n"),
+		Location:    property.Location,
+		Start:       showing.StartsAt,
+		End:         showing.EndsAt,
+	}
+}
+
+func angleBracketed(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "<" + value + ">"
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/calendar"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestShowingService_Create(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	start := now.Add(24 * time.Hour)
+	agent := models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}
+
+	tests := []struct {
+		name        string
+		showing     models.Showing
+		agent       models.NullInt32
+		actor       uint
+		expectAgent uint
+		expectError bool
+	}{
+		{name: "held by the listing agent", showing: models.Showing{StartsAt: start, EndsAt: start.Add(time.Hour)}, agent: agent, actor: 9, expectAgent: 4},
+		{name: "held by the caller", showing: models.Showing{StartsAt: start, EndsAt: start.Add(time.Hour)}, actor: 9, expectAgent: 9},
+		{name: "no agent", showing: models.Showing{StartsAt: start, EndsAt: start.Add(time.Hour)}, expectError: true},
+		{name: "ends before start", showing: models.Showing{StartsAt: start, EndsAt: start}, agent: agent, expectError: true},
+		{name: "in the past", showing: models.Showing{StartsAt: now.Add(-time.Hour), EndsAt: now}, agent: agent, expectError: true},
+		{name: "unknown kind", showing: models.Showing{Kind: "tour", StartsAt: start, EndsAt: start.Add(time.Hour)}, agent: agent, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12, AgentID: tt.agent}, nil)
+			mockRepo := mocks.NewMockShowingRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewShowingService(mockRepo, NewPropertyService(mockProperties), nil)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+			if tt.actor != 0 {
+				ctx = WithActor(ctx, tt.actor)
+			}
+			showing := tt.showing
+			err := service.Create(ctx, 12, &showing)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if showing.AgentID != tt.expectAgent || showing.PropertyID != 12 || showing.Kind != models.ShowingPrivate || showing.Status != models.ShowingRequested {
+				t.Errorf("Unexpected showing %+v", showing)
+			}
+		})
+	}
+}
+
+func TestShowingService_Confirm(t *testing.T) {
+	start := time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC)
+	requested := &models.Showing{ID: 3, PropertyID: 12, AgentID: 4, Kind: models.ShowingOpenHouse, StartsAt: start, EndsAt: start.Add(2 * time.Hour),
+		Status: models.ShowingRequested, ContactName: "Jane", ContactEmail: "jane@example.com"}
+	connections := []models.CalendarConnection{{UserID: 4, Provider: calendar.Google, AccessToken: "access-1"}}
+
+	tests := []struct {
+		name            string
+		booked          []models.Showing
+		busy            []calendar.Interval
+		expectConflicts int
+	}{
+		{name: "free slot"},
+		{name: "another confirmed showing", booked: []models.Showing{{ID: 5, StartsAt: start.Add(time.Hour), EndsAt: start.Add(3 * time.Hour)}}, expectConflicts: 1},
+		{name: "calendar event", busy: []calendar.Interval{{Start: start, End: start.Add(time.Hour)}}, expectConflicts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			showing := *requested
+			mockRepo := mocks.NewMockShowingRepository(ctrl)
+			mockRepo.EXPECT().GetByID(gomock.Any(), 3).Return(&showing, nil)
+			mockRepo.EXPECT().ListConfirmedBetween(gomock.Any(), uint(4), start, start.Add(2*time.Hour)).Return(tt.booked, nil)
+			mockCalendars := mocks.NewMockCalendarRepository(ctrl)
+			mockCalendars.EXPECT().List(gomock.Any(), uint(4)).Return(connections, nil).AnyTimes()
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			if tt.expectConflicts == 0 {
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), 3, models.ShowingConfirmed).Return(nil)
+				mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12, Name: "Casa Verde", Location: "Houston, TX"}, nil)
+			}
+
+			provider := &fakeCalendar{busy: tt.busy}
+			calendars := NewCalendarService([]calendar.Provider{provider}, mockCalendars, "secret", "")
+			service := NewShowingService(mockRepo, NewPropertyService(mockProperties), calendars)
+			confirmed, conflicts, err := service.Confirm(context.Background(), 3)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(conflicts) != tt.expectConflicts {
+				t.Fatalf("Expected %d conflicts, got %+v", tt.expectConflicts, conflicts)
+			}
+			if tt.expectConflicts > 0 {
+				if confirmed.Status != models.ShowingRequested || len(provider.created) != 0 {
+					t.Errorf("Expected a conflicting showing to stay requested and off the calendar")
+				}
+				return
+			}
+
+			if confirmed.Status != models.ShowingConfirmed || len(provider.created) != 1 {
+				t.Fatalf("Expected the showing to be confirmed and added to the calendar, got %+v", confirmed)
+			}
+			event := provider.created[0]
+			if event.Title != "Open house: Casa Verde" || event.Location != "Houston, TX" || !event.Start.Equal(start) ||
+				!strings.Contains(event.Description, "Jane <jane@example.com>") {
+				t.Errorf("Unexpected event %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/esign"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+const (
+	// maxSignatureDocumentBytes is the largest document e-signature
+	// providers accept
+	maxSignatureDocumentBytes = 25 << 20
+	maxSigners                = 10
+)
+
+// SignatureService sends offer and disclosure documents uploaded to a
+// property out for e-signature, and follows their status through the
+// provider's callbacks
+type SignatureService struct {
+	repo       repository.SignatureRepository
+	provider   esign.Provider
+	store      ObjectStore
+	properties *PropertyService
+	uploads    repository.UploadRepository
+	offers     repository.OfferRepository
+	now        func() time.Time
+}
+
+// NewSignatureService creates the service. Without a provider, or without
+// the object storage documents are uploaded to, nothing can be sent.
+func NewSignatureService(repo repository.SignatureRepository, provider esign.Provider, store ObjectStore, properties *PropertyService,
+	uploads repository.UploadRepository, offers repository.OfferRepository) *SignatureService {
+	return &SignatureService{repo: repo, provider: provider, store: store, properties: properties, uploads: uploads, offers: offers,
+		now: time.Now}
+}
+
+// Enabled reports whether documents can be sent for signature
+func (s *SignatureService) Enabled() bool {
+	return s.provider != nil && s.store != nil
+}
+
+// List returns a property's signature requests, newest first
+func (s *SignatureService) List(ctx context.Context, propertyID int) ([]models.SignatureRequest, error) {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByProperty(ctx, propertyID)
+}
+
+func (s *SignatureService) Get(ctx context.Context, id int) (*models.SignatureRequest, error) {
+	request, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, apperrors.NotFound("signature request not found")
+	}
+	// Requests are only visible to those who can see the property
+	if _, err := s.properties.GetProperty(ctx, request.PropertyID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Send sends a confirmed document upload of the property to its signers.
+// Offer documents name an offer on the property; disclosures do not.
+func (s *SignatureService) Send(ctx context.Context, propertyID int, request *models.SignatureRequest) error {
+	if !s.Enabled() {
+		return apperrors.NotFound("no e-signature provider is configured")
+	}
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return err
+	}
+
+	switch request.Kind {
+	case models.SignatureKindOffer:
+		if !request.OfferID.Valid {
+			return apperrors.Validation("offer_id is required for offer documents")
+		}
+		offer, err := s.offers.GetByID(ctx, int(request.OfferID.Int32))
+		if err != nil {
+			return err
+		}
+		if offer == nil || offer.PropertyID != propertyID {
+			return apperrors.Validationf("offer %d not found for this property", request.OfferID.Int32)
+		}
+	case models.SignatureKindDisclosure:
+		if request.OfferID.Valid {
+			return apperrors.Validation("offer_id is only allowed for offer documents")
+		}
+	default:
+		return apperrors.Validationf("kind must be %s or %s", models.SignatureKindOffer, models.SignatureKindDisclosure)
+	}
+	if err := validateSigners(request.Signers); err != nil {
+		return err
+	}
+
+	upload, err := s.document(ctx, propertyID, request.UploadID)
+	if err != nil {
+		return err
+	}
+	content, err := s.read(ctx, upload)
+	if err != nil {
+		return err
+	}
+
+	request.Subject = strings.TrimSpace(request.Subject)
+	if request.Subject == "" {
+		request.Subject = "Please sign: " + upload.Filename
+	}
+	envelope := esign.Envelope{Subject: request.Subject, Message: request.Message, DocumentName: upload.Filename, Document: content}
+	for _, signer := range request.Signers {
+		envelope.Signers = append(envelope.Signers, esign.Signer{Name: signer.Name, Email: signer.Email})
+	}
+	envelopeID, err := s.provider.Send(ctx, envelope)
+	if err != nil {
+		return fmt.Errorf("failed to send the document for signature: %w", err)
+	}
+
+	request.ID = 0
+	request.PropertyID = propertyID
+	request.Provider = s.provider.Name()
+	request.EnvelopeID = envelopeID
+	request.Status = models.SignatureSent
+	request.CompletedAt = models.NullTime{}
+	request.SentBy = models.NullInt32{}
+	if userID, ok := ActorFromContext(ctx); ok {
+		request.SentBy = nullID(int(userID))
+	}
+	return s.repo.Create(ctx, request)
+}
+
+// Void cancels a request that signers have not finished
+func (s *SignatureService) Void(ctx context.Context, id int, reason string) (*models.SignatureRequest, error) {
+	request, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if models.IsFinalSignatureStatus(request.Status) {
+		return nil, apperrors.Conflictf("the signature request is already %s", request.Status)
+	}
+	if s.provider == nil || s.provider.Name() != request.Provider {
+		return nil, apperrors.Conflictf("the %s e-signature provider is no longer configured", request.Provider)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.Validation("reason is required")
+	}
+
+	if err := s.provider.Void(ctx, request.EnvelopeID, reason); err != nil {
+		return nil, fmt.Errorf("failed to void the envelope: %w", err)
+	}
+	request.Status = models.SignatureVoided
+	if err := s.repo.UpdateStatus(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// HandleCallback applies a status change posted by the provider. Callbacks
+// about envelopes sent elsewhere, or that arrive out of order, are
+// acknowledged and ignored.
+func (s *SignatureService) HandleCallback(ctx context.Context, header http.Header, body []byte) error {
+	if s.provider == nil {
+		return apperrors.NotFound("no e-signature provider is configured")
+	}
+	event, err := s.provider.ParseCallback(header, body)
+	switch {
+	case errors.Is(err, esign.ErrInvalidSignature):
+		return apperrors.Unauthorized("invalid signature")
+	case errors.Is(err, esign.ErrInvalidPayload):
+		return apperrors.Validation("invalid callback payload")
+	case err != nil:
+		return err
+	}
+	if event.Status == "" {
+		return nil
+	}
+
+	request, err := s.repo.GetByEnvelope(ctx, s.provider.Name(), event.EnvelopeID)
+	if err != nil || request == nil {
+		return err
+	}
+	if models.IsFinalSignatureStatus(request.Status) ||
+		slices.Index(models.SignatureStatuses, event.Status) <= slices.Index(models.SignatureStatuses, request.Status) {
+		return nil
+	}
+
+	request.Status = event.Status
+	if event.Status == models.SignatureCompleted {
+		at := event.At
+		if at.IsZero() {
+			at = s.now()
+		}
+		request.CompletedAt = nullTime(at.UTC())
+	}
+	return s.repo.UpdateStatus(ctx, request)
+}
+
+// document returns a confirmed document upload of the property
+func (s *SignatureService) document(ctx context.Context, propertyID int, uploadID string) (*models.Upload, error) {
+	if uploadID == "" {
+		return nil, apperrors.Validation("upload_id is required")
+	}
+	upload, err := s.uploads.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case upload == nil || upload.PropertyID != propertyID:
+		return nil, apperrors.Validationf("upload %s not found for this property", uploadID)
+	case upload.Kind != models.FileKindDocument:
+		return nil, apperrors.Validation("only documents can be sent for signature")
+	case upload.Status != models.UploadStatusCompleted:
+		return nil, apperrors.Validation("the document upload has not been confirmed yet")
+	case upload.SizeBytes > maxSignatureDocumentBytes:
+		return nil, apperrors.Validationf("documents sent for signature may be at most %d MB", maxSignatureDocumentBytes>>20)
+	}
+	return upload, nil
+}
+
+func (s *SignatureService) read(ctx context.Context, upload *models.Upload) ([]byte, error) {
+	object, err := s.store.Open(ctx, upload.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document %s: %w", upload.ID, err)
+	}
+	defer object.Close()
+	return io.ReadAll(io.LimitReader(object, maxSignatureDocumentBytes))
+}
+
+// validateSigners checks that every signer has a name and a distinct,
+// valid email address
+func validateSigners(signers models.SignerList) error {
+	if len(signers) == 0 {
+		return apperrors.Validation("at least one signer is required")
+	}
+	if len(signers) > maxSigners {
+		return apperrors.Validationf("a document can have at most %d signers", maxSigners)
+	}
+	seen := map[string]bool{}
+	for i := range signers {
+		signer := &signers[i]
+		signer.Name = strings.TrimSpace(signer.Name)
+		signer.Email = strings.ToLower(strings.TrimSpace(signer.Email))
+		if signer.Name == "" {
+			return apperrors.Validation("every signer needs a name")
+		}
+		if _, err := mail.ParseAddress(signer.Email); err != nil {
+			return apperrors.Validationf("signer %q needs a valid email", signer.Name)
+		}
+		if seen[signer.Email] {
+			return apperrors.Validationf("%s is listed as a signer more than once", signer.Email)
+		}
+		seen[signer.Email] = true
+	}
+	return nil
+}
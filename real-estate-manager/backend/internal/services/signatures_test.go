@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/esign"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakeSignatureProvider records the envelopes sent and returns a fixed
+// callback event
+type fakeSignatureProvider struct {
+	sent  []esign.Envelope
+	event *esign.Event
+	err   error
+}
+
+func (f *fakeSignatureProvider) Name() string {
+	return "fake"
+}
+
+func (f *fakeSignatureProvider) Send(ctx context.Context, envelope esign.Envelope) (string, error) {
+	f.sent = append(f.sent, envelope)
+	return "env-1", nil
+}
+
+func (f *fakeSignatureProvider) Void(ctx context.Context, envelopeID, reason string) error {
+	return nil
+}
+
+func (f *fakeSignatureProvider) ParseCallback(header http.Header, body []byte) (*esign.Event, error) {
+	return f.event, f.err
+}
+
+func TestSignatureService_Send(t *testing.T) {
+	offerID := models.NullInt32{NullInt32: sql.NullInt32{Int32: 5, Valid: true}}
+	otherOfferID := models.NullInt32{NullInt32: sql.NullInt32{Int32: 6, Valid: true}}
+	document := &models.Upload{ID: "doc-1", PropertyID: 12, Kind: models.FileKindDocument, ObjectKey: "docs/offer.pdf",
+		Filename: "offer.pdf", SizeBytes: 8, Status: models.UploadStatusCompleted}
+	photo := &models.Upload{ID: "photo-1", PropertyID: 12, Kind: models.FileKindPhoto, Status: models.UploadStatusCompleted}
+	jane := models.SignerList{{Name: " Jane Roe ", Email: "Jane@Example.com"}}
+
+	tests := []struct {
+		name        string
+		request     models.SignatureRequest
+		upload      *models.Upload
+		expectError bool
+	}{
+		{name: "offer document", request: models.SignatureRequest{Kind: models.SignatureKindOffer, OfferID: offerID, UploadID: "doc-1",
+			Signers: jane}, upload: document},
+		{name: "disclosure", request: models.SignatureRequest{Kind: models.SignatureKindDisclosure, UploadID: "doc-1",
+			Subject: "Seller's disclosure", Signers: jane}, upload: document},
+		{name: "offer on another listing", request: models.SignatureRequest{Kind: models.SignatureKindOffer, OfferID: otherOfferID,
+			UploadID: "doc-1", Signers: jane}, expectError: true},
+		{name: "offer document without an offer", request: models.SignatureRequest{Kind: models.SignatureKindOffer, UploadID: "doc-1",
+			Signers: jane}, expectError: true},
+		{name: "disclosure with an offer", request: models.SignatureRequest{Kind: models.SignatureKindDisclosure, OfferID: offerID,
+			UploadID: "doc-1", Signers: jane}, expectError: true},
+		{name: "unknown kind", request: models.SignatureRequest{Kind: "lease", UploadID: "doc-1", Signers: jane}, expectError: true},
+		{name: "no signers", request: models.SignatureRequest{Kind: models.SignatureKindDisclosure, UploadID: "doc-1"}, expectError: true},
+		{name: "signer without email", request: models.SignatureRequest{Kind: models.SignatureKindDisclosure, UploadID: "doc-1",
+			Signers: models.SignerList{{Name: "Jane Roe"}}}, expectError: true},
+		{name: "same signer twice", request: models.SignatureRequest{Kind: models.SignatureKindDisclosure, UploadID: "doc-1",
+			Signers: models.SignerList{{Name: "Jane", Email: "jane@example.com"}, {Name: "Jane Roe", Email: "JANE@example.com"}}},
+			expectError: true},
+		{name: "photo", request: models.SignatureRequest{Kind: models.SignatureKindDisclosure, UploadID: "photo-1", Signers: jane},
+			upload: photo, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12}, nil)
+			mockOffers := mocks.NewMockOfferRepository(ctrl)
+			mockOffers.EXPECT().GetByID(gomock.Any(), 5).Return(&models.Offer{ID: 5, PropertyID: 12}, nil).AnyTimes()
+			mockOffers.EXPECT().GetByID(gomock.Any(), 6).Return(&models.Offer{ID: 6, PropertyID: 13}, nil).AnyTimes()
+			mockUploads := mocks.NewMockUploadRepository(ctrl)
+			if tt.upload != nil {
+				mockUploads.EXPECT().GetByID(gomock.Any(), tt.upload.ID).Return(tt.upload, nil)
+			}
+			mockRepo := mocks.NewMockSignatureRepository(ctrl)
+			if !tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *models.SignatureRequest) error {
+					request.ID = 3
+					return nil
+				})
+			}
+
+			provider := &fakeSignatureProvider{}
+			store := &fakeObjectStore{sizes: map[string]int64{"docs/offer.pdf": 8}}
+			service := NewSignatureService(mockRepo, provider, store, NewPropertyService(mockProperties), mockUploads, mockOffers)
+			request := tt.request
+			err := service.Send(WithActor(context.Background(), 4), 12, &request)
+			if tt.expectError {
+				if !errors.Is(err, apperrors.ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				if len(provider.sent) != 0 {
+					t.Errorf("Expected nothing to be sent, got %+v", provider.sent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if request.ID != 3 || request.EnvelopeID != "env-1" || request.Provider != "fake" || request.Status != models.SignatureSent ||
+				request.SentBy.Int32 != 4 || request.PropertyID != 12 {
+				t.Errorf("Unexpected request %+v", request)
+			}
+			if len(provider.sent) != 1 || len(provider.sent[0].Document) != 8 || provider.sent[0].DocumentName != "offer.pdf" ||
+				provider.sent[0].Signers[0] != (esign.Signer{Name: "Jane Roe", Email: "jane@example.com"}) {
+				t.Errorf("Unexpected envelope %+v", provider.sent)
+			}
+			if request.Subject == "" {
+				t.Error("Expected a default subject")
+			}
+		})
+	}
+}
+
+func TestSignatureService_SendDisabled(t *testing.T) {
+	service := NewSignatureService(nil, nil, nil, nil, nil, nil)
+	err := service.Send(context.Background(), 12, &models.SignatureRequest{})
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found without a provider, got %v", err)
+	}
+}
+
+func TestSignatureService_HandleCallback(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		status       string
+		event        *esign.Event
+		parseErr     error
+		expectErr    error
+		expectStatus string
+	}{
+		{name: "opened", status: models.SignatureSent, event: &esign.Event{EnvelopeID: "env-1", Status: esign.StatusDelivered},
+			expectStatus: models.SignatureDelivered},
+		{name: "completed", status: models.SignatureDelivered, event: &esign.Event{EnvelopeID: "env-1", Status: esign.StatusCompleted, At: now},
+			expectStatus: models.SignatureCompleted},
+		{name: "declined", status: models.SignatureSent, event: &esign.Event{EnvelopeID: "env-1", Status: esign.StatusDeclined},
+			expectStatus: models.SignatureDeclined},
+		{name: "late delivery callback", status: models.SignatureCompleted, event: &esign.Event{EnvelopeID: "env-1", Status: esign.StatusDelivered}},
+		{name: "repeated callback", status: models.SignatureDelivered, event: &esign.Event{EnvelopeID: "env-1", Status: esign.StatusDelivered}},
+		{name: "other event", event: &esign.Event{EnvelopeID: "env-1"}},
+		{name: "unknown envelope", event: &esign.Event{EnvelopeID: "env-9", Status: esign.StatusCompleted}},
+		{name: "bad signature", parseErr: esign.ErrInvalidSignature, expectErr: apperrors.ErrUnauthorized},
+		{name: "bad payload", parseErr: esign.ErrInvalidPayload, expectErr: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockSignatureRepository(ctrl)
+			mockRepo.EXPECT().GetByEnvelope(gomock.Any(), "fake", "env-1").
+				Return(&models.SignatureRequest{ID: 3, EnvelopeID: "env-1", Status: tt.status}, nil).AnyTimes()
+			mockRepo.EXPECT().GetByEnvelope(gomock.Any(), "fake", "env-9").Return(nil, nil).AnyTimes()
+			var updated *models.SignatureRequest
+			if tt.expectStatus != "" {
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *models.SignatureRequest) error {
+					updated = request
+					return nil
+				})
+			}
+
+			provider := &fakeSignatureProvider{event: tt.event, err: tt.parseErr}
+			service := NewSignatureService(mockRepo, provider, &fakeObjectStore{}, nil, nil, nil)
+			err := service.HandleCallback(context.Background(), http.Header{}, []byte(`{}`))
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectStatus == "" {
+				return
+			}
+			if updated.Status != tt.expectStatus || updated.CompletedAt.Valid != (tt.expectStatus == models.SignatureCompleted) {
+				t.Errorf("Unexpected update %+v", updated)
+			}
+		})
+	}
+}
@@ -1,17 +1,28 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"os"
+	"net/url"
+	"path"
 	"path/filepath"
+	"real-estate-manager/backend/internal/alerts"
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
 	"real-estate-manager/backend/internal/models"
 	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/worker"
+	"real-estate-manager/backend/pkg/imagestore"
+	"real-estate-manager/backend/pkg/mlsauth"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,12 +34,197 @@ type SimplyRETSService struct {
 	baseURL      string
 	username     string
 	password     string
+	auth         mlsauth.Authenticator
 	imagesDir    string
+	images       imagestore.Storage
+	settings     SettingsProvider
+	amenityRepo  repository.AmenityRepository
+	storage      *StorageService
+	events       EventPublisher
+	alerts       Alerter
+	breaker      *circuitBreaker
+	jobs         repository.JobRepository
+	artifactsDir string
+	manager      *JobManager
+	manifest     repository.PhotoManifestRepository
+	backfill     *worker.Pool
+	flags        *FeatureFlagService
 }
 
+// SimplyRETSOption configures optional SimplyRETSService dependencies
+type SimplyRETSOption func(*SimplyRETSService)
+
+// The SimplyRETS demo feed, used unless another is configured, and where
+// downloaded photos are stored by default
+const (
+	DefaultSimplyRETSBaseURL = "https://api.simplyrets.com"
+	DefaultImagesDir         = "./uploads/images"
+	simplyRETSDemoUsername   = "simplyrets"
+	simplyRETSDemoPassword   = "simplyrets"
+)
+
+// SimplyRETSConfig points the service at an MLS feed and the directory its
+// photos are downloaded to. Empty fields keep the demo feed's values;
+// credentials are only used for basic auth.
+type SimplyRETSConfig struct {
+	BaseURL   string
+	Username  string
+	Password  string
+	ImagesDir string
+}
+
+// Validate checks the base URL is an absolute http(s) URL and that the
+// credentials are given together
+func (c SimplyRETSConfig) Validate() error {
+	if c.BaseURL != "" {
+		parsed, err := url.Parse(c.BaseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("base URL %q must be an absolute http or https URL", c.BaseURL)
+		}
+	}
+	if (c.Username == "") != (c.Password == "") {
+		return fmt.Errorf("username and password must be set together")
+	}
+	return nil
+}
+
+// WithConfig replaces the demo feed with the one config points to
+func WithConfig(config SimplyRETSConfig) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		if config.BaseURL != "" {
+			s.baseURL = strings.TrimRight(config.BaseURL, "/")
+		}
+		if config.Username != "" {
+			s.username, s.password = config.Username, config.Password
+		}
+		if config.ImagesDir != "" {
+			s.imagesDir = config.ImagesDir
+		}
+	}
+}
+
+// WithSettings makes the service read tunables such as the import batch size
+// from runtime settings instead of the built-in defaults
+func WithSettings(settings SettingsProvider) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.settings = settings
+	}
+}
+
+// WithAmenities stores the structured amenities mapped from each imported
+// listing's MLS feature lists
+func WithAmenities(repo repository.AmenityRepository) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.amenityRepo = repo
+	}
+}
+
+// WithStorage charges downloaded listing photos to the importing user and
+// organization, failing images that would exceed their quota
+func WithStorage(storage *StorageService) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.storage = storage
+	}
+}
+
+// WithAuth replaces basic auth with another scheme, such as a bearer token
+// or OAuth client credentials
+func WithAuth(auth mlsauth.Authenticator) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.auth = auth
+	}
+}
+
+// WithImportEvents publishes job lifecycle events and an event for each
+// imported property
+func WithImportEvents(publisher EventPublisher) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.events = publisher
+	}
+}
+
+// WithAlerts raises ops alerts when an import job fails or the provider's
+// circuit breaker opens
+func WithAlerts(alerter Alerter) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.alerts = alerter
+	}
+}
+
+// WithJobManager tracks running jobs in manager, shared with whatever else
+// needs to see them, instead of a manager of the service's own
+func WithJobManager(manager *JobManager) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.manager = manager
+	}
+}
+
+// WithJobHistory records every job, with its label, description and
+// metadata and how it finished, for the job history
+func WithJobHistory(repo repository.JobRepository) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.jobs = repo
+	}
+}
+
+// WithJobArtifacts keeps each job's error CSV, change report and raw
+// provider pages in a directory per job under dir, charged to the storage
+// quota of whoever started the job
+func WithJobArtifacts(dir string) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.artifactsDir = dir
+	}
+}
+
+// WithPhotoManifest remembers the ETag of every downloaded photo, so later
+// syncs only download photos the provider reports changed
+func WithPhotoManifest(repo repository.PhotoManifestRepository) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.manifest = repo
+	}
+}
+
+// WithPhotoBackfill lets jobs started with lazy photos queue their photo
+// downloads on pool, which should be small so downloads yield to imports
+// and API traffic
+func WithPhotoBackfill(pool *worker.Pool) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.backfill = pool
+	}
+}
+
+// WithFeatureFlags only generates photo thumbnails while the
+// image_transcoding flag is on; without flags they always are
+func WithFeatureFlags(flags *FeatureFlagService) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.flags = flags
+	}
+}
+
+// WithImageStorage stores downloaded photos in images, such as a bucket
+// shared by every instance, instead of the local images directory
+func WithImageStorage(images imagestore.Storage) SimplyRETSOption {
+	return func(s *SimplyRETSService) {
+		s.images = images
+	}
+}
+
+// Limits on the details a job is started with
+const (
+	maxJobLabelLength       = 64
+	maxJobDescriptionLength = 500
+	maxJobMetadataBytes     = 4096
+	defaultJobHistoryLimit  = 50
+	maxJobHistoryLimit      = 500
+)
+
 // ProcessingJob represents a property processing job
 type ProcessingJob struct {
 	ID           string
+	// UserID and OrganizationID are who started the job, zero when the
+	// system did
+	UserID         uint
+	OrganizationID int
 	Status       chan models.ProcessingStatus
 	Cancel       context.CancelFunc
 	StartTime    time.Time
@@ -37,20 +233,76 @@ type ProcessingJob struct {
 	mu           sync.RWMutex
 }
 
+// JobScope is who is asking about a job. Jobs of other organizations, or
+// of other users when the job has no organization, do not exist for them,
+// and within their organization only jobs they started may be viewed or
+// cancelled. All covers every job, for admins.
+type JobScope struct {
+	UserID         uint
+	OrganizationID int
+	All            bool
+}
+
+// authorize returns a not found error for a job outside the scope and a
+// forbidden error for one the caller did not start
+func (s JobScope) authorize(userID uint, organizationID int) error {
+	if s.All {
+		return nil
+	}
+	outside := userID != s.UserID
+	if organizationID != 0 {
+		outside = organizationID != s.OrganizationID
+	}
+	if outside {
+		return apperrors.NotFound("job not found")
+	}
+	if userID == 0 || userID != s.UserID {
+		return apperrors.Forbidden("only the user who started a job or an admin may access it")
+	}
+	return nil
+}
+
+// authorizeJob looks up who started a running or recorded job and checks
+// scope may access it
+func (s *SimplyRETSService) authorizeJob(ctx context.Context, jobID string, scope JobScope) error {
+	if job, exists := s.manager.GetJob(jobID); exists {
+		return scope.authorize(job.UserID, job.OrganizationID)
+	}
+	if s.jobs != nil {
+		record, err := s.jobs.GetByID(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		if record != nil {
+			return scope.authorize(uint(record.CreatedBy.Int32), int(record.OrganizationID.Int32))
+		}
+	}
+	return apperrors.NotFound("job not found")
+}
+
 // JobManager manages processing jobs
 type JobManager struct {
-	jobs map[string]*ProcessingJob
-	mu   sync.RWMutex
+	jobs      map[string]*ProcessingJob
+	retention time.Duration
+	mu        sync.RWMutex
 }
 
-const JobRetentionDuration = 5 * time.Minute // Keep completed jobs for 5 minutes
+const JobRetentionDuration = 5 * time.Minute // Default time completed jobs are kept
 
 func NewJobManager() *JobManager {
 	return &JobManager{
-		jobs: make(map[string]*ProcessingJob),
+		jobs:      make(map[string]*ProcessingJob),
+		retention: JobRetentionDuration,
 	}
 }
 
+// SetRetention changes how long completed jobs are kept for status queries
+func (jm *JobManager) SetRetention(retention time.Duration) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.retention = retention
+}
+
 func (jm *JobManager) AddJob(id string, job *ProcessingJob) {
 	jm.mu.Lock()
 	defer jm.mu.Unlock()
@@ -80,6 +332,21 @@ func (jm *JobManager) RemoveJob(id string) {
 	}
 }
 
+// Running returns how many jobs have not completed yet
+func (jm *JobManager) Running() int {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	running := 0
+	for _, job := range jm.jobs {
+		job.mu.RLock()
+		if job.CompletedAt == nil {
+			running++
+		}
+		job.mu.RUnlock()
+	}
+	return running
+}
+
 func (jm *JobManager) MarkJobCompleted(id string, finalStatus models.ProcessingStatus) {
 	jm.mu.Lock()
 	defer jm.mu.Unlock()
@@ -93,9 +360,10 @@ func (jm *JobManager) MarkJobCompleted(id string, finalStatus models.ProcessingS
 		log.Printf("Job %s marked as completed with status: %s", id, finalStatus.Status)
 		
 		// Schedule cleanup after retention period
+		retention := jm.retention
 		go func() {
-			log.Printf("Job %s cleanup scheduled in %v", id, JobRetentionDuration)
-			time.Sleep(JobRetentionDuration)
+			log.Printf("Job %s cleanup scheduled in %v", id, retention)
+			time.Sleep(retention)
 			jm.CleanupJob(id)
 		}()
 	} else {
@@ -112,7 +380,7 @@ func (jm *JobManager) CleanupJob(id string) {
 		completedTime := job.CompletedAt
 		job.mu.RUnlock()
 		
-		if isCompleted && completedTime != nil && time.Since(*completedTime) >= JobRetentionDuration {
+		if isCompleted && completedTime != nil && time.Since(*completedTime) >= jm.retention {
 			close(job.Status)
 			delete(jm.jobs, id)
 			log.Printf("Job %s cleaned up after retention period (remaining jobs: %d)", id, len(jm.jobs))
@@ -124,27 +392,198 @@ func (jm *JobManager) CleanupJob(id string) {
 	}
 }
 
-var GlobalJobManager = NewJobManager()
-
-func NewSimplyRETSService(propertyRepo repository.PropertyRepository) *SimplyRETSService {
-	// Create images directory if it doesn't exist
-	imagesDir := "./uploads/images"
-	os.MkdirAll(imagesDir, 0755)
-
-	return &SimplyRETSService{
+func NewSimplyRETSService(propertyRepo repository.PropertyRepository, opts ...SimplyRETSOption) *SimplyRETSService {
+	service := &SimplyRETSService{
 		propertyRepo: propertyRepo,
 		client:       &http.Client{Timeout: 30 * time.Second},
-		baseURL:      "https://api.simplyrets.com",
-		username:     "simplyrets",
-		password:     "simplyrets",
-		imagesDir:    imagesDir,
+		baseURL:      DefaultSimplyRETSBaseURL,
+		username:     simplyRETSDemoUsername,
+		password:     simplyRETSDemoPassword,
+		imagesDir:    DefaultImagesDir,
+		settings:     defaultSettings{},
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	if service.images == nil {
+		service.images = imagestore.NewLocal(service.imagesDir)
 	}
+	if service.manager == nil {
+		service.manager = NewJobManager()
+	}
+	if service.auth == nil {
+		service.auth = mlsauth.Basic{Username: service.username, Password: service.password}
+	}
+	// Stop hammering the provider while it is down
+	service.breaker = newCircuitBreaker(upstreamFailureThreshold, upstreamCooldown, func(failures int) {
+		raiseAlert(service.alerts, alerts.CircuitOpen, "SimplyRETS circuit breaker opened", map[string]string{
+			"upstream": service.baseURL,
+			"failures": strconv.Itoa(failures),
+		})
+	})
+	return service
+}
+
+// DefaultLimit returns the number of properties imported when a job is
+// started without an explicit limit
+func (s *SimplyRETSService) DefaultLimit() int {
+	return s.settings.GetInt(SettingSyncDefaultLimit)
+}
+
+// Running jobs record a heartbeat every jobHeartbeatInterval; one that has
+// sent none for jobStaleAfter is taken to have stopped with its server
+const (
+	jobHeartbeatInterval = 30 * time.Second
+	jobStaleAfter        = 2 * time.Minute
+)
+
+// errJobRunning refuses to start an import while another one runs, on
+// this server or, with the job history kept, on any
+func errJobRunning() error {
+	return apperrors.Conflict("another import job is running")
 }
 
-// StartPropertyProcessing starts the property processing job
-func (s *SimplyRETSService) StartPropertyProcessing(ctx context.Context, jobID string, limit int) error {
+// StartPropertyProcessing starts the property processing job. Jobs without
+// a label are labeled manual. Only one job runs at a time.
+func (s *SimplyRETSService) StartPropertyProcessing(ctx context.Context, jobID string, limit int, details *models.JobDetails) error {
 	log.Printf("Starting property processing job %s with limit %d", jobID, limit)
 	
+	if err := validateJobDetails(details); err != nil {
+		return err
+	}
+	ctx, err := s.jobContext(ctx, details)
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	if s.jobs == nil && s.manager.Running() > 0 {
+		return errJobRunning()
+	}
+	if s.jobs != nil {
+		record := &models.ProcessingJob{ID: jobID, JobDetails: *details, Limit: limit, Status: "running", StartedAt: startTime}
+		if userID, ok := ActorFromContext(ctx); ok {
+			record.CreatedBy = models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(userID), Valid: true}}
+		}
+		if principal, ok := PrincipalFromContext(ctx); ok {
+			record.OrganizationID = nullID(principal.OrganizationID)
+		}
+		if err := s.jobs.Create(ctx, record, startTime.Add(-jobStaleAfter)); err != nil {
+			if errors.Is(err, repository.ErrJobRunning) {
+				return errJobRunning()
+			}
+			return fmt.Errorf("failed to record job: %w", err)
+		}
+		ctx = withJobIndex(ctx, newJobIndex(jobID, s.jobs, nil))
+	}
+
+	userID, _ := ActorFromContext(ctx)
+	organizationID := 0
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		organizationID = principal.OrganizationID
+	}
+	s.launchJob(ctx, jobID, limit, startTime, userID, organizationID)
+	publishEvent(ctx, s.events, events.JobStarted, jobSubject(jobID), map[string]any{"limit": limit, "label": details.Label})
+	
+	log.Printf("Property processing job %s started successfully", jobID)
+	return nil
+}
+
+// ResumeJob runs a failed or cancelled job again under the same ID, with
+// the limit and details it was started with; so does a job the server
+// stopped running without finishing. Listings the job already saved are
+// skipped, so each is saved once however often it is resumed. Only jobs
+// in the job history can be resumed.
+func (s *SimplyRETSService) ResumeJob(ctx context.Context, jobID string, scope JobScope) error {
+	if s.jobs == nil {
+		return apperrors.NotFound("job not found")
+	}
+	record, err := s.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return apperrors.NotFound("job not found")
+	}
+	userID, organizationID := uint(record.CreatedBy.Int32), int(record.OrganizationID.Int32)
+	if err := scope.authorize(userID, organizationID); err != nil {
+		return err
+	}
+	job, exists := s.manager.GetJob(jobID)
+	if exists {
+		job.mu.RLock()
+		running := job.CompletedAt == nil
+		job.mu.RUnlock()
+		if running {
+			return apperrors.Conflict("job is still running")
+		}
+	}
+	if record.Status == "running" && !jobInterrupted(record) {
+		return apperrors.Conflict("job is still running")
+	}
+	if record.Status == "completed" {
+		return apperrors.Conflict("only failed, cancelled or interrupted jobs can be resumed")
+	}
+
+	details := record.JobDetails
+	ctx, err = s.jobContext(ctx, &details)
+	if err != nil {
+		return err
+	}
+	done, err := s.jobs.ProcessedListings(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to load processed listings: %w", err)
+	}
+	now := time.Now()
+	if err := s.jobs.Reopen(ctx, jobID, now, now.Add(-jobStaleAfter)); err != nil {
+		if errors.Is(err, repository.ErrJobRunning) {
+			return errJobRunning()
+		}
+		return fmt.Errorf("failed to record job: %w", err)
+	}
+	if exists {
+		s.manager.RemoveJob(jobID)
+	}
+	ctx = withJobIndex(ctx, newJobIndex(jobID, s.jobs, done))
+
+	s.launchJob(ctx, jobID, record.Limit, now, userID, organizationID)
+	publishEvent(ctx, s.events, events.JobStarted, jobSubject(jobID), map[string]any{"limit": record.Limit, "label": details.Label, "resumed": true})
+	log.Printf("Property processing job %s resumed, skipping %d listings already saved", jobID, len(done))
+	return nil
+}
+
+// jobContext prepares the context a job runs with: whether it downloads
+// photos lazily and who its photos are charged to
+func (s *SimplyRETSService) jobContext(ctx context.Context, details *models.JobDetails) (context.Context, error) {
+	if details.LazyPhotos {
+		if s.backfill == nil {
+			return nil, apperrors.Validation("lazy photo downloads are not enabled")
+		}
+		ctx = withLazyPhotos(ctx)
+	}
+
+	// Resolve who the job's photos are charged to before it starts
+	if s.storage != nil {
+		owner, err := s.storage.OwnerFromContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve storage owner: %w", err)
+		}
+		// Refuse to start an import for an owner already at their quota
+		if err := s.storage.CheckQuota(ctx, owner, 1); err != nil {
+			return nil, err
+		}
+		ctx = withStorageOwner(ctx, owner)
+	}
+	return ctx, nil
+}
+
+// launchJob registers a job started by userID and runs it in the
+// background
+func (s *SimplyRETSService) launchJob(ctx context.Context, jobID string, limit int, startTime time.Time, userID uint, organizationID int) {
+	if s.artifactsDir != "" {
+		ctx = withJobReport(ctx, &jobReport{dir: filepath.Join(s.artifactsDir, jobID)})
+	}
+
 	// Create a cancellable context for this job
 	jobCtx, cancel := context.WithCancel(ctx)
 	
@@ -153,28 +592,100 @@ func (s *SimplyRETSService) StartPropertyProcessing(ctx context.Context, jobID s
 	
 	// Create and register the job
 	job := &ProcessingJob{
-		ID:          jobID,
-		Status:      statusChan,
-		Cancel:      cancel,
-		StartTime:   time.Now(),
-		LastStatus:  nil,
-		CompletedAt: nil,
+		ID:             jobID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Status:         statusChan,
+		Cancel:         cancel,
+		StartTime:      startTime,
+		LastStatus:     nil,
+		CompletedAt:    nil,
 	}
-	GlobalJobManager.AddJob(jobID, job)
+	s.manager.AddJob(jobID, job)
 	
 	// Start processing in a goroutine
-	go s.processProperties(jobCtx, jobID, statusChan, limit)
-	
-	log.Printf("Property processing job %s started successfully", jobID)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.processProperties(jobCtx, jobID, statusChan, limit)
+	}()
+	if s.jobs != nil {
+		go s.heartbeat(context.WithoutCancel(jobCtx), jobID, done)
+	}
+}
+
+// heartbeat records that a job is alive until done is closed, so other
+// servers do not start an import meanwhile
+func (s *SimplyRETSService) heartbeat(ctx context.Context, jobID string, done <-chan struct{}) {
+	ticker := time.NewTicker(jobHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if err := s.jobs.Heartbeat(ctx, jobID, now); err != nil {
+				log.Printf("Failed to record heartbeat of job %s: %v", jobID, err)
+			}
+		}
+	}
+}
+
+// ListJobs returns the job history, newest first, optionally only jobs
+// with a label. Only admins see jobs other users started. It is empty
+// unless the history is kept.
+func (s *SimplyRETSService) ListJobs(ctx context.Context, filter models.JobFilter, scope JobScope) ([]models.ProcessingJob, error) {
+	if !scope.All {
+		filter.CreatedBy = scope.UserID
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultJobHistoryLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxJobHistoryLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", maxJobHistoryLimit)
+	}
+	if s.jobs == nil {
+		return []models.ProcessingJob{}, nil
+	}
+	return s.jobs.List(ctx, filter)
+}
+
+// validateJobDetails trims a job's label and description, defaulting the
+// label, and checks they and the metadata are not too large
+func validateJobDetails(details *models.JobDetails) error {
+	details.Label = strings.TrimSpace(details.Label)
+	details.Description = strings.TrimSpace(details.Description)
+	if details.Label == "" {
+		details.Label = models.JobLabelManual
+	}
+	if len(details.Label) > maxJobLabelLength {
+		return apperrors.Validationf("label must be at most %d characters", maxJobLabelLength)
+	}
+	if len(details.Description) > maxJobDescriptionLength {
+		return apperrors.Validationf("description must be at most %d characters", maxJobDescriptionLength)
+	}
+	if len(details.Metadata) > 0 {
+		encoded, err := json.Marshal(details.Metadata)
+		if err != nil {
+			return apperrors.Validation("metadata must be a JSON object")
+		}
+		if len(encoded) > maxJobMetadataBytes {
+			return apperrors.Validationf("metadata must be at most %d bytes of JSON", maxJobMetadataBytes)
+		}
+	}
 	return nil
 }
 
-// GetJobStatus returns the current status of a job
-func (s *SimplyRETSService) GetJobStatus(jobID string) (*models.ProcessingStatus, bool) {
-	job, exists := GlobalJobManager.GetJob(jobID)
+// GetJobStatus returns the current status of a job scope may access.
+// Jobs no longer held in memory, after a restart or once retention has
+// passed, are answered from the job history.
+func (s *SimplyRETSService) GetJobStatus(ctx context.Context, jobID string, scope JobScope) (*models.ProcessingStatus, error) {
+	job, exists := s.manager.GetJob(jobID)
 	if !exists {
-		log.Printf("GetJobStatus: Job %s not found", jobID)
-		return nil, false
+		return s.recordedJobStatus(ctx, jobID, scope)
+	}
+	if err := scope.authorize(job.UserID, job.OrganizationID); err != nil {
+		return nil, err
 	}
 	
 	job.mu.RLock()
@@ -183,7 +694,7 @@ func (s *SimplyRETSService) GetJobStatus(jobID string) (*models.ProcessingStatus
 	// If job is completed, return the final status
 	if job.LastStatus != nil {
 		log.Printf("GetJobStatus: Returning completed status for job %s: %s", jobID, job.LastStatus.Status)
-		return job.LastStatus, true
+		return job.LastStatus, nil
 	}
 	
 	// For running jobs, try to get the latest status without blocking
@@ -206,35 +717,81 @@ func (s *SimplyRETSService) GetJobStatus(jobID string) (*models.ProcessingStatus
 			// Channel full, that's OK
 		}
 		
-		return &status, true
+		return &status, nil
 	default:
 		// Return a basic status if no update is available
 		log.Printf("GetJobStatus: No status update available for job %s, returning default running status", jobID)
 		return &models.ProcessingStatus{
 			Status:    "running",
 			StartedAt: job.StartTime,
-		}, true
+		}, nil
+	}
+}
+
+// recordedJobStatus returns the status of a job from the job history. A
+// job recorded as running that still sends heartbeats is running on
+// another server; one that sent none for jobStaleAfter was stopped with
+// its server and is reported as interrupted, so it can be resumed.
+func (s *SimplyRETSService) recordedJobStatus(ctx context.Context, jobID string, scope JobScope) (*models.ProcessingStatus, error) {
+	if s.jobs == nil {
+		return nil, apperrors.NotFound("job not found")
+	}
+	record, err := s.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
 	}
+	if record == nil {
+		return nil, apperrors.NotFound("job not found")
+	}
+	if err := scope.authorize(uint(record.CreatedBy.Int32), int(record.OrganizationID.Int32)); err != nil {
+		return nil, err
+	}
+
+	status := &models.ProcessingStatus{
+		Status:          record.Status,
+		TotalProperties: record.TotalProperties,
+		ProcessedCount:  record.ProcessedCount,
+		FailedCount:     record.FailedCount,
+		PhotosSkipped:   record.PhotosSkipped,
+		ListingsSkipped: record.ListingsSkipped,
+		StartedAt:       record.StartedAt,
+		CompletedAt:     record.CompletedAt,
+		ErrorMessage:    record.ErrorMessage,
+	}
+	if record.Status == "running" && jobInterrupted(record) {
+		status.Status = "interrupted"
+	}
+	return status, nil
 }
 
-// CancelJob cancels a running job
-func (s *SimplyRETSService) CancelJob(jobID string) bool {
+// jobInterrupted reports whether a job recorded as running has sent no
+// heartbeat for jobStaleAfter
+func jobInterrupted(record *models.ProcessingJob) bool {
+	return record.HeartbeatAt == nil || time.Since(*record.HeartbeatAt) > jobStaleAfter
+}
+
+// CancelJob cancels a running job scope may access
+func (s *SimplyRETSService) CancelJob(jobID string, scope JobScope) error {
 	log.Printf("Attempting to cancel job %s", jobID)
-	job, exists := GlobalJobManager.GetJob(jobID)
+	job, exists := s.manager.GetJob(jobID)
 	if !exists {
 		log.Printf("Cannot cancel job %s: job not found", jobID)
-		return false
+		return apperrors.NotFound("job not found or already completed")
+	}
+	if err := scope.authorize(job.UserID, job.OrganizationID); err != nil {
+		return err
 	}
 	
 	job.Cancel()
-	GlobalJobManager.RemoveJob(jobID)
+	s.manager.RemoveJob(jobID)
 	log.Printf("Job %s cancelled successfully", jobID)
-	return true
+	return nil
 }
 
 // processProperties is the main processing function that runs in a goroutine
 func (s *SimplyRETSService) processProperties(ctx context.Context, jobID string, statusChan chan models.ProcessingStatus, limit int) {
 	log.Printf("processProperties: Starting job %s with limit %d", jobID, limit)
+	ctx = withPhotoTally(ctx, &photoTally{})
 	
 	// Send initial status
 	status := models.ProcessingStatus{
@@ -254,9 +811,47 @@ func (s *SimplyRETSService) processProperties(ctx context.Context, jobID string,
 		return
 	}
 	
+	// Process properties in batches (import_batch_size setting, default 10)
+	// as they are decoded, so a large page is never held in memory whole.
+	// The total grows as the page is read.
+	batchSize := s.settings.GetInt(SettingImportBatchSize)
+	batch := make([]models.SimplyRETSProperty, 0, batchSize)
+	flush := func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		first := status.TotalProperties + 1
+		status.TotalProperties += len(batch)
+		log.Printf("processProperties: Processing batch %d-%d for job %s", first, status.TotalProperties, jobID)
+		s.processBatch(ctx, batch, statusChan, &status)
+		log.Printf("processProperties: Completed batch %d-%d for job %s (total processed: %d, failed: %d)", first, status.TotalProperties, jobID, status.ProcessedCount, status.FailedCount)
+		batch = batch[:0]
+		return nil
+	}
+	
 	// Fetch properties from SimplyRETS
-	log.Printf("processProperties: Fetching properties from SimplyRETS for job %s (limit: %d)", jobID, limit)
-	properties, err := s.fetchProperties(ctx, limit)
+	log.Printf("processProperties: Fetching properties from SimplyRETS for job %s (limit: %d, batch size: %d)", jobID, limit, batchSize)
+	fetched, err := s.fetchProperties(ctx, limit, func(property models.SimplyRETSProperty) error {
+		batch = append(batch, property)
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if err != nil && ctx.Err() != nil {
+		log.Printf("processProperties: Context cancelled during processing for job %s", jobID)
+		status.Status = "cancelled"
+		completedAt := time.Now()
+		status.CompletedAt = &completedAt
+		statusChan <- status
+		s.completeJob(ctx, jobID, status)
+		return
+	}
 	if err != nil {
 		log.Printf("processProperties: Failed to fetch properties for job %s: %v", jobID, err)
 		status.Status = "failed"
@@ -264,42 +859,10 @@ func (s *SimplyRETSService) processProperties(ctx context.Context, jobID string,
 		completedAt := time.Now()
 		status.CompletedAt = &completedAt
 		statusChan <- status
-		GlobalJobManager.MarkJobCompleted(jobID, status)
+		s.completeJob(ctx, jobID, status)
 		return
 	}
-	
-	log.Printf("processProperties: Successfully fetched %d properties for job %s", len(properties), jobID)
-	status.TotalProperties = len(properties)
-	statusChan <- status
-	
-	// Process properties in batches of 10
-	batchSize := 10
-	log.Printf("processProperties: Starting batch processing for job %s (%d properties, batch size: %d)", jobID, len(properties), batchSize)
-	
-	for i := 0; i < len(properties); i += batchSize {
-		select {
-		case <-ctx.Done():
-			log.Printf("processProperties: Context cancelled during processing for job %s", jobID)
-			status.Status = "cancelled"
-			completedAt := time.Now()
-			status.CompletedAt = &completedAt
-			statusChan <- status
-			GlobalJobManager.MarkJobCompleted(jobID, status)
-			return
-		default:
-		}
-		
-		end := i + batchSize
-		if end > len(properties) {
-			end = len(properties)
-		}
-		
-		log.Printf("processProperties: Processing batch %d-%d for job %s", i+1, end, jobID)
-		
-		batch := properties[i:end]
-		s.processBatch(ctx, batch, statusChan, &status)
-		log.Printf("processProperties: Completed batch %d-%d for job %s (total processed: %d, failed: %d)", i+1, end, jobID, status.ProcessedCount, status.FailedCount)
-	}
+	log.Printf("processProperties: Successfully fetched %d properties for job %s", fetched, jobID)
 	
 	// Send final status
 	log.Printf("processProperties: Job %s completed successfully. Total: %d, Processed: %d, Failed: %d", jobID, status.TotalProperties, status.ProcessedCount, status.FailedCount)
@@ -307,45 +870,162 @@ func (s *SimplyRETSService) processProperties(ctx context.Context, jobID string,
 	completedAt := time.Now()
 	status.CompletedAt = &completedAt
 	statusChan <- status
-	GlobalJobManager.MarkJobCompleted(jobID, status)
+	s.completeJob(ctx, jobID, status)
+}
+
+// completeJob stores a job's artifacts, records its final status and
+// announces it
+func (s *SimplyRETSService) completeJob(ctx context.Context, jobID string, status models.ProcessingStatus) {
+	// Cancelled jobs still record how they ended
+	recordCtx := context.WithoutCancel(ctx)
+	status.Artifacts = s.writeArtifacts(recordCtx, jobID)
+	s.manager.MarkJobCompleted(jobID, status)
+	if s.jobs != nil {
+		if err := s.jobs.Complete(recordCtx, jobID, status); err != nil {
+			log.Printf("Failed to record completion of job %s: %v", jobID, err)
+		}
+	}
+	publishEvent(ctx, s.events, jobEventType(status.Status), jobSubject(jobID), status)
+	if status.Status == "failed" {
+		raiseAlert(s.alerts, alerts.JobFailed, "SimplyRETS import job failed", map[string]string{
+			"job":   jobID,
+			"error": status.ErrorMessage,
+		})
+	}
 }
 
-// fetchProperties fetches properties from SimplyRETS API
-func (s *SimplyRETSService) fetchProperties(ctx context.Context, limit int) ([]models.SimplyRETSProperty, error) {
+// fetchProperties fetches properties from SimplyRETS API, passing them to
+// yield one at a time, and returns how many were fetched. The page is
+// spooled to disk within the client's timeout and decoded from there, so
+// memory does not grow with the page and slow processing cannot time out
+// the download. An error from yield stops the fetch and is returned.
+func (s *SimplyRETSService) fetchProperties(ctx context.Context, limit int, yield func(models.SimplyRETSProperty) error) (int, error) {
 	url := fmt.Sprintf("%s/properties?limit=%d", s.baseURL, limit)
 	log.Printf("fetchProperties: Making request to %s", url)
 	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		log.Printf("fetchProperties: Failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	
-	req.SetBasicAuth(s.username, s.password)
-	req.Header.Set("Accept", "application/json")
-	
 	log.Printf("fetchProperties: Sending request to SimplyRETS API")
-	resp, err := s.client.Do(req)
+	resp, err := s.getAuthorized(ctx, url)
 	if err != nil {
 		log.Printf("fetchProperties: Request failed: %v", err)
-		return nil, fmt.Errorf("failed to fetch properties: %w", err)
+		return 0, err
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
 		log.Printf("fetchProperties: Received non-200 status code: %d", resp.StatusCode)
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return 0, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+	
+	log.Printf("fetchProperties: Successfully received response, spooling page")
+	report := jobReportFromContext(ctx)
+	page, err := report.spool(url, resp.Body)
+	if err != nil {
+		return 0, err
 	}
+	defer page.Close()
 	
-	log.Printf("fetchProperties: Successfully received response, decoding JSON")
-	var properties []models.SimplyRETSProperty
-	if err := json.NewDecoder(resp.Body).Decode(&properties); err != nil {
-		log.Printf("fetchProperties: Failed to decode JSON response: %v", err)
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	log.Printf("fetchProperties: Decoding JSON")
+	count, err := decodeProperties(page, yield)
+	report.decoded(page, count)
+	if err != nil {
+		log.Printf("fetchProperties: Stopped after %d properties: %v", count, err)
+		return count, err
 	}
 	
-	log.Printf("fetchProperties: Successfully fetched and decoded %d properties", len(properties))
-	return properties, nil
+	log.Printf("fetchProperties: Successfully fetched and decoded %d properties", count)
+	return count, nil
+}
+
+// decodeProperties decodes a page's array of listings one element at a
+// time, passing each to yield, and returns how many it decoded. A null page
+// holds none.
+func decodeProperties(r io.Reader, yield func(models.SimplyRETSProperty) error) (int, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if token == nil {
+		return 0, nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return 0, fmt.Errorf("failed to decode response: expected an array of listings, got %v", token)
+	}
+	count := 0
+	for decoder.More() {
+		var property models.SimplyRETSProperty
+		if err := decoder.Decode(&property); err != nil {
+			return count, fmt.Errorf("failed to decode response: %w", err)
+		}
+		count++
+		if err := yield(property); err != nil {
+			return count, err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return count, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return count, nil
+}
+
+// Check verifies the provider answers an authorized request for a single
+// listing. While the circuit breaker is open it fails without contacting
+// the provider.
+func (s *SimplyRETSService) Check(ctx context.Context) error {
+	resp, err := s.getAuthorized(ctx, fmt.Sprintf("%s/properties?limit=1", s.baseURL))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// getAuthorized sends an authenticated GET to the provider. When cached
+// credentials such as an OAuth token are rejected with 401, they are
+// discarded and the request is retried once with fresh ones.
+func (s *SimplyRETSService) getAuthorized(ctx context.Context, url string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if err := s.auth.Authorize(ctx, req); err != nil {
+			return nil, fmt.Errorf("failed to authorize request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+
+		if err := s.breaker.Allow(); err != nil {
+			return nil, err
+		}
+		resp, err := s.client.Do(req)
+		s.breaker.Record(upstreamError(resp, err))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch properties: %w", err)
+		}
+
+		invalidator, cached := s.auth.(mlsauth.Invalidator)
+		if resp.StatusCode != http.StatusUnauthorized || !cached || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		log.Printf("getAuthorized: Provider rejected cached credentials, refreshing")
+		invalidator.Invalidate()
+	}
+}
+
+// upstreamError reports a failure that counts against the circuit breaker:
+// network errors and 5xx responses, but not client errors
+func upstreamError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("upstream returned status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 // processBatch processes a batch of properties
@@ -353,9 +1033,15 @@ func (s *SimplyRETSService) processBatch(ctx context.Context, batch []models.Sim
 	log.Printf("processBatch: Processing batch of %d properties", len(batch))
 	var wg sync.WaitGroup
 	results := make(chan error, len(batch))
+	index := jobIndexFromContext(ctx)
 	
-	// Process each property in the batch concurrently
+	// Process each property in the batch concurrently, skipping those the
+	// job already saved
 	for i, prop := range batch {
+		if !index.claim(prop) {
+			log.Printf("processBatch: Skipping property %d (listing %s), already saved by this job", i+1, prop.ListingID)
+			continue
+		}
 		wg.Add(1)
 		go func(idx int, property models.SimplyRETSProperty) {
 			defer wg.Done()
@@ -370,8 +1056,10 @@ func (s *SimplyRETSService) processBatch(ctx context.Context, batch []models.Sim
 			
 			log.Printf("processBatch: Processing property %d (MLS: %s)", idx+1, property.MLSNumber.String())
 			err := s.processProperty(ctx, property)
+			index.finish(ctx, property, err)
 			if err != nil {
 				log.Printf("processBatch: Failed to process property %d (MLS: %s): %v", idx+1, property.MLSNumber.String(), err)
+				jobReportFromContext(ctx).fail(property, err)
 			} else {
 				log.Printf("processBatch: Successfully processed property %d (MLS: %s)", idx+1, property.MLSNumber.String())
 			}
@@ -392,6 +1080,8 @@ func (s *SimplyRETSService) processBatch(ctx context.Context, batch []models.Sim
 			status.ProcessedCount++
 		}
 	}
+	status.PhotosSkipped = photoTallyFromContext(ctx).count()
+	status.ListingsSkipped = index.count()
 	
 	// Send updated status
 	select {
@@ -403,29 +1093,128 @@ func (s *SimplyRETSService) processBatch(ctx context.Context, batch []models.Sim
 
 // processProperty processes a single property
 func (s *SimplyRETSService) processProperty(ctx context.Context, simplyProperty models.SimplyRETSProperty) error {
-	// Download images in parallel
-	photos, err := s.downloadImages(ctx, simplyProperty.Photos, simplyProperty.ListingID)
-	if err != nil {
-		return fmt.Errorf("failed to download images for property %s: %w", simplyProperty.ListingID, err)
+	lazy := lazyPhotosFromContext(ctx) && len(simplyProperty.Photos) > 0
+	var photos models.PhotoList
+	if lazy {
+		// Saved with the provider's URLs; the downloads are queued below
+		photos = remotePhotos(simplyProperty.Photos)
+	} else {
+		// Download images in parallel
+		var err error
+		photos, err = s.downloadImages(ctx, simplyProperty.Photos, simplyProperty.ListingID)
+		if err != nil {
+			return fmt.Errorf("failed to download images for property %s: %w", simplyProperty.ListingID, err)
+		}
 	}
 	
 	// Convert SimplyRETS property to our Property model
 	property := s.convertToProperty(simplyProperty, photos)
 	
+	// Update the listing if it was imported or entered before
+	existing, err := s.importedProperty(ctx, simplyProperty)
+	if err != nil {
+		return fmt.Errorf("failed to look up property %s: %w", simplyProperty.ListingID, err)
+	}
+	if existing != nil {
+		property = mergeImported(*existing, property)
+	}
+	property.NormalizePhotos()
+	
 	// Save to database
-	if err := s.propertyRepo.Create(ctx, &property); err != nil {
+	created, err := s.propertyRepo.Upsert(ctx, &property)
+	if err != nil {
 		return fmt.Errorf("failed to save property %s: %w", simplyProperty.ListingID, err)
 	}
 	
+	if s.amenityRepo != nil {
+		amenities := AmenitiesFromSimplyRETS(simplyProperty)
+		amenities.PropertyID = property.ID
+		if err := s.amenityRepo.Upsert(ctx, &amenities); err != nil {
+			return fmt.Errorf("failed to save amenities for property %s: %w", simplyProperty.ListingID, err)
+		}
+	}
+	
+	if created {
+		jobReportFromContext(ctx).change(simplyProperty, property.ID, "created")
+		publishEvent(ctx, s.events, events.PropertyCreated, propertySubject(property.ID), property)
+	} else {
+		jobReportFromContext(ctx).change(simplyProperty, property.ID, "updated")
+		publishEvent(ctx, s.events, events.PropertyUpdated, propertySubject(property.ID), property)
+	}
+	if lazy {
+		s.queuePhotoBackfill(ctx, property.ID, simplyProperty)
+	}
 	return nil
 }
 
+// importedProperty returns the property a feed listing was imported as
+// before by the organization in ctx or, failing that, a listing it entered
+// by hand with the same MLS number; nil if there is neither
+func (s *SimplyRETSService) importedProperty(ctx context.Context, simplyProperty models.SimplyRETSProperty) (*models.Property, error) {
+	property, err := s.propertyRepo.GetByExternalID(ctx, simplyProperty.ListingID)
+	if err != nil || property != nil {
+		return property, err
+	}
+	mlsNumber := simplyProperty.MLSNumber.String()
+	if mlsNumber == "" {
+		return nil, nil
+	}
+	property, err = s.propertyRepo.GetByMLSNumber(ctx, mlsNumber)
+	if err != nil || property == nil || property.ExternalID.Valid {
+		return nil, err
+	}
+	// Shared listings are visible to every organization but only updated
+	// by imports made outside one
+	tenant, _ := repository.TenantFromContext(ctx)
+	if int(property.OrganizationID.Int32) != tenant.OrganizationID {
+		return nil, nil
+	}
+	return property, nil
+}
+
+// mergeImported applies what an import sets to the property it updates,
+// keeping what is only kept locally, such as its status, agent, expiry and
+// commercial figures, and alt text edited on its photos
+func mergeImported(existing, imported models.Property) models.Property {
+	merged := existing
+	merged.Name, merged.Location, merged.Price = imported.Name, imported.Location, imported.Price
+	merged.Description, merged.Photos = imported.Description, keepEditedAltText(existing, imported.Photos)
+	merged.ExternalID, merged.MLSNumber, merged.PropertyType = imported.ExternalID, imported.MLSNumber, imported.PropertyType
+	merged.Bedrooms, merged.Bathrooms, merged.YearBuilt = imported.Bedrooms, imported.Bathrooms, imported.YearBuilt
+	merged.SquareFeet, merged.LotSize, merged.Acreage = imported.SquareFeet, imported.LotSize, imported.Acreage
+	merged.LivingAreaSqm, merged.LotAreaSqm = imported.LivingAreaSqm, imported.LotAreaSqm
+	merged.AnnualTax, merged.ParkingSpaces, merged.ParkingDescription = imported.AnnualTax, imported.ParkingSpaces, imported.ParkingDescription
+	merged.ListingType, merged.MonthlyRent, merged.Category = imported.ListingType, imported.MonthlyRent, imported.Category
+	merged.LastSyncedAt = imported.LastSyncedAt
+	return merged
+}
+
+// keepEditedAltText gives photos the alt text edited on the existing
+// property's photos with the same URL. Generated alt text is left out, so
+// it is generated again from the imported details.
+func keepEditedAltText(existing models.Property, photos models.PhotoList) models.PhotoList {
+	generated := existing.DefaultPhotoAltText()
+	edited := make(map[string]string)
+	for _, photo := range existing.Photos {
+		if photo.AltText != "" && photo.AltText != generated {
+			edited[photo.URL] = photo.AltText
+		}
+	}
+	for i := range photos {
+		if alt, ok := edited[photos[i].URL]; ok {
+			photos[i].AltText = alt
+		}
+	}
+	return photos
+}
+
 // downloadImages downloads property images in parallel
 func (s *SimplyRETSService) downloadImages(ctx context.Context, imageURLs []string, propertyID string) (models.PhotoList, error) {
 	if len(imageURLs) == 0 {
 		return models.PhotoList{}, nil
 	}
 	
+	manifest := s.loadPhotoManifest(ctx, propertyID)
 	var wg sync.WaitGroup
 	photosChan := make(chan models.Photo, len(imageURLs))
 	errorsChan := make(chan error, len(imageURLs))
@@ -443,17 +1232,13 @@ func (s *SimplyRETSService) downloadImages(ctx context.Context, imageURLs []stri
 			default:
 			}
 			
-			localPath, err := s.downloadImage(ctx, imageURL, propertyID, index)
+			photo, err := s.downloadImage(ctx, imageURL, propertyID, index, manifest[imageURL])
 			if err != nil {
 				errorsChan <- err
 				return
 			}
-			
-			photo := models.Photo{
-				URL:      imageURL,
-				LocalURL: localPath,
-				Caption:  fmt.Sprintf("Property image %d", index+1),
-			}
+			photo.Caption = fmt.Sprintf("Property image %d", index+1)
+			photo.Position = index
 			
 			photosChan <- photo
 		}(url, i)
@@ -469,6 +1254,8 @@ func (s *SimplyRETSService) downloadImages(ctx context.Context, imageURLs []stri
 	for photo := range photosChan {
 		photos = append(photos, photo)
 	}
+	// Downloads finish in any order; keep the feed's
+	slices.SortFunc(photos, func(a, b models.Photo) int { return a.Position - b.Position })
 	
 	// Check for errors
 	var errors []string
@@ -483,21 +1270,43 @@ func (s *SimplyRETSService) downloadImages(ctx context.Context, imageURLs []stri
 	return photos, nil
 }
 
-// downloadImage downloads a single image
-func (s *SimplyRETSService) downloadImage(ctx context.Context, imageURL, propertyID string, index int) (string, error) {
+// downloadImage downloads a single image and generates its thumbnails.
+// cached is the version an earlier sync downloaded, if any; it is kept
+// when the provider reports it unchanged.
+func (s *SimplyRETSService) downloadImage(ctx context.Context, imageURL, propertyID string, index int, cached *models.PhotoManifestEntry) (models.Photo, error) {
+	photo := models.Photo{URL: imageURL}
 	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create image request: %w", err)
+		return models.Photo{}, fmt.Errorf("failed to create image request: %w", err)
+	}
+	cached = s.cachedPhoto(ctx, cached)
+	if cached != nil {
+		req.Header.Set("If-None-Match", cached.ETag)
 	}
 	
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to download image: %w", err)
+		return models.Photo{}, fmt.Errorf("failed to download image: %w", err)
 	}
 	defer resp.Body.Close()
 	
+	// Providers that ignore If-None-Match still send the same ETag
+	if cached != nil && (resp.StatusCode == http.StatusNotModified || resp.Header.Get("ETag") == cached.ETag) {
+		photoTallyFromContext(ctx).skip()
+		photo.LocalURL = cached.LocalURL
+		s.addThumbnails(ctx, &photo, path.Base(cached.LocalURL), nil)
+		return photo, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("image download returned status %d", resp.StatusCode)
+		return models.Photo{}, fmt.Errorf("image download returned status %d", resp.StatusCode)
+	}
+	
+	owner, charged := storageOwnerFromContext(ctx)
+	charged = charged && s.storage != nil
+	if charged && resp.ContentLength > 0 {
+		if err := s.storage.CheckQuota(ctx, owner, resp.ContentLength); err != nil {
+			return models.Photo{}, err
+		}
 	}
 	
 	// Generate filename
@@ -506,23 +1315,38 @@ func (s *SimplyRETSService) downloadImage(ctx context.Context, imageURL, propert
 		ext = ".png"
 	}
 	filename := fmt.Sprintf("%s_%d%s", propertyID, index, ext)
-	filePath := filepath.Join(s.imagesDir, filename)
+	localURL := s.images.URL(filename)
 	
-	// Create file
-	file, err := os.Create(filePath)
+	// The original is kept in memory for its thumbnails
+	var content bytes.Buffer
+	size, err := s.images.Put(ctx, filename, io.TeeReader(resp.Body, &content))
 	if err != nil {
-		return "", fmt.Errorf("failed to create image file: %w", err)
+		return models.Photo{}, err
 	}
-	defer file.Close()
 	
-	// Copy image data
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to save image: %w", err)
+	if charged {
+		// Servers that omit Content-Length are checked after the download
+		if resp.ContentLength <= 0 {
+			if err := s.storage.CheckQuota(ctx, owner, size); err != nil {
+				s.images.Delete(ctx, filename)
+				return models.Photo{}, err
+			}
+		}
+		if err := s.storage.Record(ctx, owner, 0, models.FileKindPhoto, localURL, size); err != nil {
+			return models.Photo{}, fmt.Errorf("failed to record image storage: %w", err)
+		}
 	}
 	
-	// Return relative path for API access
-	return fmt.Sprintf("/images/%s", filename), nil
+	s.recordPhoto(ctx, models.PhotoManifestEntry{
+		ListingID: propertyID,
+		URL:       imageURL,
+		ETag:      resp.Header.Get("ETag"),
+		LocalURL:  localURL,
+		Size:      size,
+	})
+	photo.LocalURL = localURL
+	s.addThumbnails(ctx, &photo, filename, content.Bytes())
+	return photo, nil
 }
 
 // Helper functions for creating custom null types
@@ -540,9 +1364,23 @@ func nullInt32(i int) models.NullInt32 {
 	return models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(i), Valid: true}}
 }
 
+func nullFloat64(f float64) models.NullFloat64 {
+	if f == 0 {
+		return models.NullFloat64{NullFloat64: sql.NullFloat64{Valid: false}}
+	}
+	return models.NullFloat64{NullFloat64: sql.NullFloat64{Float64: f, Valid: true}}
+}
+
+func nullTime(t time.Time) models.NullTime {
+	if t.IsZero() {
+		return models.NullTime{NullTime: sql.NullTime{Valid: false}}
+	}
+	return models.NullTime{NullTime: sql.NullTime{Time: t, Valid: true}}
+}
+
 // convertToProperty converts SimplyRETS property to our Property model
 func (s *SimplyRETSService) convertToProperty(simplyProperty models.SimplyRETSProperty, photos models.PhotoList) models.Property {
-	return models.Property{
+	property := models.Property{
 		Name:         fmt.Sprintf("%s %s", simplyProperty.Address.StreetNumber.String(), simplyProperty.Address.StreetName),
 		Location:     simplyProperty.Address.Full,
 		Price:        simplyProperty.ListPrice,
@@ -556,5 +1394,32 @@ func (s *SimplyRETSService) convertToProperty(simplyProperty models.SimplyRETSPr
 		SquareFeet:   nullInt32(simplyProperty.Property.Area),
 		LotSize:      nullString(simplyProperty.Property.LotSize),
 		YearBuilt:    nullInt32(simplyProperty.Property.YearBuilt),
+		LastSyncedAt: nullTime(time.Now()),
+		Status:       models.PropertyStatusActive,
+
+		AnnualTax:          nullFloat64(simplyProperty.Tax.TaxAnnualAmount),
+		ParkingSpaces:      nullInt32(simplyProperty.Property.Parking.Spaces),
+		ParkingDescription: nullString(strings.TrimSpace(simplyProperty.Property.Parking.Description)),
+		ListingType:        models.ListingTypeSale,
+		Category:           models.CategoryResidential,
+	}
+	switch simplyProperty.Property.PropertyType {
+	case models.SimplyRETSTypeCommercial:
+		property.Category = models.CategoryCommercial
+	case models.SimplyRETSTypeLand:
+		// Feeds fill the structure fields of land with zeros at best
+		property.Category = models.CategoryLand
+		property.Bedrooms, property.Bathrooms = models.NullInt32{}, models.NullInt32{}
+		property.SquareFeet, property.YearBuilt = models.NullInt32{}, models.NullInt32{}
+		if acres := simplyProperty.Property.Acres; acres > 0 {
+			property.Acreage = &acres
+		}
+	}
+	if simplyProperty.Property.PropertyType == models.SimplyRETSTypeRental {
+		property.ListingType = models.ListingTypeRent
+		property.MonthlyRent = nullFloat64(simplyProperty.ListPrice)
+		property.Price = 0
 	}
+	property.NormalizeMeasurements()
+	return property
 }
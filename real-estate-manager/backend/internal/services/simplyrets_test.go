@@ -5,16 +5,25 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
 	"real-estate-manager/backend/internal/mocks"
 	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/worker"
+	"real-estate-manager/backend/pkg/imagestore"
+	"real-estate-manager/backend/pkg/mlsauth"
 
 	"go.uber.org/mock/gomock"
 )
@@ -49,6 +58,44 @@ func TestNewSimplyRETSService(t *testing.T) {
 	}
 }
 
+func TestSimplyRETSConfig(t *testing.T) {
+	imagesDir := filepath.Join(t.TempDir(), "images")
+	service := NewSimplyRETSService(nil, WithConfig(SimplyRETSConfig{
+		BaseURL:   "https://mls.example.com/v2/",
+		Username:  "broker",
+		Password:  "secret",
+		ImagesDir: imagesDir,
+	}))
+	if service.baseURL != "https://mls.example.com/v2" || service.username != "broker" || service.password != "secret" {
+		t.Errorf("Expected the configured feed, got %s as %s", service.baseURL, service.username)
+	}
+	if service.imagesDir != imagesDir {
+		t.Errorf("Expected the configured images directory, got %s", service.imagesDir)
+	}
+	if _, err := os.Stat(imagesDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the images directory to wait for the first photo, got %v", err)
+	}
+
+	// Empty fields keep the demo feed
+	service = NewSimplyRETSService(nil, WithConfig(SimplyRETSConfig{}))
+	if service.baseURL != DefaultSimplyRETSBaseURL || service.username != "simplyrets" || service.imagesDir != DefaultImagesDir {
+		t.Errorf("Expected the demo feed, got %s as %s", service.baseURL, service.username)
+	}
+
+	for _, config := range []SimplyRETSConfig{
+		{BaseURL: "api.simplyrets.com"},
+		{BaseURL: "ftp://mls.example.com"},
+		{Username: "broker"},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+	if err := (SimplyRETSConfig{BaseURL: "http://localhost:9000", Username: "a", Password: "b"}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
 func TestJobManager_AddJob(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -272,7 +319,7 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 		setupMock   func(mock *mocks.MockPropertyRepository)
 		expectError bool
 		errorMsg    string
-		verify      func(t *testing.T, jobID string)
+		verify      func(t *testing.T, manager *JobManager, jobID string)
 	}{
 		{
 			name:  "successful processing start",
@@ -282,9 +329,9 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 				// Mock will be called during actual processing in goroutine
 			},
 			expectError: false,
-			verify: func(t *testing.T, jobID string) {
+			verify: func(t *testing.T, manager *JobManager, jobID string) {
 				// Verify job was added to manager
-				job, exists := GlobalJobManager.GetJob(jobID)
+				job, exists := manager.GetJob(jobID)
 				if !exists {
 					t.Error("Job should exist in manager")
 				}
@@ -307,10 +354,10 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 					select {
 					case <-timeout:
 						// Force cleanup if timeout
-						GlobalJobManager.RemoveJob(jobID)
+						manager.RemoveJob(jobID)
 						return
 					case <-ticker.C:
-						if _, exists := GlobalJobManager.GetJob(jobID); !exists {
+						if _, exists := manager.GetJob(jobID); !exists {
 							return
 						}
 					}
@@ -325,10 +372,10 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 				// Mock will be called during actual processing
 			},
 			expectError: false,
-			verify: func(t *testing.T, jobID string) {
+			verify: func(t *testing.T, manager *JobManager, jobID string) {
 				// Wait a bit for processing to start and then cancel to clean up
 				time.Sleep(10 * time.Millisecond)
-				job, exists := GlobalJobManager.GetJob(jobID)
+				job, exists := manager.GetJob(jobID)
 				if exists && job.Cancel != nil {
 					job.Cancel()
 				}
@@ -342,10 +389,10 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 					select {
 					case <-timeout:
 						// Force cleanup if timeout
-						GlobalJobManager.RemoveJob(jobID)
+						manager.RemoveJob(jobID)
 						return
 					case <-ticker.C:
-						if _, exists := GlobalJobManager.GetJob(jobID); !exists {
+						if _, exists := manager.GetJob(jobID); !exists {
 							return
 						}
 					}
@@ -375,7 +422,7 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 			service.baseURL = server.URL // Use test server
 			ctx := context.Background()
 
-			err := service.StartPropertyProcessing(ctx, tt.jobID, tt.limit)
+			err := service.StartPropertyProcessing(ctx, tt.jobID, tt.limit, &models.JobDetails{})
 
 			if tt.expectError {
 				if err == nil {
@@ -390,7 +437,7 @@ func TestSimplyRETSService_StartPropertyProcessing(t *testing.T) {
 			}
 
 			if !tt.expectError {
-				tt.verify(t, tt.jobID)
+				tt.verify(t, service.manager, tt.jobID)
 			}
 		})
 	}
@@ -492,13 +539,13 @@ func TestSimplyRETSService_GetJobStatus(t *testing.T) {
 
 			// Setup job if needed
 			if job := tt.setupJob(); job != nil {
-				GlobalJobManager.AddJob(tt.jobID, job)
-				defer GlobalJobManager.RemoveJob(tt.jobID)
+				service.manager.AddJob(tt.jobID, job)
+				defer service.manager.RemoveJob(tt.jobID)
 			}
 
-			status, found := service.GetJobStatus(tt.jobID)
+			status, err := service.GetJobStatus(context.Background(), tt.jobID, JobScope{All: true})
 
-			if found != tt.expectFound {
+			if found := err == nil; found != tt.expectFound {
 				t.Errorf("Expected found %t, got %t", tt.expectFound, found)
 			}
 
@@ -558,18 +605,18 @@ func TestSimplyRETSService_CancelJob(t *testing.T) {
 
 			// Setup job if needed
 			if job := tt.setupJob(); job != nil {
-				GlobalJobManager.AddJob(tt.jobID, job)
+				service.manager.AddJob(tt.jobID, job)
 			}
 
-			success := service.CancelJob(tt.jobID)
+			err := service.CancelJob(tt.jobID, JobScope{All: true})
 
-			if success != tt.expectSuccess {
+			if success := err == nil; success != tt.expectSuccess {
 				t.Errorf("Expected success %t, got %t", tt.expectSuccess, success)
 			}
 
 			// Verify job was removed if cancelled successfully
 			if tt.expectSuccess {
-				_, exists := GlobalJobManager.GetJob(tt.jobID)
+				_, exists := service.manager.GetJob(tt.jobID)
 				if exists {
 					t.Error("Job should have been removed after cancellation")
 				}
@@ -680,7 +727,11 @@ func TestSimplyRETSService_fetchProperties(t *testing.T) {
 			service.baseURL = server.URL
 
 			ctx := context.Background()
-			properties, err := service.fetchProperties(ctx, tt.limit)
+			var properties []models.SimplyRETSProperty
+			_, err := service.fetchProperties(ctx, tt.limit, func(property models.SimplyRETSProperty) error {
+				properties = append(properties, property)
+				return nil
+			})
 
 			if tt.expectError {
 				if err == nil {
@@ -698,6 +749,47 @@ func TestSimplyRETSService_fetchProperties(t *testing.T) {
 	}
 }
 
+func TestDecodeProperties(t *testing.T) {
+	var ids []string
+	collect := func(property models.SimplyRETSProperty) error {
+		ids = append(ids, property.ListingID)
+		return nil
+	}
+
+	count, err := decodeProperties(strings.NewReader(`[{"listingId": "A1"}, {"listingId": "B2"}, {"listingId": "C3"}]`), collect)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 3 || strings.Join(ids, ",") != "A1,B2,C3" {
+		t.Errorf("Unexpected listings %v (count %d)", ids, count)
+	}
+
+	if count, err := decodeProperties(strings.NewReader("null"), collect); err != nil || count != 0 {
+		t.Errorf("Expected a null page to hold no listings, got %d (%v)", count, err)
+	}
+
+	// Listings before a malformed one have already been passed on
+	ids = nil
+	count, err = decodeProperties(strings.NewReader(`[{"listingId": "A1"}, {"listingId": 2}]`), collect)
+	if err == nil || !strings.Contains(err.Error(), "failed to decode response") {
+		t.Errorf("Expected a decode error, got %v", err)
+	}
+	if count != 1 || strings.Join(ids, ",") != "A1" {
+		t.Errorf("Unexpected listings %v (count %d)", ids, count)
+	}
+
+	if _, err := decodeProperties(strings.NewReader(`{"listingId": "A1"}`), collect); err == nil {
+		t.Error("Expected an error for a page that is not an array")
+	}
+
+	// An error from yield stops decoding as it is
+	stop := errors.New("stop")
+	count, err = decodeProperties(strings.NewReader(`[{"listingId": "A1"}, {"listingId": "B2"}]`), func(models.SimplyRETSProperty) error { return stop })
+	if err != stop || count != 1 {
+		t.Errorf("Expected decoding to stop at the first listing, got %d (%v)", count, err)
+	}
+}
+
 func TestSimplyRETSService_processProperty(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -728,9 +820,11 @@ func TestSimplyRETSService_processProperty(t *testing.T) {
 				Remarks: "Nice condo",
 			},
 			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().GetByExternalID(gomock.Any(), "test-123").Return(nil, nil)
+				mock.EXPECT().GetByMLSNumber(gomock.Any(), "MLS123").Return(nil, nil)
 				mock.EXPECT().
-					Create(gomock.Any(), gomock.Any()).
-					Return(nil).
+					Upsert(gomock.Any(), gomock.Any()).
+					Return(true, nil).
 					Times(1)
 			},
 			setupServer: func() *httptest.Server {
@@ -753,9 +847,11 @@ func TestSimplyRETSService_processProperty(t *testing.T) {
 				Photos:    []string{},
 			},
 			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().GetByExternalID(gomock.Any(), "test-456").Return(nil, nil)
+				mock.EXPECT().GetByMLSNumber(gomock.Any(), "MLS456").Return(nil, nil)
 				mock.EXPECT().
-					Create(gomock.Any(), gomock.Any()).
-					Return(errors.New("database error")).
+					Upsert(gomock.Any(), gomock.Any()).
+					Return(false, errors.New("database error")).
 					Times(1)
 			},
 			setupServer: func() *httptest.Server {
@@ -783,6 +879,7 @@ func TestSimplyRETSService_processProperty(t *testing.T) {
 
 			service := NewSimplyRETSService(mockRepo)
 			service.imagesDir = tempDir
+			service.images = imagestore.NewLocal(service.imagesDir)
 
 			if tt.setupServer != nil {
 				server := tt.setupServer()
@@ -809,6 +906,67 @@ func TestSimplyRETSService_processProperty(t *testing.T) {
 	}
 }
 
+func TestSimplyRETSService_processPropertyUpdatesExisting(t *testing.T) {
+	agent := models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}
+	listing := models.SimplyRETSProperty{
+		ListingID: "test-123",
+		MLSNumber: "MLS123",
+		Address:   models.SimplyRETSAddress{Full: "123 Test St, Test City, TS", StreetNumber: "123", StreetName: "Test St"},
+		ListPrice: 310000.0,
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(mock *mocks.MockPropertyRepository, existing *models.Property)
+	}{
+		{
+			name: "listing imported before",
+			setupMock: func(mock *mocks.MockPropertyRepository, existing *models.Property) {
+				existing.ExternalID = models.NullString{NullString: sql.NullString{String: "test-123", Valid: true}}
+				mock.EXPECT().GetByExternalID(gomock.Any(), "test-123").Return(existing, nil)
+			},
+		},
+		{
+			name: "listing entered by hand with its MLS number",
+			setupMock: func(mock *mocks.MockPropertyRepository, existing *models.Property) {
+				mock.EXPECT().GetByExternalID(gomock.Any(), "test-123").Return(nil, nil)
+				mock.EXPECT().GetByMLSNumber(gomock.Any(), "MLS123").Return(existing, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			existing := &models.Property{ID: 12, Name: "Old name", Price: 300000, Status: models.PropertyStatusPending, AgentID: agent}
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo, existing)
+			var saved models.Property
+			mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, property *models.Property) (bool, error) {
+				saved = *property
+				return false, nil
+			})
+
+			publisher := &recordingPublisher{}
+			service := NewSimplyRETSService(mockRepo, WithImportEvents(publisher))
+			if err := service.processProperty(context.Background(), listing); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if saved.ID != 12 || saved.Name != "123 Test St" || saved.Price != 310000 || saved.ExternalID.String != "test-123" {
+				t.Errorf("Expected the feed's values on property 12, got %+v", saved)
+			}
+			if saved.Status != models.PropertyStatusPending || saved.AgentID != agent {
+				t.Errorf("Expected the local status and agent to be kept, got %s and %+v", saved.Status, saved.AgentID)
+			}
+			if len(publisher.events) != 1 || publisher.events[0].Type != events.PropertyUpdated {
+				t.Errorf("Expected a %s event, got %+v", events.PropertyUpdated, publisher.events)
+			}
+		})
+	}
+}
+
 func TestSimplyRETSService_downloadImages(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -835,30 +993,15 @@ func TestSimplyRETSService_downloadImages(t *testing.T) {
 					t.Errorf("Expected 2 photos, got %d", len(photos))
 				}
 				
-				// Check that we have the expected captions (order may vary due to concurrent processing)
-				expectedCaptions := map[string]bool{
-					"Property image 1": false,
-					"Property image 2": false,
-				}
-				
-				for _, photo := range photos {
-					if _, exists := expectedCaptions[photo.Caption]; exists {
-						expectedCaptions[photo.Caption] = true
-					} else {
-						t.Errorf("Unexpected caption '%s'", photo.Caption)
+				// Photos keep the feed's order however the downloads finish
+				for i, photo := range photos {
+					if expected := fmt.Sprintf("Property image %d", i+1); photo.Caption != expected || photo.Position != i {
+						t.Errorf("Expected photo %d to be %q, got %q at %d", i, expected, photo.Caption, photo.Position)
 					}
-					
 					if !strings.Contains(photo.LocalURL, "prop123") {
 						t.Errorf("Expected local URL to contain property ID, got '%s'", photo.LocalURL)
 					}
 				}
-				
-				// Verify all expected captions were found
-				for caption, found := range expectedCaptions {
-					if !found {
-						t.Errorf("Expected caption '%s' not found", caption)
-					}
-				}
 			},
 		},
 		{
@@ -907,6 +1050,7 @@ func TestSimplyRETSService_downloadImages(t *testing.T) {
 			mockRepo := mocks.NewMockPropertyRepository(ctrl)
 			service := NewSimplyRETSService(mockRepo)
 			service.imagesDir = tempDir
+			service.images = imagestore.NewLocal(service.imagesDir)
 
 			var imageURLs []string
 			if tt.setupServer != nil {
@@ -1022,13 +1166,14 @@ func TestSimplyRETSService_downloadImage(t *testing.T) {
 			mockRepo := mocks.NewMockPropertyRepository(ctrl)
 			service := NewSimplyRETSService(mockRepo)
 			service.imagesDir = tempDir
+			service.images = imagestore.NewLocal(service.imagesDir)
 
 			server := tt.setupServer()
 			defer server.Close()
 
 			imageURL := server.URL + tt.imageURL
 			ctx := context.Background()
-			localPath, err := service.downloadImage(ctx, imageURL, tt.propertyID, tt.index)
+			photo, err := service.downloadImage(ctx, imageURL, tt.propertyID, tt.index, nil)
 
 			if tt.expectError {
 				if err == nil {
@@ -1040,10 +1185,10 @@ func TestSimplyRETSService_downloadImage(t *testing.T) {
 				if err != nil {
 					t.Errorf("Expected no error but got: %v", err)
 				}
-				tt.verifyResult(t, localPath)
+				tt.verifyResult(t, photo.LocalURL)
 
 				// Verify file was actually created
-				fullPath := filepath.Join(tempDir, filepath.Base(localPath))
+				fullPath := filepath.Join(tempDir, filepath.Base(photo.LocalURL))
 				if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 					t.Error("Image file was not created")
 				}
@@ -1052,6 +1197,134 @@ func TestSimplyRETSService_downloadImage(t *testing.T) {
 	}
 }
 
+func TestSimplyRETSService_downloadImagesSkipsUnchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("fake jpeg data"))
+	}))
+	defer server.Close()
+	imageURL := server.URL + "/front.jpg"
+
+	var recorded []models.PhotoManifestEntry
+	mockManifest := mocks.NewMockPhotoManifestRepository(ctrl)
+	mockManifest.EXPECT().ListByListing(gomock.Any(), "L-100").Return([]models.PhotoManifestEntry{}, nil)
+	mockManifest.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry *models.PhotoManifestEntry) error {
+		recorded = append(recorded, *entry)
+		return nil
+	})
+
+	service := NewSimplyRETSService(mocks.NewMockPropertyRepository(ctrl), WithPhotoManifest(mockManifest))
+	service.imagesDir = t.TempDir()
+	service.images = imagestore.NewLocal(service.imagesDir)
+
+	tally := &photoTally{}
+	ctx := withPhotoTally(context.Background(), tally)
+	if _, err := service.downloadImages(ctx, []string{imageURL}, "L-100"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(recorded) != 1 || recorded[0].ETag != `"v1"` || recorded[0].LocalURL != "/images/L-100_0.jpg" {
+		t.Fatalf("Expected the download to be recorded, got %+v", recorded)
+	}
+
+	// The next sync finds the photo in the manifest and the provider
+	// reports it unchanged
+	mockManifest.EXPECT().ListByListing(gomock.Any(), "L-100").Return(recorded, nil)
+	photos, err := service.downloadImages(ctx, []string{imageURL}, "L-100")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(photos) != 1 || photos[0].LocalURL != "/images/L-100_0.jpg" {
+		t.Errorf("Expected the cached photo, got %+v", photos)
+	}
+	if downloads != 1 || tally.count() != 1 {
+		t.Errorf("Expected 1 download and 1 skipped photo, got %d and %d", downloads, tally.count())
+	}
+
+	// A photo whose local copy is gone is downloaded again
+	os.Remove(filepath.Join(service.imagesDir, "L-100_0.jpg"))
+	mockManifest.EXPECT().ListByListing(gomock.Any(), "L-100").Return(recorded, nil)
+	mockManifest.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+	if _, err := service.downloadImages(ctx, []string{imageURL}, "L-100"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if downloads != 2 {
+		t.Errorf("Expected the missing photo to be downloaded again, got %d downloads", downloads)
+	}
+}
+
+func TestSimplyRETSService_LazyPhotos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("fake jpeg data"))
+	}))
+	defer server.Close()
+	listing := models.SimplyRETSProperty{ListingID: "L-200", Photos: []string{server.URL + "/front.jpg", server.URL + "/kitchen.jpg"}}
+
+	saved := &models.Property{}
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByExternalID(gomock.Any(), "L-200").Return(nil, nil)
+	mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, property *models.Property) (bool, error) {
+		for _, photo := range property.Photos {
+			if photo.LocalURL != "" {
+				t.Errorf("Expected the listing to be saved before its photos are downloaded, got %+v", photo)
+			}
+		}
+		property.ID = 9
+		property.Version = 1
+		*saved = *property
+		return true, nil
+	})
+	mockRepo.EXPECT().GetByID(gomock.Any(), 9).DoAndReturn(func(_ context.Context, id int) (*models.Property, error) {
+		property := *saved
+		property.Photos = append(models.PhotoList{}, saved.Photos...)
+		return &property, nil
+	})
+	var attached models.PhotoList
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, property *models.Property) error {
+		attached = property.Photos
+		return nil
+	})
+
+	backfill := worker.New("photo_backfill", 1, 10)
+	service := NewSimplyRETSService(mockRepo, WithPhotoBackfill(backfill))
+	service.imagesDir = t.TempDir()
+	service.images = imagestore.NewLocal(service.imagesDir)
+
+	if err := service.processProperty(withLazyPhotos(context.Background()), listing); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	backfill.Stop(context.Background())
+
+	if len(attached) != 2 {
+		t.Fatalf("Expected both photos to be attached, got %+v", attached)
+	}
+	for i, photo := range attached {
+		if photo.URL != listing.Photos[i] || !strings.HasPrefix(photo.LocalURL, "/images/L-200_") {
+			t.Errorf("Expected photo %d to keep its order and gain a local copy, got %+v", i, photo)
+		}
+	}
+
+	// Jobs may only ask for lazy photos when there is somewhere to queue them
+	eager := NewSimplyRETSService(mockRepo)
+	err := eager.StartPropertyProcessing(context.Background(), "test-job-lazy", 5, &models.JobDetails{LazyPhotos: true})
+	if !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
 func TestSimplyRETSService_convertToProperty(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1077,8 +1350,10 @@ func TestSimplyRETSService_convertToProperty(t *testing.T) {
 					Area:         1800,
 					YearBuilt:    2010,
 					LotSize:      "0.25 acres",
+					Parking:      models.SimplyRETSParking{Spaces: 2, Description: "Attached Garage "},
 				},
 				Remarks: "Beautiful family home with modern amenities",
+				Tax:     models.SimplyRETSTax{TaxYear: 2023, TaxAnnualAmount: 6120.45},
 			},
 			photos: models.PhotoList{
 				{URL: "http://example.com/photo1.jpg", LocalURL: "/images/12345_0.jpg"},
@@ -1125,6 +1400,18 @@ func TestSimplyRETSService_convertToProperty(t *testing.T) {
 				if !property.YearBuilt.Valid || property.YearBuilt.Int32 != 2010 {
 					t.Errorf("Expected year built to be 2010, got %+v", property.YearBuilt)
 				}
+				if !property.AnnualTax.Valid || property.AnnualTax.Float64 != 6120.45 {
+					t.Errorf("Expected annual tax 6120.45, got %+v", property.AnnualTax)
+				}
+				if property.ParkingSpaces.Int32 != 2 || property.ParkingDescription.String != "Attached Garage" {
+					t.Errorf("Expected 2 parking spaces in an attached garage, got %+v %+v", property.ParkingSpaces, property.ParkingDescription)
+				}
+				if !property.LivingAreaSqm.Valid || property.LivingAreaSqm.Float64 != 167.23 {
+					t.Errorf("Expected living area 167.23 m2, got %+v", property.LivingAreaSqm)
+				}
+				if !property.LotAreaSqm.Valid || property.LotAreaSqm.Float64 != 1011.71 {
+					t.Errorf("Expected lot area 1011.71 m2, got %+v", property.LotAreaSqm)
+				}
 			},
 		},
 		{
@@ -1180,6 +1467,40 @@ func TestSimplyRETSService_convertToProperty(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "rental priced by its monthly rent",
+			simplyProperty: models.SimplyRETSProperty{
+				ListingID: "77",
+				Address:   models.SimplyRETSAddress{Full: "9 Pine St", StreetNumber: "9", StreetName: "Pine St"},
+				ListPrice: 2400.0,
+				Property:  models.SimplyRETSPropertyDetails{PropertyType: models.SimplyRETSTypeRental},
+			},
+			verifyResult: func(t *testing.T, property models.Property) {
+				if property.ListingType != models.ListingTypeRent || property.Price != 0 {
+					t.Errorf("Expected a rental without a sale price, got %q at %f", property.ListingType, property.Price)
+				}
+				if !property.MonthlyRent.Valid || property.MonthlyRent.Float64 != 2400 {
+					t.Errorf("Expected monthly rent 2400, got %+v", property.MonthlyRent)
+				}
+			},
+		},
+		{
+			name: "land without a structure",
+			simplyProperty: models.SimplyRETSProperty{
+				ListingID: "78",
+				Address:   models.SimplyRETSAddress{Full: "Ranch Rd", StreetName: "Ranch Rd"},
+				ListPrice: 180000.0,
+				Property:  models.SimplyRETSPropertyDetails{PropertyType: models.SimplyRETSTypeLand, Bedrooms: 1, Acres: 4.5},
+			},
+			verifyResult: func(t *testing.T, property models.Property) {
+				if property.Category != models.CategoryLand || property.Bedrooms.Valid {
+					t.Errorf("Expected land without bedrooms, got %q with %+v", property.Category, property.Bedrooms)
+				}
+				if property.Acreage == nil || *property.Acreage != 4.5 || !property.LotAreaSqm.Valid {
+					t.Errorf("Expected 4.5 acres as the lot area, got %v and %+v", property.Acreage, property.LotAreaSqm)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1267,3 +1588,394 @@ func TestHelperFunctions(t *testing.T) {
 		}
 	})
 }
+
+func TestSimplyRETSService_FetchPropertiesRefreshesRejectedToken(t *testing.T) {
+	issued := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fmt.Sprintf(`{"access_token": "token-%d", "expires_in": 3600}`, issued)))
+	}))
+	defer tokenServer.Close()
+
+	// The provider revoked the first token before it expired
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"mlsId": 1}]`))
+	}))
+	defer apiServer.Close()
+
+	auth := mlsauth.NewClientCredentials(tokenServer.Client(), tokenServer.URL, "client", "secret", "")
+	service := NewSimplyRETSService(nil, WithAuth(auth))
+	service.baseURL = apiServer.URL
+
+	count, err := service.fetchProperties(context.Background(), 10, func(models.SimplyRETSProperty) error { return nil })
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 property, got %d", count)
+	}
+	if issued != 2 {
+		t.Errorf("Expected the rejected token to be replaced once, got %d tokens", issued)
+	}
+}
+
+func TestSimplyRETSService_JobHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	completed := make(chan models.ProcessingStatus, 1)
+	mockJobs := mocks.NewMockJobRepository(ctrl)
+	mockJobs.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, job *models.ProcessingJob, liveSince time.Time) error {
+		if job.ID != "test-job-history" || job.Label != "backfill" || job.Metadata["source"] != "ticket" || job.CreatedBy.Int32 != 4 {
+			t.Errorf("Unexpected job record %+v", job)
+		}
+		if !liveSince.Equal(job.StartedAt.Add(-jobStaleAfter)) {
+			t.Errorf("Expected jobs with a heartbeat since %v to count as running, got %v", job.StartedAt.Add(-jobStaleAfter), liveSince)
+		}
+		return nil
+	})
+	mockJobs.EXPECT().Complete(gomock.Any(), "test-job-history", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id string, status models.ProcessingStatus) error {
+			completed <- status
+			return nil
+		})
+
+	service := NewSimplyRETSService(nil, WithJobHistory(mockJobs))
+	service.baseURL = server.URL
+	details := &models.JobDetails{Label: " backfill ", Metadata: models.JobMetadata{"source": "ticket"}}
+	if err := service.StartPropertyProcessing(WithActor(context.Background(), 4), "test-job-history", 5, details); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	defer service.manager.RemoveJob("test-job-history")
+
+	select {
+	case status := <-completed:
+		if status.Status != "completed" {
+			t.Errorf("Expected the job to complete, got %s", status.Status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job's completion to be recorded")
+	}
+
+	// A job running anywhere else refuses another
+	mockJobs.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrJobRunning)
+	if err := service.StartPropertyProcessing(context.Background(), "test-job-overlap", 5, &models.JobDetails{}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+
+	// Oversized details are refused before the job starts
+	for _, details := range []*models.JobDetails{
+		{Label: strings.Repeat("x", 65)},
+		{Description: strings.Repeat("x", 501)},
+		{Metadata: models.JobMetadata{"notes": strings.Repeat("x", 4096)}},
+	} {
+		if err := service.StartPropertyProcessing(context.Background(), "test-job-refused", 5, details); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	}
+}
+
+func TestSimplyRETSService_OneJobAtATime(t *testing.T) {
+	// Without the job history, only jobs on this server are known
+	service := NewSimplyRETSService(nil)
+	service.manager.AddJob("running-job", &ProcessingJob{ID: "running-job", Status: make(chan models.ProcessingStatus, 1), Cancel: func() {}})
+	defer service.manager.RemoveJob("running-job")
+
+	if err := service.StartPropertyProcessing(context.Background(), "test-job-overlap", 5, &models.JobDetails{}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+}
+
+func TestSimplyRETSService_JobArtifacts(t *testing.T) {
+	page := `[{"mlsId": 1, "listingId": "A1", "address": {"full": "1 Main St"}}, {"mlsId": 2, "listingId": "B2", "address": {"full": "2 Main St"}}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().GetByExternalID(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mockRepo.EXPECT().GetByMLSNumber(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, property *models.Property) (bool, error) {
+		if property.ExternalID.String == "B2" {
+			return false, errors.New("duplicate listing")
+		}
+		property.ID = 12
+		return true, nil
+	}).Times(2)
+
+	dir := t.TempDir()
+	service := NewSimplyRETSService(mockRepo, WithJobArtifacts(dir))
+	service.baseURL = server.URL
+	if err := service.StartPropertyProcessing(context.Background(), "test-job-artifacts", 2, &models.JobDetails{}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	defer service.manager.RemoveJob("test-job-artifacts")
+
+	var status *models.ProcessingStatus
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if status, _ = service.GetJobStatus(context.Background(), "test-job-artifacts", JobScope{All: true}); status.CompletedAt != nil {
+			break
+		}
+	}
+	if status.CompletedAt == nil {
+		t.Fatal("Expected the job to finish")
+	}
+	if strings.Join(status.Artifacts, ",") != "errors.csv,changes.csv,pages.json,page-001.json" {
+		t.Errorf("Unexpected artifacts %v", status.Artifacts)
+	}
+
+	read := func(name string) string {
+		path, err := service.JobArtifact(context.Background(), "test-job-artifacts", name, JobScope{All: true})
+		if err != nil {
+			t.Fatalf("Expected %s to exist, got %v", name, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if errorsCSV := read(ArtifactErrors); !strings.Contains(errorsCSV, "2,B2,2 Main St,") || !strings.Contains(errorsCSV, "duplicate listing") {
+		t.Errorf("Unexpected errors.csv %q", errorsCSV)
+	}
+	if changes := read(ArtifactChanges); changes != "mls_id,listing_id,property_id,action\n1,A1,12,created\n" {
+		t.Errorf("Unexpected changes.csv %q", changes)
+	}
+	if raw := read("page-001.json"); raw != page {
+		t.Errorf("Expected the page to be archived as sent, got %q", raw)
+	}
+	var pages []ArchivedPage
+	if err := json.Unmarshal([]byte(read(ArtifactPages)), &pages); err != nil || len(pages) != 1 || pages[0].Properties != 2 {
+		t.Errorf("Unexpected page index %+v (%v)", pages, err)
+	}
+
+	for _, target := range [][2]string{{"test-job-artifacts", "../../secret.json"}, {"..", "pages.json"}, {"test-job-artifacts", "missing.csv"}} {
+		if _, err := service.JobArtifact(context.Background(), target[0], target[1], JobScope{All: true}); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("Expected %v to be not found, got %v", target, err)
+		}
+	}
+}
+
+func TestSimplyRETSService_JobScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A finished job only the history still knows about
+	mockJobs := mocks.NewMockJobRepository(ctrl)
+	mockJobs.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id string) (*models.ProcessingJob, error) {
+		if id != "finished-job" {
+			return nil, nil
+		}
+		return &models.ProcessingJob{ID: id, CreatedBy: models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}},
+			OrganizationID: models.NullInt32{NullInt32: sql.NullInt32{Int32: 3, Valid: true}}}, nil
+	}).AnyTimes()
+
+	manager := NewJobManager()
+	service := NewSimplyRETSService(nil, WithJobManager(manager), WithJobHistory(mockJobs))
+	_, cancel := context.WithCancel(context.Background())
+	manager.AddJob("org-job", &ProcessingJob{ID: "org-job", UserID: 4, OrganizationID: 3, Status: make(chan models.ProcessingStatus, 1), Cancel: cancel})
+	manager.AddJob("own-job", &ProcessingJob{ID: "own-job", UserID: 5, Status: make(chan models.ProcessingStatus, 1), Cancel: cancel})
+	manager.AddJob("system-job", &ProcessingJob{ID: "system-job", Status: make(chan models.ProcessingStatus, 1), Cancel: cancel})
+
+	tests := []struct {
+		name   string
+		jobID  string
+		scope  JobScope
+		expect error
+	}{
+		{name: "creator", jobID: "org-job", scope: JobScope{UserID: 4, OrganizationID: 3}},
+		{name: "colleague in the organization", jobID: "org-job", scope: JobScope{UserID: 6, OrganizationID: 3}, expect: apperrors.ErrForbidden},
+		{name: "another organization", jobID: "org-job", scope: JobScope{UserID: 7, OrganizationID: 8}, expect: apperrors.ErrNotFound},
+		{name: "own job without an organization", jobID: "own-job", scope: JobScope{UserID: 5}},
+		{name: "someone else's job without an organization", jobID: "own-job", scope: JobScope{UserID: 6}, expect: apperrors.ErrNotFound},
+		{name: "system job", jobID: "system-job", scope: JobScope{UserID: 6}, expect: apperrors.ErrNotFound},
+		{name: "admin", jobID: "system-job", scope: JobScope{All: true}},
+		{name: "finished job creator", jobID: "finished-job", scope: JobScope{UserID: 4, OrganizationID: 3}},
+		{name: "finished job colleague", jobID: "finished-job", scope: JobScope{UserID: 6, OrganizationID: 3}, expect: apperrors.ErrForbidden},
+		{name: "unknown job", jobID: "unknown-job", scope: JobScope{All: true}, expect: apperrors.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.authorizeJob(context.Background(), tt.jobID, tt.scope)
+			if tt.expect == nil && err != nil || tt.expect != nil && !errors.Is(err, tt.expect) {
+				t.Errorf("Expected %v, got %v", tt.expect, err)
+			}
+		})
+	}
+
+	if _, err := service.GetJobStatus(context.Background(), "org-job", JobScope{UserID: 6, OrganizationID: 3}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected a colleague not to see the job's status, got %v", err)
+	}
+	if err := service.CancelJob("org-job", JobScope{UserID: 6, OrganizationID: 3}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected a colleague not to cancel the job, got %v", err)
+	}
+	if _, exists := manager.GetJob("org-job"); !exists {
+		t.Error("Expected the job to be left running")
+	}
+	if err := service.CancelJob("org-job", JobScope{UserID: 4, OrganizationID: 3}); err != nil {
+		t.Errorf("Expected the creator to cancel the job, got %v", err)
+	}
+	// The history only lists non-admins their own jobs
+	mockJobs.EXPECT().List(gomock.Any(), models.JobFilter{CreatedBy: 6, Limit: 50}).Return([]models.ProcessingJob{}, nil)
+	mockJobs.EXPECT().List(gomock.Any(), models.JobFilter{Limit: 50}).Return([]models.ProcessingJob{}, nil)
+	if _, err := service.ListJobs(context.Background(), models.JobFilter{CreatedBy: 4}, JobScope{UserID: 6, OrganizationID: 3}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := service.ListJobs(context.Background(), models.JobFilter{}, JobScope{All: true}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// Each service keeps its own jobs unless a manager is shared
+	if _, err := NewSimplyRETSService(nil).GetJobStatus(context.Background(), "own-job", JobScope{All: true}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected another service not to see the job, got %v", err)
+	}
+}
+
+func TestSimplyRETSService_ResumeJob(t *testing.T) {
+	page := `[{"mlsId": 1, "listingId": "A1"}, {"mlsId": 2, "listingId": "B2"}, {"mlsId": 3, "listingId": "C3"}, {"mlsId": 3, "listingId": "C3"}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A1 was saved before the job failed
+	creator := models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}
+	mockJobs := mocks.NewMockJobRepository(ctrl)
+	mockJobs.EXPECT().GetByID(gomock.Any(), "failed-job").Return(&models.ProcessingJob{ID: "failed-job", Limit: 4, Status: "failed", CreatedBy: creator}, nil).AnyTimes()
+	mockJobs.EXPECT().GetByID(gomock.Any(), "completed-job").Return(&models.ProcessingJob{ID: "completed-job", Limit: 4, Status: "completed", CreatedBy: creator}, nil)
+	mockJobs.EXPECT().ProcessedListings(gomock.Any(), "failed-job").Return(map[string]bool{"A1": true}, nil)
+	mockJobs.EXPECT().Reopen(gomock.Any(), "failed-job", gomock.Any(), gomock.Any()).Return(nil)
+	mockJobs.EXPECT().MarkProcessed(gomock.Any(), "failed-job", "B2").Return(nil)
+	mockJobs.EXPECT().MarkProcessed(gomock.Any(), "failed-job", "C3").Return(nil)
+	mockJobs.EXPECT().Complete(gomock.Any(), "failed-job", gomock.Any()).Return(nil)
+
+	// Only B2 and C3 are saved, C3 once although the page lists it twice
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	var saved []string
+	var mu sync.Mutex
+	mockRepo.EXPECT().GetByExternalID(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mockRepo.EXPECT().GetByMLSNumber(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, property *models.Property) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		saved = append(saved, property.ExternalID.String)
+		return true, nil
+	}).Times(2)
+
+	service := NewSimplyRETSService(mockRepo, WithJobHistory(mockJobs))
+	service.baseURL = server.URL
+
+	if err := service.ResumeJob(context.Background(), "failed-job", JobScope{UserID: 5}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected another user not to resume the job, got %v", err)
+	}
+	if err := service.ResumeJob(context.Background(), "completed-job", JobScope{UserID: 4}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected a completed job not to be resumed, got %v", err)
+	}
+	if err := service.ResumeJob(context.Background(), "failed-job", JobScope{UserID: 4}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer service.manager.RemoveJob("failed-job")
+
+	var status *models.ProcessingStatus
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if status, _ = service.GetJobStatus(context.Background(), "failed-job", JobScope{UserID: 4}); status != nil && status.CompletedAt != nil {
+			break
+		}
+	}
+	if status == nil || status.CompletedAt == nil {
+		t.Fatal("Expected the job to finish")
+	}
+	if status.ProcessedCount != 2 || status.ListingsSkipped != 2 || status.TotalProperties != 4 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	sort.Strings(saved)
+	if strings.Join(saved, ",") != "B2,C3" {
+		t.Errorf("Expected B2 and C3 to be saved, got %v", saved)
+	}
+}
+
+func TestSimplyRETSService_GetJobStatus_FromHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	startedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	completedAt := startedAt.Add(time.Minute)
+	creator := models.NullInt32{NullInt32: sql.NullInt32{Int32: 4, Valid: true}}
+	mockJobs := mocks.NewMockJobRepository(ctrl)
+	mockJobs.EXPECT().GetByID(gomock.Any(), "completed-job").Return(&models.ProcessingJob{ID: "completed-job", Status: "completed",
+		TotalProperties: 10, ProcessedCount: 9, FailedCount: 1, CreatedBy: creator, StartedAt: startedAt, CompletedAt: &completedAt}, nil).Times(2)
+	mockJobs.EXPECT().GetByID(gomock.Any(), "running-job").Return(&models.ProcessingJob{ID: "running-job", Status: "running",
+		CreatedBy: creator, StartedAt: startedAt, HeartbeatAt: &completedAt}, nil)
+	heartbeatAt := time.Now().Add(-jobHeartbeatInterval)
+	mockJobs.EXPECT().GetByID(gomock.Any(), "elsewhere-job").Return(&models.ProcessingJob{ID: "elsewhere-job", Status: "running",
+		CreatedBy: creator, StartedAt: startedAt, HeartbeatAt: &heartbeatAt}, nil).Times(2)
+	mockJobs.EXPECT().GetByID(gomock.Any(), "unknown-job").Return(nil, nil)
+
+	// A new service holds no jobs in memory, as after a restart
+	service := NewSimplyRETSService(nil, WithJobHistory(mockJobs))
+	status, err := service.GetJobStatus(context.Background(), "completed-job", JobScope{UserID: 4})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Status != "completed" || status.ProcessedCount != 9 || status.FailedCount != 1 || status.CompletedAt == nil {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if _, err := service.GetJobStatus(context.Background(), "completed-job", JobScope{UserID: 5}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected another user not to see the job, got %v", err)
+	}
+	// The server stopped running the job before it finished
+	if status, err := service.GetJobStatus(context.Background(), "running-job", JobScope{All: true}); err != nil || status.Status != "interrupted" {
+		t.Errorf("Expected an interrupted job, got %+v, %v", status, err)
+	}
+	// Another server is running the job and still sends heartbeats
+	if status, err := service.GetJobStatus(context.Background(), "elsewhere-job", JobScope{All: true}); err != nil || status.Status != "running" {
+		t.Errorf("Expected a running job, got %+v, %v", status, err)
+	}
+	if err := service.ResumeJob(context.Background(), "elsewhere-job", JobScope{All: true}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected a job running elsewhere not to be resumed, got %v", err)
+	}
+	if _, err := service.GetJobStatus(context.Background(), "unknown-job", JobScope{All: true}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestMergeImported_KeepsEditedAltText(t *testing.T) {
+	existing := models.Property{Name: "12 Oak St", Location: "12 Oak St, Austin, TX", Photos: models.PhotoList{
+		{URL: "https://mls.example.com/a.jpg", AltText: "Front porch at dusk"},
+		{URL: "https://mls.example.com/b.jpg"},
+	}}
+	existing.NormalizePhotos()
+	imported := models.Property{Name: "12 Oak St", Location: "12 Oak St, Austin, TX", Bedrooms: nullInt32(3), Photos: models.PhotoList{
+		{URL: "https://mls.example.com/b.jpg"}, {URL: "https://mls.example.com/a.jpg"},
+	}}
+
+	merged := mergeImported(existing, imported)
+	merged.NormalizePhotos()
+	if merged.Photos[1].AltText != "Front porch at dusk" {
+		t.Errorf("Expected the edited alt text to be kept, got %q", merged.Photos[1].AltText)
+	}
+	// Generated alt text follows the imported details
+	if merged.Photos[0].AltText != "12 Oak St, Austin, TX, a 3-bedroom home" {
+		t.Errorf("Expected alt text generated again, got %q", merged.Photos[0].AltText)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"real-estate-manager/backend/internal/mailer"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// StaleNotifier tells an agent about their listings that went stale
+type StaleNotifier interface {
+	NotifyStale(ctx context.Context, agentID int, properties []models.Property) error
+}
+
+// StaleListingService flags listings that have not been updated or synced
+// within the stale_after_days setting
+type StaleListingService struct {
+	repo     repository.PropertyRepository
+	settings SettingsProvider
+	notifier StaleNotifier
+	now      func() time.Time
+}
+
+// NewStaleListingService creates the service; notifier may be nil to disable
+// agent notifications entirely
+func NewStaleListingService(repo repository.PropertyRepository, settings SettingsProvider, notifier StaleNotifier) *StaleListingService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &StaleListingService{repo: repo, settings: settings, notifier: notifier, now: time.Now}
+}
+
+// DetectStale flags newly stale listings and returns how many were flagged.
+// Notification failures are logged and do not fail the run.
+func (s *StaleListingService) DetectStale(ctx context.Context) (int, error) {
+	now := s.now()
+	cutoff := now.AddDate(0, 0, -s.settings.GetInt(SettingStaleAfterDays))
+
+	candidates, err := s.repo.FindStaleCandidates(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int, len(candidates))
+	byAgent := make(map[int][]models.Property)
+	for i := range candidates {
+		ids[i] = candidates[i].ID
+		candidates[i].StaleAt = nullTime(now)
+		if candidates[i].AgentID.Valid {
+			agentID := int(candidates[i].AgentID.Int32)
+			byAgent[agentID] = append(byAgent[agentID], candidates[i])
+		}
+	}
+
+	if err := s.repo.MarkStale(ctx, ids, now); err != nil {
+		return 0, err
+	}
+
+	if s.notifier != nil && s.settings.GetBool(SettingStaleNotify) {
+		for agentID, properties := range byAgent {
+			if err := s.notifier.NotifyStale(ctx, agentID, properties); err != nil {
+				log.Printf("Failed to notify agent %d about %d stale listings: %v", agentID, len(properties), err)
+			}
+		}
+	}
+
+	return len(ids), nil
+}
+
+// MailStaleNotifier emails agents a digest of their listings that went
+// stale, dated in each agent's time zone
+type MailStaleNotifier struct {
+	users         repository.UserRepository
+	mailer        mailer.Mailer
+	notifications *NotificationService
+}
+
+// NewMailStaleNotifier creates the notifier; without notifications the
+// digests are dated in UTC
+func NewMailStaleNotifier(users repository.UserRepository, m mailer.Mailer, notifications *NotificationService) *MailStaleNotifier {
+	return &MailStaleNotifier{users: users, mailer: m, notifications: notifications}
+}
+
+// staleDigestListing is a listing as the stale_digest template shows it
+type staleDigestListing struct {
+	Name      string
+	Location  string
+	UpdatedAt string
+}
+
+func (n *MailStaleNotifier) NotifyStale(ctx context.Context, agentID int, properties []models.Property) error {
+	user, err := n.users.GetByID(ctx, uint(agentID))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	location := time.UTC
+	if n.notifications != nil {
+		if location, err = n.notifications.Location(ctx, user.ID, ""); err != nil {
+			return err
+		}
+	}
+	listings := make([]staleDigestListing, len(properties))
+	for i, property := range properties {
+		listings[i] = staleDigestListing{
+			Name:      property.Name,
+			Location:  property.Location,
+			UpdatedAt: property.UpdatedAt.In(location).Format("Jan 2, 2006"),
+		}
+	}
+
+	msg, err := mailer.Render(user.Email, "stale_digest", map[string]any{
+		"Username":   user.Username,
+		"Properties": listings,
+	})
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(ctx, msg)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+type recordingStaleNotifier struct {
+	calls map[int]int
+}
+
+func (n *recordingStaleNotifier) NotifyStale(ctx context.Context, agentID int, properties []models.Property) error {
+	n.calls[agentID] += len(properties)
+	return nil
+}
+
+type staticSettings map[string]string
+
+func (s staticSettings) GetString(name string) string {
+	if value, ok := s[name]; ok {
+		return value
+	}
+	return defaultSettings{}.GetString(name)
+}
+
+func (s staticSettings) GetInt(name string) int {
+	if value, err := strconv.Atoi(s[name]); err == nil {
+		return value
+	}
+	return defaultSettings{}.GetInt(name)
+}
+
+func (s staticSettings) GetDuration(name string) time.Duration {
+	return defaultSettings{}.GetDuration(name)
+}
+
+func (s staticSettings) GetBool(name string) bool {
+	return s[name] == "true"
+}
+
+func TestStaleListingService_DetectStale(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	agent := func(id int32) models.NullInt32 {
+		return models.NullInt32{NullInt32: sql.NullInt32{Int32: id, Valid: true}}
+	}
+
+	tests := []struct {
+		name          string
+		settings      staticSettings
+		setupMock     func(mock *mocks.MockPropertyRepository)
+		expectedCount int
+		expectError   bool
+		expectedCalls map[int]int
+	}{
+		{
+			name:     "flags candidates and notifies agents",
+			settings: staticSettings{SettingStaleAfterDays: "10", SettingStaleNotify: "true"},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().FindStaleCandidates(gomock.Any(), now.AddDate(0, 0, -10)).Return([]models.Property{
+					{ID: 1, AgentID: agent(7)},
+					{ID: 2, AgentID: agent(7)},
+					{ID: 3},
+				}, nil)
+				mock.EXPECT().MarkStale(gomock.Any(), []int{1, 2, 3}, now).Return(nil)
+			},
+			expectedCount: 3,
+			expectedCalls: map[int]int{7: 2},
+		},
+		{
+			name:     "notifications disabled by default",
+			settings: staticSettings{},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().FindStaleCandidates(gomock.Any(), now.AddDate(0, 0, -30)).Return([]models.Property{
+					{ID: 1, AgentID: agent(7)},
+				}, nil)
+				mock.EXPECT().MarkStale(gomock.Any(), []int{1}, now).Return(nil)
+			},
+			expectedCount: 1,
+			expectedCalls: map[int]int{},
+		},
+		{
+			name:     "no candidates",
+			settings: staticSettings{},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().FindStaleCandidates(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedCalls: map[int]int{},
+		},
+		{
+			name:     "repository error",
+			settings: staticSettings{},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().FindStaleCandidates(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectError:   true,
+			expectedCalls: map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			notifier := &recordingStaleNotifier{calls: map[int]int{}}
+			service := NewStaleListingService(mockRepo, tt.settings, notifier)
+			service.now = func() time.Time { return now }
+
+			count, err := service.DetectStale(context.Background())
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected %d flagged, got %d", tt.expectedCount, count)
+			}
+			if len(notifier.calls) != len(tt.expectedCalls) {
+				t.Errorf("Expected notifications %v, got %v", tt.expectedCalls, notifier.calls)
+			}
+			for agentID, n := range tt.expectedCalls {
+				if notifier.calls[agentID] != n {
+					t.Errorf("Expected %d listings for agent %d, got %d", n, agentID, notifier.calls[agentID])
+				}
+			}
+		})
+	}
+}
+
+func TestMailStaleNotifier_NotifyStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7, Username: "jane", Email: "jane@example.com"}, nil)
+
+	// Dates are in the agent's time zone
+	mockPrefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	mockPrefs.EXPECT().Get(gomock.Any(), uint(7)).Return(&models.NotificationPreferences{UserID: 7, Timezone: "America/Chicago"}, nil)
+
+	m := &recordingMailer{}
+	notifier := NewMailStaleNotifier(mockUsers, m, NewNotificationService(mockPrefs, nil))
+	err := notifier.NotifyStale(context.Background(), 7, []models.Property{
+		{ID: 1, Name: "Lake House", Location: "Austin, TX", UpdatedAt: time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "City Loft", Location: "Dallas, TX", UpdatedAt: time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(m.sent) != 1 {
+		t.Fatalf("Expected 1 email, got %d", len(m.sent))
+	}
+	msg := m.sent[0]
+	if msg.To != "jane@example.com" || msg.Subject != "2 of your listings need attention" {
+		t.Errorf("Unexpected email: %+v", msg)
+	}
+	if !strings.Contains(msg.Body, "- Lake House (Austin, TX), last updated Feb 29, 2024") || !strings.Contains(msg.HTML, "<li>City Loft (Dallas, TX), last updated Feb 10, 2024</li>") {
+		t.Errorf("Expected both listings in the digest, got %q", msg.Body)
+	}
+}
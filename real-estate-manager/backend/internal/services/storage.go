@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+const bytesPerMB = 1 << 20
+
+// StorageOwner identifies who a stored file is charged to; zero IDs mean
+// no user or no organization
+type StorageOwner struct {
+	UserID         int
+	OrganizationID int
+}
+
+type storageOwnerKey struct{}
+
+// withStorageOwner carries a resolved owner into background jobs, which
+// outlive the request that started them
+func withStorageOwner(ctx context.Context, owner StorageOwner) context.Context {
+	return context.WithValue(ctx, storageOwnerKey{}, owner)
+}
+
+func storageOwnerFromContext(ctx context.Context) (StorageOwner, bool) {
+	owner, ok := ctx.Value(storageOwnerKey{}).(StorageOwner)
+	return owner, ok
+}
+
+// StorageService tracks photo and document storage and enforces per-user
+// and per-organization quotas
+type StorageService struct {
+	repo     repository.StorageRepository
+	users    repository.UserRepository
+	settings SettingsProvider
+}
+
+func NewStorageService(repo repository.StorageRepository, users repository.UserRepository, settings SettingsProvider) *StorageService {
+	if settings == nil {
+		settings = defaultSettings{}
+	}
+	return &StorageService{repo: repo, users: users, settings: settings}
+}
+
+// OwnerFromContext resolves the acting user and their organization
+func (s *StorageService) OwnerFromContext(ctx context.Context) (StorageOwner, error) {
+	userID, ok := ActorFromContext(ctx)
+	if !ok {
+		return StorageOwner{}, nil
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return StorageOwner{}, err
+	}
+	owner := StorageOwner{UserID: int(user.ID)}
+	if user.OrganizationID.Valid {
+		owner.OrganizationID = int(user.OrganizationID.Int32)
+	}
+	return owner, nil
+}
+
+// CheckQuota returns an apperrors.ErrTooLarge error when storing size more
+// bytes would exceed the owner's user or organization quota
+func (s *StorageService) CheckQuota(ctx context.Context, owner StorageOwner, size int64) error {
+	if owner.OrganizationID != 0 {
+		quota, err := s.organizationQuota(ctx, owner.OrganizationID)
+		if err != nil {
+			return err
+		}
+		used, err := s.repo.UsageByOrganization(ctx, owner.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if quota > 0 && used+size > quota {
+			return quotaExceeded("organization", used, quota, size)
+		}
+	}
+
+	if owner.UserID != 0 {
+		quota := int64(s.settings.GetInt(SettingUserQuotaMB)) * bytesPerMB
+		used, err := s.repo.UsageByUser(ctx, owner.UserID)
+		if err != nil {
+			return err
+		}
+		if quota > 0 && used+size > quota {
+			return quotaExceeded("user", used, quota, size)
+		}
+	}
+	return nil
+}
+
+// Record adds a stored file to the owner's usage
+func (s *StorageService) Record(ctx context.Context, owner StorageOwner, propertyID int, kind, path string, size int64) error {
+	file := models.StoredFile{
+		UserID:         nullID(owner.UserID),
+		OrganizationID: nullID(owner.OrganizationID),
+		PropertyID:     nullID(propertyID),
+		Kind:           kind,
+		Path:           path,
+		SizeBytes:      size,
+	}
+	return s.repo.Record(ctx, &file)
+}
+
+// Usage reports storage per organization and user with effective quotas
+func (s *StorageService) Usage(ctx context.Context) ([]models.StorageUsage, error) {
+	usage, err := s.repo.UsageSummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range usage {
+		if usage[i].QuotaBytes > 0 {
+			continue
+		}
+		if usage[i].OwnerType == "organization" {
+			usage[i].QuotaBytes = int64(s.settings.GetInt(SettingOrgQuotaMB)) * bytesPerMB
+		} else {
+			usage[i].QuotaBytes = int64(s.settings.GetInt(SettingUserQuotaMB)) * bytesPerMB
+		}
+	}
+	return usage, nil
+}
+
+func (s *StorageService) organizationQuota(ctx context.Context, organizationID int) (int64, error) {
+	org, err := s.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		return 0, err
+	}
+	if org != nil && org.StorageQuotaMB.Valid {
+		return int64(org.StorageQuotaMB.Int32) * bytesPerMB, nil
+	}
+	return int64(s.settings.GetInt(SettingOrgQuotaMB)) * bytesPerMB, nil
+}
+
+func quotaExceeded(scope string, used, quota, size int64) error {
+	return apperrors.TooLargef("storage quota exceeded for %s: %s of %s used, upload needs %s",
+		scope, formatMB(used), formatMB(quota), formatMB(size))
+}
+
+func formatMB(bytes int64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/bytesPerMB)
+}
+
+func nullID(id int) models.NullInt32 {
+	if id == 0 {
+		return models.NullInt32{}
+	}
+	return models.NullInt32{NullInt32: sql.NullInt32{Int32: int32(id), Valid: true}}
+}
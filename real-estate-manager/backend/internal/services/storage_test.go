@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestStorageService_CheckQuota(t *testing.T) {
+	tests := []struct {
+		name        string
+		owner       StorageOwner
+		size        int64
+		settings    staticSettings
+		setupMock   func(mock *mocks.MockStorageRepository)
+		expectKind  error
+		expectError bool
+	}{
+		{
+			name:  "within user quota",
+			owner: StorageOwner{UserID: 7},
+			size:  bytesPerMB,
+			setupMock: func(mock *mocks.MockStorageRepository) {
+				mock.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(10*bytesPerMB), nil)
+			},
+		},
+		{
+			name:     "user quota exceeded",
+			owner:    StorageOwner{UserID: 7},
+			size:     2 * bytesPerMB,
+			settings: staticSettings{SettingUserQuotaMB: "100"},
+			setupMock: func(mock *mocks.MockStorageRepository) {
+				mock.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(99*bytesPerMB), nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrTooLarge,
+		},
+		{
+			name:     "zero quota is unlimited",
+			owner:    StorageOwner{UserID: 7},
+			size:     bytesPerMB,
+			settings: staticSettings{SettingUserQuotaMB: "0"},
+			setupMock: func(mock *mocks.MockStorageRepository) {
+				mock.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(5000*bytesPerMB), nil)
+			},
+		},
+		{
+			name:  "organization override exceeded",
+			owner: StorageOwner{UserID: 7, OrganizationID: 2},
+			size:  bytesPerMB,
+			setupMock: func(mock *mocks.MockStorageRepository) {
+				mock.EXPECT().GetOrganization(gomock.Any(), 2).Return(&models.Organization{
+					ID:             2,
+					StorageQuotaMB: models.NullInt32{NullInt32: sql.NullInt32{Int32: 50, Valid: true}},
+				}, nil)
+				mock.EXPECT().UsageByOrganization(gomock.Any(), 2).Return(int64(50*bytesPerMB), nil)
+			},
+			expectError: true,
+			expectKind:  apperrors.ErrTooLarge,
+		},
+		{
+			name:  "repository error",
+			owner: StorageOwner{UserID: 7},
+			size:  bytesPerMB,
+			setupMock: func(mock *mocks.MockStorageRepository) {
+				mock.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(0), errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockStorageRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			service := NewStorageService(mockRepo, mocks.NewMockUserRepository(ctrl), tt.settings)
+			err := service.CheckQuota(context.Background(), tt.owner, tt.size)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectKind != nil && !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStorageService_UsageFillsDefaultQuotas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStorageRepository(ctrl)
+	mockRepo.EXPECT().UsageSummary(gomock.Any()).Return([]models.StorageUsage{
+		{OwnerType: "organization", OwnerID: 1, QuotaBytes: 5 * bytesPerMB},
+		{OwnerType: "organization", OwnerID: 2},
+		{OwnerType: "user", OwnerID: 7},
+	}, nil)
+
+	service := NewStorageService(mockRepo, mocks.NewMockUserRepository(ctrl), staticSettings{})
+	usage, err := service.Usage(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []int64{5 * bytesPerMB, 10240 * bytesPerMB, 1024 * bytesPerMB}
+	for i, quota := range expected {
+		if usage[i].QuotaBytes != quota {
+			t.Errorf("Row %d: expected quota %d, got %d", i, quota, usage[i].QuotaBytes)
+		}
+	}
+}
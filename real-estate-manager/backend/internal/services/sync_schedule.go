@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/scheduler"
+
+	"github.com/google/uuid"
+)
+
+// SyncScheduleOff in the sync_schedule setting turns automatic imports off
+// even when SIMPLYRETS_SYNC_CRON is set
+const SyncScheduleOff = "off"
+
+// syncIdleInterval is how long the sync task waits while imports are off;
+// a schedule change wakes it sooner
+const syncIdleInterval = time.Hour
+
+// Where the sync schedule in effect comes from
+const (
+	syncSourceSetting     = "setting"
+	syncSourceEnvironment = "environment"
+)
+
+// SyncRunner starts import jobs, refusing with a conflict while another
+// one runs; SimplyRETSService implements it
+type SyncRunner interface {
+	StartPropertyProcessing(ctx context.Context, jobID string, limit int, details *models.JobDetails) error
+	DefaultLimit() int
+}
+
+// SyncScheduleService runs SimplyRETS imports on a cron schedule, in UTC.
+// The schedule is the sync_schedule setting, so it can change at runtime,
+// or SIMPLYRETS_SYNC_CRON while the setting is empty. A run is skipped
+// while any import job, scheduled or manual, is still running.
+type SyncScheduleService struct {
+	runner   SyncRunner
+	settings *SettingsService
+	fallback string
+	now      func() time.Time
+
+	mu      sync.Mutex
+	next    time.Time
+	lastRun *models.SyncRun
+}
+
+// NewSyncScheduleService creates the service; fallback is the schedule
+// from SIMPLYRETS_SYNC_CRON, empty for none
+func NewSyncScheduleService(runner SyncRunner, settings *SettingsService, fallback string) (*SyncScheduleService, error) {
+	fallback = strings.TrimSpace(fallback)
+	if fallback != "" {
+		if _, err := scheduler.ParseCron(fallback); err != nil {
+			return nil, fmt.Errorf("invalid SIMPLYRETS_SYNC_CRON: %w", err)
+		}
+	}
+	return &SyncScheduleService{runner: runner, settings: settings, fallback: fallback, now: time.Now}, nil
+}
+
+// Get returns the schedule in effect, when it next runs and how its last
+// run went
+func (s *SyncScheduleService) Get() *models.SyncSchedule {
+	cron, source := s.cron()
+	schedule := &models.SyncSchedule{Source: source, Timezone: time.UTC.String()}
+	if cron != nil {
+		next := cron.Next(s.now().UTC())
+		schedule.Cron, schedule.Enabled, schedule.NextRunAt = cron.String(), true, &next
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRun != nil {
+		run := *s.lastRun
+		schedule.LastRun = &run
+	}
+	return schedule
+}
+
+// Update changes the schedule: a cron expression, "off", or empty to
+// follow SIMPLYRETS_SYNC_CRON again
+func (s *SyncScheduleService) Update(ctx context.Context, cron, updatedBy string) (*models.SyncSchedule, error) {
+	if _, err := s.settings.Update(ctx, map[string]string{SettingSyncSchedule: strings.TrimSpace(cron)}, updatedBy); err != nil {
+		return nil, err
+	}
+	return s.Get(), nil
+}
+
+// OnChange registers fn to be called when the sync_schedule setting
+// changes, so the scheduler can pick up the new schedule
+func (s *SyncScheduleService) OnChange(fn func()) {
+	s.settings.Subscribe(func(name, value string) {
+		if name == SettingSyncSchedule {
+			fn()
+		}
+	})
+}
+
+// Interval returns how long until the next scheduled run; the scheduler
+// calls it before every wait
+func (s *SyncScheduleService) Interval() time.Duration {
+	now := s.now().UTC()
+	next := time.Time{}
+	if cron, _ := s.cron(); cron != nil {
+		next = cron.Next(now)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next = next
+	if next.IsZero() {
+		return syncIdleInterval
+	}
+	return next.Sub(now)
+}
+
+// Run starts an import when one is due, unless another import job is
+// still running
+func (s *SyncScheduleService) Run(ctx context.Context) error {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next.IsZero() || now.Before(s.next) {
+		return nil
+	}
+
+	jobID := uuid.New().String()
+	details := &models.JobDetails{Label: models.JobLabelScheduled, Description: "Scheduled sync"}
+	// The job outlives the scheduler's run, like jobs started over HTTP
+	err := s.runner.StartPropertyProcessing(context.WithoutCancel(ctx), jobID, s.runner.DefaultLimit(), details)
+	if errors.Is(err, apperrors.ErrConflict) {
+		log.Printf("Skipping scheduled SimplyRETS sync: %v", err)
+		s.lastRun = &models.SyncRun{At: now, Skipped: true}
+		return nil
+	}
+	s.lastRun = &models.SyncRun{At: now, JobID: jobID}
+	if err != nil {
+		s.lastRun.JobID, s.lastRun.Error = "", err.Error()
+		return fmt.Errorf("failed to start scheduled sync: %w", err)
+	}
+	return nil
+}
+
+// cron returns the schedule in effect, nil when imports are off, and
+// where it comes from
+func (s *SyncScheduleService) cron() (*scheduler.Cron, string) {
+	spec, source := s.settings.GetString(SettingSyncSchedule), syncSourceSetting
+	if spec == "" {
+		spec, source = s.fallback, syncSourceEnvironment
+	}
+	if spec == "" {
+		return nil, ""
+	}
+	if spec == SyncScheduleOff {
+		return nil, source
+	}
+	cron, err := scheduler.ParseCron(spec)
+	if err != nil {
+		return nil, source
+	}
+	return cron, source
+}
+
+// validateSyncSchedule accepts a cron expression, "off" or nothing
+func validateSyncSchedule(value string) error {
+	if value == "" || value == SyncScheduleOff {
+		return nil
+	}
+	_, err := scheduler.ParseCron(value)
+	return err
+}
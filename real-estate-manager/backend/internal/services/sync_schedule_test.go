@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakeSyncRunner records started jobs and refuses to start one while any
+// job, however started, is running
+type fakeSyncRunner struct {
+	started []models.JobDetails
+	running map[string]bool
+}
+
+func (r *fakeSyncRunner) StartPropertyProcessing(ctx context.Context, jobID string, limit int, details *models.JobDetails) error {
+	for _, running := range r.running {
+		if running {
+			return apperrors.Conflict("another import job is running")
+		}
+	}
+	r.started = append(r.started, *details)
+	r.running[jobID] = true
+	return nil
+}
+
+func (r *fakeSyncRunner) DefaultLimit() int { return 50 }
+
+func TestSyncScheduleService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runner := &fakeSyncRunner{running: map[string]bool{}}
+	service, err := NewSyncScheduleService(runner, NewSettingsService(mocks.NewMockSettingRepository(ctrl)), "0 2 * * *")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	now := time.Date(2024, 6, 15, 1, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	if interval := service.Interval(); interval != 30*time.Minute {
+		t.Fatalf("Expected the next run in 30 minutes, got %v", interval)
+	}
+	// Not due yet, e.g. after a wake-up
+	if err := service.Run(context.Background()); err != nil || len(runner.started) != 0 {
+		t.Fatalf("Expected nothing started before 02:00, got %v, %v", runner.started, err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if err := service.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(runner.started) != 1 || runner.started[0].Label != models.JobLabelScheduled {
+		t.Fatalf("Expected one scheduled job, got %+v", runner.started)
+	}
+	jobID := service.Get().LastRun.JobID
+
+	// The next night's run is skipped while the job still runs
+	service.Interval()
+	now = now.Add(24 * time.Hour)
+	if err := service.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(runner.started) != 1 || !service.Get().LastRun.Skipped {
+		t.Errorf("Expected an overlapping run to be skipped, got %+v", service.Get().LastRun)
+	}
+
+	runner.running[jobID] = false
+	service.Interval()
+	now = now.Add(24 * time.Hour)
+	if err := service.Run(context.Background()); err != nil || len(runner.started) != 2 {
+		t.Errorf("Expected a second job once the first finished, got %d, %v", len(runner.started), err)
+	}
+
+	// So is a run while a manually started job is running
+	runner.running[service.Get().LastRun.JobID] = false
+	runner.running["manual-job"] = true
+	service.Interval()
+	now = now.Add(24 * time.Hour)
+	if err := service.Run(context.Background()); err != nil || len(runner.started) != 2 || !service.Get().LastRun.Skipped {
+		t.Errorf("Expected the run to be skipped while a manual job runs, got %+v, %v", service.Get().LastRun, err)
+	}
+}
+
+func TestSyncScheduleService_Schedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSettingRepository(ctrl)
+	repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	service, err := NewSyncScheduleService(&fakeSyncRunner{}, NewSettingsService(repo), "0 2 * * *")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	service.now = func() time.Time { return time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC) }
+
+	schedule := service.Get()
+	if !schedule.Enabled || schedule.Source != syncSourceEnvironment || schedule.NextRunAt.Day() != 16 {
+		t.Errorf("Expected the environment schedule, got %+v", schedule)
+	}
+
+	schedule, err = service.Update(context.Background(), " */30 * * * * ", "admin")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if schedule.Cron != "*/30 * * * *" || schedule.Source != syncSourceSetting || schedule.NextRunAt.Minute() != 30 {
+		t.Errorf("Expected the updated schedule, got %+v", schedule)
+	}
+
+	schedule, err = service.Update(context.Background(), SyncScheduleOff, "admin")
+	if err != nil || schedule.Enabled || schedule.NextRunAt != nil {
+		t.Errorf("Expected imports off, got %+v, %v", schedule, err)
+	}
+	if service.Interval() != syncIdleInterval {
+		t.Error("Expected the idle interval while imports are off")
+	}
+
+	if _, err := service.Update(context.Background(), "61 * * * *", "admin"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if _, err := NewSyncScheduleService(&fakeSyncRunner{}, nil, "every night"); err == nil {
+		t.Error("Expected an invalid SIMPLYRETS_SYNC_CRON to be rejected")
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPropertyService_Sync(t *testing.T) {
+	property := func() *models.Property {
+		return &models.Property{Name: "House", Location: "Main St", Price: 1000}
+	}
+
+	tests := []struct {
+		name         string
+		mutations    []models.PropertyMutation
+		setupMock    func(mock *mocks.MockPropertyRepository)
+		expectKind   error
+		expectResult int
+		expectEvents []string
+		expectClash  bool
+	}{
+		{
+			name: "applies batch and publishes events",
+			mutations: []models.PropertyMutation{
+				{Op: models.MutationCreate, ClientRef: "tmp-1", Property: property()},
+				{Op: models.MutationDelete, ID: 8, BaseVersion: 2},
+			},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), nil).
+					DoAndReturn(func(ctx context.Context, mutations []models.PropertyMutation, _ *repository.Revision) ([]models.MutationResult, []models.SyncConflict, error) {
+						if mutations[0].Property.Status != models.PropertyStatusActive {
+							t.Errorf("Expected created property to default to active, got %q", mutations[0].Property.Status)
+						}
+						return []models.MutationResult{
+							{Index: 0, Op: models.MutationCreate, ClientRef: "tmp-1", ID: 12, Version: 1},
+							{Index: 1, Op: models.MutationDelete, ID: 8},
+						}, nil, nil
+					})
+			},
+			expectResult: 2,
+			expectEvents: []string{events.PropertyCreated, events.PropertyDeleted},
+		},
+		{
+			name:      "conflicts publish nothing",
+			mutations: []models.PropertyMutation{{Op: models.MutationUpdate, ID: 5, BaseVersion: 3, Property: property()}},
+			setupMock: func(mock *mocks.MockPropertyRepository) {
+				mock.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), nil).
+					Return(nil, []models.SyncConflict{{Index: 0, ID: 5, BaseVersion: 3}}, nil)
+			},
+			expectClash: true,
+		},
+		{
+			name:       "update without base version",
+			mutations:  []models.PropertyMutation{{Op: models.MutationUpdate, ID: 5, Property: property()}},
+			setupMock:  func(*mocks.MockPropertyRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:       "invalid property in batch",
+			mutations:  []models.PropertyMutation{{Op: models.MutationCreate, Property: &models.Property{Name: "No price"}}},
+			setupMock:  func(*mocks.MockPropertyRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:       "unknown op",
+			mutations:  []models.PropertyMutation{{Op: "upsert", ID: 5}},
+			setupMock:  func(*mocks.MockPropertyRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+		{
+			name:       "empty batch",
+			setupMock:  func(*mocks.MockPropertyRepository) {},
+			expectKind: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPropertyRepository(ctrl)
+			tt.setupMock(mockRepo)
+			publisher := &recordingPublisher{}
+			service := NewPropertyService(mockRepo, WithPropertyEvents(publisher))
+
+			result, err := service.Sync(context.Background(), models.SyncRequest{Mutations: tt.mutations})
+			if tt.expectKind != nil {
+				if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expectClash != (len(result.Conflicts) > 0) || len(result.Results) != tt.expectResult {
+				t.Errorf("Unexpected result: %+v", result)
+			}
+			if len(publisher.events) != len(tt.expectEvents) {
+				t.Fatalf("Expected %d events, got %+v", len(tt.expectEvents), publisher.events)
+			}
+			for i, eventType := range tt.expectEvents {
+				if publisher.events[i].Type != eventType {
+					t.Errorf("Event %d: expected %s, got %s", i, eventType, publisher.events[i].Type)
+				}
+			}
+		})
+	}
+}
+
+func TestPropertyService_Sync_Revisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Snapshots are taken by the batch's own transaction, never up front
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), gomock.Not(gomock.Nil())).
+		DoAndReturn(func(_ context.Context, _ []models.PropertyMutation, revision *repository.Revision) ([]models.MutationResult, []models.SyncConflict, error) {
+			if revision.ChangedBy.Int32 != 4 {
+				t.Errorf("Expected revisions by user 4, got %+v", revision)
+			}
+			return nil, nil, errors.New("batch failed")
+		})
+	mockRevisions := mocks.NewMockPropertyRevisionRepository(ctrl)
+
+	service := NewPropertyService(mockRepo, WithRevisions(mockRevisions))
+	_, err := service.Sync(WithActor(context.Background(), 4), models.SyncRequest{Mutations: []models.PropertyMutation{
+		{Op: models.MutationUpdate, ID: 5, BaseVersion: 3, Property: &models.Property{Name: "House", Location: "Main St", Price: 1000}},
+	}})
+	if err == nil {
+		t.Error("Expected the batch error")
+	}
+}
+
+func TestPropertyService_UpdatePropertyVersionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+
+	service := NewPropertyService(mockRepo)
+	err := service.UpdateProperty(context.Background(), &models.Property{ID: 5, Name: "House", Location: "Main St", Price: 1000, Version: 3})
+	if !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/syndication"
+)
+
+// syndicationBatchSize caps how many properties one scheduled sync handles
+const syndicationBatchSize = 50
+
+// syndicationQueueSize is how many changed properties can wait for the
+// sync worker; changes beyond it are picked up by the scheduled sync
+const syndicationQueueSize = 256
+
+// SyndicationConfig tells portals where to find a listing. PublicBaseURL is
+// the API's public address, which photos are served from; ListingURL is
+// the public page of a listing with "{id}" replaced by its public ID.
+type SyndicationConfig struct {
+	PublicBaseURL string
+	ListingURL    string
+}
+
+// SyndicationService publishes listings to third-party portals and keeps
+// them in step with the listing: changes are pushed by a background worker
+// fed from the event bus, and a scheduled sync retries failures and catches
+// up on anything the worker missed.
+type SyndicationService struct {
+	portals      map[string]syndication.Portal
+	names        []string
+	repo         repository.SyndicationRepository
+	properties   repository.PropertyRepository
+	publications repository.ListingPublicationRepository
+	users        repository.UserRepository
+	config       SyndicationConfig
+	queue        chan int
+	now          func() time.Time
+}
+
+func NewSyndicationService(portals []syndication.Portal, repo repository.SyndicationRepository, properties repository.PropertyRepository, publications repository.ListingPublicationRepository, users repository.UserRepository, config SyndicationConfig) *SyndicationService {
+	s := &SyndicationService{
+		portals:      make(map[string]syndication.Portal),
+		names:        []string{},
+		repo:         repo,
+		properties:   properties,
+		publications: publications,
+		users:        users,
+		config:       config,
+		queue:        make(chan int, syndicationQueueSize),
+		now:          time.Now,
+	}
+	for _, portal := range portals {
+		s.portals[portal.Name()] = portal
+		s.names = append(s.names, portal.Name())
+	}
+	sort.Strings(s.names)
+	return s
+}
+
+// Enabled reports whether any portal is configured
+func (s *SyndicationService) Enabled() bool {
+	return len(s.portals) > 0
+}
+
+// Portals returns the names of the configured portals
+func (s *SyndicationService) Portals() []string {
+	return s.names
+}
+
+// Status returns the state of a property on each portal it was published to
+func (s *SyndicationService) Status(ctx context.Context, propertyID int) ([]models.SyndicationListing, error) {
+	if _, err := s.getProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, propertyID)
+}
+
+// Publish puts a listing on a portal and keeps it there until it is
+// unpublished. The push happens right away; when it fails the returned
+// status says so and the scheduled sync retries it.
+func (s *SyndicationService) Publish(ctx context.Context, propertyID int, portalName string) (*models.SyndicationListing, error) {
+	portal, ok := s.portals[portalName]
+	if !ok {
+		return nil, apperrors.Validationf("unknown portal %q", portalName)
+	}
+	property, err := s.getProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	listed, err := publiclyListed(ctx, s.publications, property)
+	if err != nil {
+		return nil, err
+	}
+	if !listed {
+		return nil, apperrors.Validation("only approved active or pending listings can be syndicated")
+	}
+
+	listing := &models.SyndicationListing{PropertyID: propertyID, Portal: portalName, Enabled: true}
+	s.push(ctx, portal, s.listing(ctx, property), listing)
+	if err := s.repo.Save(ctx, listing); err != nil {
+		return nil, fmt.Errorf("failed to save syndication status: %w", err)
+	}
+	return listing, nil
+}
+
+// Unpublish takes a listing off a portal. When the portal cannot be
+// reached the removal is retried by the scheduled sync.
+func (s *SyndicationService) Unpublish(ctx context.Context, propertyID int, portalName string) (*models.SyndicationListing, error) {
+	portal, ok := s.portals[portalName]
+	if !ok {
+		return nil, apperrors.Validationf("unknown portal %q", portalName)
+	}
+	if _, err := s.getProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	listings, err := s.repo.List(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	var listing *models.SyndicationListing
+	for i := range listings {
+		if listings[i].Portal == portalName {
+			listing = &listings[i]
+		}
+	}
+	if listing == nil {
+		return nil, apperrors.NotFoundf("listing is not syndicated to %s", portalName)
+	}
+
+	listing.Enabled = false
+	s.remove(ctx, portal, listing, models.SyndicationUnpublished)
+	if err := s.repo.Save(ctx, listing); err != nil {
+		return nil, fmt.Errorf("failed to save syndication status: %w", err)
+	}
+	return listing, nil
+}
+
+// HandleEvent queues changed and deleted properties for the sync worker.
+// It never blocks the event bus: when the queue is full the change is left
+// to the scheduled sync.
+func (s *SyndicationService) HandleEvent(ctx context.Context, event events.Event) {
+	switch event.Type {
+	case events.PropertyCreated, events.PropertyUpdated, events.PropertyDeleted,
+		events.ListingApproved, events.ListingUnpublished, events.ListingExpired:
+	default:
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(event.Subject, "property/"))
+	if err != nil {
+		return
+	}
+	select {
+	case s.queue <- id:
+	default:
+	}
+}
+
+// Run syncs queued properties until ctx is cancelled
+func (s *SyndicationService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			if err := s.syncProperty(ctx, id); err != nil {
+				log.Printf("Failed to sync property %d to portals: %v", id, err)
+			}
+		}
+	}
+}
+
+// SyncDue syncs properties whose portal state is out of date and returns
+// how many were synced
+func (s *SyndicationService) SyncDue(ctx context.Context) (int, error) {
+	ids, err := s.repo.ListDue(ctx, syndicationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	synced := 0
+	for _, id := range ids {
+		if err := s.syncProperty(ctx, id); err != nil {
+			log.Printf("Failed to sync property %d to portals: %v", id, err)
+			continue
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+// syncProperty brings every portal a property was published to in line
+// with it: active listings are pushed, off-market ones withdrawn and
+// deleted ones removed
+func (s *SyndicationService) syncProperty(ctx context.Context, propertyID int) error {
+	listings, err := s.repo.List(ctx, propertyID)
+	if err != nil || len(listings) == 0 {
+		return err
+	}
+	property, err := s.properties.GetByID(ctx, propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to get property: %w", err)
+	}
+	if property == nil {
+		return s.removeDeleted(ctx, listings)
+	}
+
+	listed, err := publiclyListed(ctx, s.publications, property)
+	if err != nil {
+		return fmt.Errorf("failed to get publication: %w", err)
+	}
+	feed := s.listing(ctx, property)
+	for i := range listings {
+		listing := &listings[i]
+		portal, ok := s.portals[listing.Portal]
+		if !ok {
+			// The portal is no longer configured
+			continue
+		}
+		switch {
+		case !listing.Enabled:
+			if listing.Status != models.SyndicationFailed {
+				continue
+			}
+			s.remove(ctx, portal, listing, models.SyndicationUnpublished)
+		case !listed:
+			if listing.Status == models.SyndicationWithdrawn {
+				continue
+			}
+			s.remove(ctx, portal, listing, models.SyndicationWithdrawn)
+		default:
+			s.push(ctx, portal, feed, listing)
+		}
+		if err := s.repo.Save(ctx, listing); err != nil {
+			return fmt.Errorf("failed to save syndication status: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeDeleted takes a deleted property off every portal, forgetting it
+// once all of them confirmed
+func (s *SyndicationService) removeDeleted(ctx context.Context, listings []models.SyndicationListing) error {
+	removed := true
+	for i := range listings {
+		listing := &listings[i]
+		portal, ok := s.portals[listing.Portal]
+		if !ok {
+			continue
+		}
+		s.remove(ctx, portal, listing, models.SyndicationUnpublished)
+		if listing.Status != models.SyndicationFailed {
+			continue
+		}
+		removed = false
+		if err := s.repo.Save(ctx, listing); err != nil {
+			return fmt.Errorf("failed to save syndication status: %w", err)
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return s.repo.DeleteAll(ctx, listings[0].PropertyID)
+}
+
+// push publishes a listing under the property's public ID. A listing the
+// portal knows by another ID, such as the numeric ID listings were
+// published under before public IDs, is removed from it first.
+func (s *SyndicationService) push(ctx context.Context, portal syndication.Portal, feed syndication.Listing, listing *models.SyndicationListing) {
+	if listing.ListingID != "" && listing.ListingID != feed.ID {
+		if err := portal.Unpublish(ctx, listing.ListingID); err != nil {
+			s.record(listing, models.SyndicationPublished, err)
+			return
+		}
+	}
+	listing.ListingID = feed.ID
+	s.record(listing, models.SyndicationPublished, portal.Publish(ctx, feed))
+}
+
+// remove takes a listing off a portal. One that was never pushed has
+// nothing to remove.
+func (s *SyndicationService) remove(ctx context.Context, portal syndication.Portal, listing *models.SyndicationListing, status string) {
+	if listing.ListingID == "" {
+		s.record(listing, status, nil)
+		return
+	}
+	s.record(listing, status, portal.Unpublish(ctx, listing.ListingID))
+}
+
+func (s *SyndicationService) record(listing *models.SyndicationListing, status string, err error) {
+	now := s.now()
+	listing.SyncedAt = &now
+	listing.Status = status
+	listing.LastError = ""
+	if err != nil {
+		log.Printf("Failed to sync property %d to %s: %v", listing.PropertyID, listing.Portal, err)
+		listing.Status = models.SyndicationFailed
+		listing.LastError = err.Error()
+	}
+}
+
+func (s *SyndicationService) getProperty(ctx context.Context, id int) (*models.Property, error) {
+	property, err := s.properties.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property: %w", err)
+	}
+	if property == nil {
+		return nil, apperrors.NotFound("property not found")
+	}
+	return property, nil
+}
+
+// listing maps a property to the portal-neutral feed shape. Locally stored
+// photos are linked through the public image endpoint; photos that only
+// exist on the MLS are linked directly.
+func (s *SyndicationService) listing(ctx context.Context, property *models.Property) syndication.Listing {
+	listing := syndication.Listing{
+		ID:           property.PublicID,
+		MLSNumber:    property.MLSNumber.String,
+		Title:        property.Name,
+		Address:      property.Location,
+		Status:       property.Status,
+		Price:        property.Price,
+		PropertyType: property.PropertyType.String,
+		Description:  property.Description.String,
+		Bedrooms:     int(property.Bedrooms.Int32),
+		Bathrooms:    int(property.Bathrooms.Int32),
+		LivingArea:   int(property.SquareFeet.Int32),
+		LotSize:      property.LotSize.String,
+		YearBuilt:    int(property.YearBuilt.Int32),
+		URL:          expandListingURL(s.config.ListingURL, property),
+		UpdatedAt:    property.UpdatedAt,
+	}
+
+	base := strings.TrimSuffix(s.config.PublicBaseURL, "/")
+	for i, photo := range property.Photos {
+		var url string
+		switch {
+		case strings.HasPrefix(photo.LocalURL, "/images/"):
+			url = fmt.Sprintf("%s/public/images/%s/%d?size=large", base, property.PublicID, i)
+		case strings.HasPrefix(photo.URL, "https://"), strings.HasPrefix(photo.URL, "http://"):
+			url = photo.URL
+		default:
+			continue
+		}
+		listing.Photos = append(listing.Photos, syndication.Photo{URL: url, Caption: photo.Caption})
+	}
+
+	if property.AgentID.Valid {
+		// A listing without its agent is still worth publishing
+		if agent, err := s.users.GetByID(ctx, uint(property.AgentID.Int32)); err == nil && agent != nil {
+			listing.AgentName = agent.Username
+			listing.AgentEmail = agent.Email
+		}
+	}
+	return listing
+}
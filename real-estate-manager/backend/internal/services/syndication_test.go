@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/events"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/syndication"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakePortal records the listings pushed to it and fails with err
+type fakePortal struct {
+	name      string
+	err       error
+	published []syndication.Listing
+	removed   []string
+}
+
+func (p *fakePortal) Name() string {
+	return p.name
+}
+
+func (p *fakePortal) Publish(ctx context.Context, listing syndication.Listing) error {
+	p.published = append(p.published, listing)
+	return p.err
+}
+
+func (p *fakePortal) Unpublish(ctx context.Context, listingID string) error {
+	p.removed = append(p.removed, listingID)
+	return p.err
+}
+
+// publicID is the public ID of the property the tests syndicate
+const publicID = "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b"
+
+func TestSyndicationService_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	property := &models.Property{
+		ID: 12, PublicID: publicID, Name: "Casa Verde", Status: models.PropertyStatusActive, Price: 350000,
+		Photos: models.PhotoList{
+			{URL: "https://mls.example.com/a.jpg", LocalURL: "/images/a.jpg", Caption: "Front"},
+			{URL: "https://mls.example.com/b.jpg"},
+			{URL: "ftp://mls.example.com/c.jpg"},
+		},
+		SquareFeet: models.NullInt32{NullInt32: sql.NullInt32{Int32: 1850, Valid: true}},
+		AgentID:    models.NullInt32{NullInt32: sql.NullInt32{Int32: 5, Valid: true}},
+	}
+	mockRepo := mocks.NewMockSyndicationRepository(ctrl)
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(property, nil)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 13).Return(&models.Property{ID: 13, Status: models.PropertyStatusSold}, nil)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 14).Return(&models.Property{ID: 14, Status: models.PropertyStatusActive}, nil)
+	mockPublications := mocks.NewMockListingPublicationRepository(ctrl)
+	mockPublications.EXPECT().Get(gomock.Any(), 12).Return(&models.ListingPublication{PropertyID: 12, State: models.PublishApproved}, nil)
+	mockPublications.EXPECT().Get(gomock.Any(), 14).Return(&models.ListingPublication{PropertyID: 14, State: models.PublishPendingReview}, nil)
+	mockUsers.EXPECT().GetByID(gomock.Any(), uint(5)).Return(&models.User{ID: 5, Username: "Jane Doe", Email: "jane@example.com"}, nil)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, listing *models.SyndicationListing) error {
+		if listing.PropertyID != 12 || listing.Portal != syndication.Zillow || listing.ListingID != publicID || !listing.Enabled ||
+			listing.Status != models.SyndicationPublished || listing.SyncedAt == nil {
+			t.Errorf("Unexpected saved listing %+v", listing)
+		}
+		return nil
+	})
+
+	zillow := &fakePortal{name: syndication.Zillow}
+	service := NewSyndicationService([]syndication.Portal{zillow}, mockRepo, mockProperties, mockPublications, mockUsers,
+		SyndicationConfig{PublicBaseURL: "https://api.example.com/", ListingURL: "https://example.com/properties/{id}"})
+
+	if _, err := service.Publish(context.Background(), 12, syndication.Zillow); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(zillow.published) != 1 {
+		t.Fatalf("Expected 1 push, got %d", len(zillow.published))
+	}
+	pushed := zillow.published[0]
+	if pushed.ID != publicID || pushed.URL != "https://example.com/properties/"+publicID || pushed.LivingArea != 1850 || pushed.AgentName != "Jane Doe" {
+		t.Errorf("Unexpected listing %+v", pushed)
+	}
+	if len(pushed.Photos) != 2 || pushed.Photos[0].URL != "https://api.example.com/public/images/"+publicID+"/0?size=large" ||
+		pushed.Photos[1].URL != "https://mls.example.com/b.jpg" {
+		t.Errorf("Unexpected photos %+v", pushed.Photos)
+	}
+
+	if _, err := service.Publish(context.Background(), 12, syndication.Realtor); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for an unconfigured portal, got %v", err)
+	}
+	if _, err := service.Publish(context.Background(), 13, syndication.Zillow); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for a sold listing, got %v", err)
+	}
+	if _, err := service.Publish(context.Background(), 14, syndication.Zillow); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for a listing waiting for review, got %v", err)
+	}
+}
+
+func TestSyndicationService_SyncWithdrawn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSyndicationRepository(ctrl)
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), 12).Return([]models.SyndicationListing{
+		{PropertyID: 12, Portal: syndication.Zillow, ListingID: publicID, Enabled: true, Status: models.SyndicationPublished},
+		{PropertyID: 12, Portal: syndication.Realtor, ListingID: publicID, Enabled: true, Status: models.SyndicationWithdrawn},
+	}, nil)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12, Status: models.PropertyStatusSold}, nil)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, listing *models.SyndicationListing) error {
+		if listing.Portal != syndication.Zillow || !listing.Enabled || listing.Status != models.SyndicationWithdrawn {
+			t.Errorf("Unexpected saved listing %+v", listing)
+		}
+		return nil
+	})
+
+	zillow := &fakePortal{name: syndication.Zillow}
+	realtor := &fakePortal{name: syndication.Realtor}
+	service := NewSyndicationService([]syndication.Portal{zillow, realtor}, mockRepo, mockProperties, nil, nil, SyndicationConfig{})
+	if err := service.syncProperty(context.Background(), 12); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(zillow.removed) != 1 || len(realtor.removed) != 0 {
+		t.Errorf("Expected only the published listing to be withdrawn, got %v and %v", zillow.removed, realtor.removed)
+	}
+}
+
+func TestSyndicationService_SyncMovesLegacyListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSyndicationRepository(ctrl)
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockPublications := mocks.NewMockListingPublicationRepository(ctrl)
+	mockRepo.EXPECT().List(gomock.Any(), 12).Return([]models.SyndicationListing{
+		{PropertyID: 12, Portal: syndication.Zillow, ListingID: "12", Enabled: true, Status: models.SyndicationPublished},
+	}, nil)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(&models.Property{ID: 12, PublicID: publicID, Status: models.PropertyStatusActive}, nil)
+	mockPublications.EXPECT().Get(gomock.Any(), 12).Return(&models.ListingPublication{PropertyID: 12, State: models.PublishApproved}, nil)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, listing *models.SyndicationListing) error {
+		if listing.ListingID != publicID || listing.Status != models.SyndicationPublished {
+			t.Errorf("Unexpected saved listing %+v", listing)
+		}
+		return nil
+	})
+
+	zillow := &fakePortal{name: syndication.Zillow}
+	service := NewSyndicationService([]syndication.Portal{zillow}, mockRepo, mockProperties, mockPublications, nil, SyndicationConfig{})
+	if err := service.syncProperty(context.Background(), 12); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(zillow.removed) != 1 || zillow.removed[0] != "12" {
+		t.Errorf("Expected the listing under its numeric ID to be removed, got %v", zillow.removed)
+	}
+	if len(zillow.published) != 1 || zillow.published[0].ID != publicID {
+		t.Errorf("Expected the listing to be pushed under its public ID, got %+v", zillow.published)
+	}
+}
+
+func TestSyndicationService_SyncDeleted(t *testing.T) {
+	tests := []struct {
+		name         string
+		realtorErr   error
+		expectForget bool
+	}{
+		{name: "removed everywhere", expectForget: true},
+		{name: "portal unreachable", realtorErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockSyndicationRepository(ctrl)
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockRepo.EXPECT().List(gomock.Any(), 12).Return([]models.SyndicationListing{
+				{PropertyID: 12, Portal: syndication.Realtor, ListingID: publicID, Enabled: true, Status: models.SyndicationPublished},
+				{PropertyID: 12, Portal: syndication.Zillow, ListingID: "12", Enabled: true, Status: models.SyndicationPublished},
+			}, nil)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(nil, nil)
+			if tt.expectForget {
+				mockRepo.EXPECT().DeleteAll(gomock.Any(), 12).Return(nil)
+			} else {
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, listing *models.SyndicationListing) error {
+					if listing.Portal != syndication.Realtor || listing.Status != models.SyndicationFailed || listing.LastError != "connection refused" {
+						t.Errorf("Unexpected saved listing %+v", listing)
+					}
+					return nil
+				})
+			}
+
+			zillow := &fakePortal{name: syndication.Zillow}
+			realtor := &fakePortal{name: syndication.Realtor, err: tt.realtorErr}
+			service := NewSyndicationService([]syndication.Portal{zillow, realtor}, mockRepo, mockProperties, nil, nil, SyndicationConfig{})
+			if err := service.syncProperty(context.Background(), 12); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(zillow.removed) != 1 || len(realtor.removed) != 1 {
+				t.Errorf("Expected both portals to be asked to remove the listing")
+			}
+		})
+	}
+}
+
+func TestSyndicationService_HandleEvent(t *testing.T) {
+	service := NewSyndicationService(nil, nil, nil, nil, nil, SyndicationConfig{})
+	service.HandleEvent(context.Background(), events.New(events.PropertyDeleted, "property/12", nil))
+	service.HandleEvent(context.Background(), events.New(events.JobCompleted, "job/abc", nil))
+
+	if len(service.queue) != 1 || <-service.queue != 12 {
+		t.Errorf("Expected property 12 to be queued")
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/imaging"
+)
+
+// Widths of the copies generated next to each downloaded photo: thumbnails
+// for lists and cards, medium copies for galleries
+const (
+	ThumbnailWidth   = 200
+	MediumWidth      = 800
+	thumbnailQuality = 80
+)
+
+// thumbnailSizes are the copies generated, named after the original with
+// these suffixes
+var thumbnailSizes = []struct {
+	suffix string
+	width  int
+}{
+	{"_thumb", ThumbnailWidth},
+	{"_medium", MediumWidth},
+}
+
+// thumbnailName returns the file a copy of the photo stored as name is
+// stored as; copies are always JPEG
+func thumbnailName(name, suffix string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + suffix + ".jpg"
+}
+
+// thumbnailURLs returns the local URLs the copies of a local photo are
+// stored at, whether or not they were generated
+func thumbnailURLs(localURL string) []string {
+	dir, name := path.Split(localURL)
+	urls := make([]string, len(thumbnailSizes))
+	for i, size := range thumbnailSizes {
+		urls[i] = dir + thumbnailName(name, size.suffix)
+	}
+	return urls
+}
+
+// addThumbnails sets the thumbnail and medium URLs of photo, whose original
+// is stored as name. Copies are generated from content, which replaced the
+// original; when content is nil the original is unchanged, so copies
+// already stored are kept and only missing ones are generated from it. A
+// photo that cannot be decoded, or any photo while the image_transcoding
+// flag is off, gets no copies but is still imported.
+func (s *SimplyRETSService) addThumbnails(ctx context.Context, photo *models.Photo, name string, content []byte) {
+	if s.flags != nil && !s.flags.IsEnabled(FlagImageTranscoding) {
+		return
+	}
+	urls := []*string{&photo.ThumbnailURL, &photo.MediumURL}
+	var decoded *image.RGBA
+	for i, size := range thumbnailSizes {
+		variant := thumbnailName(name, size.suffix)
+		if content == nil {
+			if _, err := s.images.Size(ctx, variant); err == nil {
+				*urls[i] = s.images.URL(variant)
+				continue
+			}
+		}
+		if decoded == nil {
+			img, err := s.decodePhoto(ctx, name, content)
+			if err != nil {
+				log.Printf("Failed to generate thumbnails of %s: %v", name, err)
+				return
+			}
+			decoded = img
+		}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, imaging.Resize(decoded, size.width), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			log.Printf("Failed to encode thumbnail %s: %v", variant, err)
+			return
+		}
+		if _, err := s.images.Put(ctx, variant, &buf); err != nil {
+			log.Printf("Failed to store thumbnail %s: %v", variant, err)
+			return
+		}
+		*urls[i] = s.images.URL(variant)
+	}
+}
+
+// decodePhoto decodes content, or the stored original name when content
+// is nil
+func (s *SimplyRETSService) decodePhoto(ctx context.Context, name string, content []byte) (*image.RGBA, error) {
+	var r io.Reader = bytes.NewReader(content)
+	if content == nil {
+		stored, err := s.images.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		defer stored.Close()
+		r = stored
+	}
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode photo: %w", err)
+	}
+	return imaging.Flatten(img), nil
+}
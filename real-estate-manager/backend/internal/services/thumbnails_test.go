@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/imagestore"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSimplyRETSService_downloadImageThumbnails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	source := filepath.Join(t.TempDir(), "front.png")
+	writeTestPNG(t, source, 1200, 800)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeFile(w, r, source)
+	}))
+	defer server.Close()
+
+	service := NewSimplyRETSService(mocks.NewMockPropertyRepository(ctrl))
+	service.imagesDir = t.TempDir()
+	service.images = imagestore.NewLocal(service.imagesDir)
+
+	photo, err := service.downloadImage(context.Background(), server.URL+"/front.png", "L-300", 0, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if photo.LocalURL != "/images/L-300_0.png" || photo.ThumbnailURL != "/images/L-300_0_thumb.jpg" || photo.MediumURL != "/images/L-300_0_medium.jpg" {
+		t.Fatalf("Unexpected photo %+v", photo)
+	}
+	for name, width := range map[string]int{"L-300_0_thumb.jpg": ThumbnailWidth, "L-300_0_medium.jpg": MediumWidth} {
+		if got := imageWidth(t, filepath.Join(service.imagesDir, name)); got != width {
+			t.Errorf("Expected %s to be %dpx wide, got %d", name, width, got)
+		}
+	}
+
+	// An unchanged photo whose thumbnail is gone gets it again from the
+	// stored original
+	os.Remove(filepath.Join(service.imagesDir, "L-300_0_thumb.jpg"))
+	cached := &models.PhotoManifestEntry{URL: server.URL + "/front.png", ETag: `"v1"`, LocalURL: "/images/L-300_0.png"}
+	photo, err = service.downloadImage(context.Background(), server.URL+"/front.png", "L-300", 0, cached)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if photo.ThumbnailURL != "/images/L-300_0_thumb.jpg" || photo.MediumURL != "/images/L-300_0_medium.jpg" {
+		t.Errorf("Expected the cached photo's thumbnails, got %+v", photo)
+	}
+	if got := imageWidth(t, filepath.Join(service.imagesDir, "L-300_0_thumb.jpg")); got != ThumbnailWidth {
+		t.Errorf("Expected the thumbnail to be regenerated, got %dpx", got)
+	}
+}
+
+func TestSimplyRETSService_downloadImageUndecodable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("fake jpeg data"))
+	}))
+	defer server.Close()
+
+	service := NewSimplyRETSService(mocks.NewMockPropertyRepository(ctrl))
+	service.imagesDir = t.TempDir()
+	service.images = imagestore.NewLocal(service.imagesDir)
+
+	photo, err := service.downloadImage(context.Background(), server.URL+"/front.jpg", "L-301", 0, nil)
+	if err != nil {
+		t.Fatalf("Expected the photo to be kept without thumbnails, got %v", err)
+	}
+	if photo.LocalURL != "/images/L-301_0.jpg" || photo.ThumbnailURL != "" || photo.MediumURL != "" {
+		t.Errorf("Unexpected photo %+v", photo)
+	}
+}
+
+func TestSimplyRETSService_downloadImageTranscodingOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	source := filepath.Join(t.TempDir(), "front.png")
+	writeTestPNG(t, source, 1200, 800)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		http.ServeFile(w, r, source)
+	}))
+	defer server.Close()
+
+	// image_transcoding is off unless FEATURE_IMAGE_TRANSCODING says otherwise
+	flags := NewFeatureFlagService(mocks.NewMockFeatureFlagRepository(ctrl))
+	service := NewSimplyRETSService(mocks.NewMockPropertyRepository(ctrl), WithFeatureFlags(flags))
+	service.imagesDir = t.TempDir()
+	service.images = imagestore.NewLocal(service.imagesDir)
+
+	photo, err := service.downloadImage(context.Background(), server.URL+"/front.png", "L-302", 0, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if photo.LocalURL != "/images/L-302_0.png" || photo.ThumbnailURL != "" || photo.MediumURL != "" {
+		t.Errorf("Expected the photo without thumbnails, got %+v", photo)
+	}
+	if _, err := os.Stat(filepath.Join(service.imagesDir, "L-302_0_thumb.jpg")); !os.IsNotExist(err) {
+		t.Errorf("Expected no thumbnail to be stored, got %v", err)
+	}
+}
+
+func imageWidth(t *testing.T, path string) int {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.Width
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// TokenBlacklist holds the tokens revoked before they expire, one by one
+// or all those issued to a user up to some time. Revocations are stored in
+// the database and kept in memory, so checking a token on every request
+// needs no query; other instances pick them up on their next Load.
+type TokenBlacklist struct {
+	repo       repository.RevokedTokenRepository
+	mu         sync.RWMutex
+	revoked    map[string]time.Time // token hash -> token expiry
+	validAfter map[uint]time.Time   // user ID -> tokens issued up to then are refused
+	now        func() time.Time
+}
+
+func NewTokenBlacklist(repo repository.RevokedTokenRepository) *TokenBlacklist {
+	return &TokenBlacklist{repo: repo, revoked: map[string]time.Time{}, validAfter: map[uint]time.Time{}, now: time.Now}
+}
+
+// Load replaces the in-memory blacklist with the stored revocations of
+// tokens that have not expired
+func (b *TokenBlacklist) Load(ctx context.Context) error {
+	tokens, err := b.repo.ListActive(ctx, b.now())
+	if err != nil {
+		return err
+	}
+	revoked := make(map[string]time.Time, len(tokens))
+	for _, token := range tokens {
+		revoked[token.TokenHash] = token.ExpiresAt
+	}
+	validAfter, err := b.repo.ListRevokedUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.revoked = revoked
+	b.validAfter = validAfter
+	b.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the blacklist every interval until ctx is done
+func (b *TokenBlacklist) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Load(ctx); err != nil {
+				log.Printf("Failed to reload revoked tokens: %v", err)
+			}
+		}
+	}
+}
+
+// Add stores a revocation and applies it on this instance at once
+func (b *TokenBlacklist) Add(ctx context.Context, token *models.RevokedToken) error {
+	if err := b.repo.Create(ctx, token); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.revoked[token.TokenHash] = token.ExpiresAt
+	b.mu.Unlock()
+	return nil
+}
+
+// RevokeUser refuses every token issued to the user until now. Token issue
+// times have a one-second resolution, so tokens issued within the current
+// second are refused too.
+func (b *TokenBlacklist) RevokeUser(ctx context.Context, userID uint) error {
+	at := b.now().UTC().Truncate(time.Second)
+	if err := b.repo.RevokeUser(ctx, userID, at); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.validAfter[userID] = at
+	b.mu.Unlock()
+	return nil
+}
+
+// ContainsUser reports whether a token issued to the user at issuedAt, in
+// Unix seconds, was revoked along with the user's other tokens
+func (b *TokenBlacklist) ContainsUser(userID uint, issuedAt int64) bool {
+	b.mu.RLock()
+	validAfter, ok := b.validAfter[userID]
+	b.mu.RUnlock()
+	return ok && issuedAt <= validAfter.Unix()
+}
+
+// Contains reports whether token was revoked and has not expired yet
+func (b *TokenBlacklist) Contains(token string) bool {
+	b.mu.RLock()
+	expiresAt, ok := b.revoked[hashToken(token)]
+	b.mu.RUnlock()
+	return ok && expiresAt.After(b.now())
+}
+
+// Prune forgets the revocations of expired tokens, returning how many
+// were removed from the database
+func (b *TokenBlacklist) Prune(ctx context.Context) (int64, error) {
+	now := b.now()
+	b.mu.Lock()
+	for hash, expiresAt := range b.revoked {
+		if !expiresAt.After(now) {
+			delete(b.revoked, hash)
+		}
+	}
+	b.mu.Unlock()
+	return b.repo.DeleteExpired(ctx, now)
+}
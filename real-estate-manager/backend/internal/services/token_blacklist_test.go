@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthService_RevokeToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevoked := mocks.NewMockRevokedTokenRepository(ctrl)
+	mockRevoked.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, token *models.RevokedToken) error {
+		if len(token.TokenHash) != 64 || token.UserID.Int32 != 7 || token.RevokedBy.Int32 != 1 {
+			t.Errorf("Unexpected revocation %+v", token)
+		}
+		return nil
+	})
+
+	blacklist := NewTokenBlacklist(mockRevoked)
+	auth := NewAuthServiceWithSecret(mocks.NewMockUserRepository(ctrl), "secret", WithTokenBlacklist(blacklist))
+	token, err := auth.signToken(userClaims(&models.User{ID: 7, Username: "agent"}, time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	other, _ := auth.signToken(userClaims(&models.User{ID: 8, Username: "broker"}, time.Hour))
+
+	revoked, err := auth.RevokeToken(WithActor(context.Background(), 1), token)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if revoked.ExpiresAt.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("Expected the revocation to last until the token expires, got %v", revoked.ExpiresAt)
+	}
+	if _, err := auth.ValidateToken(token); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected the revoked token to be refused, got %v", err)
+	}
+	if _, err := auth.ValidateToken(other); err != nil {
+		t.Errorf("Expected other tokens to stay valid, got %v", err)
+	}
+	if _, err := auth.RevokeToken(context.Background(), "invalid.token.string"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error for an invalid token, got %v", err)
+	}
+}
+
+func TestTokenBlacklist_LoadAndPrune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockRevoked := mocks.NewMockRevokedTokenRepository(ctrl)
+	mockRevoked.EXPECT().ListActive(gomock.Any(), now).Return([]models.RevokedToken{
+		{TokenHash: hashToken("leaked"), ExpiresAt: now.Add(time.Hour)},
+	}, nil)
+	mockRevoked.EXPECT().ListRevokedUsers(gomock.Any()).Return(map[uint]time.Time{}, nil)
+	mockRevoked.EXPECT().DeleteExpired(gomock.Any(), now.Add(2*time.Hour)).Return(int64(1), nil)
+
+	blacklist := NewTokenBlacklist(mockRevoked)
+	blacklist.now = func() time.Time { return now }
+	if err := blacklist.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !blacklist.Contains("leaked") || blacklist.Contains("other") {
+		t.Error("Expected only the stored token to be blacklisted")
+	}
+
+	// Once the token has expired it no longer needs blacklisting
+	blacklist.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if blacklist.Contains("leaked") {
+		t.Error("Expected an expired revocation to be ignored")
+	}
+	if count, err := blacklist.Prune(context.Background()); err != nil || count != 1 {
+		t.Errorf("Unexpected prune result %d, %v", count, err)
+	}
+	if len(blacklist.revoked) != 0 {
+		t.Errorf("Expected the expired revocation to be dropped, got %v", blacklist.revoked)
+	}
+}
+
+func TestAuthService_RevokeUserTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Tokens issued in the future are invalid, so the revocation is in the past
+	now := time.Now().Add(-time.Minute)
+	mockRevoked := mocks.NewMockRevokedTokenRepository(ctrl)
+	mockRevoked.EXPECT().RevokeUser(gomock.Any(), uint(7), now.UTC().Truncate(time.Second)).Return(nil)
+
+	blacklist := NewTokenBlacklist(mockRevoked)
+	blacklist.now = func() time.Time { return now }
+	auth := NewAuthServiceWithSecret(mocks.NewMockUserRepository(ctrl), "secret", WithTokenBlacklist(blacklist))
+	sign := func(userID uint, issuedAt time.Time) string {
+		claims := userClaims(&models.User{ID: userID, Username: "agent"}, time.Hour)
+		claims["iat"] = issuedAt.Unix()
+		token, err := auth.signToken(claims)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return token
+	}
+	earlier := sign(7, now.Add(-time.Minute))
+	other := sign(8, now.Add(-time.Minute))
+
+	if err := auth.RevokeUserTokens(context.Background(), 7); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := auth.ValidateToken(earlier); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected a token issued before the revocation to be refused, got %v", err)
+	}
+	if _, err := auth.ValidateToken(other); err != nil {
+		t.Errorf("Expected other users' tokens to stay valid, got %v", err)
+	}
+	if _, err := auth.ValidateToken(sign(7, now.Add(time.Minute))); err != nil {
+		t.Errorf("Expected a token issued after the revocation to be valid, got %v", err)
+	}
+
+	// Other instances learn of the revocation on their next load
+	mockRevoked.EXPECT().ListActive(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRevoked.EXPECT().ListRevokedUsers(gomock.Any()).Return(map[uint]time.Time{8: now}, nil)
+	if err := blacklist.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !blacklist.ContainsUser(8, now.Add(-time.Minute).Unix()) || blacklist.ContainsUser(8, now.Add(time.Minute).Unix()) {
+		t.Error("Expected the loaded revocation to refuse only earlier tokens")
+	}
+
+	if err := NewAuthServiceWithSecret(nil, "secret").RevokeUserTokens(context.Background(), 7); err == nil {
+		t.Error("Expected an error without a blacklist")
+	}
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/pkg/imagestore"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxUploadChunkBytes caps the bytes one chunk request may carry
+	MaxUploadChunkBytes = 64 << 20
+	// UploadSessionTTL is how long a session is kept after its last chunk
+	UploadSessionTTL        = 24 * time.Hour
+	uploadSessionCleanBatch = 100
+)
+
+// DocumentStore is where documents uploaded through sessions are stored,
+// the bucket direct uploads go to
+type DocumentStore interface {
+	URL(key string) string
+	PutFile(ctx context.Context, key, contentType, path string) (int64, error)
+}
+
+// UploadSessionService takes large photos and documents in chunks over
+// several requests, so an upload interrupted by a flaky connection resumes
+// where it stopped instead of starting over. Chunks are written to a file
+// in dir; completing the session scans the file and stores it like a
+// regular upload: photos in the image storage, documents in documents.
+type UploadSessionService struct {
+	sessions   repository.UploadSessionRepository
+	uploads    repository.UploadRepository
+	documents  DocumentStore
+	properties *PropertyService
+	storage    *StorageService
+	scans      *VirusScanService
+	images     imagestore.Storage
+	dir        string
+	now        func() time.Time
+
+	// Chunks and completion of one session run one at a time
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewUploadSessionService takes documents only when documents is not nil
+func NewUploadSessionService(sessions repository.UploadSessionRepository, uploads repository.UploadRepository, documents DocumentStore,
+	properties *PropertyService, storage *StorageService, scans *VirusScanService, images imagestore.Storage, dir string) *UploadSessionService {
+	os.MkdirAll(dir, 0755)
+	return &UploadSessionService{
+		sessions:   sessions,
+		uploads:    uploads,
+		documents:  documents,
+		properties: properties,
+		storage:    storage,
+		scans:      scans,
+		images:     images,
+		dir:        dir,
+		now:        time.Now,
+		locks:      make(map[string]*sessionLock),
+	}
+}
+
+// Create starts a session for a file of the declared size, which is
+// checked against the uploader's quota up front
+func (s *UploadSessionService) Create(ctx context.Context, req models.UploadSessionRequest) (*models.UploadSession, error) {
+	limit := int64(MaxDirectUploadBytes)
+	if req.Kind == "" || req.Kind == models.FileKindPhoto {
+		limit = MaxPhotoBytes
+	}
+	kind, _, err := checkUploadFile(req.Kind, req.Filename, req.SizeBytes, limit)
+	if err != nil {
+		return nil, err
+	}
+	if kind == models.FileKindDocument && s.documents == nil {
+		return nil, apperrors.Validation("document uploads need object storage, which is not configured")
+	}
+
+	if _, err := s.properties.GetProperty(ctx, req.PropertyID); err != nil {
+		return nil, err
+	}
+	owner, err := s.storage.OwnerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.CheckQuota(ctx, owner, req.SizeBytes); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	session := &models.UploadSession{
+		ID:             uuid.New().String(),
+		UserID:         nullID(owner.UserID),
+		OrganizationID: nullID(owner.OrganizationID),
+		PropertyID:     req.PropertyID,
+		Kind:           kind,
+		Filename:       filepath.Base(req.Filename),
+		ContentType:    req.ContentType,
+		Caption:        req.Caption,
+		SizeBytes:      req.SizeBytes,
+		Status:         models.UploadSessionActive,
+		ExpiresAt:      now.Add(UploadSessionTTL).UTC(),
+		CreatedAt:      now.UTC(),
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Get returns a session, for the client to find the offset to resume from
+func (s *UploadSessionService) Get(ctx context.Context, id string) (*models.UploadSession, error) {
+	return s.session(ctx, id)
+}
+
+// WriteChunk appends content to the session's file at offset, which must be
+// the number of bytes received so far. Whatever arrives is kept, even when
+// the connection drops mid-chunk, so the client resumes from the offset
+// the session then reports.
+func (s *UploadSessionService) WriteChunk(ctx context.Context, id string, offset int64, content io.Reader) (*models.UploadSession, error) {
+	unlock := s.lock(id)
+	defer unlock()
+
+	session, err := s.activeSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != session.ReceivedBytes {
+		return nil, apperrors.Conflictf("upload offset is %d, not %d", session.ReceivedBytes, offset)
+	}
+	remaining := session.SizeBytes - session.ReceivedBytes
+	if remaining == 0 {
+		return nil, apperrors.Validation("upload has received the whole file")
+	}
+
+	file, err := os.OpenFile(s.partPath(id), os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	written, copyErr := io.Copy(io.NewOffsetWriter(file, offset), io.LimitReader(content, min(remaining, MaxUploadChunkBytes)))
+	if err := file.Close(); copyErr == nil {
+		copyErr = err
+	}
+
+	if written > 0 {
+		expiresAt := s.now().Add(UploadSessionTTL).UTC()
+		advanced, err := s.sessions.Advance(ctx, id, offset, offset+written, expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if !advanced {
+			return nil, apperrors.Conflict("upload session changed while writing the chunk")
+		}
+		session.ReceivedBytes, session.ExpiresAt = offset+written, expiresAt
+	}
+	if copyErr != nil {
+		return nil, fmt.Errorf("failed to write upload chunk: %w", copyErr)
+	}
+	// Bytes past the declared size or the chunk limit are not taken
+	if n, _ := content.Read(make([]byte, 1)); n > 0 {
+		if written == remaining {
+			return nil, apperrors.Validationf("chunk goes past the declared size of %d bytes", session.SizeBytes)
+		}
+		return nil, apperrors.TooLargef("chunk is larger than %s; the first %d bytes were kept", formatMB(MaxUploadChunkBytes), written)
+	}
+	return session, nil
+}
+
+// Complete stores a fully received file once it is scanned for viruses:
+// photos are added to the property and documents registered as uploads,
+// as direct uploads are when confirmed
+func (s *UploadSessionService) Complete(ctx context.Context, id string) (*models.UploadSession, error) {
+	unlock := s.lock(id)
+	defer unlock()
+
+	session, err := s.activeSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.ReceivedBytes != session.SizeBytes {
+		return nil, apperrors.Validationf("upload has received %d of %d bytes", session.ReceivedBytes, session.SizeBytes)
+	}
+	property, err := s.properties.GetProperty(ctx, session.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other uploads may have used up the quota since the session started
+	owner := StorageOwner{UserID: int(session.UserID.Int32), OrganizationID: int(session.OrganizationID.Int32)}
+	if err := s.storage.CheckQuota(ctx, owner, session.SizeBytes); err != nil {
+		return nil, err
+	}
+	path := s.partPath(id)
+	scanned := ScannedFile{Owner: owner, PropertyID: session.PropertyID, Kind: session.Kind, Filename: session.Filename, SizeBytes: session.SizeBytes}
+	if err := s.scans.ScanFile(ctx, scanned, path); err != nil {
+		// An infected file has been moved to quarantine
+		if errors.Is(err, apperrors.ErrValidation) {
+			s.discard(ctx, id)
+		}
+		return nil, err
+	}
+
+	var url string
+	if session.Kind == models.FileKindPhoto {
+		url, err = s.storePhoto(ctx, session, path)
+	} else {
+		url, err = s.storeDocument(ctx, session, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Record(ctx, owner, session.PropertyID, session.Kind, url, session.SizeBytes); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if _, err := s.sessions.MarkCompleted(ctx, id, url, now); err != nil {
+		return nil, err
+	}
+	os.Remove(path)
+	session.Status, session.URL, session.CompletedAt = models.UploadSessionCompleted, url, nullTime(now)
+
+	if session.Kind == models.FileKindPhoto {
+		photo := models.Photo{URL: url, LocalURL: url, Caption: session.Caption}
+		if err := addPhoto(ctx, s.properties, property, photo); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// Cancel abandons a session and removes what it received
+func (s *UploadSessionService) Cancel(ctx context.Context, id string) error {
+	unlock := s.lock(id)
+	defer unlock()
+
+	if _, err := s.session(ctx, id); err != nil {
+		return err
+	}
+	return s.discard(ctx, id)
+}
+
+// Clean removes sessions that expired, completed or not, with whatever
+// they received, and returns how many were removed
+func (s *UploadSessionService) Clean(ctx context.Context) (int, error) {
+	removed := 0
+	for {
+		expired, err := s.sessions.ListExpired(ctx, s.now(), uploadSessionCleanBatch)
+		if err != nil {
+			return removed, err
+		}
+		for _, session := range expired {
+			if err := s.discard(ctx, session.ID); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+		if len(expired) < uploadSessionCleanBatch {
+			return removed, nil
+		}
+	}
+}
+
+func (s *UploadSessionService) storePhoto(ctx context.Context, session *models.UploadSession, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer file.Close()
+
+	name := fmt.Sprintf("upload_%d_%s%s", session.PropertyID, session.ID, filepath.Ext(session.Filename))
+	if _, err := s.images.Put(ctx, name, file); err != nil {
+		return "", err
+	}
+	return s.images.URL(name), nil
+}
+
+// storeDocument puts the document in the bucket and registers it as a
+// completed upload, where e-signatures find documents
+func (s *UploadSessionService) storeDocument(ctx context.Context, session *models.UploadSession, path string) (string, error) {
+	key := fmt.Sprintf("properties/%d/%s%s", session.PropertyID, session.ID, filepath.Ext(session.Filename))
+	if _, err := s.documents.PutFile(ctx, key, session.ContentType, path); err != nil {
+		return "", err
+	}
+	upload := models.Upload{
+		ID:             session.ID,
+		UserID:         session.UserID,
+		OrganizationID: session.OrganizationID,
+		PropertyID:     session.PropertyID,
+		Kind:           session.Kind,
+		ObjectKey:      key,
+		Filename:       session.Filename,
+		ContentType:    session.ContentType,
+		Caption:        session.Caption,
+		SizeBytes:      session.SizeBytes,
+		Status:         models.UploadStatusPending,
+	}
+	if err := s.uploads.Create(ctx, &upload); err != nil {
+		return "", err
+	}
+	if err := s.uploads.MarkCompleted(ctx, upload.ID, upload.SizeBytes, s.now()); err != nil {
+		return "", err
+	}
+	return s.documents.URL(key), nil
+}
+
+// session returns a session its uploader may see
+func (s *UploadSessionService) session(ctx context.Context, id string) (*models.UploadSession, error) {
+	session, err := s.sessions.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || !session.ExpiresAt.After(s.now()) {
+		return nil, apperrors.NotFound("upload session not found")
+	}
+	if err := checkUploader(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// activeSession returns a session still taking chunks
+func (s *UploadSessionService) activeSession(ctx context.Context, id string) (*models.UploadSession, error) {
+	session, err := s.session(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.UploadSessionActive {
+		return nil, apperrors.Conflict("upload session already completed")
+	}
+	return session, nil
+}
+
+// discard removes a session and its file
+func (s *UploadSessionService) discard(ctx context.Context, id string) error {
+	if err := os.Remove(s.partPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Failed to remove upload file of session %s: %v", id, err)
+	}
+	return s.sessions.Delete(ctx, id)
+}
+
+func (s *UploadSessionService) partPath(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".part")
+}
+
+// lock serializes work on one session and returns its unlock
+func (s *UploadSessionService) lock(id string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[id]
+	if !ok {
+		l = &sessionLock{}
+		s.locks[id] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(s.locks, id)
+		}
+		s.mu.Unlock()
+	}
+}
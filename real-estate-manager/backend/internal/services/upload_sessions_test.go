@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/pkg/imagestore"
+
+	"go.uber.org/mock/gomock"
+)
+
+func uploadSession(received int64) *models.UploadSession {
+	return &models.UploadSession{
+		ID:            "s1",
+		UserID:        nullID(7),
+		PropertyID:    1,
+		Kind:          models.FileKindPhoto,
+		Filename:      "front.jpg",
+		Caption:       "Front",
+		SizeBytes:     10,
+		ReceivedBytes: received,
+		Status:        models.UploadSessionActive,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+}
+
+func newUploadSessionService(ctrl *gomock.Controller, sessions *mocks.MockUploadSessionRepository,
+	properties *mocks.MockPropertyRepository, dir string) *UploadSessionService {
+	storageRepo := mocks.NewMockStorageRepository(ctrl)
+	storageRepo.EXPECT().UsageByUser(gomock.Any(), 7).Return(int64(0), nil).AnyTimes()
+	storageRepo.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().GetByID(gomock.Any(), uint(7)).Return(&models.User{ID: 7}, nil).AnyTimes()
+
+	return NewUploadSessionService(sessions, mocks.NewMockUploadRepository(ctrl), nil, NewPropertyService(properties),
+		NewStorageService(storageRepo, userRepo, staticSettings{}), nil, imagestore.NewLocal(filepath.Join(dir, "images")), dir)
+}
+
+func TestUploadSessionService_Create(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        models.UploadSessionRequest
+		expectKind error
+	}{
+		{name: "photo", req: models.UploadSessionRequest{PropertyID: 1, Filename: "front.jpg", SizeBytes: 10}},
+		{name: "photo over the photo limit", req: models.UploadSessionRequest{PropertyID: 1, Filename: "front.jpg", SizeBytes: MaxPhotoBytes + 1},
+			expectKind: apperrors.ErrTooLarge},
+		{name: "document without object storage", req: models.UploadSessionRequest{PropertyID: 1, Kind: models.FileKindDocument, Filename: "plan.pdf", SizeBytes: 10},
+			expectKind: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessions := mocks.NewMockUploadSessionRepository(ctrl)
+			properties := mocks.NewMockPropertyRepository(ctrl)
+			if tt.expectKind == nil {
+				properties.EXPECT().GetByID(gomock.Any(), 1).Return(photoProperty(), nil)
+				sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := newUploadSessionService(ctrl, sessions, properties, t.TempDir())
+			session, err := service.Create(WithActor(context.Background(), 7), tt.req)
+			if tt.expectKind != nil {
+				if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if session.ID == "" || session.Kind != models.FileKindPhoto || session.UserID.Int32 != 7 || session.Status != models.UploadSessionActive {
+				t.Errorf("Unexpected session %+v", session)
+			}
+		})
+	}
+}
+
+func TestUploadSessionService_WriteChunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockUploadSessionRepository(ctrl)
+	dir := t.TempDir()
+	service := newUploadSessionService(ctrl, sessions, mocks.NewMockPropertyRepository(ctrl), dir)
+	ctx := WithActor(context.Background(), 7)
+
+	sessions.EXPECT().GetByID(gomock.Any(), "s1").Return(uploadSession(0), nil)
+	sessions.EXPECT().Advance(gomock.Any(), "s1", int64(0), int64(4), gomock.Any()).Return(true, nil)
+	session, err := service.WriteChunk(ctx, "s1", 0, strings.NewReader("0123"))
+	if err != nil || session.ReceivedBytes != 4 {
+		t.Fatalf("Expected 4 bytes received, got %+v, %v", session, err)
+	}
+
+	// A chunk resent from a stale offset is refused
+	sessions.EXPECT().GetByID(gomock.Any(), "s1").Return(uploadSession(4), nil)
+	if _, err := service.WriteChunk(ctx, "s1", 0, strings.NewReader("0123")); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+
+	// Bytes past the declared size are not kept
+	sessions.EXPECT().GetByID(gomock.Any(), "s1").Return(uploadSession(4), nil)
+	sessions.EXPECT().Advance(gomock.Any(), "s1", int64(4), int64(10), gomock.Any()).Return(true, nil)
+	if _, err := service.WriteChunk(ctx, "s1", 4, strings.NewReader("456789extra")); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "s1.part"))
+	if string(content) != "0123456789" {
+		t.Errorf("Expected the declared bytes to be kept, got %q", content)
+	}
+
+	// Sessions of other users are not theirs to write
+	sessions.EXPECT().GetByID(gomock.Any(), "s1").Return(uploadSession(0), nil)
+	if _, err := service.WriteChunk(WithActor(context.Background(), 8), "s1", 0, strings.NewReader("0")); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden, got %v", err)
+	}
+}
+
+func TestUploadSessionService_CompletePhoto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockUploadSessionRepository(ctrl)
+	properties := mocks.NewMockPropertyRepository(ctrl)
+	dir := t.TempDir()
+	service := newUploadSessionService(ctrl, sessions, properties, dir)
+	ctx := WithActor(context.Background(), 7)
+	os.WriteFile(filepath.Join(dir, "s1.part"), []byte("0123456789"), 0644)
+
+	// A partly received file cannot be completed
+	sessions.EXPECT().GetByID(gomock.Any(), "s1").Return(uploadSession(4), nil)
+	if _, err := service.Complete(ctx, "s1"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	url := "/images/upload_1_s1.jpg"
+	sessions.EXPECT().GetByID(gomock.Any(), "s1").Return(uploadSession(10), nil)
+	properties.EXPECT().GetByID(gomock.Any(), 1).Return(photoProperty(), nil)
+	sessions.EXPECT().MarkCompleted(gomock.Any(), "s1", url, gomock.Any()).Return(true, nil)
+	properties.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, property *models.Property) error {
+		last := property.Photos[len(property.Photos)-1]
+		if len(property.Photos) != 4 || last.LocalURL != url || last.Caption != "Front" {
+			t.Errorf("Expected the photo to be added, got %+v", property.Photos)
+		}
+		return nil
+	})
+
+	session, err := service.Complete(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.Status != models.UploadSessionCompleted || session.URL != url {
+		t.Errorf("Unexpected session %+v", session)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "images", "upload_1_s1.jpg")); string(content) != "0123456789" {
+		t.Errorf("Expected the photo to be stored, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "s1.part")); !os.IsNotExist(err) {
+		t.Errorf("Expected the upload file to be removed, got %v", err)
+	}
+}
+
+func TestUploadSessionService_Clean(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockUploadSessionRepository(ctrl)
+	dir := t.TempDir()
+	service := newUploadSessionService(ctrl, sessions, mocks.NewMockPropertyRepository(ctrl), dir)
+	os.WriteFile(filepath.Join(dir, "s1.part"), []byte("0123"), 0644)
+
+	sessions.EXPECT().ListExpired(gomock.Any(), gomock.Any(), uploadSessionCleanBatch).
+		Return([]models.UploadSession{*uploadSession(4), {ID: "s2"}}, nil)
+	sessions.EXPECT().Delete(gomock.Any(), "s1").Return(nil)
+	sessions.EXPECT().Delete(gomock.Any(), "s2").Return(nil)
+
+	removed, err := service.Clean(context.Background())
+	if err != nil || removed != 2 {
+		t.Fatalf("Expected 2 sessions removed, got %d, %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "s1.part")); !os.IsNotExist(err) {
+		t.Errorf("Expected the upload file to be removed, got %v", err)
+	}
+}
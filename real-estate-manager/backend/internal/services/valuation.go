@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// Estimator tuning. Comparables are searched within valuationLookback,
+// valuationRadiusKm and valuationSizeRange of the subject's living area;
+// the valuationMaxComps with the highest weight are used.
+const (
+	valuationLookbackMonths = 24
+	valuationRadiusKm       = 5.0
+	valuationSizeRange      = 0.5
+	valuationCandidates     = 200
+	valuationMaxComps       = 10
+	valuationHalfLifeDays   = 180
+	// A trend needs this many comparables over this many months, and is
+	// capped so a few outliers cannot swing every estimate
+	valuationTrendMinComps  = 6
+	valuationTrendMinMonths = 3
+	valuationTrendMaxRate   = 0.03
+	// Asking prices count for less than sale prices
+	valuationListingWeight = 0.8
+	daysPerMonth           = 30.44
+	kmPerDegree            = 111.32
+)
+
+// Confidence levels of an estimate
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// ValuationService estimates what a property is worth from comparable
+// sales and listings nearby, the trend in their price per square foot and
+// how recent they are
+type ValuationService struct {
+	repo        repository.ValuationRepository
+	properties  *PropertyService
+	enrichments repository.EnrichmentRepository
+	now         func() time.Time
+}
+
+func NewValuationService(repo repository.ValuationRepository, properties *PropertyService, enrichments repository.EnrichmentRepository) *ValuationService {
+	return &ValuationService{repo: repo, properties: properties, enrichments: enrichments, now: time.Now}
+}
+
+// Estimate values a property. Each comparable's price per square foot is
+// moved to today along the market trend and weighted by recency,
+// similarity and distance; the weighted mean times the property's living
+// area is the estimate, and the spread of the comparables sets its range.
+func (s *ValuationService) Estimate(ctx context.Context, propertyID int) (*models.ValuationEstimate, error) {
+	property, err := s.properties.GetProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if property.IsRental() {
+		return nil, apperrors.Validation("only properties for sale can be valued")
+	}
+	if !property.SquareFeet.Valid || property.SquareFeet.Int32 <= 0 {
+		return nil, apperrors.Validation("the property needs square_feet to be valued")
+	}
+	sqft := int(property.SquareFeet.Int32)
+
+	now := s.now()
+	criteria := models.ComparableCriteria{
+		ExcludeID: property.ID,
+		MinSqft:   int(math.Floor(float64(sqft) * (1 - valuationSizeRange))),
+		MaxSqft:   int(math.Ceil(float64(sqft) * (1 + valuationSizeRange))),
+		Since:     now.AddDate(0, -valuationLookbackMonths, 0),
+		Limit:     valuationCandidates,
+	}
+	if property.PropertyType.Valid {
+		criteria.PropertyType = property.PropertyType.String
+	}
+
+	enrichment, err := s.enrichments.GetByPropertyID(ctx, property.ID)
+	if err != nil {
+		return nil, err
+	}
+	var lat, lng float64
+	located := enrichment != nil && enrichment.Latitude.Valid && enrichment.Longitude.Valid
+	var area string
+	if located {
+		lat, lng = enrichment.Latitude.Float64, enrichment.Longitude.Float64
+		latDelta := valuationRadiusKm / kmPerDegree
+		lngDelta := valuationRadiusKm / (kmPerDegree * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+		criteria.UseBounds = true
+		criteria.MinLatitude, criteria.MaxLatitude = lat-latDelta, lat+latDelta
+		criteria.MinLongitude, criteria.MaxLongitude = lng-lngDelta, lng+lngDelta
+		area = fmt.Sprintf("within %.0f km", valuationRadiusKm)
+	} else {
+		criteria.Locality = locality(property.Location)
+		if criteria.Locality == "" {
+			return nil, apperrors.Validation("the property needs a location to be valued")
+		}
+		area = "in " + criteria.Locality
+	}
+
+	comps, err := s.repo.ListComparables(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+	if len(comps) == 0 {
+		return nil, apperrors.Validation("no comparable sales or listings were found to value the property")
+	}
+
+	monthsAgo := make(map[int]float64, len(comps))
+	for i := range comps {
+		comp := &comps[i]
+		comp.PricePerSqft = comp.Price / float64(comp.SquareFeet)
+		monthsAgo[comp.PropertyID] = math.Max(now.Sub(comp.ObservedAt).Hours()/24/daysPerMonth, 0)
+		if located && comp.Latitude.Valid && comp.Longitude.Valid {
+			distance := math.Round(haversineKm(lat, lng, comp.Latitude.Float64, comp.Longitude.Float64)*100) / 100
+			comp.DistanceKm = &distance
+		}
+	}
+
+	trend := priceTrend(comps, monthsAgo)
+	for i := range comps {
+		comp := &comps[i]
+		comp.AdjustedPricePerSqft = comp.PricePerSqft
+		if trend.Applied {
+			comp.AdjustedPricePerSqft *= math.Pow(1+trend.MonthlyChangePercent/100, monthsAgo[comp.PropertyID])
+		}
+		comp.Weight = comparableWeight(property, sqft, comp, monthsAgo[comp.PropertyID])
+	}
+	sort.SliceStable(comps, func(i, j int) bool { return comps[i].Weight > comps[j].Weight })
+	considered := len(comps)
+	if len(comps) > valuationMaxComps {
+		comps = comps[:valuationMaxComps]
+	}
+
+	var total, weighted float64
+	for _, comp := range comps {
+		total += comp.Weight
+		weighted += comp.Weight * comp.AdjustedPricePerSqft
+	}
+	mean := weighted / total
+	var variance float64
+	for _, comp := range comps {
+		variance += comp.Weight * (comp.AdjustedPricePerSqft - mean) * (comp.AdjustedPricePerSqft - mean)
+	}
+	spread := valuationSpread(math.Sqrt(variance/total)/mean, len(comps))
+
+	value := mean * float64(sqft)
+	for i := range comps {
+		comps[i].PricePerSqft = roundCents(comps[i].PricePerSqft)
+		comps[i].AdjustedPricePerSqft = roundCents(comps[i].AdjustedPricePerSqft)
+		comps[i].Weight = math.Round(comps[i].Weight/total*1000) / 1000
+	}
+	trend.MonthlyChangePercent = math.Round(trend.MonthlyChangePercent*100) / 100
+
+	return &models.ValuationEstimate{
+		PropertyID:   property.ID,
+		Value:        roundThousands(value),
+		Low:          roundThousands(value * (1 - spread)),
+		High:         roundThousands(value * (1 + spread)),
+		Confidence:   valuationConfidence(len(comps), spread),
+		PricePerSqft: roundCents(mean),
+		SquareFeet:   sqft,
+		Methodology: models.ValuationMethodology{
+			Summary: "Weighted mean price per square foot of comparable sales and listings, adjusted to today along " +
+				"the local price trend and weighted by recency, size, bedrooms, distance and whether the price is a sale",
+			Area:                area,
+			Since:               criteria.Since,
+			PropertyType:        criteria.PropertyType,
+			CompsConsidered:     considered,
+			CompsUsed:           len(comps),
+			RecencyHalfLifeDays: valuationHalfLifeDays,
+			Trend:               trend,
+		},
+		Comparables: comps,
+		GeneratedAt: now,
+	}, nil
+}
+
+// priceTrend fits a line through the comparables' price per square foot
+// by age and returns the monthly change it implies
+func priceTrend(comps []models.Comparable, monthsAgo map[int]float64) models.ValuationTrend {
+	oldest, newest := math.Inf(-1), math.Inf(1)
+	var sumX, sumY float64
+	for _, comp := range comps {
+		x := monthsAgo[comp.PropertyID]
+		oldest, newest = math.Max(oldest, x), math.Min(newest, x)
+		sumX += x
+		sumY += comp.PricePerSqft
+	}
+	months := 0
+	if len(comps) > 0 {
+		months = int(math.Round(oldest - newest))
+	}
+	trend := models.ValuationTrend{Months: months}
+	if len(comps) < valuationTrendMinComps || oldest-newest < valuationTrendMinMonths {
+		return trend
+	}
+
+	n := float64(len(comps))
+	meanX, meanY := sumX/n, sumY/n
+	var covariance, varianceX float64
+	for _, comp := range comps {
+		dx := monthsAgo[comp.PropertyID] - meanX
+		covariance += dx * (comp.PricePerSqft - meanY)
+		varianceX += dx * dx
+	}
+	// The slope is per month of age, so prices rising over time give a
+	// negative slope
+	rate := -(covariance / varianceX) / meanY
+	rate = math.Max(-valuationTrendMaxRate, math.Min(valuationTrendMaxRate, rate))
+	trend.MonthlyChangePercent = rate * 100
+	trend.Applied = true
+	return trend
+}
+
+// comparableWeight rates how much a comparable tells about the subject:
+// recent, similar and close comparables count the most
+func comparableWeight(subject *models.Property, sqft int, comp *models.Comparable, monthsAgo float64) float64 {
+	weight := math.Pow(0.5, monthsAgo*daysPerMonth/valuationHalfLifeDays)
+	weight *= math.Max(1-math.Abs(float64(comp.SquareFeet-sqft))/float64(sqft), 0.1)
+	if subject.Bedrooms.Valid && comp.Bedrooms.Valid {
+		weight /= 1 + math.Abs(float64(comp.Bedrooms.Int32-subject.Bedrooms.Int32))
+	}
+	if comp.DistanceKm != nil {
+		weight /= 1 + *comp.DistanceKm
+	}
+	if comp.Source == models.ComparableListing {
+		weight *= valuationListingWeight
+	}
+	return weight
+}
+
+// valuationSpread turns the comparables' relative deviation into the
+// half-width of the estimate's range
+func valuationSpread(deviation float64, comps int) float64 {
+	spread := math.Max(0.05, math.Min(0.25, deviation))
+	if comps < 3 {
+		spread = math.Max(spread, 0.15)
+	}
+	return spread
+}
+
+func valuationConfidence(comps int, spread float64) string {
+	switch {
+	case comps >= 5 && spread <= 0.10:
+		return ConfidenceHigh
+	case comps >= 3 && spread <= 0.20:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// locality returns the part of an address after its street, such as
+// "Springfield, IL" in "12 Elm St, Springfield, IL"
+func locality(location string) string {
+	location = strings.TrimSpace(location)
+	if _, rest, ok := strings.Cut(location, ","); ok && strings.TrimSpace(rest) != "" {
+		return strings.TrimSpace(rest)
+	}
+	return location
+}
+
+// haversineKm is the great-circle distance between two points
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLng := (lng2 - lng1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func roundThousands(amount float64) float64 {
+	return math.Round(amount/1000) * 1000
+}
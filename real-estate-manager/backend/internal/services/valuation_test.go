@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestValuationService_Estimate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	comp := func(id int, price float64, sqft int, monthsAgo int, source string) models.Comparable {
+		return models.Comparable{PropertyID: id, Price: price, SquareFeet: sqft, Source: source,
+			ObservedAt: now.AddDate(0, -monthsAgo, 0)}
+	}
+	subject := &models.Property{ID: 12, Location: "12 Elm St, Springfield, IL",
+		SquareFeet: models.NullInt32{NullInt32: sql.NullInt32{Int32: 2000, Valid: true}}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProperties := mocks.NewMockPropertyRepository(ctrl)
+	mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(subject, nil)
+	mockEnrichments := mocks.NewMockEnrichmentRepository(ctrl)
+	mockEnrichments.EXPECT().GetByPropertyID(gomock.Any(), 12).Return(nil, nil)
+	mockRepo := mocks.NewMockValuationRepository(ctrl)
+	mockRepo.EXPECT().ListComparables(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, criteria models.ComparableCriteria) ([]models.Comparable, error) {
+			// Without coordinates the search falls back to the town
+			if criteria.UseBounds || criteria.Locality != "Springfield, IL" {
+				t.Errorf("Expected a search in Springfield, IL, got %+v", criteria)
+			}
+			if criteria.MinSqft != 1000 || criteria.MaxSqft != 3000 {
+				t.Errorf("Expected 1000-3000 sqft, got %d-%d", criteria.MinSqft, criteria.MaxSqft)
+			}
+			return []models.Comparable{
+				comp(1, 400000, 2000, 1, models.ComparableSale),
+				comp(2, 420000, 2100, 2, models.ComparableSale),
+				comp(3, 380000, 1900, 3, models.ComparableSale),
+				comp(4, 410000, 2050, 1, models.ComparableListing),
+				comp(5, 390000, 1950, 4, models.ComparableSale),
+			}, nil
+		})
+
+	service := NewValuationService(mockRepo, NewPropertyService(mockProperties), mockEnrichments)
+	service.now = func() time.Time { return now }
+
+	estimate, err := service.Estimate(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	// Every comparable is at about $200 per square foot
+	if estimate.Value != 400000 {
+		t.Errorf("Expected a value of 400000, got %v", estimate.Value)
+	}
+	if estimate.Low >= estimate.Value || estimate.High <= estimate.Value {
+		t.Errorf("Expected the value inside its range, got %v-%v", estimate.Low, estimate.High)
+	}
+	if estimate.Confidence != ConfidenceHigh {
+		t.Errorf("Expected high confidence, got %s", estimate.Confidence)
+	}
+	if estimate.Methodology.CompsUsed != 5 || estimate.Methodology.Trend.Applied {
+		t.Errorf("Expected 5 comps without a trend, got %+v", estimate.Methodology)
+	}
+	if estimate.Comparables[0].PropertyID != 1 {
+		t.Errorf("Expected the most recent same-size sale first, got %d", estimate.Comparables[0].PropertyID)
+	}
+}
+
+func TestValuationService_EstimateWithoutComparables(t *testing.T) {
+	tests := []struct {
+		name     string
+		property *models.Property
+		comps    bool
+	}{
+		{name: "no square feet", property: &models.Property{ID: 12, Location: "Springfield"}},
+		{name: "no comparables", property: &models.Property{ID: 12, Location: "Springfield",
+			SquareFeet: models.NullInt32{NullInt32: sql.NullInt32{Int32: 2000, Valid: true}}}, comps: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProperties := mocks.NewMockPropertyRepository(ctrl)
+			mockProperties.EXPECT().GetByID(gomock.Any(), 12).Return(tt.property, nil)
+			mockEnrichments := mocks.NewMockEnrichmentRepository(ctrl)
+			mockRepo := mocks.NewMockValuationRepository(ctrl)
+			if tt.comps {
+				mockEnrichments.EXPECT().GetByPropertyID(gomock.Any(), 12).Return(nil, nil)
+				mockRepo.EXPECT().ListComparables(gomock.Any(), gomock.Any()).Return([]models.Comparable{}, nil)
+			}
+
+			service := NewValuationService(mockRepo, NewPropertyService(mockProperties), mockEnrichments)
+			_, err := service.Estimate(context.Background(), 12)
+			if !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPriceTrend(t *testing.T) {
+	// Prices rose 1% a month over the last year
+	var comps []models.Comparable
+	monthsAgo := map[int]float64{}
+	for i := 0; i < 12; i++ {
+		comps = append(comps, models.Comparable{PropertyID: i, PricePerSqft: 200 * math.Pow(1.01, float64(-i))})
+		monthsAgo[i] = float64(i)
+	}
+
+	trend := priceTrend(comps, monthsAgo)
+	if !trend.Applied || math.Abs(trend.MonthlyChangePercent-1) > 0.1 {
+		t.Errorf("Expected about 1%% a month, got %+v", trend)
+	}
+
+	// Too few comparables to fit a trend
+	if trend := priceTrend(comps[:4], monthsAgo); trend.Applied {
+		t.Errorf("Expected no trend from 4 comparables, got %+v", trend)
+	}
+}
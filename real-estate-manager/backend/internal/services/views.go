@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+)
+
+// recentlyViewedLimit is how many properties a user's recently viewed list
+// keeps; older views are dropped
+const recentlyViewedLimit = 50
+
+// viewRepeatWindow is how long after a user's view of a property another
+// view by them does not count again, so reloads do not inflate the counters
+const viewRepeatWindow = 30 * time.Minute
+
+// ViewService records which properties users view, for their recently
+// viewed list and the properties' view counters
+type ViewService struct {
+	repo       repository.ViewRepository
+	properties *PropertyService
+	now        func() time.Time
+}
+
+func NewViewService(repo repository.ViewRepository, properties *PropertyService) *ViewService {
+	return &ViewService{repo: repo, properties: properties, now: time.Now}
+}
+
+// Record notes that a user viewed a property
+func (s *ViewService) Record(ctx context.Context, userID uint, propertyID int) error {
+	now := s.now()
+	last, err := s.repo.LastViewed(ctx, userID, propertyID)
+	if err != nil {
+		return err
+	}
+	count := last == nil || now.Sub(*last) >= viewRepeatWindow
+	return s.repo.Record(ctx, userID, propertyID, now, recentlyViewedLimit, count)
+}
+
+// Recent returns the properties a user viewed, most recent first
+func (s *ViewService) Recent(ctx context.Context, userID uint, limit int) ([]models.RecentlyViewed, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > recentlyViewedLimit {
+		return nil, apperrors.Validationf("limit must be at most %d", recentlyViewedLimit)
+	}
+	return s.repo.ListRecent(ctx, userID, limit)
+}
+
+// Stats returns a property's view counters
+func (s *ViewService) Stats(ctx context.Context, propertyID int) (*models.PropertyViewStats, error) {
+	if _, err := s.properties.GetProperty(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetStats(ctx, propertyID)
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestViewService_Record(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	tests := []struct {
+		name      string
+		last      *time.Time
+		wantCount bool
+	}{
+		{name: "first view", wantCount: true},
+		{name: "reload", last: ago(5 * time.Minute), wantCount: false},
+		{name: "later visit", last: ago(2 * time.Hour), wantCount: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockViewRepository(ctrl)
+			mockRepo.EXPECT().LastViewed(gomock.Any(), uint(4), 12).Return(tt.last, nil)
+			mockRepo.EXPECT().Record(gomock.Any(), uint(4), 12, now, recentlyViewedLimit, tt.wantCount).Return(nil)
+
+			service := NewViewService(mockRepo, nil)
+			service.now = func() time.Time { return now }
+			if err := service.Record(context.Background(), 4, 12); err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestViewService_Recent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockViewRepository(ctrl)
+	mockRepo.EXPECT().ListRecent(gomock.Any(), uint(4), 20).Return(nil, nil)
+
+	service := NewViewService(mockRepo, nil)
+	if _, err := service.Recent(context.Background(), 4, 0); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+	if _, err := service.Recent(context.Background(), 4, 500); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a validation error for a limit over the list size, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/repository"
+	"real-estate-manager/backend/internal/scanner"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultFileScanLimit = 50
+	maxFileScanLimit     = 500
+)
+
+// ScannedFile describes an upload being scanned, for the scan record
+type ScannedFile struct {
+	Owner      StorageOwner
+	PropertyID int
+	Kind       string
+	Filename   string
+	SizeBytes  int64
+}
+
+// VirusScanService scans uploaded photos and documents before they are
+// stored. Infected files are moved to the quarantine directory and every
+// verdict is recorded. Without a scanner uploads are accepted unscanned.
+type VirusScanService struct {
+	scanner       scanner.Scanner
+	scans         repository.FileScanRepository
+	quarantineDir string
+}
+
+func NewVirusScanService(s scanner.Scanner, scans repository.FileScanRepository, quarantineDir string) *VirusScanService {
+	if s != nil {
+		os.MkdirAll(quarantineDir, 0700)
+	}
+	return &VirusScanService{scanner: s, scans: scans, quarantineDir: quarantineDir}
+}
+
+// Enabled reports whether uploads are scanned
+func (s *VirusScanService) Enabled() bool {
+	return s != nil && s.scanner != nil
+}
+
+// ScanFile scans a file written to path. An infected file is moved to
+// quarantine and rejected with a validation error.
+func (s *VirusScanService) ScanFile(ctx context.Context, file ScannedFile, path string) error {
+	if !s.Enabled() {
+		return nil
+	}
+	content, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open upload: %w", err)
+	}
+	result, err := s.scanner.Scan(ctx, content)
+	content.Close()
+	return s.settle(ctx, file, result, err, func(dest string) error {
+		return os.Rename(path, dest)
+	})
+}
+
+// ScanStream scans content read from elsewhere, such as object storage. A
+// copy is kept while scanning so an infected file can be quarantined
+// locally after the caller removes the original.
+func (s *VirusScanService) ScanStream(ctx context.Context, file ScannedFile, content io.Reader) error {
+	if !s.Enabled() {
+		return nil
+	}
+	kept, err := os.CreateTemp(s.quarantineDir, "scan-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create scan copy: %w", err)
+	}
+	defer os.Remove(kept.Name())
+
+	result, err := s.scanner.Scan(ctx, io.TeeReader(content, kept))
+	kept.Close()
+	return s.settle(ctx, file, result, err, func(dest string) error {
+		return os.Rename(kept.Name(), dest)
+	})
+}
+
+// settle records the verdict on a scanned file, quarantining it if infected.
+// Files the scanner could not check are refused, except those over its
+// size limit, which are recorded as skipped.
+func (s *VirusScanService) settle(ctx context.Context, file ScannedFile, result *scanner.Result, scanErr error, quarantine func(dest string) error) error {
+	scan := &models.FileScan{
+		UserID:         nullID(file.Owner.UserID),
+		OrganizationID: nullID(file.Owner.OrganizationID),
+		PropertyID:     nullID(file.PropertyID),
+		Kind:           file.Kind,
+		Filename:       filepath.Base(file.Filename),
+		SizeBytes:      file.SizeBytes,
+	}
+	switch {
+	case errors.Is(scanErr, scanner.ErrTooLarge):
+		scan.Status = models.FileScanSkipped
+	case scanErr != nil:
+		log.Printf("Virus scan of %s failed: %v", scan.Filename, scanErr)
+		return apperrors.Unavailable("virus scanner is unavailable, try again later")
+	case result.Infected:
+		scan.Status = models.FileScanInfected
+		scan.Signature = result.Signature
+		dest := filepath.Join(s.quarantineDir, uuid.New().String()+"_"+scan.Filename)
+		if err := quarantine(dest); err != nil {
+			return fmt.Errorf("failed to quarantine %s: %w", scan.Filename, err)
+		}
+		scan.QuarantinePath = dest
+	default:
+		scan.Status = models.FileScanClean
+	}
+
+	if err := s.scans.Record(ctx, scan); err != nil {
+		return fmt.Errorf("failed to record virus scan: %w", err)
+	}
+	if scan.Status == models.FileScanInfected {
+		return apperrors.Validationf("file was flagged by the virus scanner (%s) and quarantined", scan.Signature)
+	}
+	return nil
+}
+
+// List returns the most recent scans, optionally only those with a status
+func (s *VirusScanService) List(ctx context.Context, status string, limit int) ([]models.FileScan, error) {
+	switch status {
+	case "", models.FileScanClean, models.FileScanInfected, models.FileScanSkipped:
+	default:
+		return nil, apperrors.Validation("status must be clean, infected or skipped")
+	}
+	if limit == 0 {
+		limit = defaultFileScanLimit
+	}
+	if limit < 0 || limit > maxFileScanLimit {
+		return nil, apperrors.Validationf("limit must be between 1 and %d", maxFileScanLimit)
+	}
+	return s.scans.List(ctx, status, limit)
+}
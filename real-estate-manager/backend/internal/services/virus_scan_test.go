@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"real-estate-manager/backend/internal/apperrors"
+	"real-estate-manager/backend/internal/mocks"
+	"real-estate-manager/backend/internal/models"
+	"real-estate-manager/backend/internal/scanner"
+
+	"go.uber.org/mock/gomock"
+)
+
+// fakeScanner reads the whole file and returns a fixed verdict
+type fakeScanner struct {
+	result *scanner.Result
+	err    error
+}
+
+func (f *fakeScanner) Scan(ctx context.Context, content io.Reader) (*scanner.Result, error) {
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		return nil, err
+	}
+	return f.result, f.err
+}
+
+func (f *fakeScanner) Check(ctx context.Context) error {
+	return f.err
+}
+
+func TestVirusScanService_ScanFile(t *testing.T) {
+	tests := []struct {
+		name             string
+		scanner          *fakeScanner
+		expectedStatus   string
+		expectKind       error
+		expectQuarantine bool
+	}{
+		{name: "clean file", scanner: &fakeScanner{result: &scanner.Result{}}, expectedStatus: models.FileScanClean},
+		{
+			name:             "infected file",
+			scanner:          &fakeScanner{result: &scanner.Result{Infected: true, Signature: "Win.Test.EICAR_HDB-1"}},
+			expectedStatus:   models.FileScanInfected,
+			expectKind:       apperrors.ErrValidation,
+			expectQuarantine: true,
+		},
+		{name: "over the scanner's limit", scanner: &fakeScanner{err: scanner.ErrTooLarge}, expectedStatus: models.FileScanSkipped},
+		{name: "scanner unreachable", scanner: &fakeScanner{err: errors.New("connection refused")}, expectKind: apperrors.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "front.jpg")
+			os.WriteFile(path, []byte("photo"), 0644)
+
+			scanRepo := mocks.NewMockFileScanRepository(ctrl)
+			if tt.expectedStatus != "" {
+				scanRepo.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, scan *models.FileScan) error {
+					if scan.Status != tt.expectedStatus || scan.Filename != "front.jpg" || scan.UserID != nullID(7) {
+						t.Errorf("Unexpected scan record: %+v", scan)
+					}
+					if (scan.QuarantinePath != "") != tt.expectQuarantine {
+						t.Errorf("Expected quarantine=%v, got path %q", tt.expectQuarantine, scan.QuarantinePath)
+					}
+					return nil
+				})
+			}
+
+			service := NewVirusScanService(tt.scanner, scanRepo, filepath.Join(dir, "quarantine"))
+			file := ScannedFile{Owner: StorageOwner{UserID: 7}, PropertyID: 1, Kind: models.FileKindPhoto, Filename: "front.jpg", SizeBytes: 5}
+			err := service.ScanFile(context.Background(), file, path)
+
+			if tt.expectKind != nil {
+				if !errors.Is(err, tt.expectKind) {
+					t.Errorf("Expected error kind %v, got %v", tt.expectKind, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if _, statErr := os.Stat(path); tt.expectQuarantine != errors.Is(statErr, os.ErrNotExist) {
+				t.Errorf("Expected file moved=%v, stat returned %v", tt.expectQuarantine, statErr)
+			}
+		})
+	}
+}
+
+func TestVirusScanService_Disabled(t *testing.T) {
+	var service *VirusScanService
+	if err := service.ScanFile(context.Background(), ScannedFile{}, "missing.jpg"); err != nil {
+		t.Errorf("Expected unscanned uploads to be accepted, got %v", err)
+	}
+	service = NewVirusScanService(nil, nil, t.TempDir())
+	if service.Enabled() {
+		t.Error("Expected scanning to be disabled without a scanner")
+	}
+}
@@ -0,0 +1,135 @@
+// Package sms sends short text messages over SMS or WhatsApp for
+// time-sensitive notifications. The provider is chosen with SMS_PROVIDER;
+// "twilio" speaks the Twilio Messages API, which several other providers
+// also implement.
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Channels a message can be delivered over
+const (
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+)
+
+const twilioBaseURL = "https://api.twilio.com"
+
+// Message is a text message to an E.164 phone number
+type Message struct {
+	To      string
+	Body    string
+	Channel string
+}
+
+// Sender delivers messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewFromEnv builds the sender selected by SMS_PROVIDER: "none" (default),
+// "log" or "twilio" (TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN, TWILIO_FROM and
+// optionally TWILIO_WHATSAPP_FROM and TWILIO_BASE_URL). It returns nil when
+// text messages are disabled.
+func NewFromEnv() (Sender, error) {
+	switch provider := strings.ToLower(os.Getenv("SMS_PROVIDER")); provider {
+	case "", "none":
+		return nil, nil
+	case "log":
+		return LogSender{}, nil
+	case "twilio":
+		config := TwilioConfig{
+			AccountSID:   os.Getenv("TWILIO_ACCOUNT_SID"),
+			AuthToken:    os.Getenv("TWILIO_AUTH_TOKEN"),
+			From:         os.Getenv("TWILIO_FROM"),
+			WhatsAppFrom: os.Getenv("TWILIO_WHATSAPP_FROM"),
+			BaseURL:      os.Getenv("TWILIO_BASE_URL"),
+		}
+		if config.AccountSID == "" || config.AuthToken == "" || config.From == "" {
+			return nil, fmt.Errorf("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM are required for the twilio SMS provider")
+		}
+		return NewTwilioSender(config), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_PROVIDER %q", provider)
+	}
+}
+
+// LogSender writes messages to the server log instead of sending them
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	log.Printf("%s to %s: %s", msg.Channel, msg.To, msg.Body)
+	return nil
+}
+
+// TwilioConfig describes a Twilio (or Twilio-compatible) account
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	// WhatsAppFrom is the WhatsApp sender; From is used when empty
+	WhatsAppFrom string
+	BaseURL      string // optional override for compatible providers
+}
+
+// TwilioSender sends messages through the Twilio Messages API
+type TwilioSender struct {
+	config TwilioConfig
+	client *http.Client
+}
+
+func NewTwilioSender(config TwilioConfig) *TwilioSender {
+	if config.BaseURL == "" {
+		config.BaseURL = twilioBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.WhatsAppFrom == "" {
+		config.WhatsAppFrom = config.From
+	}
+	return &TwilioSender{config: config, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *TwilioSender) Send(ctx context.Context, msg Message) error {
+	to, from := msg.To, s.config.From
+	if msg.Channel == ChannelWhatsApp {
+		to, from = "whatsapp:"+msg.To, "whatsapp:"+s.config.WhatsAppFrom
+	}
+	form := url.Values{"To": {to}, "From": {from}, "Body": {msg.Body}}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.config.BaseURL, url.PathEscape(s.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create message request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.config.AccountSID, s.config.AuthToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	var failure struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(body, &failure) == nil && failure.Message != "" {
+		return fmt.Errorf("message provider returned status %d: %s (code %d)", resp.StatusCode, failure.Message, failure.Code)
+	}
+	return fmt.Errorf("message provider returned status %d", resp.StatusCode)
+}
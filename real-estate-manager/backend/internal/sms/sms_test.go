@@ -0,0 +1,59 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTwilioSender_Send(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          Message
+		status       int
+		response     string
+		expectedTo   string
+		expectedFrom string
+		expectError  string
+	}{
+		{name: "sms", msg: Message{To: "+15551234567", Body: "New lead", Channel: ChannelSMS}, status: http.StatusCreated,
+			expectedTo: "+15551234567", expectedFrom: "+15550000000"},
+		{name: "whatsapp", msg: Message{To: "+15551234567", Body: "New lead", Channel: ChannelWhatsApp}, status: http.StatusCreated,
+			expectedTo: "whatsapp:+15551234567", expectedFrom: "whatsapp:+15559999999"},
+		{name: "provider error", msg: Message{To: "+15551234567", Body: "New lead", Channel: ChannelSMS}, status: http.StatusBadRequest,
+			response: `{"code": 21211, "message": "Invalid 'To' Phone Number"}`, expectedTo: "+15551234567", expectedFrom: "+15550000000",
+			expectError: "Invalid 'To' Phone Number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if user, pass, ok := r.BasicAuth(); !ok || user != "AC123" || pass != "token" {
+					t.Errorf("unexpected credentials %q:%q", user, pass)
+				}
+				r.ParseForm()
+				if r.PostForm.Get("To") != tt.expectedTo || r.PostForm.Get("From") != tt.expectedFrom || r.PostForm.Get("Body") != tt.msg.Body {
+					t.Errorf("unexpected form %v", r.PostForm)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			sender := NewTwilioSender(TwilioConfig{AccountSID: "AC123", AuthToken: "token", From: "+15550000000",
+				WhatsAppFrom: "+15559999999", BaseURL: server.URL})
+			err := sender.Send(context.Background(), tt.msg)
+			if tt.expectError == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectError)) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package syndication
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// NewRealtorPortal pushes listings as Realtor.com-style JSON documents
+func NewRealtorPortal(client *http.Client, endpoint, token string) Portal {
+	return &feedPortal{name: Realtor, client: client, endpoint: endpoint, token: token,
+		contentType: "application/json", encode: encodeRealtor}
+}
+
+type realtorListing struct {
+	ListingID    string         `json:"listing_id"`
+	MLSID        string         `json:"mls_id,omitempty"`
+	Status       string         `json:"status"`
+	ListPrice    int64          `json:"list_price"`
+	PropertyType string         `json:"property_type"`
+	Address      string         `json:"address"`
+	Headline     string         `json:"headline"`
+	Description  string         `json:"description,omitempty"`
+	Beds         int            `json:"beds,omitempty"`
+	Baths        int            `json:"baths,omitempty"`
+	SqFt         int            `json:"sqft,omitempty"`
+	LotSize      string         `json:"lot_size,omitempty"`
+	YearBuilt    int            `json:"year_built,omitempty"`
+	Permalink    string         `json:"permalink,omitempty"`
+	Photos       []realtorPhoto `json:"photos"`
+	Agent        *realtorAgent  `json:"agent,omitempty"`
+	LastUpdate   time.Time      `json:"last_update"`
+}
+
+type realtorPhoto struct {
+	Href        string `json:"href"`
+	Description string `json:"description,omitempty"`
+}
+
+type realtorAgent struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+var realtorStatuses = map[string]string{"active": "for_sale", "pending": "pending"}
+
+var realtorPropertyTypes = map[string]string{
+	typeSingleFamily: "single_family",
+	typeCondo:        "condos",
+	typeTownhouse:    "townhomes",
+	typeMultiFamily:  "multi_family",
+	typeLand:         "land",
+	typeManufactured: "mobile",
+}
+
+func encodeRealtor(listing Listing) ([]byte, error) {
+	doc := realtorListing{
+		ListingID:    listing.ID,
+		MLSID:        listing.MLSNumber,
+		Status:       realtorStatuses[listing.Status],
+		ListPrice:    int64(listing.Price + 0.5),
+		PropertyType: realtorPropertyTypes[normalizePropertyType(listing.PropertyType)],
+		Address:      listing.Address,
+		Headline:     listing.Title,
+		Description:  listing.Description,
+		Beds:         listing.Bedrooms,
+		Baths:        listing.Bathrooms,
+		SqFt:         listing.LivingArea,
+		LotSize:      listing.LotSize,
+		YearBuilt:    listing.YearBuilt,
+		Permalink:    listing.URL,
+		Photos:       []realtorPhoto{},
+		LastUpdate:   listing.UpdatedAt.UTC(),
+	}
+	for _, photo := range listing.Photos {
+		doc.Photos = append(doc.Photos, realtorPhoto{Href: photo.URL, Description: photo.Caption})
+	}
+	if listing.AgentName != "" {
+		doc.Agent = &realtorAgent{Name: listing.AgentName, Email: listing.AgentEmail}
+	}
+	return json.Marshal(doc)
+}
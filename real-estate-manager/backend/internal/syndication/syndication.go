@@ -0,0 +1,135 @@
+// Package syndication pushes listings to third-party portals such as Zillow
+// and Realtor.com. Each portal maps listings to its own feed format and is
+// enabled by configuring its endpoint.
+package syndication
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Portal names
+const (
+	Zillow  = "zillow"
+	Realtor = "realtor"
+)
+
+// Listing is a property in the portal-neutral shape the feed formats are
+// built from. ID is the property's public ID, which portals know the
+// listing by. Areas are in square feet, as US portals expect.
+type Listing struct {
+	ID           string
+	MLSNumber    string
+	Title        string
+	Address      string
+	Status       string
+	Price        float64
+	PropertyType string
+	Description  string
+	Bedrooms     int
+	Bathrooms    int
+	LivingArea   int
+	LotSize      string
+	YearBuilt    int
+	// URL is the listing's public page
+	URL        string
+	Photos     []Photo
+	AgentName  string
+	AgentEmail string
+	UpdatedAt  time.Time
+}
+
+// Photo is a publicly reachable listing photo
+type Photo struct {
+	URL     string
+	Caption string
+}
+
+// Portal publishes listings to one third-party site
+type Portal interface {
+	Name() string
+	Publish(ctx context.Context, listing Listing) error
+	Unpublish(ctx context.Context, listingID string) error
+}
+
+// NewFromEnv returns the portals whose push endpoint is configured:
+// SYNDICATION_ZILLOW_URL and SYNDICATION_REALTOR_URL, each with an optional
+// bearer token in SYNDICATION_ZILLOW_TOKEN / SYNDICATION_REALTOR_TOKEN
+func NewFromEnv() []Portal {
+	client := &http.Client{Timeout: 30 * time.Second}
+	var portals []Portal
+	if endpoint := os.Getenv("SYNDICATION_ZILLOW_URL"); endpoint != "" {
+		portals = append(portals, NewZillowPortal(client, endpoint, os.Getenv("SYNDICATION_ZILLOW_TOKEN")))
+	}
+	if endpoint := os.Getenv("SYNDICATION_REALTOR_URL"); endpoint != "" {
+		portals = append(portals, NewRealtorPortal(client, endpoint, os.Getenv("SYNDICATION_REALTOR_TOKEN")))
+	}
+	return portals
+}
+
+// feedPortal pushes encoded listings to a portal's HTTP endpoint: PUT
+// <endpoint>/listings/<id> publishes or refreshes a listing and DELETE
+// removes it
+type feedPortal struct {
+	name        string
+	client      *http.Client
+	endpoint    string
+	token       string
+	contentType string
+	encode      func(Listing) ([]byte, error)
+}
+
+func (p *feedPortal) Name() string {
+	return p.name
+}
+
+func (p *feedPortal) Publish(ctx context.Context, listing Listing) error {
+	body, err := p.encode(listing)
+	if err != nil {
+		return fmt.Errorf("failed to encode listing for %s: %w", p.name, err)
+	}
+	return p.do(ctx, http.MethodPut, listing.ID, body)
+}
+
+func (p *feedPortal) Unpublish(ctx context.Context, listingID string) error {
+	return p.do(ctx, http.MethodDelete, listingID, nil)
+}
+
+func (p *feedPortal) do(ctx context.Context, method string, listingID string, body []byte) error {
+	target, err := url.JoinPath(p.endpoint, "listings", listingID)
+	if err != nil {
+		return fmt.Errorf("invalid %s endpoint: %w", p.name, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", p.name, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", p.contentType)
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	// A listing the portal never had is already unpublished
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned status %d: %s", p.name, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
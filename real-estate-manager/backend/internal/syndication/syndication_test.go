@@ -0,0 +1,141 @@
+package syndication
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testListing = Listing{
+	ID:           "6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b",
+	MLSNumber:    "1005192",
+	Title:        "Casa Verde",
+	Address:      "12 Main St, Houston, TX",
+	Status:       "active",
+	Price:        349999.6,
+	PropertyType: "Residential Condominium",
+	Bedrooms:     3,
+	Bathrooms:    2,
+	LivingArea:   1850,
+	URL:          "https://example.com/properties/6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b",
+	Photos:       []Photo{{URL: "https://api.example.com/public/images/6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b/0?size=large", Caption: "Front"}},
+	AgentName:    "Jane Doe",
+	AgentEmail:   "jane@example.com",
+	UpdatedAt:    time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestFeedPortal_Publish(t *testing.T) {
+	var method, path, auth, contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		auth, contentType = r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	portal := NewRealtorPortal(server.Client(), server.URL+"/v1/", "secret")
+	if err := portal.Publish(context.Background(), testListing); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if method != http.MethodPut || path != "/v1/listings/6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b" || auth != "Bearer secret" || contentType != "application/json" {
+		t.Errorf("Unexpected request %s %s (auth %q, content type %q)", method, path, auth, contentType)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Invalid JSON body: %v", err)
+	}
+	if doc["listing_id"] != testListing.ID || doc["status"] != "for_sale" || doc["list_price"] != float64(350000) ||
+		doc["property_type"] != "condos" || doc["sqft"] != float64(1850) {
+		t.Errorf("Unexpected document %v", doc)
+	}
+	if photos, _ := doc["photos"].([]any); len(photos) != 1 {
+		t.Errorf("Expected 1 photo, got %v", doc["photos"])
+	}
+}
+
+func TestFeedPortal_Unpublish(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectError bool
+	}{
+		{name: "removed", status: http.StatusNoContent},
+		{name: "never published", status: http.StatusNotFound},
+		{name: "portal error", status: http.StatusInternalServerError, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != "/listings/6f1c2b9e-3d4a-4e5f-8a7b-9c0d1e2f3a4b" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte("try again later"))
+			}))
+			defer server.Close()
+
+			err := NewZillowPortal(server.Client(), server.URL, "").Unpublish(context.Background(), testListing.ID)
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), "zillow returned status 500: try again later") {
+					t.Errorf("Expected a status error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodeZillow(t *testing.T) {
+	body, err := encodeZillow(testListing)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(body), xml.Header) {
+		t.Errorf("Expected an XML header, got %q", body[:40])
+	}
+
+	var doc zillowListing
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Invalid XML: %v", err)
+	}
+	if doc.ListingDetails.Status != "Active" || doc.ListingDetails.Price != 350000 || doc.ListingDetails.ProviderId != testListing.ID {
+		t.Errorf("Unexpected listing details %+v", doc.ListingDetails)
+	}
+	if doc.BasicDetails.PropertyType != "Condo" || doc.BasicDetails.LivingArea != 1850 {
+		t.Errorf("Unexpected basic details %+v", doc.BasicDetails)
+	}
+	if doc.Agent.FirstName != "Jane" || doc.Agent.LastName != "Doe" {
+		t.Errorf("Unexpected agent %+v", doc.Agent)
+	}
+	if len(doc.Pictures) != 1 || doc.Pictures[0].Caption != "Front" {
+		t.Errorf("Unexpected pictures %+v", doc.Pictures)
+	}
+}
+
+func TestNormalizePropertyType(t *testing.T) {
+	tests := map[string]string{
+		"Residential Condominium": typeCondo,
+		"Townhouse":               typeTownhouse,
+		"Duplex":                  typeMultiFamily,
+		"Lots and Land":           typeLand,
+		"Mobile Home":             typeManufactured,
+		"Residential":             typeSingleFamily,
+		"":                        typeSingleFamily,
+	}
+	for raw, expected := range tests {
+		if got := normalizePropertyType(raw); got != expected {
+			t.Errorf("normalizePropertyType(%q) = %q, expected %q", raw, got, expected)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+)
+
+// NewZillowPortal pushes listings in the Zillow listing feed XML format
+func NewZillowPortal(client *http.Client, endpoint, token string) Portal {
+	return &feedPortal{name: Zillow, client: client, endpoint: endpoint, token: token,
+		contentType: "application/xml", encode: encodeZillow}
+}
+
+type zillowListing struct {
+	XMLName        xml.Name `xml:"Listing"`
+	Location       zillowLocation
+	ListingDetails zillowDetails
+	BasicDetails   zillowBasics
+	Pictures       []zillowPicture `xml:"Pictures>Picture"`
+	Agent          zillowAgent
+}
+
+type zillowLocation struct {
+	StreetAddress string
+}
+
+type zillowDetails struct {
+	Status     string
+	Price      int64
+	ListingUrl string `xml:",omitempty"`
+	MlsId      string `xml:",omitempty"`
+	ProviderId string
+}
+
+type zillowBasics struct {
+	PropertyType string
+	Title        string
+	Description  string `xml:",omitempty"`
+	Bedrooms     int    `xml:",omitempty"`
+	Bathrooms    int    `xml:",omitempty"`
+	LivingArea   int    `xml:",omitempty"`
+	LotSize      string `xml:",omitempty"`
+	YearBuilt    int    `xml:",omitempty"`
+}
+
+type zillowPicture struct {
+	PictureUrl string
+	Caption    string `xml:",omitempty"`
+}
+
+type zillowAgent struct {
+	FirstName    string `xml:",omitempty"`
+	LastName     string `xml:",omitempty"`
+	EmailAddress string `xml:",omitempty"`
+}
+
+var zillowPropertyTypes = map[string]string{
+	typeSingleFamily: "SingleFamily",
+	typeCondo:        "Condo",
+	typeTownhouse:    "Townhouse",
+	typeMultiFamily:  "MultiFamily",
+	typeLand:         "VacantLand",
+	typeManufactured: "Manufactured",
+}
+
+var zillowStatuses = map[string]string{"active": "Active", "pending": "Pending"}
+
+func encodeZillow(listing Listing) ([]byte, error) {
+	first, last, _ := strings.Cut(listing.AgentName, " ")
+	doc := zillowListing{
+		Location: zillowLocation{StreetAddress: listing.Address},
+		ListingDetails: zillowDetails{
+			Status:     zillowStatuses[listing.Status],
+			Price:      int64(listing.Price + 0.5),
+			ListingUrl: listing.URL,
+			MlsId:      listing.MLSNumber,
+			ProviderId: listing.ID,
+		},
+		BasicDetails: zillowBasics{
+			PropertyType: zillowPropertyTypes[normalizePropertyType(listing.PropertyType)],
+			Title:        listing.Title,
+			Description:  listing.Description,
+			Bedrooms:     listing.Bedrooms,
+			Bathrooms:    listing.Bathrooms,
+			LivingArea:   listing.LivingArea,
+			LotSize:      listing.LotSize,
+			YearBuilt:    listing.YearBuilt,
+		},
+		Agent: zillowAgent{FirstName: first, LastName: last, EmailAddress: listing.AgentEmail},
+	}
+	for _, photo := range listing.Photos {
+		doc.Pictures = append(doc.Pictures, zillowPicture{PictureUrl: photo.URL, Caption: photo.Caption})
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// Portal-neutral property types
+const (
+	typeSingleFamily = "single_family"
+	typeCondo        = "condo"
+	typeTownhouse    = "townhouse"
+	typeMultiFamily  = "multi_family"
+	typeLand         = "land"
+	typeManufactured = "manufactured"
+)
+
+// normalizePropertyType maps the free-text MLS property type to one the
+// portals know, defaulting to a single-family home
+func normalizePropertyType(raw string) string {
+	raw = strings.ToLower(raw)
+	switch {
+	case strings.Contains(raw, "condo"):
+		return typeCondo
+	case strings.Contains(raw, "town"):
+		return typeTownhouse
+	case strings.Contains(raw, "multi"), strings.Contains(raw, "duplex"):
+		return typeMultiFamily
+	case strings.Contains(raw, "land"), strings.Contains(raw, "lot"):
+		return typeLand
+	case strings.Contains(raw, "manufactured"), strings.Contains(raw, "mobile"):
+		return typeManufactured
+	default:
+		return typeSingleFamily
+	}
+}
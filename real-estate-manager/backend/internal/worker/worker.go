@@ -0,0 +1,160 @@
+// Package worker runs background tasks on a fixed number of goroutines fed
+// by a bounded queue, so bursts of CPU-heavy work such as image rendering
+// wait their turn instead of competing with request handling.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrQueueFull is returned when a task is submitted while the queue is
+	// at capacity
+	ErrQueueFull = errors.New("task queue is full")
+	// ErrStopped is returned for tasks submitted after Stop
+	ErrStopped = errors.New("worker pool is stopped")
+)
+
+// Task is a unit of background work
+type Task func(ctx context.Context) error
+
+type job struct {
+	run Task
+}
+
+// Stats is a snapshot of a pool's load
+type Stats struct {
+	Name     string `json:"name"`
+	Workers  int    `json:"workers"`
+	Capacity int    `json:"capacity"`
+	Queued   int    `json:"queued"`
+	Running  int64  `json:"running"`
+}
+
+// Pool runs tasks with at most a fixed number at once
+type Pool struct {
+	name    string
+	workers int
+	jobs    chan job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running atomic.Int64
+
+	mu      sync.RWMutex
+	stopped bool
+}
+
+// New starts a pool of workers goroutines taking tasks from a queue of
+// queueSize
+func New(name string, workers, queueSize int) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		name:    name,
+		workers: workers,
+		jobs:    make(chan job, queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// NewFromEnv starts a pool sized by <prefix>_WORKERS and
+// <prefix>_QUEUE_SIZE, falling back to the given defaults
+func NewFromEnv(name, prefix string, defaultWorkers, defaultQueueSize int) (*Pool, error) {
+	workers, err := envInt(prefix+"_WORKERS", defaultWorkers)
+	if err != nil {
+		return nil, err
+	}
+	queueSize, err := envInt(prefix+"_QUEUE_SIZE", defaultQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	return New(name, workers, queueSize), nil
+}
+
+func envInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive integer", key, value)
+	}
+	return n, nil
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		p.running.Add(1)
+		err := job.run(p.ctx)
+		p.running.Add(-1)
+		if err != nil {
+			log.Printf("%s task failed: %v", p.name, err)
+		}
+	}
+}
+
+// Submit queues task without waiting for it. It returns ErrQueueFull
+// rather than blocking when the queue is at capacity.
+func (p *Pool) Submit(task Task) error {
+	return p.enqueue(job{run: task})
+}
+
+func (p *Pool) enqueue(j job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return ErrStopped
+	}
+	select {
+	case p.jobs <- j:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Stats returns the pool's current load
+func (p *Pool) Stats() Stats {
+	return Stats{Name: p.name, Workers: p.workers, Capacity: cap(p.jobs), Queued: len(p.jobs), Running: p.running.Load()}
+}
+
+// Stop stops accepting tasks and waits for queued and running ones to
+// finish. Tasks still running when ctx is done are cancelled.
+func (p *Pool) Stop(ctx context.Context) {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+	close(p.jobs)
+	p.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		p.cancel()
+		<-finished
+	}
+	p.cancel()
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_LimitsConcurrency(t *testing.T) {
+	pool := New("test", 2, 10)
+	defer pool.Stop(context.Background())
+
+	var running, peak atomic.Int64
+	release := make(chan struct{})
+	done := make(chan error, 5)
+	finished := make(chan struct{}, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			done <- pool.Submit(func(ctx context.Context) error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				<-release
+				running.Add(-1)
+				finished <- struct{}{}
+				return nil
+			})
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for i := 0; i < 5; i++ {
+		<-finished
+	}
+	if peak.Load() != 2 {
+		t.Errorf("expected at most 2 tasks at once, got %d", peak.Load())
+	}
+}
+
+func TestPool_QueueFull(t *testing.T) {
+	pool := New("test", 1, 1)
+	defer pool.Stop(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	if err := pool.Submit(func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected the queue to take one task, got %v", err)
+	}
+	if err := pool.Submit(func(ctx context.Context) error { return nil }); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+	close(release)
+}
+
+func TestPool_StopDrainsQueue(t *testing.T) {
+	pool := New("test", 1, 5)
+	var ran atomic.Int64
+	for i := 0; i < 5; i++ {
+		pool.Submit(func(ctx context.Context) error {
+			ran.Add(1)
+			return nil
+		})
+	}
+	pool.Stop(context.Background())
+	if ran.Load() != 5 {
+		t.Errorf("expected queued tasks to run before Stop returns, ran %d", ran.Load())
+	}
+	if err := pool.Submit(func(ctx context.Context) error { return nil }); !errors.Is(err, ErrStopped) {
+		t.Errorf("expected ErrStopped after Stop, got %v", err)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("IMAGE_WORKERS", "3")
+	t.Setenv("IMAGE_QUEUE_SIZE", "")
+	pool, err := NewFromEnv("images", "IMAGE", 1, 8)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer pool.Stop(context.Background())
+	if stats := pool.Stats(); stats.Workers != 3 || stats.Capacity != 8 {
+		t.Errorf("expected 3 workers and a queue of 8, got %+v", stats)
+	}
+
+	t.Setenv("IMAGE_WORKERS", "zero")
+	if _, err := NewFromEnv("images", "IMAGE", 1, 8); err == nil {
+		t.Error("expected an invalid worker count to be rejected")
+	}
+}
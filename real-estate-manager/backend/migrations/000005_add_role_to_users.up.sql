@@ -0,0 +1,2 @@
+-- Add role column to users table
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    description VARCHAR(255) DEFAULT NULL,
+    updated_by VARCHAR(50) DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
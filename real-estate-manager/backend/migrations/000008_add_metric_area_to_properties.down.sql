@@ -0,0 +1,3 @@
+ALTER TABLE properties
+DROP COLUMN living_area_sqm,
+DROP COLUMN lot_area_sqm;
@@ -0,0 +1,6 @@
+-- Canonical metric measurements; square_feet and lot_size keep the raw MLS values
+ALTER TABLE properties
+ADD COLUMN living_area_sqm DECIMAL(12,2) DEFAULT NULL,
+ADD COLUMN lot_area_sqm DECIMAL(14,2) DEFAULT NULL;
+
+UPDATE properties SET living_area_sqm = square_feet * 0.09290304 WHERE square_feet IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE properties
+DROP FOREIGN KEY fk_properties_agent,
+DROP INDEX idx_agent_id,
+DROP INDEX idx_stale_at,
+DROP COLUMN agent_id,
+DROP COLUMN last_synced_at,
+DROP COLUMN stale_at;
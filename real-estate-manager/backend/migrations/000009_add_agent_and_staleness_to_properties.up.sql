@@ -0,0 +1,8 @@
+-- Assigned agent and staleness tracking for listings
+ALTER TABLE properties
+ADD COLUMN agent_id INT DEFAULT NULL,
+ADD COLUMN last_synced_at TIMESTAMP NULL DEFAULT NULL,
+ADD COLUMN stale_at TIMESTAMP NULL DEFAULT NULL,
+ADD INDEX idx_agent_id (agent_id),
+ADD INDEX idx_stale_at (stale_at),
+ADD CONSTRAINT fk_properties_agent FOREIGN KEY (agent_id) REFERENCES users(id) ON DELETE SET NULL;
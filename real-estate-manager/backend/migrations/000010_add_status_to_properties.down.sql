@@ -0,0 +1,3 @@
+ALTER TABLE properties
+DROP INDEX idx_status,
+DROP COLUMN status;
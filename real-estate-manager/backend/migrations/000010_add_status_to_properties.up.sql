@@ -0,0 +1,4 @@
+-- Listing status: active, pending, sold or withdrawn
+ALTER TABLE properties
+ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active',
+ADD INDEX idx_status (status);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS property_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    property_id INT NOT NULL,
+    snapshot JSON NOT NULL,
+    changed_by INT DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_property_revisions_property (property_id, created_at),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
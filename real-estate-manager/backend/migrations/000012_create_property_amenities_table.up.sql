@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS property_amenities (
+    property_id INT PRIMARY KEY,
+    has_pool BOOLEAN NOT NULL DEFAULT FALSE,
+    garage_spaces INT DEFAULT NULL,
+    hvac_type VARCHAR(20) DEFAULT NULL,
+    hoa_fee DECIMAL(10,2) DEFAULT NULL,
+    features JSON DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_amenities_pool (has_pool),
+    INDEX idx_amenities_hvac (hvac_type),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
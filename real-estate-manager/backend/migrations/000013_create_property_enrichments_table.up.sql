@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS property_enrichments (
+    property_id INT PRIMARY KEY,
+    latitude DECIMAL(10,7) DEFAULT NULL,
+    longitude DECIMAL(10,7) DEFAULT NULL,
+    school_district VARCHAR(255) DEFAULT NULL,
+    walk_score INT DEFAULT NULL,
+    neighborhood VARCHAR(255) DEFAULT NULL,
+    enriched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_enrichments_enriched_at (enriched_at),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
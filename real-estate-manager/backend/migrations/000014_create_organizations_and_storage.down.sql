@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS stored_files;
+
+ALTER TABLE users
+DROP FOREIGN KEY fk_users_organization,
+DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organizations;
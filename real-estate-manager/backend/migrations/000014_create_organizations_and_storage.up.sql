@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    storage_quota_mb INT DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users
+ADD COLUMN organization_id INT DEFAULT NULL,
+ADD CONSTRAINT fk_users_organization FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE SET NULL;
+
+-- Ledger of stored photos and documents used to compute storage usage
+CREATE TABLE IF NOT EXISTS stored_files (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT DEFAULT NULL,
+    organization_id INT DEFAULT NULL,
+    property_id INT DEFAULT NULL,
+    kind VARCHAR(20) NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_stored_files_user (user_id),
+    INDEX idx_stored_files_organization (organization_id),
+    INDEX idx_stored_files_property (property_id)
+);
@@ -0,0 +1,18 @@
+-- Direct-to-storage uploads issued a pre-signed URL, pending until confirmed
+CREATE TABLE IF NOT EXISTS uploads (
+    id CHAR(36) PRIMARY KEY,
+    user_id INT DEFAULT NULL,
+    organization_id INT DEFAULT NULL,
+    property_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    object_key VARCHAR(512) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    caption VARCHAR(255) NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    INDEX idx_uploads_user (user_id)
+);
@@ -0,0 +1,11 @@
+-- Role to permission mappings. organization_id 0 applies to every
+-- organization; other rows define or override a role for one brokerage.
+-- Built-in roles (admin, user, viewer) have defaults in code.
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL,
+    organization_id INT NOT NULL DEFAULT 0,
+    permissions JSON NOT NULL,
+    updated_by VARCHAR(50) DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (role, organization_id)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    actor_id INT DEFAULT NULL,
+    impersonator_id INT DEFAULT NULL,
+    target_type VARCHAR(50) DEFAULT NULL,
+    target_id VARCHAR(64) DEFAULT NULL,
+    details JSON DEFAULT NULL,
+    request_id VARCHAR(128) DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_log_actor (actor_id),
+    INDEX idx_audit_log_impersonator (impersonator_id),
+    INDEX idx_audit_log_created (created_at)
+);
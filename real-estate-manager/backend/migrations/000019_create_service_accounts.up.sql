@@ -0,0 +1,8 @@
+-- Service accounts are users reserved for automation; their tokens carry scopes
+CREATE TABLE IF NOT EXISTS service_accounts (
+    user_id INT PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_service_accounts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
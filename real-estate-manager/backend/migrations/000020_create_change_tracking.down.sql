@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS tombstones;
+
+ALTER TABLE properties
+DROP INDEX idx_properties_updated_at,
+DROP INDEX idx_properties_photos_updated_at,
+DROP COLUMN photos_updated_at;
@@ -0,0 +1,16 @@
+-- Change tracking for the incremental sync feed: when each property's photos
+-- last changed, and tombstones for deleted entities
+ALTER TABLE properties
+ADD COLUMN photos_updated_at TIMESTAMP NULL DEFAULT NULL,
+ADD INDEX idx_properties_updated_at (updated_at),
+ADD INDEX idx_properties_photos_updated_at (photos_updated_at);
+
+UPDATE properties SET photos_updated_at = updated_at;
+
+CREATE TABLE IF NOT EXISTS tombstones (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    deleted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_tombstones_deleted (deleted_at, id)
+);
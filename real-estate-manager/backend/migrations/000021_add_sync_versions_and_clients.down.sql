@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sync_clients;
+
+ALTER TABLE properties DROP COLUMN version;
@@ -0,0 +1,12 @@
+-- Optimistic concurrency for offline clients: every write bumps a
+-- property's version, and each client's acknowledged sync token is stored
+ALTER TABLE properties ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS sync_clients (
+    user_id INT NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    sync_token VARCHAR(128) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, client_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
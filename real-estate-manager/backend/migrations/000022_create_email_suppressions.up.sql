@@ -0,0 +1,6 @@
+-- Addresses that must not be emailed, e.g. after a hard bounce
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    email VARCHAR(255) PRIMARY KEY,
+    reason VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
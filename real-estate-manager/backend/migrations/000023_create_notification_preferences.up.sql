@@ -0,0 +1,12 @@
+-- Per-user opt-in and quiet hours for SMS/WhatsApp notifications
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INT PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    sms_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    channel VARCHAR(10) NOT NULL DEFAULT 'sms',
+    quiet_start CHAR(5) NOT NULL DEFAULT '',
+    quiet_end CHAR(5) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
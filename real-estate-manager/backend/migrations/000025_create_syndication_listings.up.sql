@@ -0,0 +1,13 @@
+-- Per-portal syndication state of listings. property_id has no foreign key
+-- so rows outlive a deleted property until it is removed from the portals.
+CREATE TABLE IF NOT EXISTS syndication_listings (
+    property_id INT NOT NULL,
+    portal VARCHAR(32) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    last_error VARCHAR(512) NOT NULL DEFAULT '',
+    synced_at TIMESTAMP NULL DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (property_id, portal),
+    INDEX idx_syndication_enabled (enabled, property_id)
+);
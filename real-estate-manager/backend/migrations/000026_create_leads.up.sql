@@ -0,0 +1,19 @@
+-- Leads received through the integration webhooks. (source, external_id) is
+-- unique so a redelivered webhook is stored once.
+CREATE TABLE IF NOT EXISTS leads (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(32) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    property_id INT NULL,
+    assigned_to INT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'new',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_leads_source (source, external_id),
+    INDEX idx_leads_assigned (assigned_to, id),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE SET NULL,
+    FOREIGN KEY (assigned_to) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,11 @@
+-- Rules assigning leads that do not come with a listing agent, checked in
+-- priority order. Empty source and location match any lead.
+CREATE TABLE IF NOT EXISTS lead_routing_rules (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    priority INT NOT NULL DEFAULT 0,
+    source VARCHAR(32) NOT NULL DEFAULT '',
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    agent_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (agent_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+-- Agents' connected Google and Outlook calendars, with their OAuth tokens
+CREATE TABLE IF NOT EXISTS calendar_connections (
+    user_id INT NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    access_token TEXT NOT NULL,
+    refresh_token TEXT NOT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, provider),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
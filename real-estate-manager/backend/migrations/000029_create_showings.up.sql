@@ -0,0 +1,18 @@
+-- Private showings and open houses of a listing, run by an agent
+CREATE TABLE IF NOT EXISTS showings (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    property_id INT NOT NULL,
+    agent_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'showing',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    contact_name VARCHAR(255) NOT NULL DEFAULT '',
+    contact_email VARCHAR(255) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_showings_property (property_id, starts_at),
+    INDEX idx_showings_agent (agent_id, status, starts_at),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    FOREIGN KEY (agent_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+-- Sync state of each lead and contact exported to a CRM. record_key is the
+-- lead ID, or the contact's email address (or phone number when it has
+-- none).
+CREATE TABLE IF NOT EXISTS crm_sync_records (
+    crm VARCHAR(32) NOT NULL,
+    record_type VARCHAR(20) NOT NULL,
+    record_key VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    last_error VARCHAR(512) NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    synced_at TIMESTAMP NULL DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (crm, record_type, record_key),
+    INDEX idx_crm_sync_status (crm, status)
+);
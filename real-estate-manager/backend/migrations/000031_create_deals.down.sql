@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS deal_commission_splits;
+DROP TABLE IF EXISTS deals;
@@ -0,0 +1,30 @@
+-- Deals in the sales pipeline. property_id has no foreign key so closed
+-- deals stay in the revenue reports after their listing is deleted.
+CREATE TABLE IF NOT EXISTS deals (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    property_id INT NOT NULL,
+    stage VARCHAR(20) NOT NULL DEFAULT 'offer',
+    price DECIMAL(14,2) NOT NULL,
+    commission_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    expected_close_date DATE NULL DEFAULT NULL,
+    closed_at TIMESTAMP NULL DEFAULT NULL,
+    notes TEXT NOT NULL,
+    created_by INT NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_deals_property (property_id),
+    INDEX idx_deals_stage (stage, expected_close_date),
+    INDEX idx_deals_closed (closed_at)
+);
+
+-- How a deal's commission is shared between agents, in percent
+CREATE TABLE IF NOT EXISTS deal_commission_splits (
+    deal_id INT NOT NULL,
+    agent_id INT NOT NULL,
+    role VARCHAR(32) NOT NULL DEFAULT '',
+    percent DECIMAL(5,2) NOT NULL,
+    PRIMARY KEY (deal_id, agent_id),
+    INDEX idx_deal_splits_agent (agent_id),
+    FOREIGN KEY (deal_id) REFERENCES deals(id) ON DELETE CASCADE,
+    FOREIGN KEY (agent_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Monthly market summaries by city and by ZIP code, rebuilt from listings
+-- and closed deals by the market-reports task
+CREATE TABLE IF NOT EXISTS market_stats (
+    area_type VARCHAR(10) NOT NULL,
+    area VARCHAR(120) NOT NULL,
+    month CHAR(7) NOT NULL,
+    new_listings INT NOT NULL DEFAULT 0,
+    active_listings INT NOT NULL DEFAULT 0,
+    sales INT NOT NULL DEFAULT 0,
+    median_list_price DECIMAL(14,2) NULL DEFAULT NULL,
+    median_sale_price DECIMAL(14,2) NULL DEFAULT NULL,
+    avg_sale_price_per_sqft DECIMAL(10,2) NULL DEFAULT NULL,
+    refreshed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (area_type, area, month)
+);
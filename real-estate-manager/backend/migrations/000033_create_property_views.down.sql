@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS property_view_stats;
+DROP TABLE IF EXISTS recently_viewed_properties;
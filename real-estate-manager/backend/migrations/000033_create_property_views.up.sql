@@ -0,0 +1,19 @@
+-- The properties each user viewed last, trimmed to the newest few per user
+CREATE TABLE IF NOT EXISTS recently_viewed_properties (
+    user_id INT NOT NULL,
+    property_id INT NOT NULL,
+    view_count INT NOT NULL DEFAULT 1,
+    viewed_at TIMESTAMP(3) NOT NULL,
+    PRIMARY KEY (user_id, property_id),
+    INDEX idx_recently_viewed_user (user_id, viewed_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
+
+-- View counters per property for analytics
+CREATE TABLE IF NOT EXISTS property_view_stats (
+    property_id INT PRIMARY KEY,
+    views BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
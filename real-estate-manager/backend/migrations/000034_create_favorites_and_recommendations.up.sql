@@ -0,0 +1,34 @@
+-- Properties users saved as favorites
+CREATE TABLE IF NOT EXISTS user_favorites (
+    user_id INT NOT NULL,
+    property_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, property_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
+
+-- Search criteria users saved, as JSON
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    criteria JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_saved_searches_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Each user's recommended properties, recomputed by the recommendations
+-- task
+CREATE TABLE IF NOT EXISTS user_recommendations (
+    user_id INT NOT NULL,
+    property_id INT NOT NULL,
+    score DECIMAL(6,4) NOT NULL,
+    reasons JSON NOT NULL,
+    computed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, property_id),
+    INDEX idx_user_recommendations_score (user_id, score),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+-- SimplyRETS import jobs with the label, description and metadata they
+-- were started with and how they finished
+CREATE TABLE IF NOT EXISTS processing_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    label VARCHAR(64) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    metadata JSON NULL,
+    job_limit INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    total_properties INT NOT NULL DEFAULT 0,
+    processed_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    error_message TEXT NULL,
+    created_by INT NULL,
+    started_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP NULL,
+    INDEX idx_processing_jobs_started (started_at),
+    INDEX idx_processing_jobs_label (label, started_at),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
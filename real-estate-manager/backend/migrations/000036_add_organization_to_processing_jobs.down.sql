@@ -0,0 +1,2 @@
+-- Remove the organization from processing jobs
+ALTER TABLE processing_jobs DROP COLUMN organization_id;
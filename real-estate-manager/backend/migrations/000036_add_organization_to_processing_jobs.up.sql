@@ -0,0 +1,3 @@
+-- Record the organization of whoever started a job, which scopes who may
+-- see it
+ALTER TABLE processing_jobs ADD COLUMN organization_id INT NULL AFTER created_by;
@@ -0,0 +1,4 @@
+-- Restore the plaintext phone column sizes. Encrypted values must be
+-- decrypted first or they no longer fit.
+ALTER TABLE notification_preferences MODIFY phone VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE leads MODIFY phone VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Phone numbers may be stored encrypted, which takes more room than the
+-- plaintext. Calendar tokens are TEXT already.
+ALTER TABLE leads MODIFY phone VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE notification_preferences MODIFY phone VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Remove the organization from properties
+ALTER TABLE properties
+DROP FOREIGN KEY fk_properties_organization,
+DROP INDEX idx_properties_organization,
+DROP COLUMN organization_id;
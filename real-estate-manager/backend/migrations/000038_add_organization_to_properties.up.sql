@@ -0,0 +1,5 @@
+-- Record the organization owning each property; NULL is shared
+ALTER TABLE properties
+ADD COLUMN organization_id INT DEFAULT NULL,
+ADD INDEX idx_properties_organization (organization_id),
+ADD CONSTRAINT fk_properties_organization FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE SET NULL;
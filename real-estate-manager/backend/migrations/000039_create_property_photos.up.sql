@@ -0,0 +1,22 @@
+-- Photos of a property, one row each in display order. properties.photos
+-- keeps a JSON copy of the same list for reads.
+CREATE TABLE IF NOT EXISTS property_photos (
+    property_id INT NOT NULL,
+    position INT NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    local_url VARCHAR(1024) NOT NULL DEFAULT '',
+    caption VARCHAR(500) NOT NULL DEFAULT '',
+    PRIMARY KEY (property_id, position),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
+
+INSERT INTO property_photos (property_id, position, url, local_url, caption)
+SELECT p.id, photo.position - 1, photo.url, COALESCE(photo.local_url, ''), COALESCE(photo.caption, '')
+FROM properties p,
+    JSON_TABLE(p.photos, '$[*]' COLUMNS (
+        position FOR ORDINALITY,
+        url VARCHAR(2048) PATH '$.url',
+        local_url VARCHAR(1024) PATH '$.local_url',
+        caption VARCHAR(500) PATH '$.caption'
+    )) AS photo
+WHERE p.photos IS NOT NULL AND photo.url IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Remove the photo manifest and the skipped photo count of jobs
+ALTER TABLE processing_jobs DROP COLUMN photos_skipped;
+
+DROP TABLE IF EXISTS photo_manifest;
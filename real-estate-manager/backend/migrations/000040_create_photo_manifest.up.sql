@@ -0,0 +1,14 @@
+-- Photos downloaded by imports, keyed by listing and source URL, with the
+-- ETag they were served with so later syncs only download changed photos
+CREATE TABLE IF NOT EXISTS photo_manifest (
+    listing_id VARCHAR(255) NOT NULL,
+    url_hash CHAR(64) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    etag VARCHAR(255) NOT NULL,
+    local_url VARCHAR(1024) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (listing_id, url_hash)
+);
+
+ALTER TABLE processing_jobs ADD COLUMN photos_skipped INT NOT NULL DEFAULT 0 AFTER failed_count;
@@ -0,0 +1,2 @@
+-- Remove the lazy photo flag from processing jobs
+ALTER TABLE processing_jobs DROP COLUMN lazy_photos;
@@ -0,0 +1,2 @@
+-- Whether a job saved listings first and downloaded their photos afterwards
+ALTER TABLE processing_jobs ADD COLUMN lazy_photos BOOLEAN NOT NULL DEFAULT FALSE AFTER metadata;
@@ -0,0 +1,16 @@
+-- Virus scan verdict of every uploaded photo and document. Infected files
+-- are moved to the quarantine directory, recorded in quarantine_path.
+CREATE TABLE IF NOT EXISTS file_scans (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT DEFAULT NULL,
+    organization_id INT DEFAULT NULL,
+    property_id INT DEFAULT NULL,
+    kind VARCHAR(20) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    signature VARCHAR(255) NOT NULL DEFAULT '',
+    quarantine_path VARCHAR(1024) NOT NULL DEFAULT '',
+    scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_file_scans_status (status, scanned_at)
+);
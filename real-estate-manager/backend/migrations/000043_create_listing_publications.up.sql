@@ -0,0 +1,19 @@
+-- Publish state of each listing, separate from its lifecycle status. Agents
+-- submit listings for review and admins approve or reject them; only
+-- approved listings appear in public feeds. Listings without a row are
+-- drafts.
+CREATE TABLE IF NOT EXISTS listing_publications (
+    property_id INT PRIMARY KEY,
+    state VARCHAR(20) NOT NULL,
+    submitted_by INT DEFAULT NULL,
+    submitted_at TIMESTAMP NULL DEFAULT NULL,
+    reviewed_by INT DEFAULT NULL,
+    reviewed_at TIMESTAMP NULL DEFAULT NULL,
+    review_comment VARCHAR(1000) NOT NULL DEFAULT '',
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    INDEX idx_listing_publications_state (state, submitted_at)
+);
+
+-- Listings public before the review workflow stay public
+INSERT INTO listing_publications (property_id, state)
+SELECT id, 'approved' FROM properties;
@@ -0,0 +1,4 @@
+-- Remove the listings processed by each job and the skipped listing count
+ALTER TABLE processing_jobs DROP COLUMN listings_skipped;
+
+DROP TABLE IF EXISTS processing_job_listings;
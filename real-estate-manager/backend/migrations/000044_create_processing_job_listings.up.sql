@@ -0,0 +1,10 @@
+-- Listings each import job has saved, so a resumed job skips them
+CREATE TABLE IF NOT EXISTS processing_job_listings (
+    job_id VARCHAR(36) NOT NULL,
+    listing_id VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_id, listing_id),
+    FOREIGN KEY (job_id) REFERENCES processing_jobs(id) ON DELETE CASCADE
+);
+
+ALTER TABLE processing_jobs ADD COLUMN listings_skipped INT NOT NULL DEFAULT 0 AFTER photos_skipped;
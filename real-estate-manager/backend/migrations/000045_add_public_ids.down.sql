@@ -0,0 +1,10 @@
+-- Remove the public UUIDs of properties and users
+ALTER TABLE syndication_listings DROP COLUMN listing_id;
+
+ALTER TABLE users
+DROP INDEX idx_users_public_id,
+DROP COLUMN public_id;
+
+ALTER TABLE properties
+DROP INDEX idx_properties_public_id,
+DROP COLUMN public_id;
@@ -0,0 +1,19 @@
+-- Public UUIDs for properties and users, so public feeds and webhooks do not
+-- expose the guessable auto-increment IDs. Existing rows get one each.
+ALTER TABLE properties ADD COLUMN public_id CHAR(36) NULL AFTER id;
+UPDATE properties SET public_id = UUID() WHERE public_id IS NULL;
+ALTER TABLE properties
+MODIFY public_id CHAR(36) NOT NULL DEFAULT (UUID()),
+ADD UNIQUE INDEX idx_properties_public_id (public_id);
+
+ALTER TABLE users ADD COLUMN public_id CHAR(36) NULL AFTER id;
+UPDATE users SET public_id = UUID() WHERE public_id IS NULL;
+ALTER TABLE users
+MODIFY public_id CHAR(36) NOT NULL DEFAULT (UUID()),
+ADD UNIQUE INDEX idx_users_public_id (public_id);
+
+-- Listings already on portals were pushed under their numeric ID. Marking
+-- enabled ones unsynced has the scheduled sync move them to the public ID.
+ALTER TABLE syndication_listings ADD COLUMN listing_id VARCHAR(36) NOT NULL DEFAULT '' AFTER portal;
+UPDATE syndication_listings SET listing_id = CAST(property_id AS CHAR) WHERE status <> 'pending';
+UPDATE syndication_listings SET synced_at = NULL WHERE enabled;
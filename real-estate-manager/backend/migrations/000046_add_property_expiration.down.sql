@@ -0,0 +1,4 @@
+ALTER TABLE properties
+DROP INDEX idx_expires_at,
+DROP COLUMN expires_at,
+DROP COLUMN expiry_notified_at;
@@ -0,0 +1,6 @@
+-- Listing expiration. expiry_notified_at records when the agent was warned
+-- that the current expires_at is near, and is cleared when it changes.
+ALTER TABLE properties
+ADD COLUMN expires_at TIMESTAMP NULL DEFAULT NULL AFTER stale_at,
+ADD COLUMN expiry_notified_at TIMESTAMP NULL DEFAULT NULL AFTER expires_at,
+ADD INDEX idx_expires_at (expires_at);
@@ -0,0 +1,4 @@
+ALTER TABLE properties
+DROP COLUMN annual_tax,
+DROP COLUMN parking_spaces,
+DROP COLUMN parking_description;
@@ -0,0 +1,6 @@
+-- Annual property tax and parking, mapped from the MLS tax and parking
+-- sections on import. HOA fees are kept with the amenities.
+ALTER TABLE properties
+ADD COLUMN annual_tax DECIMAL(12,2) NULL DEFAULT NULL AFTER year_built,
+ADD COLUMN parking_spaces INT NULL DEFAULT NULL AFTER annual_tax,
+ADD COLUMN parking_description VARCHAR(255) NULL DEFAULT NULL AFTER parking_spaces;
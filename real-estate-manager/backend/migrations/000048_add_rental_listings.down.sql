@@ -0,0 +1,7 @@
+ALTER TABLE properties
+DROP INDEX idx_listing_type,
+DROP COLUMN listing_type,
+DROP COLUMN monthly_rent,
+DROP COLUMN security_deposit,
+DROP COLUMN lease_term_months,
+DROP COLUMN available_from;
@@ -0,0 +1,9 @@
+-- Rentals share the properties table with sales. They are priced by
+-- monthly_rent; price only applies to sales.
+ALTER TABLE properties
+ADD COLUMN listing_type VARCHAR(10) NOT NULL DEFAULT 'sale' AFTER status,
+ADD COLUMN monthly_rent DECIMAL(12,2) NULL DEFAULT NULL AFTER parking_description,
+ADD COLUMN security_deposit DECIMAL(12,2) NULL DEFAULT NULL AFTER monthly_rent,
+ADD COLUMN lease_term_months INT NULL DEFAULT NULL AFTER security_deposit,
+ADD COLUMN available_from DATE NULL DEFAULT NULL AFTER lease_term_months,
+ADD INDEX idx_listing_type (listing_type);
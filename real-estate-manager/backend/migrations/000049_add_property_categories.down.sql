@@ -0,0 +1,7 @@
+ALTER TABLE properties
+DROP INDEX idx_category,
+DROP COLUMN category,
+DROP COLUMN zoning,
+DROP COLUMN cap_rate,
+DROP COLUMN noi,
+DROP COLUMN unit_count;
@@ -0,0 +1,9 @@
+-- Listing categories. Commercial listings carry their zoning and income
+-- figures; the columns stay NULL for other categories.
+ALTER TABLE properties
+ADD COLUMN category VARCHAR(20) NOT NULL DEFAULT 'residential' AFTER listing_type,
+ADD COLUMN zoning VARCHAR(50) NULL DEFAULT NULL AFTER available_from,
+ADD COLUMN cap_rate DECIMAL(5,2) NULL DEFAULT NULL AFTER zoning,
+ADD COLUMN noi DECIMAL(14,2) NULL DEFAULT NULL AFTER cap_rate,
+ADD COLUMN unit_count INT NULL DEFAULT NULL AFTER noi,
+ADD INDEX idx_category (category);
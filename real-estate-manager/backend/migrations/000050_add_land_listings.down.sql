@@ -0,0 +1,3 @@
+ALTER TABLE properties
+DROP COLUMN acreage,
+DROP COLUMN utilities;
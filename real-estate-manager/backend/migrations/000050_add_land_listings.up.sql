@@ -0,0 +1,5 @@
+-- Land listings have no structure. acreage is the parcel size as listed
+-- and utilities the JSON array of utilities available on the parcel.
+ALTER TABLE properties
+ADD COLUMN acreage DECIMAL(12,4) NULL DEFAULT NULL AFTER unit_count,
+ADD COLUMN utilities JSON NULL DEFAULT NULL AFTER acreage;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS inspection_repairs;
+DROP TABLE IF EXISTS inspection_reports;
+DROP TABLE IF EXISTS inspections;
@@ -0,0 +1,46 @@
+-- Inspections of a property, such as the buyer's home inspection
+CREATE TABLE IF NOT EXISTS inspections (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    property_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'general',
+    inspector VARCHAR(255) NOT NULL DEFAULT '',
+    scheduled_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    notes TEXT NOT NULL,
+    created_by INT NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_inspections_property (property_id, scheduled_at),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
+
+-- Report documents attached to an inspection, uploaded as documents of
+-- the property
+CREATE TABLE IF NOT EXISTS inspection_reports (
+    inspection_id INT NOT NULL,
+    upload_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (inspection_id, upload_id),
+    FOREIGN KEY (inspection_id) REFERENCES inspections(id) ON DELETE CASCADE,
+    FOREIGN KEY (upload_id) REFERENCES uploads(id) ON DELETE CASCADE
+);
+
+-- Repairs an inspection called for. property_id is copied from the
+-- inspection so a property's punch list is one indexed lookup.
+CREATE TABLE IF NOT EXISTS inspection_repairs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    inspection_id INT NOT NULL,
+    property_id INT NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    assignee_id INT NULL DEFAULT NULL,
+    due_date DATE NULL DEFAULT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_inspection_repairs_property (property_id, status),
+    INDEX idx_inspection_repairs_assignee (assignee_id, status),
+    FOREIGN KEY (inspection_id) REFERENCES inspections(id) ON DELETE CASCADE,
+    FOREIGN KEY (assignee_id) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,20 @@
+-- Offers made on a listing. buyer_phone may be stored encrypted.
+CREATE TABLE IF NOT EXISTS offers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    property_id INT NOT NULL,
+    amount DECIMAL(14,2) NOT NULL,
+    counter_amount DECIMAL(14,2) NULL DEFAULT NULL,
+    buyer_name VARCHAR(255) NOT NULL,
+    buyer_email VARCHAR(255) NOT NULL DEFAULT '',
+    buyer_phone VARCHAR(255) NOT NULL DEFAULT '',
+    contingencies JSON NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted',
+    notes TEXT NOT NULL,
+    created_by INT NULL DEFAULT NULL,
+    responded_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_offers_property (property_id, status),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+-- Tokens revoked before they expire, by logging out or by an admin; only a
+-- hash of the token is stored. Rows are pruned once the token has expired.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id INT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_by INT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_revoked_tokens_expires_at (expires_at)
+);
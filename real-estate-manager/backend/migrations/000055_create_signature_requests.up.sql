@@ -0,0 +1,24 @@
+-- Property documents sent out for e-signature. Status follows the
+-- provider's envelope through its callbacks.
+CREATE TABLE IF NOT EXISTS signature_requests (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    property_id INT NOT NULL,
+    offer_id INT NULL DEFAULT NULL,
+    kind VARCHAR(20) NOT NULL,
+    upload_id CHAR(36) NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    envelope_id VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    signers JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'sent',
+    sent_by INT NULL DEFAULT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_signature_requests_envelope (provider, envelope_id),
+    INDEX idx_signature_requests_property (property_id, created_at),
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE SET NULL,
+    FOREIGN KEY (upload_id) REFERENCES uploads(id) ON DELETE CASCADE
+);
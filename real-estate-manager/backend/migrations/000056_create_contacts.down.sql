@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contact_links;
+DROP TABLE IF EXISTS contacts;
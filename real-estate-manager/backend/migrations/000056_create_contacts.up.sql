@@ -0,0 +1,28 @@
+-- The contact book: buyers, sellers and vendors agents work with, linked
+-- to the leads, showings and deals they are involved in. phone may be
+-- stored encrypted.
+CREATE TABLE IF NOT EXISTS contacts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    organization_id INT NULL DEFAULT NULL,
+    kind VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(255) NOT NULL DEFAULT '',
+    company VARCHAR(255) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL,
+    tags JSON NULL,
+    created_by INT NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_contacts_organization (organization_id, kind, name)
+);
+
+CREATE TABLE IF NOT EXISTS contact_links (
+    contact_id INT NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contact_id, entity_type, entity_id),
+    INDEX idx_contact_links_entity (entity_type, entity_id),
+    FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+-- The activity feed: domain events recorded with the user who caused them
+-- and the organization they concern
+CREATE TABLE IF NOT EXISTS activity_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    event_id CHAR(36) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    subject VARCHAR(100) NOT NULL,
+    actor_id INT NULL DEFAULT NULL,
+    organization_id INT NULL DEFAULT NULL,
+    summary VARCHAR(255) NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    UNIQUE KEY uq_activity_events_event (event_id),
+    INDEX idx_activity_events_actor (actor_id, id),
+    INDEX idx_activity_events_organization (organization_id, id),
+    INDEX idx_activity_events_occurred (occurred_at)
+);
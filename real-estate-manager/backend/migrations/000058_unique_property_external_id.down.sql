@@ -0,0 +1,3 @@
+-- Copies unlinked by the up migration stay unlinked
+ALTER TABLE properties
+DROP INDEX uq_properties_external_id;
@@ -0,0 +1,17 @@
+-- An imported listing is stored once per organization (shared listings
+-- count as organization 0), so re-running an import updates it. Copies left
+-- by earlier imports keep their data but only the newest stays linked to
+-- the feed listing.
+UPDATE properties p
+JOIN (
+    SELECT IFNULL(organization_id, 0) AS organization_key, external_id, MAX(id) AS keep_id
+    FROM properties
+    WHERE external_id IS NOT NULL
+    GROUP BY IFNULL(organization_id, 0), external_id
+    HAVING COUNT(*) > 1
+) duplicates ON IFNULL(p.organization_id, 0) = duplicates.organization_key AND p.external_id = duplicates.external_id
+SET p.external_id = NULL
+WHERE p.id <> duplicates.keep_id;
+
+ALTER TABLE properties
+ADD UNIQUE INDEX uq_properties_external_id ((IFNULL(organization_id, 0)), external_id);
@@ -0,0 +1,9 @@
+-- Reports of the data-quality job: how many listings each check found,
+-- with a sample of them, and whether any check crossed its threshold
+CREATE TABLE IF NOT EXISTS data_quality_reports (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    checks JSON NOT NULL,
+    exceeded BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_data_quality_reports_created (created_at)
+);
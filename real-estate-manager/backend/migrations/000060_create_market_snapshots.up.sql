@@ -0,0 +1,12 @@
+-- Nightly inventory by city and status, kept as a time series by the
+-- market-snapshot task
+CREATE TABLE IF NOT EXISTS market_snapshots (
+    snapshot_date DATE NOT NULL,
+    city VARCHAR(120) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    listings INT NOT NULL DEFAULT 0,
+    median_price DECIMAL(14,2) NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_date, city, status),
+    INDEX idx_market_snapshots_city (city, snapshot_date)
+);
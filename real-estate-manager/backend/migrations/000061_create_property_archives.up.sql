@@ -0,0 +1,22 @@
+-- Sold and withdrawn listings moved out of properties by the
+-- listing-archive task. The full listing and its photos are in the
+-- compressed archive at archive_path; the columns here are enough to find
+-- and list it.
+CREATE TABLE IF NOT EXISTS property_archives (
+    property_id INT NOT NULL PRIMARY KEY,
+    public_id CHAR(36) NOT NULL,
+    organization_id INT NULL DEFAULT NULL,
+    name VARCHAR(255) NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    listing_type VARCHAR(10) NOT NULL,
+    price DECIMAL(10,2) NOT NULL DEFAULT 0,
+    photo_count INT NOT NULL DEFAULT 0,
+    archive_path VARCHAR(255) NOT NULL,
+    archive_bytes BIGINT NOT NULL DEFAULT 0,
+    listed_at TIMESTAMP NOT NULL,
+    last_updated_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_property_archives_public_id (public_id),
+    INDEX idx_property_archives_organization (organization_id, archived_at)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE property_photos
+DROP COLUMN thumbnail_url,
+DROP COLUMN medium_url;
@@ -0,0 +1,6 @@
+-- Smaller copies generated next to downloaded photos for lists and
+-- galleries, served under /images like the originals. Empty when none
+-- could be generated.
+ALTER TABLE property_photos
+ADD COLUMN thumbnail_url VARCHAR(1024) NOT NULL DEFAULT '' AFTER local_url,
+ADD COLUMN medium_url VARCHAR(1024) NOT NULL DEFAULT '' AFTER thumbnail_url;
@@ -0,0 +1,23 @@
+-- Resumable uploads: the client sends a large file in chunks, resuming
+-- from received_bytes after a dropped connection, then completes the
+-- session. Chunks are written to a file in the sessions directory until
+-- then; sessions past expires_at are removed with their file.
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id CHAR(36) PRIMARY KEY,
+    user_id INT DEFAULT NULL,
+    organization_id INT DEFAULT NULL,
+    property_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    caption VARCHAR(255) NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL,
+    received_bytes BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    url VARCHAR(2048) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    INDEX idx_upload_sessions_expires (expires_at)
+);
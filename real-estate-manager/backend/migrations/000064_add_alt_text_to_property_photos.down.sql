@@ -0,0 +1,2 @@
+ALTER TABLE property_photos
+DROP COLUMN alt_text;
@@ -0,0 +1,4 @@
+-- Text screen readers announce for a photo. Listings saved before get a
+-- description of the listing the next time they are saved or imported.
+ALTER TABLE property_photos
+ADD COLUMN alt_text VARCHAR(500) NOT NULL DEFAULT '' AFTER caption;
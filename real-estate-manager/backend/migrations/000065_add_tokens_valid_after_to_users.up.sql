@@ -0,0 +1,4 @@
+-- Tokens issued to the user up to this time are refused, e.g. after a
+-- password reset; NULL when none were revoked this way
+ALTER TABLE users
+ADD COLUMN tokens_valid_after TIMESTAMP NULL DEFAULT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE processing_jobs
+DROP INDEX idx_processing_jobs_status,
+DROP COLUMN heartbeat_at;
@@ -0,0 +1,5 @@
+-- Running jobs record a heartbeat, so an import started on any instance
+-- keeps others from starting one until it finishes or its instance dies
+ALTER TABLE processing_jobs
+ADD COLUMN heartbeat_at TIMESTAMP NULL AFTER started_at,
+ADD INDEX idx_processing_jobs_status (status, heartbeat_at);
@@ -0,0 +1,166 @@
+package awsauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	algorithm    = "AWS4-HMAC-SHA256"
+	timeFormat   = "20060102T150405Z"
+	dateFormat   = "20060102"
+	emptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Credentials holds the static AWS credentials used for request signing
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// SignRequest adds AWS Signature Version 4 headers to req for the given
+// region and service. body must be the exact payload that will be sent.
+func SignRequest(req *http.Request, body []byte, creds Credentials, region, service string, now time.Time) {
+	SignPayloadHash(req, hashHex(body), creds, region, service, now)
+}
+
+// SignPayloadHash is SignRequest for a body the caller hashed, such as a
+// file streamed from disk; payloadHash is its hex-encoded SHA-256
+func SignPayloadHash(req *http.Request, payloadHash string, creds Credentials, region, service string, now time.Time) {
+	now = now.UTC()
+
+	req.Header.Set("X-Amz-Date", now.Format(timeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+
+	headerNames, canonicalHeaders := canonicalizeHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		headerNames,
+		payloadHash,
+	}, "\n")
+
+	scope := credentialScope(now, region, service)
+	signature := sign(creds.SecretAccessKey, now, region, service, stringToSign(now, scope, canonicalRequest))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, creds.AccessKeyID, scope, headerNames, signature))
+}
+
+// PresignURL returns a query-string signed URL for method on rawURL that is
+// valid for expires. Only the host header is signed, so clients may send any
+// body (used for direct-to-bucket uploads).
+func PresignURL(method, rawURL string, creds Credentials, region, service string, expires time.Duration, now time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	now = now.UTC()
+	scope := credentialScope(now, region, service)
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", algorithm)
+	query.Set("X-Amz-Credential", creds.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(timeFormat))
+	query.Set("X-Amz-Expires", fmt.Sprintf("%d", int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if creds.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalPath(u),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	signature := sign(creds.SecretAccessKey, now, region, service, stringToSign(now, scope, canonicalRequest))
+	query.Set("X-Amz-Signature", signature)
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
+
+func canonicalPath(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// canonicalQuery encodes query parameters the way SigV4 expects (%20, not +)
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func canonicalizeHeaders(req *http.Request) (string, string) {
+	headers := map[string]string{"host": req.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "authorization" || lower == "user-agent" {
+			continue
+		}
+		headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func credentialScope(now time.Time, region, service string) string {
+	return fmt.Sprintf("%s/%s/%s/aws4_request", now.Format(dateFormat), region, service)
+}
+
+func stringToSign(now time.Time, scope, canonicalRequest string) string {
+	return strings.Join([]string{algorithm, now.Format(timeFormat), scope, hashHex([]byte(canonicalRequest))}, "\n")
+}
+
+func sign(secret string, now time.Time, region, service, toSign string) string {
+	key := hmacSHA256([]byte("AWS4"+secret), now.Format(dateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	if len(data) == 0 {
+		return emptyPayload
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
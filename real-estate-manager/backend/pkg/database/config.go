@@ -1,6 +1,11 @@
 package database
 
-import "os"
+import (
+    "context"
+    "os"
+
+    "real-estate-manager/backend/pkg/secrets"
+)
 
 func NewConfigFromEnv() Config {
     return Config{
@@ -12,9 +17,30 @@ func NewConfigFromEnv() Config {
     }
 }
 
+// NewConfigFromProvider resolves the database credentials through a secrets
+// provider. The provider is kept on the config so new connections re-read
+// DB_PASSWORD and pick up rotated passwords without a restart.
+func NewConfigFromProvider(ctx context.Context, provider secrets.Provider) (Config, error) {
+    config := NewConfigFromEnv()
+
+    user, err := secrets.GetOrDefault(ctx, provider, "DB_USER", config.User)
+    if err != nil {
+        return Config{}, err
+    }
+    password, err := secrets.GetOrDefault(ctx, provider, "DB_PASSWORD", config.Password)
+    if err != nil {
+        return Config{}, err
+    }
+
+    config.User = user
+    config.Password = password
+    config.Secrets = provider
+    return config, nil
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
     if value := os.Getenv(key); value != "" {
         return value
     }
     return defaultValue
-}
\ No newline at end of file
+}
@@ -3,6 +3,9 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
@@ -30,4 +33,48 @@ func RunMigrations(db *sql.DB, migrationsPath string) error {
     }
 
     return nil
-}
\ No newline at end of file
+}
+// CheckMigrations returns an error unless the database is at the latest
+// migration in migrationsPath and the last migration did not fail halfway
+func CheckMigrations(db *sql.DB, migrationsPath string) error {
+    latest, err := latestMigration(migrationsPath)
+    if err != nil {
+        return err
+    }
+
+    var version uint64
+    var dirty bool
+    err = db.QueryRow("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("no migrations applied, latest is %d", latest)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to read migration version: %w", err)
+    }
+    if dirty {
+        return fmt.Errorf("migration %d failed and must be fixed by hand", version)
+    }
+    if version < latest {
+        return fmt.Errorf("database is at migration %d, latest is %d", version, latest)
+    }
+    return nil
+}
+
+// latestMigration returns the highest version among the migration files
+func latestMigration(migrationsPath string) (uint64, error) {
+    entries, err := os.ReadDir(migrationsPath)
+    if err != nil {
+        return 0, fmt.Errorf("failed to read migrations: %w", err)
+    }
+    var latest uint64
+    for _, entry := range entries {
+        prefix, _, ok := strings.Cut(entry.Name(), "_")
+        if !ok || !strings.HasSuffix(entry.Name(), ".up.sql") {
+            continue
+        }
+        if version, err := strconv.ParseUint(prefix, 10, 64); err == nil && version > latest {
+            latest = version
+        }
+    }
+    return latest, nil
+}
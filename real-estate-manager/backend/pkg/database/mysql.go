@@ -1,10 +1,14 @@
 package database
 
 import (
+    "context"
     "database/sql"
     "fmt"
+    "time"
 
-    _ "github.com/go-sql-driver/mysql"
+    "real-estate-manager/backend/pkg/secrets"
+
+    "github.com/go-sql-driver/mysql"
 )
 
 type Config struct {
@@ -13,10 +17,15 @@ type Config struct {
     User     string
     Password string
     DBName   string
+
+    // Secrets, when set, is consulted for DB_PASSWORD on every new connection
+    Secrets secrets.Provider
 }
 
 func NewMySQLConnection(config Config) (*sql.DB, error) {
-    dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+    // Sessions run in UTC so TIMESTAMP columns are written and read as UTC
+    // whatever the server's or the host's zone
+    dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC&time_zone=%%27%%2B00%%3A00%%27",
         config.User,
         config.Password,
         config.Host,
@@ -24,10 +33,33 @@ func NewMySQLConnection(config Config) (*sql.DB, error) {
         config.DBName,
     )
 
-    db, err := sql.Open("mysql", dsn)
+    mysqlConfig, err := mysql.ParseDSN(dsn)
+    if err != nil {
+        return nil, fmt.Errorf("failed to parse database DSN: %w", err)
+    }
+
+    if config.Secrets != nil {
+        // Re-resolve the password for each new connection so rotated
+        // credentials are used once the provider cache expires
+        provider := config.Secrets
+        fallback := config.Password
+        if err := mysqlConfig.Apply(mysql.BeforeConnect(func(ctx context.Context, cfg *mysql.Config) error {
+            password, err := secrets.GetOrDefault(ctx, provider, "DB_PASSWORD", fallback)
+            if err != nil {
+                return fmt.Errorf("failed to resolve database password: %w", err)
+            }
+            cfg.Passwd = password
+            return nil
+        })); err != nil {
+            return nil, fmt.Errorf("failed to configure database connector: %w", err)
+        }
+    }
+
+    connector, err := mysql.NewConnector(mysqlConfig)
     if err != nil {
         return nil, fmt.Errorf("failed to open database: %w", err)
     }
+    db := sql.OpenDB(connector)
 
     // Test the connection
     if err := db.Ping(); err != nil {
@@ -37,6 +69,8 @@ func NewMySQLConnection(config Config) (*sql.DB, error) {
     // Set connection pool settings
     db.SetMaxOpenConns(25)
     db.SetMaxIdleConns(25)
+    // Recycle connections so they eventually authenticate with rotated passwords
+    db.SetConnMaxLifetime(30 * time.Minute)
 
     return db, nil
-}
\ No newline at end of file
+}
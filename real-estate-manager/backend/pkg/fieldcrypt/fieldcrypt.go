@@ -0,0 +1,165 @@
+// Package fieldcrypt encrypts sensitive column values, such as phone
+// numbers and OAuth tokens, with AES-256-GCM before they are stored.
+//
+// Encrypted values look like "enc:v1:<key id>:<base64 nonce+ciphertext>",
+// so the key a value was encrypted with is known when decrypting. Several
+// keys can be configured at once: new values use the primary key and the
+// others are only used to decrypt, which lets keys be rotated without
+// downtime. Values without the prefix are plaintext stored before
+// encryption was enabled and are returned unchanged.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"real-estate-manager/backend/pkg/secrets"
+)
+
+// KeysSecret is the secret holding the keys: comma-separated
+// "<id>:<base64 32-byte key>" pairs, the primary key first
+const KeysSecret = "FIELD_ENCRYPTION_KEYS"
+
+const prefix = "enc:v1:"
+
+// ErrUnknownKey is returned when a value was encrypted with a key that is
+// no longer configured
+var ErrUnknownKey = errors.New("value was encrypted with an unknown key")
+
+// Cipher encrypts and decrypts column values. A nil *Cipher leaves values
+// as they are, so encryption is off until keys are configured.
+type Cipher struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewFromProvider builds the cipher from the KeysSecret secret. It returns
+// nil when no keys are configured.
+func NewFromProvider(ctx context.Context, provider secrets.Provider) (*Cipher, error) {
+	value, err := secrets.GetOrDefault(ctx, provider, KeysSecret, "")
+	if err != nil || value == "" {
+		return nil, err
+	}
+	return Parse(value)
+}
+
+// Parse builds the cipher from comma-separated "<id>:<base64 key>" pairs.
+// The first key is the primary one.
+func Parse(value string) (*Cipher, error) {
+	c := &Cipher{keys: make(map[string]cipher.AEAD)}
+	for _, pair := range strings.Split(value, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || !validKeyID(id) {
+			return nil, fmt.Errorf("invalid %s entry: use <id>:<base64 key> with a letter, digit and dash id", KeysSecret)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes of base64", id)
+		}
+		if _, exists := c.keys[id]; exists {
+			return nil, fmt.Errorf("key %q is configured twice", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		if c.primary == "" {
+			c.primary = id
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+// validKeyID keeps key IDs free of characters that are special in values
+// or LIKE patterns
+func validKeyID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Encrypt encrypts plaintext with the primary key. Empty values stay empty
+// so "not set" remains distinguishable without decrypting.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	aead := c.keys[c.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.primary))
+	return prefix + c.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of an encrypted value. Plaintext values are
+// returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	rest, encrypted := strings.CutPrefix(value, prefix)
+	if !encrypted {
+		return value, nil
+	}
+	if c == nil {
+		return "", ErrUnknownKey
+	}
+	id, encoded, _ := strings.Cut(rest, ":")
+	aead, ok := c.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// PrimaryPrefix is the prefix every value encrypted with the primary key
+// starts with, for finding values that still need rotation in SQL
+func (c *Cipher) PrimaryPrefix() string {
+	return prefix + c.primary + ":"
+}
+
+// NeedsRotation reports whether value should be encrypted again: it is
+// plaintext or was encrypted with a key other than the primary one
+func (c *Cipher) NeedsRotation(value string) bool {
+	if c == nil || value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, c.PrimaryPrefix())
+}
+
+// DecryptAll decrypts each of values in place, stopping at the first
+// error. It suits decrypting the fields of a scanned row.
+func (c *Cipher) DecryptAll(values ...*string) error {
+	for _, value := range values {
+		plaintext, err := c.Decrypt(*value)
+		if err != nil {
+			return err
+		}
+		*value = plaintext
+	}
+	return nil
+}
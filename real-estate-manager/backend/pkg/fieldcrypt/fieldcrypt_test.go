@@ -0,0 +1,94 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := Parse("k1:" + testKey('a'))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	encrypted, err := c.Encrypt("+15551234567")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "enc:v1:k1:") || strings.Contains(encrypted, "5551234567") {
+		t.Errorf("expected an encrypted value, got %q", encrypted)
+	}
+	if again, _ := c.Encrypt("+15551234567"); again == encrypted {
+		t.Error("expected a fresh nonce for every value")
+	}
+	if plaintext, err := c.Decrypt(encrypted); err != nil || plaintext != "+15551234567" {
+		t.Errorf("expected the phone number back, got %q and %v", plaintext, err)
+	}
+
+	// Plaintext from before encryption was enabled and empty values pass
+	// through
+	if plaintext, err := c.Decrypt("+15550000000"); err != nil || plaintext != "+15550000000" {
+		t.Errorf("expected plaintext to be returned unchanged, got %q and %v", plaintext, err)
+	}
+	if empty, _ := c.Encrypt(""); empty != "" {
+		t.Errorf("expected empty values to stay empty, got %q", empty)
+	}
+
+	tampered := encrypted[:len(encrypted)-4] + "AAA="
+	if _, err := c.Decrypt(tampered); err == nil {
+		t.Error("expected a tampered value to fail")
+	}
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	old, _ := Parse("k1:" + testKey('a'))
+	value, _ := old.Encrypt("refresh-token")
+
+	rotated, err := Parse("k2:" + testKey('b') + ", k1:" + testKey('a'))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if plaintext, err := rotated.Decrypt(value); err != nil || plaintext != "refresh-token" {
+		t.Errorf("expected older keys to keep decrypting, got %q and %v", plaintext, err)
+	}
+	if !rotated.NeedsRotation(value) || !rotated.NeedsRotation("plaintext") {
+		t.Error("expected values under older keys and plaintext to need rotation")
+	}
+	fresh, _ := rotated.Encrypt("refresh-token")
+	if rotated.NeedsRotation(fresh) || !strings.HasPrefix(fresh, rotated.PrimaryPrefix()) {
+		t.Errorf("expected new values to use the primary key, got %q", fresh)
+	}
+
+	retired, _ := Parse("k2:" + testKey('b'))
+	if _, err := retired.Decrypt(value); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey once the key is removed, got %v", err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"k1",
+		"k1:" + base64.StdEncoding.EncodeToString([]byte("short")),
+		"k_1:" + testKey('a'),
+		"k1:" + testKey('a') + ",k1:" + testKey('b'),
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestNilCipher(t *testing.T) {
+	var c *Cipher
+	if value, err := c.Encrypt("+15551234567"); err != nil || value != "+15551234567" {
+		t.Errorf("expected a nil cipher to store plaintext, got %q and %v", value, err)
+	}
+	if c.NeedsRotation("+15551234567") {
+		t.Error("expected nothing to rotate without keys")
+	}
+}
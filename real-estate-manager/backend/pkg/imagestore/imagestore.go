@@ -0,0 +1,208 @@
+// Package imagestore stores listing photos either in a local directory or
+// in an S3-compatible bucket. Either way photos are addressed by file name
+// and served by the API under /images, so stored photo URLs do not change
+// when the backend does.
+package imagestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"real-estate-manager/backend/pkg/objectstore"
+)
+
+// URLPrefix is the path photos are served under
+const URLPrefix = "/images/"
+
+// ErrNotFound is returned when no photo is stored under a name
+var ErrNotFound = errors.New("image not found")
+
+// Storage keeps photos by file name. Names are plain file names, without
+// directories.
+type Storage interface {
+	// Put stores content under name, replacing any photo already there,
+	// and returns its size
+	Put(ctx context.Context, name string, content io.Reader) (int64, error)
+	// Get returns a photo's content, or ErrNotFound. The caller closes it.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// Size returns a photo's size, or ErrNotFound
+	Size(ctx context.Context, name string) (int64, error)
+	// Delete removes a photo; deleting a missing photo is not an error
+	Delete(ctx context.Context, name string) error
+	// URL returns the path the photo is served at
+	URL(name string) string
+}
+
+// New returns the storage kind names: "local" (or empty) for dir, or "s3"
+// for bucket, which must then be configured
+func New(kind, dir string, bucket *objectstore.S3Store, prefix string) (Storage, error) {
+	switch kind {
+	case "", "local":
+		return NewLocal(dir), nil
+	case "s3":
+		if bucket == nil {
+			return nil, fmt.Errorf("S3 image storage needs S3_BUCKET")
+		}
+		return NewS3(bucket, prefix), nil
+	default:
+		return nil, fmt.Errorf("unknown image storage %q: use local or s3", kind)
+	}
+}
+
+// checkName rejects names that would reach outside the storage, and the
+// hidden names of files still being written or scanned
+func checkName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid image name %q", name)
+	}
+	return nil
+}
+
+// Local stores photos as files in one directory
+type Local struct {
+	dir string
+}
+
+// NewLocal stores photos in dir, created by the first Put if it does not
+// exist
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+// Dir returns the directory photos are stored in
+func (l *Local) Dir() string {
+	return l.dir
+}
+
+// Put writes content to a temporary file first, so a photo being replaced
+// is never served half-written
+func (l *Local) Put(ctx context.Context, name string, content io.Reader) (int64, error) {
+	if err := checkName(name); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create image directory: %w", err)
+	}
+	file, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create image file: %w", err)
+	}
+	size, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(l.dir, name))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return 0, fmt.Errorf("failed to save image: %w", err)
+	}
+	return size, nil
+}
+
+// Get returns the photo's *os.File
+func (l *Local) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, ErrNotFound
+	}
+	file, err := os.Open(filepath.Join(l.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	return file, nil
+}
+
+func (l *Local) Size(ctx context.Context, name string) (int64, error) {
+	if err := checkName(name); err != nil {
+		return 0, ErrNotFound
+	}
+	info, err := os.Stat(filepath.Join(l.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat image: %w", err)
+	}
+	return info.Size(), nil
+}
+
+func (l *Local) Delete(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(l.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) URL(name string) string {
+	return URLPrefix + name
+}
+
+// S3 stores photos as objects under a key prefix in a bucket, shared by
+// every API instance
+type S3 struct {
+	bucket *objectstore.S3Store
+	prefix string
+}
+
+func NewS3(bucket *objectstore.S3Store, prefix string) *S3 {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &S3{bucket: bucket, prefix: prefix}
+}
+
+func (s *S3) Put(ctx context.Context, name string, content io.Reader) (int64, error) {
+	if err := checkName(name); err != nil {
+		return 0, err
+	}
+	return s.bucket.Put(ctx, s.prefix+name, mime.TypeByExtension(filepath.Ext(name)), content)
+}
+
+func (s *S3) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, ErrNotFound
+	}
+	body, err := s.bucket.Open(ctx, s.prefix+name)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return body, err
+}
+
+func (s *S3) Size(ctx context.Context, name string) (int64, error) {
+	if err := checkName(name); err != nil {
+		return 0, ErrNotFound
+	}
+	size, err := s.bucket.Size(ctx, s.prefix+name)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return 0, ErrNotFound
+	}
+	return size, err
+}
+
+func (s *S3) Delete(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	return s.bucket.Delete(ctx, s.prefix+name)
+}
+
+func (s *S3) URL(name string) string {
+	return URLPrefix + name
+}
@@ -0,0 +1,118 @@
+package imagestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"real-estate-manager/backend/pkg/objectstore"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "images")
+	store := NewLocal(dir)
+
+	if size, err := store.Put(ctx, "front.jpg", strings.NewReader("front")); err != nil || size != 5 {
+		t.Fatalf("Expected 5 bytes stored, got %d, %v", size, err)
+	}
+	if _, err := store.Put(ctx, "front.jpg", strings.NewReader("new front")); err != nil {
+		t.Fatalf("Expected the photo to be replaced, got %v", err)
+	}
+	content, err := store.Get(ctx, "front.jpg")
+	if err != nil {
+		t.Fatalf("Expected the photo, got %v", err)
+	}
+	body, _ := io.ReadAll(content)
+	content.Close()
+	if string(body) != "new front" {
+		t.Errorf("Expected the replaced content, got %q", body)
+	}
+	if size, err := store.Size(ctx, "front.jpg"); err != nil || size != 9 {
+		t.Errorf("Expected size 9, got %d, %v", size, err)
+	}
+	if url := store.URL("front.jpg"); url != "/images/front.jpg" {
+		t.Errorf("Unexpected URL %q", url)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left, got %d files", len(entries))
+	}
+
+	if err := store.Delete(ctx, "front.jpg"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Delete(ctx, "front.jpg"); err != nil {
+		t.Errorf("Expected deleting a missing photo to succeed, got %v", err)
+	}
+	if _, err := store.Get(ctx, "front.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLocal_RejectsNames(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(t.TempDir())
+	for _, name := range []string{"", "..", "../secret.jpg", `a\b.jpg`, ".scan-front.jpg"} {
+		if _, err := store.Put(ctx, name, strings.NewReader("x")); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+		if _, err := store.Get(ctx, name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected %q to be not found, got %v", name, err)
+		}
+	}
+}
+
+func TestS3_Put(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	bucket := objectstore.NewS3Store(objectstore.S3Config{Bucket: "photos", Region: "us-east-1",
+		AccessKeyID: "key", SecretAccessKey: "secret", Endpoint: server.URL})
+	store := NewS3(bucket, "images")
+
+	size, err := store.Put(context.Background(), "front.jpg", strings.NewReader("front"))
+	if err != nil || size != 5 {
+		t.Fatalf("Expected 5 bytes stored, got %d, %v", size, err)
+	}
+	if got.Method != http.MethodPut || got.URL.Path != "/photos/images/front.jpg" {
+		t.Errorf("Unexpected request %s %s", got.Method, got.URL.Path)
+	}
+	if got.Header.Get("Content-Type") != "image/jpeg" || string(body) != "front" {
+		t.Errorf("Unexpected upload %q of %q", got.Header.Get("Content-Type"), body)
+	}
+	// The signature covers the body
+	sum := sha256.Sum256([]byte("front"))
+	if got.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) || got.Header.Get("Authorization") == "" {
+		t.Errorf("Expected a signed request, got headers %v", got.Header)
+	}
+	if url := store.URL("front.jpg"); url != "/images/front.jpg" {
+		t.Errorf("Expected photos to be served by the API, got %q", url)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if store, err := New("", t.TempDir(), nil, ""); err != nil {
+		t.Errorf("Expected local storage by default, got %v", err)
+	} else if _, ok := store.(*Local); !ok {
+		t.Errorf("Expected local storage, got %T", store)
+	}
+	if _, err := New("s3", t.TempDir(), nil, "images/"); err == nil {
+		t.Error("Expected S3 storage without a bucket to be rejected")
+	}
+	if _, err := New("ftp", t.TempDir(), nil, ""); err == nil {
+		t.Error("Expected an unknown storage to be rejected")
+	}
+}
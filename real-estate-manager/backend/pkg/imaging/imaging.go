@@ -0,0 +1,121 @@
+// Package imaging resizes and watermarks photos using only the standard
+// library, for serving public variants of listing images.
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// Flatten copies src into an opaque RGBA image, compositing transparent
+// areas onto white so they encode sensibly as JPEG
+func Flatten(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	return dst
+}
+
+// Resize scales src down to maxWidth, keeping the aspect ratio, by
+// averaging the source pixels each destination pixel covers. Images already
+// narrower than maxWidth are returned unchanged.
+func Resize(src *image.RGBA, maxWidth int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	if srcW <= maxWidth || maxWidth <= 0 {
+		return src
+	}
+	dstW := maxWidth
+	dstH := max(1, (srcH*dstW+srcW/2)/srcW)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := span(y, dstH, srcH)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := span(x, dstW, srcW)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r, g, b, a = r+int(p[0]), g+int(p[1]), b+int(p[2]), a+int(p[3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+// Fill crops src around its centre to the aspect ratio of width x height and
+// scales it down to width, for photos placed in a fixed frame
+func Fill(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	cropW, cropH := srcW, srcW*height/width
+	if cropH > srcH {
+		cropW, cropH = srcH*width/height, srcH
+	}
+	crop := image.Rect(0, 0, max(1, cropW), max(1, cropH)).Add(image.Pt((srcW-cropW)/2, (srcH-cropH)/2))
+
+	dst := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min.Add(crop.Min), draw.Src)
+	return Resize(dst, width)
+}
+
+// span returns the source range [from, to) covered by destination index i
+func span(i, dstSize, srcSize int) (int, int) {
+	from := i * srcSize / dstSize
+	to := (i + 1) * srcSize / dstSize
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// Watermark draws text in the bottom-right corner of img, as translucent
+// white capitals over a faint shadow. The text spans about a third of the
+// image width.
+func Watermark(img *image.RGBA, text string) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	if text == "" {
+		return
+	}
+
+	bounds := img.Bounds()
+	textDots := len([]rune(text))*(glyphWidth+1) - 1
+	scale := max(1, bounds.Dx()/3/textDots)
+	width, height := textDots*scale, glyphHeight*scale
+	margin := 2 * scale
+	origin := image.Pt(bounds.Max.X-width-margin, bounds.Max.Y-height-margin)
+	if origin.X < bounds.Min.X || origin.Y < bounds.Min.Y {
+		return
+	}
+
+	shadow := image.NewUniform(color.NRGBA{0, 0, 0, 90})
+	ink := image.NewUniform(color.NRGBA{255, 255, 255, 160})
+	offset := max(1, scale/2)
+	drawText(img, text, origin.Add(image.Pt(offset, offset)), scale, shadow)
+	drawText(img, text, origin, scale, ink)
+}
+
+func drawText(img *image.RGBA, text string, origin image.Point, scale int, ink image.Image) {
+	x := origin.X
+	for _, r := range text {
+		glyph := glyphs[r]
+		for row, bits := range glyph {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				dot := image.Rect(x+col*scale, origin.Y+row*scale, x+(col+1)*scale, origin.Y+(row+1)*scale)
+				draw.Draw(img, dot, ink, image.Point{}, draw.Over)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
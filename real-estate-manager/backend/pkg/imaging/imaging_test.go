@@ -0,0 +1,87 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	// Left half black, right half white
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			if x >= 200 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		maxWidth int
+		expectW  int
+		expectH  int
+	}{
+		{name: "scales down keeping aspect ratio", maxWidth: 100, expectW: 100, expectH: 75},
+		{name: "never scales up", maxWidth: 800, expectW: 400, expectH: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := Resize(Flatten(src), tt.maxWidth)
+			if dst.Bounds().Dx() != tt.expectW || dst.Bounds().Dy() != tt.expectH {
+				t.Fatalf("Expected %dx%d, got %v", tt.expectW, tt.expectH, dst.Bounds())
+			}
+			left, right := dst.RGBAAt(0, 0), dst.RGBAAt(tt.expectW-1, 0)
+			if left.R != 0 || right.R != 255 {
+				t.Errorf("Expected black and white halves to survive, got %v and %v", left, right)
+			}
+		})
+	}
+}
+
+func TestWatermark(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 600, 400))
+
+	Watermark(img, "Sample 1")
+
+	// Only the bottom-right corner is marked
+	lit := func(r image.Rectangle) int {
+		n := 0
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if img.RGBAAt(x, y).R > 0 {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if lit(image.Rect(300, 300, 600, 400)) == 0 {
+		t.Error("Expected watermark in the bottom-right corner")
+	}
+	if lit(image.Rect(0, 0, 300, 300)) != 0 {
+		t.Error("Expected the rest of the image to be untouched")
+	}
+}
+
+func TestFill(t *testing.T) {
+	// A wide image with a red centre strip
+	src := image.NewRGBA(image.Rect(0, 0, 900, 300))
+	for y := 0; y < 300; y++ {
+		for x := 300; x < 600; x++ {
+			src.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+
+	dst := Fill(src, 100, 100)
+	if dst.Bounds().Dx() != 100 || dst.Bounds().Dy() != 100 {
+		t.Fatalf("Expected 100x100, got %v", dst.Bounds())
+	}
+	if corner := dst.RGBAAt(0, 0); corner.R != 255 {
+		t.Errorf("Expected the centre to be kept, got %v in the corner", corner)
+	}
+}
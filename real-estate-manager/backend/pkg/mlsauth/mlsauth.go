@@ -0,0 +1,186 @@
+// Package mlsauth authorizes requests to MLS data providers. Providers use
+// HTTP basic auth, a static bearer token, or OAuth 2.0 client credentials;
+// the latter's access tokens are cached and refreshed before they expire.
+package mlsauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expiryDelta refreshes OAuth tokens this long before they expire, so a
+// token does not lapse while a request is in flight
+const expiryDelta = 30 * time.Second
+
+// Authenticator adds credentials to a provider request
+type Authenticator interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// Invalidator is implemented by authenticators with cached credentials that
+// should be discarded when the provider rejects them
+type Invalidator interface {
+	Invalidate()
+}
+
+// NewFromEnv builds the authenticator selected by <prefix>_AUTH: "basic"
+// (default, returns nil so the caller's basic credentials apply), "bearer"
+// (<prefix>_TOKEN) or "oauth" (<prefix>_TOKEN_URL, <prefix>_CLIENT_ID,
+// <prefix>_CLIENT_SECRET and optional <prefix>_OAUTH_SCOPE).
+func NewFromEnv(prefix string) (Authenticator, error) {
+	env := func(name string) string { return os.Getenv(prefix + "_" + name) }
+
+	switch mode := strings.ToLower(env("AUTH")); mode {
+	case "", "basic":
+		return nil, nil
+	case "bearer":
+		if env("TOKEN") == "" {
+			return nil, fmt.Errorf("%s_TOKEN is required for bearer auth", prefix)
+		}
+		return Bearer{Token: env("TOKEN")}, nil
+	case "oauth":
+		for _, name := range []string{"TOKEN_URL", "CLIENT_ID", "CLIENT_SECRET"} {
+			if env(name) == "" {
+				return nil, fmt.Errorf("%s_%s is required for oauth auth", prefix, name)
+			}
+		}
+		return NewClientCredentials(&http.Client{Timeout: 30 * time.Second},
+			env("TOKEN_URL"), env("CLIENT_ID"), env("CLIENT_SECRET"), env("OAUTH_SCOPE")), nil
+	default:
+		return nil, fmt.Errorf("unknown %s_AUTH %q", prefix, mode)
+	}
+}
+
+// Basic sends HTTP basic auth credentials
+type Basic struct {
+	Username string
+	Password string
+}
+
+func (b Basic) Authorize(ctx context.Context, req *http.Request) error {
+	req.SetBasicAuth(b.Username, b.Password)
+	return nil
+}
+
+// Bearer sends a static bearer token
+type Bearer struct {
+	Token string
+}
+
+func (b Bearer) Authorize(ctx context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
+
+// ClientCredentials obtains access tokens with the OAuth 2.0 client
+// credentials grant. Tokens are shared by concurrent requests and fetched
+// again shortly before they expire or after Invalidate.
+type ClientCredentials struct {
+	client       *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scope        string
+	now          func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewClientCredentials(client *http.Client, tokenURL, clientID, clientSecret, scope string) *ClientCredentials {
+	return &ClientCredentials{
+		client:       client,
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scope:        scope,
+		now:          time.Now,
+	}
+}
+
+func (c *ClientCredentials) Authorize(ctx context.Context, req *http.Request) error {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns the cached access token, fetching a new one when there is
+// none or it is about to expire
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && (c.expiresAt.IsZero() || c.now().Add(expiryDelta).Before(c.expiresAt)) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.expiresAt = time.Time{}
+	if expiresIn > 0 {
+		c.expiresAt = c.now().Add(expiresIn)
+	}
+	return c.token, nil
+}
+
+// Invalidate discards the cached token so the next request fetches a new one
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+}
+
+func (c *ClientCredentials) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if c.scope != "" {
+		form.Set("scope", c.scope)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", 0, fmt.Errorf("token endpoint returned no access token")
+	}
+	if result.TokenType != "" && !strings.EqualFold(result.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("unsupported token type %q", result.TokenType)
+	}
+	return result.AccessToken, time.Duration(result.ExpiresIn) * time.Second, nil
+}
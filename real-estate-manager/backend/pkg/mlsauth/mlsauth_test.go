@@ -0,0 +1,109 @@
+package mlsauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientCredentials_Token(t *testing.T) {
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			t.Errorf("Unexpected client credentials %q/%q", id, secret)
+		}
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "listings" {
+			t.Errorf("Unexpected form: %v", r.Form)
+		}
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, issued)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	auth := NewClientCredentials(server.Client(), server.URL, "client", "secret", "listings")
+	auth.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		reset   bool
+		expect  string
+	}{
+		{name: "first request fetches a token", expect: "token-1"},
+		{name: "cached while valid", advance: 30 * time.Minute, expect: "token-1"},
+		{name: "refreshed shortly before expiry", advance: 29*time.Minute + 45*time.Second, expect: "token-2"},
+		{name: "refetched after invalidation", reset: true, expect: "token-3"},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if step.reset {
+			auth.Invalidate()
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/properties", nil)
+		if err := auth.Authorize(context.Background(), req); err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer "+step.expect {
+			t.Errorf("%s: expected bearer %s, got %q", step.name, step.expect, got)
+		}
+	}
+}
+
+func TestClientCredentials_TokenError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	auth := NewClientCredentials(server.Client(), server.URL, "client", "wrong", "")
+	if err := auth.Authorize(context.Background(), httptest.NewRequest(http.MethodGet, "/", nil)); err == nil {
+		t.Error("Expected error for a rejected client")
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectNil   bool
+		expectError bool
+	}{
+		{name: "basic by default", expectNil: true},
+		{name: "bearer", env: map[string]string{"MLS_AUTH": "bearer", "MLS_TOKEN": "abc"}},
+		{name: "bearer without token", env: map[string]string{"MLS_AUTH": "bearer"}, expectError: true},
+		{name: "oauth", env: map[string]string{"MLS_AUTH": "oauth", "MLS_TOKEN_URL": "https://idp.example.com/token",
+			"MLS_CLIENT_ID": "client", "MLS_CLIENT_SECRET": "secret"}},
+		{name: "oauth without secret", env: map[string]string{"MLS_AUTH": "oauth", "MLS_TOKEN_URL": "https://idp.example.com/token",
+			"MLS_CLIENT_ID": "client"}, expectError: true},
+		{name: "unknown mode", env: map[string]string{"MLS_AUTH": "digest"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"AUTH", "TOKEN", "TOKEN_URL", "CLIENT_ID", "CLIENT_SECRET", "OAUTH_SCOPE"} {
+				t.Setenv("MLS_"+name, tt.env["MLS_"+name])
+			}
+
+			auth, err := NewFromEnv("MLS")
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (auth == nil) != tt.expectNil {
+				t.Errorf("Unexpected authenticator %T", auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// Package objectstore talks to S3-compatible object storage using the
+// SigV4 signing in pkg/awsauth, so large files can be uploaded straight to a
+// bucket without passing through the API server.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"real-estate-manager/backend/pkg/awsauth"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// S3Config describes the bucket uploads are stored in
+type S3Config struct {
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // optional override, e.g. for MinIO or LocalStack
+}
+
+// S3Store issues pre-signed URLs and inspects objects in one bucket.
+// Objects are addressed path-style (endpoint/bucket/key).
+type S3Store struct {
+	config S3Config
+	client *http.Client
+}
+
+func NewS3Store(config S3Config) *S3Store {
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	return &S3Store{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// URL returns the object URL for key
+func (s *S3Store) URL(key string) string {
+	return s.config.Endpoint + "/" + s.config.Bucket + "/" + escapeKey(key)
+}
+
+// PresignPut returns a URL the client can PUT the object body to until it
+// expires
+func (s *S3Store) PresignPut(key string, expires time.Duration) (string, error) {
+	return awsauth.PresignURL(http.MethodPut, s.URL(key), s.credentials(), s.config.Region, "s3", expires, time.Now())
+}
+
+// Size returns the stored size of an object, or ErrNotFound
+func (s *S3Store) Size(ctx context.Context, key string) (int64, error) {
+	resp, err := s.do(ctx, http.MethodHead, key)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.ContentLength, nil
+	case http.StatusNotFound:
+		return 0, ErrNotFound
+	default:
+		return 0, fmt.Errorf("object HEAD returned status %d", resp.StatusCode)
+	}
+}
+
+// Open returns an object's content, or ErrNotFound. The caller closes it.
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("object GET returned status %d", resp.StatusCode)
+	}
+}
+
+// Put stores body under key, replacing any object already there. The body
+// is read into memory to be signed, so Put suits photos rather than large
+// files, which are better uploaded through PresignPut.
+func (s *S3Store) Put(ctx context.Context, key, contentType string, body io.Reader) (int64, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read object body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.URL(key), bytes.NewReader(content))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create object request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	awsauth.SignRequest(req, content, s.credentials(), s.config.Region, "s3", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("object storage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("object PUT returned status %d", resp.StatusCode)
+	}
+	return int64(len(content)), nil
+}
+
+// PutFile stores the file at path under key, streaming it rather than
+// reading it into memory, and returns its size
+func (s *S3Store) PutFile(ctx context.Context, key, contentType, path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	// The signature covers the content, so the file is read twice
+	digest := sha256.New()
+	size, err := io.Copy(digest, file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.URL(key), file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create object request: %w", err)
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	awsauth.SignPayloadHash(req, hex.EncodeToString(digest.Sum(nil)), s.credentials(), s.config.Region, "s3", time.Now())
+
+	// Large files take longer than the client's usual timeout
+	client := *s.client
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("object storage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("object PUT returned status %d", resp.StatusCode)
+	}
+	return size, nil
+}
+
+// Check verifies the bucket exists and the credentials may access it
+func (s *S3Store) Check(ctx context.Context) error {
+	resp, err := s.do(ctx, http.MethodHead, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bucket HEAD returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Delete removes an object; deleting a missing object is not an error
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("object DELETE returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.URL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object request: %w", err)
+	}
+	awsauth.SignRequest(req, nil, s.credentials(), s.config.Region, "s3", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("object storage request failed: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *S3Store) credentials() awsauth.Credentials {
+	return awsauth.Credentials{
+		AccessKeyID:     s.config.AccessKeyID,
+		SecretAccessKey: s.config.SecretAccessKey,
+		SessionToken:    s.config.SessionToken,
+	}
+}
+
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// NewS3StoreFromEnv configures a store from S3_BUCKET, S3_REGION (or
+// AWS_REGION), S3_ENDPOINT and the standard AWS credential variables. It
+// returns nil when S3_BUCKET is unset.
+func NewS3StoreFromEnv() *S3Store {
+	bucket := os.Getenv("S3_BUCKET")
+	if bucket == "" {
+		return nil
+	}
+	region := os.Getenv("S3_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	return NewS3Store(S3Config{
+		Bucket:          bucket,
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+	})
+}
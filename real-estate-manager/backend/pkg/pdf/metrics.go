@@ -0,0 +1,84 @@
+package pdf
+
+import "strings"
+
+// Glyph widths of the printable ASCII characters (32-126) in thousandths of
+// the font size, from the Adobe font metrics
+var widths = [...][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// defaultWidth is used for characters outside printable ASCII
+const defaultWidth = 556
+
+// TextWidth returns the width of text in points
+func TextWidth(font Font, size float64, text string) float64 {
+	total := 0
+	for _, c := range encode(text) {
+		if c >= 32 && c <= 126 {
+			total += widths[font][c-32]
+		} else {
+			total += defaultWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapText breaks text into lines no wider than width, at spaces where
+// possible. Existing line breaks are kept.
+func WrapText(font Font, size float64, text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(font, size, candidate) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+			// Words longer than a line are split
+			for TextWidth(font, size, candidate) > width && len([]rune(candidate)) > 1 {
+				runes := []rune(candidate)
+				cut := len(runes) - 1
+				for cut > 1 && TextWidth(font, size, string(runes[:cut])) > width {
+					cut--
+				}
+				lines = append(lines, string(runes[:cut]))
+				candidate = string(runes[cut:])
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Truncate shortens text with an ellipsis to fit width
+func Truncate(font Font, size float64, text string, width float64) string {
+	if TextWidth(font, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(font, size, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + "…"
+}
@@ -0,0 +1,211 @@
+// Package pdf writes simple PDF documents, such as listing flyers, using only
+// the standard library. It supports filled rectangles, text in the built-in
+// Helvetica fonts and JPEG images.
+//
+// Coordinates are in points (1/72 inch) from the top-left corner of the
+// page; text is positioned by its baseline.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+)
+
+// Page sizes in points
+const (
+	LetterWidth  = 612
+	LetterHeight = 792
+)
+
+// Font is one of the standard fonts every PDF reader provides
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = [...]string{Helvetica: "Helvetica", HelveticaBold: "Helvetica-Bold"}
+
+// Document is a PDF under construction
+type Document struct {
+	pages  []*Page
+	images [][]byte
+	// imageSizes holds the pixel size of each image
+	imageSizes []image.Point
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// Page is a page of a document
+type Page struct {
+	doc           *Document
+	width, height float64
+	content       bytes.Buffer
+	images        []int
+}
+
+// AddPage appends a page of the given size in points
+func (d *Document) AddPage(width, height float64) *Page {
+	page := &Page{doc: d, width: width, height: height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Rect fills a rectangle
+func (p *Page) Rect(x, y, width, height float64, fill color.Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n", rgb(fill), num(x), num(p.height-y-height), num(width), num(height))
+}
+
+// Line strokes a line
+func (p *Page) Line(x1, y1, x2, y2, width float64, stroke color.Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n", rgb(stroke), num(width), num(x1), num(p.height-y1), num(x2), num(p.height-y2))
+}
+
+// Text draws a single line of text with its baseline at y. Characters the
+// fonts cannot show are replaced with "?".
+func (p *Page) Text(x, y float64, font Font, size float64, fill color.Color, text string) {
+	fmt.Fprintf(&p.content, "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n", rgb(fill), font+1, num(size), num(x), num(p.height-y), escape(text))
+}
+
+// Image draws img scaled into the given rectangle. It is embedded as a JPEG
+// of the given quality.
+func (p *Page) Image(img image.Image, x, y, width, height float64, quality int) error {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: quality}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	index := len(p.doc.images)
+	p.doc.images = append(p.doc.images, encoded.Bytes())
+	p.doc.imageSizes = append(p.doc.imageSizes, img.Bounds().Size())
+	p.images = append(p.images, index)
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n", num(width), num(height), num(x), num(p.height-y-height), index+1)
+	return nil
+}
+
+// Write encodes the document
+func (d *Document) Write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", len(offsets), body)
+		if stream != nil {
+			out.WriteString("stream\n")
+			out.Write(stream)
+			out.WriteString("\nendstream\n")
+		}
+		out.WriteString("endobj\n")
+	}
+
+	// Objects are numbered in the order they are written: catalog, page
+	// tree, fonts, images, then a page and its content stream per page
+	firstImage := 3 + len(fontNames)
+	firstPage := firstImage + len(d.images)
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)), nil)
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name), nil)
+	}
+	for i, data := range d.images {
+		size := d.imageSizes[i]
+		object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			size.X, size.Y, len(data)), data)
+	}
+
+	var fonts strings.Builder
+	for i := range fontNames {
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, 3+i)
+	}
+	for i, page := range d.pages {
+		var images strings.Builder
+		for _, index := range page.images {
+			fmt.Fprintf(&images, "/Im%d %d 0 R ", index+1, firstImage+index)
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> /XObject << %s>> >> /Contents %d 0 R >>",
+			num(page.width), num(page.height), fonts.String(), images.String(), firstPage+2*i+1), nil)
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		zw.Write(page.content.Bytes())
+		zw.Close()
+		object(fmt.Sprintf("<< /Filter /FlateDecode /Length %d >>", content.Len()), content.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// num formats a coordinate without needless digits
+func num(v float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func rgb(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("%s %s %s", num(float64(r)/0xffff), num(float64(g)/0xffff), num(float64(b)/0xffff))
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsiEncoding
+// provides
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encode converts text to WinAnsiEncoding
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		case winAnsi[r] != 0:
+			out = append(out, winAnsi[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// escape encodes text as the body of a PDF string literal
+func escape(text string) string {
+	var b strings.Builder
+	for _, c := range encode(text) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Write(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(LetterWidth, LetterHeight)
+	page.Rect(0, 0, LetterWidth, 72, color.RGBA{20, 40, 80, 255})
+	page.Text(36, 50, HelveticaBold, 20, color.White, "Casa (Lisbon) – 3 bedrooms")
+	page.Line(36, 100, 576, 100, 1, color.Black)
+	if err := page.Image(image.NewRGBA(image.Rect(0, 0, 40, 30)), 36, 120, 200, 150, 80); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := doc.Write(&out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pdf := out.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("Expected a PDF header and trailer")
+	}
+
+	// Every cross-reference entry must point at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	xref, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(pdf[xref:], "xref\n") {
+		t.Fatalf("startxref does not point at the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	if len(entries) != 7 {
+		t.Fatalf("Expected 7 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Errorf("Object %d is not at offset %d", i+1, offset)
+		}
+	}
+	if !strings.Contains(pdf, "/Subtype /Image /Width 40 /Height 30") || !strings.Contains(pdf, "/BaseFont /Helvetica-Bold") {
+		t.Error("Expected the image and fonts to be embedded")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape(`a (b) \ – 日`); got != "a \\(b\\) \\\\ \x96 ?" {
+		t.Errorf("Unexpected escaped text: %q", got)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	if width := TextWidth(Helvetica, 10, "Hello"); width != 22.78 {
+		t.Errorf("Expected width 22.78, got %v", width)
+	}
+
+	lines := WrapText(Helvetica, 10, "A bright corner unit with river views\n\nand a garage", 100)
+	expected := []string{"A bright corner unit", "with river views", "", "and a garage"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+	for _, line := range lines {
+		if TextWidth(Helvetica, 10, line) > 100 {
+			t.Errorf("Line %q is wider than 100pt", line)
+		}
+	}
+
+	if got := Truncate(HelveticaBold, 12, "A very long listing name indeed", 80); TextWidth(HelveticaBold, 12, got) > 80 || !strings.HasSuffix(got, "…") {
+		t.Errorf("Unexpected truncation %q", got)
+	}
+}
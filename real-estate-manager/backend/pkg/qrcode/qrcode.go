@@ -0,0 +1,499 @@
+// Package qrcode encodes short byte strings, such as listing URLs, as QR
+// codes using only the standard library. It supports byte mode at error
+// correction level M, versions 1 to 10 (up to 213 bytes).
+package qrcode
+
+import (
+	"errors"
+)
+
+// ErrTooLong is returned for content that does not fit in version 10
+var ErrTooLong = errors.New("qrcode: content too long")
+
+// Code is an encoded QR symbol. Modules are indexed [row][column]; true is
+// dark. The quiet zone around the symbol is not included.
+type Code struct {
+	Version int
+	Size    int
+	Modules [][]bool
+}
+
+// Dark reports whether the module at row, col is dark
+func (c *Code) Dark(row, col int) bool {
+	return c.Modules[row][col]
+}
+
+// blockLayout is the error correction block structure of a version at
+// level M
+type blockLayout struct {
+	ecPerBlock int
+	// dataPerBlock lists the data codewords of each block
+	dataPerBlock []int
+}
+
+var layouts = [...]blockLayout{
+	1:  {10, []int{16}},
+	2:  {16, []int{28}},
+	3:  {26, []int{44}},
+	4:  {18, []int{32, 32}},
+	5:  {24, []int{43, 43}},
+	6:  {16, []int{27, 27, 27, 27}},
+	7:  {18, []int{31, 31, 31, 31}},
+	8:  {22, []int{38, 38, 39, 39}},
+	9:  {22, []int{36, 36, 36, 37, 37}},
+	10: {26, []int{43, 43, 43, 43, 44}},
+}
+
+// alignmentPositions are the row/column centres of alignment patterns
+var alignmentPositions = [...][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+// maxVersion is the largest supported version
+const maxVersion = 10
+
+// levelM is the format information value of error correction level M
+const levelM = 0
+
+// Encode returns the smallest QR code holding content
+func Encode(content []byte) (*Code, error) {
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if 4+countBits(v)+8*len(content) <= 8*dataCapacity(v) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	data := encodeData(content, version)
+	codewords := addErrorCorrection(data, layouts[version])
+
+	q := newSymbol(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(codewords)
+
+	// Keep the mask with the lowest penalty
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormatBits(best)
+
+	return &Code{Version: version, Size: q.size, Modules: q.modules}, nil
+}
+
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+func dataCapacity(version int) int {
+	total := 0
+	for _, n := range layouts[version].dataPerBlock {
+		total += n
+	}
+	return total
+}
+
+// encodeData builds the byte mode bit stream, terminated and padded to the
+// version's data capacity
+func encodeData(content []byte, version int) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(content), countBits(version))
+	for _, b := range content {
+		bits.append(int(b), 8)
+	}
+
+	capacity := 8 * dataCapacity(version)
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// addErrorCorrection splits data into blocks, appends Reed-Solomon error
+// correction to each and interleaves the result
+func addErrorCorrection(data []byte, layout blockLayout) []byte {
+	divisor := rsDivisor(layout.ecPerBlock)
+	var blocks, ecBlocks [][]byte
+	for _, n := range layout.dataPerBlock {
+		block := data[:n]
+		data = data[n:]
+		blocks = append(blocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+	}
+
+	var out []byte
+	longest := layout.dataPerBlock[len(layout.dataPerBlock)-1]
+	for i := 0; i < longest; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, ec := range ecBlocks {
+			out = append(out, ec[i])
+		}
+	}
+	return out
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of degree, without
+// its leading term
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// symbol is a QR code under construction
+type symbol struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+func newSymbol(version int) *symbol {
+	size := 17 + 4*version
+	q := &symbol{version: version, size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.function[i] = make([]bool, size)
+	}
+	return q
+}
+
+func (q *symbol) set(row, col int, dark bool) {
+	q.modules[row][col] = dark
+	q.function[row][col] = true
+}
+
+func (q *symbol) drawFunctionPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	q.drawFinder(3, 3)
+	q.drawFinder(3, q.size-4)
+	q.drawFinder(q.size-4, 3)
+
+	positions := alignmentPositions[q.version]
+	last := len(positions) - 1
+	for i, row := range positions {
+		for j, col := range positions {
+			// Skip the three corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignment(row, col)
+		}
+	}
+
+	// Reserve the format areas; they are drawn once the mask is chosen
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator centred on row, col
+func (q *symbol) drawFinder(row, col int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			r, c := row+dy, col+dx
+			if r < 0 || r >= q.size || c < 0 || c >= q.size {
+				continue
+			}
+			distance := max(abs(dx), abs(dy))
+			q.set(r, c, distance != 2 && distance != 4)
+		}
+	}
+}
+
+func (q *symbol) drawAlignment(row, col int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.set(row+dy, col+dx, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for level M
+// and mask, and the dark module
+func (q *symbol) drawFormatBits(mask int) {
+	data := levelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	// Around the top-left finder
+	for i := 0; i <= 5; i++ {
+		q.set(i, 8, bit(i))
+	}
+	q.set(7, 8, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(8, 7, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(8, 14-i, bit(i))
+	}
+
+	// Split between the other two finders
+	for i := 0; i < 8; i++ {
+		q.set(8, q.size-1-i, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(q.size-15+i, 8, bit(i))
+	}
+	q.set(q.size-8, 8, true)
+}
+
+// drawVersion draws the version information of versions 7 and up
+func (q *symbol) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := q.size-11+i%3, i/3
+		q.set(b, a, dark)
+		q.set(a, b, dark)
+	}
+}
+
+// drawCodewords places the codewords in the zigzag order of the standard,
+// two columns at a time from the bottom-right corner
+func (q *symbol) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// The vertical timing pattern is skipped
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				col := right - j
+				row := vert
+				if (right+1)&2 == 0 {
+					row = q.size - 1 - vert
+				}
+				if !q.function[row][col] && i < len(codewords)*8 {
+					q.modules[row][col] = (codewords[i>>3]>>(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by mask; applying it twice
+// undoes it
+func (q *symbol) applyMask(mask int) {
+	for row := 0; row < q.size; row++ {
+		for col := 0; col < q.size; col++ {
+			if q.function[row][col] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (row+col)%2 == 0
+			case 1:
+				invert = row%2 == 0
+			case 2:
+				invert = col%3 == 0
+			case 3:
+				invert = (row+col)%3 == 0
+			case 4:
+				invert = (row/2+col/3)%2 == 0
+			case 5:
+				invert = row*col%2+row*col%3 == 0
+			case 6:
+				invert = (row*col%2+row*col%3)%2 == 0
+			case 7:
+				invert = ((row+col)%2+row*col%3)%2 == 0
+			}
+			if invert {
+				q.modules[row][col] = !q.modules[row][col]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to read, by the four rules of the
+// standard: long runs, 2x2 blocks, finder-like patterns and dark balance
+func (q *symbol) penalty() int {
+	score := 0
+	line := make([]bool, q.size)
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < q.size; i++ {
+			for j := 0; j < q.size; j++ {
+				if vertical {
+					line[j] = q.modules[j][i]
+				} else {
+					line[j] = q.modules[i][j]
+				}
+			}
+			score += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for row := 0; row < q.size; row++ {
+		for col := 0; col < q.size; col++ {
+			if q.modules[row][col] {
+				dark++
+			}
+			if row > 0 && col > 0 {
+				c := q.modules[row][col]
+				if q.modules[row-1][col] == c && q.modules[row][col-1] == c && q.modules[row-1][col-1] == c {
+					score += 3
+				}
+			}
+		}
+	}
+
+	total := q.size * q.size
+	percent := dark * 100 / total
+	score += abs(percent-50) / 5 * 10
+	return score
+}
+
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// linePenalty scores runs of five or more same-colored modules and 1:1:3:1:1
+// patterns with four light modules on either side
+func linePenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+7 <= len(line); i++ {
+		if !matches(line[i:i+7], finderLike) {
+			continue
+		}
+		if lightRun(line, i-4, i) || lightRun(line, i+7, i+11) {
+			score += 40
+		}
+	}
+	return score
+}
+
+func matches(line, pattern []bool) bool {
+	for i := range pattern {
+		if line[i] != pattern[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lightRun reports whether modules [from, to) are light; modules outside the
+// symbol belong to the quiet zone
+func lightRun(line []bool, from, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
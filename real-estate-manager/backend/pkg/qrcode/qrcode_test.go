@@ -0,0 +1,185 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// HELLO WORLD at 1-M, from the worked example of the standard
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	q := newSymbol(7)
+	q.drawFormatBits(0)
+	q.drawVersion()
+
+	// Format bits 14..0 run down column 8 below the top-right finder
+	format := ""
+	for i := 14; i >= 8; i-- {
+		format += bit(q.modules[q.size-15+i][8])
+	}
+	for i := 7; i >= 0; i-- {
+		format += bit(q.modules[8][q.size-1-i])
+	}
+	if format != "101010000010010" {
+		t.Errorf("Unexpected format bits for M, mask 0: %s", format)
+	}
+
+	version := ""
+	for i := 17; i >= 0; i-- {
+		version += bit(q.modules[i/3][q.size-11+i%3])
+	}
+	if version != "000111110010010100" {
+		t.Errorf("Unexpected version bits for version 7: %s", version)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		content       string
+		expectVersion int
+	}{
+		{content: "HELLO", expectVersion: 1},
+		{content: "https://listings.example.com/properties/12", expectVersion: 3},
+		{content: strings.Repeat("x", 100), expectVersion: 6},
+		{content: strings.Repeat("x", 213), expectVersion: 10},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode([]byte(tt.content))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if code.Version != tt.expectVersion || code.Size != 17+4*tt.expectVersion {
+			t.Errorf("Expected version %d for %d bytes, got %d (size %d)", tt.expectVersion, len(tt.content), code.Version, code.Size)
+		}
+		if got := decode(t, code); got != tt.content {
+			t.Errorf("Expected %q to read back, got %q", tt.content, got)
+		}
+	}
+
+	if _, err := Encode(bytes.Repeat([]byte("x"), 214)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+}
+
+// decode reads a symbol back: it unmasks the data modules, collects the
+// codewords in placement order, checks each block's error correction and
+// parses the byte mode segment
+func decode(t *testing.T, code *Code) string {
+	t.Helper()
+
+	q := newSymbol(code.Version)
+	q.drawFunctionPatterns()
+	mask := -1
+	for candidate := 0; candidate < 8; candidate++ {
+		q.drawFormatBits(candidate)
+		if q.modules[8][code.Size-1] == code.Modules[8][code.Size-1] && formatMatches(q, code) {
+			mask = candidate
+		}
+	}
+	if mask < 0 {
+		t.Fatal("No mask matches the format bits")
+	}
+
+	for row := range q.modules {
+		copy(q.modules[row], code.Modules[row])
+	}
+	q.applyMask(mask)
+
+	var bits bitBuffer
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				col, row := right-j, vert
+				if (right+1)&2 == 0 {
+					row = q.size - 1 - vert
+				}
+				if !q.function[row][col] {
+					bits = append(bits, q.modules[row][col])
+				}
+			}
+		}
+	}
+	codewords := bits.bytes()
+
+	layout := layouts[code.Version]
+	blocks := make([][]byte, len(layout.dataPerBlock))
+	i := 0
+	for n := 0; n < layout.dataPerBlock[len(blocks)-1]; n++ {
+		for b := range blocks {
+			if n < layout.dataPerBlock[b] {
+				blocks[b] = append(blocks[b], codewords[i])
+				i++
+			}
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		ec := make([]byte, layout.ecPerBlock)
+		for n := range ec {
+			ec[n] = codewords[i+n*len(blocks)+b]
+		}
+		if !bytes.Equal(rsRemainder(block, rsDivisor(layout.ecPerBlock)), ec) {
+			t.Fatalf("Block %d has invalid error correction", b)
+		}
+		data = append(data, block...)
+	}
+
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(n int) int {
+		value := 0
+		for _, bit := range stream[:n] {
+			value <<= 1
+			if bit {
+				value |= 1
+			}
+		}
+		stream = stream[n:]
+		return value
+	}
+	if mode := read(4); mode != 0b0100 {
+		t.Fatalf("Expected byte mode, got %04b", mode)
+	}
+	length := read(countBits(code.Version))
+	content := make([]byte, length)
+	for n := range content {
+		content[n] = byte(read(8))
+	}
+	return string(content)
+}
+
+func formatMatches(q *symbol, code *Code) bool {
+	for i := 0; i < 8; i++ {
+		if q.modules[8][q.size-1-i] != code.Modules[8][code.Size-1-i] {
+			return false
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if q.modules[q.size-15+i][8] != code.Modules[code.Size-15+i][8] {
+			return false
+		}
+	}
+	return true
+}
+
+func bit(dark bool) string {
+	if dark {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"real-estate-manager/backend/pkg/awsauth"
+)
+
+// AWSConfig describes an AWS Secrets Manager secret whose SecretString is a
+// JSON object of key/value pairs (e.g. {"DB_PASSWORD": "...", "JWT_SECRET": "..."})
+type AWSConfig struct {
+	Region          string
+	SecretID        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // optional override, e.g. for LocalStack
+}
+
+// AWSSecretsManagerProvider reads secrets through the GetSecretValue API
+type AWSSecretsManagerProvider struct {
+	config AWSConfig
+	client *http.Client
+}
+
+func NewAWSSecretsManagerProvider(config AWSConfig) *AWSSecretsManagerProvider {
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", config.Region)
+	}
+	return &AWSSecretsManagerProvider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *AWSSecretsManagerProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": p.config.SecretID})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create secrets manager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsauth.SignRequest(req, body, awsauth.Credentials{
+		AccessKeyID:     p.config.AccessKeyID,
+		SecretAccessKey: p.config.SecretAccessKey,
+		SessionToken:    p.config.SessionToken,
+	}, p.config.Region, "secretsmanager", time.Now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secrets manager secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets manager returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode secrets manager response: %w", err)
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(result.SecretString), &values); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", p.config.SecretID, err)
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
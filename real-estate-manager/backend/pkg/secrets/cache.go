@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// CachedProvider memoizes another provider for ttl so remote backends are
+// not queried on every lookup, while still picking up rotated values
+type CachedProvider struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+func NewCachedProvider(provider Provider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+func (p *CachedProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	p.mu.Lock()
+	entry, ok := p.cache[key]
+	p.mu.Unlock()
+
+	if ok && p.now().Sub(entry.fetchedAt) < p.ttl {
+		return entry.value, nil
+	}
+
+	value, err := p.provider.GetSecret(ctx, key)
+	if err != nil {
+		// Keep serving the last known value if the backend is briefly unavailable
+		if ok {
+			return entry.value, nil
+		}
+		return "", err
+	}
+
+	p.mu.Lock()
+	p.cache[key] = cachedSecret{value: value, fetchedAt: p.now()}
+	p.mu.Unlock()
+	return value, nil
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// EnvProvider reads secrets from process environment variables
+type EnvProvider struct{}
+
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{}
+}
+
+func (p *EnvProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// ChainProvider asks each provider in turn and returns the first hit
+type ChainProvider struct {
+	providers []Provider
+}
+
+func NewChainProvider(providers ...Provider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+func (p *ChainProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	for _, provider := range p.providers {
+		value, err := provider.GetSecret(ctx, key)
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, ErrSecretNotFound) {
+			return "", err
+		}
+	}
+	return "", ErrSecretNotFound
+}
@@ -0,0 +1,36 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider reads one secret per file from a directory, matching the
+// layout used by Docker and Kubernetes secret mounts (e.g. /run/secrets/DB_PASSWORD)
+type FileProvider struct {
+	dir string
+}
+
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+func (p *FileProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	// Accept both DB_PASSWORD and db_password file names
+	for _, name := range []string{key, strings.ToLower(key)} {
+		data, err := os.ReadFile(filepath.Join(p.dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret %s: %w", key, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", ErrSecretNotFound
+}
@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrSecretNotFound is returned when a provider has no value for a key
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Provider resolves named secrets such as JWT_SECRET or DB_PASSWORD
+type Provider interface {
+	GetSecret(ctx context.Context, key string) (string, error)
+}
+
+// GetOrDefault returns the secret for key, falling back to defaultValue when
+// the provider does not know about it
+func GetOrDefault(ctx context.Context, p Provider, key, defaultValue string) (string, error) {
+	value, err := p.GetSecret(ctx, key)
+	if errors.Is(err, ErrSecretNotFound) || (err == nil && value == "") {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// NewProviderFromEnv builds the provider selected by SECRETS_PROVIDER
+// (env, file, vault or aws). Non-env providers are wrapped in a cache so
+// rotated values are picked up after SECRETS_CACHE_TTL.
+func NewProviderFromEnv() (Provider, error) {
+	kind := strings.ToLower(getEnvOrDefault("SECRETS_PROVIDER", "env"))
+
+	var provider Provider
+	switch kind {
+	case "env":
+		return NewEnvProvider(), nil
+	case "file":
+		provider = NewFileProvider(getEnvOrDefault("SECRETS_DIR", "/run/secrets"))
+	case "vault":
+		provider = NewVaultProvider(VaultConfig{
+			Address: os.Getenv("VAULT_ADDR"),
+			Token:   os.Getenv("VAULT_TOKEN"),
+			Path:    getEnvOrDefault("VAULT_SECRET_PATH", "secret/data/real-estate-manager"),
+		})
+	case "aws":
+		provider = NewAWSSecretsManagerProvider(AWSConfig{
+			Region:          getEnvOrDefault("AWS_REGION", "us-east-1"),
+			SecretID:        os.Getenv("AWS_SECRETS_ID"),
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", kind)
+	}
+
+	ttl, err := time.ParseDuration(getEnvOrDefault("SECRETS_CACHE_TTL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECRETS_CACHE_TTL: %w", err)
+	}
+
+	// Fall back to plain environment variables for keys the backend doesn't hold
+	return NewCachedProvider(NewChainProvider(provider, NewEnvProvider()), ttl), nil
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
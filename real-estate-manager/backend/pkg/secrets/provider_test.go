@@ -0,0 +1,329 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubProvider answers from a map and counts lookups
+type stubProvider struct {
+	values map[string]string
+	err    error
+	calls  int
+}
+
+func (p *stubProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	p.calls++
+	if p.err != nil {
+		return "", p.err
+	}
+	value, ok := p.values[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("SECRETS_TEST_SET", "value")
+	t.Setenv("SECRETS_TEST_EMPTY", "")
+	provider := NewEnvProvider()
+
+	if value, err := provider.GetSecret(context.Background(), "SECRETS_TEST_SET"); err != nil || value != "value" {
+		t.Errorf("Expected value, got %q and %v", value, err)
+	}
+	for _, key := range []string{"SECRETS_TEST_EMPTY", "SECRETS_TEST_UNSET"} {
+		if _, err := provider.GetSecret(context.Background(), key); !errors.Is(err, ErrSecretNotFound) {
+			t.Errorf("%s: expected ErrSecretNotFound, got %v", key, err)
+		}
+	}
+}
+
+func TestChainProvider(t *testing.T) {
+	first := &stubProvider{values: map[string]string{"A": "from first"}}
+	second := &stubProvider{values: map[string]string{"A": "from second", "B": "from second"}}
+	chain := NewChainProvider(first, second)
+
+	tests := []struct {
+		key     string
+		expect  string
+		wantErr error
+	}{
+		{key: "A", expect: "from first"},
+		{key: "B", expect: "from second"},
+		{key: "C", wantErr: ErrSecretNotFound},
+	}
+	for _, tt := range tests {
+		value, err := chain.GetSecret(context.Background(), tt.key)
+		if value != tt.expect || !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %q and %v, got %q and %v", tt.key, tt.expect, tt.wantErr, value, err)
+		}
+	}
+
+	// A failing backend is reported rather than skipped
+	failing := errors.New("backend down")
+	if _, err := NewChainProvider(&stubProvider{err: failing}, second).GetSecret(context.Background(), "B"); !errors.Is(err, failing) {
+		t.Errorf("Expected the backend error, got %v", err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "DB_PASSWORD"), []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "jwt_secret"), []byte("  signing-key  "), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "DB_USER"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewFileProvider(dir)
+
+	tests := []struct {
+		key     string
+		expect  string
+		wantErr bool
+	}{
+		{key: "DB_PASSWORD", expect: "s3cret"},
+		{key: "JWT_SECRET", expect: "signing-key"},
+		{key: "DB_NAME", wantErr: true},
+		{key: "DB_USER", wantErr: true},
+	}
+	for _, tt := range tests {
+		value, err := provider.GetSecret(context.Background(), tt.key)
+		if (err != nil) != tt.wantErr || value != tt.expect {
+			t.Errorf("%s: expected %q (error %v), got %q and %v", tt.key, tt.expect, tt.wantErr, value, err)
+		}
+	}
+	if _, err := provider.GetSecret(context.Background(), "DB_NAME"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Expected ErrSecretNotFound for a missing file, got %v", err)
+	}
+}
+
+func TestCachedProvider(t *testing.T) {
+	backend := &stubProvider{values: map[string]string{"JWT_SECRET": "v1"}}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewCachedProvider(backend, 5*time.Minute)
+	provider.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		rotate  string
+		fail    bool
+		expect  string
+		calls   int
+	}{
+		{name: "first lookup reaches the backend", expect: "v1", calls: 1},
+		{name: "cached within the ttl", advance: 4 * time.Minute, rotate: "v2", expect: "v1", calls: 1},
+		{name: "rotated value picked up after the ttl", advance: 2 * time.Minute, expect: "v2", calls: 2},
+		{name: "last value served while the backend is down", advance: 10 * time.Minute, fail: true, expect: "v2", calls: 3},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if step.rotate != "" {
+			backend.values["JWT_SECRET"] = step.rotate
+		}
+		backend.err = nil
+		if step.fail {
+			backend.err = errors.New("backend down")
+		}
+
+		value, err := provider.GetSecret(context.Background(), "JWT_SECRET")
+		if err != nil || value != step.expect || backend.calls != step.calls {
+			t.Errorf("%s: expected %q after %d calls, got %q, %v after %d", step.name, step.expect, step.calls, value, err, backend.calls)
+		}
+	}
+
+	if _, err := provider.GetSecret(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Error("Expected an error for a key never fetched while the backend is down")
+	}
+}
+
+func TestGetOrDefault(t *testing.T) {
+	provider := &stubProvider{values: map[string]string{"SET": "value", "EMPTY": ""}}
+
+	tests := []struct {
+		key    string
+		expect string
+	}{
+		{key: "SET", expect: "value"},
+		{key: "EMPTY", expect: "fallback"},
+		{key: "MISSING", expect: "fallback"},
+	}
+	for _, tt := range tests {
+		if value, err := GetOrDefault(context.Background(), provider, tt.key, "fallback"); err != nil || value != tt.expect {
+			t.Errorf("%s: expected %q, got %q and %v", tt.key, tt.expect, value, err)
+		}
+	}
+
+	provider.err = errors.New("backend down")
+	if _, err := GetOrDefault(context.Background(), provider, "SET", "fallback"); err == nil {
+		t.Error("Expected backend errors not to fall back to the default")
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			w.Write([]byte(`{"data": {"data": {"DB_PASSWORD": "s3cret"}, "metadata": {"version": 2}}}`))
+		case "/v1/secret/data/broken":
+			w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		config  VaultConfig
+		key     string
+		expect  string
+		wantErr error
+	}{
+		{name: "found", config: VaultConfig{Address: server.URL + "/", Token: "root", Path: "/secret/data/app"}, key: "DB_PASSWORD", expect: "s3cret"},
+		{name: "key missing from the entry", config: VaultConfig{Address: server.URL, Token: "root", Path: "secret/data/app"}, key: "JWT_SECRET", wantErr: ErrSecretNotFound},
+		{name: "entry missing", config: VaultConfig{Address: server.URL, Token: "root", Path: "secret/data/other"}, key: "DB_PASSWORD", wantErr: ErrSecretNotFound},
+		{name: "token rejected", config: VaultConfig{Address: server.URL, Token: "wrong", Path: "secret/data/app"}, key: "DB_PASSWORD"},
+		{name: "malformed response", config: VaultConfig{Address: server.URL, Token: "root", Path: "secret/data/broken"}, key: "DB_PASSWORD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := NewVaultProvider(tt.config).GetSecret(context.Background(), tt.key)
+			switch {
+			case tt.expect != "":
+				if err != nil || value != tt.expect {
+					t.Errorf("Expected %q, got %q and %v", tt.expect, value, err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+			case err == nil || errors.Is(err, ErrSecretNotFound):
+				t.Errorf("Expected a backend error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	secretStrings := map[string]string{
+		"app":    `{"DB_PASSWORD": "s3cret", "JWT_SECRET": "signing-key"}`,
+		"plain":  `not an object`,
+		"denied": "",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("Unexpected target %q", r.Header.Get("X-Amz-Target"))
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("Expected a SigV4 signed request, got %q", r.Header.Get("Authorization"))
+		}
+		var body struct{ SecretId string }
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Unexpected body: %v", err)
+		}
+		secret, ok := secretStrings[body.SecretId]
+		if !ok || secret == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": secret})
+	}))
+	defer server.Close()
+
+	provider := func(secretID string) *AWSSecretsManagerProvider {
+		return NewAWSSecretsManagerProvider(AWSConfig{
+			Region:          "us-east-1",
+			SecretID:        secretID,
+			AccessKeyID:     "AKID",
+			SecretAccessKey: "secret",
+			Endpoint:        server.URL,
+		})
+	}
+
+	if value, err := provider("app").GetSecret(context.Background(), "JWT_SECRET"); err != nil || value != "signing-key" {
+		t.Errorf("Expected signing-key, got %q and %v", value, err)
+	}
+	if _, err := provider("app").GetSecret(context.Background(), "DB_USER"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Expected ErrSecretNotFound for a key missing from the secret, got %v", err)
+	}
+	for _, secretID := range []string{"plain", "denied"} {
+		if _, err := provider(secretID).GetSecret(context.Background(), "JWT_SECRET"); err == nil || errors.Is(err, ErrSecretNotFound) {
+			t.Errorf("%s: expected an error, got %v", secretID, err)
+		}
+	}
+
+	if got := NewAWSSecretsManagerProvider(AWSConfig{Region: "eu-west-1"}).config.Endpoint; got != "https://secretsmanager.eu-west-1.amazonaws.com/" {
+		t.Errorf("Unexpected default endpoint %s", got)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "DB_PASSWORD"), []byte("from file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectError bool
+		expectCache bool
+	}{
+		{name: "env by default"},
+		{name: "file", env: map[string]string{"SECRETS_PROVIDER": "file", "SECRETS_DIR": dir}, expectCache: true},
+		{name: "vault", env: map[string]string{"SECRETS_PROVIDER": "Vault"}, expectCache: true},
+		{name: "aws", env: map[string]string{"SECRETS_PROVIDER": "aws"}, expectCache: true},
+		{name: "unknown provider", env: map[string]string{"SECRETS_PROVIDER": "consul"}, expectError: true},
+		{name: "invalid cache ttl", env: map[string]string{"SECRETS_PROVIDER": "file", "SECRETS_CACHE_TTL": "soon"}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"SECRETS_PROVIDER", "SECRETS_DIR", "SECRETS_CACHE_TTL"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			provider, err := NewProviderFromEnv()
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error %v, got %v", tt.expectError, err)
+			}
+			if err != nil {
+				return
+			}
+			if _, cached := provider.(*CachedProvider); cached != tt.expectCache {
+				t.Errorf("Expected cached %v, got %T", tt.expectCache, provider)
+			}
+		})
+	}
+
+	// Keys missing from the backend fall back to the environment
+	t.Setenv("SECRETS_PROVIDER", "file")
+	t.Setenv("SECRETS_DIR", dir)
+	t.Setenv("SECRETS_CACHE_TTL", "")
+	t.Setenv("DB_USER", "from env")
+	provider, err := NewProviderFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for key, expect := range map[string]string{"DB_PASSWORD": "from file", "DB_USER": "from env"} {
+		if value, err := provider.GetSecret(context.Background(), key); err != nil || value != expect {
+			t.Errorf("%s: expected %q, got %q and %v", key, expect, value, err)
+		}
+	}
+}
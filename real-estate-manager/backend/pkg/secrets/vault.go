@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultConfig describes a HashiCorp Vault KV v2 secret holding all keys
+type VaultConfig struct {
+	Address string
+	Token   string
+	Path    string // e.g. secret/data/real-estate-manager
+}
+
+// VaultProvider reads secrets from a single Vault KV v2 entry
+type VaultProvider struct {
+	config VaultConfig
+	client *http.Client
+}
+
+func NewVaultProvider(config VaultConfig) *VaultProvider {
+	return &VaultProvider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *VaultProvider) GetSecret(ctx context.Context, key string) (string, error) {
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimRight(p.config.Address, "/"), strings.TrimLeft(p.config.Path, "/"))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.config.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	value, ok := body.Data.Data[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
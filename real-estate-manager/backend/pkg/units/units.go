@@ -0,0 +1,133 @@
+// Package units converts property measurements between the canonical metric
+// values stored in the database and the unit system requested by clients.
+package units
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// System is a unit system for API responses
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+const (
+	squareMetersPerSquareFoot = 0.09290304
+	squareMetersPerAcre       = 4046.8564224
+	squareMetersPerHectare    = 10000.0
+)
+
+// Unit labels used in API responses
+const (
+	SquareFeet   = "sqft"
+	SquareMeters = "m2"
+	Acres        = "acres"
+	Hectares     = "ha"
+)
+
+// ParseSystem parses a ?units= value; empty defaults to imperial, matching
+// the MLS source data
+func ParseSystem(value string) (System, error) {
+	switch System(strings.ToLower(strings.TrimSpace(value))) {
+	case "", Imperial:
+		return Imperial, nil
+	case Metric:
+		return Metric, nil
+	default:
+		return "", fmt.Errorf("units must be %q or %q", Imperial, Metric)
+	}
+}
+
+func SquareFeetToSquareMeters(sqft float64) float64 {
+	return sqft * squareMetersPerSquareFoot
+}
+
+func SquareMetersToSquareFeet(sqm float64) float64 {
+	return sqm / squareMetersPerSquareFoot
+}
+
+func AcresToHectares(acres float64) float64 {
+	return acres * squareMetersPerAcre / squareMetersPerHectare
+}
+
+func HectaresToAcres(hectares float64) float64 {
+	return hectares * squareMetersPerHectare / squareMetersPerAcre
+}
+
+func AcresToSquareMeters(acres float64) float64 {
+	return acres * squareMetersPerAcre
+}
+
+func SquareMetersToAcres(sqm float64) float64 {
+	return sqm / squareMetersPerAcre
+}
+
+func HectaresToSquareMeters(hectares float64) float64 {
+	return hectares * squareMetersPerHectare
+}
+
+func SquareMetersToHectares(sqm float64) float64 {
+	return sqm / squareMetersPerHectare
+}
+
+// LivingArea converts a canonical living area in square meters to the
+// requested system (square feet or square meters)
+func LivingArea(sqm float64, system System) (float64, string) {
+	if system == Metric {
+		return Round(sqm, 2), SquareMeters
+	}
+	return Round(SquareMetersToSquareFeet(sqm), 0), SquareFeet
+}
+
+// LotArea converts a canonical lot area in square meters to the requested
+// system (acres or hectares)
+func LotArea(sqm float64, system System) (float64, string) {
+	if system == Metric {
+		return Round(SquareMetersToHectares(sqm), 4), Hectares
+	}
+	return Round(SquareMetersToAcres(sqm), 4), Acres
+}
+
+// Round rounds v to the given number of decimal places
+func Round(v float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(v*factor) / factor
+}
+
+var lotSizePattern = regexp.MustCompile(`^([0-9][0-9,]*(?:\.[0-9]+)?)\s*([a-zA-Z0-9. ]*)$`)
+
+// ParseLotSize parses free-text MLS lot sizes such as "0.25 Acres",
+// "10,890 sqft" or "1.2 ha" into square meters. A bare number is treated as
+// square feet, the most common MLS convention.
+func ParseLotSize(value string) (float64, bool) {
+	match := lotSizePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, false
+	}
+
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+	if err != nil || amount <= 0 {
+		return 0, false
+	}
+
+	unit := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(match[2]), ".", ""))
+	switch unit {
+	case "", "sqft", "sq ft", "sf", "square feet", "ft2":
+		return SquareFeetToSquareMeters(amount), true
+	case "ac", "acre", "acres":
+		return AcresToSquareMeters(amount), true
+	case "ha", "hectare", "hectares":
+		return HectaresToSquareMeters(amount), true
+	case "m2", "sqm", "sq m", "square meters", "square metres":
+		return amount, true
+	default:
+		return 0, false
+	}
+}
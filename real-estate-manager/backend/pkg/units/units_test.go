@@ -0,0 +1,118 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.001
+}
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{name: "sqft to sqm", got: SquareFeetToSquareMeters(1000), expected: 92.90304},
+		{name: "sqm to sqft", got: SquareMetersToSquareFeet(92.90304), expected: 1000},
+		{name: "acres to hectares", got: AcresToHectares(1), expected: 0.4046856},
+		{name: "hectares to acres", got: HectaresToAcres(1), expected: 2.4710538},
+		{name: "acres to sqm", got: AcresToSquareMeters(0.25), expected: 1011.7141},
+		{name: "sqm to acres round trip", got: SquareMetersToAcres(AcresToSquareMeters(3.5)), expected: 3.5},
+		{name: "hectares round trip", got: SquareMetersToHectares(HectaresToSquareMeters(2)), expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !almostEqual(tt.got, tt.expected) {
+				t.Errorf("Expected %f, got %f", tt.expected, tt.got)
+			}
+		})
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    System
+		expectError bool
+	}{
+		{input: "", expected: Imperial},
+		{input: "imperial", expected: Imperial},
+		{input: "METRIC", expected: Metric},
+		{input: "furlongs", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			system, err := ParseSystem(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if system != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, system)
+			}
+		})
+	}
+}
+
+func TestLivingAndLotArea(t *testing.T) {
+	value, unit := LivingArea(92.90304, Imperial)
+	if value != 1000 || unit != SquareFeet {
+		t.Errorf("Expected 1000 sqft, got %v %s", value, unit)
+	}
+
+	value, unit = LivingArea(92.90304, Metric)
+	if value != 92.9 || unit != SquareMeters {
+		t.Errorf("Expected 92.9 m2, got %v %s", value, unit)
+	}
+
+	value, unit = LotArea(AcresToSquareMeters(0.25), Imperial)
+	if value != 0.25 || unit != Acres {
+		t.Errorf("Expected 0.25 acres, got %v %s", value, unit)
+	}
+
+	value, unit = LotArea(10000, Metric)
+	if value != 1 || unit != Hectares {
+		t.Errorf("Expected 1 ha, got %v %s", value, unit)
+	}
+}
+
+func TestParseLotSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		ok       bool
+	}{
+		{input: "0.25 Acres", expected: 1011.7141, ok: true},
+		{input: "1 acre", expected: 4046.8564, ok: true},
+		{input: "10,890 sqft", expected: 1011.7141, ok: true},
+		{input: "10890 Sq. Ft.", expected: 1011.7141, ok: true},
+		{input: "5000", expected: 464.5152, ok: true},
+		{input: "1.5 ha", expected: 15000, ok: true},
+		{input: "500 m2", expected: 500, ok: true},
+		{input: "", ok: false},
+		{input: "irregular", ok: false},
+		{input: "2 furlongs", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseLotSize(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, ok)
+			}
+			if ok && !almostEqual(got, tt.expected) {
+				t.Errorf("Expected %f sqm, got %f", tt.expected, got)
+			}
+		})
+	}
+}
@@ -2,8 +2,9 @@
 
 import React, { useEffect, useState } from 'react';
 import { useRouter } from 'next/navigation';
-import { Property } from '@/types/property';
+import { Photo, Property } from '@/types/property';
 import { fetchProperties } from '@/lib/api';
+import { API_BASE_URL } from '@/lib/config';
 
 interface PropertyListProps {
   properties: Property[] | null;
@@ -13,6 +14,12 @@ interface PropertyListProps {
   error?: string | null;
 }
 
+// Cards show the medium copy of the cover photo when the backend generated
+// one; its URL is relative to the API server unless a CDN serves it
+const listPhotoSrc = (photo: Photo): string => {
+  return photo.medium_url ? new URL(photo.medium_url, API_BASE_URL).toString() : photo.url;
+};
+
 const formatPrice = (price: number): string => {
   return new Intl.NumberFormat('en-US', {
     style: 'currency',
@@ -95,8 +102,8 @@ const PropertyList: React.FC<PropertyListProps> = ({
             {property.photos && property.photos.length > 0 ? (
               <div className="h-48 overflow-hidden relative">
                 <img
-                  src={property.photos[0].url}
-                  alt={property.photos[0].caption || property.name || 'Property image'}
+                  src={listPhotoSrc(property.photos[0])}
+                  alt={property.photos[0].alt_text || property.photos[0].caption || property.name || 'Property image'}
                   className="w-full h-full object-cover hover:scale-105 transition-transform duration-200"
                 />
                 {property.photos.length > 1 && (
@@ -51,7 +51,7 @@ const PropertyPhotoGallery: React.FC<PropertyPhotoGalleryProps> = ({ photos, pro
         <div className="relative h-64 md:h-96 overflow-hidden rounded-lg bg-gray-200">
           <img
             src={currentPhoto.url}
-            alt={currentPhoto.caption || `${propertyName} - Photo ${currentIndex + 1}`}
+            alt={currentPhoto.alt_text || currentPhoto.caption || `${propertyName} - Photo ${currentIndex + 1}`}
             className="w-full h-full object-cover cursor-pointer hover:scale-105 transition-transform duration-200"
             onClick={openFullscreen}
           />
@@ -113,7 +113,7 @@ const PropertyPhotoGallery: React.FC<PropertyPhotoGalleryProps> = ({ photos, pro
               >
                 <img
                   src={photo.url}
-                  alt={photo.caption || `Thumbnail ${index + 1}`}
+                  alt={photo.alt_text || photo.caption || `Thumbnail ${index + 1}`}
                   className="w-16 h-16 object-cover"
                 />
                 {index === currentIndex && (
@@ -138,7 +138,7 @@ const PropertyPhotoGallery: React.FC<PropertyPhotoGalleryProps> = ({ photos, pro
           <div className="relative max-w-7xl max-h-full">
             <img
               src={currentPhoto.url}
-              alt={currentPhoto.caption || `${propertyName} - Photo ${currentIndex + 1}`}
+              alt={currentPhoto.alt_text || currentPhoto.caption || `${propertyName} - Photo ${currentIndex + 1}`}
               className="max-w-full max-h-full object-contain"
             />
             
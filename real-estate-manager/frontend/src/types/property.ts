@@ -1,7 +1,11 @@
 export interface Photo {
   url: string;
   local_url?: string;
+  thumbnail_url?: string;
+  medium_url?: string;
   caption?: string;
+  alt_text?: string;
+  position?: number;
 }
 
 export interface Property {